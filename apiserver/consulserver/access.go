@@ -0,0 +1,173 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	ParamService   = "service"
+	ParamServiceID = "service_id"
+
+	paramIndex     = "index"
+	paramWait      = "wait"
+	paramNamespace = "ns"
+	paramTag       = "tag"
+	paramPassing   = "passing"
+	paramToken     = "token"
+
+	headerIndex       = "X-Consul-Index"
+	headerKnownLeader = "X-Consul-KnownLeader"
+	headerLastContact = "X-Consul-LastContact"
+	headerToken       = "X-Consul-Token"
+
+	mimeAll  = "*/*"
+	mimeText = "text/plain"
+)
+
+// GetConsulServer consul http api web server
+func (h *ConsulServer) GetConsulServer() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/v1").Consumes(restful.MIME_JSON, mimeAll).Produces(restful.MIME_JSON)
+	h.addCatalogAccess(ws)
+	h.addAgentAccess(ws)
+	return ws
+}
+
+func (h *ConsulServer) addCatalogAccess(ws *restful.WebService) {
+	ws.Route(ws.GET("/catalog/services").To(h.CatalogServices)).
+		Doc("查询服务列表").
+		Param(ws.QueryParameter(paramIndex, "blocking query index").DataType("int")).
+		Param(ws.QueryParameter(paramWait, "blocking query wait time").DataType("string"))
+	ws.Route(ws.GET(fmt.Sprintf("/catalog/service/{%s}", ParamService)).To(h.CatalogService)).
+		Doc("查询服务的实例列表").
+		Param(ws.PathParameter(ParamService, "服务名").DataType("string")).
+		Param(ws.QueryParameter(paramTag, "按标签过滤").DataType("string")).
+		Param(ws.QueryParameter(paramIndex, "blocking query index").DataType("int")).
+		Param(ws.QueryParameter(paramWait, "blocking query wait time").DataType("string"))
+	ws.Route(ws.GET(fmt.Sprintf("/health/service/{%s}", ParamService)).To(h.HealthService)).
+		Doc("查询服务的实例及健康状态").
+		Param(ws.PathParameter(ParamService, "服务名").DataType("string")).
+		Param(ws.QueryParameter(paramTag, "按标签过滤").DataType("string")).
+		Param(ws.QueryParameter(paramPassing, "只返回健康的实例").DataType("boolean")).
+		Param(ws.QueryParameter(paramIndex, "blocking query index").DataType("int")).
+		Param(ws.QueryParameter(paramWait, "blocking query wait time").DataType("string"))
+}
+
+func (h *ConsulServer) addAgentAccess(ws *restful.WebService) {
+	ws.Route(ws.PUT("/agent/service/register").To(h.RegisterService)).
+		Doc("注册服务实例")
+	ws.Route(ws.PUT(fmt.Sprintf("/agent/service/deregister/{%s}", ParamServiceID)).To(h.DeregisterService)).
+		Doc("反注册服务实例").
+		Param(ws.PathParameter(ParamServiceID, "实例ID").DataType("string"))
+}
+
+// getNamespace consul 企业版通过 ns 参数指定命名空间，没有指定时使用配置的命名空间
+func (h *ConsulServer) getNamespace(req *restful.Request) string {
+	if namespace := req.QueryParameter(paramNamespace); len(namespace) > 0 {
+		return namespace
+	}
+	return h.namespace
+}
+
+// parseContext 构建请求上下文，consul 客户端通过 X-Consul-Token 头或者 token 参数传递鉴权信息
+func parseContext(req *restful.Request) context.Context {
+	token := req.HeaderParameter(headerToken)
+	if len(token) == 0 {
+		token = req.QueryParameter(paramToken)
+	}
+	ctx := context.WithValue(req.Request.Context(), utils.ContextAuthTokenKey, token)
+	ctx = context.WithValue(ctx, utils.ContextClientAddress, req.Request.RemoteAddr)
+	ctx = context.WithValue(ctx, utils.StringContext("request-id"), utils.NewUUID())
+	return ctx
+}
+
+// parseBlockingQuery 解析 blocking query 的参数
+func parseBlockingQuery(req *restful.Request) (uint64, time.Duration, error) {
+	var index uint64
+	if value := req.QueryParameter(paramIndex); len(value) > 0 {
+		var err error
+		if index, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid index: %v", err)
+		}
+	}
+	wait := DefaultBlockingWait
+	if value := req.QueryParameter(paramWait); len(value) > 0 {
+		var err error
+		if wait, err = time.ParseDuration(value); err != nil {
+			return 0, 0, fmt.Errorf("invalid wait time: %v", err)
+		}
+	}
+	if wait > MaxBlockingWait {
+		wait = MaxBlockingWait
+	}
+	return index, wait, nil
+}
+
+func writePolarisStatusCode(req *restful.Request, statusCode uint32) {
+	req.SetAttribute(statusCodeHeader, statusCode)
+}
+
+// writeJSON 以 json 的方式应答，并携带 consul index
+func writeJSON(req *restful.Request, rsp *restful.Response, index uint64, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeError(req, rsp, http.StatusInternalServerError, api.ExecuteException, err.Error())
+		return
+	}
+	writePolarisStatusCode(req, api.ExecuteSuccess)
+	rsp.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	if index > 0 {
+		rsp.Header().Set(headerIndex, strconv.FormatUint(index, 10))
+		rsp.Header().Set(headerKnownLeader, "true")
+		rsp.Header().Set(headerLastContact, "0")
+	}
+	rsp.WriteHeader(http.StatusOK)
+	_, _ = rsp.Write(data)
+}
+
+// writeError consul 的错误应答为纯文本
+func writeError(req *restful.Request, rsp *restful.Response, httpStatus int, code uint32, message string) {
+	writePolarisStatusCode(req, code)
+	if len(message) == 0 {
+		message = api.Code2Info(code)
+	}
+	rsp.Header().Set(restful.HEADER_ContentType, mimeText)
+	rsp.WriteHeader(httpStatus)
+	_, _ = rsp.Write([]byte(message))
+}
+
+// toHTTPStatus 将北极星的错误码转换为 http 状态码
+func toHTTPStatus(code uint32) int {
+	httpStatus := int(code / 1000)
+	if httpStatus < http.StatusBadRequest || len(http.StatusText(httpStatus)) == 0 {
+		return http.StatusInternalServerError
+	}
+	return httpStatus
+}
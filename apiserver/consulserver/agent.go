@@ -0,0 +1,240 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	defaultCheckInterval = 10 * time.Second
+	maxWeight            = 10000
+)
+
+// RegisterService 注册服务实例，实例 ID 使用 consul 的服务 ID
+func (h *ConsulServer) RegisterService(req *restful.Request, rsp *restful.Response) {
+	registration := &AgentServiceRegistration{}
+	if err := req.ReadEntity(registration); err != nil {
+		writeError(req, rsp, http.StatusBadRequest, api.ParseException, fmt.Sprintf("Request decode failed: %v", err))
+		return
+	}
+	if len(registration.Name) == 0 {
+		writeError(req, rsp, http.StatusBadRequest, api.InvalidServiceName, "Missing service name")
+		return
+	}
+	if len(registration.Address) == 0 {
+		// 没有指定地址时，consul 使用 agent 的地址，这里使用客户端的地址
+		host, _, err := net.SplitHostPort(req.Request.RemoteAddr)
+		if err != nil {
+			host = req.Request.RemoteAddr
+		}
+		registration.Address = host
+	}
+	instance, err := buildInstance(h.getNamespace(req), registration)
+	if err != nil {
+		writeError(req, rsp, http.StatusBadRequest, api.InvalidParameter, err.Error())
+		return
+	}
+
+	code := h.registerInstance(parseContext(req), instance)
+	if code == api.ExecuteSuccess || code == api.ExistedResource || code == api.SameInstanceRequest {
+		log.Infof("[CONSUL-SERVER]instance (namespace=%s, service=%s, id=%s, host=%s, port=%d) has been registered, "+
+			"code is %d", instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+			instance.GetId().GetValue(), instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+		writePolarisStatusCode(req, code)
+		rsp.WriteHeader(http.StatusOK)
+		return
+	}
+	log.Errorf("[CONSUL-SERVER]instance (namespace=%s, service=%s, id=%s, host=%s, port=%d) register failed, code is %d",
+		instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+		instance.GetId().GetValue(), instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+	writeError(req, rsp, toHTTPStatus(code), code, "")
+}
+
+// DeregisterService 反注册服务实例
+func (h *ConsulServer) DeregisterService(req *restful.Request, rsp *restful.Response) {
+	serviceID := req.PathParameter(ParamServiceID)
+	resp := h.namingServer.DeregisterInstance(parseContext(req), &api.Instance{
+		Id: &wrappers.StringValue{Value: serviceID},
+	})
+	code := resp.GetCode().GetValue()
+	switch code {
+	case api.ExecuteSuccess:
+		log.Infof("[CONSUL-SERVER]instance (id=%s) has been deregistered", serviceID)
+		writePolarisStatusCode(req, code)
+		rsp.WriteHeader(http.StatusOK)
+	case api.NotFoundResource, api.NotFoundInstance:
+		writeError(req, rsp, http.StatusNotFound, code, fmt.Sprintf("Unknown service ID %q", serviceID))
+	default:
+		log.Errorf("[CONSUL-SERVER]instance (id=%s) deregister failed, code is %d", serviceID, code)
+		writeError(req, rsp, toHTTPStatus(code), code, "")
+	}
+}
+
+func (h *ConsulServer) registerInstance(ctx context.Context, instance *api.Instance) uint32 {
+	resp := h.namingServer.RegisterInstance(ctx, instance)
+	code := resp.GetCode().GetValue()
+	if code != api.NotFoundResource {
+		return code
+	}
+	// 服务不存在，先创建服务再重试注册实例
+	svc := &api.Service{
+		Namespace: instance.GetNamespace(),
+		Name:      instance.GetService(),
+	}
+	svcResp := h.namingServer.CreateServices(ctx, []*api.Service{svc})
+	svcCreateCode := svcResp.GetCode().GetValue()
+	if svcCreateCode != api.ExecuteSuccess && svcCreateCode != api.ExistedResource {
+		return svcCreateCode
+	}
+	resp = h.namingServer.RegisterInstance(ctx, instance)
+	return resp.GetCode().GetValue()
+}
+
+// buildInstance consul 注册请求转换为北极星实例，标签保存在元数据中
+func buildInstance(namespace string, registration *AgentServiceRegistration) (*api.Instance, error) {
+	metadata := make(map[string]string, len(registration.Meta)+2)
+	for k, v := range registration.Meta {
+		metadata[k] = v
+	}
+	metadata[MetadataRegisterFrom] = ServerConsul
+	if len(registration.Tags) > 0 {
+		tags, err := json.Marshal(registration.Tags)
+		if err != nil {
+			return nil, err
+		}
+		metadata[MetadataTags] = string(tags)
+	}
+	weight := weightRatio
+	if registration.Weights != nil {
+		if registration.Weights.Passing < 0 {
+			return nil, fmt.Errorf("invalid weights: %d", registration.Weights.Passing)
+		}
+		// 和 consul 一致，没有指定 passing 权重时默认为 1
+		if registration.Weights.Passing > 0 {
+			weight = int(math.Min(float64(registration.Weights.Passing*weightRatio), maxWeight))
+		}
+	}
+
+	instance := &api.Instance{
+		Namespace: &wrappers.StringValue{Value: namespace},
+		Service:   &wrappers.StringValue{Value: registration.Name},
+		Host:      &wrappers.StringValue{Value: registration.Address},
+		Port:      &wrappers.UInt32Value{Value: uint32(registration.Port)},
+		Weight:    &wrappers.UInt32Value{Value: uint32(weight)},
+		Healthy:   &wrappers.BoolValue{Value: true},
+		Metadata:  metadata,
+	}
+	if len(registration.ID) > 0 {
+		instance.Id = &wrappers.StringValue{Value: registration.ID}
+	}
+
+	checks := registration.Checks
+	if registration.Check != nil {
+		checks = append([]*AgentServiceCheck{registration.Check}, checks...)
+	}
+	// 北极星的实例只支持一种健康检查，使用第一个可以由服务端主动探测的检查
+	for _, check := range checks {
+		healthCheck, err := toHealthCheck(check, metadata)
+		if err != nil {
+			return nil, err
+		}
+		if healthCheck != nil {
+			instance.EnableHealthCheck = &wrappers.BoolValue{Value: true}
+			instance.HealthCheck = healthCheck
+			break
+		}
+	}
+	return instance, nil
+}
+
+// toHealthCheck consul 的 http/tcp/grpc 检查转换为北极星的主动探测，ttl 等依赖客户端上报的检查不支持
+func toHealthCheck(check *AgentServiceCheck, metadata map[string]string) (*api.HealthCheck, error) {
+	var checkType api.HealthCheck_HealthCheckType
+	var port string
+	switch {
+	case len(check.HTTP) > 0:
+		u, err := url.Parse(check.HTTP)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http check: %v", err)
+		}
+		checkType = api.HealthCheck_HTTP
+		port = u.Port()
+		if len(port) == 0 && u.Scheme == "https" {
+			port = "443"
+		} else if len(port) == 0 {
+			port = "80"
+		}
+		metadata[model.MetaKeyHealthCheckHTTPPath] = u.RequestURI()
+	case len(check.TCP) > 0:
+		_, tcpPort, err := net.SplitHostPort(check.TCP)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tcp check: %v", err)
+		}
+		checkType = api.HealthCheck_TCP
+		port = tcpPort
+	case len(check.GRPC) > 0:
+		address := check.GRPC
+		if idx := strings.Index(address, "/"); idx >= 0 {
+			metadata[model.MetaKeyHealthCheckGRPCService] = address[idx+1:]
+			address = address[:idx]
+		}
+		_, grpcPort, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grpc check: %v", err)
+		}
+		checkType = api.HealthCheck_GRPC
+		port = grpcPort
+	default:
+		log.Warnf("[CONSUL-SERVER]health check %q is not supported, only http/tcp/grpc checks are supported",
+			check.Name)
+		return nil, nil
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return nil, fmt.Errorf("invalid check port: %s", port)
+	}
+	metadata[model.MetaKeyHealthCheckPort] = port
+
+	interval := defaultCheckInterval
+	if len(check.Interval) > 0 {
+		var err error
+		if interval, err = time.ParseDuration(check.Interval); err != nil {
+			return nil, fmt.Errorf("invalid check interval: %v", err)
+		}
+	}
+	ttl := uint32(math.Max(interval.Seconds(), 1))
+	return &api.HealthCheck{
+		Type:      checkType,
+		Heartbeat: &api.HeartbeatHealthCheck{Ttl: &wrappers.UInt32Value{Value: ttl}},
+	}, nil
+}
@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service"
+)
+
+type mockNamingServer struct {
+	service.DiscoverServer
+	services  map[string]bool
+	instances map[string]*api.Instance
+}
+
+func newMockNamingServer() *mockNamingServer {
+	return &mockNamingServer{services: map[string]bool{}, instances: map[string]*api.Instance{}}
+}
+
+func (m *mockNamingServer) CreateServices(ctx context.Context, req []*api.Service) *api.BatchWriteResponse {
+	for _, svc := range req {
+		m.services[svc.GetNamespace().GetValue()+"/"+svc.GetName().GetValue()] = true
+	}
+	return api.NewBatchWriteResponse(api.ExecuteSuccess)
+}
+
+func (m *mockNamingServer) RegisterInstance(ctx context.Context, req *api.Instance) *api.Response {
+	if !m.services[req.GetNamespace().GetValue()+"/"+req.GetService().GetValue()] {
+		return api.NewInstanceResponse(api.NotFoundResource, req)
+	}
+	m.instances[req.GetId().GetValue()] = req
+	return api.NewInstanceResponse(api.ExecuteSuccess, req)
+}
+
+func (m *mockNamingServer) DeregisterInstance(ctx context.Context, req *api.Instance) *api.Response {
+	if _, ok := m.instances[req.GetId().GetValue()]; !ok {
+		return api.NewInstanceResponse(api.NotFoundResource, req)
+	}
+	delete(m.instances, req.GetId().GetValue())
+	return api.NewInstanceResponse(api.ExecuteSuccess, req)
+}
+
+func doRequest(h *ConsulServer, method, path, body string) *httptest.ResponseRecorder {
+	container := restful.NewContainer()
+	container.Add(h.GetConsulServer())
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	rsp := httptest.NewRecorder()
+	container.ServeHTTP(rsp, req)
+	return rsp
+}
+
+func TestRegisterAndDeregisterService(t *testing.T) {
+	naming := newMockNamingServer()
+	h := &ConsulServer{namespace: DefaultNamespace, namingServer: naming}
+
+	body := `{"ID":"web-1","Name":"web","Tags":["v1","primary"],"Port":8080,"Meta":{"k":"v"},
+		"Weights":{"Passing":3,"Warning":1},"Check":{"HTTP":"http://127.0.0.1:8081/health","Interval":"5s"}}`
+	rsp := doRequest(h, http.MethodPut, "/v1/agent/service/register", body)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.True(t, naming.services["default/web"])
+
+	instance := naming.instances["web-1"]
+	assert.NotNil(t, instance)
+	// 没有指定地址时使用客户端地址
+	assert.Equal(t, "192.0.2.1", instance.GetHost().GetValue())
+	assert.Equal(t, uint32(300), instance.GetWeight().GetValue())
+	assert.Equal(t, `["v1","primary"]`, instance.GetMetadata()[MetadataTags])
+	assert.Equal(t, "v", instance.GetMetadata()["k"])
+	assert.True(t, instance.GetEnableHealthCheck().GetValue())
+	assert.Equal(t, api.HealthCheck_HTTP, instance.GetHealthCheck().GetType())
+	assert.Equal(t, uint32(5), instance.GetHealthCheck().GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, "8081", instance.GetMetadata()[model.MetaKeyHealthCheckPort])
+	assert.Equal(t, "/health", instance.GetMetadata()[model.MetaKeyHealthCheckHTTPPath])
+
+	rsp = doRequest(h, http.MethodPut, "/v1/agent/service/deregister/web-1", "")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, 0, len(naming.instances))
+
+	rsp = doRequest(h, http.MethodPut, "/v1/agent/service/deregister/web-1", "")
+	assert.Equal(t, http.StatusNotFound, rsp.Code)
+}
+
+func TestRegisterServiceInvalid(t *testing.T) {
+	h := &ConsulServer{namespace: DefaultNamespace, namingServer: newMockNamingServer()}
+
+	rsp := doRequest(h, http.MethodPut, "/v1/agent/service/register", `{"Port":8080}`)
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+
+	rsp = doRequest(h, http.MethodPut, "/v1/agent/service/register", `{"Name":"web","Check":{"TCP":"bad"}}`)
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+}
+
+func TestToHealthCheck(t *testing.T) {
+	metadata := map[string]string{}
+	check, err := toHealthCheck(&AgentServiceCheck{TCP: "127.0.0.1:22"}, metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, api.HealthCheck_TCP, check.GetType())
+	assert.Equal(t, uint32(10), check.GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, "22", metadata[model.MetaKeyHealthCheckPort])
+
+	metadata = map[string]string{}
+	check, err = toHealthCheck(&AgentServiceCheck{GRPC: "127.0.0.1:9090/my.Service", Interval: "30s"}, metadata)
+	assert.Nil(t, err)
+	assert.Equal(t, api.HealthCheck_GRPC, check.GetType())
+	assert.Equal(t, uint32(30), check.GetHeartbeat().GetTtl().GetValue())
+	assert.Equal(t, "9090", metadata[model.MetaKeyHealthCheckPort])
+	assert.Equal(t, "my.Service", metadata[model.MetaKeyHealthCheckGRPCService])
+
+	metadata = map[string]string{}
+	check, err = toHealthCheck(&AgentServiceCheck{HTTP: "https://example.com/ping?x=1"}, metadata)
+	assert.Nil(t, err)
+	assert.NotNil(t, check)
+	assert.Equal(t, "443", metadata[model.MetaKeyHealthCheckPort])
+	assert.Equal(t, "/ping?x=1", metadata[model.MetaKeyHealthCheckHTTPPath])
+
+	// ttl 检查依赖客户端上报，不支持
+	check, err = toHealthCheck(&AgentServiceCheck{TTL: "10s"}, map[string]string{})
+	assert.Nil(t, err)
+	assert.Nil(t, check)
+}
@@ -0,0 +1,270 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	// 北极星权重默认为 100，consul 权重默认为 1
+	weightRatio = 100
+	// internalMetadataPrefix 北极星内部使用的元数据，不返回给 consul 客户端
+	internalMetadataPrefix = "internal-"
+)
+
+// CatalogServices 查询命名空间下的服务列表及其标签
+func (h *ConsulServer) CatalogServices(req *restful.Request, rsp *restful.Response) {
+	index, wait, err := parseBlockingQuery(req)
+	if err != nil {
+		writeError(req, rsp, http.StatusBadRequest, api.InvalidParameter, err.Error())
+		return
+	}
+	current := h.indexWorker.WaitIndex(parseContext(req), "", index, wait)
+	writeJSON(req, rsp, current, buildCatalogServices(h.getServices(h.getNamespace(req))))
+}
+
+// CatalogService 查询服务的实例列表
+func (h *ConsulServer) CatalogService(req *restful.Request, rsp *restful.Response) {
+	index, wait, err := parseBlockingQuery(req)
+	if err != nil {
+		writeError(req, rsp, http.StatusBadRequest, api.InvalidParameter, err.Error())
+		return
+	}
+	namespace := h.getNamespace(req)
+	serviceName := req.PathParameter(ParamService)
+	current := h.indexWorker.WaitIndex(parseContext(req), indexKey(namespace, serviceName), index, wait)
+
+	tags := req.Request.URL.Query()[paramTag]
+	services := make([]*CatalogService, 0)
+	for _, instance := range h.getInstances(namespace, serviceName) {
+		if !hasTags(parseTags(instance), tags) {
+			continue
+		}
+		services = append(services, h.toCatalogService(serviceName, instance, current))
+	}
+	writeJSON(req, rsp, current, services)
+}
+
+// HealthService 查询服务的实例列表及健康状态
+func (h *ConsulServer) HealthService(req *restful.Request, rsp *restful.Response) {
+	index, wait, err := parseBlockingQuery(req)
+	if err != nil {
+		writeError(req, rsp, http.StatusBadRequest, api.InvalidParameter, err.Error())
+		return
+	}
+	namespace := h.getNamespace(req)
+	serviceName := req.PathParameter(ParamService)
+	current := h.indexWorker.WaitIndex(parseContext(req), indexKey(namespace, serviceName), index, wait)
+
+	tags := req.Request.URL.Query()[paramTag]
+	_, passingOnly := req.Request.URL.Query()[paramPassing]
+	if passingOnly {
+		passingOnly = req.QueryParameter(paramPassing) != "false"
+	}
+	entries := make([]*ServiceEntry, 0)
+	for _, instance := range h.getInstances(namespace, serviceName) {
+		if !hasTags(parseTags(instance), tags) {
+			continue
+		}
+		if passingOnly && (!instance.Healthy() || instance.Isolate()) {
+			continue
+		}
+		entries = append(entries, h.toServiceEntry(serviceName, instance, current))
+	}
+	writeJSON(req, rsp, current, entries)
+}
+
+// getServices 从缓存中获取命名空间下的所有服务及其实例
+func (h *ConsulServer) getServices(namespace string) map[string][]*model.Instance {
+	services := make(map[string][]*model.Instance)
+	cacheMgr := h.namingServer.Cache()
+	_ = cacheMgr.Service().IteratorServices(func(key string, svc *model.Service) (bool, error) {
+		if svc.Namespace == namespace {
+			services[svc.Name] = cacheMgr.Instance().GetInstancesByServiceID(svc.ID)
+		}
+		return true, nil
+	})
+	return services
+}
+
+// getInstances 从缓存中获取服务的实例
+func (h *ConsulServer) getInstances(namespace, serviceName string) []*model.Instance {
+	cacheMgr := h.namingServer.Cache()
+	svc := cacheMgr.Service().GetServiceByName(serviceName, namespace)
+	if svc == nil {
+		return nil
+	}
+	return cacheMgr.Instance().GetInstancesByServiceID(svc.ID)
+}
+
+// buildCatalogServices 构建服务名到标签列表的映射
+func buildCatalogServices(services map[string][]*model.Instance) map[string][]string {
+	catalog := make(map[string][]string, len(services))
+	for name, instances := range services {
+		tagSet := make(map[string]struct{})
+		for _, instance := range instances {
+			for _, tag := range parseTags(instance) {
+				tagSet[tag] = struct{}{}
+			}
+		}
+		tags := make([]string, 0, len(tagSet))
+		for tag := range tagSet {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		catalog[name] = tags
+	}
+	return catalog
+}
+
+// parseTags 获取实例注册时携带的 consul 标签
+func parseTags(instance *model.Instance) []string {
+	tags := make([]string, 0)
+	if value, ok := instance.Metadata()[MetadataTags]; ok && len(value) > 0 {
+		_ = json.Unmarshal([]byte(value), &tags)
+	}
+	return tags
+}
+
+// hasTags 实例需要包含所有指定的标签
+func hasTags(tags []string, required []string) bool {
+	for _, requiredTag := range required {
+		found := false
+		for _, tag := range tags {
+			if tag == requiredTag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// serviceMeta 去掉北极星内部使用的元数据
+func serviceMeta(instance *model.Instance) map[string]string {
+	meta := make(map[string]string, len(instance.Metadata()))
+	for k, v := range instance.Metadata() {
+		if strings.HasPrefix(k, internalMetadataPrefix) {
+			continue
+		}
+		meta[k] = v
+	}
+	return meta
+}
+
+// toConsulWeights 北极星权重转换为 consul 权重，非 0 的权重最小为 1
+func toConsulWeights(weight uint32) AgentWeights {
+	passing := int(weight / weightRatio)
+	if passing == 0 && weight > 0 {
+		passing = 1
+	}
+	return AgentWeights{Passing: passing, Warning: 1}
+}
+
+func (h *ConsulServer) toCatalogService(serviceName string, instance *model.Instance,
+	index uint64) *CatalogService {
+	return &CatalogService{
+		ID:              instance.Host(),
+		Node:            instance.Host(),
+		Address:         instance.Host(),
+		Datacenter:      h.datacenter,
+		TaggedAddresses: map[string]string{},
+		NodeMeta:        map[string]string{},
+		ServiceID:       instance.ID(),
+		ServiceName:     serviceName,
+		ServiceAddress:  instance.Host(),
+		ServiceTags:     parseTags(instance),
+		ServiceMeta:     serviceMeta(instance),
+		ServicePort:     int(instance.Port()),
+		ServiceWeights:  toConsulWeights(instance.Weight()),
+		CreateIndex:     index,
+		ModifyIndex:     index,
+	}
+}
+
+func (h *ConsulServer) toServiceEntry(serviceName string, instance *model.Instance, index uint64) *ServiceEntry {
+	tags := parseTags(instance)
+	status := HealthPassing
+	if !instance.Healthy() {
+		status = HealthCritical
+	}
+	checks := []*HealthCheck{
+		{
+			Node:        instance.Host(),
+			CheckID:     serviceCheckPrefix + instance.ID(),
+			Name:        "Service '" + serviceName + "' check",
+			Status:      status,
+			ServiceID:   instance.ID(),
+			ServiceName: serviceName,
+			ServiceTags: tags,
+			CreateIndex: index,
+			ModifyIndex: index,
+		},
+	}
+	// 隔离的实例对应 consul 的维护模式
+	if instance.Isolate() {
+		checks = append(checks, &HealthCheck{
+			Node:        instance.Host(),
+			CheckID:     maintenanceCheckPrefix + instance.ID(),
+			Name:        "Service Maintenance Mode",
+			Status:      HealthCritical,
+			Notes:       HealthMaintenance,
+			ServiceID:   instance.ID(),
+			ServiceName: serviceName,
+			ServiceTags: tags,
+			CreateIndex: index,
+			ModifyIndex: index,
+		})
+	}
+	return &ServiceEntry{
+		Node: &Node{
+			ID:              instance.Host(),
+			Node:            instance.Host(),
+			Address:         instance.Host(),
+			Datacenter:      h.datacenter,
+			TaggedAddresses: map[string]string{},
+			Meta:            map[string]string{},
+			CreateIndex:     index,
+			ModifyIndex:     index,
+		},
+		Service: &AgentService{
+			ID:          instance.ID(),
+			Service:     serviceName,
+			Tags:        tags,
+			Meta:        serviceMeta(instance),
+			Port:        int(instance.Port()),
+			Address:     instance.Host(),
+			Weights:     toConsulWeights(instance.Weight()),
+			CreateIndex: index,
+			ModifyIndex: index,
+		},
+		Checks: checks,
+	}
+}
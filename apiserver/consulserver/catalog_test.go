@@ -0,0 +1,102 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func newTestInstance(id string, healthy, isolate bool, tags string) *model.Instance {
+	metadata := map[string]string{
+		MetadataRegisterFrom: ServerConsul,
+		"k":                  "v",
+	}
+	if len(tags) > 0 {
+		metadata[MetadataTags] = tags
+	}
+	return &model.Instance{Proto: &api.Instance{
+		Id:       utils.NewStringValue(id),
+		Host:     utils.NewStringValue("127.0.0.1"),
+		Port:     utils.NewUInt32Value(8080),
+		Weight:   utils.NewUInt32Value(100),
+		Healthy:  utils.NewBoolValue(healthy),
+		Isolate:  utils.NewBoolValue(isolate),
+		Metadata: metadata,
+	}}
+}
+
+func TestBuildCatalogServices(t *testing.T) {
+	services := map[string][]*model.Instance{
+		"web": {
+			newTestInstance("web-1", true, false, `["v2","primary"]`),
+			newTestInstance("web-2", true, false, `["v1","primary"]`),
+		},
+		"db": {},
+	}
+	catalog := buildCatalogServices(services)
+	assert.Equal(t, []string{"primary", "v1", "v2"}, catalog["web"])
+	assert.Equal(t, []string{}, catalog["db"])
+
+	data, err := json.Marshal(catalog)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"db":[],"web":["primary","v1","v2"]}`, string(data))
+}
+
+func TestHasTags(t *testing.T) {
+	assert.True(t, hasTags([]string{"a", "b"}, nil))
+	assert.True(t, hasTags([]string{"a", "b"}, []string{"b"}))
+	assert.False(t, hasTags([]string{"a", "b"}, []string{"a", "c"}))
+}
+
+func TestToConsulWeights(t *testing.T) {
+	assert.Equal(t, AgentWeights{Passing: 1, Warning: 1}, toConsulWeights(100))
+	assert.Equal(t, AgentWeights{Passing: 1, Warning: 1}, toConsulWeights(50))
+	assert.Equal(t, AgentWeights{Passing: 3, Warning: 1}, toConsulWeights(300))
+	assert.Equal(t, AgentWeights{Passing: 0, Warning: 1}, toConsulWeights(0))
+}
+
+func TestToServiceEntry(t *testing.T) {
+	h := &ConsulServer{namespace: DefaultNamespace, datacenter: DefaultDatacenter}
+
+	entry := h.toServiceEntry("web", newTestInstance("web-1", true, false, `["v1"]`), 10)
+	assert.Equal(t, "web-1", entry.Service.ID)
+	assert.Equal(t, []string{"v1"}, entry.Service.Tags)
+	assert.Equal(t, map[string]string{"k": "v"}, entry.Service.Meta)
+	assert.Equal(t, DefaultDatacenter, entry.Node.Datacenter)
+	assert.Equal(t, 1, len(entry.Checks))
+	assert.Equal(t, HealthPassing, entry.Checks[0].Status)
+	assert.Equal(t, uint64(10), entry.Service.ModifyIndex)
+
+	entry = h.toServiceEntry("web", newTestInstance("web-2", false, true, ""), 10)
+	assert.Equal(t, []string{}, entry.Service.Tags)
+	assert.Equal(t, 2, len(entry.Checks))
+	assert.Equal(t, HealthCritical, entry.Checks[0].Status)
+	assert.Equal(t, maintenanceCheckPrefix+"web-2", entry.Checks[1].CheckID)
+
+	service := h.toCatalogService("web", newTestInstance("web-1", true, false, `["v1"]`), 10)
+	assert.Equal(t, "web-1", service.ServiceID)
+	assert.Equal(t, 8080, service.ServicePort)
+	assert.Equal(t, []string{"v1"}, service.ServiceTags)
+}
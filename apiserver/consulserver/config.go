@@ -0,0 +1,42 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import "time"
+
+const (
+	optionListenIP   = "listenIP"
+	optionListenPort = "listenPort"
+	optionNamespace  = "namespace"
+	optionDatacenter = "datacenter"
+	optionConnLimit  = "connLimit"
+	optionTLS        = "tls"
+)
+
+const (
+	// DefaultNamespace polaris namespace for consul services
+	DefaultNamespace = "default"
+	// DefaultDatacenter datacenter returned to consul clients
+	DefaultDatacenter = "dc1"
+	// DefaultIndexRefreshInterval interval to refresh consul index from cache revisions
+	DefaultIndexRefreshInterval = time.Second
+	// DefaultBlockingWait default wait time of blocking query, same as consul
+	DefaultBlockingWait = 5 * time.Minute
+	// MaxBlockingWait max wait time of blocking query, same as consul
+	MaxBlockingWait = 10 * time.Minute
+)
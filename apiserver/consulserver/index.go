@@ -0,0 +1,157 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// revisionGetter 获取所有服务的实例 revision，key 为 namespace/service
+type revisionGetter func() map[string]string
+
+// IndexWorker 将服务实例的 revision 转换为 consul 单调递增的 X-Consul-Index，用于支持 blocking query
+type IndexWorker struct {
+	lock      sync.RWMutex
+	index     uint64
+	revisions map[string]string
+	indexes   map[string]uint64
+	notifyCh  chan struct{}
+	getter    revisionGetter
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewIndexWorker 创建并启动 index 计算任务
+func NewIndexWorker(interval time.Duration, getter revisionGetter) *IndexWorker {
+	worker := &IndexWorker{
+		// consul 客户端将 0 视为不阻塞，因此 index 从 1 开始
+		index:     1,
+		revisions: map[string]string{},
+		indexes:   map[string]uint64{},
+		notifyCh:  make(chan struct{}),
+		getter:    getter,
+		stopCh:    make(chan struct{}),
+	}
+	worker.refresh()
+	go worker.run(interval)
+	return worker
+}
+
+func indexKey(namespace, service string) string {
+	return namespace + "/" + service
+}
+
+func (w *IndexWorker) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.refresh()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// refresh 对比服务的 revision，发生变更的服务共享同一个新的 index，并唤醒所有等待中的请求
+func (w *IndexWorker) refresh() {
+	revisions := w.getter()
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	next := w.index + 1
+	changed := false
+	for key, revision := range revisions {
+		if oldRevision, ok := w.revisions[key]; !ok || oldRevision != revision {
+			w.indexes[key] = next
+			changed = true
+		}
+	}
+	for key := range w.revisions {
+		if _, ok := revisions[key]; !ok {
+			delete(w.indexes, key)
+			changed = true
+		}
+	}
+	w.revisions = revisions
+	if !changed {
+		return
+	}
+	w.index = next
+	close(w.notifyCh)
+	w.notifyCh = make(chan struct{})
+}
+
+// Index 获取服务的 index，key 为空时返回全局的 index，服务不存在时也返回全局的 index
+func (w *IndexWorker) Index(key string) (uint64, <-chan struct{}) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	if index, ok := w.indexes[key]; ok && len(key) > 0 {
+		return index, w.notifyCh
+	}
+	return w.index, w.notifyCh
+}
+
+// WaitIndex blocking query，客户端 index 与当前 index 一致时阻塞，直到 index 变化或者超时
+func (w *IndexWorker) WaitIndex(ctx context.Context, key string, index uint64, wait time.Duration) uint64 {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		current, notifyCh := w.Index(key)
+		// index 回退时（比如服务端重启）直接返回，由客户端重置 index
+		if index == 0 || current != index {
+			return current
+		}
+		select {
+		case <-notifyCh:
+		case <-timer.C:
+			return current
+		case <-ctx.Done():
+			return current
+		case <-w.stopCh:
+			return current
+		}
+	}
+}
+
+// Stop 停止 index 计算任务
+func (w *IndexWorker) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+}
+
+// getServiceRevisions 从缓存中获取所有服务计算之后的实例 revision
+func (h *ConsulServer) getServiceRevisions() map[string]string {
+	revisions := make(map[string]string)
+	cacheMgr := h.namingServer.Cache()
+	_ = cacheMgr.Service().IteratorServices(func(key string, svc *model.Service) (bool, error) {
+		revision := cacheMgr.GetServiceInstanceRevision(svc.ID)
+		if len(revision) == 0 {
+			revision = svc.Revision
+		}
+		revisions[indexKey(svc.Namespace, svc.Name)] = revision
+		return true, nil
+	})
+	return revisions
+}
@@ -0,0 +1,111 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRevisions struct {
+	lock      sync.Mutex
+	revisions map[string]string
+}
+
+func (m *mockRevisions) set(key, revision string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(revision) == 0 {
+		delete(m.revisions, key)
+		return
+	}
+	m.revisions[key] = revision
+}
+
+func (m *mockRevisions) get() map[string]string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ret := make(map[string]string, len(m.revisions))
+	for k, v := range m.revisions {
+		ret[k] = v
+	}
+	return ret
+}
+
+func TestIndexWorkerRefresh(t *testing.T) {
+	revisions := &mockRevisions{revisions: map[string]string{"default/a": "r1", "default/b": "r1"}}
+	worker := NewIndexWorker(time.Hour, revisions.get)
+	defer worker.Stop()
+
+	indexA, _ := worker.Index("default/a")
+	indexB, _ := worker.Index("default/b")
+	global, _ := worker.Index("")
+	assert.Equal(t, uint64(2), global)
+	assert.Equal(t, global, indexA)
+	assert.Equal(t, global, indexB)
+
+	// 没有变化时 index 不变
+	worker.refresh()
+	global, _ = worker.Index("")
+	assert.Equal(t, uint64(2), global)
+
+	// 只有发生变化的服务 index 递增
+	revisions.set("default/a", "r2")
+	worker.refresh()
+	newIndexA, _ := worker.Index("default/a")
+	newIndexB, _ := worker.Index("default/b")
+	assert.Equal(t, uint64(3), newIndexA)
+	assert.Equal(t, indexB, newIndexB)
+
+	// 服务删除后返回全局 index
+	revisions.set("default/b", "")
+	worker.refresh()
+	newIndexB, _ = worker.Index("default/b")
+	assert.Equal(t, uint64(4), newIndexB)
+}
+
+func TestIndexWorkerWaitIndex(t *testing.T) {
+	revisions := &mockRevisions{revisions: map[string]string{"default/a": "r1"}}
+	worker := NewIndexWorker(10*time.Millisecond, revisions.get)
+	defer worker.Stop()
+	index, _ := worker.Index("default/a")
+
+	// index 为 0 或者与当前 index 不一致时不阻塞
+	assert.Equal(t, index, worker.WaitIndex(context.Background(), "default/a", 0, time.Minute))
+	assert.Equal(t, index, worker.WaitIndex(context.Background(), "default/a", index+100, time.Minute))
+
+	// 超时返回
+	start := time.Now()
+	assert.Equal(t, index, worker.WaitIndex(context.Background(), "default/a", index, 50*time.Millisecond))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	// 其他服务变化不影响当前服务的 blocking query
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		revisions.set("default/b", "r1")
+		time.Sleep(30 * time.Millisecond)
+		revisions.set("default/a", "r2")
+	}()
+	newIndex := worker.WaitIndex(context.Background(), "default/a", index, time.Minute)
+	assert.True(t, newIndex > index)
+	assert.Equal(t, "r2", revisions.get()["default/a"])
+}
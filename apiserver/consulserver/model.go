@@ -0,0 +1,132 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package consulserver
+
+const (
+	MetadataRegisterFrom = "internal-register-from"
+	MetadataTags         = "internal-consul-tags"
+
+	HealthPassing     = "passing"
+	HealthCritical    = "critical"
+	HealthMaintenance = "maintenance"
+
+	// serviceCheckPrefix consul 中服务健康检查的 CheckID 前缀
+	serviceCheckPrefix = "service:"
+	// maintenanceCheckPrefix consul 中服务维护模式的 CheckID 前缀，北极星的隔离实例对应维护模式
+	maintenanceCheckPrefix = "_service_maintenance:"
+)
+
+// AgentWeights 服务权重
+type AgentWeights struct {
+	Passing int
+	Warning int
+}
+
+// AgentServiceCheck 注册服务时携带的健康检查
+type AgentServiceCheck struct {
+	CheckID  string `json:",omitempty"`
+	Name     string `json:",omitempty"`
+	Interval string `json:",omitempty"`
+	Timeout  string `json:",omitempty"`
+	TTL      string `json:",omitempty"`
+	HTTP     string `json:",omitempty"`
+	TCP      string `json:",omitempty"`
+	GRPC     string `json:",omitempty"`
+}
+
+// AgentServiceRegistration 注册服务的请求
+type AgentServiceRegistration struct {
+	ID                string
+	Name              string
+	Tags              []string
+	Port              int
+	Address           string
+	Meta              map[string]string
+	Weights           *AgentWeights
+	EnableTagOverride bool
+	Check             *AgentServiceCheck
+	Checks            []*AgentServiceCheck
+}
+
+// CatalogService /v1/catalog/service/:service 的应答
+type CatalogService struct {
+	ID                       string
+	Node                     string
+	Address                  string
+	Datacenter               string
+	TaggedAddresses          map[string]string
+	NodeMeta                 map[string]string
+	ServiceID                string
+	ServiceName              string
+	ServiceAddress           string
+	ServiceTags              []string
+	ServiceMeta              map[string]string
+	ServicePort              int
+	ServiceWeights           AgentWeights
+	ServiceEnableTagOverride bool
+	CreateIndex              uint64
+	ModifyIndex              uint64
+}
+
+// Node consul 节点，北极星中以实例的 host 作为节点
+type Node struct {
+	ID              string
+	Node            string
+	Address         string
+	Datacenter      string
+	TaggedAddresses map[string]string
+	Meta            map[string]string
+	CreateIndex     uint64
+	ModifyIndex     uint64
+}
+
+// AgentService 服务实例
+type AgentService struct {
+	ID                string
+	Service           string
+	Tags              []string
+	Meta              map[string]string
+	Port              int
+	Address           string
+	Weights           AgentWeights
+	EnableTagOverride bool
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// HealthCheck 实例的健康检查状态
+type HealthCheck struct {
+	Node        string
+	CheckID     string
+	Name        string
+	Status      string
+	Notes       string
+	Output      string
+	ServiceID   string
+	ServiceName string
+	ServiceTags []string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ServiceEntry /v1/health/service/:service 的应答
+type ServiceEntry struct {
+	Node    *Node
+	Service *AgentService
+	Checks  []*HealthCheck
+}
@@ -0,0 +1,243 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"encoding/hex"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	// addrLabel 实例地址的域名 <ip>.addr.polaris.，用于 SRV 记录的 target
+	addrLabel = "addr"
+)
+
+// instanceGetter 获取服务的实例，服务不存在时返回 false
+type instanceGetter func(namespace, service string) ([]*model.Instance, bool)
+
+// handler 处理 dns 查询，<service>.<namespace>.<domain> 返回服务健康且未隔离的实例
+type handler struct {
+	domain       string
+	ttl          uint32
+	getInstances instanceGetter
+}
+
+func newHandler(domain string, ttl uint32, getInstances instanceGetter) *handler {
+	return &handler{
+		domain:       domain,
+		ttl:          ttl,
+		getInstances: getInstances,
+	}
+}
+
+// ServeDNS 实现 dns.Handler
+func (h *handler) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	msg := new(dns.Msg)
+	if len(req.Question) != 1 {
+		msg.SetRcode(req, dns.RcodeFormatError)
+		_ = w.WriteMsg(msg)
+		return
+	}
+	msg.SetReply(req)
+	msg.Authoritative = true
+	h.answer(msg, req.Question[0])
+
+	// udp 的应答需要按照客户端支持的大小进行截断，客户端收到截断的应答后会使用 tcp 重试
+	if _, ok := w.RemoteAddr().(*net.UDPAddr); ok {
+		size := dns.MinMsgSize
+		if opt := req.IsEdns0(); opt != nil {
+			size = int(opt.UDPSize())
+			msg.SetEdns0(opt.UDPSize(), false)
+		}
+		msg.Truncate(size)
+	}
+	if err := w.WriteMsg(msg); err != nil {
+		log.Errorf("[API-Server][DNS] write response to %s err: %s", w.RemoteAddr(), err.Error())
+	}
+}
+
+func (h *handler) answer(msg *dns.Msg, question dns.Question) {
+	name := strings.ToLower(question.Name)
+	if !dns.IsSubDomain(h.domain, name) {
+		msg.Authoritative = false
+		msg.Rcode = dns.RcodeRefused
+		return
+	}
+	// 保留查询中服务名的大小写
+	prefix := strings.TrimSuffix(question.Name[:len(question.Name)-len(h.domain)], ".")
+	labels := dns.SplitDomainName(prefix)
+	if len(labels) == 0 {
+		h.noData(msg)
+		return
+	}
+	if len(labels) == 2 && strings.ToLower(labels[1]) == addrLabel {
+		h.answerAddr(msg, question, labels[0])
+		return
+	}
+
+	// 兼容 RFC2782 的 SRV 查询格式：_http._tcp.<service>.<namespace>.<domain>
+	for len(labels) > 0 && strings.HasPrefix(labels[0], "_") {
+		labels = labels[1:]
+	}
+	if len(labels) < 2 {
+		h.nxDomain(msg)
+		return
+	}
+	namespace := labels[len(labels)-1]
+	serviceName := strings.Join(labels[:len(labels)-1], ".")
+	instances, ok := h.getInstances(namespace, serviceName)
+	if !ok {
+		h.nxDomain(msg)
+		return
+	}
+	instances = availableInstances(instances)
+
+	switch question.Qtype {
+	case dns.TypeA, dns.TypeAAAA:
+		for _, instance := range instances {
+			if rr := h.addressRecord(question.Name, question.Qtype, instance.Host()); rr != nil {
+				msg.Answer = append(msg.Answer, rr)
+			}
+		}
+	case dns.TypeSRV:
+		for _, instance := range instances {
+			target := h.target(instance.Host())
+			msg.Answer = append(msg.Answer, &dns.SRV{
+				Hdr:      h.header(question.Name, dns.TypeSRV),
+				Priority: uint16(math.Min(float64(instance.Priority()), math.MaxUint16)),
+				Weight:   uint16(math.Min(float64(instance.Weight()), math.MaxUint16)),
+				Port:     uint16(instance.Port()),
+				Target:   target,
+			})
+			for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+				if rr := h.addressRecord(target, qtype, instance.Host()); rr != nil {
+					msg.Extra = append(msg.Extra, rr)
+				}
+			}
+		}
+	}
+	if len(msg.Answer) == 0 {
+		h.noData(msg)
+	}
+}
+
+// answerAddr 解析 SRV 记录中 target 对应的地址
+func (h *handler) answerAddr(msg *dns.Msg, question dns.Question, label string) {
+	ip := decodeAddr(label)
+	if ip == nil {
+		h.nxDomain(msg)
+		return
+	}
+	if rr := h.addressRecord(question.Name, question.Qtype, ip.String()); rr != nil {
+		msg.Answer = append(msg.Answer, rr)
+		return
+	}
+	h.noData(msg)
+}
+
+// availableInstances 过滤掉不健康、隔离以及权重为 0 的实例
+func availableInstances(instances []*model.Instance) []*model.Instance {
+	ret := make([]*model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if !instance.Healthy() || instance.Isolate() || instance.Weight() == 0 {
+			continue
+		}
+		ret = append(ret, instance)
+	}
+	return ret
+}
+
+func (h *handler) header(name string, rrtype uint16) dns.RR_Header {
+	return dns.RR_Header{Name: name, Rrtype: rrtype, Class: dns.ClassINET, Ttl: h.ttl}
+}
+
+// addressRecord 根据查询类型构建 A 或 AAAA 记录，host 不是对应类型的 ip 时返回 nil
+func (h *handler) addressRecord(name string, qtype uint16, host string) dns.RR {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	ipv4 := ip.To4()
+	switch {
+	case qtype == dns.TypeA && ipv4 != nil:
+		return &dns.A{Hdr: h.header(name, dns.TypeA), A: ipv4}
+	case qtype == dns.TypeAAAA && ipv4 == nil:
+		return &dns.AAAA{Hdr: h.header(name, dns.TypeAAAA), AAAA: ip}
+	}
+	return nil
+}
+
+// target SRV 记录的 target 必须是域名，实例 host 为 ip 时转换为 <ip>.addr.<domain>
+func (h *handler) target(host string) string {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return dns.Fqdn(host)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return strings.ReplaceAll(ipv4.String(), ".", "-") + "." + addrLabel + "." + h.domain
+	}
+	return hex.EncodeToString(ip.To16()) + "." + addrLabel + "." + h.domain
+}
+
+// decodeAddr 解析 target 中的 ip，ipv4 以 - 分隔，ipv6 为 16 进制编码
+func decodeAddr(label string) net.IP {
+	if len(label) == 2*net.IPv6len {
+		data, err := hex.DecodeString(label)
+		if err != nil {
+			return nil
+		}
+		return net.IP(data)
+	}
+	ip := net.ParseIP(strings.ReplaceAll(label, "-", "."))
+	if ip == nil || ip.To4() == nil {
+		return nil
+	}
+	return ip
+}
+
+func (h *handler) soa() dns.RR {
+	return &dns.SOA{
+		Hdr:     h.header(h.domain, dns.TypeSOA),
+		Ns:      "ns." + h.domain,
+		Mbox:    "hostmaster." + h.domain,
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  h.ttl,
+	}
+}
+
+// noData 域名存在但没有对应类型的记录
+func (h *handler) noData(msg *dns.Msg) {
+	msg.Ns = append(msg.Ns, h.soa())
+}
+
+// nxDomain 域名不存在
+func (h *handler) nxDomain(msg *dns.Msg) {
+	msg.Rcode = dns.RcodeNameError
+	msg.Ns = append(msg.Ns, h.soa())
+}
@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func newTestInstance(host string, port, weight, priority uint32, healthy, isolate bool) *model.Instance {
+	return &model.Instance{Proto: &api.Instance{
+		Host:     utils.NewStringValue(host),
+		Port:     utils.NewUInt32Value(port),
+		Weight:   utils.NewUInt32Value(weight),
+		Priority: utils.NewUInt32Value(priority),
+		Healthy:  utils.NewBoolValue(healthy),
+		Isolate:  utils.NewBoolValue(isolate),
+	}}
+}
+
+func mockInstanceGetter(services map[string][]*model.Instance) instanceGetter {
+	return func(namespace, service string) ([]*model.Instance, bool) {
+		instances, ok := services[namespace+"/"+service]
+		return instances, ok
+	}
+}
+
+// startTestServer 同时启动 udp 和 tcp 的 dns server，返回监听地址
+func startTestServer(t *testing.T, h dns.Handler) (string, string, func()) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	udpServer := &dns.Server{PacketConn: pc, Handler: h, NotifyStartedFunc: wg.Done}
+	tcpServer := &dns.Server{Listener: ln, Handler: h, NotifyStartedFunc: wg.Done}
+	go func() { _ = udpServer.ActivateAndServe() }()
+	go func() { _ = tcpServer.ActivateAndServe() }()
+	wg.Wait()
+	return pc.LocalAddr().String(), ln.Addr().String(), func() {
+		_ = udpServer.Shutdown()
+		_ = tcpServer.Shutdown()
+	}
+}
+
+func exchange(t *testing.T, network, address, name string, qtype uint16) *dns.Msg {
+	client := &dns.Client{Net: network}
+	req := new(dns.Msg)
+	req.SetQuestion(name, qtype)
+	rsp, _, err := client.Exchange(req, address)
+	assert.Nil(t, err)
+	return rsp
+}
+
+func TestServeDNS(t *testing.T) {
+	services := map[string][]*model.Instance{
+		"default/echo": {
+			newTestInstance("10.0.0.1", 8080, 100, 0, true, false),
+			newTestInstance("10.0.0.2", 8080, 200, 1, true, false),
+			newTestInstance("10.0.0.3", 8080, 100, 0, false, false),
+			newTestInstance("10.0.0.4", 8080, 100, 0, true, true),
+			newTestInstance("10.0.0.5", 8080, 0, 0, true, false),
+			newTestInstance("fd00::1", 8080, 100, 0, true, false),
+			newTestInstance("echo.example.com", 8080, 100, 0, true, false),
+		},
+		"default/empty": {},
+	}
+	h := newHandler(DefaultDomain, DefaultTTL, mockInstanceGetter(services))
+	udpAddr, tcpAddr, stop := startTestServer(t, h)
+	defer stop()
+
+	for _, address := range []struct{ network, addr string }{{"udp", udpAddr}, {"tcp", tcpAddr}} {
+		// A 记录只返回健康、未隔离且权重不为 0 的 ipv4 实例
+		rsp := exchange(t, address.network, address.addr, "echo.default.polaris.", dns.TypeA)
+		assert.Equal(t, dns.RcodeSuccess, rsp.Rcode)
+		assert.True(t, rsp.Authoritative)
+		assert.Equal(t, 2, len(rsp.Answer))
+		assert.Equal(t, "10.0.0.1", rsp.Answer[0].(*dns.A).A.String())
+		assert.Equal(t, "10.0.0.2", rsp.Answer[1].(*dns.A).A.String())
+		assert.Equal(t, uint32(DefaultTTL), rsp.Answer[0].Header().Ttl)
+
+		rsp = exchange(t, address.network, address.addr, "echo.default.polaris.", dns.TypeAAAA)
+		assert.Equal(t, 1, len(rsp.Answer))
+		assert.Equal(t, "fd00::1", rsp.Answer[0].(*dns.AAAA).AAAA.String())
+	}
+
+	rsp := exchange(t, "udp", udpAddr, "_http._tcp.echo.default.polaris.", dns.TypeSRV)
+	assert.Equal(t, 4, len(rsp.Answer))
+	srv := rsp.Answer[1].(*dns.SRV)
+	assert.Equal(t, uint16(200), srv.Weight)
+	assert.Equal(t, uint16(1), srv.Priority)
+	assert.Equal(t, uint16(8080), srv.Port)
+	assert.Equal(t, "10-0-0-2.addr.polaris.", srv.Target)
+	assert.Equal(t, "echo.example.com.", rsp.Answer[3].(*dns.SRV).Target)
+	assert.Equal(t, 3, len(rsp.Extra))
+
+	// SRV 的 target 可以继续解析
+	rsp = exchange(t, "udp", udpAddr, "10-0-0-2.addr.polaris.", dns.TypeA)
+	assert.Equal(t, 1, len(rsp.Answer))
+	assert.Equal(t, "10.0.0.2", rsp.Answer[0].(*dns.A).A.String())
+	rsp = exchange(t, "udp", udpAddr, rsp.Answer[0].Header().Name, dns.TypeAAAA)
+	assert.Equal(t, 0, len(rsp.Answer))
+	rsp = exchange(t, "udp", udpAddr, h.target("fd00::1"), dns.TypeAAAA)
+	assert.Equal(t, "fd00::1", rsp.Answer[0].(*dns.AAAA).AAAA.String())
+
+	rsp = exchange(t, "udp", udpAddr, "empty.default.polaris.", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, rsp.Rcode)
+	assert.Equal(t, 0, len(rsp.Answer))
+	assert.Equal(t, 1, len(rsp.Ns))
+
+	rsp = exchange(t, "udp", udpAddr, "unknown.default.polaris.", dns.TypeA)
+	assert.Equal(t, dns.RcodeNameError, rsp.Rcode)
+
+	rsp = exchange(t, "udp", udpAddr, "echo.polaris.", dns.TypeA)
+	assert.Equal(t, dns.RcodeNameError, rsp.Rcode)
+
+	rsp = exchange(t, "udp", udpAddr, "www.example.com.", dns.TypeA)
+	assert.Equal(t, dns.RcodeRefused, rsp.Rcode)
+}
+
+func TestServeDNSTruncate(t *testing.T) {
+	instances := make([]*model.Instance, 0, 100)
+	for i := 0; i < 100; i++ {
+		instances = append(instances, newTestInstance(fmt.Sprintf("10.0.1.%d", i), 8080, 100, 0, true, false))
+	}
+	services := map[string][]*model.Instance{"default/echo": instances}
+	udpAddr, tcpAddr, stop := startTestServer(t, newHandler(DefaultDomain, DefaultTTL, mockInstanceGetter(services)))
+	defer stop()
+
+	rsp := exchange(t, "udp", udpAddr, "echo.default.polaris.", dns.TypeA)
+	assert.True(t, rsp.Truncated)
+	assert.True(t, len(rsp.Answer) < 100)
+
+	rsp = exchange(t, "tcp", tcpAddr, "echo.default.polaris.", dns.TypeA)
+	assert.False(t, rsp.Truncated)
+	assert.Equal(t, 100, len(rsp.Answer))
+}
+
+func TestDecodeAddr(t *testing.T) {
+	h := newHandler(DefaultDomain, DefaultTTL, nil)
+	assert.Equal(t, "1-2-3-4.addr.polaris.", h.target("1.2.3.4"))
+	assert.Equal(t, "1.2.3.4", decodeAddr("1-2-3-4").String())
+	assert.Equal(t, "fd00::1", decodeAddr("fd000000000000000000000000000001").String())
+	assert.Nil(t, decodeAddr("abc"))
+	assert.Equal(t, "host.example.com.", h.target("host.example.com"))
+}
@@ -0,0 +1,206 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/apiserver"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service"
+)
+
+const (
+	// DefaultDomain 默认的域名后缀，查询 <service>.<namespace>.polaris.
+	DefaultDomain = "polaris."
+	// DefaultTTL 默认的记录 TTL，单位秒，实例变化较快，TTL 不宜过长
+	DefaultTTL = 5
+
+	optionListenIP   = "listenIP"
+	optionListenPort = "listenPort"
+	optionDomain     = "domain"
+	optionTTL        = "ttl"
+)
+
+// DNSServer 通过 DNS 协议提供服务发现，同时监听 udp 和 tcp
+type DNSServer struct {
+	listenIP   string
+	listenPort uint32
+	option     map[string]interface{}
+	openAPI    map[string]apiserver.APIConfig
+	start      bool
+	restart    bool
+	exitCh     chan struct{}
+
+	domain  string
+	ttl     uint32
+	servers []*dns.Server
+
+	namingServer service.DiscoverServer
+}
+
+// GetPort 获取端口
+func (h *DNSServer) GetPort() uint32 {
+	return h.listenPort
+}
+
+// GetProtocol 获取Server的协议
+func (h *DNSServer) GetProtocol() string {
+	return "dns"
+}
+
+// Initialize 初始化DNS服务器
+func (h *DNSServer) Initialize(_ context.Context, option map[string]interface{},
+	api map[string]apiserver.APIConfig) error {
+	h.option = option
+	h.openAPI = api
+	h.listenIP, _ = option[optionListenIP].(string)
+	h.listenPort = uint32(option[optionListenPort].(int))
+
+	h.domain = DefaultDomain
+	if domain, _ := option[optionDomain].(string); len(domain) > 0 {
+		h.domain = dns.Fqdn(strings.ToLower(strings.TrimPrefix(domain, ".")))
+	}
+	h.ttl = DefaultTTL
+	if ttl, ok := option[optionTTL].(int); ok && ttl >= 0 {
+		h.ttl = uint32(ttl)
+	}
+	return nil
+}
+
+// Run 启动DNS服务器
+func (h *DNSServer) Run(errCh chan error) {
+	log.Infof("[API-Server][DNS] start server")
+	h.exitCh = make(chan struct{}, 1)
+	h.start = true
+	defer func() {
+		close(h.exitCh)
+		h.start = false
+	}()
+
+	var err error
+
+	// 引入功能模块和插件
+	h.namingServer, err = service.GetServer()
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+
+	address := fmt.Sprintf("%v:%v", h.listenIP, h.listenPort)
+	handler := newHandler(h.domain, h.ttl, h.getInstances)
+	h.servers = []*dns.Server{
+		{Addr: address, Net: "udp", Handler: handler},
+		{Addr: address, Net: "tcp", Handler: handler},
+	}
+
+	// udp 和 tcp 任意一个退出，都认为服务退出
+	serveErrCh := make(chan error, len(h.servers))
+	wg := &sync.WaitGroup{}
+	for _, server := range h.servers {
+		wg.Add(1)
+		go func(server *dns.Server) {
+			defer wg.Done()
+			serveErrCh <- server.ListenAndServe()
+		}(server)
+	}
+	err = <-serveErrCh
+	h.shutdown()
+	wg.Wait()
+	if err != nil {
+		log.Errorf("%+v", err)
+		if !h.restart {
+			log.Info("[API-Server][DNS] not in restart progress", zap.Error(err))
+			errCh <- err
+		}
+		return
+	}
+
+	log.Infof("[API-Server][DNS] server stop")
+}
+
+// getInstances 从缓存中获取服务的实例，别名服务返回其指向的服务的实例
+func (h *DNSServer) getInstances(namespace, serviceName string) ([]*model.Instance, bool) {
+	cacheMgr := h.namingServer.Cache()
+	svc := cacheMgr.Service().GetServiceByName(serviceName, namespace)
+	if svc == nil {
+		return nil, false
+	}
+	if svc.IsAlias() {
+		if svc = cacheMgr.Service().GetServiceByID(svc.Reference); svc == nil {
+			return nil, false
+		}
+	}
+	return cacheMgr.Instance().GetInstancesByServiceID(svc.ID), true
+}
+
+func (h *DNSServer) shutdown() {
+	for _, server := range h.servers {
+		// 未启动完成的 server 关闭时会返回错误，忽略即可
+		_ = server.Shutdown()
+	}
+}
+
+// Stop shutdown server
+func (h *DNSServer) Stop() {
+	h.shutdown()
+}
+
+// Restart restart server
+func (h *DNSServer) Restart(option map[string]interface{}, api map[string]apiserver.APIConfig,
+	errCh chan error) error {
+	log.Infof("[API-Server][DNS] restart server new config: %+v", option)
+	// 备份一下option
+	backupOption := h.option
+	// 备份一下api
+	backupAPI := h.openAPI
+
+	// 设置restart标记，防止stop的时候把错误抛出
+	h.restart = true
+	h.Stop()
+	if h.start {
+		<-h.exitCh
+	}
+
+	log.Infof("[API-Server][DNS] old server has stopped, begin restart server")
+	ctx := context.Background()
+	if err := h.Initialize(ctx, option, api); err != nil {
+		h.restart = false
+		if initErr := h.Initialize(ctx, backupOption, backupAPI); initErr != nil {
+			log.Errorf("[API-Server][DNS] start server with backup cfg err: %s", initErr.Error())
+			return initErr
+		}
+		go h.Run(errCh)
+
+		log.Errorf("[API-Server][DNS] restart server err: %s", err.Error())
+		return err
+	}
+
+	log.Infof("[API-Server][DNS] init server successfully, restart it")
+	h.restart = false
+	go h.Run(errCh)
+	return nil
+}
@@ -45,6 +45,7 @@ func (h *EurekaServer) GetEurekaServer() *restful.WebService {
 	ws.Path("/eureka").Consumes(restful.MIME_JSON, restful.MIME_OCTET, restful.MIME_XML).Produces(restful.MIME_JSON,
 		restful.MIME_XML)
 	h.addDiscoverAccess(ws)
+	h.addReplicateAccess(ws)
 	return ws
 }
 
@@ -55,6 +56,7 @@ func (h *EurekaServer) GetEurekaV1Server() *restful.WebService {
 	ws.Path("/eureka/v1").Consumes(restful.MIME_JSON, restful.MIME_OCTET, restful.MIME_XML).Produces(restful.MIME_JSON,
 		restful.MIME_XML)
 	h.addDiscoverAccess(ws)
+	h.addReplicateAccess(ws)
 	return ws
 }
 
@@ -65,6 +67,7 @@ func (h *EurekaServer) GetEurekaV2Server() *restful.WebService {
 	ws.Path("/eureka/v2").Consumes(restful.MIME_JSON, restful.MIME_OCTET, restful.MIME_XML).Produces(restful.MIME_JSON,
 		restful.MIME_XML)
 	h.addDiscoverAccess(ws)
+	h.addReplicateAccess(ws)
 	return ws
 }
 
@@ -373,6 +376,13 @@ func (h *EurekaServer) RegisterApplication(req *restful.Request, rsp *restful.Re
 	if code == api.ExecuteSuccess || code == api.ExistedResource || code == api.SameInstanceRequest {
 		log.Infof("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been registered successfully, code is %d",
 			registrationRequest.Instance.InstanceId, appId, code)
+		h.replicate(req, &ReplicationInstance{
+			AppName:      appId,
+			Id:           registrationRequest.Instance.InstanceId,
+			Status:       registrationRequest.Instance.Status,
+			InstanceInfo: registrationRequest.Instance,
+			Action:       ActionRegister,
+		})
 		writePolarisStatusCode(req, code)
 		writeHeader(http.StatusNoContent, rsp)
 		return
@@ -415,6 +425,13 @@ func (h *EurekaServer) UpdateStatus(req *restful.Request, rsp *restful.Response)
 	writePolarisStatusCode(req, code)
 	if code == api.ExecuteSuccess {
 		log.Infof("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been updated successfully", instId, appId)
+		h.replicate(req, &ReplicationInstance{
+			AppName:          appId,
+			Id:               instId,
+			Status:           status,
+			OverriddenStatus: status,
+			Action:           ActionStatusUpdate,
+		})
 		writeHeader(http.StatusOK, rsp)
 		return
 	}
@@ -450,11 +467,12 @@ func (h *EurekaServer) DeleteStatus(req *restful.Request, rsp *restful.Response)
 	log.Infof("[EUREKA-SERVER]received instance status delete request, client: %s, instId=%s, appId=%s",
 		remoteAddr, instId, appId)
 
-	code := h.updateStatus(context.Background(), appId, instId, StatusUp)
+	code := h.deleteStatusOverride(context.Background(), appId, instId)
 	writePolarisStatusCode(req, code)
 	if code == api.ExecuteSuccess {
 		log.Infof("[EUREKA-SERVER]instance status (instId=%s, appId=%s) has been deleted successfully",
 			instId, appId)
+		h.replicate(req, &ReplicationInstance{AppName: appId, Id: instId, Action: ActionDeleteStatusOverride})
 		writeHeader(http.StatusOK, rsp)
 		return
 	}
@@ -489,6 +507,7 @@ func (h *EurekaServer) RenewInstance(req *restful.Request, rsp *restful.Response
 	code := h.renew(context.Background(), appId, instId)
 	writePolarisStatusCode(req, code)
 	if code == api.ExecuteSuccess || code == api.HeartbeatExceedLimit {
+		h.replicate(req, &ReplicationInstance{AppName: appId, Id: instId, Action: ActionHeartbeat})
 		writeHeader(http.StatusOK, rsp)
 		return
 	}
@@ -525,6 +544,7 @@ func (h *EurekaServer) CancelInstance(req *restful.Request, rsp *restful.Respons
 	code := h.deregisterInstance(context.Background(), appId, instId)
 	writePolarisStatusCode(req, code)
 	if code == api.ExecuteSuccess || code == api.NotFoundResource || code == api.SameInstanceRequest {
+		h.replicate(req, &ReplicationInstance{AppName: appId, Id: instId, Action: ActionCancel})
 		writeHeader(http.StatusOK, rsp)
 		log.Infof("[EUREKA-SERVER]instance (instId=%s, appId=%s) has been deregistered successfully, code is %d",
 			instId, appId, code)
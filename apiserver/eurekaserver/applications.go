@@ -57,6 +57,12 @@ func getCacheServices(namingServer service.DiscoverServer, namespace string) map
 		}
 		return true, nil
 	})
+	// 其他命名空间导出过来的服务，同样作为当前命名空间的 application 返回
+	for _, value := range namingServer.Cache().Service().GetExportedServices(namespace) {
+		if _, ok := newServices[value.Name]; !ok {
+			newServices[value.Name] = value
+		}
+	}
 	return newServices
 }
 
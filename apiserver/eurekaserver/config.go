@@ -28,6 +28,7 @@ const (
 	optionConnLimit              = "connLimit"
 	optionTLS                    = "tls"
 	optionEnableSelfPreservation = "enableSelfPreservation"
+	optionPeersToReplicate       = "peersToReplicate"
 )
 
 const (
@@ -42,4 +43,12 @@ const (
 	// DefaultSelfPreservationDuration instance unhealthy check point to preservation,
 	// instances over 15 min won't get preservation
 	DefaultSelfPreservationDuration = 15 * time.Minute
+	// DefaultReplicateBatchSize max replication count in one peer batch request
+	DefaultReplicateBatchSize = 250
+	// DefaultReplicateInterval interval to flush replication tasks to peers
+	DefaultReplicateInterval = 500 * time.Millisecond
+	// DefaultReplicateQueueSize max pending replication tasks
+	DefaultReplicateQueueSize = 10000
+	// DefaultReplicateTimeout timeout of peer batch request
+	DefaultReplicateTimeout = 5 * time.Second
 )
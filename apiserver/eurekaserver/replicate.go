@@ -0,0 +1,224 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// HeaderReplication eureka 节点之间复制请求的标识头
+	HeaderReplication = "x-netflix-discovery-replication"
+
+	ActionHeartbeat            = "Heartbeat"
+	ActionRegister             = "Register"
+	ActionCancel               = "Cancel"
+	ActionStatusUpdate         = "StatusUpdate"
+	ActionDeleteStatusOverride = "DeleteStatusOverride"
+
+	operationReplicate = "POST:/eureka/peerreplication/batch"
+
+	replicatePath = "/peerreplication/batch"
+)
+
+// ReplicationList eureka 节点之间批量复制的请求
+type ReplicationList struct {
+	XMLName struct{} `json:"-" xml:"replicationList"`
+
+	ReplicationList []*ReplicationInstance `json:"replicationList" xml:"replicationInstance"`
+}
+
+// ReplicationInstance 单条复制请求
+type ReplicationInstance struct {
+	XMLName struct{} `json:"-" xml:"replicationInstance"`
+
+	AppName string `json:"appName" xml:"appName"`
+
+	Id string `json:"id" xml:"id"`
+
+	LastDirtyTimestamp int64 `json:"lastDirtyTimestamp" xml:"lastDirtyTimestamp"`
+
+	OverriddenStatus string `json:"overriddenStatus,omitempty" xml:"overriddenStatus,omitempty"`
+
+	Status string `json:"status,omitempty" xml:"status,omitempty"`
+
+	InstanceInfo *InstanceInfo `json:"instanceInfo,omitempty" xml:"instance,omitempty"`
+
+	Action string `json:"action" xml:"action"`
+}
+
+// ReplicationListResponse 批量复制的应答，与请求一一对应
+type ReplicationListResponse struct {
+	XMLName struct{} `json:"-" xml:"replicationListResponse"`
+
+	ResponseList []*ReplicationInstanceResponse `json:"responseList" xml:"replicationInstanceResponse"`
+}
+
+// ReplicationInstanceResponse 单条复制请求的应答
+type ReplicationInstanceResponse struct {
+	XMLName struct{} `json:"-" xml:"replicationInstanceResponse"`
+
+	StatusCode int `json:"statusCode" xml:"statusCode"`
+
+	ResponseEntity *InstanceInfo `json:"responseEntity,omitempty" xml:"instance,omitempty"`
+}
+
+// addReplicateAccess 增加 eureka 节点间复制的接口
+func (h *EurekaServer) addReplicateAccess(ws *restful.WebService) {
+	// Receive batch replication from eureka peers
+	ws.Route(ws.POST(replicatePath).To(h.BatchReplication))
+}
+
+// isReplicationRequest 判断请求是否来自于其他 eureka 节点的复制
+func isReplicationRequest(req *restful.Request) bool {
+	return strings.EqualFold(getParamFromEurekaRequestHeader(req, HeaderReplication), "true")
+}
+
+// BatchReplication 处理其他 eureka 节点的批量复制请求
+func (h *EurekaServer) BatchReplication(req *restful.Request, rsp *restful.Response) {
+	remoteAddr := req.Request.RemoteAddr
+	replicationList := &ReplicationList{}
+	if err := req.ReadEntity(replicationList); err != nil {
+		log.Errorf("[EUREKA-SERVER] fail to parse peer replication request, uri: %s, client: %s, err: %v",
+			req.Request.RequestURI, remoteAddr, err)
+		writePolarisStatusCode(req, api.ParseException)
+		writeHeader(http.StatusBadRequest, rsp)
+		return
+	}
+
+	token, err := getAuthFromEurekaRequestHeader(req)
+	if err != nil {
+		log.Infof("[EUREKA-SERVER]peer replication get basic auth info fail, client: %s", remoteAddr)
+		writePolarisStatusCode(req, api.ExecuteException)
+		writeHeader(http.StatusUnauthorized, rsp)
+		return
+	}
+	ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, token)
+
+	log.Infof("[EUREKA-SERVER]received peer replication request, client: %s, size: %d",
+		remoteAddr, len(replicationList.ReplicationList))
+	replicationRsp := &ReplicationListResponse{
+		ResponseList: make([]*ReplicationInstanceResponse, 0, len(replicationList.ReplicationList)),
+	}
+	for _, instance := range replicationList.ReplicationList {
+		replicationRsp.ResponseList = append(replicationRsp.ResponseList, h.dispatch(ctx, instance))
+	}
+
+	acceptValue := getParamFromEurekaRequestHeader(req, restful.HEADER_Accept)
+	if err := writeEurekaResponse(acceptValue, replicationRsp, req, rsp); err != nil {
+		log.Errorf("[EUREKA-SERVER]fail to write peer replication response, client: %s, err: %v", remoteAddr, err)
+	}
+}
+
+// dispatch 根据复制的动作，执行对应的操作
+func (h *EurekaServer) dispatch(ctx context.Context, instance *ReplicationInstance) *ReplicationInstanceResponse {
+	appId := strings.ToUpper(instance.AppName)
+	var code uint32
+	switch instance.Action {
+	case ActionRegister:
+		if instance.InstanceInfo == nil {
+			return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+		}
+		if err := convertInstancePorts(instance.InstanceInfo); err != nil {
+			log.Errorf("[EUREKA-SERVER]fail to parse replicated instance(instId=%s, appId=%s) port, err: %v",
+				instance.Id, appId, err)
+			return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+		}
+		code = h.registerInstances(ctx, appId, instance.InstanceInfo)
+		if code == api.ExistedResource || code == api.SameInstanceRequest {
+			code = api.ExecuteSuccess
+		}
+	case ActionHeartbeat:
+		code = h.renew(ctx, appId, instance.Id)
+		if code == api.HeartbeatExceedLimit {
+			code = api.ExecuteSuccess
+		}
+	case ActionCancel:
+		code = h.deregisterInstance(ctx, appId, instance.Id)
+		if code == api.SameInstanceRequest {
+			code = api.ExecuteSuccess
+		}
+	case ActionStatusUpdate:
+		if instance.Status == StatusUnknown {
+			return &ReplicationInstanceResponse{StatusCode: http.StatusOK}
+		}
+		code = h.updateStatus(ctx, appId, instance.Id, instance.Status)
+	case ActionDeleteStatusOverride:
+		code = h.deleteStatusOverride(ctx, appId, instance.Id)
+	default:
+		log.Errorf("[EUREKA-SERVER]unknown peer replication action %s, instId=%s, appId=%s",
+			instance.Action, instance.Id, appId)
+		return &ReplicationInstanceResponse{StatusCode: http.StatusBadRequest}
+	}
+
+	switch code {
+	case api.ExecuteSuccess:
+		return &ReplicationInstanceResponse{StatusCode: http.StatusOK}
+	case api.NotFoundResource, api.NotFoundInstance:
+		// 心跳返回 404 时，源节点会重新发起注册
+		return &ReplicationInstanceResponse{StatusCode: http.StatusNotFound}
+	default:
+		log.Errorf("[EUREKA-SERVER]peer replication action %s (instId=%s, appId=%s) failed, code is %d",
+			instance.Action, instance.Id, appId, code)
+		return &ReplicationInstanceResponse{StatusCode: int(code / 1000)}
+	}
+}
+
+// convertInstancePorts 转换复制请求中实例的端口信息
+func convertInstancePorts(instance *InstanceInfo) error {
+	for _, port := range []*PortWrapper{instance.Port, instance.SecurePort} {
+		if port == nil {
+			continue
+		}
+		if err := port.convertPortValue(); err != nil {
+			return err
+		}
+		if err := port.convertEnableValue(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replicate 将本节点接收到的客户端请求复制到其他 eureka 节点
+func (h *EurekaServer) replicate(req *restful.Request, instance *ReplicationInstance) {
+	if h.replicateWorker == nil || isReplicationRequest(req) {
+		return
+	}
+	h.replicateWorker.AddReplicateTask(instance)
+}
+
+// getReplicateInstance 从缓存中获取实例信息，用于向其他 eureka 节点重新注册
+func (h *EurekaServer) getReplicateInstance(appName string, instId string) *InstanceInfo {
+	appsRespCache := h.worker.GetCachedAppsWithLoad()
+	if appsRespCache == nil {
+		return nil
+	}
+	app := appsRespCache.AppsResp.Applications.GetApplication(strings.ToUpper(appName))
+	if app == nil {
+		return nil
+	}
+	return app.GetInstance(instId)
+}
@@ -0,0 +1,223 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/service"
+)
+
+const replicationListJson = `{
+  "replicationList": [
+    {
+      "appName": "ECHO",
+      "id": "127.0.0.1:echo:8080",
+      "lastDirtyTimestamp": 1680000000000,
+      "status": "UP",
+      "instanceInfo": {
+        "instanceId": "127.0.0.1:echo:8080",
+        "app": "ECHO",
+        "ipAddr": "127.0.0.1",
+        "status": "UP",
+        "port": {"$": 8080, "@enabled": "true"},
+        "securePort": {"$": 443, "@enabled": "false"},
+        "dataCenterInfo": {"@class": "com.netflix.appinfo.InstanceInfo$DefaultDataCenterInfo", "name": "MyOwn"}
+      },
+      "action": "Register"
+    },
+    {
+      "appName": "ECHO",
+      "id": "127.0.0.1:echo:8080",
+      "lastDirtyTimestamp": 1680000000000,
+      "action": "Heartbeat"
+    }
+  ]
+}`
+
+func TestReplicationList_Unmarshal(t *testing.T) {
+	replicationList := &ReplicationList{}
+	decoder := json.NewDecoder(strings.NewReader(replicationListJson))
+	decoder.UseNumber()
+	assert.Nil(t, decoder.Decode(replicationList))
+	assert.Equal(t, 2, len(replicationList.ReplicationList))
+
+	register := replicationList.ReplicationList[0]
+	assert.Equal(t, ActionRegister, register.Action)
+	assert.Equal(t, int64(1680000000000), register.LastDirtyTimestamp)
+	assert.NotNil(t, register.InstanceInfo)
+	assert.Nil(t, convertInstancePorts(register.InstanceInfo))
+	assert.Equal(t, 8080, register.InstanceInfo.Port.RealPort)
+	assert.True(t, register.InstanceInfo.Port.RealEnable)
+	assert.Equal(t, 443, register.InstanceInfo.SecurePort.RealPort)
+	assert.False(t, register.InstanceInfo.SecurePort.RealEnable)
+
+	heartbeat := replicationList.ReplicationList[1]
+	assert.Equal(t, ActionHeartbeat, heartbeat.Action)
+	assert.Nil(t, heartbeat.InstanceInfo)
+
+	// xml 格式的往返
+	data, err := xml.Marshal(replicationList)
+	assert.Nil(t, err)
+	xmlList := &ReplicationList{}
+	assert.Nil(t, xml.Unmarshal(data, xmlList))
+	assert.Equal(t, 2, len(xmlList.ReplicationList))
+	assert.Equal(t, "127.0.0.1", xmlList.ReplicationList[0].InstanceInfo.IpAddr)
+	assert.Equal(t, 8080, xmlList.ReplicationList[0].InstanceInfo.Port.RealPort)
+	assert.Equal(t, ActionHeartbeat, xmlList.ReplicationList[1].Action)
+}
+
+func TestEurekaServer_dispatchInvalid(t *testing.T) {
+	h := &EurekaServer{}
+	rsp := h.dispatch(context.Background(), &ReplicationInstance{AppName: "echo", Id: "1", Action: "Unknown"})
+	assert.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+	rsp = h.dispatch(context.Background(), &ReplicationInstance{AppName: "echo", Id: "1", Action: ActionRegister})
+	assert.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+}
+
+type statusNamingServer struct {
+	service.DiscoverServer
+	updated []*api.Instance
+	code    uint32
+}
+
+func (m *statusNamingServer) UpdateInstances(ctx context.Context, req []*api.Instance) *api.BatchWriteResponse {
+	m.updated = append(m.updated, req...)
+	return api.NewBatchWriteResponse(m.code)
+}
+
+func TestEurekaServer_dispatchDeleteStatusOverride(t *testing.T) {
+	naming := &statusNamingServer{code: api.ExecuteSuccess}
+	h := &EurekaServer{namingServer: naming}
+	rsp := h.dispatch(context.Background(),
+		&ReplicationInstance{AppName: "echo", Id: "1", Action: ActionDeleteStatusOverride})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	assert.Equal(t, 1, len(naming.updated))
+	// 只解除隔离，不修改实例的健康状态
+	assert.False(t, naming.updated[0].GetIsolate().GetValue())
+	assert.NotNil(t, naming.updated[0].GetIsolate())
+	assert.Nil(t, naming.updated[0].GetHealthy())
+
+	// 实例本身未被覆盖状态
+	naming = &statusNamingServer{code: api.NoNeedUpdate}
+	h = &EurekaServer{namingServer: naming}
+	rsp = h.dispatch(context.Background(),
+		&ReplicationInstance{AppName: "echo", Id: "1", Action: ActionDeleteStatusOverride})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+}
+
+func TestReplicateWorker(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		received []*ReplicationList
+	)
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, replicatePath, r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get(HeaderReplication))
+		replicationList := &ReplicationList{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(replicationList))
+		lock.Lock()
+		received = append(received, replicationList)
+		lock.Unlock()
+
+		replicationRsp := &ReplicationListResponse{}
+		for _, item := range replicationList.ReplicationList {
+			statusCode := http.StatusOK
+			// 对端不存在该实例
+			if item.Action == ActionHeartbeat {
+				statusCode = http.StatusNotFound
+			}
+			replicationRsp.ResponseList = append(replicationRsp.ResponseList,
+				&ReplicationInstanceResponse{StatusCode: statusCode})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(replicationRsp)
+	}))
+	defer peer.Close()
+
+	instance := &InstanceInfo{InstanceId: "ins-1", AppName: "ECHO", IpAddr: "127.0.0.1", Status: StatusUp}
+	worker := NewReplicateWorker([]string{peer.URL + "/"}, 2, 50*time.Millisecond,
+		func(appName string, instId string) *InstanceInfo {
+			if appName == "ECHO" && instId == "ins-1" {
+				return instance
+			}
+			return nil
+		})
+	defer worker.Stop()
+
+	worker.AddReplicateTask(&ReplicationInstance{AppName: "ECHO", Id: "ins-1", Action: ActionHeartbeat})
+	worker.AddReplicateTask(&ReplicationInstance{AppName: "ECHO", Id: "ins-2", Action: ActionCancel})
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(received) == 2
+	}, 3*time.Second, 20*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, 2, len(received[0].ReplicationList))
+	assert.NotZero(t, received[0].ReplicationList[0].LastDirtyTimestamp)
+	// 心跳 404 后重新注册
+	assert.Equal(t, 1, len(received[1].ReplicationList))
+	register := received[1].ReplicationList[0]
+	assert.Equal(t, ActionRegister, register.Action)
+	assert.Equal(t, "ins-1", register.Id)
+	assert.Equal(t, "127.0.0.1", register.InstanceInfo.IpAddr)
+}
+
+func TestGetEurekaApi_Replicate(t *testing.T) {
+	assert.Equal(t, operationReplicate, getEurekaApi(http.MethodPost, "/eureka/peerreplication/batch"))
+	assert.Equal(t, operationReplicate, getEurekaApi(http.MethodPost, "/eureka/v2/peerreplication/batch"))
+}
+
+func TestEurekaServer_BatchReplicationRoute(t *testing.T) {
+	h := &EurekaServer{}
+	container := restful.NewContainer()
+	container.Add(h.GetEurekaServer())
+
+	for _, path := range []string{"/eureka/peerreplication/batch", "/eureka/peerreplication/batch/"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{invalid"))
+		req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+		rsp := httptest.NewRecorder()
+		container.ServeHTTP(rsp, req)
+		assert.Equal(t, http.StatusBadRequest, rsp.Code, path)
+	}
+
+	// 空的复制请求直接返回空应答
+	req := httptest.NewRequest(http.MethodPost, "/eureka/peerreplication/batch",
+		strings.NewReader(`{"replicationList":[]}`))
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	req.Header.Set(restful.HEADER_Accept, restful.MIME_JSON)
+	rsp := httptest.NewRecorder()
+	container.ServeHTTP(rsp, req)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.JSONEq(t, `{"responseList":[]}`, rsp.Body.String())
+}
@@ -0,0 +1,190 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package eurekaserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+)
+
+// ReplicateWorker 将本节点的注册信息批量复制到其他 eureka 节点
+type ReplicateWorker struct {
+	peers []string
+
+	batchSize int
+
+	batchInterval time.Duration
+
+	taskChannel chan *ReplicationInstance
+
+	client *http.Client
+
+	// 用于心跳复制返回 404 时，获取实例信息重新注册
+	instanceGetter func(appName string, instId string) *InstanceInfo
+
+	cancel context.CancelFunc
+}
+
+// NewReplicateWorker 构造函数
+func NewReplicateWorker(peers []string, batchSize int, batchInterval time.Duration,
+	instanceGetter func(appName string, instId string) *InstanceInfo) *ReplicateWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	worker := &ReplicateWorker{
+		peers:          peers,
+		batchSize:      batchSize,
+		batchInterval:  batchInterval,
+		taskChannel:    make(chan *ReplicationInstance, DefaultReplicateQueueSize),
+		client:         &http.Client{Timeout: DefaultReplicateTimeout},
+		instanceGetter: instanceGetter,
+		cancel:         cancel,
+	}
+	go worker.run(ctx)
+	return worker
+}
+
+// AddReplicateTask 添加复制任务，队列满时丢弃，依赖客户端后续的心跳进行修复
+func (r *ReplicateWorker) AddReplicateTask(task *ReplicationInstance) {
+	if task.LastDirtyTimestamp == 0 {
+		task.LastDirtyTimestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	select {
+	case r.taskChannel <- task:
+	default:
+		log.Warnf("[EUREKA-SERVER]replicate queue is full, drop task, action: %s, instId: %s, appId: %s",
+			task.Action, task.Id, task.AppName)
+	}
+}
+
+// Stop 停止复制
+func (r *ReplicateWorker) Stop() {
+	r.cancel()
+}
+
+func (r *ReplicateWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(r.batchInterval)
+	defer ticker.Stop()
+	batch := make([]*ReplicationInstance, 0, r.batchSize)
+	for {
+		select {
+		case task := <-r.taskChannel:
+			batch = append(batch, task)
+			if len(batch) >= r.batchSize {
+				r.doBatchReplicate(batch)
+				batch = make([]*ReplicationInstance, 0, r.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				r.doBatchReplicate(batch)
+				batch = make([]*ReplicationInstance, 0, r.batchSize)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *ReplicateWorker) doBatchReplicate(batch []*ReplicationInstance) {
+	for _, peer := range r.peers {
+		r.replicateToPeer(peer, batch)
+	}
+}
+
+func (r *ReplicateWorker) replicateToPeer(peer string, batch []*ReplicationInstance) {
+	replicationRsp, err := r.sendBatch(peer, &ReplicationList{ReplicationList: batch})
+	if err != nil {
+		log.Error("[EUREKA-SERVER]fail to replicate to peer", zap.String("peer", peer),
+			zap.Int("size", len(batch)), zap.Error(err))
+		return
+	}
+	// 对端不存在该实例的心跳，需要重新注册
+	var registers []*ReplicationInstance
+	for i, itemRsp := range replicationRsp.ResponseList {
+		if i >= len(batch) {
+			break
+		}
+		task := batch[i]
+		if itemRsp.StatusCode == http.StatusNotFound && task.Action == ActionHeartbeat && r.instanceGetter != nil {
+			instance := r.instanceGetter(task.AppName, task.Id)
+			if instance == nil {
+				continue
+			}
+			registers = append(registers, &ReplicationInstance{
+				AppName:            task.AppName,
+				Id:                 task.Id,
+				LastDirtyTimestamp: task.LastDirtyTimestamp,
+				Status:             instance.Status,
+				InstanceInfo:       instance,
+				Action:             ActionRegister,
+			})
+			continue
+		}
+		if itemRsp.StatusCode >= http.StatusBadRequest && itemRsp.StatusCode != http.StatusNotFound {
+			log.Warn("[EUREKA-SERVER]peer replication item failed", zap.String("peer", peer),
+				zap.String("action", task.Action), zap.String("instId", task.Id),
+				zap.Int("status", itemRsp.StatusCode))
+		}
+	}
+	if len(registers) == 0 {
+		return
+	}
+	if _, err := r.sendBatch(peer, &ReplicationList{ReplicationList: registers}); err != nil {
+		log.Error("[EUREKA-SERVER]fail to re-register to peer", zap.String("peer", peer),
+			zap.Int("size", len(registers)), zap.Error(err))
+	}
+}
+
+func (r *ReplicateWorker) sendBatch(peer string, replicationList *ReplicationList) (*ReplicationListResponse, error) {
+	body, err := json.Marshal(replicationList)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(peer, "/") + replicatePath
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	req.Header.Set(restful.HEADER_Accept, restful.MIME_JSON)
+	req.Header.Set(HeaderReplication, "true")
+	rsp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	rspBody, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer %s replied status %d, body: %s", peer, rsp.StatusCode, string(rspBody))
+	}
+	replicationRsp := &ReplicationListResponse{}
+	if err := json.Unmarshal(rspBody, replicationRsp); err != nil {
+		return nil, err
+	}
+	return replicationRsp, nil
+}
@@ -132,6 +132,8 @@ type EurekaServer struct {
 	refreshInterval        time.Duration
 	deltaExpireInterval    time.Duration
 	enableSelfPreservation bool
+	peersToReplicate       []string
+	replicateWorker        *ReplicateWorker
 }
 
 // GetPort 获取端口
@@ -207,6 +209,16 @@ func (h *EurekaServer) Initialize(ctx context.Context, option map[string]interfa
 		enableSelfPreservation = DefaultEnableSelfPreservation
 	}
 	h.enableSelfPreservation = enableSelfPreservation
+
+	var peersToReplicate []string
+	if values, ok := option[optionPeersToReplicate].([]interface{}); ok {
+		for _, value := range values {
+			if peer, _ := value.(string); len(peer) > 0 {
+				peersToReplicate = append(peersToReplicate, peer)
+			}
+		}
+	}
+	h.peersToReplicate = peersToReplicate
 	return nil
 }
 
@@ -235,6 +247,11 @@ func (h *EurekaServer) Run(errCh chan error) {
 	}
 	h.worker = NewApplicationsWorker(h.refreshInterval, h.deltaExpireInterval, h.enableSelfPreservation,
 		h.namingServer, h.healthCheckServer, h.namespace)
+	if len(h.peersToReplicate) > 0 {
+		log.Infof("[EUREKA-SERVER]replicate registrations to peers %v", h.peersToReplicate)
+		h.replicateWorker = NewReplicateWorker(h.peersToReplicate, DefaultReplicateBatchSize,
+			DefaultReplicateInterval, h.getReplicateInstance)
+	}
 	h.statis = plugin.GetStatis()
 	// 初始化http server
 	address := fmt.Sprintf("%v:%v", h.listenIP, h.listenPort)
@@ -422,6 +439,9 @@ func getEurekaApi(method, path string) string {
 	if path == "" {
 		return ""
 	}
+	if method == http.MethodPost && strings.HasSuffix(path, replicatePath) {
+		return operationReplicate
+	}
 	if !strings.HasPrefix(path, pathPrefix) {
 		return path
 	}
@@ -476,6 +496,10 @@ func (h *EurekaServer) Stop() {
 		_ = h.server.Close()
 	}
 	h.worker.Stop()
+	if h.replicateWorker != nil {
+		h.replicateWorker.Stop()
+		h.replicateWorker = nil
+	}
 }
 
 // Restart 重启eurekaServer
@@ -209,6 +209,18 @@ func (h *EurekaServer) updateStatus(ctx context.Context, appId string, instanceI
 	return resp.GetCode().GetValue()
 }
 
+// deleteStatusOverride 删除 eureka 的状态覆盖，仅解除实例隔离，健康状态仍以实例自身上报为准
+func (h *EurekaServer) deleteStatusOverride(ctx context.Context, appId string, instanceId string) uint32 {
+	resp := h.namingServer.UpdateInstances(ctx,
+		[]*api.Instance{{Id: &wrappers.StringValue{Value: instanceId}, Isolate: &wrappers.BoolValue{Value: false}}})
+	code := resp.GetCode().GetValue()
+	// 实例本身没有被覆盖过状态，对于 eureka 来说，仍然属于删除成功
+	if code == api.NoNeedUpdate {
+		return api.ExecuteSuccess
+	}
+	return code
+}
+
 func (h *EurekaServer) renew(ctx context.Context, appId string, instanceId string) uint32 {
 	resp := h.healthCheckServer.Report(ctx, &api.Instance{Id: &wrappers.StringValue{Value: instanceId}})
 	code := resp.GetCode().GetValue()
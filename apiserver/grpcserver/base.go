@@ -404,6 +404,8 @@ func ConvertContext(ctx context.Context) context.Context {
 	var (
 		requestID = ""
 		userAgent = ""
+		publicKey = ""
+		clientID  = ""
 	)
 	meta, exist := metadata.FromIncomingContext(ctx)
 	if exist {
@@ -415,6 +417,14 @@ func ConvertContext(ctx context.Context) context.Context {
 		if len(agents) > 0 {
 			userAgent = agents[0]
 		}
+		publicKeys := meta[strings.ToLower(utils.HeaderConfigPublicKey)]
+		if len(publicKeys) > 0 {
+			publicKey = publicKeys[0]
+		}
+		clientIDs := meta[strings.ToLower(utils.HeaderClientIDKey)]
+		if len(clientIDs) > 0 {
+			clientID = clientIDs[0]
+		}
 	} else {
 		meta = metadata.MD{}
 	}
@@ -437,6 +447,12 @@ func ConvertContext(ctx context.Context) context.Context {
 	ctx = context.WithValue(ctx, utils.StringContext("client-ip"), clientIP)
 	ctx = context.WithValue(ctx, utils.ContextClientAddress, address)
 	ctx = context.WithValue(ctx, utils.StringContext("user-agent"), userAgent)
+	if publicKey != "" {
+		ctx = context.WithValue(ctx, utils.ContextConfigPublicKey, publicKey)
+	}
+	if clientID != "" {
+		ctx = context.WithValue(ctx, utils.ContextClientIDKey, clientID)
+	}
 
 	return ctx
 }
@@ -19,9 +19,14 @@ package config
 
 import (
 	"context"
+	"io"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"github.com/polarismesh/polaris/apiserver/grpcserver"
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
 )
 
 // GetConfigFile 拉取配置
@@ -49,3 +54,74 @@ func (g *ConfigGRPCServer) WatchConfigFiles(ctx context.Context,
 
 	return callback(), nil
 }
+
+// WatchConfigFilesStream 通过双向流订阅配置变更，一个流上可以增量订阅、取消订阅多个配置文件，
+// 配置发布后服务端合并推送发生变更的配置文件
+func (g *ConfigGRPCServer) WatchConfigFilesStream(server api.PolarisConfigStreamGRPC_WatchConfigFilesStreamServer) error {
+	ctx := grpcserver.ConvertContext(server.Context())
+	clientIP, _ := ctx.Value(utils.StringContext("client-ip")).(string)
+	clientAddress, _ := ctx.Value(utils.StringContext("client-address")).(string)
+	method, _ := grpc.MethodFromServerStream(server)
+
+	stream, err := g.configServer.OpenWatchStream(ctx, func(rsp *api.ConfigWatchStreamResponse) error {
+		return server.Send(rsp)
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for {
+		in, err := server.Recv()
+		if err != nil {
+			if io.EOF == err {
+				return nil
+			}
+			return err
+		}
+
+		configLog.Info("receive grpc config watch stream request",
+			zap.String("action", in.GetAction().GetValue()),
+			zap.Int("files", len(in.GetWatchFiles())),
+			zap.String("client-address", clientAddress))
+
+		// 是否允许访问
+		if ok := g.allowAccess(method); !ok {
+			if err := stream.Send(api.NewConfigWatchStreamResponse(api.ClientAPINotOpen, nil)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// stream模式，需要对每个包进行检测
+		if code := g.enterRateLimit(clientIP, method); code != api.ExecuteSuccess {
+			if err := stream.Send(api.NewConfigWatchStreamResponse(code, nil)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		code := api.ExecuteSuccess
+		switch in.GetAction().GetValue() {
+		case api.ConfigWatchStreamActionAdd:
+			code = stream.Add(in.GetWatchFiles())
+		case api.ConfigWatchStreamActionRemove:
+			stream.Remove(in.GetWatchFiles())
+		case api.ConfigWatchStreamActionError:
+			for _, file := range in.GetWatchFiles() {
+				rsp := g.configServer.ReportConfigFileError(ctx, file)
+				if rsp.GetCode().GetValue() != api.ExecuteSuccess {
+					code = rsp.GetCode().GetValue()
+				}
+			}
+		default:
+			code = api.InvalidParameter
+		}
+		if code == api.ExecuteSuccess {
+			continue
+		}
+		if err := stream.Send(api.NewConfigWatchStreamResponse(code, nil)); err != nil {
+			return err
+		}
+	}
+}
@@ -70,6 +70,7 @@ func (g *ConfigGRPCServer) Run(errCh chan error) {
 			case "client":
 				if apiConfig.Enable {
 					api.RegisterPolarisConfigGRPCServer(server, g)
+					api.RegisterPolarisConfigStreamGRPCServer(server, g)
 					openMethod, getErr := getConfigClientOpenMethod(g.GetProtocol())
 					if getErr != nil {
 						return getErr
@@ -133,6 +134,7 @@ func getConfigClientOpenMethod(protocol string) (map[string]bool, error) {
 		method := "/v1.PolarisConfig" + strings.ToUpper(protocol) + "/" + item
 		openMethod[method] = true
 	}
+	openMethod["/v1.PolarisConfigStream"+strings.ToUpper(protocol)+"/WatchConfigFilesStream"] = true
 
 	return openMethod, nil
 }
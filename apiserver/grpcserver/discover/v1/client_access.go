@@ -89,6 +89,20 @@ func (g *DiscoverServer) Discover(server api.PolarisGRPC_DiscoverServer) error {
 	userAgent, _ := ctx.Value(utils.StringContext("user-agent")).(string)
 	method, _ := grpc.MethodFromServerStream(server)
 
+	send := server.Send
+	// 订阅模式下服务端会主动推送，应答与推送共用一个串行的发送
+	var subscriber *discoverSubscriber
+	if isSubscribeMode(ctx) {
+		g.subscribes.start(g.namingServer.Cache())
+		subscriber = newDiscoverSubscriber(server)
+		send = subscriber.send
+		defer g.subscribes.removeSubscriber(subscriber)
+		// ctx 没有继承 stream 的生命周期，流结束时需要主动停止推送协程
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go subscriber.run(subCtx, g.discover)
+	}
+
 	for {
 		in, err := server.Recv()
 		if err != nil {
@@ -109,7 +123,7 @@ func (g *DiscoverServer) Discover(server api.PolarisGRPC_DiscoverServer) error {
 		// 是否允许访问
 		if ok := g.allowAccess(method); !ok {
 			resp := api.NewDiscoverResponse(api.ClientAPINotOpen)
-			if sendErr := server.Send(resp); sendErr != nil {
+			if sendErr := send(resp); sendErr != nil {
 				return sendErr
 			}
 			continue
@@ -118,35 +132,44 @@ func (g *DiscoverServer) Discover(server api.PolarisGRPC_DiscoverServer) error {
 		// stream模式，需要对每个包进行检测
 		if code := g.enterRateLimit(clientIP, method); code != api.ExecuteSuccess {
 			resp := api.NewDiscoverResponse(code)
-			if err = server.Send(resp); err != nil {
+			if err = send(resp); err != nil {
 				return err
 			}
 			continue
 		}
 
-		var out *api.DiscoverResponse
-		switch in.Type {
-		case api.DiscoverRequest_INSTANCE:
-			out = g.namingServer.ServiceInstancesCache(ctx, in.Service)
-		case api.DiscoverRequest_ROUTING:
-			out = g.namingServer.GetRoutingConfigWithCache(ctx, in.Service)
-		case api.DiscoverRequest_RATE_LIMIT:
-			out = g.namingServer.GetRateLimitWithCache(ctx, in.Service)
-		case api.DiscoverRequest_CIRCUIT_BREAKER:
-			out = g.namingServer.GetCircuitBreakerWithCache(ctx, in.Service)
-		case api.DiscoverRequest_SERVICES:
-			out = g.namingServer.GetServiceWithCache(ctx, in.Service)
-		default:
-			out = api.NewDiscoverRoutingResponse(api.InvalidDiscoverResource, in.Service)
+		out := g.discover(ctx, in)
+
+		if subscriber != nil && out.GetCode().GetValue() != api.InvalidDiscoverResource {
+			key := subscriber.subscribe(in, out)
+			g.subscribes.addSubscriber(key, subscriber)
 		}
 
-		err = server.Send(out)
+		err = send(out)
 		if err != nil {
 			return err
 		}
 	}
 }
 
+// discover 根据请求类型从缓存中获取资源
+func (g *DiscoverServer) discover(ctx context.Context, in *api.DiscoverRequest) *api.DiscoverResponse {
+	switch in.Type {
+	case api.DiscoverRequest_INSTANCE:
+		return g.namingServer.ServiceInstancesCache(ctx, in.Service)
+	case api.DiscoverRequest_ROUTING:
+		return g.namingServer.GetRoutingConfigWithCache(ctx, in.Service)
+	case api.DiscoverRequest_RATE_LIMIT:
+		return g.namingServer.GetRateLimitWithCache(ctx, in.Service)
+	case api.DiscoverRequest_CIRCUIT_BREAKER:
+		return g.namingServer.GetCircuitBreakerWithCache(ctx, in.Service)
+	case api.DiscoverRequest_SERVICES:
+		return g.namingServer.GetServiceWithCache(ctx, in.Service)
+	default:
+		return api.NewDiscoverRoutingResponse(api.InvalidDiscoverResource, in.Service)
+	}
+}
+
 // Heartbeat 上报心跳
 func (g *DiscoverServer) Heartbeat(ctx context.Context, in *api.Instance) (*api.Response, error) {
 	return g.healthCheckServer.Report(grpcserver.ConvertContext(ctx), in), nil
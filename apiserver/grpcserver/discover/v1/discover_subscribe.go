@@ -0,0 +1,462 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/polarismesh/polaris/cache"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// discoverModeHeader Discover 流的请求头，取值为 subscribe 时开启订阅模式
+	discoverModeHeader = "discover-mode"
+	// discoverModeSubscribe 订阅模式，客户端发送的每个 DiscoverRequest 都会注册为订阅，
+	// 服务端立即应答一次，后续只在服务的 revision 或者服务的路由、限流、熔断规则变化时主动推送
+	discoverModeSubscribe = "subscribe"
+)
+
+// isSubscribeMode Discover 流是否开启了订阅模式
+func isSubscribeMode(ctx context.Context) bool {
+	md, ok := ctx.Value(utils.ContextGrpcHeader).(metadata.MD)
+	if !ok {
+		return false
+	}
+	values := md.Get(discoverModeHeader)
+	return len(values) > 0 && values[0] == discoverModeSubscribe
+}
+
+type subscriptionKey struct {
+	reqType   api.DiscoverRequest_DiscoverRequestType
+	namespace string
+	service   string
+}
+
+func newSubscriptionKey(in *api.DiscoverRequest) subscriptionKey {
+	return subscriptionKey{
+		reqType:   in.Type,
+		namespace: in.GetService().GetNamespace().GetValue(),
+		service:   in.GetService().GetName().GetValue(),
+	}
+}
+
+// serviceKey 订阅在 subscribeCenter 中的索引，SERVICES 类型的订阅关注整个命名空间
+func (k subscriptionKey) serviceKey() string {
+	if k.reqType == api.DiscoverRequest_SERVICES {
+		return k.namespace + "/"
+	}
+	return k.namespace + "/" + k.service
+}
+
+type subscription struct {
+	request  *api.DiscoverRequest
+	revision string // 最近一次推送给客户端的 revision
+	// source 订阅的是别名或者其他命名空间导出的服务时，源服务的索引，事件中携带的是源服务
+	source string
+}
+
+// matches 订阅是否关注 serviceKey 对应服务的变化
+func (s *subscription) matches(key subscriptionKey, serviceKey string) bool {
+	return key.serviceKey() == serviceKey || (s.source != "" && s.source == serviceKey)
+}
+
+// discoverSubscriber 一条订阅模式的 Discover 流
+type discoverSubscriber struct {
+	server   api.PolarisGRPC_DiscoverServer
+	sendLock sync.Mutex // grpc stream 不支持并发 Send
+
+	lock          sync.Mutex
+	subscriptions map[subscriptionKey]*subscription
+	pending       map[subscriptionKey]struct{}
+	notifyCh      chan struct{}
+}
+
+func newDiscoverSubscriber(server api.PolarisGRPC_DiscoverServer) *discoverSubscriber {
+	return &discoverSubscriber{
+		server:        server,
+		subscriptions: make(map[subscriptionKey]*subscription),
+		pending:       make(map[subscriptionKey]struct{}),
+		notifyCh:      make(chan struct{}, 1),
+	}
+}
+
+func (s *discoverSubscriber) send(out *api.DiscoverResponse) error {
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+
+	return s.server.Send(out)
+}
+
+// subscribe 记录订阅以及已经应答给客户端的 revision，重复订阅时以最新的应答为准
+func (s *discoverSubscriber) subscribe(in *api.DiscoverRequest, out *api.DiscoverResponse) subscriptionKey {
+	key := newSubscriptionKey(in)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.subscriptions[key] = &subscription{
+		request:  proto.Clone(in).(*api.DiscoverRequest),
+		revision: responseRevision(out),
+	}
+	return key
+}
+
+// setSource 记录订阅对应的源服务索引
+func (s *discoverSubscriber) setSource(key subscriptionKey, source string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sub, ok := s.subscriptions[key]; ok {
+		sub.source = source
+	}
+}
+
+// markPending 标记满足条件的订阅需要重新检查
+func (s *discoverSubscriber) markPending(match func(key subscriptionKey, sub *subscription) bool) {
+	s.lock.Lock()
+	for key, sub := range s.subscriptions {
+		if match(key, sub) {
+			s.pending[key] = struct{}{}
+		}
+	}
+	s.lock.Unlock()
+
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *discoverSubscriber) takePending() map[subscriptionKey]*subscription {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ret := make(map[subscriptionKey]*subscription, len(s.pending))
+	for key := range s.pending {
+		if sub, ok := s.subscriptions[key]; ok {
+			ret[key] = &subscription{request: sub.request, revision: sub.revision, source: sub.source}
+		}
+	}
+	s.pending = make(map[subscriptionKey]struct{})
+	return ret
+}
+
+func (s *discoverSubscriber) updateRevision(key subscriptionKey, revision string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sub, ok := s.subscriptions[key]; ok {
+		sub.revision = revision
+	}
+}
+
+// run 等待订阅的资源变化的通知，只重新获取被标记的订阅，revision 发生变化时推送给客户端
+func (s *discoverSubscriber) run(ctx context.Context,
+	discover func(ctx context.Context, in *api.DiscoverRequest) *api.DiscoverResponse) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notifyCh:
+			for key, sub := range s.takePending() {
+				in := proto.Clone(sub.request).(*api.DiscoverRequest)
+				if in.Service != nil {
+					in.Service.Revision = utils.NewStringValue(sub.revision)
+				}
+				out := discover(ctx, in)
+				if out.GetCode().GetValue() == api.DataNoChange {
+					continue
+				}
+				revision := responseRevision(out)
+				if revision == sub.revision {
+					continue
+				}
+				if err := s.send(out); err != nil {
+					namingLog.Error("[Grpc][Discover] push discover response to subscriber error",
+						zap.String("service", key.serviceKey()), zap.Error(err))
+					return
+				}
+				s.updateRevision(key, revision)
+			}
+		}
+	}
+}
+
+// responseRevision 应答的 revision，SERVICES 类型的应答没有 revision，根据服务列表计算
+func responseRevision(out *api.DiscoverResponse) string {
+	if out.GetType() != api.DiscoverResponse_SERVICES {
+		return out.GetService().GetRevision().GetValue()
+	}
+	h := sha1.New()
+	for _, svc := range out.GetServices() {
+		_, _ = h.Write([]byte(svc.GetNamespace().GetValue()))
+		_, _ = h.Write([]byte("/"))
+		_, _ = h.Write([]byte(svc.GetName().GetValue()))
+		_, _ = h.Write([]byte("#"))
+		_, _ = h.Write([]byte(svc.GetRevision().GetValue()))
+		_, _ = h.Write([]byte(";"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// serviceLookup 查询服务缓存，用于将别名以及导出到其他命名空间的服务解析为源服务
+type serviceLookup interface {
+	GetServiceByID(id string) *model.Service
+	GetServiceByName(name string, namespace string) *model.Service
+	GetExportedService(name string, namespace string) *model.Service
+}
+
+// subscribeCenter 管理全部订阅模式的 Discover 流，服务 revision 或者规则变化时通知订阅了该服务的流。
+// revision 以及规则的变化事件只携带源服务，订阅别名或者导出服务的流同时按照源服务建立索引
+type subscribeCenter struct {
+	once        sync.Once
+	lock        sync.RWMutex
+	services    serviceLookup
+	subscribers map[string]map[*discoverSubscriber]struct{} // namespace/service -> subscribers
+}
+
+func newSubscribeCenter() *subscribeCenter {
+	return &subscribeCenter{
+		subscribers: make(map[string]map[*discoverSubscriber]struct{}),
+	}
+}
+
+// start 注册服务 revision 以及路由、限流、熔断规则变化的监听，只会注册一次
+func (c *subscribeCenter) start(caches *cache.CacheManager) {
+	if caches == nil {
+		return
+	}
+	c.once.Do(func() {
+		c.lock.Lock()
+		c.services = caches.Service()
+		c.lock.Unlock()
+		caches.AddRevisionListener(func(event cache.RevisionEvent) {
+			c.onRevisionChanged(event.Namespace, event.Name)
+		})
+		caches.AddListener(cache.CacheNameRoutingConfig, []cache.Listener{
+			&ruleListener{center: c, caches: caches, reqType: api.DiscoverRequest_ROUTING},
+		})
+		caches.AddListener(cache.CacheNameRateLimit, []cache.Listener{
+			&ruleListener{center: c, caches: caches, reqType: api.DiscoverRequest_RATE_LIMIT},
+		})
+		caches.AddListener(cache.CacheNameCircuitBreaker, []cache.Listener{
+			&ruleListener{center: c, caches: caches, reqType: api.DiscoverRequest_CIRCUIT_BREAKER},
+		})
+	})
+}
+
+func (c *subscribeCenter) addSubscriber(key subscriptionKey, subscriber *discoverSubscriber) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	source := c.sourceKey(key)
+	subscriber.setSource(key, source)
+	c.index(key.serviceKey(), subscriber)
+	if source != "" {
+		c.index(source, subscriber)
+	}
+}
+
+func (c *subscribeCenter) index(serviceKey string, subscriber *discoverSubscriber) {
+	subscribers, ok := c.subscribers[serviceKey]
+	if !ok {
+		subscribers = make(map[*discoverSubscriber]struct{})
+		c.subscribers[serviceKey] = subscribers
+	}
+	subscribers[subscriber] = struct{}{}
+}
+
+// sourceKey 订阅的服务是别名或者其他命名空间导出的服务时，返回源服务的索引，否则返回空
+func (c *subscribeCenter) sourceKey(key subscriptionKey) string {
+	if c.services == nil || key.reqType == api.DiscoverRequest_SERVICES {
+		return ""
+	}
+	svc := c.services.GetServiceByName(key.service, key.namespace)
+	if svc == nil {
+		svc = c.services.GetExportedService(key.service, key.namespace)
+	}
+	if svc != nil && svc.IsAlias() {
+		svc = c.services.GetServiceByID(svc.Reference)
+	}
+	if svc == nil {
+		return ""
+	}
+	if source := svc.Namespace + "/" + svc.Name; source != key.serviceKey() {
+		return source
+	}
+	return ""
+}
+
+func (c *subscribeCenter) removeSubscriber(subscriber *discoverSubscriber) {
+	subscriber.lock.Lock()
+	serviceKeys := make([]string, 0, len(subscriber.subscriptions))
+	for key, sub := range subscriber.subscriptions {
+		serviceKeys = append(serviceKeys, key.serviceKey())
+		if sub.source != "" {
+			serviceKeys = append(serviceKeys, sub.source)
+		}
+	}
+	subscriber.lock.Unlock()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, serviceKey := range serviceKeys {
+		subscribers, ok := c.subscribers[serviceKey]
+		if !ok {
+			continue
+		}
+		delete(subscribers, subscriber)
+		if len(subscribers) == 0 {
+			delete(c.subscribers, serviceKey)
+		}
+	}
+}
+
+// onRevisionChanged 在 revision 计算协程中执行，只标记需要检查的订阅，不能阻塞。
+// 服务导出到其他命名空间时，同时标记订阅了目标命名空间服务列表的订阅
+func (c *subscribeCenter) onRevisionChanged(namespace, service string) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	for _, serviceKey := range c.revisionKeys(namespace, service) {
+		serviceKey := serviceKey
+		for subscriber := range c.subscribers[serviceKey] {
+			subscriber.markPending(func(key subscriptionKey, sub *subscription) bool {
+				return sub.matches(key, serviceKey)
+			})
+		}
+	}
+}
+
+// revisionKeys 服务 revision 变化时需要检查的索引
+func (c *subscribeCenter) revisionKeys(namespace, service string) []string {
+	keys := []string{namespace + "/" + service, namespace + "/", "/"}
+	if c.services == nil {
+		return keys
+	}
+	svc := c.services.GetServiceByName(service, namespace)
+	if svc == nil {
+		return keys
+	}
+	for _, target := range svc.ExportTo() {
+		if target != model.ExportToAllNamespaces {
+			keys = append(keys, target+"/")
+			continue
+		}
+		for serviceKey := range c.subscribers {
+			if strings.HasSuffix(serviceKey, "/") && serviceKey != namespace+"/" && serviceKey != "/" {
+				keys = append(keys, serviceKey)
+			}
+		}
+	}
+	return keys
+}
+
+// onRuleChanged 在缓存更新协程中执行，只标记订阅了该服务该类型规则的订阅。
+// 无法确定规则所属的服务时，标记全部该类型规则的订阅
+func (c *subscribeCenter) onRuleChanged(reqType api.DiscoverRequest_DiscoverRequestType,
+	namespace, service string) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if namespace == "" && service == "" {
+		for _, subscribers := range c.subscribers {
+			for subscriber := range subscribers {
+				subscriber.markPending(func(key subscriptionKey, _ *subscription) bool {
+					return key.reqType == reqType
+				})
+			}
+		}
+		return
+	}
+
+	serviceKey := namespace + "/" + service
+	for subscriber := range c.subscribers[serviceKey] {
+		subscriber.markPending(func(key subscriptionKey, sub *subscription) bool {
+			return key.reqType == reqType && sub.matches(key, serviceKey)
+		})
+	}
+}
+
+// ruleListener 监听路由、限流、熔断规则缓存的变化，规则的变化不会触发服务 revision 变化
+type ruleListener struct {
+	center  *subscribeCenter
+	caches  *cache.CacheManager
+	reqType api.DiscoverRequest_DiscoverRequestType
+}
+
+// OnCreated callback when cache value created
+func (l *ruleListener) OnCreated(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnUpdated callback when cache value updated
+func (l *ruleListener) OnUpdated(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnDeleted callback when cache value deleted
+func (l *ruleListener) OnDeleted(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnBatchCreated callback when cache value created
+func (l *ruleListener) OnBatchCreated(value interface{}) {
+}
+
+// OnBatchUpdated callback when cache value updated
+func (l *ruleListener) OnBatchUpdated(value interface{}) {
+}
+
+// OnBatchDeleted callback when cache value deleted
+func (l *ruleListener) OnBatchDeleted(value interface{}) {
+}
+
+// onChanged 根据规则绑定的服务 ID 找到规则所属的服务，v2 路由规则可以通过通配符匹配多个服务，
+// 不区分服务
+func (l *ruleListener) onChanged(value interface{}) {
+	var serviceID string
+	switch rule := value.(type) {
+	case *model.RoutingConfig:
+		serviceID = rule.ID
+	case *model.RateLimit:
+		serviceID = rule.ServiceID
+	case *model.ServiceWithCircuitBreaker:
+		serviceID = rule.ServiceID
+	}
+
+	var namespace, service string
+	if serviceID != "" {
+		if svc := l.caches.Service().GetServiceByID(serviceID); svc != nil {
+			namespace, service = svc.Namespace, svc.Name
+		}
+	}
+	l.center.onRuleChanged(l.reqType, namespace, service)
+}
@@ -0,0 +1,250 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+type mockDiscoverStream struct {
+	grpc.ServerStream
+	sent chan *api.DiscoverResponse
+}
+
+func (m *mockDiscoverStream) Send(out *api.DiscoverResponse) error {
+	m.sent <- out
+	return nil
+}
+
+func (m *mockDiscoverStream) Recv() (*api.DiscoverRequest, error) {
+	return nil, nil
+}
+
+type mockServiceLookup struct {
+	services []*model.Service
+}
+
+func (m *mockServiceLookup) GetServiceByID(id string) *model.Service {
+	for _, svc := range m.services {
+		if svc.ID == id {
+			return svc
+		}
+	}
+	return nil
+}
+
+func (m *mockServiceLookup) GetServiceByName(name string, namespace string) *model.Service {
+	for _, svc := range m.services {
+		if svc.Name == name && svc.Namespace == namespace {
+			return svc
+		}
+	}
+	return nil
+}
+
+func (m *mockServiceLookup) GetExportedService(name string, namespace string) *model.Service {
+	for _, svc := range m.services {
+		if svc.Name == name && svc.IsExportedTo(namespace) {
+			return svc
+		}
+	}
+	return nil
+}
+
+func Test_discoverSubscriber(t *testing.T) {
+	var lock sync.Mutex
+	revision := "rev-1"
+	discover := func(ctx context.Context, in *api.DiscoverRequest) *api.DiscoverResponse {
+		lock.Lock()
+		defer lock.Unlock()
+		if in.GetService().GetRevision().GetValue() == revision {
+			return api.NewDiscoverInstanceResponse(api.DataNoChange, in.Service)
+		}
+		svc := &api.Service{
+			Name:      in.Service.Name,
+			Namespace: in.Service.Namespace,
+			Revision:  utils.NewStringValue(revision),
+		}
+		return api.NewDiscoverInstanceResponse(api.ExecuteSuccess, svc)
+	}
+
+	stream := &mockDiscoverStream{sent: make(chan *api.DiscoverResponse, 8)}
+	center := newSubscribeCenter()
+	subscriber := newDiscoverSubscriber(stream)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go subscriber.run(ctx, discover)
+
+	in := &api.DiscoverRequest{
+		Type: api.DiscoverRequest_INSTANCE,
+		Service: &api.Service{
+			Name:      utils.NewStringValue("svc"),
+			Namespace: utils.NewStringValue("ns"),
+		},
+	}
+	key := subscriber.subscribe(in, discover(ctx, in))
+	center.addSubscriber(key, subscriber)
+
+	t.Run("revision 没有变化不推送", func(t *testing.T) {
+		center.onRevisionChanged("ns", "svc")
+		select {
+		case out := <-stream.sent:
+			t.Fatalf("unexpected push %s", out.GetService().GetRevision().GetValue())
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("其他服务的 revision 变化不推送", func(t *testing.T) {
+		lock.Lock()
+		revision = "rev-2"
+		lock.Unlock()
+
+		center.onRevisionChanged("ns", "other")
+		select {
+		case out := <-stream.sent:
+			t.Fatalf("unexpected push %s", out.GetService().GetRevision().GetValue())
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("revision 变化推送", func(t *testing.T) {
+		center.onRevisionChanged("ns", "svc")
+		select {
+		case out := <-stream.sent:
+			assert.Equal(t, api.ExecuteSuccess, out.GetCode().GetValue())
+			assert.Equal(t, "rev-2", out.GetService().GetRevision().GetValue())
+		case <-time.After(time.Second):
+			t.Fatal("subscriber should receive push")
+		}
+	})
+
+	t.Run("流结束后不再通知", func(t *testing.T) {
+		center.removeSubscriber(subscriber)
+		assert.Equal(t, 0, len(center.subscribers))
+
+		lock.Lock()
+		revision = "rev-3"
+		lock.Unlock()
+
+		center.onRevisionChanged("ns", "svc")
+		select {
+		case out := <-stream.sent:
+			t.Fatalf("unexpected push %s", out.GetService().GetRevision().GetValue())
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func Test_subscribeCenter_onRuleChanged(t *testing.T) {
+	center := newSubscribeCenter()
+	subscriber := newDiscoverSubscriber(&mockDiscoverStream{sent: make(chan *api.DiscoverResponse, 8)})
+
+	subscribe := func(reqType api.DiscoverRequest_DiscoverRequestType, service string) subscriptionKey {
+		in := &api.DiscoverRequest{
+			Type: reqType,
+			Service: &api.Service{
+				Name:      utils.NewStringValue(service),
+				Namespace: utils.NewStringValue("ns"),
+			},
+		}
+		key := subscriber.subscribe(in, &api.DiscoverResponse{})
+		center.addSubscriber(key, subscriber)
+		return key
+	}
+	instanceKey := subscribe(api.DiscoverRequest_INSTANCE, "svc")
+	routingKey := subscribe(api.DiscoverRequest_ROUTING, "svc")
+	otherRoutingKey := subscribe(api.DiscoverRequest_ROUTING, "other")
+	rateLimitKey := subscribe(api.DiscoverRequest_RATE_LIMIT, "svc")
+
+	t.Run("只检查规则所属服务的同类型订阅", func(t *testing.T) {
+		center.onRuleChanged(api.DiscoverRequest_ROUTING, "ns", "svc")
+		pending := subscriber.takePending()
+		assert.Equal(t, 1, len(pending))
+		assert.Contains(t, pending, routingKey)
+	})
+
+	t.Run("无法确定服务时检查全部同类型订阅", func(t *testing.T) {
+		center.onRuleChanged(api.DiscoverRequest_ROUTING, "", "")
+		pending := subscriber.takePending()
+		assert.Equal(t, 2, len(pending))
+		assert.Contains(t, pending, routingKey)
+		assert.Contains(t, pending, otherRoutingKey)
+		assert.NotContains(t, pending, instanceKey)
+		assert.NotContains(t, pending, rateLimitKey)
+	})
+}
+
+func Test_subscribeCenter_sourceService(t *testing.T) {
+	center := newSubscribeCenter()
+	center.services = &mockServiceLookup{services: []*model.Service{
+		{ID: "svc-id", Name: "svc", Namespace: "ns", Meta: map[string]string{model.MetaKeyServiceExportTo: "ns-b"}},
+		{ID: "alias-id", Name: "alias", Namespace: "ns-a", Reference: "svc-id"},
+	}}
+	subscriber := newDiscoverSubscriber(&mockDiscoverStream{sent: make(chan *api.DiscoverResponse, 8)})
+
+	subscribe := func(reqType api.DiscoverRequest_DiscoverRequestType, namespace, service string) subscriptionKey {
+		in := &api.DiscoverRequest{
+			Type: reqType,
+			Service: &api.Service{
+				Name:      utils.NewStringValue(service),
+				Namespace: utils.NewStringValue(namespace),
+			},
+		}
+		key := subscriber.subscribe(in, &api.DiscoverResponse{})
+		center.addSubscriber(key, subscriber)
+		return key
+	}
+	aliasKey := subscribe(api.DiscoverRequest_INSTANCE, "ns-a", "alias")
+	aliasRoutingKey := subscribe(api.DiscoverRequest_ROUTING, "ns-a", "alias")
+	exportKey := subscribe(api.DiscoverRequest_INSTANCE, "ns-b", "svc")
+	servicesKey := subscribe(api.DiscoverRequest_SERVICES, "ns-b", "")
+	otherServicesKey := subscribe(api.DiscoverRequest_SERVICES, "ns-c", "")
+
+	t.Run("源服务 revision 变化时检查别名以及导出服务的订阅", func(t *testing.T) {
+		center.onRevisionChanged("ns", "svc")
+		pending := subscriber.takePending()
+		assert.Equal(t, 4, len(pending))
+		assert.Contains(t, pending, aliasKey)
+		assert.Contains(t, pending, aliasRoutingKey)
+		assert.Contains(t, pending, exportKey)
+		assert.Contains(t, pending, servicesKey)
+		assert.NotContains(t, pending, otherServicesKey)
+	})
+
+	t.Run("源服务规则变化时检查别名的同类型订阅", func(t *testing.T) {
+		center.onRuleChanged(api.DiscoverRequest_ROUTING, "ns", "svc")
+		pending := subscriber.takePending()
+		assert.Equal(t, 1, len(pending))
+		assert.Contains(t, pending, aliasRoutingKey)
+	})
+
+	t.Run("流结束后删除源服务的索引", func(t *testing.T) {
+		center.removeSubscriber(subscriber)
+		assert.Equal(t, 0, len(center.subscribers))
+	})
+}
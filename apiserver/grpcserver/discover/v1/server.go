@@ -27,10 +27,13 @@ type DiscoverServer struct {
 	healthCheckServer *healthcheck.Server
 	enterRateLimit    func(ip string, method string) uint32
 	allowAccess       func(method string) bool
+	subscribes        *subscribeCenter
 }
 
 func NewDiscoverServer(options ...Option) *DiscoverServer {
-	s := &DiscoverServer{}
+	s := &DiscoverServer{
+		subscribes: newSubscribeCenter(),
+	}
 
 	for i := range options {
 		options[i](s)
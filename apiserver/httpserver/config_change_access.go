@@ -0,0 +1,161 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// configFileChangeReviewRequest 审核配置文件变更请求
+type configFileChangeReviewRequest struct {
+	Id      uint64 `json:"id"`
+	Comment string `json:"comment"`
+}
+
+// configFileChangeView 配置文件变更，diff 为变更内容相对于最新发布内容的 unified diff
+type configFileChangeView struct {
+	Id            uint64 `json:"id"`
+	Namespace     string `json:"namespace"`
+	Group         string `json:"group"`
+	FileName      string `json:"fileName"`
+	Content       string `json:"content"`
+	Diff          string `json:"diff"`
+	Md5           string `json:"md5"`
+	Status        string `json:"status"`
+	ReviewBy      string `json:"reviewBy"`
+	ReviewComment string `json:"reviewComment"`
+	CreateTime    string `json:"createTime"`
+	CreateBy      string `json:"createBy"`
+	ModifyTime    string `json:"modifyTime"`
+	ModifyBy      string `json:"modifyBy"`
+}
+
+// configFileChangesView 配置文件变更列表
+type configFileChangesView struct {
+	Code    uint32                  `json:"code"`
+	Info    string                  `json:"info"`
+	Total   uint32                  `json:"total"`
+	Changes []*configFileChangeView `json:"changes"`
+}
+
+func newConfigFileChangeView(change *model.ConfigFileChange) *configFileChangeView {
+	return &configFileChangeView{
+		Id:            change.Id,
+		Namespace:     change.Namespace,
+		Group:         change.Group,
+		FileName:      change.FileName,
+		Content:       change.Content,
+		Diff:          change.Diff,
+		Md5:           change.Md5,
+		Status:        change.Status,
+		ReviewBy:      change.ReviewBy,
+		ReviewComment: change.ReviewComment,
+		CreateTime:    commontime.Time2String(change.CreateTime),
+		CreateBy:      change.CreateBy,
+		ModifyTime:    commontime.Time2String(change.ModifyTime),
+		ModifyBy:      change.ModifyBy,
+	}
+}
+
+// QueryConfigFileChanges 查询配置文件变更，按照提交时间倒序排序
+func (h *HTTPServer) QueryConfigFileChanges(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+	status := handler.QueryParameter("status")
+	offset, _ := strconv.ParseUint(handler.QueryParameter("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(handler.QueryParameter("limit"), 10, 64)
+
+	total, changes, response := h.configServer.QueryConfigFileChanges(handler.ParseHeaderContext(),
+		namespace, group, name, status, uint32(offset), uint32(limit))
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	views := make([]*configFileChangeView, 0, len(changes))
+	for _, change := range changes {
+		views = append(views, newConfigFileChangeView(change))
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileChangesView{
+		Code:    response.GetCode().GetValue(),
+		Info:    response.GetInfo().GetValue(),
+		Total:   total,
+		Changes: views,
+	}, restful.MIME_JSON)
+}
+
+// GetConfigFileChange 获取单个配置文件变更
+func (h *HTTPServer) GetConfigFileChange(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	id, err := strconv.ParseUint(handler.QueryParameter("id"), 10, 64)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid change id"))
+		return
+	}
+
+	change, response := h.configServer.GetConfigFileChange(handler.ParseHeaderContext(), id)
+	if change == nil {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, newConfigFileChangeView(change), restful.MIME_JSON)
+}
+
+// ApproveConfigFileChange 审核通过配置文件变更
+func (h *HTTPServer) ApproveConfigFileChange(req *restful.Request, rsp *restful.Response) {
+	h.reviewConfigFileChange(req, rsp, true)
+}
+
+// RejectConfigFileChange 拒绝配置文件变更
+func (h *HTTPServer) RejectConfigFileChange(req *restful.Request, rsp *restful.Response) {
+	h.reviewConfigFileChange(req, rsp, false)
+}
+
+func (h *HTTPServer) reviewConfigFileChange(req *restful.Request, rsp *restful.Response, approve bool) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	reviewReq := &configFileChangeReviewRequest{}
+	if err := req.ReadEntity(reviewReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file change review from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	if approve {
+		handler.WriteHeaderAndProto(h.configServer.ApproveConfigFileChange(ctx, reviewReq.Id, reviewReq.Comment))
+		return
+	}
+	handler.WriteHeaderAndProto(h.configServer.RejectConfigFileChange(ctx, reviewReq.Id, reviewReq.Comment))
+}
@@ -47,6 +47,18 @@ func (h *HTTPServer) getConfigFile(req *restful.Request, rsp *restful.Response)
 	handler.WriteHeaderAndProto(response)
 }
 
+func (h *HTTPServer) reportConfigFileError(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	configFile := &api.ClientConfigFileInfo{}
+	if _, err := handler.Parse(configFile); err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigClientResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.configServer.ReportConfigFileError(handler.ParseHeaderContext(), configFile))
+}
+
 func (h *HTTPServer) watchConfigFile(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{req, rsp}
 
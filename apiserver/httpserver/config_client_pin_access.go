@@ -0,0 +1,130 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// configFileClientPinRequest 客户端版本固定请求，client 为客户端 ID 或者客户端 IP，historyId 为配置文件的发布历史记录 ID
+type configFileClientPinRequest struct {
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	FileName  string `json:"fileName"`
+	Client    string `json:"client"`
+	HistoryId uint64 `json:"historyId"`
+	Comment   string `json:"comment"`
+}
+
+// configFileClientPinView 客户端版本固定
+type configFileClientPinView struct {
+	Namespace  string `json:"namespace"`
+	Group      string `json:"group"`
+	FileName   string `json:"fileName"`
+	Client     string `json:"client"`
+	HistoryId  uint64 `json:"historyId"`
+	Md5        string `json:"md5"`
+	Comment    string `json:"comment"`
+	CreateTime string `json:"createTime"`
+	CreateBy   string `json:"createBy"`
+	ModifyTime string `json:"modifyTime"`
+	ModifyBy   string `json:"modifyBy"`
+}
+
+// configFileClientPinsView 客户端版本固定列表
+type configFileClientPinsView struct {
+	Code uint32                     `json:"code"`
+	Info string                     `json:"info"`
+	Pins []*configFileClientPinView `json:"pins"`
+}
+
+// QueryConfigFileClientPins 查询配置文件的客户端版本固定
+func (h *HTTPServer) QueryConfigFileClientPins(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	pins, response := h.configServer.QueryConfigFileClientPins(handler.ParseHeaderContext(),
+		handler.QueryParameter("namespace"), handler.QueryParameter("group"), handler.QueryParameter("name"))
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	views := make([]*configFileClientPinView, 0, len(pins))
+	for _, pin := range pins {
+		views = append(views, &configFileClientPinView{
+			Namespace:  pin.Namespace,
+			Group:      pin.Group,
+			FileName:   pin.FileName,
+			Client:     pin.Client,
+			HistoryId:  pin.HistoryId,
+			Md5:        pin.Md5,
+			Comment:    pin.Comment,
+			CreateTime: commontime.Time2String(pin.CreateTime),
+			CreateBy:   pin.CreateBy,
+			ModifyTime: commontime.Time2String(pin.ModifyTime),
+			ModifyBy:   pin.ModifyBy,
+		})
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileClientPinsView{
+		Code: response.GetCode().GetValue(),
+		Info: response.GetInfo().GetValue(),
+		Pins: views,
+	}, restful.MIME_JSON)
+}
+
+// PinConfigFileClient 将客户端固定到指定的发布历史版本
+func (h *HTTPServer) PinConfigFileClient(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	pinReq := &configFileClientPinRequest{}
+	if err := req.ReadEntity(pinReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file client pin from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.configServer.PinConfigFileClient(ctx, &model.ConfigFileClientPin{
+		Namespace: pinReq.Namespace,
+		Group:     pinReq.Group,
+		FileName:  pinReq.FileName,
+		Client:    pinReq.Client,
+		HistoryId: pinReq.HistoryId,
+		Comment:   pinReq.Comment,
+	}))
+}
+
+// UnpinConfigFileClient 取消客户端的版本固定
+func (h *HTTPServer) UnpinConfigFileClient(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	handler.WriteHeaderAndProto(h.configServer.UnpinConfigFileClient(handler.ParseHeaderContext(),
+		handler.QueryParameter("namespace"), handler.QueryParameter("group"), handler.QueryParameter("name"),
+		handler.QueryParameter("client")))
+}
@@ -239,6 +239,24 @@ func (h *HTTPServer) GetConfigFileRelease(req *restful.Request, rsp *restful.Res
 	handler.WriteHeaderAndProto(response)
 }
 
+// RollbackConfigFileRelease 回滚配置文件到指定的发布历史版本
+func (h *HTTPServer) RollbackConfigFileRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+	historyId, err := strconv.ParseUint(handler.QueryParameter("historyId"), 10, 64)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid historyId"))
+		return
+	}
+
+	response := h.configServer.RollbackConfigFileRelease(handler.ParseHeaderContext(), namespace, group, name, historyId)
+
+	handler.WriteHeaderAndProto(response)
+}
+
 // GetConfigFileReleaseHistory 获取配置文件发布历史，按照发布时间倒序排序
 func (h *HTTPServer) GetConfigFileReleaseHistory(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{req, rsp}
@@ -0,0 +1,182 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// grayPublishRequest 灰度发布请求，灰度规则为客户端 IP（支持 CIDR）列表、客户端标签或者灰度比例
+type grayPublishRequest struct {
+	Namespace  string            `json:"namespace"`
+	Group      string            `json:"group"`
+	FileName   string            `json:"fileName"`
+	Name       string            `json:"name"`
+	Comment    string            `json:"comment"`
+	ClientIPs  []string          `json:"clientIps"`
+	Labels     map[string]string `json:"labels"`
+	Percentage int               `json:"percentage"`
+	MaxErrors  uint32            `json:"maxErrors"`
+}
+
+// grayPercentageRequest 提高灰度比例请求
+type grayPercentageRequest struct {
+	Namespace  string `json:"namespace"`
+	Group      string `json:"group"`
+	FileName   string `json:"fileName"`
+	Percentage int    `json:"percentage"`
+}
+
+// grayReleaseView 配置文件当前的灰度发布
+type grayReleaseView struct {
+	Name       string          `json:"name"`
+	Namespace  string          `json:"namespace"`
+	Group      string          `json:"group"`
+	FileName   string          `json:"fileName"`
+	Content    string          `json:"content"`
+	Comment    string          `json:"comment"`
+	Md5        string          `json:"md5"`
+	Version    uint64          `json:"version"`
+	Rule       *model.GrayRule `json:"rule"`
+	ErrorCount uint32          `json:"errorCount"`
+	CreateTime string          `json:"createTime"`
+	CreateBy   string          `json:"createBy"`
+	ModifyTime string          `json:"modifyTime"`
+	ModifyBy   string          `json:"modifyBy"`
+}
+
+// PublishConfigFileGray 灰度发布配置文件
+func (h *HTTPServer) PublishConfigFileGray(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	grayReq := &grayPublishRequest{}
+	if err := req.ReadEntity(grayReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file gray release from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileReleaseResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	release := &api.ConfigFileRelease{
+		Name:      utils.NewStringValue(grayReq.Name),
+		Namespace: utils.NewStringValue(grayReq.Namespace),
+		Group:     utils.NewStringValue(grayReq.Group),
+		FileName:  utils.NewStringValue(grayReq.FileName),
+		Comment:   utils.NewStringValue(grayReq.Comment),
+	}
+	rule := &model.GrayRule{
+		ClientIPs:  grayReq.ClientIPs,
+		Labels:     grayReq.Labels,
+		Percentage: grayReq.Percentage,
+		MaxErrors:  grayReq.MaxErrors,
+	}
+
+	handler.WriteHeaderAndProto(h.configServer.PublishConfigFileGray(ctx, release, rule))
+}
+
+// GetConfigFileGrayRelease 获取配置文件当前的灰度发布
+func (h *HTTPServer) GetConfigFileGrayRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+
+	gray, err := h.configServer.GetConfigFileGrayRelease(handler.ParseHeaderContext(), namespace, group, name)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponse(api.StoreLayerException, nil))
+		return
+	}
+	if gray == nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponse(api.NotFoundResource, nil))
+		return
+	}
+
+	rule := &model.GrayRule{}
+	_ = json.Unmarshal([]byte(gray.Rule), rule)
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &grayReleaseView{
+		Name:       gray.Name,
+		Namespace:  gray.Namespace,
+		Group:      gray.Group,
+		FileName:   gray.FileName,
+		Content:    gray.Content,
+		Comment:    gray.Comment,
+		Md5:        gray.Md5,
+		Version:    gray.Version,
+		Rule:       rule,
+		ErrorCount: gray.ErrorCount,
+		CreateTime: commontime.Time2String(gray.CreateTime),
+		CreateBy:   gray.CreateBy,
+		ModifyTime: commontime.Time2String(gray.ModifyTime),
+		ModifyBy:   gray.ModifyBy,
+	}, restful.MIME_JSON)
+}
+
+// PromoteConfigFileGray 灰度发布转为全量发布
+func (h *HTTPServer) PromoteConfigFileGray(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+
+	handler.WriteHeaderAndProto(h.configServer.PromoteConfigFileGray(handler.ParseHeaderContext(),
+		namespace, group, name))
+}
+
+// CancelConfigFileGray 取消灰度发布
+func (h *HTTPServer) CancelConfigFileGray(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+
+	handler.WriteHeaderAndProto(h.configServer.CancelConfigFileGray(handler.ParseHeaderContext(),
+		namespace, group, name))
+}
+
+// UpdateConfigFileGrayPercentage 提高按比例灰度的比例
+func (h *HTTPServer) UpdateConfigFileGrayPercentage(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	percentageReq := &grayPercentageRequest{}
+	if err := req.ReadEntity(percentageReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file gray percentage from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileGrayPercentage(ctx, percentageReq.Namespace,
+		percentageReq.Group, percentageReq.FileName, percentageReq.Percentage))
+}
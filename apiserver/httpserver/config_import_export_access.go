@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+)
+
+const (
+	// maxConfigFileImportSize 导入的压缩包的最大字节数
+	maxConfigFileImportSize = 32 * 1024 * 1024
+)
+
+// ExportConfigFile 导出配置文件为 zip 压缩包
+func (h *HTTPServer) ExportConfigFile(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	var groups []string
+	if groupsStr := handler.QueryParameter("groups"); groupsStr != "" {
+		groups = strings.Split(groupsStr, ",")
+	}
+
+	data, response := h.configServer.ExportConfigFile(handler.ParseHeaderContext(), namespace, groups)
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	rsp.AddHeader("Content-Type", "application/zip")
+	rsp.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=config_%s.zip", namespace))
+	rsp.WriteHeader(http.StatusOK)
+	_, _ = rsp.Write(data)
+}
+
+// ImportConfigFile 从 zip 压缩包导入配置文件，压缩包通过 multipart 表单的 file 字段上传
+func (h *HTTPServer) ImportConfigFile(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	conflictHandling := handler.QueryParameter("conflictHandling")
+	publish, _ := strconv.ParseBool(handler.QueryParameter("publish"))
+
+	req.Request.Body = http.MaxBytesReader(rsp, req.Request.Body, maxConfigFileImportSize)
+	file, _, err := req.Request.FormFile("file")
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigBatchWriteResponseWithMessage(api.InvalidParameter, err.Error()))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigBatchWriteResponseWithMessage(api.InvalidParameter, err.Error()))
+		return
+	}
+
+	response := h.configServer.ImportConfigFile(handler.ParseHeaderContext(), namespace, data, conflictHandling, publish)
+
+	handler.WriteHeaderAndProto(response)
+}
@@ -0,0 +1,141 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// configFileSchemaRequest 创建、更新配置文件 JSON Schema 请求
+type configFileSchemaRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Comment string `json:"comment"`
+}
+
+// configFileSchemaView 配置文件 JSON Schema
+type configFileSchemaView struct {
+	Id         uint64 `json:"id"`
+	Name       string `json:"name"`
+	Content    string `json:"content"`
+	Comment    string `json:"comment"`
+	CreateTime string `json:"createTime"`
+	CreateBy   string `json:"createBy"`
+	ModifyTime string `json:"modifyTime"`
+	ModifyBy   string `json:"modifyBy"`
+}
+
+// configFileSchemasView 配置文件 JSON Schema 列表
+type configFileSchemasView struct {
+	Code    uint32                  `json:"code"`
+	Info    string                  `json:"info"`
+	Total   uint32                  `json:"total"`
+	Schemas []*configFileSchemaView `json:"schemas"`
+}
+
+func newConfigFileSchemaView(schema *model.ConfigFileSchema) *configFileSchemaView {
+	return &configFileSchemaView{
+		Id:         schema.Id,
+		Name:       schema.Name,
+		Content:    schema.Content,
+		Comment:    schema.Comment,
+		CreateTime: commontime.Time2String(schema.CreateTime),
+		CreateBy:   schema.CreateBy,
+		ModifyTime: commontime.Time2String(schema.ModifyTime),
+		ModifyBy:   schema.ModifyBy,
+	}
+}
+
+// GetAllConfigFileSchemas 获取全部的配置文件 JSON Schema
+func (h *HTTPServer) GetAllConfigFileSchemas(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	schemas, response := h.configServer.GetAllConfigFileSchemas(handler.ParseHeaderContext())
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	views := make([]*configFileSchemaView, 0, len(schemas))
+	for _, schema := range schemas {
+		views = append(views, newConfigFileSchemaView(schema))
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileSchemasView{
+		Code:    response.GetCode().GetValue(),
+		Info:    response.GetInfo().GetValue(),
+		Total:   uint32(len(views)),
+		Schemas: views,
+	}, restful.MIME_JSON)
+}
+
+// GetConfigFileSchema 获取单个配置文件 JSON Schema
+func (h *HTTPServer) GetConfigFileSchema(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	schema, response := h.configServer.GetConfigFileSchema(handler.ParseHeaderContext(),
+		handler.QueryParameter("name"))
+	if schema == nil {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, newConfigFileSchemaView(schema), restful.MIME_JSON)
+}
+
+// CreateConfigFileSchema 创建配置文件 JSON Schema
+func (h *HTTPServer) CreateConfigFileSchema(req *restful.Request, rsp *restful.Response) {
+	h.saveConfigFileSchema(req, rsp, true)
+}
+
+// UpdateConfigFileSchema 更新配置文件 JSON Schema
+func (h *HTTPServer) UpdateConfigFileSchema(req *restful.Request, rsp *restful.Response) {
+	h.saveConfigFileSchema(req, rsp, false)
+}
+
+func (h *HTTPServer) saveConfigFileSchema(req *restful.Request, rsp *restful.Response, create bool) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	schemaReq := &configFileSchemaRequest{}
+	if err := req.ReadEntity(schemaReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file schema from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.ParseException, err.Error()))
+		return
+	}
+
+	schema := &model.ConfigFileSchema{
+		Name:    schemaReq.Name,
+		Content: schemaReq.Content,
+		Comment: schemaReq.Comment,
+	}
+	if create {
+		handler.WriteHeaderAndProto(h.configServer.CreateConfigFileSchema(ctx, schema))
+		return
+	}
+	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileSchema(ctx, schema))
+}
@@ -73,10 +73,33 @@ func (h *HTTPServer) bindConfigConsoleEndpoint(ws *restful.WebService) {
 	ws.Route(enrichUpdateConfigFileApiDocs(ws.PUT("/configfiles").To(h.UpdateConfigFile)))
 	ws.Route(enrichDeleteConfigFileApiDocs(ws.DELETE("/configfiles").To(h.DeleteConfigFile)))
 	ws.Route(enrichBatchDeleteConfigFileApiDocs(ws.POST("/configfiles/batchdelete").To(h.BatchDeleteConfigFile)))
+	ws.Route(enrichExportConfigFileApiDocs(ws.GET("/configfiles/export").To(h.ExportConfigFile)))
+	ws.Route(enrichImportConfigFileApiDocs(ws.POST("/configfiles/import").Consumes("multipart/form-data").
+		To(h.ImportConfigFile)))
 
 	// 配置文件发布
 	ws.Route(enrichPublishConfigFileApiDocs(ws.POST("/configfiles/release").To(h.PublishConfigFile)))
 	ws.Route(enrichGetConfigFileReleaseApiDocs(ws.GET("/configfiles/release").To(h.GetConfigFileRelease)))
+	ws.Route(enrichRollbackConfigFileReleaseApiDocs(ws.POST("/configfiles/release/rollback").To(h.RollbackConfigFileRelease)))
+
+	// 配置文件灰度发布
+	ws.Route(enrichPublishConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray").To(h.PublishConfigFileGray)))
+	ws.Route(enrichGetConfigFileGrayReleaseApiDocs(ws.GET("/configfiles/release/gray").To(h.GetConfigFileGrayRelease)))
+	ws.Route(enrichPromoteConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray/promote").To(h.PromoteConfigFileGray)))
+	ws.Route(enrichCancelConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray/cancel").To(h.CancelConfigFileGray)))
+	ws.Route(enrichUpdateConfigFileGrayPercentageApiDocs(ws.PUT("/configfiles/release/gray/percentage").
+		To(h.UpdateConfigFileGrayPercentage)))
+
+	// 配置文件客户端版本固定
+	ws.Route(enrichQueryConfigFileClientPinsApiDocs(ws.GET("/configfiles/clientpins").To(h.QueryConfigFileClientPins)))
+	ws.Route(enrichPinConfigFileClientApiDocs(ws.POST("/configfiles/clientpins").To(h.PinConfigFileClient)))
+	ws.Route(enrichUnpinConfigFileClientApiDocs(ws.DELETE("/configfiles/clientpins").To(h.UnpinConfigFileClient)))
+
+	// 配置文件变更审核
+	ws.Route(enrichQueryConfigFileChangesApiDocs(ws.GET("/configfiles/changes").To(h.QueryConfigFileChanges)))
+	ws.Route(enrichGetConfigFileChangeApiDocs(ws.GET("/configfiles/change").To(h.GetConfigFileChange)))
+	ws.Route(enrichApproveConfigFileChangeApiDocs(ws.POST("/configfiles/change/approve").To(h.ApproveConfigFileChange)))
+	ws.Route(enrichRejectConfigFileChangeApiDocs(ws.POST("/configfiles/change/reject").To(h.RejectConfigFileChange)))
 
 	// 配置文件发布历史
 	ws.Route(enrichGetConfigFileReleaseHistoryApiDocs(ws.GET("/configfiles/releasehistory").To(h.GetConfigFileReleaseHistory)))
@@ -84,11 +107,34 @@ func (h *HTTPServer) bindConfigConsoleEndpoint(ws *restful.WebService) {
 	// config file template
 	ws.Route(enrichGetAllConfigFileTemplatesApiDocs(ws.GET("/configfiletemplates").To(h.GetAllConfigFileTemplates)))
 	ws.Route(enrichCreateConfigFileTemplateApiDocs(ws.POST("/configfiletemplates").To(h.CreateConfigFileTemplate)))
+
+	// 配置文件 JSON Schema
+	ws.Route(enrichGetAllConfigFileSchemasApiDocs(ws.GET("/configfileschemas").To(h.GetAllConfigFileSchemas)))
+	ws.Route(enrichGetConfigFileSchemaApiDocs(ws.GET("/configfileschema").To(h.GetConfigFileSchema)))
+	ws.Route(enrichCreateConfigFileSchemaApiDocs(ws.POST("/configfileschemas").To(h.CreateConfigFileSchema)))
+	ws.Route(enrichUpdateConfigFileSchemaApiDocs(ws.PUT("/configfileschemas").To(h.UpdateConfigFileSchema)))
+
+	// 配置文件变更 webhook
+	ws.Route(enrichQueryConfigFileWebhooksApiDocs(ws.GET("/configfilewebhooks").To(h.QueryConfigFileWebhooks)))
+	ws.Route(enrichGetConfigFileWebhookApiDocs(ws.GET("/configfilewebhook").To(h.GetConfigFileWebhook)))
+	ws.Route(enrichCreateConfigFileWebhookApiDocs(ws.POST("/configfilewebhooks").To(h.CreateConfigFileWebhook)))
+	ws.Route(enrichUpdateConfigFileWebhookApiDocs(ws.PUT("/configfilewebhooks").To(h.UpdateConfigFileWebhook)))
+	ws.Route(enrichDeleteConfigFileWebhookApiDocs(ws.DELETE("/configfilewebhooks").To(h.DeleteConfigFileWebhook)))
+	ws.Route(enrichQueryConfigFileWebhookDeliveriesApiDocs(ws.GET("/configfilewebhook/deliveries").
+		To(h.QueryConfigFileWebhookDeliveries)))
+
+	// 配置文件模板变量
+	ws.Route(enrichGetConfigFileVariablesApiDocs(ws.GET("/configfilevariables").To(h.GetConfigFileVariables)))
+	ws.Route(enrichCreateConfigFileVariableApiDocs(ws.POST("/configfilevariables").To(h.CreateConfigFileVariable)))
+	ws.Route(enrichUpdateConfigFileVariableApiDocs(ws.PUT("/configfilevariables").To(h.UpdateConfigFileVariable)))
+	ws.Route(enrichDeleteConfigFileVariableApiDocs(ws.DELETE("/configfilevariables").To(h.DeleteConfigFileVariable)))
+	ws.Route(enrichRenderConfigFileApiDocs(ws.GET("/configfiles/render").To(h.RenderConfigFile)))
 }
 
 func (h *HTTPServer) bindConfigClientEndpoint(ws *restful.WebService) {
 	ws.Route(enrichGetConfigFileForClientApiDocs(ws.GET("/GetConfigFile").To(h.getConfigFile)))
 	ws.Route(enrichWatchConfigFileForClientApiDocs(ws.POST("/WatchConfigFile").To(h.watchConfigFile)))
+	ws.Route(enrichReportConfigFileErrorApiDocs(ws.POST("/ReportConfigFileError").To(h.reportConfigFileError)))
 }
 
 // StopConfigServer 停止配置中心模块
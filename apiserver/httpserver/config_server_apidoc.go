@@ -22,6 +22,7 @@ import (
 	restfulspec "github.com/polarismesh/go-restful-openapi/v2"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
 )
 
 var (
@@ -125,6 +126,27 @@ func enrichBatchDeleteConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteB
 		Reads(api.ConfigFile{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```[\n     {\n         \"name\":\"application.properties\",\n         \"namespace\":\"someNamespace\",\n         \"group\":\"someGroup\"\n     }\n]\n```")
 }
 
+func enrichExportConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("导出配置文件").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Produces("application/zip", restful.MIME_JSON).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("groups", "配置文件分组，多个以逗号分隔，为空时导出命名空间下全部分组").
+			DataType("string").Required(false))
+}
+
+func enrichImportConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("导入配置文件").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("conflictHandling", "配置文件已存在时的处理方式，skip 或者 overwrite，默认 skip").
+			DataType("string").Required(false)).
+		Param(restful.QueryParameter("publish", "导入后是否发布").DataType("boolean").Required(false)).
+		Param(restful.FormParameter("file", "导出的 zip 压缩包").DataType("file").Required(true))
+}
+
 func enrichPublishConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("发布配置文件").
@@ -141,6 +163,116 @@ func enrichGetConfigFileReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBu
 		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
 }
 
+func enrichRollbackConfigFileReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("回滚配置文件到指定的发布历史版本，历史版本的内容会作为新版本重新发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true)).
+		Param(restful.QueryParameter("historyId", "发布历史记录 ID").DataType("integer").Required(true))
+}
+
+func enrichPublishConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("灰度发布配置文件，只有命中灰度规则的客户端才会获取到灰度发布的内容").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(grayPublishRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"fileName\":\"application.properties\",\n    \"namespace\":\"someNamespace\",\n    \"group\":\"someGroup\",\n    \"comment\":\"灰度发布\",\n    \"clientIps\":[\"127.0.0.1\", \"10.0.0.0/24\"],\n    \"labels\":{\"region\":\"ap-guangzhou\"},\n    \"percentage\":10,\n    \"maxErrors\":5\n}\n```")
+}
+
+func enrichGetConfigFileGrayReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置文件当前的灰度发布信息").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
+func enrichPromoteConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("灰度发布转为全量发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
+func enrichCancelConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("取消灰度发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
+func enrichUpdateConfigFileGrayPercentageApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("提高按比例灰度的比例，已经命中灰度的客户端仍然命中，灰度比例只能提高").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(grayPercentageRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"fileName\":\"application.properties\",\n    \"namespace\":\"someNamespace\",\n    \"group\":\"someGroup\",\n    \"percentage\":50\n}\n```")
+}
+
+func enrichQueryConfigFileClientPinsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询配置文件的客户端版本固定").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
+func enrichPinConfigFileClientApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("将客户端固定到指定的发布历史版本，被固定的客户端不再感知后续的发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileClientPinRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"fileName\":\"application.properties\",\n    \"namespace\":\"someNamespace\",\n    \"group\":\"someGroup\",\n    \"client\":\"127.0.0.1\",\n    \"historyId\":1,\n    \"comment\":\"pin to last stable version\"\n}\n```")
+}
+
+func enrichUnpinConfigFileClientApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("取消客户端的版本固定，客户端重新获取当前的发布内容").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true)).
+		Param(restful.QueryParameter("client", "客户端 ID 或者客户端 IP").DataType("string").Required(true))
+}
+
+func enrichQueryConfigFileChangesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询配置文件变更，开启变更审核后编辑配置文件会生成待审核的变更").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(false)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(false)).
+		Param(restful.QueryParameter("status", "审核状态，pending、approved、rejected、published").DataType("string").Required(false)).
+		Param(restful.QueryParameter("offset", "翻页偏移量 默认为 0").DataType("integer").Required(false).DefaultValue("0")).
+		Param(restful.QueryParameter("limit", "一页大小，最大为 100").DataType("integer").Required(true).DefaultValue("100"))
+}
+
+func enrichGetConfigFileChangeApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置文件变更，包含相对于最新发布内容的 unified diff").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("id", "变更 ID").DataType("integer").Required(true))
+}
+
+func enrichApproveConfigFileChangeApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("审核通过配置文件变更，审核通过后才能发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileChangeReviewRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"id\":1,\n    \"comment\":\"lgtm\"\n}\n```")
+}
+
+func enrichRejectConfigFileChangeApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("拒绝配置文件变更").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileChangeReviewRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"id\":1,\n    \"comment\":\"need to fix\"\n}\n```")
+}
+
 func enrichGetConfigFileReleaseHistoryApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取配置文件发布历史记录").
@@ -164,6 +296,122 @@ func enrichCreateConfigFileTemplateApiDocs(r *restful.RouteBuilder) *restful.Rou
 		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags)
 }
 
+func enrichGetAllConfigFileSchemasApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取全部的配置文件 JSON Schema").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags)
+}
+
+func enrichGetConfigFileSchemaApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置文件 JSON Schema").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("name", "Schema 名称").DataType("string").Required(true))
+}
+
+func enrichCreateConfigFileSchemaApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("创建配置文件 JSON Schema，配置文件通过 internal-schema 标签关联 Schema，保存和发布时校验内容").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileSchemaRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"name\":\"server-schema\",\n    \"content\":\"{\\\"type\\\":\\\"object\\\",\\\"required\\\":[\\\"port\\\"]}\",\n    \"comment\":\"server config\"\n}\n```")
+}
+
+func enrichUpdateConfigFileSchemaApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("更新配置文件 JSON Schema，只对之后保存、发布的配置文件生效").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileSchemaRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"name\":\"server-schema\",\n    \"content\":\"{\\\"type\\\":\\\"object\\\"}\",\n    \"comment\":\"server config\"\n}\n```")
+}
+
+func enrichQueryConfigFileWebhooksApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询命名空间下的配置文件变更 webhook").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(false)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(false)).
+		Param(restful.QueryParameter("offset", "翻页偏移量 默认为 0").DataType("integer").Required(false).DefaultValue("0")).
+		Param(restful.QueryParameter("limit", "一页大小，最大为 100").DataType("integer").Required(true).DefaultValue("100"))
+}
+
+func enrichGetConfigFileWebhookApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置文件变更 webhook").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("id", "webhook ID").DataType("integer").Required(true))
+}
+
+func enrichCreateConfigFileWebhookApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("创建配置文件变更 webhook，配置文件发布、删除时推送变更。group、fileName 为空时订阅全部，events 为空时订阅全部事件，secret 为空时自动生成并在返回结果中返回。"+
+			"推送请求携带 X-Polaris-Timestamp（Unix 秒）、X-Polaris-Delivery（推送记录 ID）以及 X-Polaris-Signature（sha256=hex(HMAC-SHA256(secret, timestamp + \".\" + body))），"+
+			"接收方应拒绝时间戳与本地时间相差超过 5 分钟的请求，并按照 X-Polaris-Delivery 去重").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileWebhookRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"name\":\"ci-notify\",\n    \"namespace\":\"default\",\n    \"group\":\"order\",\n    \"fileName\":\"application.yaml\",\n    \"url\":\"https://ci.example.com/hooks/polaris\",\n    \"events\":\"publish,delete\",\n    \"comment\":\"notify ci\"\n}\n```")
+}
+
+func enrichUpdateConfigFileWebhookApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("更新配置文件变更 webhook，name、namespace、group、fileName 不能修改，secret 为空时保持不变").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileWebhookRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"id\":1,\n    \"url\":\"https://ci.example.com/hooks/polaris\",\n    \"events\":\"publish\",\n    \"enable\":false\n}\n```")
+}
+
+func enrichDeleteConfigFileWebhookApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("删除配置文件变更 webhook").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("id", "webhook ID").DataType("integer").Required(true))
+}
+
+func enrichQueryConfigFileWebhookDeliveriesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询配置文件变更 webhook 的推送记录").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("webhookId", "webhook ID").DataType("integer").Required(true)).
+		Param(restful.QueryParameter("status", "推送状态，pending、success、failed").DataType("string").Required(false)).
+		Param(restful.QueryParameter("offset", "翻页偏移量 默认为 0").DataType("integer").Required(false).DefaultValue("0")).
+		Param(restful.QueryParameter("limit", "一页大小，最大为 100").DataType("integer").Required(true).DefaultValue("100"))
+}
+
+func enrichGetConfigFileVariablesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取命名空间下的配置文件模板变量").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true))
+}
+
+func enrichCreateConfigFileVariableApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("创建配置文件模板变量，type 支持 string、int、float、bool，为空时为 string").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileVariableRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"namespace\":\"default\",\n    \"name\":\"db.host\",\n    \"type\":\"string\",\n    \"value\":\"127.0.0.1\",\n    \"comment\":\"database host\"\n}\n```")
+}
+
+func enrichUpdateConfigFileVariableApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("更新配置文件模板变量，已经发布的配置文件需要重新发布后才会生效").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(configFileVariableRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader X-Polaris-Token: {访问凭据}\n```{\n    \"namespace\":\"default\",\n    \"name\":\"db.host\",\n    \"value\":\"10.0.0.1\"\n}\n```")
+}
+
+func enrichDeleteConfigFileVariableApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("删除配置文件模板变量").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "变量名").DataType("string").Required(true))
+}
+
+func enrichRenderConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("预览配置文件发布时渲染后的内容，配置文件需要添加标签 internal-render=true 开启渲染").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType("string").Required(true))
+}
+
 func enrichGetConfigFileForClientApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("拉取配置").
@@ -171,12 +419,25 @@ func enrichGetConfigFileForClientApiDocs(r *restful.RouteBuilder) *restful.Route
 		Param(restful.QueryParameter("namespace", "命名空间").DataType("string").Required(true)).
 		Param(restful.QueryParameter("group", "配置文件分组").DataType("string").Required(true)).
 		Param(restful.QueryParameter("fileName", "配置文件名").DataType("string").Required(true)).
-		Param(restful.QueryParameter("version", "配置文件客户端版本号，刚启动时设置为 0").DataType("integer").Required(true))
+		Param(restful.QueryParameter("version", "配置文件客户端版本号，刚启动时设置为 0").DataType("integer").Required(true)).
+		Param(restful.HeaderParameter(utils.HeaderConfigPublicKey,
+			"客户端 RSA 公钥，获取加密配置文件时返回密文以及使用公钥加密的数据密钥").DataType("string").Required(false)).
+		Param(restful.HeaderParameter(utils.HeaderClientIDKey,
+			"客户端 ID，用于灰度发布分桶以及客户端版本固定，缺省时使用客户端 IP").DataType("string").Required(false))
 }
 
 func enrichWatchConfigFileForClientApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("监听配置").
 		Metadata(restfulspec.KeyOpenAPITags, configClientApiTags).
+		Param(restful.HeaderParameter(utils.HeaderClientIDKey,
+			"客户端 ID，用于灰度发布分桶以及客户端版本固定，缺省时使用客户端 IP").DataType("string").Required(false)).
 		Reads(api.ClientWatchConfigFileRequest{}, "通过 Http LongPolling 机制订阅配置变更。")
 }
+
+func enrichReportConfigFileErrorApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("上报加载配置失败，加载灰度发布的内容失败次数达到灰度规则的 maxErrors 时自动取消灰度发布").
+		Metadata(restfulspec.KeyOpenAPITags, configClientApiTags).
+		Reads(api.ClientConfigFileInfo{}, "md5 为客户端加载失败的配置内容的 md5")
+}
@@ -0,0 +1,182 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// configFileVariableRequest 创建、更新配置文件模板变量请求，type 为空时为字符串类型
+type configFileVariableRequest struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Comment   string `json:"comment"`
+}
+
+// configFileVariableView 配置文件模板变量
+type configFileVariableView struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Comment    string `json:"comment"`
+	CreateTime string `json:"createTime"`
+	CreateBy   string `json:"createBy"`
+	ModifyTime string `json:"modifyTime"`
+	ModifyBy   string `json:"modifyBy"`
+}
+
+// configFileVariablesView 配置文件模板变量列表
+type configFileVariablesView struct {
+	Code      uint32                    `json:"code"`
+	Info      string                    `json:"info"`
+	Variables []*configFileVariableView `json:"variables"`
+}
+
+// renderedConfigFileView 配置文件渲染后的内容
+type renderedConfigFileView struct {
+	Code      uint32 `json:"code"`
+	Info      string `json:"info"`
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	Name      string `json:"name"`
+	Content   string `json:"content"`
+}
+
+func newConfigFileVariableView(variable *model.ConfigFileVariable) *configFileVariableView {
+	return &configFileVariableView{
+		Namespace:  variable.Namespace,
+		Name:       variable.Name,
+		Type:       variable.Type,
+		Value:      variable.Value,
+		Comment:    variable.Comment,
+		CreateTime: commontime.Time2String(variable.CreateTime),
+		CreateBy:   variable.CreateBy,
+		ModifyTime: commontime.Time2String(variable.ModifyTime),
+		ModifyBy:   variable.ModifyBy,
+	}
+}
+
+// GetConfigFileVariables 获取命名空间下的全部配置文件模板变量
+func (h *HTTPServer) GetConfigFileVariables(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	variables, response := h.configServer.GetConfigFileVariables(handler.ParseHeaderContext(),
+		handler.QueryParameter("namespace"))
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	views := make([]*configFileVariableView, 0, len(variables))
+	for _, variable := range variables {
+		views = append(views, newConfigFileVariableView(variable))
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileVariablesView{
+		Code:      response.GetCode().GetValue(),
+		Info:      response.GetInfo().GetValue(),
+		Variables: views,
+	}, restful.MIME_JSON)
+}
+
+// CreateConfigFileVariable 创建配置文件模板变量
+func (h *HTTPServer) CreateConfigFileVariable(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	variable, ok := h.parseConfigFileVariable(ctx, handler)
+	if !ok {
+		return
+	}
+	handler.WriteHeaderAndProto(h.configServer.CreateConfigFileVariable(ctx, variable))
+}
+
+// UpdateConfigFileVariable 更新配置文件模板变量
+func (h *HTTPServer) UpdateConfigFileVariable(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	variable, ok := h.parseConfigFileVariable(ctx, handler)
+	if !ok {
+		return
+	}
+	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileVariable(ctx, variable))
+}
+
+// DeleteConfigFileVariable 删除配置文件模板变量
+func (h *HTTPServer) DeleteConfigFileVariable(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	handler.WriteHeaderAndProto(h.configServer.DeleteConfigFileVariable(handler.ParseHeaderContext(),
+		handler.QueryParameter("namespace"), handler.QueryParameter("name")))
+}
+
+// RenderConfigFile 预览配置文件发布时渲染后的内容
+func (h *HTTPServer) RenderConfigFile(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+
+	content, response := h.configServer.RenderConfigFile(handler.ParseHeaderContext(), namespace, group, name)
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &renderedConfigFileView{
+		Code:      response.GetCode().GetValue(),
+		Info:      response.GetInfo().GetValue(),
+		Namespace: namespace,
+		Group:     group,
+		Name:      name,
+		Content:   content,
+	}, restful.MIME_JSON)
+}
+
+func (h *HTTPServer) parseConfigFileVariable(ctx context.Context,
+	handler *httpcommon.Handler) (*model.ConfigFileVariable, bool) {
+
+	variableReq := &configFileVariableRequest{}
+	if err := handler.Request.ReadEntity(variableReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file variable from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.ParseException, err.Error()))
+		return nil, false
+	}
+	return &model.ConfigFileVariable{
+		Namespace: variableReq.Namespace,
+		Name:      variableReq.Name,
+		Type:      variableReq.Type,
+		Value:     variableReq.Value,
+		Comment:   variableReq.Comment,
+	}, true
+}
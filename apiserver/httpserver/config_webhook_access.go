@@ -0,0 +1,299 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/http"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// configFileWebhookRequest 创建、更新配置文件变更 webhook 请求，更新时 name、namespace、group、fileName 不能修改
+type configFileWebhookRequest struct {
+	Id        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	FileName  string `json:"fileName"`
+	Url       string `json:"url"`
+	Secret    string `json:"secret"`
+	Events    string `json:"events"`
+	// Enable 为空时默认启用
+	Enable  *bool  `json:"enable"`
+	Comment string `json:"comment"`
+}
+
+// configFileWebhookView 配置文件变更 webhook，secret 只在创建时返回
+type configFileWebhookView struct {
+	Id         uint64 `json:"id"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Group      string `json:"group"`
+	FileName   string `json:"fileName"`
+	Url        string `json:"url"`
+	Secret     string `json:"secret,omitempty"`
+	Events     string `json:"events"`
+	Enable     bool   `json:"enable"`
+	Comment    string `json:"comment"`
+	CreateTime string `json:"createTime"`
+	CreateBy   string `json:"createBy"`
+	ModifyTime string `json:"modifyTime"`
+	ModifyBy   string `json:"modifyBy"`
+}
+
+// configFileWebhookResponse 创建配置文件变更 webhook 的结果
+type configFileWebhookResponse struct {
+	Code    uint32                 `json:"code"`
+	Info    string                 `json:"info"`
+	Webhook *configFileWebhookView `json:"webhook"`
+}
+
+// configFileWebhooksView 配置文件变更 webhook 列表
+type configFileWebhooksView struct {
+	Code     uint32                   `json:"code"`
+	Info     string                   `json:"info"`
+	Total    uint32                   `json:"total"`
+	Webhooks []*configFileWebhookView `json:"webhooks"`
+}
+
+// configFileWebhookDeliveryView webhook 推送记录
+type configFileWebhookDeliveryView struct {
+	Id           uint64 `json:"id"`
+	WebhookId    uint64 `json:"webhookId"`
+	EventType    string `json:"eventType"`
+	Namespace    string `json:"namespace"`
+	Group        string `json:"group"`
+	FileName     string `json:"fileName"`
+	Payload      string `json:"payload"`
+	Status       string `json:"status"`
+	Attempts     int    `json:"attempts"`
+	ResponseCode int    `json:"responseCode"`
+	Error        string `json:"error"`
+	CreateTime   string `json:"createTime"`
+	ModifyTime   string `json:"modifyTime"`
+}
+
+// configFileWebhookDeliveriesView webhook 推送记录列表
+type configFileWebhookDeliveriesView struct {
+	Code       uint32                           `json:"code"`
+	Info       string                           `json:"info"`
+	Total      uint32                           `json:"total"`
+	Deliveries []*configFileWebhookDeliveryView `json:"deliveries"`
+}
+
+func newConfigFileWebhookView(webhook *model.ConfigFileWebhook) *configFileWebhookView {
+	return &configFileWebhookView{
+		Id:         webhook.Id,
+		Name:       webhook.Name,
+		Namespace:  webhook.Namespace,
+		Group:      webhook.Group,
+		FileName:   webhook.FileName,
+		Url:        webhook.Url,
+		Events:     webhook.Events,
+		Enable:     webhook.Enable,
+		Comment:    webhook.Comment,
+		CreateTime: commontime.Time2String(webhook.CreateTime),
+		CreateBy:   webhook.CreateBy,
+		ModifyTime: commontime.Time2String(webhook.ModifyTime),
+		ModifyBy:   webhook.ModifyBy,
+	}
+}
+
+func newConfigFileWebhookDeliveryView(delivery *model.ConfigFileWebhookDelivery) *configFileWebhookDeliveryView {
+	return &configFileWebhookDeliveryView{
+		Id:           delivery.Id,
+		WebhookId:    delivery.WebhookId,
+		EventType:    delivery.EventType,
+		Namespace:    delivery.Namespace,
+		Group:        delivery.Group,
+		FileName:     delivery.FileName,
+		Payload:      delivery.Payload,
+		Status:       delivery.Status,
+		Attempts:     delivery.Attempts,
+		ResponseCode: delivery.ResponseCode,
+		Error:        delivery.Error,
+		CreateTime:   commontime.Time2String(delivery.CreateTime),
+		ModifyTime:   commontime.Time2String(delivery.ModifyTime),
+	}
+}
+
+// QueryConfigFileWebhooks 查询命名空间下的配置文件变更 webhook
+func (h *HTTPServer) QueryConfigFileWebhooks(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	namespace := handler.QueryParameter("namespace")
+	group := handler.QueryParameter("group")
+	name := handler.QueryParameter("name")
+	offset, _ := strconv.ParseUint(handler.QueryParameter("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(handler.QueryParameter("limit"), 10, 64)
+
+	total, webhooks, response := h.configServer.QueryConfigFileWebhooks(handler.ParseHeaderContext(),
+		namespace, group, name, uint32(offset), uint32(limit))
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	views := make([]*configFileWebhookView, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		views = append(views, newConfigFileWebhookView(webhook))
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileWebhooksView{
+		Code:     response.GetCode().GetValue(),
+		Info:     response.GetInfo().GetValue(),
+		Total:    total,
+		Webhooks: views,
+	}, restful.MIME_JSON)
+}
+
+// GetConfigFileWebhook 获取单个配置文件变更 webhook
+func (h *HTTPServer) GetConfigFileWebhook(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	id, err := strconv.ParseUint(handler.QueryParameter("id"), 10, 64)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid webhook id"))
+		return
+	}
+
+	webhook, response := h.configServer.GetConfigFileWebhook(handler.ParseHeaderContext(), id)
+	if webhook == nil {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, newConfigFileWebhookView(webhook), restful.MIME_JSON)
+}
+
+// CreateConfigFileWebhook 创建配置文件变更 webhook，返回结果中包含用于校验签名的 secret
+func (h *HTTPServer) CreateConfigFileWebhook(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	webhook, ok := h.parseConfigFileWebhook(ctx, handler)
+	if !ok {
+		return
+	}
+
+	created, response := h.configServer.CreateConfigFileWebhook(ctx, webhook)
+	if created == nil {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+	view := newConfigFileWebhookView(created)
+	view.Secret = created.Secret
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileWebhookResponse{
+		Code:    response.GetCode().GetValue(),
+		Info:    response.GetInfo().GetValue(),
+		Webhook: view,
+	}, restful.MIME_JSON)
+}
+
+// UpdateConfigFileWebhook 更新配置文件变更 webhook
+func (h *HTTPServer) UpdateConfigFileWebhook(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	ctx := handler.ParseHeaderContext()
+	webhook, ok := h.parseConfigFileWebhook(ctx, handler)
+	if !ok {
+		return
+	}
+	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileWebhook(ctx, webhook))
+}
+
+// DeleteConfigFileWebhook 删除配置文件变更 webhook
+func (h *HTTPServer) DeleteConfigFileWebhook(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	id, err := strconv.ParseUint(handler.QueryParameter("id"), 10, 64)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid webhook id"))
+		return
+	}
+	handler.WriteHeaderAndProto(h.configServer.DeleteConfigFileWebhook(handler.ParseHeaderContext(), id))
+}
+
+// QueryConfigFileWebhookDeliveries 查询 webhook 的推送记录，按照创建时间倒序排序
+func (h *HTTPServer) QueryConfigFileWebhookDeliveries(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{req, rsp}
+
+	webhookId, err := strconv.ParseUint(handler.QueryParameter("webhookId"), 10, 64)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid webhook id"))
+		return
+	}
+	status := handler.QueryParameter("status")
+	offset, _ := strconv.ParseUint(handler.QueryParameter("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(handler.QueryParameter("limit"), 10, 64)
+
+	total, deliveries, response := h.configServer.QueryConfigFileWebhookDeliveries(handler.ParseHeaderContext(),
+		webhookId, status, uint32(offset), uint32(limit))
+	if response.GetCode().GetValue() != api.ExecuteSuccess {
+		handler.WriteHeaderAndProto(response)
+		return
+	}
+
+	views := make([]*configFileWebhookDeliveryView, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		views = append(views, newConfigFileWebhookDeliveryView(delivery))
+	}
+	_ = rsp.WriteHeaderAndJson(http.StatusOK, &configFileWebhookDeliveriesView{
+		Code:       response.GetCode().GetValue(),
+		Info:       response.GetInfo().GetValue(),
+		Total:      total,
+		Deliveries: views,
+	}, restful.MIME_JSON)
+}
+
+func (h *HTTPServer) parseConfigFileWebhook(ctx context.Context,
+	handler *httpcommon.Handler) (*model.ConfigFileWebhook, bool) {
+
+	webhookReq := &configFileWebhookRequest{}
+	if err := handler.Request.ReadEntity(webhookReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file webhook from request error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileResponseWithMessage(api.ParseException, err.Error()))
+		return nil, false
+	}
+
+	enable := true
+	if webhookReq.Enable != nil {
+		enable = *webhookReq.Enable
+	}
+	return &model.ConfigFileWebhook{
+		Id:        webhookReq.Id,
+		Name:      webhookReq.Name,
+		Namespace: webhookReq.Namespace,
+		Group:     webhookReq.Group,
+		FileName:  webhookReq.FileName,
+		Url:       webhookReq.Url,
+		Secret:    webhookReq.Secret,
+		Events:    webhookReq.Events,
+		Enable:    enable,
+		Comment:   webhookReq.Comment,
+	}, true
+}
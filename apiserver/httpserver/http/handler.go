@@ -80,6 +80,12 @@ func (h *Handler) postParseMessage(requestID string) (context.Context, error) {
 	if authToken != "" {
 		ctx = context.WithValue(ctx, utils.ContextAuthTokenKey, authToken)
 	}
+	if publicKey := h.Request.HeaderParameter(utils.HeaderConfigPublicKey); publicKey != "" {
+		ctx = context.WithValue(ctx, utils.ContextConfigPublicKey, publicKey)
+	}
+	if clientID := h.Request.HeaderParameter(utils.HeaderClientIDKey); clientID != "" {
+		ctx = context.WithValue(ctx, utils.ContextClientIDKey, clientID)
+	}
 
 	var operator string
 	addrSlice := strings.Split(h.Request.Request.RemoteAddr, ":")
@@ -116,6 +116,7 @@ func init() {
 		api.AuthStrategyRuleExisted:                {ID: fmt.Sprint(api.AuthStrategyRuleExisted)},
 		api.SubAccountExisted:                      {ID: fmt.Sprint(api.SubAccountExisted)},
 		api.NamespaceExistedConfigGroups:           {ID: fmt.Sprint(api.NamespaceExistedConfigGroups)},
+		api.NamespaceQuotaExceeded:                 {ID: fmt.Sprint(api.NamespaceQuotaExceeded)},
 		api.NotFoundService:                        {ID: fmt.Sprint(api.NotFoundService)},
 		api.NotFoundRouting:                        {ID: fmt.Sprint(api.NotFoundRouting)},
 		api.NotFoundInstance:                       {ID: fmt.Sprint(api.NotFoundInstance)},
@@ -153,6 +154,7 @@ func init() {
 		api.NotAllowDifferentNamespaceBindRule:     {ID: fmt.Sprint(api.NotAllowDifferentNamespaceBindRule)},
 		api.NotAllowModifyDefaultStrategyPrincipal: {ID: fmt.Sprint(api.NotAllowModifyDefaultStrategyPrincipal)},
 		api.NotAllowModifyOwnerDefaultStrategy:     {ID: fmt.Sprint(api.NotAllowModifyOwnerDefaultStrategy)},
+		api.NotAllowModifySyncedInstance:           {ID: fmt.Sprint(api.NotAllowModifySyncedInstance)},
 		api.InvalidRoutingID:                       {ID: fmt.Sprint(api.InvalidRoutingID)},
 		api.InvalidRoutingPolicy:                   {ID: fmt.Sprint(api.InvalidRoutingPolicy)},
 		api.InvalidRoutingName:                     {ID: fmt.Sprint(api.InvalidRoutingName)},
@@ -165,6 +167,11 @@ func init() {
 		api.InvalidWatchConfigFileFormat:           {ID: fmt.Sprint(api.InvalidWatchConfigFileFormat)},
 		api.NotFoundResourceConfigFile:             {ID: fmt.Sprint(api.NotFoundResourceConfigFile)},
 		api.InvalidConfigFileTemplateName:          {ID: fmt.Sprint(api.InvalidConfigFileTemplateName)},
+		api.ConfigFileChangeNotApproved:            {ID: fmt.Sprint(api.ConfigFileChangeNotApproved)},
+		api.InvalidConfigFileContent:               {ID: fmt.Sprint(api.InvalidConfigFileContent)},
+		api.InvalidConfigFileSchema:                {ID: fmt.Sprint(api.InvalidConfigFileSchema)},
+		api.InvalidConfigFileTemplate:              {ID: fmt.Sprint(api.InvalidConfigFileTemplate)},
+		api.ConfigFileChangeSelfApproval:           {ID: fmt.Sprint(api.ConfigFileChangeSelfApproval)},
 		api.Unauthorized:                           {ID: fmt.Sprint(api.Unauthorized)},
 		api.NotAllowedAccess:                       {ID: fmt.Sprint(api.NotAllowedAccess)},
 		api.EmptyAutToken:                          {ID: fmt.Sprint(api.EmptyAutToken)},
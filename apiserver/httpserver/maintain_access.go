@@ -39,6 +39,7 @@ func (h *HTTPServer) GetMaintainAccessServer() *restful.WebService {
 
 	ws.Route(ws.GET("/apiserver/conn").To(h.GetServerConnections))
 	ws.Route(ws.GET("/apiserver/conn/stats").To(h.GetServerConnStats))
+	ws.Route(ws.GET("/config/conn").To(h.GetConfigConnections))
 	ws.Route(ws.POST("apiserver/conn/close").To(h.CloseConnections))
 	ws.Route(ws.POST("/memory/free").To(h.FreeOSMemory))
 	ws.Route(ws.POST("/instance/clean").Consumes(restful.MIME_JSON).To(h.CleanInstance))
@@ -94,6 +95,18 @@ func (h *HTTPServer) GetServerConnStats(req *restful.Request, rsp *restful.Respo
 	}
 }
 
+// GetConfigConnections 查看配置中心客户端长轮询、长连接的统计
+func (h *HTTPServer) GetConfigConnections(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+
+	ret, err := h.maintainServer.GetConfigConnections(ctx)
+	if err != nil {
+		_ = rsp.WriteError(http.StatusBadRequest, err)
+	} else {
+		_ = rsp.WriteAsJson(ret)
+	}
+}
+
 // CloseConnections 关闭指定client ip的连接
 func (h *HTTPServer) CloseConnections(req *restful.Request, rsp *restful.Response) {
 	log.Info("[MAINTAIN] Start doing close connections")
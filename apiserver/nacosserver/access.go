@@ -0,0 +1,153 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	operationRegister     = "/instance"
+	operationBeat         = "/instance/beat"
+	operationInstanceList = "/instance/list"
+	operationConfigs      = "/configs"
+	operationListener     = "/configs/listener"
+
+	namingPathPrefix = "/nacos/v1/ns"
+	configPathPrefix = "/nacos/v1/cs"
+
+	paramAccessToken = "accessToken"
+
+	mimeForm    = "application/x-www-form-urlencoded"
+	mimeText    = "text/plain"
+	mimeAll     = "*/*"
+	charsetUTF8 = ";charset=UTF-8"
+)
+
+// GetNamingServer nacos 注册发现 web server
+func (h *NacosServer) GetNamingServer() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(namingPathPrefix).Consumes(mimeForm, restful.MIME_JSON, mimeAll).Produces(restful.MIME_JSON, mimeText)
+	ws.Route(ws.POST(operationRegister).To(h.RegisterInstance)).
+		Doc("注册实例").
+		Param(ws.QueryParameter("serviceName", "服务名").DataType("string").Required(true)).
+		Param(ws.QueryParameter("groupName", "分组名").DataType("string")).
+		Param(ws.QueryParameter("ip", "实例IP").DataType("string").Required(true)).
+		Param(ws.QueryParameter("port", "实例端口").DataType("int").Required(true))
+	ws.Route(ws.DELETE(operationRegister).To(h.DeregisterInstance)).
+		Doc("反注册实例").
+		Param(ws.QueryParameter("serviceName", "服务名").DataType("string").Required(true)).
+		Param(ws.QueryParameter("groupName", "分组名").DataType("string")).
+		Param(ws.QueryParameter("ip", "实例IP").DataType("string").Required(true)).
+		Param(ws.QueryParameter("port", "实例端口").DataType("int").Required(true))
+	ws.Route(ws.PUT(operationBeat).To(h.Beat)).
+		Doc("实例心跳").
+		Param(ws.QueryParameter("serviceName", "服务名").DataType("string").Required(true)).
+		Param(ws.QueryParameter("beat", "心跳信息").DataType("string"))
+	ws.Route(ws.GET(operationInstanceList).To(h.ListInstances)).
+		Doc("查询实例列表").
+		Param(ws.QueryParameter("serviceName", "服务名").DataType("string").Required(true)).
+		Param(ws.QueryParameter("groupName", "分组名").DataType("string")).
+		Param(ws.QueryParameter("clusters", "集群名，多个以逗号分隔").DataType("string")).
+		Param(ws.QueryParameter("healthyOnly", "是否只返回健康实例").DataType("boolean"))
+	return ws
+}
+
+// GetConfigServer nacos 配置中心 web server
+func (h *NacosServer) GetConfigServer() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(configPathPrefix).Consumes(mimeForm, restful.MIME_JSON, mimeAll).Produces(mimeText, restful.MIME_JSON)
+	ws.Route(ws.GET(operationConfigs).To(h.GetConfig)).
+		Doc("获取配置").
+		Param(ws.QueryParameter("dataId", "配置ID").DataType("string").Required(true)).
+		Param(ws.QueryParameter("group", "配置分组").DataType("string").Required(true)).
+		Param(ws.QueryParameter("tenant", "租户").DataType("string"))
+	ws.Route(ws.POST(operationConfigs).To(h.PublishConfig)).
+		Doc("发布配置").
+		Param(ws.QueryParameter("dataId", "配置ID").DataType("string").Required(true)).
+		Param(ws.QueryParameter("group", "配置分组").DataType("string").Required(true)).
+		Param(ws.QueryParameter("content", "配置内容").DataType("string").Required(true)).
+		Param(ws.QueryParameter("tenant", "租户").DataType("string")).
+		Param(ws.QueryParameter("type", "配置格式").DataType("string"))
+	ws.Route(ws.DELETE(operationConfigs).To(h.DeleteConfig)).
+		Doc("删除配置").
+		Param(ws.QueryParameter("dataId", "配置ID").DataType("string").Required(true)).
+		Param(ws.QueryParameter("group", "配置分组").DataType("string").Required(true)).
+		Param(ws.QueryParameter("tenant", "租户").DataType("string"))
+	ws.Route(ws.POST(operationListener).To(h.ListenConfigs)).
+		Doc("监听配置").
+		Param(ws.FormParameter(paramListeningConfigs, "监听的配置列表").DataType("string").Required(true))
+	return ws
+}
+
+// formValue nacos 客户端的参数可能放在 query 中，也可能放在 form 表单中
+func formValue(req *restful.Request, key string) string {
+	return req.Request.FormValue(key)
+}
+
+// parseContext 构建请求上下文，nacos 客户端通过 accessToken 参数传递鉴权信息
+func parseContext(req *restful.Request) context.Context {
+	ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, formValue(req, paramAccessToken))
+	ctx = context.WithValue(ctx, utils.ContextClientAddress, req.Request.RemoteAddr)
+	ctx = context.WithValue(ctx, utils.StringContext("request-id"), utils.NewUUID())
+	return ctx
+}
+
+func writePolarisStatusCode(req *restful.Request, statusCode uint32) {
+	req.SetAttribute(statusCodeHeader, statusCode)
+}
+
+// writeText 以纯文本的方式应答，nacos open api 的写接口均返回纯文本
+func writeText(req *restful.Request, rsp *restful.Response, code uint32, httpStatus int, text string) {
+	writePolarisStatusCode(req, code)
+	rsp.Header().Set(restful.HEADER_ContentType, mimeText+charsetUTF8)
+	rsp.WriteHeader(httpStatus)
+	_, _ = rsp.Write([]byte(text))
+}
+
+// writeJSON 以 json 的方式应答
+func writeJSON(req *restful.Request, rsp *restful.Response, code uint32, value interface{}) {
+	writePolarisStatusCode(req, code)
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeText(req, rsp, api.ExecuteException, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rsp.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	rsp.WriteHeader(http.StatusOK)
+	_, _ = rsp.Write(data)
+}
+
+// writeError 将北极星的错误码转换为 http 状态码应答
+func writeError(req *restful.Request, rsp *restful.Response, code uint32, message string) {
+	httpStatus := int(code / 1000)
+	if httpStatus < http.StatusBadRequest || len(http.StatusText(httpStatus)) == 0 {
+		httpStatus = http.StatusInternalServerError
+	}
+	if len(message) == 0 {
+		message = api.Code2Info(code)
+	}
+	writeText(req, rsp, code, httpStatus, "caused: "+message)
+}
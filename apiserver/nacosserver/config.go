@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import "time"
+
+const (
+	optionListenIP   = "listenIP"
+	optionListenPort = "listenPort"
+	optionNamespace  = "namespace"
+	optionConnLimit  = "connLimit"
+	optionTLS        = "tls"
+)
+
+const (
+	// DefaultNamespace polaris namespace for nacos DEFAULT_GROUP services and public tenant configs
+	DefaultNamespace = "default"
+	// DefaultGroup nacos default group name
+	DefaultGroup = "DEFAULT_GROUP"
+	// DefaultCluster nacos default cluster name
+	DefaultCluster = "DEFAULT"
+	// DefaultTenant nacos default namespace id
+	DefaultTenant = "public"
+	// DefaultHeartbeatTTL ttl of nacos ephemeral instances, same as nacos client beat interval
+	DefaultHeartbeatTTL = 5
+	// DefaultHeartbeatInterval nacos client beat interval in milliseconds
+	DefaultHeartbeatInterval = 5000
+	// DefaultHeartbeatTimeout instance turns unhealthy after this time without beat, in milliseconds
+	DefaultHeartbeatTimeout = 15000
+	// DefaultIPDeleteTimeout nacos ip delete timeout in milliseconds
+	DefaultIPDeleteTimeout = 30000
+	// DefaultCacheMillis client side cache time of instance list
+	DefaultCacheMillis = 10000
+	// DefaultLongPullingTimeout max hold time of config listener request
+	DefaultLongPullingTimeout = 30 * time.Second
+	// longPullingDelay respond a little earlier than client timeout, same as nacos server
+	longPullingDelay = 500 * time.Millisecond
+)
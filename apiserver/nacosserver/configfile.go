@@ -0,0 +1,301 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	paramListeningConfigs = "Listening-Configs"
+
+	headerLongPullingTimeout  = "Long-Pulling-Timeout"
+	headerLongPullingNoHangup = "Long-Pulling-No-Hangup"
+	headerContentMD5          = "Content-MD5"
+	headerConfigType          = "Config-Type"
+
+	// nacos 监听配置报文中字段与字段、配置与配置之间的分隔符
+	wordSeparator = "\x02"
+	lineSeparator = "\x01"
+)
+
+// configKey nacos 配置的唯一标识，dataId 对应北极星的配置文件名
+type configKey struct {
+	dataId string
+	group  string
+	tenant string
+	md5    string
+}
+
+// toConfigNamespace nacos 的租户映射为北极星的命名空间，public 租户映射为配置的默认命名空间
+func (h *NacosServer) toConfigNamespace(tenant string) string {
+	if len(tenant) == 0 || tenant == DefaultTenant {
+		return h.namespace
+	}
+	return tenant
+}
+
+func parseConfigKey(req *restful.Request) (*configKey, error) {
+	key := &configKey{
+		dataId: formValue(req, "dataId"),
+		group:  formValue(req, "group"),
+		tenant: formValue(req, "tenant"),
+	}
+	if len(key.dataId) == 0 {
+		return nil, fmt.Errorf("param 'dataId' is required")
+	}
+	if len(key.group) == 0 {
+		key.group = DefaultGroup
+	}
+	return key, nil
+}
+
+// parseListeningConfigs 解析监听报文：dataId^2group^2md5^2tenant^1，tenant 可以为空
+func parseListeningConfigs(value string) ([]*configKey, error) {
+	var keys []*configKey
+	for _, line := range strings.Split(value, lineSeparator) {
+		if len(line) == 0 {
+			continue
+		}
+		words := strings.Split(line, wordSeparator)
+		if len(words) != 3 && len(words) != 4 {
+			return nil, fmt.Errorf("invalid listening config: %q", line)
+		}
+		key := &configKey{dataId: words[0], group: words[1], md5: words[2]}
+		if len(words) == 4 {
+			key.tenant = words[3]
+		}
+		if len(key.dataId) == 0 || len(key.group) == 0 {
+			return nil, fmt.Errorf("invalid listening config: %q", line)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("param '%s' is required", paramListeningConfigs)
+	}
+	return keys, nil
+}
+
+// encodeChangedConfigs 生成监听应答：dataId^2group^2tenant^1，并进行 url 编码
+func encodeChangedConfigs(keys []*configKey) string {
+	builder := strings.Builder{}
+	for _, key := range keys {
+		builder.WriteString(key.dataId)
+		builder.WriteString(wordSeparator)
+		builder.WriteString(key.group)
+		if len(key.tenant) > 0 {
+			builder.WriteString(wordSeparator)
+			builder.WriteString(key.tenant)
+		}
+		builder.WriteString(lineSeparator)
+	}
+	return url.QueryEscape(builder.String())
+}
+
+func (h *NacosServer) toClientConfigFile(key *configKey) *api.ClientConfigFileInfo {
+	return &api.ClientConfigFileInfo{
+		Namespace: utils.NewStringValue(h.toConfigNamespace(key.tenant)),
+		Group:     utils.NewStringValue(key.group),
+		FileName:  utils.NewStringValue(key.dataId),
+	}
+}
+
+// GetConfig 获取已发布的配置内容
+func (h *NacosServer) GetConfig(req *restful.Request, rsp *restful.Response) {
+	key, err := parseConfigKey(req)
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	resp := h.configServer.GetConfigFileForClient(parseContext(req), h.toClientConfigFile(key))
+	code := resp.GetCode().GetValue()
+	switch code {
+	case api.ExecuteSuccess:
+		rsp.Header().Set(headerContentMD5, resp.GetConfigFile().GetMd5().GetValue())
+		rsp.Header().Set(headerConfigType, utils.FileFormatText)
+		writeText(req, rsp, code, http.StatusOK, resp.GetConfigFile().GetContent().GetValue())
+	case api.NotFoundResource:
+		writeText(req, rsp, code, http.StatusNotFound, "config data not exist")
+	default:
+		writeError(req, rsp, code, resp.GetInfo().GetValue())
+	}
+}
+
+// PublishConfig 创建或更新配置，并立即发布
+func (h *NacosServer) PublishConfig(req *restful.Request, rsp *restful.Response) {
+	key, err := parseConfigKey(req)
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	content := formValue(req, "content")
+	if len(content) == 0 {
+		writeError(req, rsp, api.InvalidParameter, "param 'content' is required")
+		return
+	}
+	format := formValue(req, "type")
+	if !utils.IsValidFileFormat(format) {
+		format = utils.FileFormatText
+	}
+
+	ctx := parseContext(req)
+	namespace := h.toConfigNamespace(key.tenant)
+	configFile := &api.ConfigFile{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(key.group),
+		Name:      utils.NewStringValue(key.dataId),
+		Content:   utils.NewStringValue(content),
+		Format:    utils.NewStringValue(format),
+		Comment:   utils.NewStringValue(formValue(req, "desc")),
+	}
+	var resp *api.ConfigResponse
+	baseResp := h.configServer.GetConfigFileBaseInfo(ctx, namespace, key.group, key.dataId)
+	switch baseResp.GetCode().GetValue() {
+	case api.ExecuteSuccess:
+		resp = h.configServer.UpdateConfigFile(ctx, configFile)
+	case api.NotFoundResource:
+		resp = h.configServer.CreateConfigFile(ctx, configFile)
+	default:
+		resp = baseResp
+	}
+	if code := resp.GetCode().GetValue(); code != api.ExecuteSuccess {
+		log.Errorf("[NACOS-SERVER]save config (namespace=%s, group=%s, dataId=%s) failed, code is %d",
+			namespace, key.group, key.dataId, code)
+		writeError(req, rsp, code, resp.GetInfo().GetValue())
+		return
+	}
+
+	resp = h.configServer.PublishConfigFile(ctx, &api.ConfigFileRelease{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(key.group),
+		FileName:  utils.NewStringValue(key.dataId),
+	})
+	if code := resp.GetCode().GetValue(); code != api.ExecuteSuccess {
+		log.Errorf("[NACOS-SERVER]publish config (namespace=%s, group=%s, dataId=%s) failed, code is %d",
+			namespace, key.group, key.dataId, code)
+		writeError(req, rsp, code, resp.GetInfo().GetValue())
+		return
+	}
+	writeText(req, rsp, api.ExecuteSuccess, http.StatusOK, "true")
+}
+
+// DeleteConfig 删除配置及其发布内容
+func (h *NacosServer) DeleteConfig(req *restful.Request, rsp *restful.Response) {
+	key, err := parseConfigKey(req)
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	ctx := parseContext(req)
+	namespace := h.toConfigNamespace(key.tenant)
+	resp := h.configServer.DeleteConfigFile(ctx, namespace, key.group, key.dataId, utils.ParseUserName(ctx))
+	if code := resp.GetCode().GetValue(); code != api.ExecuteSuccess && code != api.NotFoundResource {
+		log.Errorf("[NACOS-SERVER]delete config (namespace=%s, group=%s, dataId=%s) failed, code is %d",
+			namespace, key.group, key.dataId, code)
+		writeError(req, rsp, code, resp.GetInfo().GetValue())
+		return
+	}
+	writeText(req, rsp, api.ExecuteSuccess, http.StatusOK, "true")
+}
+
+// ListenConfigs 监听配置变更，客户端 md5 与服务端不一致的配置会立即返回，否则 hold 住请求直到配置发布或超时
+func (h *NacosServer) ListenConfigs(req *restful.Request, rsp *restful.Response) {
+	keys, err := parseListeningConfigs(formValue(req, paramListeningConfigs))
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	ctx := parseContext(req)
+	changed, watchFiles := h.compareConfigs(ctx, keys)
+
+	timeout := longPullingTimeout(req)
+	if len(changed) > 0 || timeout <= 0 || req.HeaderParameter(headerLongPullingNoHangup) == "true" {
+		writeText(req, rsp, api.ExecuteSuccess, http.StatusOK, encodeChangedConfigs(changed))
+		return
+	}
+
+	callback, err := h.configServer.WatchConfigFiles(ctx, &api.ClientWatchConfigFileRequest{WatchFiles: watchFiles})
+	if err != nil {
+		writeError(req, rsp, api.ExecuteException, err.Error())
+		return
+	}
+	finishCh := make(chan struct{}, 1)
+	go func() {
+		_ = callback()
+		finishCh <- struct{}{}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-finishCh:
+	case <-timer.C:
+	case <-req.Request.Context().Done():
+		return
+	}
+	changed, _ = h.compareConfigs(ctx, keys)
+	writeText(req, rsp, api.ExecuteSuccess, http.StatusOK, encodeChangedConfigs(changed))
+}
+
+// compareConfigs 对比客户端与服务端的配置 md5，返回发生变更的配置以及用于监听的配置文件列表
+func (h *NacosServer) compareConfigs(ctx context.Context,
+	keys []*configKey) ([]*configKey, []*api.ClientConfigFileInfo) {
+	var changed []*configKey
+	watchFiles := make([]*api.ClientConfigFileInfo, 0, len(keys))
+	for _, key := range keys {
+		file := h.toClientConfigFile(key)
+		resp := h.configServer.GetConfigFileForClient(ctx, file)
+		var md5 string
+		if resp.GetCode().GetValue() == api.ExecuteSuccess {
+			md5 = resp.GetConfigFile().GetMd5().GetValue()
+			file.Version = utils.NewUInt64Value(resp.GetConfigFile().GetVersion().GetValue())
+		}
+		if md5 != key.md5 {
+			changed = append(changed, key)
+		}
+		watchFiles = append(watchFiles, file)
+	}
+	return changed, watchFiles
+}
+
+// longPullingTimeout 根据客户端的超时时间计算 hold 请求的时间，没有超时时间的请求为短轮询
+func longPullingTimeout(req *restful.Request) time.Duration {
+	value := req.HeaderParameter(headerLongPullingTimeout)
+	if len(value) == 0 {
+		return 0
+	}
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	timeout := time.Duration(millis)*time.Millisecond - longPullingDelay
+	if timeout > DefaultLongPullingTimeout {
+		timeout = DefaultLongPullingTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,244 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+type mockConfigServer struct {
+	config.ConfigCenterServer
+	files    map[string]*api.ConfigFile
+	releases map[string]*api.ClientConfigFileInfo
+	// onWatch 模拟监听期间发生的配置发布，为空时模拟监听超时
+	onWatch func()
+}
+
+func newMockConfigServer() *mockConfigServer {
+	return &mockConfigServer{
+		files:    map[string]*api.ConfigFile{},
+		releases: map[string]*api.ClientConfigFileInfo{},
+	}
+}
+
+func fileKey(namespace, group, name string) string {
+	return namespace + "/" + group + "/" + name
+}
+
+func (m *mockConfigServer) GetConfigFileBaseInfo(ctx context.Context, namespace, group,
+	name string) *api.ConfigResponse {
+	file, ok := m.files[fileKey(namespace, group, name)]
+	if !ok {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, file)
+}
+
+func (m *mockConfigServer) CreateConfigFile(ctx context.Context, file *api.ConfigFile) *api.ConfigResponse {
+	m.files[fileKey(file.GetNamespace().GetValue(), file.GetGroup().GetValue(), file.GetName().GetValue())] = file
+	return api.NewConfigFileResponse(api.ExecuteSuccess, file)
+}
+
+func (m *mockConfigServer) UpdateConfigFile(ctx context.Context, file *api.ConfigFile) *api.ConfigResponse {
+	return m.CreateConfigFile(ctx, file)
+}
+
+func (m *mockConfigServer) PublishConfigFile(ctx context.Context,
+	release *api.ConfigFileRelease) *api.ConfigResponse {
+	key := fileKey(release.GetNamespace().GetValue(), release.GetGroup().GetValue(), release.GetFileName().GetValue())
+	file := m.files[key]
+	var version uint64 = 1
+	if old, ok := m.releases[key]; ok {
+		version = old.GetVersion().GetValue() + 1
+	}
+	content := file.GetContent().GetValue()
+	m.releases[key] = &api.ClientConfigFileInfo{
+		Content: utils.NewStringValue(content),
+		Md5:     utils.NewStringValue(utils2.CalMd5(content)),
+		Version: utils.NewUInt64Value(version),
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+func (m *mockConfigServer) DeleteConfigFile(ctx context.Context, namespace, group, name,
+	deleteBy string) *api.ConfigResponse {
+	key := fileKey(namespace, group, name)
+	delete(m.files, key)
+	delete(m.releases, key)
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+func (m *mockConfigServer) GetConfigFileForClient(ctx context.Context,
+	file *api.ClientConfigFileInfo) *api.ConfigClientResponse {
+	release, ok := m.releases[fileKey(file.GetNamespace().GetValue(), file.GetGroup().GetValue(),
+		file.GetFileName().GetValue())]
+	if !ok {
+		return api.NewConfigClientResponse(api.NotFoundResource, nil)
+	}
+	return utils2.GenConfigFileResponse(file.GetNamespace().GetValue(), file.GetGroup().GetValue(),
+		file.GetFileName().GetValue(), release.GetContent().GetValue(), release.GetMd5().GetValue(),
+		release.GetVersion().GetValue())
+}
+
+func (m *mockConfigServer) WatchConfigFiles(ctx context.Context,
+	request *api.ClientWatchConfigFileRequest) (config.WatchCallback, error) {
+	onWatch := m.onWatch
+	return func() *api.ConfigClientResponse {
+		if onWatch == nil {
+			time.Sleep(time.Minute)
+			return api.NewConfigClientResponse(api.DataNoChange, nil)
+		}
+		onWatch()
+		return api.NewConfigClientResponse(api.ExecuteSuccess, nil)
+	}, nil
+}
+
+func TestParseListeningConfigs(t *testing.T) {
+	keys, err := parseListeningConfigs("a.yaml\x02g1\x02md5\x01b.yaml\x02g2\x02\x02tenant\x01")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, &configKey{dataId: "a.yaml", group: "g1", md5: "md5"}, keys[0])
+	assert.Equal(t, &configKey{dataId: "b.yaml", group: "g2", tenant: "tenant"}, keys[1])
+
+	_, err = parseListeningConfigs("a.yaml\x02g1\x01")
+	assert.NotNil(t, err)
+	_, err = parseListeningConfigs("")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, url.QueryEscape("a.yaml\x02g1\x01b.yaml\x02g2\x02tenant\x01"), encodeChangedConfigs(keys))
+}
+
+func TestPublishAndGetConfig(t *testing.T) {
+	h := newTestServer()
+	configServer := newMockConfigServer()
+	h.configServer = configServer
+
+	form := url.Values{}
+	form.Set("dataId", "app.yaml")
+	form.Set("group", "g1")
+	form.Set("content", "a: 1")
+	form.Set("type", "yaml")
+	rsp := doRequest(t, h.GetConfigServer(), http.MethodPost, "/nacos/v1/cs/configs", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "true", rsp.Body.String())
+	file := configServer.files[fileKey(DefaultNamespace, "g1", "app.yaml")]
+	assert.NotNil(t, file)
+	assert.Equal(t, "yaml", file.GetFormat().GetValue())
+
+	form = url.Values{}
+	form.Set("dataId", "app.yaml")
+	form.Set("group", "g1")
+	form.Set("tenant", DefaultTenant)
+	rsp = doRequest(t, h.GetConfigServer(), http.MethodGet, "/nacos/v1/cs/configs", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "a: 1", rsp.Body.String())
+	assert.Equal(t, utils2.CalMd5("a: 1"), rsp.Header().Get(headerContentMD5))
+
+	rsp = doRequest(t, h.GetConfigServer(), http.MethodDelete, "/nacos/v1/cs/configs", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	rsp = doRequest(t, h.GetConfigServer(), http.MethodGet, "/nacos/v1/cs/configs", form)
+	assert.Equal(t, http.StatusNotFound, rsp.Code)
+}
+
+func TestListenConfigs(t *testing.T) {
+	h := newTestServer()
+	configServer := newMockConfigServer()
+	h.configServer = configServer
+	configServer.files[fileKey(DefaultNamespace, "g1", "app.yaml")] = &api.ConfigFile{
+		Content: utils.NewStringValue("a: 1"),
+	}
+	configServer.PublishConfigFile(context.Background(), &api.ConfigFileRelease{
+		Namespace: utils.NewStringValue(DefaultNamespace),
+		Group:     utils.NewStringValue("g1"),
+		FileName:  utils.NewStringValue("app.yaml"),
+	})
+	changedBody := url.QueryEscape("app.yaml\x02g1\x01")
+
+	// md5 不一致时立即返回
+	form := url.Values{}
+	form.Set(paramListeningConfigs, "app.yaml\x02g1\x02\x01")
+	rsp := doRequest(t, h.GetConfigServer(), http.MethodPost, "/nacos/v1/cs/configs/listener", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, changedBody, rsp.Body.String())
+
+	// md5 一致且没有超时时间时为短轮询
+	form.Set(paramListeningConfigs, "app.yaml\x02g1\x02"+utils2.CalMd5("a: 1")+"\x01")
+	rsp = doRequest(t, h.GetConfigServer(), http.MethodPost, "/nacos/v1/cs/configs/listener", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "", rsp.Body.String())
+
+	// 长轮询期间配置发布
+	container := h.GetConfigServer()
+	configServer.onWatch = func() {
+		configServer.files[fileKey(DefaultNamespace, "g1", "app.yaml")].Content = utils.NewStringValue("a: 2")
+		configServer.PublishConfigFile(context.Background(), &api.ConfigFileRelease{
+			Namespace: utils.NewStringValue(DefaultNamespace),
+			Group:     utils.NewStringValue("g1"),
+			FileName:  utils.NewStringValue("app.yaml"),
+		})
+	}
+	rsp = doListenRequest(container, form, "30000")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, changedBody, rsp.Body.String())
+
+	// 长轮询超时
+	configServer.onWatch = nil
+	form.Set(paramListeningConfigs, "app.yaml\x02g1\x02"+utils2.CalMd5("a: 2")+"\x01")
+	start := time.Now()
+	rsp = doListenRequest(container, form, "700")
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "", rsp.Body.String())
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+}
+
+func TestLongPullingTimeout(t *testing.T) {
+	assert.Equal(t, 29500*time.Millisecond, longPullingTimeout(newListenRequest(url.Values{}, "30000")))
+	assert.Equal(t, DefaultLongPullingTimeout, longPullingTimeout(newListenRequest(url.Values{}, "90000")))
+	assert.Equal(t, time.Duration(0), longPullingTimeout(newListenRequest(url.Values{}, "")))
+}
+
+func newListenRequest(form url.Values, timeout string) *restful.Request {
+	req := httptest.NewRequest(http.MethodPost, "/nacos/v1/cs/configs/listener", strings.NewReader(form.Encode()))
+	req.Header.Set(restful.HEADER_ContentType, mimeForm)
+	if len(timeout) > 0 {
+		req.Header.Set(headerLongPullingTimeout, timeout)
+	}
+	return restful.NewRequest(req)
+}
+
+func doListenRequest(ws *restful.WebService, form url.Values, timeout string) *httptest.ResponseRecorder {
+	container := restful.NewContainer()
+	container.Add(ws)
+	rsp := httptest.NewRecorder()
+	container.ServeHTTP(rsp, newListenRequest(form, timeout).Request)
+	return rsp
+}
@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+const (
+	MetadataRegisterFrom = "internal-register-from"
+	MetadataCluster      = "internal-nacos-cluster"
+	MetadataEphemeral    = "internal-nacos-ephemeral"
+
+	// GroupServiceSeparator nacos 分组与服务名之间的分隔符
+	GroupServiceSeparator = "@@"
+
+	// CodeOK nacos 心跳成功的返回码
+	CodeOK = 10200
+	// CodeResourceNotFound nacos 心跳时实例不存在的返回码，客户端收到后会重新注册
+	CodeResourceNotFound = 20404
+)
+
+// ServiceInfo nacos 实例列表查询的应答
+type ServiceInfo struct {
+	Name                     string      `json:"name"`
+	Dom                      string      `json:"dom"`
+	GroupName                string      `json:"groupName"`
+	Clusters                 string      `json:"clusters"`
+	CacheMillis              int64       `json:"cacheMillis"`
+	Hosts                    []*Instance `json:"hosts"`
+	LastRefTime              int64       `json:"lastRefTime"`
+	Checksum                 string      `json:"checksum"`
+	AllIPs                   bool        `json:"allIPs"`
+	ReachProtectionThreshold bool        `json:"reachProtectionThreshold"`
+	Valid                    bool        `json:"valid"`
+}
+
+// Instance nacos 实例
+type Instance struct {
+	InstanceId                string            `json:"instanceId"`
+	Ip                        string            `json:"ip"`
+	Port                      int               `json:"port"`
+	Weight                    float64           `json:"weight"`
+	Healthy                   bool              `json:"healthy"`
+	Enabled                   bool              `json:"enabled"`
+	Ephemeral                 bool              `json:"ephemeral"`
+	ClusterName               string            `json:"clusterName"`
+	ServiceName               string            `json:"serviceName"`
+	Metadata                  map[string]string `json:"metadata"`
+	InstanceHeartBeatInterval int64             `json:"instanceHeartBeatInterval"`
+	InstanceHeartBeatTimeOut  int64             `json:"instanceHeartBeatTimeOut"`
+	IpDeleteTimeout           int64             `json:"ipDeleteTimeout"`
+}
+
+// BeatInfo nacos 客户端上报的心跳信息
+type BeatInfo struct {
+	Ip          string            `json:"ip"`
+	Port        int               `json:"port"`
+	ServiceName string            `json:"serviceName"`
+	Cluster     string            `json:"cluster"`
+	Metadata    map[string]string `json:"metadata"`
+	Weight      float64           `json:"weight"`
+	Scheduled   bool              `json:"scheduled"`
+	Period      int64             `json:"period"`
+	Stopped     bool              `json:"stopped"`
+}
+
+// BeatResult nacos 心跳的应答
+type BeatResult struct {
+	ClientBeatInterval int64 `json:"clientBeatInterval"`
+	Code               int   `json:"code"`
+	LightBeatEnabled   bool  `json:"lightBeatEnabled"`
+}
@@ -0,0 +1,367 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	// nacos 权重为浮点数，默认为 1，北极星权重为整数，默认为 100
+	weightRatio   = 100
+	maxWeight     = 10000
+	defaultWeight = 1.0
+)
+
+// parseServiceName 解析 nacos 服务名，客户端可能会将分组编码在服务名中：group@@service
+func parseServiceName(serviceName, groupName string) (string, string) {
+	if idx := strings.Index(serviceName, GroupServiceSeparator); idx >= 0 {
+		groupName = serviceName[:idx]
+		serviceName = serviceName[idx+len(GroupServiceSeparator):]
+	}
+	if len(groupName) == 0 {
+		groupName = DefaultGroup
+	}
+	return groupName, serviceName
+}
+
+// groupedServiceName 构建带分组的 nacos 服务名
+func groupedServiceName(groupName, serviceName string) string {
+	return groupName + GroupServiceSeparator + serviceName
+}
+
+// toNamespace nacos 的分组映射为北极星的命名空间，DEFAULT_GROUP 映射为配置的默认命名空间
+func (h *NacosServer) toNamespace(groupName string) string {
+	if len(groupName) == 0 || groupName == DefaultGroup {
+		return h.namespace
+	}
+	return groupName
+}
+
+// toPolarisWeight nacos 权重转换为北极星权重
+func toPolarisWeight(weight float64) uint32 {
+	if weight <= 0 {
+		return 0
+	}
+	return uint32(math.Min(math.Round(weight*weightRatio), maxWeight))
+}
+
+// toNacosWeight 北极星权重转换为 nacos 权重
+func toNacosWeight(weight uint32) float64 {
+	return float64(weight) / weightRatio
+}
+
+func parseBool(value string, defaultValue bool) bool {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	ret, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return ret
+}
+
+// buildBaseInstance 根据请求参数构建只包含四元组的北极星实例
+func (h *NacosServer) buildBaseInstance(req *restful.Request) (*api.Instance, error) {
+	groupName, serviceName := parseServiceName(formValue(req, "serviceName"), formValue(req, "groupName"))
+	if len(serviceName) == 0 {
+		return nil, fmt.Errorf("param 'serviceName' is required")
+	}
+	ip := formValue(req, "ip")
+	if len(ip) == 0 {
+		return nil, fmt.Errorf("param 'ip' is required")
+	}
+	port, err := strconv.ParseUint(formValue(req, "port"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("param 'port' is invalid: %v", err)
+	}
+	return &api.Instance{
+		Service:   &wrappers.StringValue{Value: serviceName},
+		Namespace: &wrappers.StringValue{Value: h.toNamespace(groupName)},
+		Host:      &wrappers.StringValue{Value: ip},
+		Port:      &wrappers.UInt32Value{Value: uint32(port)},
+	}, nil
+}
+
+// buildInstance 根据注册请求参数构建北极星实例
+func (h *NacosServer) buildInstance(req *restful.Request) (*api.Instance, error) {
+	instance, err := h.buildBaseInstance(req)
+	if err != nil {
+		return nil, err
+	}
+	weight := defaultWeight
+	if value := formValue(req, "weight"); len(value) > 0 {
+		if weight, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("param 'weight' is invalid: %v", err)
+		}
+	}
+	metadata := make(map[string]string)
+	if value := formValue(req, "metadata"); len(value) > 0 {
+		if err := json.Unmarshal([]byte(value), &metadata); err != nil {
+			return nil, fmt.Errorf("param 'metadata' is invalid: %v", err)
+		}
+	}
+	clusterName := formValue(req, "clusterName")
+	if len(clusterName) == 0 {
+		clusterName = DefaultCluster
+	}
+	ephemeral := parseBool(formValue(req, "ephemeral"), true)
+	metadata[MetadataRegisterFrom] = ServerNacos
+	metadata[MetadataCluster] = clusterName
+	metadata[MetadataEphemeral] = strconv.FormatBool(ephemeral)
+
+	instance.Weight = &wrappers.UInt32Value{Value: toPolarisWeight(weight)}
+	instance.Healthy = &wrappers.BoolValue{Value: parseBool(formValue(req, "healthy"), true)}
+	instance.Isolate = &wrappers.BoolValue{Value: !parseBool(formValue(req, "enabled"), true)}
+	instance.Metadata = metadata
+	// 临时实例由客户端心跳维持健康状态，持久化实例不开启健康检查
+	instance.EnableHealthCheck = &wrappers.BoolValue{Value: ephemeral}
+	if ephemeral {
+		instance.HealthCheck = &api.HealthCheck{
+			Type:      api.HealthCheck_HEARTBEAT,
+			Heartbeat: &api.HeartbeatHealthCheck{Ttl: &wrappers.UInt32Value{Value: DefaultHeartbeatTTL}},
+		}
+	}
+	return instance, nil
+}
+
+func (h *NacosServer) registerInstance(ctx context.Context, instance *api.Instance) uint32 {
+	resp := h.namingServer.RegisterInstance(ctx, instance)
+	code := resp.GetCode().GetValue()
+	if code != api.NotFoundResource {
+		return code
+	}
+	// 服务不存在，先创建服务再重试注册实例
+	svc := &api.Service{
+		Namespace: instance.GetNamespace(),
+		Name:      instance.GetService(),
+	}
+	svcResp := h.namingServer.CreateServices(ctx, []*api.Service{svc})
+	svcCreateCode := svcResp.GetCode().GetValue()
+	if svcCreateCode != api.ExecuteSuccess && svcCreateCode != api.ExistedResource {
+		return svcCreateCode
+	}
+	resp = h.namingServer.RegisterInstance(ctx, instance)
+	return resp.GetCode().GetValue()
+}
+
+// RegisterInstance 注册实例
+func (h *NacosServer) RegisterInstance(req *restful.Request, rsp *restful.Response) {
+	instance, err := h.buildInstance(req)
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	code := h.registerInstance(parseContext(req), instance)
+	if code == api.ExecuteSuccess || code == api.ExistedResource || code == api.SameInstanceRequest {
+		log.Infof("[NACOS-SERVER]instance (namespace=%s, service=%s, host=%s, port=%d) has been registered, code is %d",
+			instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+			instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+		writeText(req, rsp, code, http.StatusOK, "ok")
+		return
+	}
+	log.Errorf("[NACOS-SERVER]instance (namespace=%s, service=%s, host=%s, port=%d) register failed, code is %d",
+		instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+		instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+	writeError(req, rsp, code, "")
+}
+
+// DeregisterInstance 反注册实例
+func (h *NacosServer) DeregisterInstance(req *restful.Request, rsp *restful.Response) {
+	instance, err := h.buildBaseInstance(req)
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	resp := h.namingServer.DeregisterInstance(parseContext(req), instance)
+	code := resp.GetCode().GetValue()
+	// 实例已经不存在时，和 nacos 一样认为反注册成功
+	if code == api.ExecuteSuccess || code == api.NotFoundResource || code == api.NotFoundInstance {
+		log.Infof("[NACOS-SERVER]instance (namespace=%s, service=%s, host=%s, port=%d) has been deregistered, code is %d",
+			instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+			instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+		writeText(req, rsp, code, http.StatusOK, "ok")
+		return
+	}
+	log.Errorf("[NACOS-SERVER]instance (namespace=%s, service=%s, host=%s, port=%d) deregister failed, code is %d",
+		instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+		instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+	writeError(req, rsp, code, "")
+}
+
+// parseBeatInstance 解析心跳请求，心跳信息可能在 beat 参数中，也可能直接在请求参数中
+func (h *NacosServer) parseBeatInstance(req *restful.Request) (*api.Instance, error) {
+	beat := &BeatInfo{}
+	if value := formValue(req, "beat"); len(value) > 0 {
+		if err := json.Unmarshal([]byte(value), beat); err != nil {
+			return nil, fmt.Errorf("param 'beat' is invalid: %v", err)
+		}
+	}
+	if len(beat.ServiceName) == 0 {
+		beat.ServiceName = formValue(req, "serviceName")
+	}
+	if len(beat.Ip) == 0 {
+		beat.Ip = formValue(req, "ip")
+	}
+	if beat.Port == 0 {
+		port, err := strconv.Atoi(formValue(req, "port"))
+		if err != nil {
+			return nil, fmt.Errorf("param 'port' is invalid: %v", err)
+		}
+		beat.Port = port
+	}
+	groupName, serviceName := parseServiceName(beat.ServiceName, formValue(req, "groupName"))
+	if len(serviceName) == 0 || len(beat.Ip) == 0 {
+		return nil, fmt.Errorf("param 'serviceName' and 'ip' are required")
+	}
+	return &api.Instance{
+		Service:   &wrappers.StringValue{Value: serviceName},
+		Namespace: &wrappers.StringValue{Value: h.toNamespace(groupName)},
+		Host:      &wrappers.StringValue{Value: beat.Ip},
+		Port:      &wrappers.UInt32Value{Value: uint32(beat.Port)},
+	}, nil
+}
+
+// Beat 实例心跳
+func (h *NacosServer) Beat(req *restful.Request, rsp *restful.Response) {
+	instance, err := h.parseBeatInstance(req)
+	if err != nil {
+		writeError(req, rsp, api.InvalidParameter, err.Error())
+		return
+	}
+	resp := h.healthCheckServer.Report(parseContext(req), instance)
+	code := resp.GetCode().GetValue()
+	result := &BeatResult{ClientBeatInterval: DefaultHeartbeatInterval, Code: CodeOK}
+	switch code {
+	case api.ExecuteSuccess:
+	case api.NotFoundResource:
+		// 实例不存在，通知客户端重新注册
+		result.Code = CodeResourceNotFound
+	default:
+		log.Errorf("[NACOS-SERVER]instance (namespace=%s, service=%s, host=%s, port=%d) heartbeat failed, code is %d",
+			instance.GetNamespace().GetValue(), instance.GetService().GetValue(),
+			instance.GetHost().GetValue(), instance.GetPort().GetValue(), code)
+		writeError(req, rsp, code, "")
+		return
+	}
+	writeJSON(req, rsp, code, result)
+}
+
+// ListInstances 查询服务的实例列表
+func (h *NacosServer) ListInstances(req *restful.Request, rsp *restful.Response) {
+	groupName, serviceName := parseServiceName(formValue(req, "serviceName"), formValue(req, "groupName"))
+	if len(serviceName) == 0 {
+		writeError(req, rsp, api.InvalidServiceName, "param 'serviceName' is required")
+		return
+	}
+	clusters := formValue(req, "clusters")
+	healthyOnly := parseBool(formValue(req, "healthyOnly"), false)
+
+	namespace := h.toNamespace(groupName)
+	var instances []*model.Instance
+	svc := h.namingServer.Cache().Service().GetServiceByName(serviceName, namespace)
+	if svc != nil {
+		instances = h.namingServer.Cache().Instance().GetInstancesByServiceID(svc.ID)
+	}
+	info := h.buildServiceInfo(groupName, serviceName, clusters, healthyOnly, instances)
+	if svc != nil {
+		info.Checksum = svc.Revision
+	}
+	writeJSON(req, rsp, api.ExecuteSuccess, info)
+}
+
+// buildServiceInfo 将北极星的实例转换为 nacos 的实例列表，隔离的实例对应 nacos 中 enabled=false 的实例，不返回
+func (h *NacosServer) buildServiceInfo(groupName, serviceName, clusters string, healthyOnly bool,
+	instances []*model.Instance) *ServiceInfo {
+	clusterSet := make(map[string]struct{})
+	for _, cluster := range strings.Split(clusters, ",") {
+		if cluster = strings.TrimSpace(cluster); len(cluster) > 0 {
+			clusterSet[cluster] = struct{}{}
+		}
+	}
+	name := groupedServiceName(groupName, serviceName)
+	info := &ServiceInfo{
+		Name:        name,
+		Dom:         name,
+		GroupName:   groupName,
+		Clusters:    clusters,
+		CacheMillis: DefaultCacheMillis,
+		Hosts:       make([]*Instance, 0, len(instances)),
+		LastRefTime: time.Now().UnixNano() / int64(time.Millisecond),
+		Valid:       true,
+	}
+	for _, instance := range instances {
+		if instance.Isolate() || (healthyOnly && !instance.Healthy()) {
+			continue
+		}
+		host := toNacosInstance(name, instance)
+		if _, ok := clusterSet[host.ClusterName]; len(clusterSet) > 0 && !ok {
+			continue
+		}
+		info.Hosts = append(info.Hosts, host)
+	}
+	return info
+}
+
+// toNacosInstance 北极星实例转换为 nacos 实例
+func toNacosInstance(serviceName string, instance *model.Instance) *Instance {
+	clusterName := DefaultCluster
+	ephemeral := true
+	metadata := make(map[string]string, len(instance.Metadata()))
+	for k, v := range instance.Metadata() {
+		switch k {
+		case MetadataCluster:
+			clusterName = v
+		case MetadataEphemeral:
+			ephemeral = parseBool(v, true)
+		case MetadataRegisterFrom:
+		default:
+			metadata[k] = v
+		}
+	}
+	return &Instance{
+		InstanceId:  fmt.Sprintf("%s#%d#%s#%s", instance.Host(), instance.Port(), clusterName, serviceName),
+		Ip:          instance.Host(),
+		Port:        int(instance.Port()),
+		Weight:      toNacosWeight(instance.Weight()),
+		Healthy:     instance.Healthy(),
+		Enabled:     !instance.Isolate(),
+		Ephemeral:   ephemeral,
+		ClusterName: clusterName,
+		ServiceName: serviceName,
+		Metadata:    metadata,
+
+		InstanceHeartBeatInterval: DefaultHeartbeatInterval,
+		InstanceHeartBeatTimeOut:  DefaultHeartbeatTimeout,
+		IpDeleteTimeout:           DefaultIPDeleteTimeout,
+	}
+}
@@ -0,0 +1,220 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/service"
+)
+
+type mockNamingServer struct {
+	service.DiscoverServer
+	services  map[string]bool
+	instances map[string]*api.Instance
+}
+
+func newMockNamingServer() *mockNamingServer {
+	return &mockNamingServer{services: map[string]bool{}, instances: map[string]*api.Instance{}}
+}
+
+func (m *mockNamingServer) CreateServices(ctx context.Context, req []*api.Service) *api.BatchWriteResponse {
+	for _, svc := range req {
+		m.services[svc.GetNamespace().GetValue()+"/"+svc.GetName().GetValue()] = true
+	}
+	return api.NewBatchWriteResponse(api.ExecuteSuccess)
+}
+
+func (m *mockNamingServer) RegisterInstance(ctx context.Context, req *api.Instance) *api.Response {
+	if !m.services[req.GetNamespace().GetValue()+"/"+req.GetService().GetValue()] {
+		return api.NewInstanceResponse(api.NotFoundResource, req)
+	}
+	id, _ := utils.CheckInstanceTetrad(req)
+	m.instances[id] = req
+	return api.NewInstanceResponse(api.ExecuteSuccess, req)
+}
+
+func (m *mockNamingServer) DeregisterInstance(ctx context.Context, req *api.Instance) *api.Response {
+	id, _ := utils.CheckInstanceTetrad(req)
+	if _, ok := m.instances[id]; !ok {
+		return api.NewInstanceResponse(api.NotFoundResource, req)
+	}
+	delete(m.instances, id)
+	return api.NewInstanceResponse(api.ExecuteSuccess, req)
+}
+
+func newTestServer() *NacosServer {
+	return &NacosServer{namespace: DefaultNamespace}
+}
+
+func doRequest(t *testing.T, ws *restful.WebService, method, path string, form url.Values) *httptest.ResponseRecorder {
+	container := restful.NewContainer()
+	container.Add(ws)
+	var req *http.Request
+	if method == http.MethodGet || method == http.MethodDelete {
+		req = httptest.NewRequest(method, path+"?"+form.Encode(), nil)
+	} else {
+		req = httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		req.Header.Set(restful.HEADER_ContentType, mimeForm+charsetUTF8)
+	}
+	rsp := httptest.NewRecorder()
+	container.ServeHTTP(rsp, req)
+	return rsp
+}
+
+func TestParseServiceName(t *testing.T) {
+	group, svc := parseServiceName("g1@@svc", "")
+	assert.Equal(t, "g1", group)
+	assert.Equal(t, "svc", svc)
+
+	group, svc = parseServiceName("svc", "")
+	assert.Equal(t, DefaultGroup, group)
+	assert.Equal(t, "svc", svc)
+
+	group, svc = parseServiceName("svc", "g2")
+	assert.Equal(t, "g2", group)
+	assert.Equal(t, "svc", svc)
+}
+
+func TestWeightConvert(t *testing.T) {
+	assert.Equal(t, uint32(100), toPolarisWeight(1))
+	assert.Equal(t, uint32(150), toPolarisWeight(1.5))
+	assert.Equal(t, uint32(0), toPolarisWeight(-1))
+	assert.Equal(t, uint32(maxWeight), toPolarisWeight(1000))
+	assert.Equal(t, 1.5, toNacosWeight(150))
+}
+
+func TestRegisterAndDeregisterInstance(t *testing.T) {
+	h := newTestServer()
+	naming := newMockNamingServer()
+	h.namingServer = naming
+
+	form := url.Values{}
+	form.Set("serviceName", "g1@@svc")
+	form.Set("ip", "127.0.0.1")
+	form.Set("port", "8080")
+	form.Set("weight", "2")
+	form.Set("enabled", "false")
+	form.Set("clusterName", "c1")
+	form.Set("metadata", `{"k":"v"}`)
+	rsp := doRequest(t, h.GetNamingServer(), http.MethodPost, "/nacos/v1/ns/instance", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, "ok", rsp.Body.String())
+	assert.True(t, naming.services["g1/svc"])
+	assert.Equal(t, 1, len(naming.instances))
+	for _, instance := range naming.instances {
+		assert.Equal(t, "g1", instance.GetNamespace().GetValue())
+		assert.Equal(t, uint32(200), instance.GetWeight().GetValue())
+		assert.True(t, instance.GetIsolate().GetValue())
+		assert.True(t, instance.GetEnableHealthCheck().GetValue())
+		assert.Equal(t, "c1", instance.GetMetadata()[MetadataCluster])
+		assert.Equal(t, "v", instance.GetMetadata()["k"])
+	}
+
+	form = url.Values{}
+	form.Set("serviceName", "svc")
+	form.Set("groupName", "g1")
+	form.Set("ip", "127.0.0.1")
+	form.Set("port", "8080")
+	rsp = doRequest(t, h.GetNamingServer(), http.MethodDelete, "/nacos/v1/ns/instance", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+	assert.Equal(t, 0, len(naming.instances))
+
+	// 实例不存在时反注册也返回成功
+	rsp = doRequest(t, h.GetNamingServer(), http.MethodDelete, "/nacos/v1/ns/instance", form)
+	assert.Equal(t, http.StatusOK, rsp.Code)
+}
+
+func TestRegisterInstanceInvalidParam(t *testing.T) {
+	h := newTestServer()
+	h.namingServer = newMockNamingServer()
+
+	form := url.Values{}
+	form.Set("serviceName", "svc")
+	form.Set("ip", "127.0.0.1")
+	form.Set("port", "abc")
+	rsp := doRequest(t, h.GetNamingServer(), http.MethodPost, "/nacos/v1/ns/instance", form)
+	assert.Equal(t, http.StatusBadRequest, rsp.Code)
+}
+
+func TestParseBeatInstance(t *testing.T) {
+	h := newTestServer()
+	form := url.Values{}
+	form.Set("serviceName", "DEFAULT_GROUP@@svc")
+	form.Set("beat", `{"ip":"127.0.0.1","port":8080,"serviceName":"DEFAULT_GROUP@@svc","cluster":"DEFAULT"}`)
+	httpReq := httptest.NewRequest(http.MethodPut, "/nacos/v1/ns/instance/beat?"+form.Encode(), nil)
+	instance, err := h.parseBeatInstance(restful.NewRequest(httpReq))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultNamespace, instance.GetNamespace().GetValue())
+	assert.Equal(t, "svc", instance.GetService().GetValue())
+	assert.Equal(t, "127.0.0.1", instance.GetHost().GetValue())
+	assert.Equal(t, uint32(8080), instance.GetPort().GetValue())
+}
+
+func TestBuildServiceInfo(t *testing.T) {
+	h := newTestServer()
+	newInstance := func(host string, healthy, isolate bool, cluster string) *model.Instance {
+		return &model.Instance{Proto: &api.Instance{
+			Host:    utils.NewStringValue(host),
+			Port:    utils.NewUInt32Value(8080),
+			Weight:  utils.NewUInt32Value(100),
+			Healthy: utils.NewBoolValue(healthy),
+			Isolate: utils.NewBoolValue(isolate),
+			Metadata: map[string]string{
+				MetadataRegisterFrom: ServerNacos,
+				MetadataCluster:      cluster,
+				"k":                  "v",
+			},
+		}}
+	}
+	instances := []*model.Instance{
+		newInstance("127.0.0.1", true, false, "c1"),
+		newInstance("127.0.0.2", false, false, "c1"),
+		newInstance("127.0.0.3", true, true, "c1"),
+		newInstance("127.0.0.4", true, false, "c2"),
+	}
+
+	info := h.buildServiceInfo(DefaultGroup, "svc", "", false, instances)
+	assert.Equal(t, "DEFAULT_GROUP@@svc", info.Name)
+	assert.Equal(t, 3, len(info.Hosts))
+
+	info = h.buildServiceInfo(DefaultGroup, "svc", "c1", true, instances)
+	assert.Equal(t, 1, len(info.Hosts))
+	host := info.Hosts[0]
+	assert.Equal(t, "127.0.0.1", host.Ip)
+	assert.Equal(t, 1.0, host.Weight)
+	assert.Equal(t, "c1", host.ClusterName)
+	assert.True(t, host.Ephemeral)
+	assert.Equal(t, map[string]string{"k": "v"}, host.Metadata)
+
+	data, err := json.Marshal(info)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"hosts":[{"instanceId":"127.0.0.1#8080#c1#DEFAULT_GROUP@@svc"`)
+}
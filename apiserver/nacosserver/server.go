@@ -0,0 +1,304 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nacosserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/apiserver"
+	"github.com/polarismesh/polaris/common/connlimit"
+	"github.com/polarismesh/polaris/common/secure"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/service"
+	"github.com/polarismesh/polaris/service/healthcheck"
+)
+
+const (
+	ServerNacos = "nacos"
+
+	statusCodeHeader = utils.PolarisCode
+)
+
+// NacosServer 兼容 nacos v1 open api 的服务端
+type NacosServer struct {
+	server            *http.Server
+	namingServer      service.DiscoverServer
+	healthCheckServer *healthcheck.Server
+	configServer      config.ConfigCenterServer
+	connLimitConfig   *connlimit.Config
+	tlsInfo           *secure.TLSInfo
+	option            map[string]interface{}
+	openAPI           map[string]apiserver.APIConfig
+	listenPort        uint32
+	listenIP          string
+	exitCh            chan struct{}
+	start             bool
+	restart           bool
+	statis            plugin.Statis
+	namespace         string
+}
+
+// GetPort 获取端口
+func (h *NacosServer) GetPort() uint32 {
+	return h.listenPort
+}
+
+// GetProtocol 获取协议
+func (h *NacosServer) GetProtocol() string {
+	return ServerNacos
+}
+
+// Initialize 初始化 nacos API 服务器
+func (h *NacosServer) Initialize(ctx context.Context, option map[string]interface{},
+	api map[string]apiserver.APIConfig) error {
+	h.listenIP = option[optionListenIP].(string)
+	h.listenPort = uint32(option[optionListenPort].(int))
+	h.option = option
+	h.openAPI = api
+
+	var namespace = DefaultNamespace
+	if namespaceValue, ok := option[optionNamespace]; ok {
+		theNamespace := namespaceValue.(string)
+		if len(theNamespace) > 0 {
+			namespace = theNamespace
+		}
+	}
+	h.namespace = namespace
+
+	// 连接数限制的配置
+	if raw, _ := option[optionConnLimit].(map[interface{}]interface{}); raw != nil {
+		connLimitConfig, err := connlimit.ParseConnLimitConfig(raw)
+		if err != nil {
+			return err
+		}
+		h.connLimitConfig = connLimitConfig
+	}
+	if raw, _ := option[optionTLS].(map[interface{}]interface{}); raw != nil {
+		tlsConfig, err := secure.ParseTLSConfig(raw)
+		if err != nil {
+			return err
+		}
+		h.tlsInfo = &secure.TLSInfo{
+			CertFile:      tlsConfig.CertFile,
+			KeyFile:       tlsConfig.KeyFile,
+			TrustedCAFile: tlsConfig.TrustedCAFile,
+		}
+	}
+	return nil
+}
+
+// Run 启动 nacos API 服务器
+func (h *NacosServer) Run(errCh chan error) {
+	log.Infof("start nacosserver")
+	h.exitCh = make(chan struct{})
+	h.start = true
+	defer func() {
+		close(h.exitCh)
+		h.start = false
+	}()
+	var err error
+	// 引入功能模块和插件
+	h.namingServer, err = service.GetServer()
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+	h.healthCheckServer, err = healthcheck.GetServer()
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+	h.configServer, err = config.GetServer()
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+	h.statis = plugin.GetStatis()
+	// 初始化http server
+	address := fmt.Sprintf("%v:%v", h.listenIP, h.listenPort)
+
+	wsContainer := h.createRestfulContainer()
+	server := http.Server{Addr: address, Handler: wsContainer, WriteTimeout: 2 * time.Minute}
+
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("net listen(%s) err: %s", address, err.Error())
+		errCh <- err
+		return
+	}
+	ln = &tcpKeepAliveListener{ln.(*net.TCPListener)}
+	// 开启最大连接数限制
+	if h.connLimitConfig != nil && h.connLimitConfig.OpenConnLimit {
+		log.Infof("http server use max connection limit per ip: %d, http max limit: %d",
+			h.connLimitConfig.MaxConnPerHost, h.connLimitConfig.MaxConnLimit)
+		ln, err = connlimit.NewListener(ln, h.GetProtocol(), h.connLimitConfig)
+		if err != nil {
+			log.Errorf("conn limit init err: %s", err.Error())
+			errCh <- err
+			return
+		}
+	}
+	h.server = &server
+
+	// 开始对外服务
+	if h.tlsInfo.IsEmpty() {
+		err = server.Serve(ln)
+	} else {
+		err = server.ServeTLS(ln, h.tlsInfo.CertFile, h.tlsInfo.KeyFile)
+	}
+	if err != nil {
+		log.Errorf("%+v", err)
+		if !h.restart {
+			log.Infof("not in restart progress, broadcast error")
+			errCh <- err
+		}
+		return
+	}
+	log.Infof("nacosserver stop")
+}
+
+// 创建handler
+func (h *NacosServer) createRestfulContainer() *restful.Container {
+	wsContainer := restful.NewContainer()
+	wsContainer.Filter(h.process)
+	wsContainer.Add(h.GetNamingServer())
+	wsContainer.Add(h.GetConfigServer())
+	return wsContainer
+}
+
+// process 在接收和回复时统一处理请求
+func (h *NacosServer) process(req *restful.Request, rsp *restful.Response, chain *restful.FilterChain) {
+	h.preprocess(req)
+	chain.ProcessFilter(req, rsp)
+	h.postprocess(req, rsp)
+}
+
+// preprocess 请求预处理
+func (h *NacosServer) preprocess(req *restful.Request) {
+	// 设置开始时间
+	req.SetAttribute("start-time", time.Now())
+
+	if req.Request.Method != http.MethodGet && !strings.HasSuffix(req.Request.URL.Path, "/beat") &&
+		!strings.HasSuffix(req.Request.URL.Path, "/listener") {
+		// 打印请求
+		log.Info("receive request",
+			zap.String("client-address", req.Request.RemoteAddr),
+			zap.String("user-agent", req.HeaderParameter("User-Agent")),
+			zap.String("method", req.Request.Method),
+			zap.String("url", req.Request.URL.String()),
+		)
+	}
+}
+
+// postprocess 请求后处理：统计
+func (h *NacosServer) postprocess(req *restful.Request, rsp *restful.Response) {
+	path := req.Request.URL.Path
+	if path != "/" {
+		// 去掉最后一个"/"
+		path = strings.TrimSuffix(path, "/")
+	}
+	startTime := req.Attribute("start-time").(time.Time)
+	diff := time.Since(startTime)
+
+	code, ok := req.Attribute(statusCodeHeader).(uint32)
+	if !ok {
+		code = uint32(rsp.StatusCode())
+	}
+	if h.statis != nil {
+		_ = h.statis.AddAPICall(req.Request.Method+":"+path, "HTTP", int(code), diff.Nanoseconds())
+	}
+}
+
+// Stop 结束 nacosserver 的运行
+func (h *NacosServer) Stop() {
+	// 释放connLimit的数据，如果没有开启，也需要执行一下
+	// 目的：防止restart的时候，connLimit冲突
+	connlimit.RemoveLimitListener(h.GetProtocol())
+	if h.server != nil {
+		_ = h.server.Close()
+	}
+}
+
+// Restart 重启 nacosserver
+func (h *NacosServer) Restart(
+	option map[string]interface{}, api map[string]apiserver.APIConfig, errCh chan error) error {
+	log.Infof("restart nacosserver new config: %+v", option)
+	// 备份一下option
+	backupOption := h.option
+	// 备份一下api
+	backupAPI := h.openAPI
+
+	// 设置restart标记，防止stop的时候把错误抛出
+	h.restart = true
+	h.Stop()
+	if h.start {
+		<-h.exitCh
+	}
+
+	log.Infof("old nacosserver has stopped, begin restart nacosserver")
+
+	if err := h.Initialize(context.Background(), option, api); err != nil {
+		h.restart = false
+		if initErr := h.Initialize(context.Background(), backupOption, backupAPI); initErr != nil {
+			log.Errorf("start nacosserver with backup cfg err: %s", initErr.Error())
+			return initErr
+		}
+		go h.Run(errCh)
+
+		log.Errorf("restart nacosserver initialize err: %s", err.Error())
+		return err
+	}
+
+	log.Infof("init nacosserver successfully, restart it")
+	h.restart = false
+	go h.Run(errCh)
+	return nil
+}
+
+// tcpKeepAliveListener sets TCP keep-alive timeouts on accepted
+// connections. It's used by ListenAndServe and ListenAndServeTLS so
+// dead TCP connections (e.g. closing laptop mid-download) eventually
+// go away.
+// 来自net/http
+type tcpKeepAliveListener struct {
+	*net.TCPListener
+}
+
+// Accept 来自于net/http
+func (ln tcpKeepAliveListener) Accept() (net.Conn, error) {
+	tc, err := ln.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	_ = tc.SetKeepAlive(true)
+	_ = tc.SetKeepAlivePeriod(3 * time.Minute)
+	return tc, nil
+}
@@ -0,0 +1,342 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	ratelimitconf "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	ratelimitfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	upstreams_http "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	v32 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	// RateLimitClusterName envoy 访问北极星全局限流服务的 cluster
+	RateLimitClusterName = "polaris-ratelimit"
+	// RateLimitDomain 全局限流的 domain
+	RateLimitDomain = "polaris"
+	// descriptorKeyService 限流描述符中标识服务 ID 的 key
+	descriptorKeyService = "polaris_service_id"
+	// descriptorKeyRule 限流描述符中标识限流规则 ID 的 key
+	descriptorKeyRule = "polaris_ratelimit_rule"
+	// defaultRateLimitHost 默认通过 k8s 中北极星的 service 访问限流服务
+	defaultRateLimitHost = "polaris.polaris-system"
+	// headerPath http path 对应的 envoy 伪请求头
+	headerPath = ":path"
+)
+
+// RateLimitConfig 全局限流服务配置
+type RateLimitConfig struct {
+	// Enable 是否开启全局限流服务
+	Enable bool `mapstructure:"enable"`
+	// Host envoy 访问北极星全局限流服务的地址
+	Host string `mapstructure:"host"`
+	// Port envoy 访问北极星全局限流服务的端口，默认为 xds 服务的端口
+	Port uint32 `mapstructure:"port"`
+	// Timeout envoy 调用限流服务的超时时间
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Redis 限流计数器存放在 redis 中，由北极星集群内的所有节点共享
+	Redis map[string]interface{} `mapstructure:"redis"`
+	// Standalone 北极星单节点部署，未配置 redis 时允许使用本节点内存计数
+	Standalone bool `mapstructure:"standalone"`
+}
+
+// ParseRateLimitConfig 解析全局限流服务配置
+func ParseRateLimitConfig(raw map[interface{}]interface{}, listenPort uint32) (*RateLimitConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	config := &RateLimitConfig{}
+	decodeConfig := &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     config,
+	}
+	decoder, err := mapstructure.NewDecoder(decodeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return nil, err
+	}
+
+	if config.Host == "" {
+		config.Host = defaultRateLimitHost
+	}
+	if config.Port == 0 {
+		config.Port = listenPort
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 100 * time.Millisecond
+	}
+	return config, nil
+}
+
+func (x *XDSServer) globalRateLimitEnabled() bool {
+	return x.rateLimitConfig != nil && x.rateLimitConfig.Enable
+}
+
+// makeHTTPFilters 生成 envoy http connection manager 的 http filter，router 必须位于最后
+func (x *XDSServer) makeHTTPFilters() []*hcm.HttpFilter {
+	if !x.globalRateLimitEnabled() {
+		return nil
+	}
+
+	rateLimit := &ratelimitfilter.RateLimit{
+		Domain: RateLimitDomain,
+		// 限流服务不可用时放通请求
+		FailureModeDeny: false,
+		Timeout:         durationpb.New(x.rateLimitConfig.Timeout),
+		RateLimitService: &ratelimitconf.RateLimitServiceConfig{
+			GrpcService: &core.GrpcService{
+				TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &core.GrpcService_EnvoyGrpc{
+						ClusterName: RateLimitClusterName,
+					},
+				},
+			},
+			TransportApiVersion: core.ApiVersion_V3,
+		},
+	}
+	return []*hcm.HttpFilter{
+		{
+			Name: "envoy.filters.http.ratelimit",
+			ConfigType: &hcm.HttpFilter_TypedConfig{
+				TypedConfig: mustNewAny(rateLimit),
+			},
+		},
+	}
+}
+
+// makeRateLimitCluster 生成访问北极星全局限流服务的 cluster
+func makeRateLimitCluster(conf *RateLimitConfig) *cluster.Cluster {
+	return &cluster.Cluster{
+		Name:                 RateLimitClusterName,
+		ConnectTimeout:       durationpb.New(5 * time.Second),
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_STRICT_DNS},
+		TypedExtensionProtocolOptions: map[string]*anypb.Any{
+			"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": mustNewAny(&upstreams_http.HttpProtocolOptions{
+				UpstreamProtocolOptions: &upstreams_http.HttpProtocolOptions_ExplicitHttpConfig_{
+					ExplicitHttpConfig: &upstreams_http.HttpProtocolOptions_ExplicitHttpConfig{
+						ProtocolConfig: &upstreams_http.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+							Http2ProtocolOptions: &core.Http2ProtocolOptions{},
+						},
+					},
+				},
+			}),
+		},
+		LoadAssignment: &endpoint.ClusterLoadAssignment{
+			ClusterName: RateLimitClusterName,
+			Endpoints: []*endpoint.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*endpoint.LbEndpoint{
+						{
+							HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+								Endpoint: &endpoint.Endpoint{
+									Address: &core.Address{
+										Address: &core.Address_SocketAddress{
+											SocketAddress: &core.SocketAddress{
+												Protocol: core.SocketAddress_TCP,
+												Address:  conf.Host,
+												PortSpecifier: &core.SocketAddress_PortValue{
+													PortValue: conf.Port,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// makeGlobalRateLimits 将服务的全局限流规则翻译为 virtual host 上的 rate_limits，
+// 描述符中携带服务 ID 以及规则 ID，由北极星的限流服务根据规则的配额进行计数
+func (x *XDSServer) makeGlobalRateLimits(serviceID string, conf []*model.RateLimit) []*route.RateLimit {
+	if !x.globalRateLimitEnabled() {
+		return nil
+	}
+
+	var rateLimits []*route.RateLimit
+	for _, c := range conf {
+		rule := parseRateLimitRule(c)
+		if rule == nil || rule.GetType() != api.Rule_GLOBAL || rule.GetDisable().GetValue() ||
+			len(rule.GetAmounts()) == 0 {
+			continue
+		}
+
+		actions := []*route.RateLimit_Action{
+			{
+				ActionSpecifier: &route.RateLimit_Action_GenericKey_{
+					GenericKey: &route.RateLimit_Action_GenericKey{
+						DescriptorKey:   descriptorKeyService,
+						DescriptorValue: serviceID,
+					},
+				},
+			},
+			{
+				ActionSpecifier: &route.RateLimit_Action_GenericKey_{
+					GenericKey: &route.RateLimit_Action_GenericKey{
+						DescriptorKey:   descriptorKeyRule,
+						DescriptorValue: c.ID,
+					},
+				},
+			},
+		}
+		matchActions, ok := makeRateLimitMatchActions(rule)
+		if !ok {
+			log.Warnf("global ratelimit rule %s contains labels that envoy can not match, skip it", c.ID)
+			continue
+		}
+		rateLimits = append(rateLimits, &route.RateLimit{
+			Actions: append(actions, matchActions...),
+		})
+	}
+	return rateLimits
+}
+
+// makeRateLimitMatchActions 规则中的接口以及标签转为 header_value_match，只有全部匹配时 envoy 才会生成描述符
+func makeRateLimitMatchActions(rule *api.Rule) ([]*route.RateLimit_Action, bool) {
+	var actions []*route.RateLimit_Action
+
+	if rule.GetMethod().GetValue().GetValue() != "" {
+		method := rule.GetMethod()
+		value := method.GetValue().GetValue()
+		var pattern string
+		switch method.GetType() {
+		case api.MatchString_EXACT, api.MatchString_NOT_EQUALS:
+			pattern = regexp.QuoteMeta(value)
+		case api.MatchString_REGEX:
+			pattern = "(?:" + value + ")"
+		default:
+			pattern = inRegex(value)
+		}
+		invert := method.GetType() == api.MatchString_NOT_EQUALS || method.GetType() == api.MatchString_NOT_IN
+		// :path 中包含 query 参数，匹配时需要忽略
+		stringMatcher := &v32.StringMatcher{MatchPattern: &v32.StringMatcher_SafeRegex{
+			SafeRegex: &v32.RegexMatcher{Regex: pattern + `(\?.*)?`}}}
+		actions = append(actions, headerValueMatchAction(headerPath, value, headerPath, stringMatcher, invert))
+	}
+
+	// 保证生成的 xds 资源稳定，避免无意义的推送
+	keys := make([]string, 0, len(rule.GetLabels()))
+	for key := range rule.GetLabels() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := rule.GetLabels()[key]
+		if value.GetValueType() != api.MatchString_TEXT {
+			return nil, false
+		}
+		var header string
+		switch {
+		case key == model.LabelKeyMethod:
+			header = headerMethod
+		case strings.HasPrefix(key, model.LabelKeyHeader+"."):
+			header = strings.TrimPrefix(key, model.LabelKeyHeader+".")
+		case strings.HasPrefix(key, "$"):
+			// query、主调服务、主调 IP 等标签 envoy 无法匹配
+			return nil, false
+		default:
+			// 自定义标签通过同名的请求头传递
+			header = key
+		}
+		action, ok := makeHeaderValueMatch(key, header,
+			apiv2.MatchString_MatchStringType(value.GetType()), value.GetValue().GetValue())
+		if !ok {
+			return nil, false
+		}
+		actions = append(actions, action)
+	}
+	return actions, true
+}
+
+func makeHeaderValueMatch(descriptor, header string, matchType apiv2.MatchString_MatchStringType,
+	value string) (*route.RateLimit_Action, bool) {
+	if header == "" {
+		return nil, false
+	}
+	stringMatcher, invert, ok := makeStringMatcher(matchType, value)
+	if !ok {
+		return nil, false
+	}
+	return headerValueMatchAction(descriptor, value, header, stringMatcher, invert), true
+}
+
+func headerValueMatchAction(descriptorKey, descriptorValue, header string, stringMatcher *v32.StringMatcher,
+	invert bool) *route.RateLimit_Action {
+	return &route.RateLimit_Action{
+		ActionSpecifier: &route.RateLimit_Action_HeaderValueMatch_{
+			HeaderValueMatch: &route.RateLimit_Action_HeaderValueMatch{
+				DescriptorKey:   descriptorKey,
+				DescriptorValue: descriptorValue,
+				ExpectMatch:     &wrappers.BoolValue{Value: !invert},
+				Headers: []*route.HeaderMatcher{
+					{
+						Name:                 header,
+						HeaderMatchSpecifier: &route.HeaderMatcher_StringMatch{StringMatch: stringMatcher},
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseRateLimitRule 获取限流规则的 proto 结构，缓存中的规则已经完成了解析
+func parseRateLimitRule(c *model.RateLimit) *api.Rule {
+	if c.Proto != nil {
+		return c.Proto
+	}
+	if c.Rule == "" {
+		return nil
+	}
+	rule := &api.Rule{}
+	if err := json.Unmarshal([]byte(c.Rule), rule); err != nil {
+		log.Errorf("unmarshal global rate limit rule error, %v", err)
+		return nil
+	}
+	if len(rule.Labels) == 0 && c.Labels != "" {
+		if err := json.Unmarshal([]byte(c.Labels), &rule.Labels); err != nil {
+			log.Errorf("unmarshal global rate limit labels error, %v", err)
+		}
+	}
+	return rule
+}
@@ -28,7 +28,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 )
 
-func makeListeners() []types.Resource {
+func makeListeners(httpFilters []*hcm.HttpFilter) []types.Resource {
 	manager := &hcm.HttpConnectionManager{
 		CodecType:  hcm.HttpConnectionManager_AUTO,
 		StatPrefix: "http",
@@ -43,9 +43,9 @@ func makeListeners() []types.Resource {
 				RouteConfigName: "polaris-router",
 			},
 		},
-		HttpFilters: []*hcm.HttpFilter{{
+		HttpFilters: append(httpFilters, &hcm.HttpFilter{
 			Name: wellknown.Router,
-		}},
+		}),
 	}
 
 	pbst, err := ptypes.MarshalAny(manager)
@@ -111,14 +111,14 @@ func makeListeners() []types.Resource {
 	}
 }
 
-func makePermissiveListeners() []types.Resource {
-	resources := makeListeners()
+func makePermissiveListeners(httpFilters []*hcm.HttpFilter) []types.Resource {
+	resources := makeListeners(httpFilters)
 	resources = append(resources, inboundListener())
 	return resources
 }
 
-func makeStrictListeners() []types.Resource {
-	resources := makeListeners()
+func makeStrictListeners(httpFilters []*hcm.HttpFilter) []types.Resource {
+	resources := makeListeners(httpFilters)
 	resources = append(resources, inboundStrictListener())
 	return resources
 }
@@ -0,0 +1,181 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/stream/v3"
+	"google.golang.org/protobuf/proto"
+
+	commonlog "github.com/polarismesh/polaris/common/log"
+)
+
+const (
+	// snapshotCacheKey LDS/RDS 仍然以命名空间快照的方式下发
+	snapshotCacheKey = "snapshot"
+	// resourceCacheKey CDS/EDS 按照单个资源的粒度下发
+	resourceCacheKey = "resource"
+)
+
+// perResourceTypes 按照单个资源维护版本的 xds 资源类型
+var perResourceTypes = []resource.Type{resource.ClusterType, resource.EndpointType}
+
+// isPerResourceType 判断 xds 资源类型是否按照单个资源维护版本
+func isPerResourceType(typeURL string) bool {
+	for _, t := range perResourceTypes {
+		if t == typeURL {
+			return true
+		}
+	}
+	return false
+}
+
+// newMuxCache CDS/EDS 的请求交给 ResourceCache 处理，其余的请求交给命名空间级别的 SnapshotCache 处理
+func newMuxCache(snapshotCache cachev3.SnapshotCache, resourceCache *ResourceCache) *cachev3.MuxCache {
+	return &cachev3.MuxCache{
+		Classify: func(req *cachev3.Request) string {
+			if isPerResourceType(req.GetTypeUrl()) {
+				return resourceCacheKey
+			}
+			return snapshotCacheKey
+		},
+		ClassifyDelta: func(req *cachev3.DeltaRequest) string {
+			if isPerResourceType(req.GetTypeUrl()) {
+				return resourceCacheKey
+			}
+			return snapshotCacheKey
+		},
+		Caches: map[string]cachev3.Cache{
+			snapshotCacheKey: snapshotCache,
+			resourceCacheKey: resourceCache,
+		},
+	}
+}
+
+// ResourceCache 为每一组 envoy 节点以及每一种资源类型维护一个 LinearCache，
+// 每个 cluster、endpoint 都有自己独立的版本，只有发生变化的资源才会推送给 envoy，
+// 同时支持 envoy 按需订阅（on-demand CDS/EDS）部分资源
+type ResourceCache struct {
+	hash cachev3.NodeHash
+	log  *commonlog.Scope
+
+	lock sync.RWMutex
+	// caches node group => type url => LinearCache
+	caches map[string]map[string]*cachev3.LinearCache
+}
+
+var _ cachev3.Cache = &ResourceCache{}
+
+// NewResourceCache 创建 ResourceCache，hash 决定 envoy 节点所属的分组
+func NewResourceCache(hash cachev3.NodeHash, log *commonlog.Scope) *ResourceCache {
+	return &ResourceCache{
+		hash:   hash,
+		log:    log,
+		caches: map[string]map[string]*cachev3.LinearCache{},
+	}
+}
+
+// getOrCreate 获取节点分组下某个资源类型的 LinearCache，不存在时创建
+func (rc *ResourceCache) getOrCreate(group, typeURL string) *cachev3.LinearCache {
+	rc.lock.RLock()
+	linear, ok := rc.caches[group][typeURL]
+	rc.lock.RUnlock()
+	if ok {
+		return linear
+	}
+
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	if _, ok := rc.caches[group]; !ok {
+		rc.caches[group] = map[string]*cachev3.LinearCache{}
+	}
+	if linear, ok = rc.caches[group][typeURL]; !ok {
+		linear = cachev3.NewLinearCache(typeURL)
+		rc.caches[group][typeURL] = linear
+	}
+	return linear
+}
+
+// SetResources 全量设置某个节点分组下某种类型的资源，只有内容发生变化或者被删除的资源才会更新版本并通知 envoy
+func (rc *ResourceCache) SetResources(group, typeURL string, resources []types.Resource) error {
+	linear := rc.getOrCreate(group, typeURL)
+	olds := linear.GetResources()
+
+	toUpdate := map[string]types.Resource{}
+	names := make(map[string]struct{}, len(resources))
+	for _, item := range resources {
+		name := cachev3.GetResourceName(item)
+		names[name] = struct{}{}
+		if old, ok := olds[name]; ok && proto.Equal(old, item) {
+			continue
+		}
+		toUpdate[name] = item
+	}
+	var toDelete []string
+	for name := range olds {
+		if _, ok := names[name]; !ok {
+			toDelete = append(toDelete, name)
+		}
+	}
+
+	if len(toUpdate) == 0 && len(toDelete) == 0 {
+		return nil
+	}
+	if rc.log != nil && rc.log.DebugEnabled() {
+		rc.log.Debugf("node group %s type %s update %d resources, delete %v",
+			group, typeURL, len(toUpdate), toDelete)
+	}
+	return linear.UpdateResources(toUpdate, toDelete)
+}
+
+// GetResources 获取某个节点分组下某种类型的资源
+func (rc *ResourceCache) GetResources(group, typeURL string) map[string]types.Resource {
+	rc.lock.RLock()
+	linear, ok := rc.caches[group][typeURL]
+	rc.lock.RUnlock()
+	if !ok {
+		return map[string]types.Resource{}
+	}
+	return linear.GetResources()
+}
+
+// CreateWatch 处理全量协议（SotW）的请求
+func (rc *ResourceCache) CreateWatch(request *cachev3.Request, state stream.StreamState,
+	value chan cachev3.Response) func() {
+	group := rc.hash.ID(request.GetNode())
+	return rc.getOrCreate(group, request.GetTypeUrl()).CreateWatch(request, state, value)
+}
+
+// CreateDeltaWatch 处理增量协议（Delta）的请求
+func (rc *ResourceCache) CreateDeltaWatch(request *cachev3.DeltaRequest, state stream.StreamState,
+	value chan cachev3.DeltaResponse) func() {
+	group := rc.hash.ID(request.GetNode())
+	return rc.getOrCreate(group, request.GetTypeUrl()).CreateDeltaWatch(request, state, value)
+}
+
+// Fetch 不支持 REST 方式获取资源
+func (rc *ResourceCache) Fetch(ctx context.Context, request *cachev3.Request) (cachev3.Response, error) {
+	return nil, errors.New("not implemented")
+}
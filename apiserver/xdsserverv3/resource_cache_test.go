@@ -0,0 +1,187 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/stream/v3"
+)
+
+func newTestCLA(name string, ports ...uint32) *endpoint.ClusterLoadAssignment {
+	var lbEndpoints []*endpoint.LbEndpoint
+	for _, port := range ports {
+		lbEndpoints = append(lbEndpoints, &endpoint.LbEndpoint{
+			HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+				Endpoint: &endpoint.Endpoint{
+					Address: &core.Address{
+						Address: &core.Address_SocketAddress{
+							SocketAddress: &core.SocketAddress{
+								Address:       "127.0.0.1",
+								PortSpecifier: &core.SocketAddress_PortValue{PortValue: port},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	return &endpoint.ClusterLoadAssignment{
+		ClusterName: name,
+		Endpoints:   []*endpoint.LocalityLbEndpoints{{LbEndpoints: lbEndpoints}},
+	}
+}
+
+var testNode = &core.Node{Id: "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1"}
+
+func TestResourceCacheSotW(t *testing.T) {
+	rc := NewResourceCache(PolarisNodeHash{}, nil)
+	err := rc.SetResources("default", resource.EndpointType, []types.Resource{
+		newTestCLA("a", 8080), newTestCLA("b", 8080),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 首次请求，返回订阅的资源
+	value := make(chan cachev3.Response, 1)
+	rc.CreateWatch(&cachev3.Request{
+		Node:          testNode,
+		TypeUrl:       resource.EndpointType,
+		ResourceNames: []string{"a", "b"},
+	}, stream.NewStreamState(false, nil), value)
+	resp := <-value
+	if len(resp.(*cachev3.RawResponse).Resources) != 2 {
+		t.Fatalf("expect 2 resources, got %v", resp)
+	}
+	version, _ := resp.GetVersion()
+
+	// 资源没有变化，不会触发推送
+	value = make(chan cachev3.Response, 1)
+	rc.CreateWatch(&cachev3.Request{
+		Node:          testNode,
+		TypeUrl:       resource.EndpointType,
+		ResourceNames: []string{"a", "b"},
+		VersionInfo:   version,
+	}, stream.NewStreamState(false, nil), value)
+	err = rc.SetResources("default", resource.EndpointType, []types.Resource{
+		newTestCLA("a", 8080), newTestCLA("b", 8080),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case resp := <-value:
+		t.Fatalf("unexpect response %v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// 只推送发生变化的资源
+	err = rc.SetResources("default", resource.EndpointType, []types.Resource{
+		newTestCLA("a", 8080), newTestCLA("b", 8080, 8081),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = <-value
+	resources := resp.(*cachev3.RawResponse).Resources
+	if len(resources) != 1 || cachev3.GetResourceName(resources[0].Resource) != "b" {
+		t.Fatalf("expect only b changed, got %v", resources)
+	}
+
+	// 其他节点分组互不影响
+	if len(rc.GetResources("default/strict", resource.EndpointType)) != 0 {
+		t.Fatal("node groups should be isolated")
+	}
+}
+
+func TestResourceCacheDelta(t *testing.T) {
+	rc := NewResourceCache(PolarisNodeHash{}, nil)
+	err := rc.SetResources("default", resource.EndpointType, []types.Resource{
+		newTestCLA("a", 8080), newTestCLA("b", 8080),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 按需订阅 a
+	state := stream.NewStreamState(false, nil)
+	state.GetResourceVersions()["a"] = ""
+	value := make(chan cachev3.DeltaResponse, 1)
+	rc.CreateDeltaWatch(&cachev3.DeltaRequest{
+		Node:                   testNode,
+		TypeUrl:                resource.EndpointType,
+		ResourceNamesSubscribe: []string{"a"},
+	}, state, value)
+	resp := (<-value).(*cachev3.RawDeltaResponse)
+	if len(resp.Resources) != 1 || cachev3.GetResourceName(resp.Resources[0]) != "a" {
+		t.Fatalf("expect only a, got %v", resp.Resources)
+	}
+	state.SetResourceVersions(resp.GetNextVersionMap())
+
+	// b 发生变化，不会推送给只订阅了 a 的 envoy
+	value = make(chan cachev3.DeltaResponse, 1)
+	rc.CreateDeltaWatch(&cachev3.DeltaRequest{
+		Node:    testNode,
+		TypeUrl: resource.EndpointType,
+	}, state, value)
+	err = rc.SetResources("default", resource.EndpointType, []types.Resource{
+		newTestCLA("a", 8080), newTestCLA("b", 8081),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case resp := <-value:
+		t.Fatalf("unexpect response %v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// a 被删除
+	err = rc.SetResources("default", resource.EndpointType, []types.Resource{newTestCLA("b", 8081)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = (<-value).(*cachev3.RawDeltaResponse)
+	if len(resp.RemovedResources) != 1 || resp.RemovedResources[0] != "a" {
+		t.Fatalf("expect a removed, got %v", resp.RemovedResources)
+	}
+}
+
+func TestMuxCacheClassify(t *testing.T) {
+	mux := newMuxCache(cachev3.NewSnapshotCache(false, PolarisNodeHash{}, nil),
+		NewResourceCache(PolarisNodeHash{}, nil))
+	if key := mux.Classify(&cachev3.Request{TypeUrl: resource.ClusterType}); key != resourceCacheKey {
+		t.Fatalf("unexpect cache %s", key)
+	}
+	if key := mux.ClassifyDelta(&cachev3.DeltaRequest{TypeUrl: resource.EndpointType}); key != resourceCacheKey {
+		t.Fatalf("unexpect cache %s", key)
+	}
+	if key := mux.Classify(&cachev3.Request{TypeUrl: resource.ListenerType}); key != snapshotCacheKey {
+		t.Fatalf("unexpect cache %s", key)
+	}
+	if key := mux.ClassifyDelta(&cachev3.DeltaRequest{TypeUrl: resource.RouteType}); key != snapshotCacheKey {
+		t.Fatalf("unexpect cache %s", key)
+	}
+}
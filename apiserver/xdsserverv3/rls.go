@@ -0,0 +1,297 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	envoy_extensions_common_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	rls "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/redispool"
+)
+
+// QuotaCounter 全局限流的计数器
+type QuotaCounter interface {
+	// Incr 在 key 当前所处的时间窗口内累加 hits，返回累加后的计数以及当前窗口的剩余时间
+	Incr(ctx context.Context, key string, hits uint32, window time.Duration) (uint64, time.Duration, error)
+}
+
+// windowStart 时间窗口按照窗口长度对齐，保证北极星集群内各个节点的窗口一致
+func windowStart(now time.Time, window time.Duration) time.Time {
+	return now.Truncate(window)
+}
+
+// memoryCounter 计数器存放在本节点内存中，适用于单节点部署
+type memoryCounter struct {
+	lock      sync.Mutex
+	counters  map[string]*windowCounter
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+type windowCounter struct {
+	start  time.Time
+	expire time.Time
+	count  uint64
+}
+
+func newMemoryCounter() *memoryCounter {
+	return &memoryCounter{
+		counters:  map[string]*windowCounter{},
+		lastPurge: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Incr 累加计数
+func (m *memoryCounter) Incr(_ context.Context, key string, hits uint32,
+	window time.Duration) (uint64, time.Duration, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+	m.purge(now)
+
+	start := windowStart(now, window)
+	counter, ok := m.counters[key]
+	if !ok || !counter.start.Equal(start) {
+		counter = &windowCounter{start: start, expire: start.Add(window)}
+		m.counters[key] = counter
+	}
+	counter.count += uint64(hits)
+	return counter.count, counter.expire.Sub(now), nil
+}
+
+// purge 定期清理已经过期的计数器
+func (m *memoryCounter) purge(now time.Time) {
+	if now.Sub(m.lastPurge) < time.Minute {
+		return
+	}
+	m.lastPurge = now
+	for key, counter := range m.counters {
+		if !now.Before(counter.expire) {
+			delete(m.counters, key)
+		}
+	}
+}
+
+// redisCounter 计数器存放在 redis 中，北极星集群内的所有节点共享同一份计数
+type redisCounter struct {
+	client redis.UniversalClient
+	now    func() time.Time
+}
+
+func newRedisCounter(client redis.UniversalClient) *redisCounter {
+	return &redisCounter{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Incr 累加计数
+func (r *redisCounter) Incr(ctx context.Context, key string, hits uint32,
+	window time.Duration) (uint64, time.Duration, error) {
+	now := r.now()
+	start := windowStart(now, window)
+	redisKey := fmt.Sprintf("polaris:ratelimit:%s:%d", key, start.Unix())
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, redisKey, int64(hits))
+	pipe.Expire(ctx, redisKey, window+time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+	return uint64(incr.Val()), start.Add(window).Sub(now), nil
+}
+
+// RateLimitServer envoy 全局限流服务（envoy.service.ratelimit.v3）的实现，
+// 根据描述符中的服务 ID 以及规则 ID 找到对应的限流规则，按照规则的配额进行计数
+type RateLimitServer struct {
+	rateLimitGetter RatelimitConfigGetter
+	counter         QuotaCounter
+}
+
+// NewRateLimitServer 创建全局限流服务
+func NewRateLimitServer(rateLimitGetter RatelimitConfigGetter, counter QuotaCounter) *RateLimitServer {
+	return &RateLimitServer{
+		rateLimitGetter: rateLimitGetter,
+		counter:         counter,
+	}
+}
+
+// ShouldRateLimit 判断请求是否需要被限流
+func (s *RateLimitServer) ShouldRateLimit(ctx context.Context,
+	req *rls.RateLimitRequest) (*rls.RateLimitResponse, error) {
+	hits := req.GetHitsAddend()
+	if hits == 0 {
+		hits = 1
+	}
+
+	resp := &rls.RateLimitResponse{
+		OverallCode: rls.RateLimitResponse_OK,
+		Statuses:    make([]*rls.RateLimitResponse_DescriptorStatus, 0, len(req.GetDescriptors())),
+	}
+	for _, descriptor := range req.GetDescriptors() {
+		status := s.checkDescriptor(ctx, req.GetDomain(), descriptor.GetEntries(), hits)
+		if status.GetCode() == rls.RateLimitResponse_OVER_LIMIT {
+			resp.OverallCode = rls.RateLimitResponse_OVER_LIMIT
+		}
+		resp.Statuses = append(resp.Statuses, status)
+	}
+	return resp, nil
+}
+
+func (s *RateLimitServer) checkDescriptor(ctx context.Context, domain string,
+	entries []*envoy_extensions_common_ratelimit_v3.RateLimitDescriptor_Entry, hits uint32) *rls.RateLimitResponse_DescriptorStatus {
+	ok := &rls.RateLimitResponse_DescriptorStatus{Code: rls.RateLimitResponse_OK}
+
+	var (
+		serviceID string
+		ruleID    string
+		labels    []string
+	)
+	for _, entry := range entries {
+		switch entry.GetKey() {
+		case descriptorKeyService:
+			serviceID = entry.GetValue()
+		case descriptorKeyRule:
+			ruleID = entry.GetValue()
+		default:
+			labels = append(labels, entry.GetKey()+"="+entry.GetValue())
+		}
+	}
+	if domain != RateLimitDomain || serviceID == "" || ruleID == "" {
+		return ok
+	}
+
+	rule := s.findRule(serviceID, ruleID)
+	if rule == nil || rule.GetType() != api.Rule_GLOBAL || rule.GetDisable().GetValue() {
+		return ok
+	}
+
+	status := ok
+	for i, amount := range rule.GetAmounts() {
+		window := amount.GetValidDuration().AsDuration()
+		if window <= 0 {
+			continue
+		}
+		maxAmount := uint64(amount.GetMaxAmount().GetValue())
+		key := fmt.Sprintf("%s|%d|%s", ruleID, i, strings.Join(labels, ","))
+		count, remain, err := s.counter.Incr(ctx, key, hits, window)
+		if err != nil {
+			// 计数器不可用时放通请求
+			log.Errorf("[RLS] incr quota counter for rule %s error %v", ruleID, err)
+			continue
+		}
+
+		current := &rls.RateLimitResponse_DescriptorStatus{
+			Code:               rls.RateLimitResponse_OK,
+			CurrentLimit:       makeRateLimitUnit(maxAmount, window),
+			DurationUntilReset: durationpb.New(remain),
+		}
+		if count > maxAmount {
+			current.Code = rls.RateLimitResponse_OVER_LIMIT
+		} else {
+			current.LimitRemaining = uint32(maxAmount - count)
+		}
+		// 多个配额同时生效时，返回最先达到限制的那一个
+		if status.GetCode() == rls.RateLimitResponse_OK &&
+			(current.GetCode() == rls.RateLimitResponse_OVER_LIMIT ||
+				status.GetCurrentLimit() == nil || current.GetLimitRemaining() < status.GetLimitRemaining()) {
+			status = current
+		}
+	}
+	return status
+}
+
+func (s *RateLimitServer) findRule(serviceID, ruleID string) *api.Rule {
+	for _, c := range s.rateLimitGetter(serviceID) {
+		if c.ID == ruleID {
+			return parseRateLimitRule(c)
+		}
+	}
+	return nil
+}
+
+// makeRateLimitUnit envoy 只支持秒、分、时、天四种单位，其余的时间窗口按照秒换算
+func makeRateLimitUnit(maxAmount uint64, window time.Duration) *rls.RateLimitResponse_RateLimit {
+	units := []struct {
+		unit     rls.RateLimitResponse_RateLimit_Unit
+		duration time.Duration
+	}{
+		{unit: rls.RateLimitResponse_RateLimit_DAY, duration: 24 * time.Hour},
+		{unit: rls.RateLimitResponse_RateLimit_HOUR, duration: time.Hour},
+		{unit: rls.RateLimitResponse_RateLimit_MINUTE, duration: time.Minute},
+	}
+	for _, item := range units {
+		if window == item.duration {
+			return &rls.RateLimitResponse_RateLimit{RequestsPerUnit: uint32(maxAmount), Unit: item.unit}
+		}
+	}
+	seconds := uint64(window / time.Second)
+	if seconds == 0 {
+		seconds = 1
+	}
+	return &rls.RateLimitResponse_RateLimit{
+		RequestsPerUnit: uint32(maxAmount / seconds),
+		Unit:            rls.RateLimitResponse_RateLimit_SECOND,
+	}
+}
+
+var _ rls.RateLimitServiceServer = (*RateLimitServer)(nil)
+
+// newQuotaCounter 根据配置创建限流计数器
+func newQuotaCounter(conf *RateLimitConfig) (QuotaCounter, error) {
+	if len(conf.Redis) == 0 {
+		// 内存计数只在本节点生效，多节点部署时实际的限流阈值会随节点数成倍放大
+		if !conf.Standalone {
+			return nil, errors.New("global rate limit requires a shared redis counter, " +
+				"set rateLimit.standalone to true to use memory counter on single node")
+		}
+		log.Warnf("global rate limit uses memory counter, quota is NOT shared between polaris nodes")
+		return newMemoryCounter(), nil
+	}
+	redisConf, err := parseRedisConfig(conf.Redis)
+	if err != nil {
+		return nil, err
+	}
+	return newRedisCounter(redispool.NewRedisClient(redisConf)), nil
+}
+
+// parseRedisConfig 复用 redispool 的配置格式
+func parseRedisConfig(raw map[string]interface{}) (*redispool.Config, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	config := redispool.DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,213 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	envoy_extensions_common_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	rls "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func newGlobalRateLimit(id string, maxAmount uint32, window time.Duration) *model.RateLimit {
+	return &model.RateLimit{
+		ID:        id,
+		ServiceID: "svc-1",
+		Proto: &api.Rule{
+			Id:   &wrappers.StringValue{Value: id},
+			Type: api.Rule_GLOBAL,
+			Method: &api.MatchString{
+				Type:  api.MatchString_EXACT,
+				Value: &wrappers.StringValue{Value: "/echo"},
+			},
+			Labels: map[string]*api.MatchString{
+				model.LabelKeyHeader + ".uid": {
+					Type:  api.MatchString_IN,
+					Value: &wrappers.StringValue{Value: "1,2"},
+				},
+			},
+			Amounts: []*api.Amount{
+				{
+					MaxAmount:     &wrappers.UInt32Value{Value: maxAmount},
+					ValidDuration: &duration.Duration{Seconds: int64(window / time.Second)},
+				},
+			},
+			Disable: &wrappers.BoolValue{Value: false},
+		},
+	}
+}
+
+func TestMemoryCounter(t *testing.T) {
+	counter := newMemoryCounter()
+	now := time.Unix(1000, 0)
+	counter.now = func() time.Time { return now }
+	counter.lastPurge = now
+
+	count, remain, _ := counter.Incr(context.Background(), "k", 1, 10*time.Second)
+	if count != 1 || remain != 10*time.Second {
+		t.Fatalf("unexpect count %d remain %v", count, remain)
+	}
+	now = now.Add(5 * time.Second)
+	count, remain, _ = counter.Incr(context.Background(), "k", 2, 10*time.Second)
+	if count != 3 || remain != 5*time.Second {
+		t.Fatalf("unexpect count %d remain %v", count, remain)
+	}
+
+	// 进入下一个时间窗口后重新计数
+	now = now.Add(5 * time.Second)
+	count, _, _ = counter.Incr(context.Background(), "k", 1, 10*time.Second)
+	if count != 1 {
+		t.Fatalf("unexpect count %d", count)
+	}
+
+	// 过期的计数器会被清理
+	now = now.Add(2 * time.Minute)
+	counter.Incr(context.Background(), "other", 1, time.Second)
+	if _, ok := counter.counters["k"]; ok {
+		t.Fatal("expired counter should be purged")
+	}
+}
+
+func TestNewQuotaCounter(t *testing.T) {
+	// 多节点时内存计数无法共享，必须配置 redis
+	conf, _ := ParseRateLimitConfig(map[interface{}]interface{}{"enable": true}, 15010)
+	if _, err := newQuotaCounter(conf); err == nil {
+		t.Fatal("memory counter should be rejected without standalone")
+	}
+
+	conf, _ = ParseRateLimitConfig(map[interface{}]interface{}{"enable": true, "standalone": true}, 15010)
+	counter, err := newQuotaCounter(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := counter.(*memoryCounter); !ok {
+		t.Fatalf("expect memory counter, got %T", counter)
+	}
+}
+
+func TestRateLimitServer(t *testing.T) {
+	rules := []*model.RateLimit{newGlobalRateLimit("rule-1", 2, time.Second)}
+	server := NewRateLimitServer(func(serviceID string) []*model.RateLimit {
+		if serviceID == "svc-1" {
+			return rules
+		}
+		return nil
+	}, newMemoryCounter())
+
+	newRequest := func(ruleID string) *rls.RateLimitRequest {
+		return &rls.RateLimitRequest{
+			Domain: RateLimitDomain,
+			Descriptors: []*envoy_extensions_common_ratelimit_v3.RateLimitDescriptor{
+				{
+					Entries: []*envoy_extensions_common_ratelimit_v3.RateLimitDescriptor_Entry{
+						{Key: descriptorKeyService, Value: "svc-1"},
+						{Key: descriptorKeyRule, Value: ruleID},
+						{Key: model.LabelKeyHeader + ".uid", Value: "1,2"},
+					},
+				},
+			},
+		}
+	}
+
+	// 同一个窗口内超过 2 次的请求被限流，为了避免跨窗口，最多尝试 3 轮
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		codes := make([]rls.RateLimitResponse_Code, 0, 3)
+		for j := 0; j < 3; j++ {
+			resp, err := server.ShouldRateLimit(context.Background(), newRequest("rule-1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			codes = append(codes, resp.GetOverallCode())
+		}
+		if windowStart(start, time.Second) != windowStart(time.Now(), time.Second) {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		if codes[0] != rls.RateLimitResponse_OK || codes[1] != rls.RateLimitResponse_OK ||
+			codes[2] != rls.RateLimitResponse_OVER_LIMIT {
+			t.Fatalf("unexpect codes %v", codes)
+		}
+		break
+	}
+
+	// 未知的规则直接放通
+	resp, _ := server.ShouldRateLimit(context.Background(), newRequest("unknown"))
+	if resp.GetOverallCode() != rls.RateLimitResponse_OK {
+		t.Fatalf("unknown rule should pass, got %v", resp.GetOverallCode())
+	}
+
+	// 停用的规则直接放通
+	rules[0].Proto.Disable = &wrappers.BoolValue{Value: true}
+	resp, _ = server.ShouldRateLimit(context.Background(), newRequest("rule-1"))
+	if resp.GetOverallCode() != rls.RateLimitResponse_OK {
+		t.Fatalf("disabled rule should pass, got %v", resp.GetOverallCode())
+	}
+}
+
+func TestMakeGlobalRateLimits(t *testing.T) {
+	x := &XDSServer{}
+	conf := []*model.RateLimit{newGlobalRateLimit("rule-1", 10, time.Second)}
+	if rateLimits := x.makeGlobalRateLimits("svc-1", conf); rateLimits != nil {
+		t.Fatal("global rate limit is disabled")
+	}
+
+	x.rateLimitConfig, _ = ParseRateLimitConfig(map[interface{}]interface{}{"enable": true}, 15010)
+	if x.rateLimitConfig.Host != defaultRateLimitHost || x.rateLimitConfig.Port != 15010 {
+		t.Fatalf("unexpect config %+v", x.rateLimitConfig)
+	}
+
+	local := newGlobalRateLimit("rule-2", 10, time.Second)
+	local.Proto.Type = api.Rule_LOCAL
+	query := newGlobalRateLimit("rule-3", 10, time.Second)
+	query.Proto.Labels[model.LabelKeyQuery+".env"] = &api.MatchString{Value: &wrappers.StringValue{Value: "gray"}}
+	conf = append(conf, local, query)
+
+	rateLimits := x.makeGlobalRateLimits("svc-1", conf)
+	if len(rateLimits) != 1 {
+		t.Fatalf("expect only 1 global rate limit, got %d", len(rateLimits))
+	}
+	actions := rateLimits[0].GetActions()
+	if len(actions) != 4 {
+		t.Fatalf("expect 4 actions, got %v", actions)
+	}
+	if actions[0].GetGenericKey().GetDescriptorValue() != "svc-1" ||
+		actions[1].GetGenericKey().GetDescriptorValue() != "rule-1" {
+		t.Fatalf("unexpect generic keys %v", actions[:2])
+	}
+	path := actions[2].GetHeaderValueMatch()
+	if path.GetHeaders()[0].GetName() != headerPath ||
+		path.GetHeaders()[0].GetStringMatch().GetSafeRegex().GetRegex() != `/echo(\?.*)?` {
+		t.Fatalf("unexpect path match %v", path)
+	}
+	uid := actions[3].GetHeaderValueMatch()
+	if uid.GetHeaders()[0].GetName() != "uid" || !uid.GetExpectMatch().GetValue() {
+		t.Fatalf("unexpect header match %v", uid)
+	}
+
+	if len(x.makeHTTPFilters()) != 1 || makeRateLimitCluster(x.rateLimitConfig).GetName() != RateLimitClusterName {
+		t.Fatal("rate limit filter and cluster should be generated")
+	}
+}
@@ -0,0 +1,403 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	v32 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+	routingcommon "github.com/polarismesh/polaris/common/routing"
+)
+
+const (
+	// headerCookie cookie 所在的请求头
+	headerCookie = "cookie"
+	// headerMethod http method 对应的 envoy 伪请求头
+	headerMethod = ":method"
+)
+
+// getRuleRoutingV2 获取服务下生效的 v2 规则路由，按照优先级从高到低排序
+func (x *XDSServer) getRuleRoutingV2(svc *ServiceInfo) ([]*apiv2.RuleRoutingConfig, string, error) {
+	rules, err := x.namingServer.Cache().RoutingConfig().GetRoutingConfigV2(svc.ID, svc.Name, svc.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+
+	enables := make([]*apiv2.Routing, 0, len(rules))
+	for i := range rules {
+		if rules[i].GetEnable() && rules[i].GetRoutingPolicy() == apiv2.RoutingPolicy_RulePolicy {
+			enables = append(enables, rules[i])
+		}
+	}
+	// priority 越小，优先级越高；相同优先级时按照规则 ID 保证顺序稳定
+	sort.Slice(enables, func(i, j int) bool {
+		if enables[i].GetPriority() != enables[j].GetPriority() {
+			return enables[i].GetPriority() < enables[j].GetPriority()
+		}
+		return enables[i].GetId() < enables[j].GetId()
+	})
+
+	ret := make([]*apiv2.RuleRoutingConfig, 0, len(enables))
+	revisions := make([]string, 0, len(enables))
+	for i := range enables {
+		conf := &apiv2.RuleRoutingConfig{}
+		if err := ptypes.UnmarshalAny(enables[i].GetRoutingConfig(), conf); err != nil {
+			return nil, "", err
+		}
+		ret = append(ret, conf)
+		revisions = append(revisions, enables[i].GetId()+":"+enables[i].GetRevision())
+	}
+
+	return ret, strings.Join(revisions, ","), nil
+}
+
+// makeRoutesV2 将 v2 规则路由翻译为 envoy 的路由，每一个 source 生成一条路由
+func makeRoutesV2(serviceInfo *ServiceInfo) []*route.Route {
+	var routes []*route.Route
+
+	for _, rule := range serviceInfo.RoutingV2 {
+		weightedClusters, totalWeight := makeWeightedClustersV2(serviceInfo, rule)
+		if len(weightedClusters) == 0 {
+			continue
+		}
+
+		for _, source := range rule.GetSources() {
+			// envoy 的配置按照命名空间下发，主调方只会是当前命名空间下的服务
+			if !matchNamespace(source.GetNamespace(), serviceInfo.Namespace) {
+				continue
+			}
+			routeMatch, ok := makeRouteMatchV2(source.GetArguments())
+			if !ok {
+				continue
+			}
+
+			routes = append(routes, &route.Route{
+				Match: routeMatch,
+				Action: &route.Route_Route{
+					Route: &route.RouteAction{
+						ClusterSpecifier: &route.RouteAction_WeightedClusters{
+							WeightedClusters: &route.WeightedCluster{
+								TotalWeight: &wrappers.UInt32Value{Value: totalWeight},
+								Clusters:    weightedClusters,
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return routes
+}
+
+// makeSubsetSelectorsV2 为 v2 规则路由中的 destination 生成 subset
+func makeSubsetSelectorsV2(serviceInfo *ServiceInfo) []*cluster.Cluster_LbSubsetConfig_LbSubsetSelector {
+	var selectors []*cluster.Cluster_LbSubsetConfig_LbSubsetSelector
+	exists := map[string]struct{}{}
+
+	for _, rule := range serviceInfo.RoutingV2 {
+		for _, destination := range selectDestinationsV2(serviceInfo, rule) {
+			keys := make([]string, 0, len(destination.GetLabels()))
+			for k := range destination.GetLabels() {
+				keys = append(keys, k)
+			}
+			if len(keys) == 0 {
+				continue
+			}
+			sort.Strings(keys)
+			id := strings.Join(keys, ",")
+			if _, ok := exists[id]; ok {
+				continue
+			}
+			exists[id] = struct{}{}
+			selectors = append(selectors, &cluster.Cluster_LbSubsetConfig_LbSubsetSelector{
+				Keys:           keys,
+				FallbackPolicy: cluster.Cluster_LbSubsetConfig_LbSubsetSelector_NO_FALLBACK,
+			})
+		}
+	}
+
+	return selectors
+}
+
+// makeWeightedClustersV2 使用 destinations 生成 weightedClusters
+func makeWeightedClustersV2(serviceInfo *ServiceInfo,
+	rule *apiv2.RuleRoutingConfig) ([]*route.WeightedCluster_ClusterWeight, uint32) {
+
+	destinations := selectDestinationsV2(serviceInfo, rule)
+
+	// 没有设置权重时，认为各个实例分组的权重相同
+	noWeight := true
+	for _, destination := range destinations {
+		if destination.GetWeight() > 0 {
+			noWeight = false
+			break
+		}
+	}
+
+	var (
+		weightedClusters []*route.WeightedCluster_ClusterWeight
+		totalWeight      uint32
+	)
+	for _, destination := range destinations {
+		weight := destination.GetWeight()
+		if noWeight {
+			weight = 1
+		}
+		if weight == 0 {
+			continue
+		}
+
+		clusterWeight := &route.WeightedCluster_ClusterWeight{
+			Name:   serviceInfo.Name,
+			Weight: &wrappers.UInt32Value{Value: weight},
+		}
+		if len(destination.GetLabels()) > 0 {
+			fields := make(map[string]*_struct.Value)
+			for k, v := range destination.GetLabels() {
+				fields[k] = &_struct.Value{
+					Kind: &_struct.Value_StringValue{
+						StringValue: v.GetValue().GetValue(),
+					},
+				}
+			}
+			clusterWeight.MetadataMatch = &core.Metadata{
+				FilterMetadata: map[string]*_struct.Struct{
+					"envoy.lb": {
+						Fields: fields,
+					},
+				},
+			}
+		}
+		weightedClusters = append(weightedClusters, clusterWeight)
+		totalWeight += weight
+	}
+
+	return weightedClusters, totalWeight
+}
+
+// selectDestinationsV2 选出规则中当前服务可以翻译为 subset 的 destination
+// envoy 的 weightedClusters 无法表达按照 priority 的降级，这里只取优先级最高的一组
+func selectDestinationsV2(serviceInfo *ServiceInfo, rule *apiv2.RuleRoutingConfig) []*apiv2.Destination {
+	var (
+		ret      []*apiv2.Destination
+		priority uint32
+	)
+
+	for _, destination := range rule.GetDestinations() {
+		if destination.GetIsolate() {
+			continue
+		}
+		if !matchNamespace(destination.GetNamespace(), serviceInfo.Namespace) ||
+			!matchService(destination.GetService(), serviceInfo.Name) {
+			continue
+		}
+		// subset 只能做元数据的精确匹配
+		supported := true
+		for _, label := range destination.GetLabels() {
+			if label.GetType() != apiv2.MatchString_EXACT || label.GetValueType() != apiv2.MatchString_TEXT {
+				supported = false
+				break
+			}
+		}
+		if !supported {
+			continue
+		}
+
+		if len(ret) == 0 || destination.GetPriority() < priority {
+			ret = []*apiv2.Destination{destination}
+			priority = destination.GetPriority()
+			continue
+		}
+		if destination.GetPriority() == priority {
+			ret = append(ret, destination)
+		}
+	}
+
+	return ret
+}
+
+// makeRouteMatchV2 使用 source 的 arguments 生成 routeMatch，存在 envoy 无法表达的参数时返回 false
+func makeRouteMatchV2(arguments []*apiv2.SourceMatch) (*route.RouteMatch, bool) {
+	routeMatch := &route.RouteMatch{
+		PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/"},
+	}
+
+	for _, argument := range arguments {
+		matchString := argument.GetValue()
+		if matchString.GetValueType() != apiv2.MatchString_TEXT {
+			return nil, false
+		}
+
+		switch argument.GetType() {
+		case apiv2.SourceMatch_PATH:
+			value := matchString.GetValue().GetValue()
+			switch matchString.GetType() {
+			case apiv2.MatchString_EXACT:
+				routeMatch.PathSpecifier = &route.RouteMatch_Path{Path: value}
+			case apiv2.MatchString_REGEX:
+				routeMatch.PathSpecifier = &route.RouteMatch_SafeRegex{
+					SafeRegex: &v32.RegexMatcher{Regex: value}}
+			case apiv2.MatchString_IN:
+				routeMatch.PathSpecifier = &route.RouteMatch_SafeRegex{
+					SafeRegex: &v32.RegexMatcher{Regex: inRegex(value)}}
+			default:
+				return nil, false
+			}
+		case apiv2.SourceMatch_HEADER:
+			headerMatch, ok := makeHeaderMatcher(argument.GetKey(), matchString)
+			if !ok {
+				return nil, false
+			}
+			routeMatch.Headers = append(routeMatch.Headers, headerMatch)
+		case apiv2.SourceMatch_METHOD:
+			headerMatch, ok := makeHeaderMatcher(headerMethod, matchString)
+			if !ok {
+				return nil, false
+			}
+			routeMatch.Headers = append(routeMatch.Headers, headerMatch)
+		case apiv2.SourceMatch_CALLER_IP:
+			// 调用方 IP 只能通过 x-forwarded-for 等可被客户端伪造的请求头获取，不下发给 envoy
+			return nil, false
+		case apiv2.SourceMatch_COOKIE:
+			headerMatch, ok := makeCookieMatcher(argument.GetKey(), matchString)
+			if !ok {
+				return nil, false
+			}
+			routeMatch.Headers = append(routeMatch.Headers, headerMatch)
+		case apiv2.SourceMatch_QUERY:
+			stringMatcher, invert, ok := makeStringMatcher(matchString.GetType(), matchString.GetValue().GetValue())
+			// query 参数的匹配不支持取反
+			if !ok || invert {
+				return nil, false
+			}
+			routeMatch.QueryParameters = append(routeMatch.QueryParameters, &route.QueryParameterMatcher{
+				Name: argument.GetKey(),
+				QueryParameterMatchSpecifier: &route.QueryParameterMatcher_StringMatch{
+					StringMatch: stringMatcher,
+				},
+			})
+		default:
+			// 自定义参数只有 SDK 才能获取到，envoy 无法处理
+			return nil, false
+		}
+	}
+
+	return routeMatch, true
+}
+
+func makeHeaderMatcher(name string, matchString *apiv2.MatchString) (*route.HeaderMatcher, bool) {
+	if name == "" {
+		return nil, false
+	}
+	stringMatcher, invert, ok := makeStringMatcher(matchString.GetType(), matchString.GetValue().GetValue())
+	if !ok {
+		return nil, false
+	}
+	return &route.HeaderMatcher{
+		Name:                 name,
+		HeaderMatchSpecifier: &route.HeaderMatcher_StringMatch{StringMatch: stringMatcher},
+		InvertMatch:          invert,
+	}, true
+}
+
+// makeCookieMatcher envoy 不支持直接匹配 cookie，通过正则匹配 cookie 请求头实现
+func makeCookieMatcher(name string, matchString *apiv2.MatchString) (*route.HeaderMatcher, bool) {
+	if name == "" {
+		return nil, false
+	}
+	var (
+		value   = matchString.GetValue().GetValue()
+		pattern string
+		invert  bool
+	)
+	switch matchString.GetType() {
+	case apiv2.MatchString_EXACT:
+		pattern = regexp.QuoteMeta(value)
+	case apiv2.MatchString_NOT_EQUALS:
+		pattern = regexp.QuoteMeta(value)
+		invert = true
+	case apiv2.MatchString_REGEX:
+		pattern = "(?:" + strings.TrimSuffix(strings.TrimPrefix(value, "^"), "$") + ")"
+	case apiv2.MatchString_IN:
+		pattern = inRegex(value)
+	case apiv2.MatchString_NOT_IN:
+		pattern = inRegex(value)
+		invert = true
+	default:
+		return nil, false
+	}
+
+	regex := `(.*;\s*)?` + regexp.QuoteMeta(name) + `=` + pattern + `(;.*)?`
+	return &route.HeaderMatcher{
+		Name: headerCookie,
+		HeaderMatchSpecifier: &route.HeaderMatcher_StringMatch{
+			StringMatch: &v32.StringMatcher{MatchPattern: &v32.StringMatcher_SafeRegex{
+				SafeRegex: &v32.RegexMatcher{Regex: regex}}},
+		},
+		InvertMatch: invert,
+	}, true
+}
+
+// makeStringMatcher 将北极星的 MatchString 转为 envoy 的 StringMatcher，第二个返回值表示是否需要取反
+func makeStringMatcher(matchType apiv2.MatchString_MatchStringType, value string) (*v32.StringMatcher, bool, bool) {
+	switch matchType {
+	case apiv2.MatchString_EXACT:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_Exact{Exact: value}}, false, true
+	case apiv2.MatchString_NOT_EQUALS:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_Exact{Exact: value}}, true, true
+	case apiv2.MatchString_REGEX:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_SafeRegex{
+			SafeRegex: &v32.RegexMatcher{Regex: value}}}, false, true
+	case apiv2.MatchString_IN:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_SafeRegex{
+			SafeRegex: &v32.RegexMatcher{Regex: inRegex(value)}}}, false, true
+	case apiv2.MatchString_NOT_IN:
+		return &v32.StringMatcher{MatchPattern: &v32.StringMatcher_SafeRegex{
+			SafeRegex: &v32.RegexMatcher{Regex: inRegex(value)}}}, true, true
+	default:
+		return nil, false, false
+	}
+}
+
+// inRegex IN 类型的取值为逗号分隔的多个值，转为正则表达式
+func inRegex(value string) string {
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = regexp.QuoteMeta(strings.TrimSpace(values[i]))
+	}
+	return "(" + strings.Join(values, "|") + ")"
+}
+
+func matchNamespace(expect, namespace string) bool {
+	return expect == "" || expect == routingcommon.MatchAll || expect == namespace
+}
+
+func matchService(expect, service string) bool {
+	return expect == "" || expect == routingcommon.MatchAll || expect == service
+}
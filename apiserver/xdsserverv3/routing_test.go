@@ -0,0 +1,212 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
+)
+
+func newTextMatch(t apiv2.MatchString_MatchStringType, value string) *apiv2.MatchString {
+	return &apiv2.MatchString{
+		Type:  t,
+		Value: &wrappers.StringValue{Value: value},
+	}
+}
+
+func TestMakeRoutesV2(t *testing.T) {
+	serviceInfo := &ServiceInfo{
+		Name:      "echo",
+		Namespace: "default",
+		RoutingV2: []*apiv2.RuleRoutingConfig{
+			{
+				Sources: []*apiv2.Source{
+					{
+						Service:   "*",
+						Namespace: "default",
+						Arguments: []*apiv2.SourceMatch{
+							{Type: apiv2.SourceMatch_HEADER, Key: "uid", Value: newTextMatch(apiv2.MatchString_EXACT, "1001")},
+							{Type: apiv2.SourceMatch_QUERY, Key: "env", Value: newTextMatch(apiv2.MatchString_IN, "gray,beta")},
+							{Type: apiv2.SourceMatch_COOKIE, Key: "lane", Value: newTextMatch(apiv2.MatchString_EXACT, "blue")},
+							{Type: apiv2.SourceMatch_PATH, Value: newTextMatch(apiv2.MatchString_REGEX, "/api/.*")},
+						},
+					},
+					// 其他命名空间的主调方，不会下发
+					{
+						Service:   "*",
+						Namespace: "other",
+					},
+					// 自定义参数 envoy 无法处理
+					{
+						Service:   "*",
+						Namespace: "*",
+						Arguments: []*apiv2.SourceMatch{
+							{Type: apiv2.SourceMatch_CUSTOM, Key: "k", Value: newTextMatch(apiv2.MatchString_EXACT, "v")},
+						},
+					},
+				},
+				Destinations: []*apiv2.Destination{
+					{
+						Service:   "echo",
+						Namespace: "default",
+						Labels:    map[string]*apiv2.MatchString{"version": newTextMatch(apiv2.MatchString_EXACT, "v1")},
+						Weight:    80,
+					},
+					{
+						Service:   "echo",
+						Namespace: "default",
+						Labels:    map[string]*apiv2.MatchString{"version": newTextMatch(apiv2.MatchString_EXACT, "v2")},
+						Weight:    20,
+					},
+					// 低优先级的分组只用于降级，不参与权重分配
+					{
+						Service:   "echo",
+						Namespace: "default",
+						Labels:    map[string]*apiv2.MatchString{"version": newTextMatch(apiv2.MatchString_EXACT, "v3")},
+						Priority:  1,
+						Weight:    100,
+					},
+					{
+						Service:   "echo",
+						Namespace: "default",
+						Labels:    map[string]*apiv2.MatchString{"env": newTextMatch(apiv2.MatchString_EXACT, "x")},
+						Isolate:   true,
+					},
+				},
+			},
+			// 没有 destination 指向当前服务，直接跳过
+			{
+				Sources: []*apiv2.Source{{Service: "*", Namespace: "*"}},
+				Destinations: []*apiv2.Destination{
+					{Service: "other", Namespace: "default", Weight: 100},
+				},
+			},
+			{
+				Sources: []*apiv2.Source{{Service: "*", Namespace: "*"}},
+				Destinations: []*apiv2.Destination{
+					{Service: "*", Namespace: "*"},
+				},
+			},
+		},
+	}
+
+	routes := makeRoutes(serviceInfo)
+	if len(routes) != 3 {
+		t.Fatalf("expect 3 routes, got %d", len(routes))
+	}
+
+	match := routes[0].GetMatch()
+	if match.GetSafeRegex().GetRegex() != "/api/.*" {
+		t.Fatalf("unexpect path match %v", match.GetPathSpecifier())
+	}
+	if len(match.GetHeaders()) != 2 || match.GetHeaders()[0].GetName() != "uid" ||
+		match.GetHeaders()[1].GetName() != headerCookie {
+		t.Fatalf("unexpect header match %v", match.GetHeaders())
+	}
+	if len(match.GetQueryParameters()) != 1 ||
+		match.GetQueryParameters()[0].GetStringMatch().GetSafeRegex().GetRegex() != "(gray|beta)" {
+		t.Fatalf("unexpect query match %v", match.GetQueryParameters())
+	}
+
+	weighted := routes[0].GetRoute().GetWeightedClusters()
+	if weighted.GetTotalWeight().GetValue() != 100 || len(weighted.GetClusters()) != 2 {
+		t.Fatalf("unexpect weighted clusters %v", weighted)
+	}
+	v := weighted.GetClusters()[1].GetMetadataMatch().GetFilterMetadata()["envoy.lb"].GetFields()["version"]
+	if v.GetStringValue() != "v2" {
+		t.Fatalf("unexpect metadata match %v", v)
+	}
+
+	// 没有设置权重时，平均分配
+	weighted = routes[1].GetRoute().GetWeightedClusters()
+	if weighted.GetTotalWeight().GetValue() != 1 || weighted.GetClusters()[0].GetMetadataMatch() != nil {
+		t.Fatalf("unexpect weighted clusters %v", weighted)
+	}
+	if routes[1].GetMatch().GetPrefix() != "/" {
+		t.Fatalf("unexpect match %v", routes[1].GetMatch())
+	}
+
+	// 最后是默认路由
+	if routes[2].GetRoute().GetCluster() != "echo" {
+		t.Fatalf("unexpect default route %v", routes[2])
+	}
+
+	subset := makeLbSubsetConfig(serviceInfo)
+	if subset == nil || len(subset.GetSubsetSelectors()) != 1 ||
+		subset.GetSubsetSelectors()[0].GetKeys()[0] != "version" {
+		t.Fatalf("unexpect subset config %v", subset)
+	}
+}
+
+func TestMakeRouteMatchV2(t *testing.T) {
+	// query 参数不支持取反
+	_, ok := makeRouteMatchV2([]*apiv2.SourceMatch{
+		{Type: apiv2.SourceMatch_QUERY, Key: "env", Value: newTextMatch(apiv2.MatchString_NOT_EQUALS, "prod")},
+	})
+	if ok {
+		t.Fatal("query not equals should not be supported")
+	}
+
+	// 参数取值来自主调参数时无法处理
+	_, ok = makeRouteMatchV2([]*apiv2.SourceMatch{
+		{Type: apiv2.SourceMatch_HEADER, Key: "uid", Value: &apiv2.MatchString{
+			ValueType: apiv2.MatchString_PARAMETER,
+			Value:     &wrappers.StringValue{Value: "uid"},
+		}},
+	})
+	if ok {
+		t.Fatal("parameter value type should not be supported")
+	}
+
+	// 调用方 IP 无法可靠获取
+	_, ok = makeRouteMatchV2([]*apiv2.SourceMatch{
+		{Type: apiv2.SourceMatch_CALLER_IP, Value: newTextMatch(apiv2.MatchString_EXACT, "127.0.0.1")},
+	})
+	if ok {
+		t.Fatal("caller ip should not be supported")
+	}
+
+	match, ok := makeRouteMatchV2([]*apiv2.SourceMatch{
+		{Type: apiv2.SourceMatch_METHOD, Value: newTextMatch(apiv2.MatchString_NOT_IN, "PUT,DELETE")},
+		{Type: apiv2.SourceMatch_HEADER, Key: "uid", Value: newTextMatch(apiv2.MatchString_EXACT, "1001")},
+		{Type: apiv2.SourceMatch_PATH, Value: newTextMatch(apiv2.MatchString_EXACT, "/echo")},
+	})
+	if !ok {
+		t.Fatal("route match should be supported")
+	}
+	if match.GetPath() != "/echo" {
+		t.Fatalf("unexpect path %v", match.GetPathSpecifier())
+	}
+	headers := match.GetHeaders()
+	expect := []*route.HeaderMatcher{
+		{Name: headerMethod, InvertMatch: true},
+		{Name: "uid"},
+	}
+	for i := range expect {
+		if headers[i].GetName() != expect[i].GetName() || headers[i].GetInvertMatch() != expect[i].GetInvertMatch() {
+			t.Fatalf("unexpect header %v", headers[i])
+		}
+	}
+	if headers[0].GetStringMatch().GetSafeRegex().GetRegex() != "(PUT|DELETE)" {
+		t.Fatalf("unexpect method regex %v", headers[0].GetStringMatch())
+	}
+}
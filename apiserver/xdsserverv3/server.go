@@ -38,6 +38,7 @@ import (
 	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	endpointservice "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
 	listenerservice "github.com/envoyproxy/go-control-plane/envoy/service/listener/v3"
+	rls "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
 	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
 	runtimeservice "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
 	secretservice "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
@@ -57,6 +58,7 @@ import (
 	"github.com/polarismesh/polaris/apiserver"
 	"github.com/polarismesh/polaris/cache"
 	api "github.com/polarismesh/polaris/common/api/v1"
+	apiv2 "github.com/polarismesh/polaris/common/api/v2"
 	"github.com/polarismesh/polaris/common/connlimit"
 	commonlog "github.com/polarismesh/polaris/common/log"
 	"github.com/polarismesh/polaris/common/model"
@@ -86,9 +88,12 @@ type XDSServer struct {
 	exitCh          chan struct{}
 	namingServer    service.DiscoverServer
 	cache           cachev3.SnapshotCache
+	resourceCache   *ResourceCache
 	versionNum      *atomic.Uint64
 	server          *grpc.Server
 	connLimitConfig *connlimit.Config
+	rateLimitConfig *RateLimitConfig
+	rateLimitServer *RateLimitServer
 
 	registryInfo               map[string][]*ServiceInfo
 	CircuitBreakerConfigGetter CircuitBreakerConfigGetter
@@ -100,6 +105,7 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 	api map[string]apiserver.APIConfig,
 ) error {
 	x.cache = cachev3.NewSnapshotCache(false, PolarisNodeHash{}, commonlog.XDSV3Scope())
+	x.resourceCache = NewResourceCache(PolarisNodeHash{}, commonlog.XDSV3Scope())
 	x.registryInfo = make(map[string][]*ServiceInfo)
 	x.listenPort = uint32(option["listenPort"].(int))
 	x.listenIP = option["listenIP"].(string)
@@ -121,6 +127,22 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 		x.connLimitConfig = connConfig
 	}
 
+	if raw, _ := option["rateLimit"].(map[interface{}]interface{}); raw != nil {
+		rateLimitConfig, err := ParseRateLimitConfig(raw, x.listenPort)
+		if err != nil {
+			return err
+		}
+		x.rateLimitConfig = rateLimitConfig
+	}
+	if x.globalRateLimitEnabled() {
+		counter, err := newQuotaCounter(x.rateLimitConfig)
+		if err != nil {
+			log.Errorf("init global rate limit counter err: %s", err.Error())
+			return err
+		}
+		x.rateLimitServer = NewRateLimitServer(x.namingServer.Cache().RateLimit().GetRateLimitByServiceID, counter)
+	}
+
 	err = x.initRegistryInfo()
 	if err != nil {
 		log.Errorf("%v", err)
@@ -149,7 +171,7 @@ func (x *XDSServer) Run(errCh chan error) {
 	// 启动 grpc server
 	ctx := context.Background()
 	cb := &Callbacks{log: commonlog.XDSV3Scope()}
-	srv := serverv3.NewServer(ctx, x.cache, cb)
+	srv := serverv3.NewServer(ctx, newMuxCache(x.cache, x.resourceCache), cb)
 	var grpcOptions []grpc.ServerOption
 	grpcOptions = append(grpcOptions, grpc.MaxConcurrentStreams(1000))
 	grpcServer := grpc.NewServer(grpcOptions...)
@@ -175,6 +197,10 @@ func (x *XDSServer) Run(errCh chan error) {
 	}
 
 	registerServer(grpcServer, srv)
+	if x.rateLimitServer != nil {
+		// 全局限流服务与 xds 服务共用同一个端口
+		rls.RegisterRateLimitServiceServer(grpcServer, x.rateLimitServer)
+	}
 
 	log.Infof("management server listening on %d\n", x.listenPort)
 
@@ -285,18 +311,18 @@ type ServiceInfo struct {
 	SvcInsRevision       string
 	Routing              *api.Routing
 	SvcRoutingRevision   string
+	RoutingV2            []*apiv2.RuleRoutingConfig
+	SvcRoutingV2Revision string
 	Ports                string
 	RateLimit            *api.RateLimit
 	SvcRateLimitRevision string
 }
 
 func makeLbSubsetConfig(serviceInfo *ServiceInfo) *cluster.Cluster_LbSubsetConfig {
-	if serviceInfo.Routing != nil && serviceInfo.Routing.Inbounds != nil &&
-		len(serviceInfo.Routing.Inbounds) > 0 {
-		lbSubsetConfig := &cluster.Cluster_LbSubsetConfig{}
-		var subsetSelectors []*cluster.Cluster_LbSubsetConfig_LbSubsetSelector
-		lbSubsetConfig.FallbackPolicy = cluster.Cluster_LbSubsetConfig_ANY_ENDPOINT
-
+	var subsetSelectors []*cluster.Cluster_LbSubsetConfig_LbSubsetSelector
+	hasInbounds := serviceInfo.Routing != nil && serviceInfo.Routing.Inbounds != nil &&
+		len(serviceInfo.Routing.Inbounds) > 0
+	if hasInbounds {
 		for _, inbound := range serviceInfo.Routing.Inbounds {
 			// 对每一个 destination 产生一个 subset
 			for _, destination := range inbound.Destinations {
@@ -310,11 +336,18 @@ func makeLbSubsetConfig(serviceInfo *ServiceInfo) *cluster.Cluster_LbSubsetConfi
 				})
 			}
 		}
+	}
 
-		lbSubsetConfig.SubsetSelectors = subsetSelectors
-		return lbSubsetConfig
+	// v2 规则路由中的 destination 同样需要对应的 subset
+	selectorsV2 := makeSubsetSelectorsV2(serviceInfo)
+	if !hasInbounds && len(selectorsV2) == 0 {
+		return nil
 	}
-	return nil
+
+	lbSubsetConfig := &cluster.Cluster_LbSubsetConfig{}
+	lbSubsetConfig.FallbackPolicy = cluster.Cluster_LbSubsetConfig_ANY_ENDPOINT
+	lbSubsetConfig.SubsetSelectors = append(subsetSelectors, selectorsV2...)
+	return lbSubsetConfig
 }
 
 // Translate the circuit breaker configuration of Polaris into OutlierDetection
@@ -432,7 +465,8 @@ func makeEndpoints(services []*ServiceInfo) []types.Resource {
 }
 
 func makeRoutes(serviceInfo *ServiceInfo) []*route.Route {
-	var routes []*route.Route
+	// v2 规则路由按照优先级排在 v1 路由之前
+	routes := makeRoutesV2(serviceInfo)
 	var matchAllRoute *route.Route
 	// 路由目前只处理 inbounds
 	if serviceInfo.Routing != nil && len(serviceInfo.Routing.Inbounds) > 0 {
@@ -735,6 +769,7 @@ func (x *XDSServer) makeVirtualHosts(services []*ServiceInfo) []types.Resource {
 			Domains:              generateServiceDomains(service),
 			Routes:               makeRoutes(service),
 			TypedPerFilterConfig: makeLocalRateLimit(rateLimitConf),
+			RateLimits:           x.makeGlobalRateLimits(service.ID, rateLimitConf),
 		})
 	}
 
@@ -787,7 +822,7 @@ func (x *XDSServer) makeSnapshot(ns, version string, services []*ServiceInfo) (e
 	resources[resource.EndpointType] = makeEndpoints(services)
 	resources[resource.ClusterType] = x.makeClusters(services)
 	resources[resource.RouteType] = x.makeVirtualHosts(services)
-	resources[resource.ListenerType] = makeListeners()
+	resources[resource.ListenerType] = makeListeners(x.makeHTTPFilters())
 	snapshot, err := cachev3.NewSnapshot(version, resources)
 	if err != nil {
 		log.Errorf("fail to create snapshot for %s, err is %v", ns, err)
@@ -803,6 +838,7 @@ func (x *XDSServer) makeSnapshot(ns, version string, services []*ServiceInfo) (e
 		log.Errorf("snapshot error %q for %+v", err, snapshot)
 		return err
 	}
+	x.updateResourceCache(ns, resources)
 	return
 }
 
@@ -811,7 +847,7 @@ func (x *XDSServer) makePermissiveSnapshot(ns, version string, services []*Servi
 	resources[resource.EndpointType] = makeEndpoints(services)
 	resources[resource.ClusterType] = x.makePermissiveClusters(services)
 	resources[resource.RouteType] = x.makeVirtualHosts(services)
-	resources[resource.ListenerType] = makePermissiveListeners()
+	resources[resource.ListenerType] = makePermissiveListeners(x.makeHTTPFilters())
 	snapshot, err := cachev3.NewSnapshot(version, resources)
 	if err != nil {
 		return err
@@ -826,6 +862,7 @@ func (x *XDSServer) makePermissiveSnapshot(ns, version string, services []*Servi
 		log.Errorf("snapshot error %q for %+v", err, snapshot)
 		return err
 	}
+	x.updateResourceCache(ns+"/permissive", resources)
 	return
 }
 
@@ -834,7 +871,7 @@ func (x *XDSServer) makeStrictSnapshot(ns, version string, services []*ServiceIn
 	resources[resource.EndpointType] = makeEndpoints(services)
 	resources[resource.ClusterType] = x.makeStrictClusters(services)
 	resources[resource.RouteType] = x.makeVirtualHosts(services)
-	resources[resource.ListenerType] = makeStrictListeners()
+	resources[resource.ListenerType] = makeStrictListeners(x.makeHTTPFilters())
 	snapshot, err := cachev3.NewSnapshot(version, resources)
 	if err != nil {
 		return err
@@ -849,9 +886,22 @@ func (x *XDSServer) makeStrictSnapshot(ns, version string, services []*ServiceIn
 		log.Errorf("snapshot error %q for %+v", err, snapshot)
 		return err
 	}
+	x.updateResourceCache(ns+"/strict", resources)
 	return
 }
 
+// updateResourceCache CDS/EDS 资源按照单个资源的粒度更新，只有发生变化的 cluster、endpoint 才会推送
+func (x *XDSServer) updateResourceCache(group string, resources map[resource.Type][]types.Resource) {
+	if x.resourceCache == nil {
+		return
+	}
+	for _, typeURL := range perResourceTypes {
+		if err := x.resourceCache.SetResources(group, typeURL, resources[typeURL]); err != nil {
+			log.Errorf("update resource cache %s for %s error %v", typeURL, group, err)
+		}
+	}
+}
+
 // syncPolarisServiceInfo 初始化本地 cache，初始化 xds cache
 func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo map[string][]*ServiceInfo) error {
 	// 从 cache 中获取全量的服务信息
@@ -859,24 +909,7 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 		if _, ok := registryInfo[value.Namespace]; !ok {
 			registryInfo[value.Namespace] = []*ServiceInfo{}
 		}
-
-		info := &ServiceInfo{
-			ID:        value.ID,
-			Name:      value.Name,
-			Namespace: value.Namespace,
-			Instances: []*api.Instance{},
-			Ports:     value.Ports,
-		}
-
-		if info.Ports == "" {
-			ports := x.namingServer.Cache().Instance().GetServicePorts(value.ID)
-			if len(ports) != 0 {
-				info.Ports = strings.Join(ports, ",")
-			}
-		}
-
-		registryInfo[value.Namespace] = append(registryInfo[value.Namespace], info)
-
+		registryInfo[value.Namespace] = append(registryInfo[value.Namespace], x.newServiceInfo(value))
 		return true, nil
 	}
 
@@ -885,6 +918,14 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 		return err
 	}
 
+	// 其他命名空间导出过来的服务，同样下发到目标命名空间中
+	for _, namespace := range x.namingServer.Cache().Namespace().GetNamespaceList() {
+		exported := x.namingServer.Cache().Service().GetExportedServices(namespace.Name)
+		for _, value := range exported {
+			registryInfo[namespace.Name] = append(registryInfo[namespace.Name], x.newServiceInfo(value))
+		}
+	}
+
 	// 遍历每一个服务，获取路由、熔断策略和全量的服务实例信息
 	for _, v := range registryInfo {
 		for _, svc := range v {
@@ -913,6 +954,15 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 				svc.Routing = routeResp.Routing
 			}
 
+			// 获取 v2 版本的规则路由配置
+			routingV2, routingV2Revision, err := x.getRuleRoutingV2(svc)
+			if err != nil {
+				log.Errorf("[XDSV3] error sync routing v2 for %s, err : %v", svc.Name, err)
+				return fmt.Errorf("[XDSV3] error sync routing v2 for %s", svc.Name)
+			}
+			svc.RoutingV2 = routingV2
+			svc.SvcRoutingV2Revision = routingV2Revision
+
 			// 获取instance配置
 			resp := x.namingServer.ServiceInstancesCache(context.TODO(), s)
 			if resp.GetCode().Value != api.ExecuteSuccess {
@@ -939,6 +989,25 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 	return nil
 }
 
+// newServiceInfo 根据缓存中的服务构建 ServiceInfo，实例、路由等信息在后续填充
+func (x *XDSServer) newServiceInfo(value *model.Service) *ServiceInfo {
+	info := &ServiceInfo{
+		ID:        value.ID,
+		Name:      value.Name,
+		Namespace: value.Namespace,
+		Instances: []*api.Instance{},
+		Ports:     value.Ports,
+	}
+
+	if info.Ports == "" {
+		ports := x.namingServer.Cache().Instance().GetServicePorts(value.ID)
+		if len(ports) != 0 {
+			info.Ports = strings.Join(ports, ",")
+		}
+	}
+	return info
+}
+
 func (x *XDSServer) initRegistryInfo() error {
 	namespaceServer, err := namespace.GetOriginServer()
 	if err != nil {
@@ -1036,6 +1105,9 @@ func (x *XDSServer) checkUpdate(curServiceInfo, cacheServiceInfo []*ServiceInfo)
 				if info.SvcRoutingRevision != serviceInfo.SvcRoutingRevision {
 					return true
 				}
+				if info.SvcRoutingV2Revision != serviceInfo.SvcRoutingV2Revision {
+					return true
+				}
 				if info.SvcRateLimitRevision != serviceInfo.SvcRateLimitRevision {
 					return true
 				}
@@ -63,6 +63,8 @@ type AuthChecker interface {
 	CheckClientPermission(preCtx *model.AcquireContext) (bool, error)
 	// CheckConsolePermission 执行检查控制台动作判断是否有权限，并且对 RequestContext 注入操作者数据
 	CheckConsolePermission(preCtx *model.AcquireContext) (bool, error)
+	// CheckPermission 不论是否开启了操作鉴权，都执行检查动作判断是否有权限，并且对 RequestContext 注入操作者数据
+	CheckPermission(authCtx *model.AcquireContext) (bool, error)
 	// IsOpenConsoleAuth 返回是否开启了操作鉴权，可以用于前端查询
 	IsOpenConsoleAuth() bool
 	// IsOpenClientAuth
@@ -44,6 +44,7 @@ import (
 	"github.com/polarismesh/polaris/plugin"
 	"github.com/polarismesh/polaris/service"
 	"github.com/polarismesh/polaris/service/batch"
+	"github.com/polarismesh/polaris/service/clustersync"
 	"github.com/polarismesh/polaris/service/healthcheck"
 	"github.com/polarismesh/polaris/store"
 )
@@ -188,8 +189,13 @@ func StartComponents(ctx context.Context, cfg *boot_config.Config) error {
 		return err
 	}
 
+	configSvr, err := config_center.GetOriginServer()
+	if err != nil {
+		return err
+	}
+
 	// 初始化运维操作模块
-	if err := maintain.Initialize(ctx, namingSvr, healthCheckServer); err != nil {
+	if err := maintain.Initialize(ctx, namingSvr, healthCheckServer, configSvr); err != nil {
 		return err
 	}
 
@@ -257,6 +263,11 @@ func StartDiscoverComponents(ctx context.Context, cfg *boot_config.Config, s sto
 		return err
 	}
 
+	syncConfig, err := clustersync.ParseConfig(cfg.Naming.Sync)
+	if err != nil {
+		return err
+	}
+
 	opts := []service.InitOption{
 		service.WithBatchController(bc),
 		service.WithStorage(s),
@@ -264,6 +275,7 @@ func StartDiscoverComponents(ctx context.Context, cfg *boot_config.Config, s sto
 		service.WithHealthCheckSvr(healthCheckServer),
 		service.WithNamespaceSvr(namespaceSvr),
 		service.WithHiddenService(map[model.ServiceKey]struct{}{}),
+		service.WithPreferLocalInstances(syncConfig.PreferLocal),
 	}
 
 	// 初始化服务模块
@@ -271,11 +283,20 @@ func StartDiscoverComponents(ctx context.Context, cfg *boot_config.Config, s sto
 		return err
 	}
 
-	_, err = service.GetServer()
+	namingSvr, err := service.GetOriginServer()
 	if err != nil {
 		return err
 	}
 
+	// 多集群服务同步
+	if syncConfig.Open {
+		syncer, err := clustersync.NewSyncer(syncConfig, s, cacheMgn, namingSvr)
+		if err != nil {
+			return err
+		}
+		syncer.Start(ctx)
+	}
+
 	return nil
 }
 
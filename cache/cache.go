@@ -142,7 +142,7 @@ func newBaseCache() *baseCache {
 
 // addListener 添加
 func (bc *baseCache) addListener(listeners []Listener) {
-	bc.manager.listeners = append(bc.manager.listeners, listeners...)
+	bc.manager.addListeners(listeners)
 }
 
 const (
@@ -161,6 +161,8 @@ const (
 type revisionNotify struct {
 	serviceID string
 	valid     bool
+	// service 被删除的服务，服务删除后无法再通过 serviceID 从缓存中获取服务的命名空间以及名称
+	service *model.Service
 }
 
 // create new revision notify
@@ -171,6 +173,27 @@ func newRevisionNotify(serviceID string, valid bool) *revisionNotify {
 	}
 }
 
+// newRemovedRevisionNotify 服务被删除时的 revision 通知
+func newRemovedRevisionNotify(service *model.Service) *revisionNotify {
+	return &revisionNotify{
+		serviceID: service.ID,
+		valid:     false,
+		service:   service,
+	}
+}
+
+// RevisionEvent 服务 revision 变化事件，服务被删除时 Revision 为空
+type RevisionEvent struct {
+	ServiceID string
+	Namespace string
+	Name      string
+	Revision  string
+}
+
+// RevisionListener 服务 revision 变化的回调。
+// 回调在 revision 计算协程中同步执行，实现方不能阻塞
+type RevisionListener func(event RevisionEvent)
+
 // CacheManager 名字服务缓存
 type CacheManager struct {
 	storage store.Store
@@ -180,6 +203,9 @@ type CacheManager struct {
 	revisions        map[string]string // service id -> reversion (所有instance reversion 的累计计算值)
 	lock             sync.RWMutex      // for revisions rw lock
 	storeTimeDiffSec int64
+
+	revisionListeners []RevisionListener
+	listenerLock      sync.RWMutex // for revisionListeners rw lock
 }
 
 // initialize 缓存对象初始化
@@ -222,16 +248,44 @@ func (nc *CacheManager) update() error {
 	return nil
 }
 
-func (nc *CacheManager) deleteRevisions(id string) {
+func (nc *CacheManager) deleteRevisions(service *model.Service) {
 	nc.lock.Lock()
-	delete(nc.revisions, id)
+	_, ok := nc.revisions[service.ID]
+	delete(nc.revisions, service.ID)
 	nc.lock.Unlock()
+
+	if ok {
+		nc.notifyRevisionListeners(RevisionEvent{
+			ServiceID: service.ID,
+			Namespace: service.Namespace,
+			Name:      service.Name,
+		})
+	}
 }
 
-func (nc *CacheManager) setRevisions(key string, val string) {
+func (nc *CacheManager) setRevisions(service *model.Service, val string) {
 	nc.lock.Lock()
-	nc.revisions[key] = val
+	old := nc.revisions[service.ID]
+	nc.revisions[service.ID] = val
 	nc.lock.Unlock()
+
+	if old != val {
+		nc.notifyRevisionListeners(RevisionEvent{
+			ServiceID: service.ID,
+			Namespace: service.Namespace,
+			Name:      service.Name,
+			Revision:  val,
+		})
+	}
+}
+
+func (nc *CacheManager) notifyRevisionListeners(event RevisionEvent) {
+	nc.listenerLock.RLock()
+	defer nc.listenerLock.RUnlock()
+
+	for _, listener := range nc.revisionListeners {
+		listener(event)
+	}
 }
 
 func (nc *CacheManager) readRevisions(key string) (string, bool) {
@@ -336,7 +390,9 @@ func (nc *CacheManager) processRevisionWorker(req *revisionNotify) bool {
 
 	if !req.valid {
 		log.CacheScope().Infof("[Cache][Revision] service(%s) revision has all been removed", req.serviceID)
-		nc.deleteRevisions(req.serviceID)
+		if req.service != nil {
+			nc.deleteRevisions(req.service)
+		}
 		return true
 	}
 
@@ -354,7 +410,7 @@ func (nc *CacheManager) processRevisionWorker(req *revisionNotify) bool {
 		return false
 	}
 
-	nc.setRevisions(req.serviceID, revision) // string -> string
+	nc.setRevisions(service, revision)
 	return true
 }
 
@@ -381,6 +437,14 @@ func (nc *CacheManager) GetServiceRevisionCount() int {
 	return len(nc.revisions)
 }
 
+// AddRevisionListener 注册服务 revision 变化的回调
+func (nc *CacheManager) AddRevisionListener(listener RevisionListener) {
+	nc.listenerLock.Lock()
+	defer nc.listenerLock.Unlock()
+
+	nc.revisionListeners = append(nc.revisionListeners, listener)
+}
+
 func (nc *CacheManager) AddListener(cacheName CacheName, listeners []Listener) {
 	cacheIndex := cacheIndexMap[cacheName]
 	nc.caches[cacheIndex].addListener(listeners)
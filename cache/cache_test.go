@@ -212,3 +212,42 @@ func TestComputeRevision(t *testing.T) {
 		So(lhs, ShouldNotEqual, rhs)
 	})
 }
+
+// TestCacheManager_RevisionListener 测试服务 revision 变化时通知监听者
+func TestCacheManager_RevisionListener(t *testing.T) {
+	Convey("revision 变化时通知监听者", t, func() {
+		nc := &CacheManager{revisions: map[string]string{}}
+		notified := make(map[string]RevisionEvent)
+		count := 0
+		nc.AddRevisionListener(func(event RevisionEvent) {
+			notified[event.ServiceID] = event
+			count++
+		})
+		svc := &model.Service{ID: "svc-1", Namespace: "ns-1", Name: "name-1"}
+
+		nc.setRevisions(svc, "rev-1")
+		So(count, ShouldEqual, 1)
+		So(notified["svc-1"].Revision, ShouldEqual, "rev-1")
+		So(notified["svc-1"].Namespace, ShouldEqual, "ns-1")
+		So(notified["svc-1"].Name, ShouldEqual, "name-1")
+
+		// revision 没有变化不通知
+		nc.setRevisions(svc, "rev-1")
+		So(count, ShouldEqual, 1)
+
+		nc.setRevisions(svc, "rev-2")
+		So(count, ShouldEqual, 2)
+		So(notified["svc-1"].Revision, ShouldEqual, "rev-2")
+
+		// 服务删除后仍然携带服务的命名空间以及名称
+		nc.deleteRevisions(svc)
+		So(count, ShouldEqual, 3)
+		So(notified["svc-1"].Revision, ShouldEqual, "")
+		So(notified["svc-1"].Namespace, ShouldEqual, "ns-1")
+		So(notified["svc-1"].Name, ShouldEqual, "name-1")
+
+		// 不存在的 revision 删除时不通知
+		nc.deleteRevisions(svc)
+		So(count, ShouldEqual, 3)
+	})
+}
@@ -151,10 +151,16 @@ func (c *circuitBreakerCache) setCircuitBreaker(cbs []*model.ServiceWithCircuitB
 
 		if !cbs[k].Valid {
 			c.deleteCircuitBreaker(cbs[k].ServiceID)
+			c.manager.onEvent(cbs[k], EventDeleted)
 			continue
 		}
 
+		event := EventUpdated
+		if c.GetCircuitBreakerConfig(cbs[k].ServiceID) == nil {
+			event = EventCreated
+		}
 		c.storeCircuitBreaker(cbs[k])
+		c.manager.onEvent(cbs[k], event)
 	}
 
 	if c.lastTime.Unix() < lastTime {
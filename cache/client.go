@@ -54,6 +54,9 @@ type ClientCache interface {
 	// IteratorClients 迭代
 	IteratorClients(iterProc ClientIterProc)
 
+	// GetClientsByHost 获取指定 IP 上报的所有客户端
+	GetClientsByHost(host string) []*model.Client
+
 	// GetClientsByFilter Query client information
 	GetClientsByFilter(filters map[string]string, offset, limit uint32) (uint32, []*model.Client, error)
 }
@@ -66,7 +69,8 @@ type clientCache struct {
 	lastMtime       int64
 	lastMtimeLogged int64
 	firstUpdate     bool
-	clients         map[string]*model.Client // instance id -> instance
+	clients         map[string]*model.Client            // instance id -> instance
+	hosts           map[string]map[string]*model.Client // host -> client id -> client
 	lock            sync.RWMutex
 	singleFlight    *singleflight.Group
 	lastUpdateTime  time.Time
@@ -88,6 +92,7 @@ func newClientCache(storage store.Store) *clientCache {
 		baseCache: newBaseCache(),
 		storage:   storage,
 		clients:   map[string]*model.Client{},
+		hosts:     map[string]map[string]*model.Client{},
 	}
 }
 
@@ -156,14 +161,40 @@ func (c *clientCache) getClient(id string) (*model.Client, bool) {
 
 func (c *clientCache) deleteClient(id string) {
 	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if old, ok := c.clients[id]; ok {
+		c.removeHostIndex(id, old.Proto().GetHost().GetValue())
+	}
 	delete(c.clients, id)
-	c.lock.Unlock()
 }
 
 func (c *clientCache) storeClient(id string, client *model.Client) {
 	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// 客户端的 IP 可能发生变化，需要先移除旧的索引
+	if old, ok := c.clients[id]; ok {
+		c.removeHostIndex(id, old.Proto().GetHost().GetValue())
+	}
 	c.clients[id] = client
-	c.lock.Unlock()
+
+	host := client.Proto().GetHost().GetValue()
+	if _, ok := c.hosts[host]; !ok {
+		c.hosts[host] = map[string]*model.Client{}
+	}
+	c.hosts[host][id] = client
+}
+
+func (c *clientCache) removeHostIndex(id, host string) {
+	clients, ok := c.hosts[host]
+	if !ok {
+		return
+	}
+	delete(clients, id)
+	if len(clients) == 0 {
+		delete(c.hosts, host)
+	}
 }
 
 // setClients 保存client到内存中
@@ -226,6 +257,7 @@ func (c *clientCache) setClients(clients map[string]*model.Client) (int, int) {
 func (c *clientCache) clear() error {
 	c.lock.Lock()
 	c.clients = map[string]*model.Client{}
+	c.hosts = map[string]map[string]*model.Client{}
 	c.lock.Unlock()
 
 	c.lastMtime = 0
@@ -260,6 +292,23 @@ func (c *clientCache) IteratorClients(iterProc ClientIterProc) {
 	}
 }
 
+// GetClientsByHost 获取指定 IP 上报的所有客户端
+func (c *clientCache) GetClientsByHost(host string) []*model.Client {
+	if host == "" {
+		return nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	clients := c.hosts[host]
+	ret := make([]*model.Client, 0, len(clients))
+	for _, client := range clients {
+		ret = append(ret, client)
+	}
+	return ret
+}
+
 // GetClientsByFilter Query client information
 func (c *clientCache) GetClientsByFilter(filters map[string]string, offset, limit uint32) (uint32,
 	[]*model.Client, error) {
@@ -122,3 +122,49 @@ func Test_clientCache_GetClientByFilter(t *testing.T) {
 		assert.Equal(t, ret[id], item[0])
 	})
 }
+
+func Test_clientCache_GetClientsByHost(t *testing.T) {
+
+	t.Run("测试按照IP获取client", func(t *testing.T) {
+		ctrl, store, clientCache := newTestClientCache(t)
+		defer ctrl.Finish()
+
+		ret := mockClients(10)
+
+		id := ""
+		host := ""
+		for k := range ret {
+			id = k
+			host = ret[id].Proto().Host.Value
+			break
+		}
+
+		store.EXPECT().GetMoreClients(gomock.Any(), gomock.Any()).Return(ret, nil)
+
+		err := clientCache.update(time.Duration(0))
+		assert.NoError(t, err)
+
+		items := clientCache.GetClientsByHost(host)
+		assert.Equal(t, 1, len(items))
+		assert.Equal(t, ret[id], items[0])
+
+		// 客户端 IP 变化后旧的索引需要移除
+		moved := model.NewClient(&apiv1.Client{
+			Host: utils.NewStringValue("127.0.1.1"),
+			Id:   utils.NewStringValue(id),
+		})
+		moved.SetValid(true)
+		clientCache.setClients(map[string]*model.Client{id: moved})
+		assert.Equal(t, 0, len(clientCache.GetClientsByHost(host)))
+		assert.Equal(t, moved, clientCache.GetClientsByHost("127.0.1.1")[0])
+
+		// 客户端删除后索引需要移除
+		deleted := model.NewClient(&apiv1.Client{
+			Host: utils.NewStringValue("127.0.1.1"),
+			Id:   utils.NewStringValue(id),
+		})
+		deleted.SetValid(false)
+		clientCache.setClients(map[string]*model.Client{id: deleted})
+		assert.Equal(t, 0, len(clientCache.GetClientsByHost("127.0.1.1")))
+	})
+}
@@ -94,6 +94,8 @@ type Entry struct {
 	ExpireTime time.Time
 	// 标识是否是空缓存
 	Empty bool
+	// DataKey 加密配置文件经过 KMS 加密后的数据密钥，未加密的配置文件为空
+	DataKey string
 }
 
 // newFileCache 创建文件缓存
@@ -266,6 +268,16 @@ func (fc *fileCache) GetOrLoadIfAbsent(namespace, group, fileName string) (*Entr
 		return emptyEntry, nil
 	}
 
+	tags, err := fc.storage.QueryTagByConfigFile(namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Cache] load config file tags error.",
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return nil, err
+	}
+
 	// 数据库中有对象，更新缓存
 	newEntry := &Entry{
 		Content:    file.Content,
@@ -274,6 +286,11 @@ func (fc *fileCache) GetOrLoadIfAbsent(namespace, group, fileName string) (*Entr
 		ExpireTime: fc.getExpireTime(),
 		Empty:      false,
 	}
+	for _, tag := range tags {
+		if tag.Key == utils.ConfigFileTagKeyDataKey {
+			newEntry.DataKey = tag.Value
+		}
+	}
 
 	// 缓存不存在，则直接存入缓存
 	if !ok {
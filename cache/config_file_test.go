@@ -163,6 +163,7 @@ func TestUpdateCache(t *testing.T) {
 func newConfigFileMockedCache(t *testing.T) (*gomock.Controller, *mock.MockStore, FileCache) {
 	control := gomock.NewController(t)
 	mockedStorage := mock.NewMockStore(control)
+	mockedStorage.EXPECT().QueryTagByConfigFile(testNamespace, testGroup, testFile).Return(nil, nil).AnyTimes()
 	fileCache := newFileCache(context.Background(), mockedStorage)
 
 	return control, mockedStorage, fileCache
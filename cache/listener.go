@@ -17,6 +17,8 @@
 
 package cache
 
+import "sync"
+
 // Listener listener for value changes
 type Listener interface {
 	// OnCreated callback when cache value created
@@ -50,6 +52,7 @@ const (
 )
 
 type listenerManager struct {
+	lock      sync.RWMutex // 监听者可能在缓存更新协程运行后注册
 	listeners []Listener
 }
 
@@ -59,7 +62,17 @@ func newListenerManager(listeners []Listener) *listenerManager {
 	}
 }
 
+func (l *listenerManager) addListeners(listeners []Listener) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.listeners = append(l.listeners, listeners...)
+}
+
 func (l *listenerManager) onEvent(value interface{}, event EventType) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
 	if len(l.listeners) == 0 {
 		return
 	}
@@ -154,6 +154,7 @@ func (rlc *rateLimitCache) setRateLimit(rateLimits []*model.RateLimit,
 				continue
 			}
 			value.(*sync.Map).Delete(item.ID)
+			rlc.manager.onEvent(item, EventDeleted)
 			continue
 		}
 
@@ -162,7 +163,12 @@ func (rlc *rateLimitCache) setRateLimit(rateLimits []*model.RateLimit,
 			value = new(sync.Map)
 			rlc.ids.Store(item.ServiceID, value)
 		}
+		event := EventCreated
+		if _, loaded := value.(*sync.Map).Load(item.ID); loaded {
+			event = EventUpdated
+		}
 		value.(*sync.Map).Store(item.ID, item)
+		rlc.manager.onEvent(item, event)
 	}
 
 	// 更新last revision
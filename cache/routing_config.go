@@ -278,12 +278,18 @@ func (rc *routingConfigCache) setRoutingConfigV1(cs []*model.RoutingConfig) erro
 			rc.bucketV2.deleteV1(entry.ID)
 			// 删除 v1 转换到 v2 的任务id
 			delete(rc.pendingV1RuleIds, entry.ID)
+			rc.manager.onEvent(entry, EventDeleted)
 			continue
 		}
 
+		event := EventUpdated
+		if rc.bucketV1.get(entry.ID) == nil {
+			event = EventCreated
+		}
 		// 保存到老的 v1 缓存
 		rc.bucketV1.save(entry)
 		rc.pendingV1RuleIds[entry.ID] = struct{}{}
+		rc.manager.onEvent(entry, event)
 	}
 
 	if rc.lastMtimeV1.Unix() < lastMtimeV1 {
@@ -308,6 +314,7 @@ func (rc *routingConfigCache) setRoutingConfigV2(cs []*v2.RoutingConfig) error {
 		}
 		if !entry.Valid {
 			rc.bucketV2.deleteV2(entry.ID)
+			rc.manager.onEvent(entry, EventDeleted)
 			continue
 		}
 		extendEntry, err := entry.ToExpendRoutingConfig()
@@ -315,7 +322,12 @@ func (rc *routingConfigCache) setRoutingConfigV2(cs []*v2.RoutingConfig) error {
 			log.CacheScope().Error("[Cache] routing config v2 convert to expend", zap.Error(err))
 			continue
 		}
+		event := EventUpdated
+		if rc.bucketV2.getV2(entry.ID) == nil {
+			event = EventCreated
+		}
 		rc.bucketV2.saveV2(extendEntry)
+		rc.manager.onEvent(entry, event)
 	}
 	if rc.lastMtimeV2.Unix() < lastMtimeV2 {
 		rc.lastMtimeV2 = time.Unix(lastMtimeV2, 0)
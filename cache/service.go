@@ -106,6 +106,12 @@ type ServiceCache interface {
 	GetServicesByFilter(serviceFilters *ServiceArgs,
 		instanceFilters *store.InstanceArgs, offset, limit uint32) (uint32, []*model.EnhancedService, error)
 
+	// GetExportedService Get the service with the name which is exported to the namespace by other namespaces
+	GetExportedService(name string, namespace string) *model.Service
+
+	// GetExportedServices Get all the services exported to the namespace by other namespaces
+	GetExportedServices(namespace string) []*model.Service
+
 	// Update Query trigger update interface
 	Update() error
 }
@@ -122,6 +128,7 @@ type serviceCache struct {
 	names               *sync.Map // spacename -> [serviceName -> service]
 	cl5Sid2Name         *sync.Map // 兼容Cl5，sid -> name
 	cl5Names            *sync.Map // 兼容Cl5，name -> service
+	exports             *sync.Map // 导出到其他命名空间的服务，serviceName -> [serviceID -> service]
 	revisionCh          chan *revisionNotify
 	disableBusiness     bool
 	needMeta            bool
@@ -156,6 +163,7 @@ func (sc *serviceCache) initialize(opt map[string]interface{}) error {
 	sc.names = new(sync.Map)
 	sc.cl5Sid2Name = new(sync.Map)
 	sc.cl5Names = new(sync.Map)
+	sc.exports = new(sync.Map)
 	sc.firstUpdate = true
 
 	sc.countChangeCh = make(chan map[string]bool, 1024)
@@ -217,6 +225,7 @@ func (sc *serviceCache) clear() error {
 	sc.names = new(sync.Map)
 	sc.cl5Sid2Name = new(sync.Map)
 	sc.cl5Names = new(sync.Map)
+	sc.exports = new(sync.Map)
 	sc.namespaceServiceCnt = new(sync.Map)
 	sc.pendingServices = make(map[string]int8)
 	sc.lastMtime = 0
@@ -336,6 +345,8 @@ func (sc *serviceCache) removeServices(service *model.Service) {
 		sc.cl5Names.Delete(cl5Name)
 	}
 	/******兼容cl5******/
+
+	sc.removeExportedService(service)
 }
 
 // setServices 服务缓存更新
@@ -369,13 +380,17 @@ func (sc *serviceCache) setServices(services map[string]*model.Service) (int, in
 		// 发现有删除操作
 		if !service.Valid {
 			sc.removeServices(service)
-			sc.revisionCh <- newRevisionNotify(service.ID, false)
+			sc.revisionCh <- newRemovedRevisionNotify(service)
 			del++
 			continue
 		}
 
 		update++
+		if oldService, ok := sc.ids.Load(service.ID); ok {
+			sc.removeExportedService(oldService.(*model.Service))
+		}
 		sc.ids.Store(service.ID, service)
+		sc.storeExportedService(service)
 		sc.revisionCh <- newRevisionNotify(service.ID, true)
 
 		spaces, ok := sc.names.Load(spaceName)
@@ -398,6 +413,62 @@ func (sc *serviceCache) setServices(services map[string]*model.Service) (int, in
 	return update, del
 }
 
+// storeExportedService 记录导出到其他命名空间的服务
+func (sc *serviceCache) storeExportedService(service *model.Service) {
+	if service.IsAlias() || len(service.ExportTo()) == 0 {
+		return
+	}
+	value, _ := sc.exports.LoadOrStore(service.Name, new(sync.Map))
+	value.(*sync.Map).Store(service.ID, service)
+}
+
+// removeExportedService 删除服务的导出记录
+func (sc *serviceCache) removeExportedService(service *model.Service) {
+	if value, ok := sc.exports.Load(service.Name); ok {
+		value.(*sync.Map).Delete(service.ID)
+	}
+}
+
+// GetExportedService 查找其他命名空间导出到 namespace 下的同名服务，
+// 存在多个时取命名空间名称最小的一个，保证查找结果是稳定的
+func (sc *serviceCache) GetExportedService(name string, namespace string) *model.Service {
+	if name == "" || namespace == "" {
+		return nil
+	}
+	value, ok := sc.exports.Load(name)
+	if !ok {
+		return nil
+	}
+	var ret *model.Service
+	value.(*sync.Map).Range(func(_, item interface{}) bool {
+		service := item.(*model.Service)
+		if !service.IsExportedTo(namespace) {
+			return true
+		}
+		if ret == nil || service.Namespace < ret.Namespace {
+			ret = service
+		}
+		return true
+	})
+	return ret
+}
+
+// GetExportedServices 获取其他命名空间导出到 namespace 下的全部服务，namespace 下已经存在的同名服务优先
+func (sc *serviceCache) GetExportedServices(namespace string) []*model.Service {
+	services := make([]*model.Service, 0)
+	sc.exports.Range(func(key, _ interface{}) bool {
+		name := key.(string)
+		if sc.GetServiceByName(name, namespace) != nil {
+			return true
+		}
+		if service := sc.GetExportedService(name, namespace); service != nil {
+			services = append(services, service)
+		}
+		return true
+	})
+	return services
+}
+
 func (sc *serviceCache) notifyServiceCountReload(svcIds map[string]bool) {
 	sc.countChangeCh <- svcIds
 }
@@ -314,6 +314,63 @@ func TestGetServiceByName(t *testing.T) {
 	})
 }
 
+// TestServiceCache_GetExportedService 获取其他命名空间导出的服务
+func TestServiceCache_GetExportedService(t *testing.T) {
+	ctl, _, sc, _ := newTestServiceCache(t)
+	defer ctl.Finish()
+
+	genExportService := func(namespace string, exportTo string) *model.Service {
+		return &model.Service{
+			ID:         namespace + "-export-svc",
+			Name:       "export-svc",
+			Namespace:  namespace,
+			Meta:       map[string]string{model.MetaKeyServiceExportTo: exportTo},
+			Valid:      true,
+			ModifyTime: time.Now(),
+		}
+	}
+
+	t.Run("导出到指定命名空间的服务，可以正常获取", func(t *testing.T) {
+		_ = sc.clear()
+		svcA := genExportService("ns-a", "ns-c")
+		svcB := genExportService("ns-b", "*")
+		sc.setServices(map[string]*model.Service{svcA.ID: svcA, svcB.ID: svcB})
+
+		// 多个命名空间都导出时，取命名空间名称最小的一个
+		if service := sc.GetExportedService("export-svc", "ns-c"); service == nil || service.ID != svcA.ID {
+			t.Fatalf("error: %+v", service)
+		}
+		if service := sc.GetExportedService("export-svc", "ns-d"); service == nil || service.ID != svcB.ID {
+			t.Fatalf("error: %+v", service)
+		}
+		if service := sc.GetExportedService("export-svc", "ns-b"); service != nil {
+			t.Fatalf("error: %+v", service)
+		}
+		if services := sc.GetExportedServices("ns-c"); len(services) != 1 || services[0].ID != svcA.ID {
+			t.Fatalf("error: %+v", services)
+		}
+		// 目标命名空间存在同名服务时，以本地服务为准
+		if services := sc.GetExportedServices("ns-a"); len(services) != 0 {
+			t.Fatalf("error: %+v", services)
+		}
+	})
+
+	t.Run("取消导出或者删除服务之后，无法继续获取", func(t *testing.T) {
+		_ = sc.clear()
+		svcA := genExportService("ns-a", "ns-c")
+		svcB := genExportService("ns-b", "ns-c")
+		sc.setServices(map[string]*model.Service{svcA.ID: svcA, svcB.ID: svcB})
+
+		svcA = genExportService("ns-a", "")
+		svcB = genExportService("ns-b", "ns-c")
+		svcB.Valid = false
+		sc.setServices(map[string]*model.Service{svcA.ID: svcA, svcB.ID: svcB})
+		if service := sc.GetExportedService("export-svc", "ns-c"); service != nil {
+			t.Fatalf("error: %+v", service)
+		}
+	})
+}
+
 // TestServiceCache_GetServiceByID 根据服务ID获取服务缓存信息
 func TestServiceCache_GetServiceByID(t *testing.T) {
 	ctl, _, sc, _ := newTestServiceCache(t)
@@ -117,6 +117,7 @@ const (
 	ServiceSubscribedByMeshes       uint32 = 400213
 	ServiceExistedFluxRateLimits    uint32 = 400214
 	NamespaceExistedConfigGroups    uint32 = 400219
+	NamespaceQuotaExceeded          uint32 = 400220
 
 	NotFoundService                    uint32 = 400301
 	NotFoundRouting                    uint32 = 400302
@@ -138,6 +139,7 @@ const (
 	NotAllowAliasCreateRateLimit       uint32 = 400505
 	NotAllowAliasBindRule              uint32 = 400506
 	NotAllowDifferentNamespaceBindRule uint32 = 400507
+	NotAllowModifySyncedInstance       uint32 = 400510
 	Unauthorized                       uint32 = 401000
 	NotAllowedAccess                   uint32 = 401001
 	IPRateLimit                        uint32 = 403001
@@ -164,6 +166,11 @@ const (
 	InvalidWatchConfigFileFormat   uint32 = 400806
 	NotFoundResourceConfigFile     uint32 = 400807
 	InvalidConfigFileTemplateName  uint32 = 400808
+	ConfigFileChangeNotApproved    uint32 = 400809
+	InvalidConfigFileContent       uint32 = 400810
+	InvalidConfigFileSchema        uint32 = 400811
+	InvalidConfigFileTemplate      uint32 = 400812
+	ConfigFileChangeSelfApproval   uint32 = 400813
 
 	// 鉴权相关错误码
 	InvalidUserOwners         uint32 = 400410
@@ -265,6 +272,7 @@ var code2info = map[uint32]string{
 	NotAllowAliasCreateRateLimit:       "not allow service alias creating rate limit",
 	NotAllowAliasBindRule:              "not allow service alias binding rule",
 	NotAllowDifferentNamespaceBindRule: "not allow different namespace binding rule",
+	NotAllowModifySyncedInstance:       "not allow modifying instance synced from other cluster",
 	NamespaceExistedServices:           "some services existed in namespace",
 	ServiceExistedInstances:            "some instances existed in service",
 	ServiceExistedRoutings:             "some routings existed in service",
@@ -326,6 +334,11 @@ var code2info = map[uint32]string{
 	InvalidWatchConfigFileFormat:   "invalid watch config file format",
 	NotFoundResourceConfigFile:     "config file not existed",
 	InvalidConfigFileTemplateName:  "invalid config file template name",
+	ConfigFileChangeNotApproved:    "config file change must be approved before publish",
+	InvalidConfigFileContent:       "invalid config file content",
+	InvalidConfigFileSchema:        "invalid config file json schema",
+	InvalidConfigFileTemplate:      "render config file template failed",
+	ConfigFileChangeSelfApproval:   "config file change can not be approved by its creator",
 
 	// 鉴权错误
 	NotFoundUser:             "not found user",
@@ -357,6 +370,7 @@ var code2info = map[uint32]string{
 	InvalidRoutingName:   "invalid routing name",
 
 	NamespaceExistedConfigGroups: "some config group existed in namespace",
+	NamespaceQuotaExceeded:       "the resources of namespace exceed the quota",
 }
 
 // code to info
@@ -34,6 +34,30 @@ func NewConfigClientResponseWithMessage(code uint32, message string) *ConfigClie
 	}
 }
 
+const (
+	// ConfigWatchStreamActionAdd 新增订阅
+	ConfigWatchStreamActionAdd = "add"
+	// ConfigWatchStreamActionRemove 取消订阅
+	ConfigWatchStreamActionRemove = "remove"
+	// ConfigWatchStreamActionError 上报加载配置失败，watchFiles 中的 md5 为加载失败的配置内容的 md5
+	ConfigWatchStreamActionError = "error"
+)
+
+func NewConfigWatchStreamResponse(code uint32, configFiles []*ClientConfigFileInfo) *ConfigWatchStreamResponse {
+	return &ConfigWatchStreamResponse{
+		Code:        &wrappers.UInt32Value{Value: code},
+		Info:        &wrappers.StringValue{Value: code2info[code]},
+		ConfigFiles: configFiles,
+	}
+}
+
+func NewConfigWatchStreamResponseWithMessage(code uint32, message string) *ConfigWatchStreamResponse {
+	return &ConfigWatchStreamResponse{
+		Code: &wrappers.UInt32Value{Value: code},
+		Info: &wrappers.StringValue{Value: message},
+	}
+}
+
 func NewConfigFileGroupResponse(code uint32, configFileGroup *ConfigFileGroup) *ConfigResponse {
 	return &ConfigResponse{
 		Code:            &wrappers.UInt32Value{Value: code},
@@ -139,3 +163,32 @@ func NewConfigFileReleaseHistoryResponse(code uint32, configFileReleaseHistory *
 		ConfigFileReleaseHistory: configFileReleaseHistory,
 	}
 }
+
+func NewConfigBatchWriteResponse(code uint32) *ConfigBatchWriteResponse {
+	return &ConfigBatchWriteResponse{
+		Code:  &wrappers.UInt32Value{Value: code},
+		Info:  &wrappers.StringValue{Value: code2info[code]},
+		Total: &wrappers.UInt32Value{Value: 0},
+	}
+}
+
+func NewConfigBatchWriteResponseWithMessage(code uint32, message string) *ConfigBatchWriteResponse {
+	return &ConfigBatchWriteResponse{
+		Code:  &wrappers.UInt32Value{Value: code},
+		Info:  &wrappers.StringValue{Value: code2info[code] + ":" + message},
+		Total: &wrappers.UInt32Value{Value: 0},
+	}
+}
+
+// Collect ConfigBatchWriteResponse 添加 ConfigResponse，非 200 的 code 都归为异常
+func (b *ConfigBatchWriteResponse) Collect(response *ConfigResponse) {
+	if CalcCode(response) != 200 {
+		if response.GetCode().GetValue() >= b.GetCode().GetValue() {
+			b.Code.Value = response.GetCode().GetValue()
+			b.Info.Value = code2info[b.GetCode().GetValue()]
+		}
+	}
+
+	b.Total.Value++
+	b.Responses = append(b.Responses, response)
+}
@@ -47,15 +47,24 @@ type HealthCheck_HealthCheckType int32
 const (
 	HealthCheck_UNKNOWN   HealthCheck_HealthCheckType = 0
 	HealthCheck_HEARTBEAT HealthCheck_HealthCheckType = 1
+	HealthCheck_TCP       HealthCheck_HealthCheckType = 2
+	HealthCheck_HTTP      HealthCheck_HealthCheckType = 3
+	HealthCheck_GRPC      HealthCheck_HealthCheckType = 4
 )
 
 var HealthCheck_HealthCheckType_name = map[int32]string{
 	0: "UNKNOWN",
 	1: "HEARTBEAT",
+	2: "TCP",
+	3: "HTTP",
+	4: "GRPC",
 }
 var HealthCheck_HealthCheckType_value = map[string]int32{
 	"UNKNOWN":   0,
 	"HEARTBEAT": 1,
+	"TCP":       2,
+	"HTTP":      3,
+	"GRPC":      4,
 }
 
 func (x HealthCheck_HealthCheckType) String() string {
@@ -81,6 +90,10 @@ type Namespace struct {
 	RemoveGroupIds           []*wrappers.StringValue `protobuf:"bytes,14,rep,name=remove_group_ids,proto3" json:"remove_group_ids,omitempty"`
 	Id                       *wrappers.StringValue   `protobuf:"bytes,12,opt,name=id,proto3" json:"id,omitempty"`
 	Editable                 *wrappers.BoolValue     `protobuf:"bytes,15,opt,name=editable,proto3" json:"editable,omitempty"`
+	ServiceQuota             *wrappers.UInt32Value   `protobuf:"bytes,16,opt,name=service_quota,proto3" json:"service_quota,omitempty"`
+	InstanceQuota            *wrappers.UInt32Value   `protobuf:"bytes,17,opt,name=instance_quota,proto3" json:"instance_quota,omitempty"`
+	ConfigFileQuota          *wrappers.UInt32Value   `protobuf:"bytes,18,opt,name=config_file_quota,proto3" json:"config_file_quota,omitempty"`
+	TotalConfigFileCount     *wrappers.UInt32Value   `protobuf:"bytes,19,opt,name=total_config_file_count,proto3" json:"total_config_file_count,omitempty"`
 	XXX_NoUnkeyedLiteral     struct{}                `json:"-"`
 	XXX_unrecognized         []byte                  `json:"-"`
 	XXX_sizecache            int32                   `json:"-"`
@@ -251,6 +264,34 @@ func (*Service) ProtoMessage()    {}
 func (*Service) Descriptor() ([]byte, []int) {
 	return fileDescriptor_service_35e377566d316886, []int{1}
 }
+
+func (m *Namespace) GetServiceQuota() *wrappers.UInt32Value {
+	if m != nil {
+		return m.ServiceQuota
+	}
+	return nil
+}
+
+func (m *Namespace) GetInstanceQuota() *wrappers.UInt32Value {
+	if m != nil {
+		return m.InstanceQuota
+	}
+	return nil
+}
+
+func (m *Namespace) GetConfigFileQuota() *wrappers.UInt32Value {
+	if m != nil {
+		return m.ConfigFileQuota
+	}
+	return nil
+}
+
+func (m *Namespace) GetTotalConfigFileCount() *wrappers.UInt32Value {
+	if m != nil {
+		return m.TotalConfigFileCount
+	}
+	return nil
+}
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Service.Unmarshal(m, b)
 }
@@ -861,73 +902,78 @@ func init() {
 func init() { proto.RegisterFile("service.proto", fileDescriptor_service_35e377566d316886) }
 
 var fileDescriptor_service_35e377566d316886 = []byte{
-	// 1083 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x98, 0xd1, 0x6e, 0xe3, 0x44,
-	0x14, 0x86, 0x37, 0x49, 0x9b, 0xc4, 0xc7, 0x49, 0x9b, 0x4e, 0xcb, 0x62, 0x0a, 0x82, 0x25, 0xe2,
-	0x62, 0x85, 0x20, 0x4b, 0x93, 0x6e, 0x55, 0x15, 0x84, 0xd4, 0xee, 0xb6, 0x6a, 0xa1, 0x04, 0x94,
-	0xa6, 0x70, 0x87, 0x35, 0xb1, 0x67, 0x13, 0xab, 0xb6, 0xc7, 0xb2, 0x27, 0xa9, 0xf2, 0x38, 0x5c,
-	0xf2, 0x04, 0x3c, 0x09, 0x8f, 0xc0, 0x7b, 0xa0, 0x99, 0xb1, 0x1d, 0x27, 0x4d, 0xb6, 0x13, 0x77,
-	0xef, 0x9c, 0x99, 0xff, 0x3f, 0x63, 0x8d, 0xcf, 0xf9, 0xe6, 0x4c, 0xa0, 0x1e, 0x91, 0x70, 0xe2,
-	0x58, 0xa4, 0x15, 0x84, 0x94, 0x51, 0x54, 0x9c, 0x1c, 0xec, 0x7f, 0x3e, 0xa4, 0x74, 0xe8, 0x92,
-	0x57, 0x62, 0x64, 0x30, 0x7e, 0xf7, 0xea, 0x3e, 0xc4, 0x41, 0x40, 0xc2, 0x48, 0x6a, 0xf6, 0x75,
-	0x8f, 0xda, 0xc4, 0x95, 0x3f, 0x9a, 0xff, 0x54, 0x40, 0xeb, 0x62, 0x8f, 0x44, 0x01, 0xb6, 0x08,
-	0xfa, 0x0e, 0x36, 0x7c, 0xec, 0x11, 0xa3, 0xf0, 0xa2, 0xf0, 0x52, 0x6f, 0x7f, 0xd6, 0x92, 0x91,
-	0x5a, 0x49, 0xa4, 0xd6, 0x0d, 0x0b, 0x1d, 0x7f, 0xf8, 0x3b, 0x76, 0xc7, 0xa4, 0x27, 0x94, 0xe8,
-	0x08, 0x2a, 0x16, 0xf5, 0x3c, 0xe2, 0x33, 0xa3, 0xa8, 0x60, 0x4a, 0xc4, 0xe8, 0x10, 0xca, 0xf4,
-	0xde, 0x27, 0x61, 0x64, 0x94, 0x14, 0x6c, 0xb1, 0x16, 0xb5, 0x61, 0x93, 0xd1, 0x3b, 0xe2, 0x1b,
-	0x1b, 0x0a, 0x26, 0x29, 0xe5, 0x1e, 0x8b, 0x39, 0x1e, 0x31, 0x36, 0x55, 0x3c, 0x42, 0xca, 0x3d,
-	0x9e, 0xf0, 0x94, 0x55, 0x3c, 0x42, 0x8a, 0xba, 0xb0, 0xcb, 0x28, 0xc3, 0xae, 0x19, 0x7f, 0x11,
-	0xd3, 0xa2, 0x63, 0x9f, 0x19, 0x95, 0x15, 0x11, 0x6e, 0xaf, 0x7c, 0xd6, 0x69, 0xcb, 0x08, 0xcb,
-	0x8c, 0xe8, 0x4f, 0xf8, 0x54, 0x0e, 0x8f, 0x08, 0x76, 0xd9, 0xc8, 0x74, 0xfc, 0x88, 0x61, 0x3f,
-	0x8d, 0x5b, 0x55, 0x88, 0xfb, 0xbe, 0x00, 0xe8, 0x37, 0xd8, 0x93, 0xd3, 0x0b, 0x81, 0x35, 0x85,
-	0xc0, 0x4b, 0x9d, 0xe8, 0x18, 0xaa, 0xe3, 0x88, 0x84, 0xa6, 0x63, 0x47, 0x06, 0xbc, 0x28, 0x3d,
-	0xba, 0x71, 0xa9, 0x1a, 0x9d, 0x80, 0x36, 0x0c, 0xe9, 0x38, 0x10, 0x56, 0x5d, 0xc1, 0x3a, 0x93,
-	0xa3, 0x0b, 0xd8, 0x0e, 0x89, 0x47, 0x27, 0xc4, 0x4c, 0x17, 0xaf, 0x2b, 0x44, 0x58, 0x34, 0xa1,
-	0x4b, 0x68, 0xc4, 0x43, 0xb3, 0x57, 0xd9, 0x52, 0x08, 0xf4, 0xc0, 0x85, 0xbe, 0x81, 0xa2, 0x63,
-	0x1b, 0x35, 0x85, 0xd4, 0x29, 0x3a, 0x36, 0x3a, 0x82, 0x2a, 0xb1, 0x1d, 0x86, 0x07, 0x2e, 0x31,
-	0xb6, 0x85, 0x67, 0xff, 0x81, 0xe7, 0x8c, 0x52, 0x37, 0xde, 0xb3, 0x44, 0xdb, 0xfc, 0x4f, 0x87,
-	0xca, 0x8d, 0xcc, 0x98, 0x1c, 0x75, 0x7b, 0x02, 0x9a, 0x9f, 0x94, 0xbd, 0x52, 0xe5, 0xce, 0xe4,
-	0xe8, 0x35, 0x54, 0x3d, 0xc2, 0xb0, 0x8d, 0x19, 0x36, 0x4a, 0x62, 0x87, 0x3e, 0x69, 0x4d, 0x0e,
-	0x5a, 0xf1, 0xcb, 0xb4, 0x7e, 0x89, 0xe7, 0xce, 0x7d, 0x16, 0x4e, 0x7b, 0xa9, 0x94, 0x17, 0x55,
-	0x40, 0x43, 0x16, 0xa9, 0x15, 0xaf, 0x90, 0xf2, 0x94, 0x1a, 0x8c, 0x23, 0xc7, 0x27, 0x51, 0xa4,
-	0x54, 0xbf, 0xa9, 0x1a, 0xfd, 0x00, 0x60, 0x93, 0x00, 0x87, 0x4c, 0xb0, 0x49, 0xa5, 0x8e, 0x33,
-	0x7a, 0xbe, 0x3d, 0x96, 0x67, 0x0f, 0x4c, 0x8f, 0xda, 0x07, 0x2b, 0x4b, 0x78, 0x6e, 0x7b, 0x52,
-	0x79, 0xd6, 0xdb, 0x5e, 0x59, 0xa6, 0x4b, 0xbd, 0xed, 0xac, 0xb7, 0xb3, 0xb2, 0x12, 0x97, 0x7a,
-	0x3b, 0x59, 0x14, 0x43, 0x3e, 0x14, 0xeb, 0x79, 0x50, 0x5c, 0xcb, 0x81, 0xe2, 0x7a, 0x0e, 0x14,
-	0x6f, 0xa9, 0xa3, 0xf8, 0x18, 0xaa, 0x21, 0x99, 0x38, 0x91, 0x43, 0xfd, 0xb8, 0xa4, 0x1e, 0xc9,
-	0x9a, 0x44, 0x8d, 0x7e, 0x04, 0x3d, 0x70, 0x31, 0x7b, 0x47, 0x43, 0xcf, 0x74, 0x6c, 0xa3, 0xa1,
-	0x60, 0xce, 0x1a, 0x56, 0x42, 0x75, 0x27, 0x37, 0x54, 0xfb, 0xf0, 0x5c, 0xf2, 0x7b, 0xba, 0x18,
-	0x13, 0x29, 0xc4, 0x5c, 0xe1, 0x9d, 0x43, 0xf5, 0x6e, 0x7e, 0x54, 0xef, 0x3d, 0x19, 0xd5, 0xcf,
-	0x3f, 0x14, 0xaa, 0x3f, 0x7e, 0x02, 0xaa, 0x3f, 0xca, 0x81, 0x6a, 0x43, 0x1d, 0xd5, 0xfb, 0xdf,
-	0x43, 0x7d, 0x0e, 0x8a, 0xa8, 0x01, 0xa5, 0x3b, 0x32, 0x15, 0xb8, 0xd6, 0x7a, 0xfc, 0x11, 0xed,
-	0xc1, 0xe6, 0x84, 0xbb, 0x04, 0x8b, 0xb5, 0x9e, 0xfc, 0x71, 0x52, 0x3c, 0x2e, 0x34, 0xff, 0xda,
-	0x84, 0x5a, 0x8c, 0xd6, 0x53, 0xd7, 0xc1, 0x11, 0xaf, 0xf3, 0xb8, 0x53, 0x50, 0xe2, 0x7d, 0x22,
-	0x7e, 0x12, 0xf2, 0xdb, 0xb0, 0x89, 0xf9, 0xe2, 0x4a, 0xdd, 0x9a, 0x94, 0xf2, 0xaf, 0x2d, 0x1e,
-	0xcc, 0xd9, 0xaa, 0x2a, 0xe4, 0x5f, 0x34, 0xa1, 0x2f, 0x61, 0x83, 0x4d, 0x03, 0xd9, 0xbf, 0x6d,
-	0xb5, 0xeb, 0xfc, 0xa8, 0x11, 0x1b, 0xd1, 0x9f, 0x06, 0xa4, 0x27, 0xa6, 0x32, 0x08, 0x2b, 0xaf,
-	0x81, 0xb0, 0x0c, 0x30, 0x2b, 0xeb, 0x00, 0xf3, 0x2c, 0xed, 0xba, 0x4d, 0x89, 0x40, 0x15, 0xc8,
-	0xcf, 0x5b, 0x66, 0x28, 0xd4, 0x72, 0xa0, 0x10, 0xd4, 0x51, 0x28, 0x13, 0x5c, 0xcf, 0x91, 0xe0,
-	0xb5, 0x35, 0x7a, 0x91, 0x7f, 0x35, 0xa8, 0x5e, 0xc5, 0x84, 0x89, 0x97, 0x2c, 0x28, 0x2f, 0x99,
-	0x66, 0x73, 0x31, 0x77, 0x36, 0x97, 0xd6, 0xcb, 0xe6, 0x43, 0x28, 0x4f, 0x02, 0xcb, 0x54, 0xac,
-	0xfc, 0x58, 0xcb, 0x9b, 0xac, 0x11, 0x8d, 0x98, 0x52, 0x12, 0x0b, 0x25, 0x77, 0xf0, 0x36, 0x66,
-	0x65, 0xe7, 0x92, 0x25, 0xb5, 0x50, 0x72, 0x2e, 0x8b, 0x59, 0x8b, 0xba, 0x4a, 0xa9, 0x9c, 0xaa,
-	0xf9, 0x3e, 0x4e, 0x48, 0x28, 0x8e, 0x3c, 0xa5, 0x64, 0x8e, 0xc5, 0x72, 0x45, 0x87, 0x86, 0x0e,
-	0x9b, 0x2a, 0xdd, 0x29, 0x52, 0x35, 0xdf, 0xc5, 0x7b, 0xe2, 0x0c, 0x47, 0x6a, 0x57, 0x86, 0x58,
-	0x8b, 0x7e, 0x82, 0x5d, 0xe2, 0xf3, 0xa4, 0x49, 0xae, 0x25, 0xd6, 0x88, 0x58, 0x77, 0xc6, 0xde,
-	0xa3, 0xd9, 0xb6, 0x23, 0x6d, 0x97, 0xc2, 0xf5, 0x86, 0x9b, 0x50, 0x1b, 0x6a, 0x73, 0x41, 0x64,
-	0x5d, 0x6c, 0x73, 0x42, 0x64, 0x64, 0x3d, 0x7d, 0x94, 0xf1, 0x1c, 0x42, 0x25, 0x3e, 0x13, 0xe3,
-	0xaa, 0x78, 0xdf, 0x9a, 0x89, 0x94, 0xbb, 0x9c, 0x88, 0xba, 0x98, 0xa9, 0xd4, 0x45, 0x22, 0x45,
-	0x2f, 0xa1, 0xea, 0x52, 0x0b, 0x33, 0xfe, 0x51, 0x64, 0xcb, 0x53, 0xe3, 0xef, 0x76, 0x1d, 0x8f,
-	0xf5, 0xd2, 0x59, 0x5e, 0x78, 0x69, 0x4b, 0x2d, 0x2f, 0x1d, 0xfb, 0x5c, 0x99, 0xd4, 0xd4, 0xca,
-	0x9e, 0xfa, 0x04, 0x34, 0x97, 0x0e, 0x1d, 0xcb, 0x8c, 0x08, 0x53, 0x6a, 0x75, 0x66, 0xf2, 0x19,
-	0x82, 0x1a, 0x39, 0x10, 0xb4, 0x93, 0xaf, 0x1b, 0x43, 0x6b, 0x75, 0x63, 0x0f, 0x40, 0xbb, 0xbb,
-	0x36, 0x68, 0x9f, 0x76, 0xf6, 0xfe, 0x5d, 0x00, 0x3d, 0x9b, 0x70, 0x9d, 0xf8, 0x28, 0x2a, 0x88,
-	0xa3, 0xe8, 0x8b, 0x85, 0x44, 0xcb, 0x3e, 0x67, 0x0e, 0xa7, 0x23, 0xd0, 0x46, 0x04, 0x87, 0x6c,
-	0x40, 0x70, 0xf2, 0x27, 0x89, 0x11, 0x3b, 0xe5, 0x60, 0x36, 0x57, 0x67, 0xd2, 0xe6, 0xb7, 0xb0,
-	0xbd, 0x10, 0x10, 0xe9, 0x50, 0xb9, 0xed, 0xfe, 0xdc, 0xfd, 0xf5, 0x8f, 0x6e, 0xe3, 0x19, 0xaa,
-	0x83, 0x76, 0x79, 0x7e, 0xda, 0xeb, 0x9f, 0x9d, 0x9f, 0xf6, 0x1b, 0x85, 0xe6, 0x05, 0xec, 0x2d,
-	0x8b, 0x88, 0x5a, 0x50, 0x62, 0xcc, 0x5d, 0xc9, 0xe3, 0x6c, 0x8d, 0x72, 0xe1, 0xd7, 0x5f, 0x81,
-	0x96, 0x1e, 0xaf, 0x7c, 0xc1, 0xb7, 0xe7, 0x17, 0xa7, 0xb7, 0xd7, 0xfd, 0xc6, 0x33, 0x04, 0x50,
-	0x7e, 0x73, 0xfd, 0xfa, 0xe6, 0xea, 0x6d, 0xa3, 0x30, 0x28, 0x8b, 0x00, 0x9d, 0xff, 0x03, 0x00,
-	0x00, 0xff, 0xff, 0xff, 0x5a, 0x9c, 0x82, 0x80, 0x12, 0x00, 0x00,
+	// 1153 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xed, 0x52, 0xe3, 0x36,
+	0x1b, 0xdd, 0x24, 0xe4, 0xc3, 0x8f, 0x13, 0x30, 0x82, 0x77, 0x57, 0x2f, 0xed, 0xb4, 0xdb, 0x4c,
+	0x7f, 0xec, 0x74, 0x3a, 0xd9, 0x12, 0x58, 0x86, 0xa1, 0x9d, 0xce, 0xf0, 0x59, 0xa0, 0x94, 0x32,
+	0x21, 0x6c, 0xff, 0xd5, 0xe3, 0x38, 0x22, 0xf1, 0x60, 0x5b, 0xae, 0xad, 0x84, 0xc9, 0xe5, 0xf4,
+	0x66, 0x7a, 0x07, 0xbd, 0x84, 0xbd, 0x8f, 0x8e, 0x24, 0xdb, 0x31, 0x81, 0x14, 0xc5, 0xf4, 0x9f,
+	0x23, 0x9d, 0x73, 0x64, 0xa4, 0x47, 0xe7, 0x3c, 0x06, 0x1a, 0x11, 0x09, 0xc7, 0x8e, 0x4d, 0x5a,
+	0x41, 0x48, 0x19, 0x45, 0xc5, 0xf1, 0xe6, 0xc6, 0x17, 0x03, 0x4a, 0x07, 0x2e, 0x79, 0x2f, 0x46,
+	0x7a, 0xa3, 0xdb, 0xf7, 0xf7, 0xa1, 0x15, 0x04, 0x24, 0x8c, 0x24, 0x66, 0x43, 0xf7, 0x68, 0x9f,
+	0xb8, 0xf2, 0x47, 0xf3, 0x93, 0x06, 0xda, 0xa5, 0xe5, 0x91, 0x28, 0xb0, 0x6c, 0x82, 0xbe, 0x83,
+	0x25, 0xdf, 0xf2, 0x08, 0x2e, 0xbc, 0x2d, 0xbc, 0xd3, 0xdb, 0x9f, 0xb7, 0xa4, 0x52, 0x2b, 0x51,
+	0x6a, 0x5d, 0xb3, 0xd0, 0xf1, 0x07, 0x1f, 0x2d, 0x77, 0x44, 0x3a, 0x02, 0x89, 0x76, 0xa0, 0x6a,
+	0x53, 0xcf, 0x23, 0x3e, 0xc3, 0x45, 0x05, 0x52, 0x02, 0x46, 0xdb, 0x50, 0xa1, 0xf7, 0x3e, 0x09,
+	0x23, 0x5c, 0x52, 0xa0, 0xc5, 0x58, 0xd4, 0x86, 0x32, 0xa3, 0x77, 0xc4, 0xc7, 0x4b, 0x0a, 0x24,
+	0x09, 0xe5, 0x1c, 0x9b, 0x39, 0x1e, 0xc1, 0x65, 0x15, 0x8e, 0x80, 0x72, 0x8e, 0x27, 0x38, 0x15,
+	0x15, 0x8e, 0x80, 0xa2, 0x4b, 0x58, 0x63, 0x94, 0x59, 0xae, 0x19, 0x9f, 0x88, 0x69, 0xd3, 0x91,
+	0xcf, 0x70, 0x75, 0x8e, 0xc2, 0xcd, 0x99, 0xcf, 0xb6, 0xda, 0x52, 0xe1, 0x29, 0x22, 0xfa, 0x1d,
+	0x3e, 0x93, 0xc3, 0x43, 0x62, 0xb9, 0x6c, 0x68, 0x3a, 0x7e, 0xc4, 0x2c, 0x3f, 0xd5, 0xad, 0x29,
+	0xe8, 0xfe, 0x9b, 0x00, 0xba, 0x82, 0x75, 0x39, 0x3d, 0x23, 0xac, 0x29, 0x08, 0x3f, 0xc9, 0x44,
+	0xbb, 0x50, 0x1b, 0x45, 0x24, 0x34, 0x9d, 0x7e, 0x84, 0xe1, 0x6d, 0xe9, 0xd9, 0x8d, 0x4b, 0xd1,
+	0x68, 0x0f, 0xb4, 0x41, 0x48, 0x47, 0x81, 0xa0, 0xea, 0x0a, 0xd4, 0x29, 0x1c, 0x9d, 0xc0, 0x4a,
+	0x48, 0x3c, 0x3a, 0x26, 0x66, 0xba, 0x78, 0x43, 0x41, 0x61, 0x96, 0x84, 0x4e, 0xc1, 0x88, 0x87,
+	0xa6, 0xaf, 0xb2, 0xac, 0x20, 0xf4, 0x88, 0x85, 0xbe, 0x85, 0xa2, 0xd3, 0xc7, 0x75, 0x85, 0xd2,
+	0x29, 0x3a, 0x7d, 0xb4, 0x03, 0x35, 0xd2, 0x77, 0x98, 0xd5, 0x73, 0x09, 0x5e, 0x11, 0x9c, 0x8d,
+	0x47, 0x9c, 0x03, 0x4a, 0xdd, 0x78, 0xcf, 0x12, 0x2c, 0x3a, 0x48, 0xef, 0xbe, 0xf9, 0xc7, 0x88,
+	0x32, 0x0b, 0x1b, 0x0a, 0x07, 0xf7, 0x90, 0x82, 0x8e, 0x60, 0x39, 0x3d, 0x43, 0x29, 0xb2, 0xaa,
+	0x20, 0x32, 0xc3, 0x41, 0xe7, 0xb0, 0x6a, 0x53, 0xff, 0xd6, 0x19, 0x98, 0xb7, 0x8e, 0x9b, 0x08,
+	0x21, 0x05, 0xa1, 0xc7, 0x34, 0xf4, 0x11, 0xde, 0xc8, 0xda, 0xca, 0x4e, 0xc9, 0xc2, 0x5c, 0x53,
+	0x50, 0x9c, 0x47, 0x6e, 0x7e, 0xd2, 0xa1, 0x7a, 0x2d, 0xff, 0xf6, 0x1c, 0x2e, 0xb7, 0x07, 0x9a,
+	0x9f, 0x98, 0xa4, 0x92, 0xcf, 0x4d, 0xe1, 0xe8, 0x03, 0xd4, 0x3c, 0xc2, 0xac, 0xbe, 0xc5, 0x2c,
+	0x5c, 0x12, 0xf5, 0xf4, 0xff, 0xd6, 0x78, 0xb3, 0x15, 0xbf, 0x4c, 0xeb, 0x97, 0x78, 0xee, 0xd8,
+	0x67, 0xe1, 0xa4, 0x93, 0x42, 0xb9, 0x05, 0x05, 0x34, 0x64, 0x91, 0x9a, 0xd5, 0x09, 0x28, 0xbf,
+	0x80, 0xbd, 0x51, 0xe4, 0xf8, 0x24, 0x8a, 0x94, 0xdc, 0x2e, 0x45, 0xa3, 0x1f, 0x00, 0xfa, 0x24,
+	0xb0, 0x42, 0x26, 0x9c, 0x5c, 0xc5, 0xf5, 0x32, 0x78, 0xbe, 0x3d, 0xb6, 0xd7, 0xef, 0x99, 0x1e,
+	0xed, 0x6f, 0xe2, 0xaa, 0x02, 0x79, 0x0a, 0xcf, 0x72, 0xdb, 0xb8, 0xb6, 0x08, 0xb7, 0x9d, 0xe5,
+	0x6e, 0x61, 0x6d, 0x11, 0xee, 0x56, 0x36, 0xb8, 0x20, 0x5f, 0x70, 0xe9, 0x79, 0x82, 0xab, 0x9e,
+	0x23, 0xb8, 0x1a, 0x39, 0x82, 0x6b, 0x59, 0x3d, 0xb8, 0x76, 0xa1, 0x16, 0x92, 0xb1, 0x13, 0x39,
+	0xd4, 0xc7, 0x2b, 0x0a, 0xb4, 0x14, 0x8d, 0x7e, 0x04, 0x3d, 0x70, 0x2d, 0x76, 0x4b, 0x43, 0xcf,
+	0x74, 0xfa, 0xd8, 0x50, 0x20, 0x67, 0x09, 0x73, 0x23, 0x68, 0x35, 0x77, 0x04, 0x75, 0xe1, 0xb5,
+	0x4c, 0xbb, 0xc9, 0xac, 0xa6, 0x8a, 0x1f, 0xcd, 0xe1, 0x3e, 0x08, 0xb6, 0xb5, 0xfc, 0xc1, 0xb6,
+	0xfe, 0xe2, 0x60, 0x7b, 0xfd, 0x5f, 0x05, 0xdb, 0x9b, 0x17, 0x04, 0xdb, 0xff, 0x72, 0x04, 0x1b,
+	0x56, 0x0f, 0xb6, 0x8d, 0xef, 0xa1, 0xf1, 0xc0, 0x14, 0x91, 0x01, 0xa5, 0x3b, 0x32, 0x11, 0x76,
+	0xad, 0x75, 0xf8, 0x23, 0x5a, 0x87, 0xf2, 0x98, 0xb3, 0x84, 0x17, 0x6b, 0x1d, 0xf9, 0x63, 0xaf,
+	0xb8, 0x5b, 0x68, 0xfe, 0x59, 0x86, 0x7a, 0x6c, 0xad, 0xfb, 0xae, 0x63, 0x45, 0xfc, 0x9e, 0xc7,
+	0x99, 0xa7, 0xe4, 0xf7, 0x09, 0xf8, 0x45, 0x96, 0xdf, 0x86, 0xb2, 0xc5, 0x17, 0x57, 0xea, 0x6d,
+	0x25, 0x94, 0x9f, 0xb6, 0x78, 0x30, 0xa7, 0xab, 0xaa, 0x38, 0xff, 0x2c, 0x09, 0x7d, 0x05, 0x4b,
+	0x6c, 0x12, 0xc8, 0x6e, 0x77, 0xb9, 0xdd, 0xe0, 0x51, 0x23, 0x36, 0xa2, 0x3b, 0x09, 0x48, 0x47,
+	0x4c, 0x65, 0x2c, 0xac, 0xb2, 0x80, 0x85, 0x65, 0x0c, 0xb3, 0xba, 0x88, 0x61, 0x66, 0xfa, 0x14,
+	0x69, 0x81, 0x2a, 0x26, 0xff, 0x90, 0x32, 0xb5, 0x42, 0x2d, 0x87, 0x15, 0x82, 0xba, 0x15, 0xca,
+	0x02, 0xd7, 0x73, 0x14, 0x78, 0x5d, 0xbd, 0xc0, 0x9b, 0x7f, 0x6b, 0x50, 0x3b, 0x8b, 0x1d, 0x26,
+	0x5e, 0xb2, 0xa0, 0xbc, 0x64, 0x5a, 0xcd, 0xc5, 0xdc, 0xd5, 0x5c, 0x5a, 0xac, 0x9a, 0xb7, 0xa1,
+	0x32, 0x0e, 0x6c, 0x53, 0xf1, 0xe6, 0xc7, 0x58, 0xde, 0x64, 0x0d, 0x69, 0xc4, 0x94, 0x8a, 0x58,
+	0x20, 0x39, 0x83, 0xb7, 0x31, 0xb8, 0xac, 0xe0, 0xd4, 0x02, 0xc9, 0x7d, 0x59, 0xcc, 0xda, 0xd4,
+	0x55, 0x2a, 0xe5, 0x14, 0xcd, 0xf7, 0x71, 0x4c, 0x42, 0x11, 0x79, 0x4a, 0xc5, 0x1c, 0x83, 0xe5,
+	0x8a, 0x0e, 0x0d, 0x1d, 0x36, 0x51, 0xfa, 0x02, 0x4b, 0xd1, 0x7c, 0x17, 0xef, 0x89, 0x33, 0x18,
+	0xaa, 0x7d, 0x60, 0xc5, 0x58, 0x74, 0x0e, 0x6b, 0xc4, 0xe7, 0x45, 0x93, 0x7c, 0xc4, 0xd9, 0x43,
+	0x62, 0xdf, 0xe1, 0xf5, 0x67, 0xab, 0x6d, 0x55, 0xd2, 0x4e, 0x05, 0xeb, 0x90, 0x93, 0x50, 0x1b,
+	0xea, 0x0f, 0x44, 0xe4, 0xbd, 0x58, 0xe1, 0x0e, 0x91, 0x81, 0x75, 0xf4, 0x61, 0x86, 0xb3, 0x0d,
+	0xd5, 0x38, 0x13, 0xb1, 0xfe, 0xec, 0x9a, 0x09, 0x94, 0xb3, 0x9c, 0x88, 0xba, 0x16, 0x53, 0xb9,
+	0x17, 0x09, 0x14, 0xbd, 0x83, 0x9a, 0x4b, 0x6d, 0x8b, 0xf1, 0x43, 0x91, 0x2d, 0x4f, 0x9d, 0xbf,
+	0xdb, 0x45, 0x3c, 0xd6, 0x49, 0x67, 0xf9, 0xc5, 0x4b, 0x5b, 0x6a, 0xf9, 0x89, 0xb6, 0xc1, 0x91,
+	0xc9, 0x9d, 0x9a, 0xdb, 0x53, 0xef, 0x81, 0xe6, 0xd2, 0x81, 0x63, 0x9b, 0x11, 0x61, 0x4a, 0xad,
+	0xce, 0x14, 0x3e, 0xb5, 0x20, 0x23, 0x87, 0x05, 0xad, 0xe6, 0xeb, 0xc6, 0xd0, 0x42, 0xdd, 0xd8,
+	0x23, 0xa3, 0x5d, 0x5b, 0xd8, 0x68, 0x5f, 0x96, 0xbd, 0x7f, 0x15, 0x40, 0xcf, 0x16, 0xdc, 0x56,
+	0x1c, 0x45, 0x05, 0x11, 0x45, 0x5f, 0xce, 0x14, 0x5a, 0xf6, 0x39, 0x13, 0x4e, 0x3b, 0xa0, 0x0d,
+	0x89, 0x15, 0xb2, 0x1e, 0xb1, 0x92, 0x7f, 0x29, 0xe1, 0x98, 0x29, 0x07, 0xb3, 0xb5, 0x3a, 0x85,
+	0x36, 0xcf, 0x61, 0x65, 0x46, 0x10, 0xe9, 0x50, 0xbd, 0xb9, 0xfc, 0xf9, 0xf2, 0xd7, 0xdf, 0x2e,
+	0x8d, 0x57, 0xa8, 0x01, 0xda, 0xe9, 0xf1, 0x7e, 0xa7, 0x7b, 0x70, 0xbc, 0xdf, 0x35, 0x0a, 0xa8,
+	0x0a, 0xa5, 0xee, 0xe1, 0x95, 0x51, 0x44, 0x35, 0x58, 0x3a, 0xed, 0x76, 0xaf, 0x8c, 0x12, 0x7f,
+	0xfa, 0xa9, 0x73, 0x75, 0x68, 0x2c, 0x35, 0x4f, 0x60, 0xfd, 0xa9, 0xe5, 0x50, 0x0b, 0x4a, 0x8c,
+	0xb9, 0xb8, 0xa0, 0x70, 0x81, 0x39, 0xf0, 0x9b, 0xaf, 0x41, 0x4b, 0xb3, 0x97, 0xbf, 0xcd, 0xd1,
+	0xf1, 0xc9, 0xfe, 0xcd, 0x45, 0xd7, 0x78, 0x85, 0x00, 0x2a, 0x87, 0x17, 0x1f, 0xae, 0xcf, 0x8e,
+	0x8c, 0x42, 0xaf, 0x22, 0x04, 0xb6, 0xfe, 0x19, 0x00, 0x60, 0x49, 0xe8, 0x52, 0xcb, 0x13, 0x00,
+	0x00,
 }
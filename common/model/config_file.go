@@ -71,6 +71,62 @@ type ConfigFileRelease struct {
 	Valid      bool
 }
 
+// ConfigFileGrayRelease 配置文件灰度发布数据持久化对象，一个配置文件同一时间最多只有一个灰度发布
+type ConfigFileGrayRelease struct {
+	Id        uint64
+	Name      string
+	Namespace string
+	Group     string
+	FileName  string
+	Content   string
+	Comment   string
+	Md5       string
+	Version   uint64
+	// Rule 灰度规则，JSON 格式的 GrayRule
+	Rule string
+	// ErrorCount 客户端上报的灰度发布内容加载失败次数
+	ErrorCount uint32
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+	Valid      bool
+}
+
+// GrayRule 灰度规则，客户端 IP 命中 ClientIPs、客户端标签命中全部 Labels，或者客户端落在 Percentage 比例内时，
+// 客户端获取灰度发布的内容
+type GrayRule struct {
+	// ClientIPs 客户端 IP 列表，支持 CIDR 格式
+	ClientIPs []string `json:"clientIps,omitempty"`
+	// Labels 客户端标签，来自客户端通过 ReportClient 上报的信息
+	Labels map[string]string `json:"labels,omitempty"`
+	// Percentage 按比例灰度，取值 1~100，根据客户端 IP 的哈希值稳定地选中该比例的客户端
+	Percentage int `json:"percentage,omitempty"`
+	// MaxErrors 客户端上报的加载失败次数达到该值时自动取消灰度发布，为 0 时不自动取消
+	MaxErrors uint32 `json:"maxErrors,omitempty"`
+}
+
+// ConfigFileChange 配置文件变更数据持久化对象，开启变更审核后编辑配置文件会生成待审核的变更，审核通过后才能发布
+type ConfigFileChange struct {
+	Id        uint64
+	Namespace string
+	Group     string
+	FileName  string
+	// Content 变更后的配置文件内容，与配置文件的存储内容一致，加密的配置文件为密文
+	Content string
+	// Diff 变更内容相对于最新发布内容的 unified diff，加密的配置文件为密文
+	Diff          string
+	Md5           string
+	Status        string
+	ReviewBy      string
+	ReviewComment string
+	CreateTime    time.Time
+	CreateBy      string
+	ModifyTime    time.Time
+	ModifyBy      string
+	Valid         bool
+}
+
 // ConfigFileReleaseHistory 配置文件发布历史记录数据持久化对象
 type ConfigFileReleaseHistory struct {
 	Id         uint64
@@ -92,6 +148,27 @@ type ConfigFileReleaseHistory struct {
 	Valid      bool
 }
 
+// ConfigFileClientPin 客户端版本固定数据持久化对象，被固定的客户端只获取固定的发布内容，不再感知后续的发布
+type ConfigFileClientPin struct {
+	Id        uint64
+	Namespace string
+	Group     string
+	FileName  string
+	// Client 被固定的客户端，客户端 ID 或者客户端 IP
+	Client string
+	// HistoryId 固定的发布历史记录
+	HistoryId uint64
+	// Content 固定的发布内容，与发布历史记录的内容一致，加密的配置文件为密文
+	Content    string
+	Md5        string
+	Comment    string
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+	Valid      bool
+}
+
 // ConfigFileTag 配置文件标签数据持久化对象
 type ConfigFileTag struct {
 	Id         uint64
@@ -119,3 +196,70 @@ type ConfigFileTemplate struct {
 	ModifyTime time.Time
 	ModifyBy   string
 }
+
+// ConfigFileSchema config file json schema data object
+type ConfigFileSchema struct {
+	Id         uint64
+	Name       string
+	Content    string
+	Comment    string
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+}
+
+// ConfigFileVariable 配置文件模板变量，同一个命名空间下的变量组成变量集合，发布时替换配置文件中的占位符
+type ConfigFileVariable struct {
+	Id         uint64
+	Namespace  string
+	Name       string
+	Type       string
+	Value      string
+	Comment    string
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+}
+
+// ConfigFileWebhook 配置文件变更订阅，Group、FileName 为空时订阅命名空间或者分组下的全部配置文件
+type ConfigFileWebhook struct {
+	Id        uint64
+	Name      string
+	Namespace string
+	Group     string
+	FileName  string
+	Url       string
+	// Secret 用于对推送内容进行 HMAC-SHA256 签名
+	Secret string
+	// Events 订阅的事件类型，多个事件以逗号分隔
+	Events     string
+	Enable     bool
+	Comment    string
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+}
+
+// ConfigFileWebhookDelivery 配置文件变更推送记录，同一个 webhook 的同一个事件只会推送一次
+type ConfigFileWebhookDelivery struct {
+	Id        uint64
+	WebhookId uint64
+	// EventKey 事件的唯一标识，集群中多个节点同时处理同一个事件时，只有一个节点能够创建推送记录
+	EventKey     string
+	EventType    string
+	Namespace    string
+	Group        string
+	FileName     string
+	Payload      string
+	Status       string
+	Attempts     int
+	ResponseCode int
+	Error        string
+	// NextRetryTime 待推送的记录在该时间之后可以被任意节点认领推送
+	NextRetryTime time.Time
+	CreateTime    time.Time
+	ModifyTime    time.Time
+}
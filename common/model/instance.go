@@ -37,6 +37,9 @@ type Instance struct {
 	ModifyTime time.Time
 	// FirstRegis Whether the label instance is the first registration
 	FirstRegis bool
+	// SkipQuotaCheck The cached instance count of the namespace is far below the quota,
+	// the store does not need to lock the namespace and count instances when adding the instance
+	SkipQuotaCheck bool
 }
 
 // ID get id
@@ -167,6 +170,17 @@ func (i *Instance) Metadata() map[string]string {
 	return i.Proto.GetMetadata()
 }
 
+// SyncSourceCluster get the name of cluster which the instance is synced from,
+// empty means the instance is registered in local cluster
+func (i *Instance) SyncSourceCluster() string {
+	return i.Metadata()[MetaKeySyncSourceCluster]
+}
+
+// IsSynced whether the instance is synced from other cluster
+func (i *Instance) IsSynced() bool {
+	return i.SyncSourceCluster() != ""
+}
+
 // LogicSet get logic set
 func (i *Instance) LogicSet() string {
 	if i.Proto == nil {
@@ -23,4 +23,38 @@ const (
 
 	// MetaKeyBuildRevision build revision for server
 	MetaKeyBuildRevision = "build-revision"
+
+	// MetaKeyHealthCheckPort port used by active probe, default is the instance port
+	MetaKeyHealthCheckPort = "internal-healthcheck-port"
+
+	// MetaKeyHealthCheckHTTPPath request path used by http probe
+	MetaKeyHealthCheckHTTPPath = "internal-healthcheck-http-path"
+
+	// MetaKeyHealthCheckHTTPStatus expected status of http probe, like 200 or 2xx
+	MetaKeyHealthCheckHTTPStatus = "internal-healthcheck-http-status"
+
+	// MetaKeyHealthCheckGRPCService service name sent in grpc health check request
+	MetaKeyHealthCheckGRPCService = "internal-healthcheck-grpc-service"
+
+	// MetaKeyProtectThreshold instance protection threshold of service, between 0 and 1.
+	// When the ratio of healthy instances drops below it, discovery returns all non-isolated instances
+	MetaKeyProtectThreshold = "internal-protect-threshold"
+
+	// MetaKeyProtectionEngaged set on the service of discover response when instance protection is engaged
+	MetaKeyProtectionEngaged = "internal-protection-engaged"
+
+	// MetaKeyServiceExportTo namespaces which the service is exported to, separated by comma, "*" means all namespaces.
+	// Clients in these namespaces can discover the service by its name as if it were a local one
+	MetaKeyServiceExportTo = "internal-export-to"
+
+	// ExportToAllNamespaces export the service to all namespaces
+	ExportToAllNamespaces = "*"
+
+	// MetaKeySyncSourceCluster name of the remote cluster which the instance is synced from.
+	// Synced instances are read only in local cluster and never synced back to other clusters
+	MetaKeySyncSourceCluster = "internal-sync-source-cluster"
+
+	// MetaKeyPreferLocalInstances whether discovery of the service prefers instances registered in local cluster,
+	// "true" or "false", overrides the preferLocal option of naming sync
+	MetaKeyPreferLocalInstances = "internal-prefer-local-instances"
 )
@@ -20,6 +20,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
@@ -37,6 +38,12 @@ type Namespace struct {
 	Valid      bool
 	CreateTime time.Time
 	ModifyTime time.Time
+	// ServiceQuota 命名空间下服务数量的上限，0 表示不限制
+	ServiceQuota uint32
+	// InstanceQuota 命名空间下实例数量的上限，0 表示不限制
+	InstanceQuota uint32
+	// ConfigFileQuota 命名空间下配置文件数量的上限，0 表示不限制
+	ConfigFileQuota uint32
 }
 
 // Business 业务结构体
@@ -95,6 +102,34 @@ func (s *Service) IsAlias() bool {
 	return s.Reference != ""
 }
 
+// ExportTo 服务导出的目标命名空间列表，没有导出时返回空
+func (s *Service) ExportTo() []string {
+	value := s.Meta[MetaKeyServiceExportTo]
+	if value == "" {
+		return nil
+	}
+	namespaces := make([]string, 0, 1)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			namespaces = append(namespaces, item)
+		}
+	}
+	return namespaces
+}
+
+// IsExportedTo 服务是否导出到了指定的命名空间，服务所在的命名空间本身不算作导出
+func (s *Service) IsExportedTo(namespace string) bool {
+	if namespace == "" || namespace == s.Namespace {
+		return false
+	}
+	for _, item := range s.ExportTo() {
+		if item == ExportToAllNamespaces || item == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // ServiceAlias 服务别名结构体
 type ServiceAlias struct {
 	ID             string
@@ -520,6 +555,10 @@ const (
 	EventInstanceCloseIsolate DiscoverEventType = "InstanceCloseIsolate"
 	// EventInstanceOffline Instance offline
 	EventInstanceOffline DiscoverEventType = "InstanceOffline"
+	// EventServiceProtectionEngaged Healthy ratio of service drops below the protection threshold
+	EventServiceProtectionEngaged DiscoverEventType = "ServiceProtectionEngaged"
+	// EventServiceProtectionReleased Healthy ratio of service recovers above the protection threshold
+	EventServiceProtectionReleased DiscoverEventType = "ServiceProtectionReleased"
 )
 
 // DiscoverEvent 服务发现事件
@@ -107,3 +107,25 @@ func TestRateLimit_Arguments2Labels(t *testing.T) {
 	assert.True(t, hasValue)
 	assert.Equal(t, "127.0.0.1", value.Value.GetValue())
 }
+
+// TestService_IsExportedTo 测试服务导出的目标命名空间
+func TestService_IsExportedTo(t *testing.T) {
+	service := &Service{
+		Name:      "svc",
+		Namespace: "ns-a",
+		Meta:      map[string]string{MetaKeyServiceExportTo: " ns-b , ns-c,"},
+	}
+	assert.Equal(t, []string{"ns-b", "ns-c"}, service.ExportTo())
+	assert.True(t, service.IsExportedTo("ns-b"))
+	assert.True(t, service.IsExportedTo("ns-c"))
+	assert.False(t, service.IsExportedTo("ns-d"))
+	assert.False(t, service.IsExportedTo("ns-a"))
+
+	service.Meta[MetaKeyServiceExportTo] = ExportToAllNamespaces
+	assert.True(t, service.IsExportedTo("ns-d"))
+	assert.False(t, service.IsExportedTo("ns-a"))
+
+	delete(service.Meta, MetaKeyServiceExportTo)
+	assert.Nil(t, service.ExportTo())
+	assert.False(t, service.IsExportedTo("ns-b"))
+}
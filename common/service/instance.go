@@ -23,6 +23,7 @@ import (
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/plugin"
 )
 
 type NamespaceSet struct {
@@ -144,7 +145,7 @@ func CreateInstanceModel(serviceID string, req *api.Instance) *model.Instance {
 		(req.GetEnableHealthCheck() == nil || req.GetEnableHealthCheck().GetValue()) {
 		protoIns.EnableHealthCheck = utils.NewBoolValue(true)
 		protoIns.HealthCheck = req.HealthCheck
-		protoIns.HealthCheck.Type = api.HealthCheck_HEARTBEAT
+		protoIns.HealthCheck.Type = HealthCheckType(req.HealthCheck)
 		// ttl range: (0, 60]
 		ttl := protoIns.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
 		if ttl == 0 || ttl > 60 {
@@ -158,3 +159,12 @@ func CreateInstanceModel(serviceID string, req *api.Instance) *model.Instance {
 	instance.Proto = protoIns
 	return instance
 }
+
+// HealthCheckType 返回实例的健康检查类型，非主动探测的类型统一按心跳处理
+func HealthCheckType(healthCheck *api.HealthCheck) api.HealthCheck_HealthCheckType {
+	checkType := healthCheck.GetType()
+	if plugin.HealthCheckType(checkType).IsActiveProbe() {
+		return checkType
+	}
+	return api.HealthCheck_HEARTBEAT
+}
@@ -24,6 +24,14 @@ const (
 	ReleaseTypeNormal = "normal"
 	// ReleaseTypeDelete 发布类型，删除配置文件
 	ReleaseTypeDelete = "delete"
+	// ReleaseTypeGray 发布类型，灰度发布
+	ReleaseTypeGray = "gray"
+	// ReleaseTypeCancelGray 发布类型，取消灰度发布
+	ReleaseTypeCancelGray = "cancel-gray"
+	// ReleaseTypeRollback 发布类型，回滚到历史版本
+	ReleaseTypeRollback = "rollback"
+	// ReleaseTypeReview 发布历史类型，配置文件变更审核记录，状态为变更的审核状态
+	ReleaseTypeReview = "review"
 
 	// ReleaseStatusSuccess 发布成功状态
 	ReleaseStatusSuccess = "success"
@@ -32,6 +40,38 @@ const (
 	// ReleaseStatusToRelease 待发布状态
 	ReleaseStatusToRelease = "to-be-released"
 
+	// ChangeStatusPending 配置文件变更状态，待审核
+	ChangeStatusPending = "pending"
+	// ChangeStatusApproved 配置文件变更状态，审核通过，可以发布
+	ChangeStatusApproved = "approved"
+	// ChangeStatusRejected 配置文件变更状态，审核拒绝
+	ChangeStatusRejected = "rejected"
+	// ChangeStatusPublished 配置文件变更状态，审核通过后已发布
+	ChangeStatusPublished = "published"
+	// ChangeStatusSuperseded 配置文件变更状态，待审核期间被其他用户编辑，由编辑人重新提交的变更替代
+	ChangeStatusSuperseded = "superseded"
+
+	// WebhookEventPublish 配置文件发布事件
+	WebhookEventPublish = "publish"
+	// WebhookEventDelete 配置文件删除发布事件
+	WebhookEventDelete = "delete"
+
+	// WebhookDeliveryPending 推送中，失败后等待重试
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliverySuccess 推送成功
+	WebhookDeliverySuccess = "success"
+	// WebhookDeliveryFailed 重试次数用完后仍然推送失败
+	WebhookDeliveryFailed = "failed"
+
+	// VariableTypeString 模板变量类型，字符串
+	VariableTypeString = "string"
+	// VariableTypeInt 模板变量类型，整数
+	VariableTypeInt = "int"
+	// VariableTypeFloat 模板变量类型，浮点数
+	VariableTypeFloat = "float"
+	// VariableTypeBool 模板变量类型，布尔值
+	VariableTypeBool = "bool"
+
 	// 文件格式
 	FileFormatText       = "text"
 	FileFormatYaml       = "yaml"
@@ -41,6 +81,23 @@ const (
 	FileFormatProperties = "properties"
 
 	FileIdSeparator = "+"
+
+	// ConfigFileTagKeyUseEncrypted 配置文件标签，值为 true 时配置文件内容加密存储
+	ConfigFileTagKeyUseEncrypted = "internal-encrypted"
+	// ConfigFileTagKeyDataKey 配置文件标签，经过 KMS 主密钥加密后的数据密钥，由服务端维护
+	ConfigFileTagKeyDataKey = "internal-datakey"
+	// ConfigFileTagKeyEncryptAlgo 配置文件标签，配置文件内容的加密算法，由服务端维护
+	ConfigFileTagKeyEncryptAlgo = "internal-encryptalgo"
+	// ConfigFileTagKeySchema 配置文件标签，值为关联的 JSON Schema 名称，保存与发布前校验内容是否满足 Schema
+	ConfigFileTagKeySchema = "internal-schema"
+	// ConfigFileTagKeyRender 配置文件标签，值为 true 时发布前使用命名空间下的模板变量渲染配置文件，
+	// 并展开 include、extends 指令
+	ConfigFileTagKeyRender = "internal-render"
+
+	// ConfigFileImportConflictSkip 导入配置文件时，跳过已存在的配置文件
+	ConfigFileImportConflictSkip = "skip"
+	// ConfigFileImportConflictOverwrite 导入配置文件时，覆盖已存在的配置文件
+	ConfigFileImportConflictOverwrite = "overwrite"
 )
 
 func IsValidFileFormat(format string) bool {
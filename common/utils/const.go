@@ -37,6 +37,11 @@ const (
 	HeaderOwnerIDKey   string = "X-Owner-ID"
 	HeaderUserRoleKey  string = "X-Polaris-User-Role"
 
+	// HeaderConfigPublicKey 客户端的 RSA 公钥，用于获取加密配置文件的数据密钥
+	HeaderConfigPublicKey string = "X-Polaris-Config-Public-Key"
+	// HeaderClientIDKey 客户端 ID，与客户端通过 ReportClient 上报的 ID 一致，用于灰度发布分桶以及客户端版本固定
+	HeaderClientIDKey string = "X-Polaris-Client-Id"
+
 	ContextAuthTokenKey   StringContext = StringContext(HeaderAuthTokenKey)
 	ContextIsOwnerKey     StringContext = StringContext(HeaderIsOwnerKey)
 	ContextUserIDKey      StringContext = StringContext(HeaderUserIDKey)
@@ -48,4 +53,7 @@ const (
 
 	ContextOpenAsyncRegis StringContext = StringContext("client-asyncRegis")
 	ContextGrpcHeader     StringContext = StringContext("grpc-header")
+
+	ContextConfigPublicKey StringContext = StringContext(HeaderConfigPublicKey)
+	ContextClientIDKey     StringContext = StringContext(HeaderClientIDKey)
 )
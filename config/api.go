@@ -21,6 +21,7 @@ import (
 	"context"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
 )
 
 type (
@@ -73,6 +74,16 @@ type ConfigFileOperate interface {
 	BatchDeleteConfigFile(ctx context.Context, configFiles []*api.ConfigFile, operator string) *api.ConfigResponse
 }
 
+// ConfigFileImportExportOperate 配置文件导入导出接口
+type ConfigFileImportExportOperate interface {
+	// ExportConfigFile 导出配置文件为 zip 压缩包，groups 为空时导出命名空间下的全部配置文件组
+	ExportConfigFile(ctx context.Context, namespace string, groups []string) ([]byte, *api.ConfigResponse)
+
+	// ImportConfigFile 从 zip 压缩包导入配置文件，conflictHandling 为已存在配置文件的处理方式，publish 为导入后是否发布
+	ImportConfigFile(ctx context.Context, namespace string, data []byte, conflictHandling string,
+		publish bool) *api.ConfigBatchWriteResponse
+}
+
 // ConfigFileReleaseOperate 配置文件发布接口
 type ConfigFileReleaseOperate interface {
 	// PublishConfigFile 发布配置文件
@@ -83,6 +94,54 @@ type ConfigFileReleaseOperate interface {
 
 	// DeleteConfigFileRelease 删除配置文件发布内容
 	DeleteConfigFileRelease(ctx context.Context, namespace, group, fileName, deleteBy string) *api.ConfigResponse
+
+	// RollbackConfigFileRelease 回滚配置文件到指定的发布历史版本
+	RollbackConfigFileRelease(ctx context.Context, namespace, group, fileName string, historyId uint64) *api.ConfigResponse
+}
+
+// ConfigFileGrayReleaseOperate 配置文件灰度发布接口
+type ConfigFileGrayReleaseOperate interface {
+	// PublishConfigFileGray 灰度发布配置文件，只有命中灰度规则的客户端才会获取到灰度发布的内容
+	PublishConfigFileGray(ctx context.Context, configFileRelease *api.ConfigFileRelease, rule *model.GrayRule) *api.ConfigResponse
+
+	// GetConfigFileGrayRelease 获取配置文件当前的灰度发布
+	GetConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error)
+
+	// PromoteConfigFileGray 灰度发布转为全量发布
+	PromoteConfigFileGray(ctx context.Context, namespace, group, fileName string) *api.ConfigResponse
+
+	// CancelConfigFileGray 取消灰度发布
+	CancelConfigFileGray(ctx context.Context, namespace, group, fileName string) *api.ConfigResponse
+
+	// UpdateConfigFileGrayPercentage 提高按比例灰度的比例
+	UpdateConfigFileGrayPercentage(ctx context.Context, namespace, group, fileName string, percentage int) *api.ConfigResponse
+}
+
+// ConfigFileClientPinOperate 客户端版本固定接口
+type ConfigFileClientPinOperate interface {
+	// PinConfigFileClient 将客户端固定到指定的发布历史版本
+	PinConfigFileClient(ctx context.Context, pin *model.ConfigFileClientPin) *api.ConfigResponse
+
+	// UnpinConfigFileClient 取消客户端的版本固定
+	UnpinConfigFileClient(ctx context.Context, namespace, group, fileName, client string) *api.ConfigResponse
+
+	// QueryConfigFileClientPins 查询配置文件的全部客户端版本固定
+	QueryConfigFileClientPins(ctx context.Context, namespace, group, fileName string) ([]*model.ConfigFileClientPin, *api.ConfigResponse)
+}
+
+// ConfigFileChangeOperate 配置文件变更审核接口
+type ConfigFileChangeOperate interface {
+	// QueryConfigFileChanges 查询配置文件变更，status 为空时查询全部状态
+	QueryConfigFileChanges(ctx context.Context, namespace, group, fileName, status string, offset, limit uint32) (uint32, []*model.ConfigFileChange, *api.ConfigResponse)
+
+	// GetConfigFileChange 获取单个配置文件变更
+	GetConfigFileChange(ctx context.Context, id uint64) (*model.ConfigFileChange, *api.ConfigResponse)
+
+	// ApproveConfigFileChange 审核通过配置文件变更，审核通过后才能发布
+	ApproveConfigFileChange(ctx context.Context, id uint64, comment string) *api.ConfigResponse
+
+	// RejectConfigFileChange 拒绝配置文件变更
+	RejectConfigFileChange(ctx context.Context, id uint64, comment string) *api.ConfigResponse
 }
 
 // ConfigFileReleaseHistoryOperate 配置文件发布历史接口
@@ -101,6 +160,12 @@ type ConfigFileClientOperate interface {
 
 	// WatchConfigFiles 客户端监听配置文件
 	WatchConfigFiles(ctx context.Context, request *api.ClientWatchConfigFileRequest) (WatchCallback, error)
+
+	// OpenWatchStream 客户端建立订阅配置的长连接，一个连接上可以增量订阅多个配置文件
+	OpenWatchStream(ctx context.Context, sender WatchStreamSender) (*WatchStream, error)
+
+	// ReportConfigFileError 客户端上报加载配置文件失败
+	ReportConfigFileError(ctx context.Context, configFile *api.ClientConfigFileInfo) *api.ConfigClientResponse
 }
 
 // ConfigFileTemplateOperate config file template operate
@@ -115,12 +180,73 @@ type ConfigFileTemplateOperate interface {
 	GetConfigFileTemplate(ctx context.Context, name string) *api.ConfigResponse
 }
 
+// ConfigFileSchemaOperate 配置文件 JSON Schema 接口，配置文件通过 internal-schema 标签关联 Schema
+type ConfigFileSchemaOperate interface {
+	// GetAllConfigFileSchemas 获取全部的 JSON Schema
+	GetAllConfigFileSchemas(ctx context.Context) ([]*model.ConfigFileSchema, *api.ConfigResponse)
+
+	// GetConfigFileSchema 获取单个 JSON Schema
+	GetConfigFileSchema(ctx context.Context, name string) (*model.ConfigFileSchema, *api.ConfigResponse)
+
+	// CreateConfigFileSchema 创建 JSON Schema
+	CreateConfigFileSchema(ctx context.Context, schema *model.ConfigFileSchema) *api.ConfigResponse
+
+	// UpdateConfigFileSchema 更新 JSON Schema，只对之后保存、发布的配置文件生效
+	UpdateConfigFileSchema(ctx context.Context, schema *model.ConfigFileSchema) *api.ConfigResponse
+}
+
+// ConfigFileWebhookOperate 配置文件变更 webhook 接口
+type ConfigFileWebhookOperate interface {
+	// CreateConfigFileWebhook 创建 webhook，返回的 webhook 包含 secret
+	CreateConfigFileWebhook(ctx context.Context, webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, *api.ConfigResponse)
+
+	// UpdateConfigFileWebhook 更新 webhook
+	UpdateConfigFileWebhook(ctx context.Context, webhook *model.ConfigFileWebhook) *api.ConfigResponse
+
+	// DeleteConfigFileWebhook 删除 webhook
+	DeleteConfigFileWebhook(ctx context.Context, id uint64) *api.ConfigResponse
+
+	// GetConfigFileWebhook 获取单个 webhook
+	GetConfigFileWebhook(ctx context.Context, id uint64) (*model.ConfigFileWebhook, *api.ConfigResponse)
+
+	// QueryConfigFileWebhooks 查询命名空间下的 webhook
+	QueryConfigFileWebhooks(ctx context.Context, namespace, group, fileName string, offset, limit uint32) (uint32, []*model.ConfigFileWebhook, *api.ConfigResponse)
+
+	// QueryConfigFileWebhookDeliveries 查询 webhook 的推送记录
+	QueryConfigFileWebhookDeliveries(ctx context.Context, webhookId uint64, status string, offset, limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, *api.ConfigResponse)
+}
+
+// ConfigFileVariableOperate 配置文件模板变量接口
+type ConfigFileVariableOperate interface {
+	// GetConfigFileVariables 获取命名空间下的全部模板变量
+	GetConfigFileVariables(ctx context.Context, namespace string) ([]*model.ConfigFileVariable, *api.ConfigResponse)
+
+	// CreateConfigFileVariable 创建模板变量
+	CreateConfigFileVariable(ctx context.Context, variable *model.ConfigFileVariable) *api.ConfigResponse
+
+	// UpdateConfigFileVariable 更新模板变量
+	UpdateConfigFileVariable(ctx context.Context, variable *model.ConfigFileVariable) *api.ConfigResponse
+
+	// DeleteConfigFileVariable 删除模板变量
+	DeleteConfigFileVariable(ctx context.Context, namespace, name string) *api.ConfigResponse
+
+	// RenderConfigFile 预览配置文件渲染后的内容
+	RenderConfigFile(ctx context.Context, namespace, group, name string) (string, *api.ConfigResponse)
+}
+
 // ConfigCenterServer 配置中心server
 type ConfigCenterServer interface {
 	ConfigFileGroupOperate
 	ConfigFileOperate
+	ConfigFileImportExportOperate
 	ConfigFileReleaseOperate
+	ConfigFileGrayReleaseOperate
+	ConfigFileClientPinOperate
+	ConfigFileChangeOperate
 	ConfigFileReleaseHistoryOperate
 	ConfigFileClientOperate
 	ConfigFileTemplateOperate
+	ConfigFileSchemaOperate
+	ConfigFileWebhookOperate
+	ConfigFileVariableOperate
 }
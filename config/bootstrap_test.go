@@ -41,9 +41,11 @@ import (
 	"github.com/polarismesh/polaris/plugin"
 	_ "github.com/polarismesh/polaris/plugin/auth/defaultauth"
 	_ "github.com/polarismesh/polaris/plugin/auth/platform"
+	_ "github.com/polarismesh/polaris/plugin/crypto/aes"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/heartbeatmemory"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/heartbeatredis"
 	_ "github.com/polarismesh/polaris/plugin/history/logger"
+	_ "github.com/polarismesh/polaris/plugin/kms/local"
 	_ "github.com/polarismesh/polaris/plugin/password"
 	"github.com/polarismesh/polaris/store"
 	"github.com/polarismesh/polaris/store/boltdb"
@@ -216,8 +218,22 @@ func (c *ConfigCenterTest) clearTestDataWhenUseBoltdb() error {
 		"ConfigFileReleaseHistoryID",
 		"ConfigFileRelease",
 		"ConfigFileReleaseID",
+		"ConfigFileGrayRelease",
+		"ConfigFileGrayReleaseID",
+		"ConfigFileChange",
+		"ConfigFileChangeID",
+		"ConfigFileSchema",
+		"ConfigFileSchemaID",
+		"ConfigFileWebhook",
+		"ConfigFileWebhookID",
+		"ConfigFileWebhookDelivery",
+		"ConfigFileWebhookDeliveryID",
+		"ConfigFileVariable",
+		"ConfigFileVariableID",
 		"ConfigFileTag",
 		"ConfigFileTagID",
+		"ConfigFileClientPin",
+		"ConfigFileClientPinID",
 		"namespace",
 	}
 
@@ -261,10 +277,38 @@ func (c *ConfigCenterTest) clearTestDataWhenUseRDS() error {
 	if err != nil {
 		return err
 	}
+	_, err = tx.Exec("delete from config_file_gray_release where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("delete from config_file_change where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
 	_, err = tx.Exec("delete from config_file_tag where namespace = ? ", testNamespace)
 	if err != nil {
 		return err
 	}
+	_, err = tx.Exec("delete from config_file_schema where name = ? ", testSchema)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("delete from config_file_webhook where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("delete from config_file_webhook_delivery where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("delete from config_file_variable where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("delete from config_file_client_pin where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
 	_, err = tx.Exec("delete from namespace where name = ? ", testNamespace)
 	if err != nil {
 		return err
@@ -67,6 +67,29 @@ func (s *Server) GetConfigFileForClient(ctx context.Context,
 		return api.NewConfigClientResponse(api.NotFoundResource, nil)
 	}
 
+	// 被固定版本的客户端只获取固定的内容
+	if pin := s.matchClientPin(ctx, namespace, group, fileName); pin != nil {
+		pinned := s.clientPinEntry(pin, entry)
+		log.ConfigScope().Info("[Config][Client] client get config file pinned release.",
+			zap.String("requestId", requestID),
+			zap.String("client", utils.ParseClientAddress(ctx)),
+			zap.String("file", fileName),
+			zap.Uint64("historyId", pin.HistoryId))
+
+		return utils2.GenConfigFileResponse(namespace, group, fileName, pinned.Content, pinned.Md5, pinned.Version)
+	}
+
+	// 命中灰度规则的客户端获取灰度发布的内容
+	if gray := s.matchGrayRelease(ctx, namespace, group, fileName); gray != nil && gray.Version > entry.Version {
+		log.ConfigScope().Info("[Config][Client] client get config file gray release.",
+			zap.String("requestId", requestID),
+			zap.String("client", utils.ParseClientAddress(ctx)),
+			zap.String("file", fileName),
+			zap.Uint64("version", gray.Version))
+
+		return utils2.GenConfigFileResponse(namespace, group, fileName, gray.Content, gray.Md5, gray.Version)
+	}
+
 	// 客户端版本号大于服务端版本号，服务端需要重新加载缓存
 	if clientVersion > entry.Version {
 		entry, err = s.fileCache.ReLoad(namespace, group, fileName)
@@ -105,7 +128,7 @@ func (s *Server) WatchConfigFiles(ctx context.Context,
 	// 3. 监听配置变更，hold 请求 30s，30s 内如果有配置发布，则响应请求
 	clientId := clientAddr + "@" + utils.NewUUID()[0:8]
 
-	finishChan := s.ConnManager().AddConn(clientId, watchFiles)
+	finishChan := s.ConnManager().AddConn(clientId, s.parseClient(ctx), watchFiles)
 
 	return func() *api.ConfigClientResponse {
 		return <-finishChan
@@ -150,6 +173,17 @@ func (s *Server) doCheckClientConfigFile(ctx context.Context, configFiles []*api
 			return api.NewConfigClientResponse(api.ExecuteException, nil)
 		}
 
+		if pin := s.matchClientPin(ctx, namespace, group, fileName); pin != nil && !entry.Empty {
+			// 被固定版本的客户端版本号不会回退，客户端上报了 md5 时通过 md5 判断是否获取到了固定的内容
+			entry = s.clientPinEntry(pin, entry)
+			if configFile.GetMd5().GetValue() != "" && compareByMD5(configFile, entry) {
+				return utils2.GenConfigFileResponse(namespace, group, fileName, "", entry.Md5, entry.Version)
+			}
+		} else if gray := s.matchGrayRelease(ctx, namespace, group, fileName); gray != nil &&
+			!entry.Empty && gray.Version > entry.Version {
+			entry = gray.entry()
+		}
+
 		if compartor(configFile, entry) {
 			return utils2.GenConfigFileResponse(namespace, group, fileName, "", entry.Md5, entry.Version)
 		}
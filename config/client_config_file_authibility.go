@@ -27,7 +27,8 @@ import (
 func (s *serverAuthability) GetConfigFileForClient(ctx context.Context,
 	fileInfo *api.ClientConfigFileInfo) *api.ConfigClientResponse {
 
-	return s.targetServer.GetConfigFileForClient(ctx, fileInfo)
+	rsp := s.targetServer.GetConfigFileForClient(ctx, fileInfo)
+	return s.decryptClientConfigFile(ctx, rsp)
 }
 
 // WatchConfigFiles
@@ -36,3 +37,17 @@ func (s *serverAuthability) WatchConfigFiles(ctx context.Context,
 
 	return s.targetServer.WatchConfigFiles(ctx, request)
 }
+
+// OpenWatchStream 客户端建立订阅配置的长连接
+func (s *serverAuthability) OpenWatchStream(ctx context.Context,
+	sender WatchStreamSender) (*WatchStream, error) {
+
+	return s.targetServer.OpenWatchStream(ctx, sender)
+}
+
+// ReportConfigFileError 客户端上报加载配置文件失败
+func (s *serverAuthability) ReportConfigFileError(ctx context.Context,
+	configFile *api.ClientConfigFileInfo) *api.ConfigClientResponse {
+
+	return s.targetServer.ReportConfigFileError(ctx, configFile)
+}
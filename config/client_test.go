@@ -198,7 +198,7 @@ func TestWatchConfigFileAtFirstPublish(t *testing.T) {
 			testSuit.testServer.WatchCenter().RemoveWatcher(clientId, watchConfigFiles)
 		}()
 
-		testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, clientIdentity{}, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
 			t.Logf("clientId=[%s] receive config publish msg", clientId)
 			received <- rsp.ConfigFile.Version.GetValue()
 			return true
@@ -223,7 +223,7 @@ func TestWatchConfigFileAtFirstPublish(t *testing.T) {
 
 		clientId := "TestWatchConfigFileAtFirstPublish-second"
 
-		testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, clientIdentity{}, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
 			t.Logf("clientId=[%s] receive config publish msg", clientId)
 			received <- rsp.ConfigFile.Version.GetValue()
 			return true
@@ -262,7 +262,7 @@ func Test10000ClientWatchConfigFile(t *testing.T) {
 		clientId := fmt.Sprintf("Test10000ClientWatchConfigFile-client-id=%d", i)
 		received[clientId] = false
 		receivedVersion[clientId] = uint64(0)
-		testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, clientIdentity{}, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
 			received[clientId] = true
 			receivedVersion[clientId] = rsp.ConfigFile.Version.GetValue()
 			return true
@@ -331,7 +331,7 @@ func TestDeleteConfigFile(t *testing.T) {
 
 	t.Log("add config watcher")
 
-	testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
+	testSuit.testServer.WatchCenter().AddWatcher(clientId, clientIdentity{}, watchConfigFiles, func(clientId string, rsp *api.ConfigClientResponse) bool {
 		received <- rsp.ConfigFile.Version.GetValue()
 		return true
 	})
@@ -51,6 +51,22 @@ func (s *Server) checkNamespaceExisted(namespaceName string) bool {
 	return namespace != nil
 }
 
+// checkConfigFileQuota 检查命名空间下的配置文件数量是否达到配额，返回 true 表示还可以继续创建配置文件
+func (s *Server) checkConfigFileQuota(namespaceName string) (bool, error) {
+	namespace, err := s.storage.GetNamespace(namespaceName)
+	if err != nil {
+		return false, err
+	}
+	if namespace == nil || namespace.ConfigFileQuota == 0 {
+		return true, nil
+	}
+	total, err := s.storage.CountConfigFiles(namespaceName)
+	if err != nil {
+		return false, err
+	}
+	return total < uint64(namespace.ConfigFileQuota), nil
+}
+
 func convertToErrCode(err error) uint32 {
 	if errors.Is(err, model.ErrorTokenNotExist) {
 		return api.TokenNotExisted
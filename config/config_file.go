@@ -42,6 +42,11 @@ func (s *Server) CreateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return checkRsp
 	}
 
+	if checkRsp := s.checkConfigFileContentForSave(ctx, configFile.Format.GetValue(), configFile.Content.GetValue(),
+		configFile.Tags); checkRsp != nil {
+		return checkRsp
+	}
+
 	userName := utils.ParseUserName(ctx)
 	configFile.CreateBy = utils.NewStringValue(userName)
 	configFile.ModifyBy = utils.NewStringValue(userName)
@@ -94,8 +99,32 @@ func (s *Server) CreateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return api.NewConfigFileResponse(api.ExistedResource, configFile)
 	}
 
+	// 检查命名空间的配置文件配额
+	allowed, err := s.checkConfigFileQuota(namespace)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] check config file quota error.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, configFile)
+	}
+	if !allowed {
+		log.ConfigScope().Error("[Config][Service] create config file not allowed: exceed namespace quota.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("name", name))
+		return api.NewConfigFileResponse(api.NamespaceQuotaExceeded, configFile)
+	}
+
+	content, rsp := s.encryptConfigFile(ctx, configFile, nil)
+	if rsp != nil {
+		return rsp
+	}
+
 	fileStoreModel := transferConfigFileAPIModel2StoreModel(configFile)
 	fileStoreModel.ModifyBy = fileStoreModel.CreateBy
+	fileStoreModel.Content = content
 
 	// 创建配置文件
 	createdFile, err := s.storage.CreateConfigFile(s.getTx(ctx), fileStoreModel)
@@ -115,6 +144,11 @@ func (s *Server) CreateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return response
 	}
 
+	// 开启变更审核时提交待审核的变更
+	if rsp := s.submitConfigFileChange(ctx, createdFile, configFile.Content.GetValue()); rsp != nil {
+		return rsp
+	}
+
 	// 创建成功
 	log.ConfigScope().Info("[Config][Service] create config file success.",
 		zap.String("request-id", requestID),
@@ -123,7 +157,9 @@ func (s *Server) CreateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		zap.String("name", name),
 		zap.Error(err))
 
-	return api.NewConfigFileResponse(api.ExecuteSuccess, transferConfigFileStoreModel2APIModel(createdFile))
+	createdFileAPIModel := transferConfigFileStoreModel2APIModel(createdFile)
+	createdFileAPIModel.Content = utils.NewStringValue(configFile.Content.GetValue())
+	return api.NewConfigFileResponse(api.ExecuteSuccess, createdFileAPIModel)
 }
 
 // GetConfigFileBaseInfo 获取配置文件，只返回基础元信息
@@ -340,11 +376,26 @@ func (s *Server) UpdateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return api.NewConfigFileResponse(api.NotFoundResource, configFile)
 	}
 
+	format := configFile.Format.GetValue()
+	if format == "" {
+		format = managedFile.Format
+	}
+	if checkRsp := s.checkConfigFileContentForSave(ctx, format, configFile.Content.GetValue(),
+		configFile.Tags); checkRsp != nil {
+		return checkRsp
+	}
+
 	userName := utils.ParseUserName(ctx)
 	configFile.ModifyBy = utils.NewStringValue(userName)
 
+	content, rsp := s.encryptConfigFile(ctx, configFile, managedFile)
+	if rsp != nil {
+		return rsp
+	}
+
 	toUpdateFile := transferConfigFileAPIModel2StoreModel(configFile)
 	toUpdateFile.ModifyBy = configFile.ModifyBy.GetValue()
+	toUpdateFile.Content = content
 
 	if configFile.Format.GetValue() == "" {
 		toUpdateFile.Format = managedFile.Format
@@ -367,8 +418,16 @@ func (s *Server) UpdateConfigFile(ctx context.Context, configFile *api.ConfigFil
 		return response
 	}
 
+	// 开启变更审核时提交待审核的变更
+	if rsp := s.submitConfigFileChange(ctx, updatedFile, configFile.Content.GetValue()); rsp != nil {
+		return rsp
+	}
+
 	baseFile := transferConfigFileStoreModel2APIModel(updatedFile)
 	baseFile, err = s.fillReleaseAndTags(ctx, baseFile)
+	if err == nil {
+		baseFile.Content = utils.NewStringValue(configFile.Content.GetValue())
+	}
 
 	return api.NewConfigFileResponse(api.ExecuteSuccess, baseFile)
 }
@@ -599,7 +658,7 @@ func (s *Server) fillReleaseAndTags(ctx context.Context, file *api.ConfigFile) (
 	}
 
 	latestRelease := latestReleaseRsp.ConfigFileReleaseHistory
-	if latestRelease != nil && latestRelease.Type.GetValue() == utils.ReleaseTypeNormal {
+	if latestRelease != nil && isFullReleaseType(latestRelease.Type.GetValue()) {
 		file.ReleaseBy = latestRelease.CreateBy
 		file.ReleaseTime = latestRelease.CreateTime
 
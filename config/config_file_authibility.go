@@ -44,27 +44,35 @@ func (s *serverAuthability) CreateConfigFile(ctx context.Context,
 func (s *serverAuthability) GetConfigFileBaseInfo(ctx context.Context, namespace,
 	group, name string) *api.ConfigResponse {
 
-	return s.targetServer.GetConfigFileBaseInfo(ctx, namespace, group, name)
+	rsp := s.targetServer.GetConfigFileBaseInfo(ctx, namespace, group, name)
+	s.newDecryptChecker(ctx).decryptConfigFiles(rsp.GetConfigFile())
+	return rsp
 }
 
 // GetConfigFileRichInfo 获取单个配置文件基础信息，包含发布状态等信息
 func (s *serverAuthability) GetConfigFileRichInfo(ctx context.Context, namespace,
 	group, name string) *api.ConfigResponse {
 
-	return s.targetServer.GetConfigFileRichInfo(ctx, namespace, group, name)
+	rsp := s.targetServer.GetConfigFileRichInfo(ctx, namespace, group, name)
+	s.newDecryptChecker(ctx).decryptConfigFiles(rsp.GetConfigFile())
+	return rsp
 }
 
 func (s *serverAuthability) QueryConfigFilesByGroup(ctx context.Context, namespace, group string,
 	offset, limit uint32) *api.ConfigBatchQueryResponse {
 
-	return s.targetServer.QueryConfigFilesByGroup(ctx, namespace, group, offset, limit)
+	rsp := s.targetServer.QueryConfigFilesByGroup(ctx, namespace, group, offset, limit)
+	s.newDecryptChecker(ctx).decryptConfigFiles(rsp.GetConfigFiles()...)
+	return rsp
 }
 
 // SearchConfigFile 查询配置文件
 func (s *serverAuthability) SearchConfigFile(ctx context.Context, namespace, group, name,
 	tags string, offset, limit uint32) *api.ConfigBatchQueryResponse {
 
-	return s.targetServer.SearchConfigFile(ctx, namespace, group, name, tags, offset, limit)
+	rsp := s.targetServer.SearchConfigFile(ctx, namespace, group, name, tags, offset, limit)
+	s.newDecryptChecker(ctx).decryptConfigFiles(rsp.GetConfigFiles()...)
+	return rsp
 }
 
 // UpdateConfigFile 更新配置文件
@@ -0,0 +1,306 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// submitConfigFileChange 开启变更审核后，编辑配置文件时提交待审核的变更。
+// content 为配置文件的存储内容，plaintext 为明文内容，用于和最新的发布内容生成 diff
+func (s *Server) submitConfigFileChange(ctx context.Context, file *model.ConfigFile,
+	plaintext string) *api.ConfigResponse {
+	if !s.reviewOpen {
+		return nil
+	}
+
+	namespace, group, fileName := file.Namespace, file.Group, file.Name
+	requestID := utils.ParseRequestID(ctx)
+
+	latest, err := s.storage.GetLatestConfigFileChange(nil, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get latest config file change error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	md5 := utils2.CalMd5(file.Content)
+	// 内容没有变化时不需要重新审核，被拒绝的变更再次提交时重新审核
+	if latest != nil && latest.Md5 == md5 && latest.Status != utils.ChangeStatusRejected {
+		return nil
+	}
+
+	diff, err := s.genConfigFileChangeDiff(file, plaintext)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] generate config file change diff error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.ExecuteException, nil)
+	}
+
+	userName := utils.ParseUserName(ctx)
+	var saved *model.ConfigFileChange
+	// 同一个配置文件同一时间最多只有一个待审核的变更，提交人再次编辑时更新待审核的变更；
+	// 其他用户编辑时原变更被替代，由编辑人重新提交，避免提交人以外的编辑人审核通过自己修改的内容
+	if latest != nil && latest.Status == utils.ChangeStatusPending && latest.CreateBy == userName {
+		latest.Content = file.Content
+		latest.Diff = diff
+		latest.Md5 = md5
+		latest.ModifyBy = userName
+		saved, err = s.storage.UpdateConfigFileChange(nil, latest)
+	} else {
+		if latest != nil && latest.Status == utils.ChangeStatusPending {
+			if rsp := s.supersedeConfigFileChange(ctx, latest); rsp != nil {
+				return rsp
+			}
+		}
+		saved, err = s.storage.CreateConfigFileChange(nil, &model.ConfigFileChange{
+			Namespace: namespace,
+			Group:     group,
+			FileName:  fileName,
+			Content:   file.Content,
+			Diff:      diff,
+			Md5:       md5,
+			Status:    utils.ChangeStatusPending,
+			CreateBy:  userName,
+			ModifyBy:  userName,
+		})
+	}
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] save config file change error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordConfigFileChangeHistory(ctx, saved, "")
+	return nil
+}
+
+// supersedeConfigFileChange 其他用户编辑待审核的变更时，将原变更标记为已被替代，原提交人记录在原变更以及发布历史中
+func (s *Server) supersedeConfigFileChange(ctx context.Context, change *model.ConfigFileChange) *api.ConfigResponse {
+	userName := utils.ParseUserName(ctx)
+	change.Status = utils.ChangeStatusSuperseded
+	change.ModifyBy = userName
+	superseded, err := s.storage.UpdateConfigFileChange(nil, change)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] supersede config file change error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.Uint64("id", change.Id),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	s.recordConfigFileChangeHistory(ctx, superseded, "superseded by "+userName+", submitted by "+change.CreateBy)
+	return nil
+}
+
+// genConfigFileChangeDiff 生成变更内容相对于最新发布内容的 unified diff，加密的配置文件 diff 同样加密存储
+func (s *Server) genConfigFileChangeDiff(file *model.ConfigFile, plaintext string) (string, error) {
+	release, err := s.storage.GetConfigFileRelease(nil, file.Namespace, file.Group, file.Name)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		dataKey        string
+		releaseContent string
+	)
+	encrypted := file.Content != plaintext
+	if encrypted || release != nil {
+		if dataKey, err = s.fileDataKey(file.Namespace, file.Group, file.Name); err != nil {
+			return "", err
+		}
+	}
+	if release != nil {
+		if releaseContent, err = s.decryptContent(release.Content, dataKey); err != nil {
+			return "", err
+		}
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(releaseContent),
+		B:        difflib.SplitLines(plaintext),
+		FromFile: file.Name + "@release",
+		ToFile:   file.Name + "@change",
+		Context:  3,
+	})
+	if err != nil || !encrypted || diff == "" {
+		return diff, err
+	}
+	return s.encryptContent(diff, dataKey)
+}
+
+// QueryConfigFileChanges 查询配置文件变更，status 为空时查询全部状态
+func (s *Server) QueryConfigFileChanges(ctx context.Context, namespace, group, fileName, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileChange, *api.ConfigResponse) {
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return 0, nil, api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if limit <= 0 || limit > MaxPageSize {
+		return 0, nil, api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	if status != "" && !isValidChangeStatus(status) {
+		return 0, nil, api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid change status "+status)
+	}
+
+	total, changes, err := s.storage.QueryConfigFileChanges(namespace, group, fileName, status, offset, limit)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file changes error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return 0, nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return total, changes, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// GetConfigFileChange 获取单个配置文件变更
+func (s *Server) GetConfigFileChange(ctx context.Context, id uint64) (*model.ConfigFileChange, *api.ConfigResponse) {
+	change, err := s.storage.GetConfigFileChange(nil, id)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file change error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.Uint64("id", id),
+			zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if change == nil {
+		return nil, api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	return change, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// ApproveConfigFileChange 审核通过配置文件变更，审核通过后才能发布
+func (s *Server) ApproveConfigFileChange(ctx context.Context, id uint64, comment string) *api.ConfigResponse {
+	return s.reviewConfigFileChange(ctx, id, utils.ChangeStatusApproved, comment)
+}
+
+// RejectConfigFileChange 拒绝配置文件变更
+func (s *Server) RejectConfigFileChange(ctx context.Context, id uint64, comment string) *api.ConfigResponse {
+	return s.reviewConfigFileChange(ctx, id, utils.ChangeStatusRejected, comment)
+}
+
+func (s *Server) reviewConfigFileChange(ctx context.Context, id uint64, status,
+	comment string) *api.ConfigResponse {
+
+	change, rsp := s.GetConfigFileChange(ctx, id)
+	if change == nil {
+		return rsp
+	}
+	if change.Status != utils.ChangeStatusPending {
+		return api.NewConfigFileResponseWithMessage(api.BadRequest,
+			"only pending config file change can be reviewed")
+	}
+
+	userName := utils.ParseUserName(ctx)
+	// 变更不能由提交人自己审核通过
+	if status == utils.ChangeStatusApproved && userName != "" && userName == change.CreateBy {
+		return api.NewConfigFileResponse(api.ConfigFileChangeSelfApproval, nil)
+	}
+	change.Status = status
+	change.ReviewBy = userName
+	change.ReviewComment = comment
+	change.ModifyBy = userName
+
+	updated, err := s.storage.UpdateConfigFileChange(nil, change)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] review config file change error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.Uint64("id", id),
+			zap.String("status", status),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordConfigFileChangeHistory(ctx, updated, comment)
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// checkConfigFileChangeApproved 开启变更审核后，配置文件的当前内容必须是审核通过的变更才能发布
+func (s *Server) checkConfigFileChangeApproved(tx store.Tx,
+	file *model.ConfigFile) (*model.ConfigFileChange, *api.ConfigResponse) {
+	if !s.reviewOpen {
+		return nil, nil
+	}
+	change, err := s.storage.GetLatestConfigFileChange(tx, file.Namespace, file.Group, file.Name)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get latest config file change error.",
+			zap.String("file", utils.GenFileId(file.Namespace, file.Group, file.Name)),
+			zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if change == nil || change.Md5 != utils2.CalMd5(file.Content) ||
+		(change.Status != utils.ChangeStatusApproved && change.Status != utils.ChangeStatusPublished) {
+		return nil, api.NewConfigFileResponse(api.ConfigFileChangeNotApproved, nil)
+	}
+	return change, nil
+}
+
+// markConfigFileChangePublished 审核通过的变更发布后标记为已发布
+func (s *Server) markConfigFileChangePublished(ctx context.Context, change *model.ConfigFileChange) {
+	if change == nil || change.Status != utils.ChangeStatusApproved {
+		return
+	}
+	change.Status = utils.ChangeStatusPublished
+	change.ModifyBy = utils.ParseUserName(ctx)
+	if _, err := s.storage.UpdateConfigFileChange(s.getTx(ctx), change); err != nil {
+		log.ConfigScope().Error("[Config][Service] mark config file change published error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.Uint64("id", change.Id),
+			zap.Error(err))
+	}
+}
+
+// recordConfigFileChangeHistory 变更的提交以及审核记录在发布历史中，发布类型为 review，状态为变更的审核状态
+func (s *Server) recordConfigFileChangeHistory(ctx context.Context, change *model.ConfigFileChange,
+	comment string) {
+	s.recordReleaseHistory(ctx, &model.ConfigFileRelease{
+		Name:      "change-" + strconv.FormatUint(change.Id, 10),
+		Namespace: change.Namespace,
+		Group:     change.Group,
+		FileName:  change.FileName,
+		Content:   change.Content,
+		Comment:   comment,
+		Md5:       change.Md5,
+		ModifyBy:  change.ModifyBy,
+	}, utils.ReleaseTypeReview, change.Status)
+}
+
+func isValidChangeStatus(status string) bool {
+	return status == utils.ChangeStatusPending || status == utils.ChangeStatusApproved ||
+		status == utils.ChangeStatusRejected || status == utils.ChangeStatusPublished ||
+		status == utils.ChangeStatusSuperseded
+}
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// QueryConfigFileChanges 查询配置文件变更
+func (s *serverAuthability) QueryConfigFileChanges(ctx context.Context, namespace, group, fileName, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileChange, *api.ConfigResponse) {
+
+	total, changes, rsp := s.targetServer.QueryConfigFileChanges(ctx, namespace, group, fileName, status,
+		offset, limit)
+	checker := s.newDecryptChecker(ctx)
+	for i := range changes {
+		changes[i] = checker.decryptConfigFileChange(changes[i])
+	}
+	return total, changes, rsp
+}
+
+// GetConfigFileChange 获取单个配置文件变更
+func (s *serverAuthability) GetConfigFileChange(ctx context.Context,
+	id uint64) (*model.ConfigFileChange, *api.ConfigResponse) {
+
+	change, rsp := s.targetServer.GetConfigFileChange(ctx, id)
+	return s.newDecryptChecker(ctx).decryptConfigFileChange(change), rsp
+}
+
+// ApproveConfigFileChange 审核通过配置文件变更，审核人需要拥有变更所属配置分组的写权限
+func (s *serverAuthability) ApproveConfigFileChange(ctx context.Context, id uint64,
+	comment string) *api.ConfigResponse {
+
+	ctx, rsp := s.checkConfigFileChangePermission(ctx, id, "ApproveConfigFileChange")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.ApproveConfigFileChange(ctx, id, comment)
+}
+
+// RejectConfigFileChange 拒绝配置文件变更，审核人需要拥有变更所属配置分组的写权限
+func (s *serverAuthability) RejectConfigFileChange(ctx context.Context, id uint64,
+	comment string) *api.ConfigResponse {
+
+	ctx, rsp := s.checkConfigFileChangePermission(ctx, id, "RejectConfigFileChange")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.RejectConfigFileChange(ctx, id, comment)
+}
+
+// checkConfigFileChangePermission 通过鉴权插件校验审核人对变更所属配置分组的写权限。
+// 审核是发布前的强制校验，不论控制台是否开启鉴权都会执行，无法解析出审核用户时拒绝审核
+func (s *serverAuthability) checkConfigFileChangePermission(ctx context.Context, id uint64,
+	method string) (context.Context, *api.ConfigResponse) {
+
+	change, rsp := s.targetServer.GetConfigFileChange(ctx, id)
+	if change == nil {
+		return nil, rsp
+	}
+	authCtx := s.collectConfigGroupAuthContext(ctx, []*api.ConfigFileGroup{{
+		Namespace: utils.NewStringValue(change.Namespace),
+		Name:      utils.NewStringValue(change.Group),
+	}}, model.Modify, method)
+	ok, err := s.checker.CheckPermission(authCtx)
+	if err != nil {
+		return nil, api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	principalType, _ := authCtx.GetAttachment(model.OperatorPrincipalType).(model.PrincipalType)
+	if !ok || principalType != model.PrincipalUser || utils.ParseUserName(authCtx.GetRequestContext()) == "" {
+		return nil, api.NewConfigFileResponseWithMessage(api.NotAllowedAccess,
+			"config file change reviewer can not be resolved or has no permission of the config group")
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return ctx, nil
+}
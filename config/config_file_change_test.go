@@ -0,0 +1,200 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func latestConfigFileChange(t *testing.T, testSuit *ConfigCenterTest) *model.ConfigFileChange {
+	total, changes, rsp := testSuit.testService.QueryConfigFileChanges(testSuit.defaultCtx,
+		testNamespace, testGroup, testFile, "", 0, 1)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	if total == 0 || len(changes) == 0 {
+		t.Fatal("config file change not found")
+	}
+	return changes[0]
+}
+
+// TestConfigFileChangeReview 测试开启变更审核后，配置文件的修改需要审核通过才能发布
+func TestConfigFileChangeReview(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testSuit.testServer.reviewOpen = true
+	defer func() {
+		testSuit.testServer.reviewOpen = false
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	var change *model.ConfigFileChange
+
+	t.Run("创建配置文件生成待审核的变更", func(t *testing.T) {
+		change = latestConfigFileChange(t, testSuit)
+		assert.Equal(t, utils.ChangeStatusPending, change.Status)
+		assert.Equal(t, configFile.Content.GetValue(), change.Content)
+		assert.True(t, strings.Contains(change.Diff, "+"+configFile.Content.GetValue()))
+
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ConfigFileChangeNotApproved, rsp.Code.GetValue())
+	})
+
+	t.Run("拒绝变更后不能发布", func(t *testing.T) {
+		rsp := testSuit.testService.RejectConfigFileChange(testSuit.defaultCtx, change.Id, "need to fix")
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rejected, rsp := testSuit.testService.GetConfigFileChange(testSuit.defaultCtx, change.Id)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, utils.ChangeStatusRejected, rejected.Status)
+		assert.Equal(t, "need to fix", rejected.ReviewComment)
+
+		rsp = testSuit.testService.ApproveConfigFileChange(testSuit.defaultCtx, change.Id, "")
+		assert.Equal(t, api.BadRequest, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ConfigFileChangeNotApproved, rsp.Code.GetValue())
+	})
+
+	t.Run("审核通过后发布", func(t *testing.T) {
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		change = latestConfigFileChange(t, testSuit)
+		assert.Equal(t, utils.ChangeStatusPending, change.Status)
+
+		// 提交人不能审核通过自己的变更
+		rsp = testSuit.testService.ApproveConfigFileChange(testSuit.defaultCtx, change.Id, "lgtm")
+		assert.Equal(t, api.ConfigFileChangeSelfApproval, rsp.Code.GetValue())
+
+		reviewerCtx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "reviewer")
+		rsp = testSuit.testServer.ApproveConfigFileChange(reviewerCtx, change.Id, "lgtm")
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		approved, _ := testSuit.testService.GetConfigFileChange(testSuit.defaultCtx, change.Id)
+		assert.Equal(t, "reviewer", approved.ReviewBy)
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		published, _ := testSuit.testService.GetConfigFileChange(testSuit.defaultCtx, change.Id)
+		assert.Equal(t, utils.ChangeStatusPublished, published.Status)
+	})
+
+	t.Run("修改配置文件生成相对发布内容的diff", func(t *testing.T) {
+		oldContent := configFile.Content.GetValue()
+		configFile.Content = utils.NewStringValue("k1=v3,k2=v2")
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		pending := latestConfigFileChange(t, testSuit)
+		assert.NotEqual(t, change.Id, pending.Id)
+		assert.Equal(t, utils.ChangeStatusPending, pending.Status)
+		assert.True(t, strings.Contains(pending.Diff, "-"+oldContent))
+		assert.True(t, strings.Contains(pending.Diff, "+k1=v3,k2=v2"))
+
+		// 再次编辑时更新待审核的变更
+		configFile.Content = utils.NewStringValue("k1=v4,k2=v2")
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		updated := latestConfigFileChange(t, testSuit)
+		assert.Equal(t, pending.Id, updated.Id)
+		assert.True(t, strings.Contains(updated.Diff, "+k1=v4,k2=v2"))
+
+		// 发布的仍然是上一次审核通过的内容
+		releaseRsp := testSuit.testService.GetConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, oldContent, releaseRsp.ConfigFileRelease.Content.GetValue())
+
+		_, changes, _ := testSuit.testService.QueryConfigFileChanges(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile, utils.ChangeStatusPending, 0, 10)
+		assert.Equal(t, 1, len(changes))
+	})
+
+	t.Run("审核记录在发布历史中", func(t *testing.T) {
+		rsp := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 0, 100, 0)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		statuses := map[string]int{}
+		for _, history := range rsp.ConfigFileReleaseHistories {
+			if history.Type.GetValue() == utils.ReleaseTypeReview {
+				statuses[history.Status.GetValue()]++
+			}
+		}
+		assert.Equal(t, 1, statuses[utils.ChangeStatusRejected])
+		assert.Equal(t, 1, statuses[utils.ChangeStatusApproved])
+		assert.Equal(t, 4, statuses[utils.ChangeStatusPending])
+	})
+
+	t.Run("其他用户编辑待审核的变更时由编辑人重新提交", func(t *testing.T) {
+		pending := latestConfigFileChange(t, testSuit)
+		assert.Equal(t, utils.ChangeStatusPending, pending.Status)
+		assert.Equal(t, "polaris", pending.CreateBy)
+
+		editorCtx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "editor")
+		configFile.Content = utils.NewStringValue("k1=v5,k2=v2")
+		rsp := testSuit.testServer.UpdateConfigFile(editorCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		resubmitted := latestConfigFileChange(t, testSuit)
+		assert.NotEqual(t, pending.Id, resubmitted.Id)
+		assert.Equal(t, utils.ChangeStatusPending, resubmitted.Status)
+		assert.Equal(t, "editor", resubmitted.CreateBy)
+
+		superseded, _ := testSuit.testService.GetConfigFileChange(testSuit.defaultCtx, pending.Id)
+		assert.Equal(t, utils.ChangeStatusSuperseded, superseded.Status)
+		assert.Equal(t, "polaris", superseded.CreateBy)
+
+		// 编辑人不能审核通过自己重新提交的变更
+		rsp = testSuit.testServer.ApproveConfigFileChange(editorCtx, resubmitted.Id, "lgtm")
+		assert.Equal(t, api.ConfigFileChangeSelfApproval, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.ApproveConfigFileChange(testSuit.defaultCtx, resubmitted.Id, "lgtm")
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	})
+
+	t.Run("无法解析审核人时拒绝审核", func(t *testing.T) {
+		configFile.Content = utils.NewStringValue("k1=v6,k2=v2")
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		pending := latestConfigFileChange(t, testSuit)
+
+		anonymousCtx := context.WithValue(context.Background(), utils.StringContext("request-id"), "anonymous")
+		rsp = testSuit.testService.RejectConfigFileChange(anonymousCtx, pending.Id, "")
+		assert.NotEqual(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		unchanged, _ := testSuit.testService.GetConfigFileChange(testSuit.defaultCtx, pending.Id)
+		assert.Equal(t, utils.ChangeStatusPending, unchanged.Status)
+	})
+}
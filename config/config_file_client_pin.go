@@ -0,0 +1,241 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"sync"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/cache"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+// maxClientPinLength 被固定的客户端标识的最大长度，与存储层的字段长度一致
+const maxClientPinLength = 128
+
+// clientPinBucket 缓存全部的客户端版本固定，由发布事件扫描器定时全量刷新
+type clientPinBucket struct {
+	lock sync.RWMutex
+	pins map[string]*model.ConfigFileClientPin // fileId#client -> pin
+}
+
+func newClientPinBucket() *clientPinBucket {
+	return &clientPinBucket{
+		pins: make(map[string]*model.ConfigFileClientPin),
+	}
+}
+
+func clientPinKey(namespace, group, fileName, client string) string {
+	return utils.GenFileId(namespace, group, fileName) + "#" + client
+}
+
+// get 获取客户端的版本固定，按照客户端 ID 固定的优先于按照客户端 IP 固定的
+func (b *clientPinBucket) get(namespace, group, fileName string, client clientIdentity) *model.ConfigFileClientPin {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for _, target := range []string{client.ID, client.IP} {
+		if target == "" {
+			continue
+		}
+		if pin, ok := b.pins[clientPinKey(namespace, group, fileName, target)]; ok {
+			return pin
+		}
+	}
+	return nil
+}
+
+// reload 使用存储层的全量数据替换缓存，返回新增或者固定内容发生变化的记录，以及被删除的记录
+func (b *clientPinBucket) reload(pins []*model.ConfigFileClientPin) (changed, removed []*model.ConfigFileClientPin) {
+	latest := make(map[string]*model.ConfigFileClientPin, len(pins))
+	for _, pin := range pins {
+		latest[clientPinKey(pin.Namespace, pin.Group, pin.FileName, pin.Client)] = pin
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for key, pin := range latest {
+		if old, ok := b.pins[key]; !ok || old.Md5 != pin.Md5 {
+			changed = append(changed, pin)
+		}
+	}
+	for key, pin := range b.pins {
+		if _, ok := latest[key]; !ok {
+			removed = append(removed, pin)
+		}
+	}
+	b.pins = latest
+	return changed, removed
+}
+
+// pinnedEntry 被固定的客户端使用固定的内容比较配置是否变化。版本号沿用当前的发布版本，
+// 保证客户端的版本号不会回退，固定内容与客户端内容是否一致通过 md5 判断
+func pinnedEntry(pin *model.ConfigFileClientPin, current *cache.Entry) *cache.Entry {
+	return &cache.Entry{
+		Content: pin.Content,
+		Md5:     pin.Md5,
+		Version: current.Version,
+	}
+}
+
+// clientPinEntry 被固定的客户端对应的缓存对象，灰度发布中时版本号沿用灰度发布的版本号
+func (s *Server) clientPinEntry(pin *model.ConfigFileClientPin, entry *cache.Entry) *cache.Entry {
+	if gray := s.grayReleases.get(pin.Namespace, pin.Group, pin.FileName); gray != nil &&
+		gray.Version > entry.Version {
+		entry = gray.entry()
+	}
+	return pinnedEntry(pin, entry)
+}
+
+// clientPinEvent 客户端版本固定变化事件，只通知被固定的客户端
+type clientPinEvent struct {
+	Pin *model.ConfigFileClientPin
+	// Removed 为 true 时表示取消固定，客户端需要重新获取当前的发布内容
+	Removed bool
+	// Entry 当前的全量发布
+	Entry *cache.Entry
+	// Gray 当前生效中的灰度发布，没有灰度发布时为 nil
+	Gray *grayRelease
+}
+
+// PinConfigFileClient 将客户端固定到指定的发布历史版本，已经固定的客户端更新为新的版本
+func (s *Server) PinConfigFileClient(ctx context.Context, pin *model.ConfigFileClientPin) *api.ConfigResponse {
+	if rsp := checkGrayReleaseParams(pin.Namespace, pin.Group, pin.FileName); rsp != nil {
+		return rsp
+	}
+	if pin.Client == "" || utf8.RuneCountInString(pin.Client) > maxClientPinLength {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid client "+pin.Client)
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+
+	history, err := s.storage.GetConfigFileReleaseHistory(pin.HistoryId)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file release history error.",
+			zap.String("request-id", requestID),
+			zap.Uint64("historyId", pin.HistoryId),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if history == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	if history.Namespace != pin.Namespace || history.Group != pin.Group || history.FileName != pin.FileName {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter,
+			"release history does not belong to the config file")
+	}
+	if history.Type == utils.ReleaseTypeDelete || history.Status != utils.ReleaseStatusSuccess {
+		return api.NewConfigFileResponseWithMessage(api.BadRequest,
+			"only successful release history can be pinned")
+	}
+
+	// 加密的配置文件按照当前的加密设置重新加密历史版本的内容，客户端使用当前的数据密钥解密
+	content, err := s.rollbackContent(history)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] prepare pinned content error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(pin.Namespace, pin.Group, pin.FileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.ExecuteException, nil)
+	}
+
+	userName := utils.ParseUserName(ctx)
+	pin.Content = content
+	pin.Md5 = utils2.CalMd5(content)
+	pin.CreateBy = userName
+	pin.ModifyBy = userName
+
+	saved, err := s.storage.GetConfigFileClientPin(pin.Namespace, pin.Group, pin.FileName, pin.Client)
+	if err == nil {
+		if saved == nil {
+			_, err = s.storage.CreateConfigFileClientPin(pin)
+		} else {
+			err = s.storage.UpdateConfigFileClientPin(pin)
+		}
+	}
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] save config file client pin error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(pin.Namespace, pin.Group, pin.FileName)),
+			zap.String("client", pin.Client),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	log.ConfigScope().Info("[Config][Service] pin config file client.",
+		zap.String("request-id", requestID),
+		zap.String("file", utils.GenFileId(pin.Namespace, pin.Group, pin.FileName)),
+		zap.String("client", pin.Client),
+		zap.Uint64("historyId", pin.HistoryId))
+
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// UnpinConfigFileClient 取消客户端的版本固定，客户端重新获取当前的发布内容
+func (s *Server) UnpinConfigFileClient(ctx context.Context, namespace, group, fileName,
+	client string) *api.ConfigResponse {
+	if rsp := checkGrayReleaseParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+	if client == "" {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "client can not be empty")
+	}
+
+	if err := s.storage.DeleteConfigFileClientPin(namespace, group, fileName, client); err != nil {
+		log.ConfigScope().Error("[Config][Service] delete config file client pin error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.String("client", client),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// QueryConfigFileClientPins 查询配置文件的全部客户端版本固定
+func (s *Server) QueryConfigFileClientPins(ctx context.Context, namespace, group,
+	fileName string) ([]*model.ConfigFileClientPin, *api.ConfigResponse) {
+	if rsp := checkGrayReleaseParams(namespace, group, fileName); rsp != nil {
+		return nil, rsp
+	}
+
+	pins, err := s.storage.QueryConfigFileClientPins(namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file client pins error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return pins, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// matchClientPin 获取客户端的版本固定，客户端没有被固定时返回 nil
+func (s *Server) matchClientPin(ctx context.Context, namespace, group, fileName string) *model.ConfigFileClientPin {
+	if s.clientPins == nil {
+		return nil
+	}
+	return s.clientPins.get(namespace, group, fileName, s.parseClient(ctx))
+}
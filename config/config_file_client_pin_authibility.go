@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// PinConfigFileClient 将客户端固定到指定的发布历史版本
+func (s *serverAuthability) PinConfigFileClient(ctx context.Context,
+	pin *model.ConfigFileClientPin) *api.ConfigResponse {
+
+	authCtx, rsp := s.checkConfigFileReleasePermission(ctx, pin.Namespace, pin.Group, pin.FileName,
+		"PinConfigFileClient")
+	if rsp != nil {
+		return rsp
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.PinConfigFileClient(ctx, pin)
+}
+
+// UnpinConfigFileClient 取消客户端的版本固定
+func (s *serverAuthability) UnpinConfigFileClient(ctx context.Context,
+	namespace, group, fileName, client string) *api.ConfigResponse {
+
+	authCtx, rsp := s.checkConfigFileReleasePermission(ctx, namespace, group, fileName, "UnpinConfigFileClient")
+	if rsp != nil {
+		return rsp
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.UnpinConfigFileClient(ctx, namespace, group, fileName, client)
+}
+
+// QueryConfigFileClientPins 查询配置文件的全部客户端版本固定
+func (s *serverAuthability) QueryConfigFileClientPins(ctx context.Context,
+	namespace, group, fileName string) ([]*model.ConfigFileClientPin, *api.ConfigResponse) {
+
+	return s.targetServer.QueryConfigFileClientPins(ctx, namespace, group, fileName)
+}
@@ -0,0 +1,198 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// TestPinConfigFileClient 测试固定客户端的配置版本，被固定的客户端获取历史版本的内容，其他客户端不受影响
+func TestPinConfigFileClient(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	pinnedContent := configFile.Content.GetValue()
+	latestContent := "k1=latest"
+	configFile.Content = utils.NewStringValue(latestContent)
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	historyRsp := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx, testNamespace, testGroup,
+		testFile, 0, 10, 0)
+	assert.Equal(t, api.ExecuteSuccess, historyRsp.Code.GetValue())
+	var historyId uint64
+	for _, history := range historyRsp.ConfigFileReleaseHistories {
+		if history.Content.GetValue() == pinnedContent {
+			historyId = history.Id.GetValue()
+		}
+	}
+	assert.NotZero(t, historyId)
+
+	pin := func(client string, historyId uint64) *api.ConfigResponse {
+		return testSuit.testService.PinConfigFileClient(testSuit.defaultCtx, &model.ConfigFileClientPin{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+			Client:    client,
+			HistoryId: historyId,
+		})
+	}
+
+	t.Run("参数不合法", func(t *testing.T) {
+		assert.Equal(t, api.InvalidParameter, pin("", historyId).Code.GetValue())
+		assert.Equal(t, api.InvalidParameter, pin(strings.Repeat("a", maxClientPinLength+1), historyId).Code.GetValue())
+		assert.Equal(t, api.NotFoundResource, pin("127.0.0.1", historyId+100).Code.GetValue())
+	})
+
+	t.Run("固定客户端版本", func(t *testing.T) {
+		assert.Equal(t, api.ExecuteSuccess, pin("127.0.0.1", historyId).Code.GetValue())
+
+		pins, rsp := testSuit.testService.QueryConfigFileClientPins(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, 1, len(pins))
+		assert.Equal(t, "127.0.0.1", pins[0].Client)
+
+		// 等待扫描器加载客户端版本固定
+		time.Sleep(3 * time.Second)
+
+		pinned := getConfigFileWithClientIP(testSuit, "127.0.0.1")
+		assert.Equal(t, api.ExecuteSuccess, pinned.Code.GetValue())
+		assert.Equal(t, uint64(2), pinned.ConfigFile.Version.GetValue())
+		assert.Equal(t, pinnedContent, pinned.ConfigFile.Content.GetValue())
+
+		other := getConfigFileWithClientIP(testSuit, "127.0.0.2")
+		assert.Equal(t, api.ExecuteSuccess, other.Code.GetValue())
+		assert.Equal(t, uint64(2), other.ConfigFile.Version.GetValue())
+		assert.Equal(t, latestContent, other.ConfigFile.Content.GetValue())
+
+		// 被固定的客户端通过 md5 比较内容是否变化
+		check := testSuit.testServer.doCheckClientConfigFile(withClientIP(testSuit.defaultCtx, "127.0.0.1"),
+			[]*api.ClientConfigFileInfo{{
+				Namespace: utils.NewStringValue(testNamespace),
+				Group:     utils.NewStringValue(testGroup),
+				FileName:  utils.NewStringValue(testFile),
+				Version:   utils.NewUInt64Value(2),
+				Md5:       other.ConfigFile.Md5,
+			}}, compareByVersion)
+		assert.Equal(t, api.ExecuteSuccess, check.Code.GetValue())
+
+		check = testSuit.testServer.doCheckClientConfigFile(withClientIP(testSuit.defaultCtx, "127.0.0.1"),
+			[]*api.ClientConfigFileInfo{{
+				Namespace: utils.NewStringValue(testNamespace),
+				Group:     utils.NewStringValue(testGroup),
+				FileName:  utils.NewStringValue(testFile),
+				Version:   utils.NewUInt64Value(2),
+				Md5:       pinned.ConfigFile.Md5,
+			}}, compareByVersion)
+		assert.Equal(t, api.DataNoChange, check.Code.GetValue())
+	})
+
+	t.Run("被固定的客户端不接收新的发布", func(t *testing.T) {
+		received := make(chan uint64, 1)
+		watchConfigFiles := assembleDefaultClientConfigFile(2)
+		pinnedClient := "TestPinConfigFileClient-pinned"
+		otherClient := "TestPinConfigFileClient-other"
+		defer func() {
+			testSuit.testServer.WatchCenter().RemoveWatcher(pinnedClient, watchConfigFiles)
+			testSuit.testServer.WatchCenter().RemoveWatcher(otherClient, watchConfigFiles)
+		}()
+		testSuit.testServer.WatchCenter().AddWatcher(pinnedClient, clientIdentity{IP: "127.0.0.1"}, watchConfigFiles,
+			func(clientId string, rsp *api.ConfigClientResponse) bool {
+				t.Errorf("pinned client %s should not receive new release", clientId)
+				return true
+			})
+		testSuit.testServer.WatchCenter().AddWatcher(otherClient, clientIdentity{IP: "127.0.0.2"}, watchConfigFiles,
+			func(clientId string, rsp *api.ConfigClientResponse) bool {
+				received <- rsp.ConfigFile.Version.GetValue()
+				return true
+			})
+
+		configFile.Content = utils.NewStringValue("k1=newest")
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		assert.Equal(t, uint64(3), <-received)
+
+		pinned := getConfigFileWithClientIP(testSuit, "127.0.0.1")
+		assert.Equal(t, uint64(3), pinned.ConfigFile.Version.GetValue())
+		assert.Equal(t, pinnedContent, pinned.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("取消固定客户端版本", func(t *testing.T) {
+		rsp := testSuit.testService.UnpinConfigFileClient(testSuit.defaultCtx, testNamespace, testGroup, testFile,
+			"127.0.0.1")
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		time.Sleep(3 * time.Second)
+
+		pinned := getConfigFileWithClientIP(testSuit, "127.0.0.1")
+		assert.Equal(t, uint64(3), pinned.ConfigFile.Version.GetValue())
+		assert.Equal(t, "k1=newest", pinned.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("按照客户端 ID 固定版本", func(t *testing.T) {
+		getConfigFile := func(clientID string) *api.ConfigClientResponse {
+			ctx := context.WithValue(withClientIP(testSuit.defaultCtx, "127.0.0.1"), utils.ContextClientIDKey, clientID)
+			return testSuit.testService.GetConfigFileForClient(ctx, &api.ClientConfigFileInfo{
+				Namespace: utils.NewStringValue(testNamespace),
+				Group:     utils.NewStringValue(testGroup),
+				FileName:  utils.NewStringValue(testFile),
+				Version:   utils.NewUInt64Value(0),
+			})
+		}
+
+		assert.Equal(t, api.ExecuteSuccess, pin("TestPinConfigFileClient-id", historyId).Code.GetValue())
+		time.Sleep(3 * time.Second)
+
+		// 同一个 IP 上只有客户端 ID 一致的客户端被固定
+		assert.Equal(t, pinnedContent, getConfigFile("TestPinConfigFileClient-id").ConfigFile.Content.GetValue())
+		assert.Equal(t, "k1=newest", getConfigFile("TestPinConfigFileClient-other").ConfigFile.Content.GetValue())
+
+		rsp := testSuit.testService.UnpinConfigFileClient(testSuit.defaultCtx, testNamespace, testGroup, testFile,
+			"TestPinConfigFileClient-id")
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	})
+}
@@ -0,0 +1,246 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+const (
+	// maskedContent 没有权限查看加密配置文件时，返回脱敏后的内容
+	maskedContent = "******"
+)
+
+var (
+	errConfigEncryptNotEnabled = errors.New("config encryption is not enabled")
+)
+
+// encryptConfigFile 加密配置文件内容，返回需要存储的内容。
+// 数据密钥在配置文件开启加密时生成，经过 KMS 加密后保存在内置标签中，保持加密期间不会变化；
+// 关闭加密时删除数据密钥，存储明文内容，再次开启加密时重新生成。切换加密设置后需要重新发布，发布内容才会随之切换。
+// 客户端传入的内置标签会被忽略，以存储的数据密钥为准
+func (s *Server) encryptConfigFile(ctx context.Context, configFile *api.ConfigFile,
+	managedFile *model.ConfigFile) (string, *api.ConfigResponse) {
+
+	namespace := configFile.Namespace.GetValue()
+	group := configFile.Group.GetValue()
+	name := configFile.Name.GetValue()
+	content := configFile.Content.GetValue()
+
+	var storedTags []*model.ConfigFileTag
+	if managedFile != nil {
+		tags, err := s.storage.QueryTagByConfigFile(namespace, group, name)
+		if err != nil {
+			log.ConfigScope().Error("[Config][Service] query config file tags error.",
+				zap.String("request-id", utils.ParseRequestID(ctx)),
+				zap.String("namespace", namespace),
+				zap.String("group", group),
+				zap.String("fileName", name),
+				zap.Error(err))
+			return "", api.NewConfigFileResponse(api.StoreLayerException, configFile)
+		}
+		storedTags = tags
+	}
+
+	encrypted := false
+	tags := make([]*api.ConfigFileTag, 0, len(configFile.Tags)+2)
+	for _, tag := range configFile.Tags {
+		switch tag.Key.GetValue() {
+		case utils.ConfigFileTagKeyDataKey, utils.ConfigFileTagKeyEncryptAlgo:
+			continue
+		case utils.ConfigFileTagKeyUseEncrypted:
+			encrypted = tag.Value.GetValue() == "true"
+		}
+		tags = append(tags, tag)
+	}
+
+	var dataKey, algorithm string
+	if encrypted {
+		dataKey, algorithm = dataKeyOfStoreTags(storedTags)
+	}
+
+	if encrypted && dataKey == "" {
+		if s.crypto == nil || s.kms == nil {
+			return "", api.NewConfigFileResponseWithMessage(api.BadRequest, errConfigEncryptNotEnabled.Error())
+		}
+		key, err := s.crypto.GenerateKey()
+		if err == nil {
+			dataKey, err = s.kms.EncryptDataKey(key)
+		}
+		if err != nil {
+			log.ConfigScope().Error("[Config][Service] generate config file data key error.",
+				zap.String("request-id", utils.ParseRequestID(ctx)),
+				zap.String("namespace", namespace),
+				zap.String("group", group),
+				zap.String("fileName", name),
+				zap.Error(err))
+			return "", api.NewConfigFileResponse(api.ExecuteException, configFile)
+		}
+		algorithm = s.crypto.Name()
+	}
+	if dataKey != "" {
+		tags = append(tags,
+			&api.ConfigFileTag{
+				Key:   utils.NewStringValue(utils.ConfigFileTagKeyDataKey),
+				Value: utils.NewStringValue(dataKey),
+			},
+			&api.ConfigFileTag{
+				Key:   utils.NewStringValue(utils.ConfigFileTagKeyEncryptAlgo),
+				Value: utils.NewStringValue(algorithm),
+			})
+	}
+	configFile.Tags = tags
+
+	if !encrypted {
+		return content, nil
+	}
+
+	// 内容没有变化时沿用已存储的密文，避免配置文件被误判为待发布
+	if managedFile != nil {
+		if plaintext, err := s.decryptContent(managedFile.Content, dataKey); err == nil && plaintext == content {
+			return managedFile.Content, nil
+		}
+	}
+	ciphertext, err := s.encryptContent(content, dataKey)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] encrypt config file error.",
+			zap.String("request-id", utils.ParseRequestID(ctx)),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", name),
+			zap.Error(err))
+		if err == errConfigEncryptNotEnabled {
+			return "", api.NewConfigFileResponseWithMessage(api.BadRequest, err.Error())
+		}
+		return "", api.NewConfigFileResponse(api.ExecuteException, configFile)
+	}
+	return ciphertext, nil
+}
+
+// encryptContent 使用 KMS 加密后的数据密钥加密内容
+func (s *Server) encryptContent(content, dataKey string) (string, error) {
+	if s.crypto == nil || s.kms == nil {
+		return "", errConfigEncryptNotEnabled
+	}
+	key, err := s.kms.DecryptDataKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+	return s.crypto.Encrypt(content, key)
+}
+
+// decryptContent 使用 KMS 加密后的数据密钥解密内容，数据密钥为空表示内容没有加密，原样返回
+func (s *Server) decryptContent(content, dataKey string) (string, error) {
+	if dataKey == "" || content == "" {
+		return content, nil
+	}
+	if s.crypto == nil || s.kms == nil {
+		return "", errConfigEncryptNotEnabled
+	}
+	key, err := s.kms.DecryptDataKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+	return s.crypto.Decrypt(content, key)
+}
+
+// rollbackContent 历史版本的内容使用历史版本的数据密钥解密后，按照配置文件当前的加密设置重新加密
+func (s *Server) rollbackContent(history *model.ConfigFileReleaseHistory) (string, error) {
+	historyDataKey, _ := dataKeyOfAPITags(utils2.FromTagJson(history.Tags))
+	content, err := s.decryptContent(history.Content, historyDataKey)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := s.storage.QueryTagByConfigFile(history.Namespace, history.Group, history.FileName)
+	if err != nil {
+		return "", err
+	}
+	dataKey, _ := dataKeyOfStoreTags(tags)
+	if dataKey == "" || !isEncryptedByStoreTags(tags) {
+		return content, nil
+	}
+	return s.encryptContent(content, dataKey)
+}
+
+// genClientEncryptedContent 使用客户端的公钥加密数据密钥，和密文一起下发给客户端
+func (s *Server) genClientEncryptedContent(ciphertext, dataKey, publicKey string) (string, error) {
+	key, err := s.kms.DecryptDataKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+	clientDataKey, err := utils2.EncryptByPublicKey(publicKey, key)
+	if err != nil {
+		return "", err
+	}
+	return utils2.GenEncryptedContent(s.crypto.Name(), clientDataKey, ciphertext)
+}
+
+// fileDataKey 查询配置文件的数据密钥
+func (s *Server) fileDataKey(namespace, group, fileName string) (string, error) {
+	tags, err := s.storage.QueryTagByConfigFile(namespace, group, fileName)
+	if err != nil {
+		return "", err
+	}
+	dataKey, _ := dataKeyOfStoreTags(tags)
+	return dataKey, nil
+}
+
+func dataKeyOfStoreTags(tags []*model.ConfigFileTag) (string, string) {
+	var dataKey, algorithm string
+	for _, tag := range tags {
+		switch tag.Key {
+		case utils.ConfigFileTagKeyDataKey:
+			dataKey = tag.Value
+		case utils.ConfigFileTagKeyEncryptAlgo:
+			algorithm = tag.Value
+		}
+	}
+	return dataKey, algorithm
+}
+
+func dataKeyOfAPITags(tags []*api.ConfigFileTag) (string, string) {
+	var dataKey, algorithm string
+	for _, tag := range tags {
+		switch tag.Key.GetValue() {
+		case utils.ConfigFileTagKeyDataKey:
+			dataKey = tag.Value.GetValue()
+		case utils.ConfigFileTagKeyEncryptAlgo:
+			algorithm = tag.Value.GetValue()
+		}
+	}
+	return dataKey, algorithm
+}
+
+func isEncryptedByStoreTags(tags []*model.ConfigFileTag) bool {
+	for _, tag := range tags {
+		if tag.Key == utils.ConfigFileTagKeyUseEncrypted && tag.Value == "true" {
+			return true
+		}
+	}
+	return false
+}
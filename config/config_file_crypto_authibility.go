@@ -0,0 +1,219 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// decryptChecker 判断调用方是否可以查看加密配置文件的明文，同一个请求内按照配置分组缓存鉴权结果
+type decryptChecker struct {
+	s       *serverAuthability
+	ctx     context.Context
+	results map[string]bool
+}
+
+func (s *serverAuthability) newDecryptChecker(ctx context.Context) *decryptChecker {
+	return &decryptChecker{
+		s:       s,
+		ctx:     ctx,
+		results: map[string]bool{},
+	}
+}
+
+// allow 控制台查看加密配置文件的明文需要拥有配置分组的写权限
+func (c *decryptChecker) allow(namespace, group, fileName string) bool {
+	key := namespace + "@" + group
+	if ret, ok := c.results[key]; ok {
+		return ret
+	}
+	authCtx := c.s.collectConfigFileAuthContext(c.ctx, []*api.ConfigFile{{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		Name:      utils.NewStringValue(fileName),
+	}}, model.Modify, "DecryptConfigFile")
+	_, err := c.s.checker.CheckConsolePermission(authCtx)
+	c.results[key] = err == nil
+	return err == nil
+}
+
+// decrypt 解密内容，没有数据密钥时原样返回，没有权限或者解密失败时返回脱敏后的内容
+func (c *decryptChecker) decrypt(namespace, group, fileName string, content *wrappers.StringValue,
+	dataKey string) *wrappers.StringValue {
+	if content == nil || dataKey == "" {
+		return content
+	}
+	if !c.allow(namespace, group, fileName) {
+		return utils.NewStringValue(maskedContent)
+	}
+	plaintext, err := c.s.targetServer.decryptContent(content.GetValue(), dataKey)
+	if err != nil {
+		// 解密失败时不返回密文，按照脱敏内容返回
+		log.ConfigScope().Error("[Config][Service] decrypt config file error.",
+			zap.String("request-id", utils.ParseRequestID(c.ctx)),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return utils.NewStringValue(maskedContent)
+	}
+	return utils.NewStringValue(plaintext)
+}
+
+// fileDataKey 查询配置文件的数据密钥，查询失败时按照未加密处理，返回的密文内容不会泄露明文
+func (c *decryptChecker) fileDataKey(namespace, group, fileName string) string {
+	dataKey, err := c.s.targetServer.fileDataKey(namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file data key error.",
+			zap.String("request-id", utils.ParseRequestID(c.ctx)),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+	}
+	return dataKey
+}
+
+func (c *decryptChecker) decryptConfigFiles(files ...*api.ConfigFile) {
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		namespace, group, name := file.Namespace.GetValue(), file.Group.GetValue(), file.Name.GetValue()
+		dataKey, _ := dataKeyOfAPITags(file.Tags)
+		if dataKey == "" && file.Tags == nil {
+			dataKey = c.fileDataKey(namespace, group, name)
+		}
+		file.Content = c.decrypt(namespace, group, name, file.Content, dataKey)
+	}
+}
+
+func (c *decryptChecker) decryptConfigFileReleases(releases ...*api.ConfigFileRelease) {
+	for _, release := range releases {
+		if release == nil {
+			continue
+		}
+		namespace, group, name := release.Namespace.GetValue(), release.Group.GetValue(), release.FileName.GetValue()
+		release.Content = c.decrypt(namespace, group, name, release.Content, c.fileDataKey(namespace, group, name))
+	}
+}
+
+// decryptReleaseHistories 发布历史记录了发布时的标签，使用发布时的数据密钥解密
+func (c *decryptChecker) decryptReleaseHistories(histories ...*api.ConfigFileReleaseHistory) {
+	for _, history := range histories {
+		if history == nil {
+			continue
+		}
+		namespace, group, name := history.Namespace.GetValue(), history.Group.GetValue(), history.FileName.GetValue()
+		dataKey, _ := dataKeyOfAPITags(history.Tags)
+		history.Content = c.decrypt(namespace, group, name, history.Content, dataKey)
+	}
+}
+
+func (c *decryptChecker) decryptGrayRelease(gray *model.ConfigFileGrayRelease) *model.ConfigFileGrayRelease {
+	if gray == nil {
+		return nil
+	}
+	dataKey := c.fileDataKey(gray.Namespace, gray.Group, gray.FileName)
+	content := c.decrypt(gray.Namespace, gray.Group, gray.FileName, utils.NewStringValue(gray.Content), dataKey)
+	ret := *gray
+	ret.Content = content.GetValue()
+	return &ret
+}
+
+// decryptConfigFileChange 配置文件变更的内容以及 diff 使用配置文件的数据密钥解密
+func (c *decryptChecker) decryptConfigFileChange(change *model.ConfigFileChange) *model.ConfigFileChange {
+	if change == nil {
+		return nil
+	}
+	dataKey := c.fileDataKey(change.Namespace, change.Group, change.FileName)
+	ret := *change
+	ret.Content = c.decrypt(change.Namespace, change.Group, change.FileName,
+		utils.NewStringValue(change.Content), dataKey).GetValue()
+	ret.Diff = c.decrypt(change.Namespace, change.Group, change.FileName,
+		utils.NewStringValue(change.Diff), dataKey).GetValue()
+	return &ret
+}
+
+// decryptClientConfigFile 客户端获取加密的配置文件。
+// 客户端携带公钥时，下发密文以及使用公钥加密后的数据密钥；否则开启客户端鉴权并且鉴权通过后下发明文
+func (s *serverAuthability) decryptClientConfigFile(ctx context.Context,
+	resp *api.ConfigClientResponse) *api.ConfigClientResponse {
+
+	file := resp.GetConfigFile()
+	if resp.GetCode().GetValue() != api.ExecuteSuccess || file == nil {
+		return resp
+	}
+	namespace, group, fileName := file.GetNamespace().GetValue(), file.GetGroup().GetValue(),
+		file.GetFileName().GetValue()
+
+	entry, err := s.targetServer.fileCache.GetOrLoadIfAbsent(namespace, group, fileName)
+	if err != nil {
+		return api.NewConfigClientResponseWithMessage(api.ExecuteException, "load config file error")
+	}
+	if entry.DataKey == "" {
+		return resp
+	}
+	plaintext, err := s.targetServer.decryptContent(file.GetContent().GetValue(), entry.DataKey)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Client] decrypt config file error.",
+			zap.String("requestId", utils.ParseRequestID(ctx)),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("file", fileName),
+			zap.Error(err))
+		return api.NewConfigClientResponseWithMessage(api.ExecuteException, "decrypt config file error")
+	}
+
+	if publicKey, _ := ctx.Value(utils.ContextConfigPublicKey).(string); publicKey != "" {
+		content, err := s.targetServer.genClientEncryptedContent(file.GetContent().GetValue(), entry.DataKey, publicKey)
+		if err != nil {
+			log.ConfigScope().Error("[Config][Client] encrypt config file data key for client error.",
+				zap.String("requestId", utils.ParseRequestID(ctx)),
+				zap.String("namespace", namespace),
+				zap.String("group", group),
+				zap.String("file", fileName),
+				zap.Error(err))
+			return api.NewConfigClientResponseWithMessage(api.InvalidParameter, "invalid client public key")
+		}
+		file.Content = utils.NewStringValue(content)
+		return resp
+	}
+
+	if s.checker.IsOpenClientAuth() {
+		authCtx := s.collectConfigFileAuthContext(ctx, []*api.ConfigFile{{
+			Namespace: file.GetNamespace(),
+			Group:     file.GetGroup(),
+			Name:      file.GetFileName(),
+		}}, model.Read, "GetConfigFileForClient")
+		if _, err := s.checker.CheckClientPermission(authCtx); err == nil {
+			file.Content = utils.NewStringValue(plaintext)
+			return resp
+		}
+	}
+	return api.NewConfigClientResponseWithMessage(api.NotAllowedAccess,
+		"config file is encrypted, client must provide public key or pass client auth")
+}
@@ -0,0 +1,178 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+// TestEncryptedConfigFile 测试加密配置文件的存储、控制台查看以及客户端获取
+func TestEncryptedConfigFile(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	plaintext := "username=polaris\npassword=polaris"
+	configFile := assembleConfigFile()
+	configFile.Content = utils.NewStringValue(plaintext)
+	configFile.Tags = append(configFile.Tags, &api.ConfigFileTag{
+		Key:   utils.NewStringValue(utils.ConfigFileTagKeyUseEncrypted),
+		Value: utils.NewStringValue("true"),
+	}, &api.ConfigFileTag{
+		Key:   utils.NewStringValue(utils.ConfigFileTagKeyDataKey),
+		Value: utils.NewStringValue("client-data-key"),
+	})
+
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, plaintext, rsp.ConfigFile.Content.GetValue())
+
+	t.Run("存储的内容为密文", func(t *testing.T) {
+		storedFile, err := testSuit.testServer.storage.GetConfigFile(nil, testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.NotEqual(t, plaintext, storedFile.Content)
+
+		// 客户端传入的数据密钥被忽略
+		dataKey, err := testSuit.testServer.fileDataKey(testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.NotEqual(t, "", dataKey)
+		assert.NotEqual(t, "client-data-key", dataKey)
+	})
+
+	t.Run("控制台查看明文", func(t *testing.T) {
+		rsp := testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, plaintext, rsp.ConfigFile.Content.GetValue())
+
+		// 内容没有变化的更新不会改变发布状态
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, plaintext, rsp.ConfigFileRelease.Content.GetValue())
+
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, utils.ReleaseStatusSuccess, rsp.ConfigFile.Status.GetValue())
+
+		histories := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 0, 10, 0)
+		assert.Equal(t, api.ExecuteSuccess, histories.Code.GetValue())
+		assert.Equal(t, plaintext, histories.ConfigFileReleaseHistories[0].Content.GetValue())
+	})
+
+	t.Run("没有权限查看脱敏内容", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), utils.StringContext("request-id"), "no-permission")
+		rsp := testSuit.testService.GetConfigFileRichInfo(ctx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, maskedContent, rsp.ConfigFile.Content.GetValue())
+
+		releaseRsp := testSuit.testService.GetConfigFileRelease(ctx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, releaseRsp.Code.GetValue())
+		assert.Equal(t, maskedContent, releaseRsp.ConfigFileRelease.Content.GetValue())
+	})
+
+	fileInfo := &api.ClientConfigFileInfo{
+		Namespace: &wrapperspb.StringValue{Value: testNamespace},
+		Group:     &wrapperspb.StringValue{Value: testGroup},
+		FileName:  &wrapperspb.StringValue{Value: testFile},
+		Version:   &wrapperspb.UInt64Value{Value: 0},
+	}
+
+	t.Run("客户端使用公钥获取数据密钥", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.Nil(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		assert.Nil(t, err)
+
+		ctx := context.WithValue(testSuit.defaultCtx, utils.ContextConfigPublicKey,
+			base64.StdEncoding.EncodeToString(der))
+		rsp := testSuit.testService.GetConfigFileForClient(ctx, fileInfo)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		encrypted := &utils2.EncryptedContent{}
+		assert.Nil(t, json.Unmarshal([]byte(rsp.ConfigFile.Content.GetValue()), encrypted))
+		assert.Equal(t, testSuit.testServer.crypto.Name(), encrypted.Algorithm)
+
+		encryptedKey, err := base64.StdEncoding.DecodeString(encrypted.DataKey)
+		assert.Nil(t, err)
+		dataKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, encryptedKey, nil)
+		assert.Nil(t, err)
+		content, err := testSuit.testServer.crypto.Decrypt(encrypted.Content, dataKey)
+		assert.Nil(t, err)
+		assert.Equal(t, plaintext, content)
+	})
+
+	t.Run("客户端未鉴权不能获取明文", func(t *testing.T) {
+		rsp := testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx, fileInfo)
+		assert.Equal(t, api.NotAllowedAccess, rsp.Code.GetValue())
+	})
+
+	t.Run("解密失败返回错误", func(t *testing.T) {
+		dataKey, err := testSuit.testServer.fileDataKey(testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		_, err = testSuit.testServer.decryptContent("not-a-ciphertext", dataKey)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("关闭加密后删除数据密钥并发布明文", func(t *testing.T) {
+		tags := make([]*api.ConfigFileTag, 0, len(configFile.Tags))
+		for _, tag := range configFile.Tags {
+			if tag.Key.GetValue() == utils.ConfigFileTagKeyUseEncrypted {
+				tag = &api.ConfigFileTag{Key: tag.Key, Value: utils.NewStringValue("false")}
+			}
+			tags = append(tags, tag)
+		}
+		configFile.Tags = tags
+
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		dataKey, err := testSuit.testServer.fileDataKey(testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.Equal(t, "", dataKey)
+		storedFile, err := testSuit.testServer.storage.GetConfigFile(nil, testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.Equal(t, plaintext, storedFile.Content)
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		release, err := testSuit.testServer.storage.GetConfigFileRelease(nil, testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.Equal(t, plaintext, release.Content)
+	})
+}
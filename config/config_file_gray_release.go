@@ -0,0 +1,703 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/cache"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// 灰度规则中可以使用的客户端标签，取值来自客户端通过 ReportClient 上报的信息
+const (
+	ClientLabelHost    = "host"
+	ClientLabelVersion = "version"
+	ClientLabelType    = "type"
+	ClientLabelRegion  = "region"
+	ClientLabelZone    = "zone"
+	ClientLabelCampus  = "campus"
+)
+
+var (
+	errEmptyGrayRule         = errors.New("gray rule must contain client ips, labels or percentage")
+	errInvalidGrayPercentage = errors.New("gray percentage must be between 0 and 100")
+)
+
+// grayRelease 生效中的灰度发布，缓存解析后的灰度规则
+type grayRelease struct {
+	*model.ConfigFileGrayRelease
+	ips        map[string]struct{}
+	nets       []*net.IPNet
+	labels     map[string]string
+	percentage int
+	maxErrors  uint32
+}
+
+func newGrayRelease(release *model.ConfigFileGrayRelease) (*grayRelease, error) {
+	rule := &model.GrayRule{}
+	if err := json.Unmarshal([]byte(release.Rule), rule); err != nil {
+		return nil, err
+	}
+	gray := &grayRelease{
+		ConfigFileGrayRelease: release,
+		ips:                   make(map[string]struct{}),
+		labels:                rule.Labels,
+		percentage:            rule.Percentage,
+		maxErrors:             rule.MaxErrors,
+	}
+	for _, item := range rule.ClientIPs {
+		if strings.Contains(item, "/") {
+			_, ipNet, err := net.ParseCIDR(item)
+			if err != nil {
+				return nil, err
+			}
+			gray.nets = append(gray.nets, ipNet)
+			continue
+		}
+		if net.ParseIP(item) == nil {
+			return nil, errors.New("invalid client ip " + item)
+		}
+		gray.ips[item] = struct{}{}
+	}
+	return gray, nil
+}
+
+// checkGrayRule 检查灰度规则是否合法
+func checkGrayRule(rule *model.GrayRule) error {
+	if rule == nil || (len(rule.ClientIPs) == 0 && len(rule.Labels) == 0 && rule.Percentage == 0) {
+		return errEmptyGrayRule
+	}
+	if rule.Percentage < 0 || rule.Percentage > 100 {
+		return errInvalidGrayPercentage
+	}
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	_, err = newGrayRelease(&model.ConfigFileGrayRelease{Rule: string(data)})
+	return err
+}
+
+// match 客户端 IP 命中 IP 列表或者 CIDR，客户端落在灰度比例内，或者客户端的标签命中全部的灰度标签
+func (g *grayRelease) match(client clientIdentity, labelsOf func(client clientIdentity) map[string]string) bool {
+	if client.key() == "" {
+		return false
+	}
+	if _, ok := g.ips[client.IP]; ok {
+		return true
+	}
+	if ip := net.ParseIP(client.IP); ip != nil {
+		for _, ipNet := range g.nets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	if g.percentage > 0 && grayBucketOf(g.Namespace, g.Group, g.FileName, client.key()) < g.percentage {
+		return true
+	}
+	if len(g.labels) == 0 {
+		return false
+	}
+	clientLabels := labelsOf(client)
+	for key, value := range g.labels {
+		if clientLabels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// grayBucketOf 根据配置文件以及客户端标识计算客户端所在的灰度分桶，取值 0~99。
+// 同一个客户端在同一个配置文件下的分桶固定，提高灰度比例时已经命中的客户端仍然命中
+func grayBucketOf(namespace, group, fileName, clientKey string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(utils.GenFileId(namespace, group, fileName)))
+	_, _ = h.Write([]byte("#"))
+	_, _ = h.Write([]byte(clientKey))
+	return int(h.Sum32() % 100)
+}
+
+// entry 灰度发布对应的缓存对象，命中灰度规则的客户端使用该对象比较版本
+func (g *grayRelease) entry() *cache.Entry {
+	return &cache.Entry{
+		Content: g.Content,
+		Md5:     g.Md5,
+		Version: g.Version,
+	}
+}
+
+// grayReleaseBucket 缓存全部生效中的灰度发布，由发布事件扫描器维护
+type grayReleaseBucket struct {
+	releases *sync.Map // fileId -> *grayRelease
+	// errorReporters 上报过灰度发布加载失败的客户端，同一个客户端对同一个灰度内容只计数一次
+	errorReporters map[string]*grayErrorReporters // fileId -> *grayErrorReporters
+	lock           sync.Mutex
+}
+
+type grayErrorReporters struct {
+	md5     string
+	clients map[string]struct{}
+}
+
+func newGrayReleaseBucket() *grayReleaseBucket {
+	return &grayReleaseBucket{
+		releases:       new(sync.Map),
+		errorReporters: make(map[string]*grayErrorReporters),
+	}
+}
+
+func (b *grayReleaseBucket) get(namespace, group, fileName string) *grayRelease {
+	val, ok := b.releases.Load(utils.GenFileId(namespace, group, fileName))
+	if !ok {
+		return nil
+	}
+	return val.(*grayRelease)
+}
+
+// save 保存灰度发布，只有版本号更大的灰度发布才会覆盖缓存，返回是否更新了缓存
+func (b *grayReleaseBucket) save(release *model.ConfigFileGrayRelease) (*grayRelease, bool) {
+	if old := b.get(release.Namespace, release.Group, release.FileName); old != nil &&
+		old.Version >= release.Version {
+		return old, false
+	}
+	gray, err := newGrayRelease(release)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Gray] parse gray rule error.",
+			zap.String("file", utils.GenFileId(release.Namespace, release.Group, release.FileName)),
+			zap.Error(err))
+		return nil, false
+	}
+	b.releases.Store(utils.GenFileId(release.Namespace, release.Group, release.FileName), gray)
+	return gray, true
+}
+
+func (b *grayReleaseBucket) remove(namespace, group, fileName string) {
+	fileId := utils.GenFileId(namespace, group, fileName)
+	b.releases.Delete(fileId)
+
+	b.lock.Lock()
+	delete(b.errorReporters, fileId)
+	b.lock.Unlock()
+}
+
+// markErrorReported 记录客户端上报了灰度内容加载失败，客户端第一次上报时返回 true
+func (b *grayReleaseBucket) markErrorReported(fileId, md5, clientKey string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	reporters, ok := b.errorReporters[fileId]
+	if !ok || reporters.md5 != md5 {
+		reporters = &grayErrorReporters{md5: md5, clients: make(map[string]struct{})}
+		b.errorReporters[fileId] = reporters
+	}
+	if _, ok := reporters.clients[clientKey]; ok {
+		return false
+	}
+	reporters.clients[clientKey] = struct{}{}
+	return true
+}
+
+// PublishConfigFileGray 灰度发布配置文件，只有命中灰度规则的客户端才会获取到灰度发布的内容
+func (s *Server) PublishConfigFileGray(ctx context.Context, configFileRelease *api.ConfigFileRelease,
+	rule *model.GrayRule) *api.ConfigResponse {
+	namespace := configFileRelease.Namespace.GetValue()
+	group := configFileRelease.Group.GetValue()
+	fileName := configFileRelease.FileName.GetValue()
+
+	if rsp := checkGrayReleaseParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+	if err := checkGrayRule(rule); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+	if !s.checkNamespaceExisted(namespace) {
+		return api.NewConfigFileReleaseResponse(api.NotFoundNamespace, configFileRelease)
+	}
+
+	userName := utils.ParseUserName(ctx)
+	requestID := utils.ParseRequestID(ctx)
+	tx := s.getTx(ctx)
+
+	toPublishFile, err := s.storage.GetConfigFile(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if toPublishFile == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	content, rsp := s.prepareConfigFileContentForPublish(ctx, toPublishFile)
+	if rsp != nil {
+		return rsp
+	}
+	// 灰度发布同样只能发布审核通过的变更，灰度转为全量发布时不需要再次审核
+	if _, rsp := s.checkConfigFileChangeApproved(tx, toPublishFile); rsp != nil {
+		return rsp
+	}
+
+	fullRelease, err := s.storage.GetConfigFileRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	// 灰度发布是在已有的全量发布基础上进行的，没有命中灰度规则的客户端获取全量发布的内容
+	if fullRelease == nil {
+		return api.NewConfigFileResponseWithMessage(api.NotFoundResource,
+			"config file must be published before gray release")
+	}
+
+	managedGrayRelease, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file gray release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	// 灰度发布与全量发布共用版本号，保证客户端总是能感知到版本变化
+	version := fullRelease.Version
+	if managedGrayRelease != nil && managedGrayRelease.Version > version {
+		version = managedGrayRelease.Version
+	}
+
+	ruleData, _ := json.Marshal(rule)
+	releaseName := configFileRelease.Name.GetValue()
+	if releaseName == "" {
+		releaseName = utils2.GenReleaseName(fullRelease.Name, fileName)
+	}
+	toSave := &model.ConfigFileGrayRelease{
+		Name:      releaseName,
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   content,
+		Comment:   configFileRelease.Comment.GetValue(),
+		Md5:       utils2.CalMd5(content),
+		Version:   version + 1,
+		Rule:      string(ruleData),
+		CreateBy:  userName,
+		ModifyBy:  userName,
+	}
+
+	var saved *model.ConfigFileGrayRelease
+	if managedGrayRelease == nil {
+		saved, err = s.storage.CreateConfigFileGrayRelease(tx, toSave)
+	} else {
+		saved, err = s.storage.UpdateConfigFileGrayRelease(tx, toSave)
+	}
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] save config file gray release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		s.recordReleaseHistory(ctx, grayRelease2Release(toSave), utils.ReleaseTypeGray, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordReleaseHistory(ctx, grayRelease2Release(saved), utils.ReleaseTypeGray, utils.ReleaseStatusSuccess)
+
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(grayRelease2Release(saved)))
+}
+
+// GetConfigFileGrayRelease 获取配置文件当前的灰度发布，没有灰度发布时返回 nil
+func (s *Server) GetConfigFileGrayRelease(ctx context.Context, namespace, group,
+	fileName string) (*model.ConfigFileGrayRelease, error) {
+	return s.storage.GetConfigFileGrayRelease(s.getTx(ctx), namespace, group, fileName)
+}
+
+// PromoteConfigFileGray 将灰度发布的内容全量发布，全部客户端都会获取到灰度发布的内容
+func (s *Server) PromoteConfigFileGray(ctx context.Context, namespace, group, fileName string) *api.ConfigResponse {
+	return s.finishConfigFileGray(ctx, namespace, group, fileName, true)
+}
+
+// CancelConfigFileGray 取消灰度发布，命中灰度规则的客户端重新获取全量发布的内容
+func (s *Server) CancelConfigFileGray(ctx context.Context, namespace, group, fileName string) *api.ConfigResponse {
+	return s.finishConfigFileGray(ctx, namespace, group, fileName, false)
+}
+
+// UpdateConfigFileGrayPercentage 提高按比例灰度的比例，已经命中灰度的客户端仍然命中。
+// 灰度比例只能提高，需要缩小灰度范围时取消灰度发布后重新发布
+func (s *Server) UpdateConfigFileGrayPercentage(ctx context.Context, namespace, group, fileName string,
+	percentage int) *api.ConfigResponse {
+	if rsp := checkGrayReleaseParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+	if percentage <= 0 || percentage > 100 {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, errInvalidGrayPercentage.Error())
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+	tx := s.getTx(ctx)
+
+	gray, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file gray release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if gray == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	rule := &model.GrayRule{}
+	if err := json.Unmarshal([]byte(gray.Rule), rule); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+	if percentage <= rule.Percentage {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter,
+			"gray percentage can only be increased, cancel the gray release to shrink it")
+	}
+	rule.Percentage = percentage
+	ruleData, _ := json.Marshal(rule)
+
+	// 版本号递增，发布事件扫描器据此刷新灰度发布缓存并通知新命中的客户端
+	toSave := *gray
+	toSave.Rule = string(ruleData)
+	toSave.Version = gray.Version + 1
+	toSave.ModifyBy = utils.ParseUserName(ctx)
+
+	saved, err := s.storage.UpdateConfigFileGrayRelease(tx, &toSave)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] update config file gray percentage error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Int("percentage", percentage),
+			zap.Error(err))
+		s.recordReleaseHistory(ctx, grayRelease2Release(&toSave), utils.ReleaseTypeGray, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	log.ConfigScope().Info("[Config][Service] update config file gray percentage.",
+		zap.String("request-id", requestID),
+		zap.String("file", utils.GenFileId(namespace, group, fileName)),
+		zap.Int("percentage", percentage))
+
+	s.recordReleaseHistory(ctx, grayRelease2Release(saved), utils.ReleaseTypeGray, utils.ReleaseStatusSuccess)
+
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(grayRelease2Release(saved)))
+}
+
+// ReportConfigFileError 客户端上报加载配置文件失败，客户端加载的是灰度发布的内容时累加灰度发布的失败次数，
+// 失败次数达到灰度规则的 MaxErrors 时自动取消灰度发布
+func (s *Server) ReportConfigFileError(ctx context.Context,
+	client *api.ClientConfigFileInfo) *api.ConfigClientResponse {
+	namespace := client.GetNamespace().GetValue()
+	group := client.GetGroup().GetValue()
+	fileName := client.GetFileName().GetValue()
+	md5 := client.GetMd5().GetValue()
+
+	if namespace == "" || group == "" || fileName == "" || md5 == "" {
+		return api.NewConfigClientResponseWithMessage(api.BadRequest,
+			"namespace & group & fileName & md5 can not be empty")
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+	reporter := s.parseClient(ctx)
+
+	log.ConfigScope().Warn("[Config][Client] client report config file error.",
+		zap.String("requestId", requestID),
+		zap.String("client", reporter.key()),
+		zap.String("file", utils.GenFileId(namespace, group, fileName)),
+		zap.String("md5", md5))
+
+	if s.grayReleases == nil {
+		return api.NewConfigClientResponse(api.ExecuteSuccess, nil)
+	}
+	gray := s.grayReleases.get(namespace, group, fileName)
+	if gray == nil || gray.Md5 != md5 ||
+		!s.grayReleases.markErrorReported(utils.GenFileId(namespace, group, fileName), md5, reporter.key()) {
+		return api.NewConfigClientResponse(api.ExecuteSuccess, nil)
+	}
+
+	count, err := s.storage.IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName, md5)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] incr config file gray release error count error.",
+			zap.String("requestId", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigClientResponse(api.StoreLayerException, nil)
+	}
+
+	if gray.maxErrors == 0 || count < gray.maxErrors {
+		return api.NewConfigClientResponse(api.ExecuteSuccess, nil)
+	}
+
+	log.ConfigScope().Warn("[Config][Service] too many client errors, cancel config file gray release.",
+		zap.String("requestId", requestID),
+		zap.String("file", utils.GenFileId(namespace, group, fileName)),
+		zap.Uint32("errors", count),
+		zap.Uint32("max-errors", gray.maxErrors))
+
+	rsp := s.CancelConfigFileGray(ctx, namespace, group, fileName)
+	if code := rsp.GetCode().GetValue(); code != api.ExecuteSuccess && code != api.NotFoundResource {
+		log.ConfigScope().Error("[Config][Service] auto cancel config file gray release error.",
+			zap.String("requestId", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.String("info", rsp.GetInfo().GetValue()))
+	}
+	return api.NewConfigClientResponse(api.ExecuteSuccess, nil)
+}
+
+// finishConfigFileGray 结束灰度发布，全量发布的版本号会递增到大于灰度发布的版本号，从而通知到全部客户端
+func (s *Server) finishConfigFileGray(ctx context.Context, namespace, group, fileName string,
+	promote bool) *api.ConfigResponse {
+	if rsp := checkGrayReleaseParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+	tx := s.getTx(ctx)
+	if tx == nil {
+		var err error
+		tx, ctx, err = s.StartTxAndSetToContext(ctx)
+		if err != nil {
+			return api.NewConfigFileResponse(api.StoreLayerException, nil)
+		}
+		defer func() { _ = tx.Rollback() }()
+		defer func() {
+			if err := tx.Commit(); err != nil {
+				log.ConfigScope().Error("[Config][Service] commit finish gray release tx error.",
+					zap.String("request-id", requestID), zap.Error(err))
+			}
+		}()
+	}
+
+	gray, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file gray release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if gray == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	fullRelease, err := s.storage.GetConfigFileRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if fullRelease == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	releaseType := utils.ReleaseTypeCancelGray
+	release := &model.ConfigFileRelease{
+		Name:      fullRelease.Name,
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   fullRelease.Content,
+		Comment:   fullRelease.Comment,
+		Md5:       fullRelease.Md5,
+		Version:   gray.Version + 1,
+		ModifyBy:  utils.ParseUserName(ctx),
+	}
+	if promote {
+		releaseType = utils.ReleaseTypeNormal
+		release.Name = gray.Name
+		release.Content = gray.Content
+		release.Comment = gray.Comment
+		release.Md5 = gray.Md5
+	}
+
+	updated, err := s.storage.UpdateConfigFileRelease(tx, release)
+	if err == nil {
+		err = s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName)
+	}
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] finish config file gray release error.",
+			zap.String("request-id", requestID),
+			zap.String("file", utils.GenFileId(namespace, group, fileName)),
+			zap.Bool("promote", promote),
+			zap.Error(err))
+		s.recordReleaseHistory(ctx, release, releaseType, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	s.recordReleaseHistory(ctx, updated, releaseType, utils.ReleaseStatusSuccess)
+
+	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(updated))
+}
+
+// removeGrayRelease 全量发布或者删除发布时结束灰度发布，返回灰度发布的版本号，没有灰度发布时返回 0
+func (s *Server) removeGrayRelease(tx store.Tx, namespace, group, fileName string) (uint64, error) {
+	gray, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil || gray == nil {
+		return 0, err
+	}
+	if err := s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName); err != nil {
+		return 0, err
+	}
+	return gray.Version, nil
+}
+
+// matchGrayRelease 获取客户端命中的灰度发布，没有命中时返回 nil
+func (s *Server) matchGrayRelease(ctx context.Context, namespace, group, fileName string) *grayRelease {
+	if s.grayReleases == nil {
+		return nil
+	}
+	gray := s.grayReleases.get(namespace, group, fileName)
+	if gray == nil || !s.matchGrayRule(s.parseClient(ctx), gray) {
+		return nil
+	}
+	return gray
+}
+
+// matchGrayRule 判断客户端是否命中灰度规则
+func (s *Server) matchGrayRule(client clientIdentity, gray *grayRelease) bool {
+	return gray.match(client, s.clientLabels)
+}
+
+// clientLabels 获取客户端通过 ReportClient 上报的标签，优先按照客户端 ID 查找上报的客户端
+func (s *Server) clientLabels(client clientIdentity) map[string]string {
+	if s.caches == nil || s.caches.Client() == nil {
+		return nil
+	}
+	var reported *model.Client
+	if client.ID != "" {
+		reported = s.caches.Client().GetClient(client.ID)
+	}
+	if reported == nil {
+		// 同一个 IP 上可能存在多个客户端，以最近上报的客户端为准
+		for _, item := range s.caches.Client().GetClientsByHost(client.IP) {
+			if reported == nil || item.ModifyTime().After(reported.ModifyTime()) {
+				reported = item
+			}
+		}
+	}
+	if reported == nil {
+		return nil
+	}
+	proto := reported.Proto()
+	return map[string]string{
+		ClientLabelHost:    proto.GetHost().GetValue(),
+		ClientLabelVersion: proto.GetVersion().GetValue(),
+		ClientLabelType:    proto.GetType().String(),
+		ClientLabelRegion:  proto.GetLocation().GetRegion().GetValue(),
+		ClientLabelZone:    proto.GetLocation().GetZone().GetValue(),
+		ClientLabelCampus:  proto.GetLocation().GetCampus().GetValue(),
+	}
+}
+
+// clientIdentity 客户端标识。灰度分桶以及客户端版本固定优先使用客户端 ID，同一个 IP 上的多个客户端
+// 能够区分开，客户端的 IP 发生变化后分桶也保持不变；没有客户端 ID 时使用客户端 IP
+type clientIdentity struct {
+	ID string
+	IP string
+}
+
+// key 客户端的稳定标识，没有客户端 ID 时为客户端 IP
+func (c clientIdentity) key() string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return c.IP
+}
+
+// is 判断目标是否为该客户端，目标可以是客户端 ID 或者客户端 IP
+func (c clientIdentity) is(target string) bool {
+	return target != "" && (target == c.ID || target == c.IP)
+}
+
+// parseClient 从请求上下文中获取客户端标识。请求没有携带客户端 ID 时，
+// 如果客户端 IP 上只有一个通过 ReportClient 上报的客户端，则使用该客户端的 ID
+func (s *Server) parseClient(ctx context.Context) clientIdentity {
+	client := clientIdentity{IP: parseClientIP(ctx)}
+	client.ID, _ = ctx.Value(utils.ContextClientIDKey).(string)
+	if client.ID != "" || client.IP == "" || s.caches == nil || s.caches.Client() == nil {
+		return client
+	}
+	if reported := s.caches.Client().GetClientsByHost(client.IP); len(reported) == 1 {
+		client.ID = reported[0].Proto().GetId().GetValue()
+	}
+	return client
+}
+
+// parseClientIP 从请求上下文中获取客户端 IP
+func parseClientIP(ctx context.Context) string {
+	if clientIP, _ := ctx.Value(utils.StringContext("client-ip")).(string); clientIP != "" {
+		return clientIP
+	}
+	address := utils.ParseClientAddress(ctx)
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+func checkGrayReleaseParams(namespace, group, fileName string) *api.ConfigResponse {
+	if err := utils2.CheckFileName(utils.NewStringValue(fileName)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileGroupName, nil)
+	}
+	return nil
+}
+
+// grayRelease2Release 灰度发布转换为发布对象，用于记录发布历史以及返回给控制台
+func grayRelease2Release(gray *model.ConfigFileGrayRelease) *model.ConfigFileRelease {
+	return &model.ConfigFileRelease{
+		Id:         gray.Id,
+		Name:       gray.Name,
+		Namespace:  gray.Namespace,
+		Group:      gray.Group,
+		FileName:   gray.FileName,
+		Content:    gray.Content,
+		Comment:    gray.Comment,
+		Md5:        gray.Md5,
+		Version:    gray.Version,
+		CreateTime: gray.CreateTime,
+		CreateBy:   gray.CreateBy,
+		ModifyTime: gray.ModifyTime,
+		ModifyBy:   gray.ModifyBy,
+		Valid:      gray.Valid,
+	}
+}
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or serverAuthibilityied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// PublishConfigFileGray 灰度发布配置文件
+func (s *serverAuthability) PublishConfigFileGray(ctx context.Context,
+	configFileRelease *api.ConfigFileRelease, rule *model.GrayRule) *api.ConfigResponse {
+
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*api.ConfigFileRelease{configFileRelease}, model.Create, "PublishConfigFileGray")
+
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.PublishConfigFileGray(ctx, configFileRelease, rule)
+}
+
+// GetConfigFileGrayRelease 获取配置文件当前的灰度发布
+func (s *serverAuthability) GetConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) (*model.ConfigFileGrayRelease, error) {
+
+	gray, err := s.targetServer.GetConfigFileGrayRelease(ctx, namespace, group, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return s.newDecryptChecker(ctx).decryptGrayRelease(gray), nil
+}
+
+// PromoteConfigFileGray 灰度发布转为全量发布
+func (s *serverAuthability) PromoteConfigFileGray(ctx context.Context,
+	namespace, group, fileName string) *api.ConfigResponse {
+
+	authCtx, rsp := s.checkConfigFileReleasePermission(ctx, namespace, group, fileName, "PromoteConfigFileGray")
+	if rsp != nil {
+		return rsp
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.PromoteConfigFileGray(ctx, namespace, group, fileName)
+}
+
+// CancelConfigFileGray 取消灰度发布
+func (s *serverAuthability) CancelConfigFileGray(ctx context.Context,
+	namespace, group, fileName string) *api.ConfigResponse {
+
+	authCtx, rsp := s.checkConfigFileReleasePermission(ctx, namespace, group, fileName, "CancelConfigFileGray")
+	if rsp != nil {
+		return rsp
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.CancelConfigFileGray(ctx, namespace, group, fileName)
+}
+
+// UpdateConfigFileGrayPercentage 提高按比例灰度的比例
+func (s *serverAuthability) UpdateConfigFileGrayPercentage(ctx context.Context,
+	namespace, group, fileName string, percentage int) *api.ConfigResponse {
+
+	authCtx, rsp := s.checkConfigFileReleasePermission(ctx, namespace, group, fileName,
+		"UpdateConfigFileGrayPercentage")
+	if rsp != nil {
+		return rsp
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.UpdateConfigFileGrayPercentage(ctx, namespace, group, fileName, percentage)
+}
+
+func (s *serverAuthability) checkConfigFileReleasePermission(ctx context.Context, namespace, group, fileName,
+	method string) (*model.AcquireContext, *api.ConfigResponse) {
+	req := []*api.ConfigFileRelease{
+		{
+			Namespace: utils.NewStringValue(namespace),
+			Group:     utils.NewStringValue(group),
+			FileName:  utils.NewStringValue(fileName),
+		},
+	}
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx, req, model.Modify, method)
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return nil, api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	return authCtx, nil
+}
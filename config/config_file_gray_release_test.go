@@ -0,0 +1,352 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func withClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, utils.StringContext("client-ip"), clientIP)
+}
+
+func getConfigFileWithClientIP(testSuit *ConfigCenterTest, clientIP string) *api.ConfigClientResponse {
+	fileInfo := &api.ClientConfigFileInfo{
+		Namespace: &wrapperspb.StringValue{Value: testNamespace},
+		Group:     &wrapperspb.StringValue{Value: testGroup},
+		FileName:  &wrapperspb.StringValue{Value: testFile},
+		Version:   &wrapperspb.UInt64Value{Value: 0},
+	}
+	return testSuit.testService.GetConfigFileForClient(withClientIP(testSuit.defaultCtx, clientIP), fileInfo)
+}
+
+// TestGrayReleaseMatch 测试灰度规则匹配
+func TestGrayReleaseMatch(t *testing.T) {
+	gray, err := newGrayRelease(&model.ConfigFileGrayRelease{
+		Rule: `{"clientIps":["127.0.0.1","10.0.0.0/24"],"labels":{"region":"gz","zone":"gz-1"}}`,
+	})
+	assert.Nil(t, err)
+
+	labels := map[string]map[string]string{
+		"192.168.0.1": {ClientLabelRegion: "gz", ClientLabelZone: "gz-1"},
+		"192.168.0.2": {ClientLabelRegion: "gz", ClientLabelZone: "gz-2"},
+	}
+	labelsOf := func(client clientIdentity) map[string]string {
+		return labels[client.IP]
+	}
+
+	assert.True(t, gray.match(clientIdentity{IP: "127.0.0.1"}, labelsOf))
+	assert.True(t, gray.match(clientIdentity{IP: "10.0.0.12"}, labelsOf))
+	assert.False(t, gray.match(clientIdentity{IP: "10.0.1.12"}, labelsOf))
+	assert.True(t, gray.match(clientIdentity{IP: "192.168.0.1"}, labelsOf))
+	assert.False(t, gray.match(clientIdentity{IP: "192.168.0.2"}, labelsOf))
+	assert.False(t, gray.match(clientIdentity{}, labelsOf))
+
+	assert.NotNil(t, checkGrayRule(&model.GrayRule{}))
+	assert.NotNil(t, checkGrayRule(&model.GrayRule{ClientIPs: []string{"not-an-ip"}}))
+	assert.NotNil(t, checkGrayRule(&model.GrayRule{ClientIPs: []string{"10.0.0.0/33"}}))
+	assert.Nil(t, checkGrayRule(&model.GrayRule{Labels: map[string]string{ClientLabelRegion: "gz"}}))
+}
+
+// TestPublishConfigFileGray 测试灰度发布，只有命中灰度规则的客户端获取到灰度内容，全量发布后所有客户端获取到最新内容
+func TestPublishConfigFileGray(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	rule := &model.GrayRule{ClientIPs: []string{"127.0.0.1"}}
+
+	t.Run("未全量发布不能灰度发布", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+	})
+
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	oldContent := configFile.Content.GetValue()
+	grayContent := "k1=gray"
+	configFile.Content = utils.NewStringValue(grayContent)
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	t.Run("灰度规则不合法", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile),
+			&model.GrayRule{})
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+	})
+
+	t.Run("灰度发布", func(t *testing.T) {
+		received := make(chan uint64, 1)
+		watchConfigFiles := assembleDefaultClientConfigFile(1)
+		matchedClient := "TestPublishConfigFileGray-matched"
+		otherClient := "TestPublishConfigFileGray-other"
+		defer func() {
+			testSuit.testServer.WatchCenter().RemoveWatcher(matchedClient, watchConfigFiles)
+			testSuit.testServer.WatchCenter().RemoveWatcher(otherClient, watchConfigFiles)
+		}()
+		testSuit.testServer.WatchCenter().AddWatcher(matchedClient, clientIdentity{IP: "127.0.0.1"}, watchConfigFiles,
+			func(clientId string, rsp *api.ConfigClientResponse) bool {
+				received <- rsp.ConfigFile.Version.GetValue()
+				return true
+			})
+		testSuit.testServer.WatchCenter().AddWatcher(otherClient, clientIdentity{IP: "127.0.0.2"}, watchConfigFiles,
+			func(clientId string, rsp *api.ConfigClientResponse) bool {
+				t.Errorf("client %s should not receive gray release", clientId)
+				return true
+			})
+
+		rsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(2), rsp.ConfigFileRelease.Version.GetValue())
+
+		assert.Equal(t, uint64(2), <-received)
+
+		gray, err := testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.NotNil(t, gray)
+		assert.Equal(t, grayContent, gray.Content)
+
+		matched := getConfigFileWithClientIP(testSuit, "127.0.0.1")
+		assert.Equal(t, api.ExecuteSuccess, matched.Code.GetValue())
+		assert.Equal(t, uint64(2), matched.ConfigFile.Version.GetValue())
+		assert.Equal(t, grayContent, matched.ConfigFile.Content.GetValue())
+
+		other := getConfigFileWithClientIP(testSuit, "127.0.0.2")
+		assert.Equal(t, api.ExecuteSuccess, other.Code.GetValue())
+		assert.Equal(t, uint64(1), other.ConfigFile.Version.GetValue())
+		assert.Equal(t, oldContent, other.ConfigFile.Content.GetValue())
+
+		check := testSuit.testServer.doCheckClientConfigFile(withClientIP(testSuit.defaultCtx, "127.0.0.2"),
+			assembleDefaultClientConfigFile(1), compareByVersion)
+		assert.Equal(t, api.DataNoChange, check.Code.GetValue())
+	})
+
+	t.Run("取消灰度发布", func(t *testing.T) {
+		rsp := testSuit.testService.CancelConfigFileGray(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(3), rsp.ConfigFileRelease.Version.GetValue())
+		assert.Equal(t, oldContent, rsp.ConfigFileRelease.Content.GetValue())
+
+		gray, err := testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Nil(t, err)
+		assert.Nil(t, gray)
+
+		rsp = testSuit.testService.CancelConfigFileGray(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+
+		// 等待扫描器刷新缓存
+		time.Sleep(3 * time.Second)
+
+		matched := getConfigFileWithClientIP(testSuit, "127.0.0.1")
+		assert.Equal(t, uint64(3), matched.ConfigFile.Version.GetValue())
+		assert.Equal(t, oldContent, matched.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("灰度发布转全量发布", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(4), rsp.ConfigFileRelease.Version.GetValue())
+
+		rsp = testSuit.testService.PromoteConfigFileGray(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(5), rsp.ConfigFileRelease.Version.GetValue())
+
+		time.Sleep(3 * time.Second)
+
+		other := getConfigFileWithClientIP(testSuit, "127.0.0.2")
+		assert.Equal(t, uint64(5), other.ConfigFile.Version.GetValue())
+		assert.Equal(t, grayContent, other.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("灰度发布与全量发布分别记录发布历史", func(t *testing.T) {
+		rsp := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 0, 10, 0)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		types := make([]string, 0, len(rsp.ConfigFileReleaseHistories))
+		for _, history := range rsp.ConfigFileReleaseHistories {
+			types = append(types, history.Type.GetValue())
+		}
+		assert.Contains(t, types, utils.ReleaseTypeGray)
+		assert.Contains(t, types, utils.ReleaseTypeCancelGray)
+		assert.Contains(t, types, utils.ReleaseTypeNormal)
+	})
+}
+
+// TestGrayReleasePercentage 测试按比例灰度，客户端分桶稳定，提高比例后已经命中的客户端仍然命中
+func TestGrayReleasePercentage(t *testing.T) {
+	newPercentageGray := func(percentage int) *grayRelease {
+		gray, err := newGrayRelease(&model.ConfigFileGrayRelease{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+			Rule:      fmt.Sprintf(`{"percentage":%d}`, percentage),
+		})
+		assert.Nil(t, err)
+		return gray
+	}
+	labelsOf := func(client clientIdentity) map[string]string {
+		return nil
+	}
+
+	small := newPercentageGray(20)
+	large := newPercentageGray(60)
+
+	smallMatched := 0
+	largeMatched := 0
+	for i := 0; i < 1000; i++ {
+		client := clientIdentity{IP: fmt.Sprintf("10.0.%d.%d", i/250, i%250)}
+		assert.Equal(t, small.match(client, labelsOf), small.match(client, labelsOf))
+		if small.match(client, labelsOf) {
+			smallMatched++
+			assert.True(t, large.match(client, labelsOf))
+		}
+		if large.match(client, labelsOf) {
+			largeMatched++
+		}
+	}
+	assert.True(t, smallMatched > 100 && smallMatched < 300, "matched %d", smallMatched)
+	assert.True(t, largeMatched > 500 && largeMatched < 700, "matched %d", largeMatched)
+
+	assert.True(t, newPercentageGray(100).match(clientIdentity{IP: "10.0.0.1"}, labelsOf))
+
+	// 有客户端 ID 时按照客户端 ID 分桶，客户端的 IP 变化后仍然落在同一个分桶
+	for i := 0; i < 100; i++ {
+		clientID := fmt.Sprintf("client-%d", i)
+		assert.Equal(t, small.match(clientIdentity{ID: clientID, IP: "10.0.0.1"}, labelsOf),
+			small.match(clientIdentity{ID: clientID, IP: "10.0.0.2"}, labelsOf))
+	}
+	assert.Nil(t, checkGrayRule(&model.GrayRule{Percentage: 10}))
+	assert.NotNil(t, checkGrayRule(&model.GrayRule{Percentage: 101}))
+	assert.NotNil(t, checkGrayRule(&model.GrayRule{Percentage: -1}))
+}
+
+// TestConfigFileGrayPercentageRollout 测试逐步提高灰度比例，以及客户端上报失败次数达到阈值后自动取消灰度发布
+func TestConfigFileGrayPercentageRollout(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	configFile.Content = utils.NewStringValue("k1=gray")
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	rule := &model.GrayRule{Percentage: 10, MaxErrors: 2}
+	rsp = testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, assembleConfigFileRelease(configFile), rule)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, uint64(2), rsp.ConfigFileRelease.Version.GetValue())
+
+	t.Run("灰度比例只能提高", func(t *testing.T) {
+		rsp := testSuit.testService.UpdateConfigFileGrayPercentage(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 5)
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.UpdateConfigFileGrayPercentage(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 101)
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.UpdateConfigFileGrayPercentage(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 50)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(3), rsp.ConfigFileRelease.Version.GetValue())
+
+		gray, err := testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile)
+		assert.Nil(t, err)
+		assert.Contains(t, gray.Rule, `"percentage":50`)
+	})
+
+	t.Run("失败次数达到阈值自动取消灰度发布", func(t *testing.T) {
+		// 等待扫描器加载灰度发布
+		time.Sleep(3 * time.Second)
+
+		gray, err := testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile)
+		assert.Nil(t, err)
+		assert.NotNil(t, gray)
+
+		report := func(clientIP, md5 string) {
+			fileInfo := assembleDefaultClientConfigFile(gray.Version)[0]
+			fileInfo.Md5 = utils.NewStringValue(md5)
+			rsp := testSuit.testService.ReportConfigFileError(withClientIP(testSuit.defaultCtx, clientIP), fileInfo)
+			assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		}
+
+		// 非灰度内容的失败以及同一个客户端重复上报不计数
+		report("127.0.0.1", "not-gray-md5")
+		report("127.0.0.1", gray.Md5)
+		report("127.0.0.1", gray.Md5)
+
+		current, err := testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile)
+		assert.Nil(t, err)
+		assert.NotNil(t, current)
+		assert.Equal(t, uint32(1), current.ErrorCount)
+
+		report("127.0.0.2", gray.Md5)
+
+		current, err = testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile)
+		assert.Nil(t, err)
+		assert.Nil(t, current)
+
+		rsp := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, 0, 10, 0)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		types := make([]string, 0, len(rsp.ConfigFileReleaseHistories))
+		for _, history := range rsp.ConfigFileReleaseHistories {
+			types = append(types, history.Type.GetValue())
+		}
+		assert.Contains(t, types, utils.ReleaseTypeCancelGray)
+	})
+}
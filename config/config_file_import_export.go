@@ -0,0 +1,373 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+const (
+	// configFileMetaFileName 压缩包中的元数据清单，记录配置文件的格式、备注以及标签
+	configFileMetaFileName = "metadata.yaml"
+	// maxImportFileSize 导入时单个配置文件的最大字节数
+	maxImportFileSize = 1024 * 1024
+)
+
+// configFileManifest 导出压缩包的元数据清单，key 为配置文件在压缩包中的路径 group/name
+type configFileManifest struct {
+	Files map[string]*configFileMeta `yaml:"files"`
+}
+
+type configFileMeta struct {
+	Format  string              `yaml:"format"`
+	Comment string              `yaml:"comment,omitempty"`
+	Tags    map[string][]string `yaml:"tags,omitempty"`
+}
+
+// ExportConfigFile 导出配置文件为 zip 压缩包，每个配置文件的路径为 group/name。
+// 加密的配置文件导出明文，导入到其他环境时按照标签重新加密
+func (s *Server) ExportConfigFile(ctx context.Context, namespace string,
+	groups []string) ([]byte, *api.ConfigResponse) {
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return nil, api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	for _, group := range groups {
+		if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+			return nil, api.NewConfigFileResponse(api.InvalidConfigFileGroupName, nil)
+		}
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+	if len(groups) == 0 {
+		var err error
+		if groups, err = s.queryConfigFileGroupNames(namespace); err != nil {
+			log.ConfigScope().Error("[Config][Service] query config file groups when export error.",
+				zap.String("request-id", requestID),
+				zap.String("namespace", namespace),
+				zap.Error(err))
+			return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	manifest := &configFileManifest{Files: map[string]*configFileMeta{}}
+	for _, group := range groups {
+		files, err := s.queryConfigFilesByGroup(namespace, group)
+		if err != nil {
+			log.ConfigScope().Error("[Config][Service] query config files when export error.",
+				zap.String("request-id", requestID),
+				zap.String("namespace", namespace),
+				zap.String("group", group),
+				zap.Error(err))
+			return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+		}
+		for _, file := range files {
+			meta, content, err := s.exportConfigFile(file)
+			if err == nil {
+				err = writeZipFile(writer, group+"/"+file.Name, file, content)
+			}
+			if err != nil {
+				log.ConfigScope().Error("[Config][Service] export config file error.",
+					zap.String("request-id", requestID),
+					zap.String("namespace", namespace),
+					zap.String("group", group),
+					zap.String("name", file.Name),
+					zap.Error(err))
+				return nil, api.NewConfigFileResponse(api.ExecuteException, nil)
+			}
+			manifest.Files[group+"/"+file.Name] = meta
+		}
+	}
+
+	if len(manifest.Files) == 0 {
+		return nil, api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	data, err := yaml.Marshal(manifest)
+	if err == nil {
+		err = writeZipFile(writer, configFileMetaFileName, nil, string(data))
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] export config file manifest error.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.ExecuteException, nil)
+	}
+	return buf.Bytes(), api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// ImportConfigFile 从 zip 压缩包导入配置文件，返回每个配置文件的导入结果
+func (s *Server) ImportConfigFile(ctx context.Context, namespace string, data []byte,
+	conflictHandling string, publish bool) *api.ConfigBatchWriteResponse {
+
+	files, err := parseConfigFileArchive(namespace, data)
+	if err != nil {
+		return api.NewConfigBatchWriteResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+	return s.importConfigFiles(ctx, files, conflictHandling, publish)
+}
+
+func (s *Server) importConfigFiles(ctx context.Context, files []*api.ConfigFile, conflictHandling string,
+	publish bool) *api.ConfigBatchWriteResponse {
+
+	if conflictHandling == "" {
+		conflictHandling = utils.ConfigFileImportConflictSkip
+	}
+	if conflictHandling != utils.ConfigFileImportConflictSkip &&
+		conflictHandling != utils.ConfigFileImportConflictOverwrite {
+		return api.NewConfigBatchWriteResponseWithMessage(api.InvalidParameter,
+			"conflict handling must be skip or overwrite")
+	}
+
+	ret := api.NewConfigBatchWriteResponse(api.ExecuteSuccess)
+	for _, file := range files {
+		namespace := file.Namespace.GetValue()
+		group := file.Group.GetValue()
+		name := file.Name.GetValue()
+		// 导入结果中不返回配置文件内容
+		result := &api.ConfigFile{Namespace: file.Namespace, Group: file.Group, Name: file.Name}
+
+		managedFile, err := s.storage.GetConfigFile(nil, namespace, group, name)
+		if err != nil {
+			log.ConfigScope().Error("[Config][Service] get config file when import error.",
+				zap.String("request-id", utils.ParseRequestID(ctx)),
+				zap.String("namespace", namespace),
+				zap.String("group", group),
+				zap.String("name", name),
+				zap.Error(err))
+			ret.Collect(api.NewConfigFileResponse(api.StoreLayerException, result))
+			continue
+		}
+
+		var rsp *api.ConfigResponse
+		switch {
+		case managedFile == nil:
+			rsp = s.CreateConfigFile(ctx, file)
+		case conflictHandling == utils.ConfigFileImportConflictOverwrite:
+			rsp = s.UpdateConfigFile(ctx, file)
+		default:
+			// 跳过已存在的配置文件属于预期的行为，不影响整体的返回码
+			ret.Responses = append(ret.Responses, api.NewConfigFileResponse(api.ExistedResource, result))
+			ret.Total.Value++
+			continue
+		}
+
+		if rsp.GetCode().GetValue() == api.ExecuteSuccess && publish {
+			rsp = s.PublishConfigFile(ctx, &api.ConfigFileRelease{
+				Namespace: file.Namespace,
+				Group:     file.Group,
+				FileName:  file.Name,
+				CreateBy:  utils.NewStringValue(utils.ParseUserName(ctx)),
+			})
+		}
+		ret.Collect(&api.ConfigResponse{Code: rsp.GetCode(), Info: rsp.GetInfo(), ConfigFile: result})
+	}
+	return ret
+}
+
+// exportConfigFile 返回配置文件的元数据以及明文内容，由服务端维护的加密标签不导出
+func (s *Server) exportConfigFile(file *model.ConfigFile) (*configFileMeta, string, error) {
+	tags, err := s.storage.QueryTagByConfigFile(file.Namespace, file.Group, file.Name)
+	if err != nil {
+		return nil, "", err
+	}
+	meta := &configFileMeta{
+		Format:  file.Format,
+		Comment: file.Comment,
+	}
+	for _, tag := range tags {
+		if tag.Key == utils.ConfigFileTagKeyDataKey || tag.Key == utils.ConfigFileTagKeyEncryptAlgo {
+			continue
+		}
+		if meta.Tags == nil {
+			meta.Tags = map[string][]string{}
+		}
+		meta.Tags[tag.Key] = append(meta.Tags[tag.Key], tag.Value)
+	}
+
+	dataKey, _ := dataKeyOfStoreTags(tags)
+	content, err := s.decryptContent(file.Content, dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return meta, content, nil
+}
+
+// queryConfigFileGroupNames 查询命名空间下全部配置文件组的名称
+func (s *Server) queryConfigFileGroupNames(namespace string) ([]string, error) {
+	var names []string
+	for offset := uint32(0); ; offset += MaxPageSize {
+		total, groups, err := s.storage.QueryConfigFileGroups(namespace, "", offset, MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			names = append(names, group.Name)
+		}
+		if len(groups) == 0 || offset+MaxPageSize >= total {
+			return names, nil
+		}
+	}
+}
+
+// queryConfigFilesByGroup 查询配置文件组下的全部配置文件
+func (s *Server) queryConfigFilesByGroup(namespace, group string) ([]*model.ConfigFile, error) {
+	var ret []*model.ConfigFile
+	for offset := uint32(0); ; offset += MaxPageSize {
+		total, files, err := s.storage.QueryConfigFilesByGroup(namespace, group, offset, MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, files...)
+		if len(files) == 0 || offset+MaxPageSize >= total {
+			return ret, nil
+		}
+	}
+}
+
+func writeZipFile(writer *zip.Writer, name string, file *model.ConfigFile, content string) error {
+	header := &zip.FileHeader{
+		Name:   name,
+		Method: zip.Deflate,
+	}
+	if file != nil {
+		header.Modified = file.ModifyTime
+	}
+	w, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+// parseConfigFileArchive 解析导入的 zip 压缩包，没有元数据的配置文件按照文件后缀推断格式
+func parseConfigFileArchive(namespace string, data []byte) ([]*api.ConfigFile, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %s", err.Error())
+	}
+
+	manifest := &configFileManifest{}
+	for _, f := range reader.File {
+		if f.Name != configFileMetaFileName {
+			continue
+		}
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal([]byte(content), manifest); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", configFileMetaFileName, err.Error())
+		}
+	}
+
+	files := make([]*api.ConfigFile, 0, len(reader.File))
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() || f.Name == configFileMetaFileName {
+			continue
+		}
+		idx := strings.Index(f.Name, "/")
+		if idx <= 0 || idx == len(f.Name)-1 {
+			return nil, fmt.Errorf("invalid file path %s, must be group/name", f.Name)
+		}
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		file := &api.ConfigFile{
+			Namespace: utils.NewStringValue(namespace),
+			Group:     utils.NewStringValue(f.Name[:idx]),
+			Name:      utils.NewStringValue(f.Name[idx+1:]),
+			Content:   utils.NewStringValue(content),
+		}
+		meta := manifest.Files[f.Name]
+		if meta == nil {
+			meta = &configFileMeta{Format: strings.TrimPrefix(path.Ext(f.Name), ".")}
+		}
+		if !utils.IsValidFileFormat(meta.Format) {
+			meta.Format = utils.FileFormatText
+		}
+		file.Format = utils.NewStringValue(meta.Format)
+		file.Comment = utils.NewStringValue(meta.Comment)
+
+		keys := make([]string, 0, len(meta.Tags))
+		for key := range meta.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range meta.Tags[key] {
+				file.Tags = append(file.Tags, &api.ConfigFileTag{
+					Key:   utils.NewStringValue(key),
+					Value: utils.NewStringValue(value),
+				})
+			}
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("no config file in archive")
+	}
+	return files, nil
+}
+
+func readZipFile(f *zip.File) (string, error) {
+	if f.UncompressedSize64 > maxImportFileSize {
+		return "", fmt.Errorf("file %s is too large", f.Name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxImportFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxImportFileSize {
+		return "", fmt.Errorf("file %s is too large", f.Name)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// ExportConfigFile 导出配置文件，导出的内容包含加密配置文件的明文，需要配置文件组的写权限
+func (s *serverAuthability) ExportConfigFile(ctx context.Context, namespace string,
+	groups []string) ([]byte, *api.ConfigResponse) {
+
+	if len(groups) == 0 {
+		names, err := s.targetServer.queryConfigFileGroupNames(namespace)
+		if err != nil {
+			return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+		}
+		if len(names) == 0 {
+			return nil, api.NewConfigFileResponse(api.NotFoundResource, nil)
+		}
+		groups = names
+	}
+
+	req := make([]*api.ConfigFileGroup, 0, len(groups))
+	for _, group := range groups {
+		req = append(req, &api.ConfigFileGroup{
+			Namespace: utils.NewStringValue(namespace),
+			Name:      utils.NewStringValue(group),
+		})
+	}
+	authCtx := s.collectConfigGroupAuthContext(ctx, req, model.Modify, "ExportConfigFile")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return nil, api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.ExportConfigFile(ctx, namespace, groups)
+}
+
+// ImportConfigFile 导入配置文件，需要压缩包中全部配置文件组的写权限
+func (s *serverAuthability) ImportConfigFile(ctx context.Context, namespace string, data []byte,
+	conflictHandling string, publish bool) *api.ConfigBatchWriteResponse {
+
+	files, err := parseConfigFileArchive(namespace, data)
+	if err != nil {
+		return api.NewConfigBatchWriteResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+
+	authCtx := s.collectConfigFileAuthContext(ctx, files, model.Create, "ImportConfigFile")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigBatchWriteResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.importConfigFiles(ctx, files, conflictHandling, publish)
+}
@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// TestExportImportConfigFile 测试配置文件导出以及导入时的冲突处理
+func TestExportImportConfigFile(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	configFile.Comment = utils.NewStringValue("exported")
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	data, rsp := testSuit.testService.ExportConfigFile(testSuit.defaultCtx, testNamespace, nil)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+	names := make([]string, 0, len(reader.File))
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{testGroup + "/" + testFile, configFileMetaFileName}, names)
+
+	t.Run("删除后导入并发布", func(t *testing.T) {
+		rsp := testSuit.testService.DeleteConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile, operator)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		batchRsp := testSuit.testService.ImportConfigFile(testSuit.defaultCtx, testNamespace, data,
+			utils.ConfigFileImportConflictSkip, true)
+		assert.Equal(t, api.ExecuteSuccess, batchRsp.Code.GetValue())
+		assert.Equal(t, uint32(1), batchRsp.Total.GetValue())
+
+		rsp = testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, configFile.Content.GetValue(), rsp.ConfigFile.Content.GetValue())
+		assert.Equal(t, "exported", rsp.ConfigFile.Comment.GetValue())
+		assert.Equal(t, len(configFile.Tags), len(rsp.ConfigFile.Tags))
+		assert.Equal(t, utils.ReleaseStatusSuccess, rsp.ConfigFile.Status.GetValue())
+	})
+
+	t.Run("已存在的配置文件跳过或者覆盖", func(t *testing.T) {
+		configFile.Content = utils.NewStringValue("k1=v3")
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		batchRsp := testSuit.testService.ImportConfigFile(testSuit.defaultCtx, testNamespace, data,
+			utils.ConfigFileImportConflictSkip, false)
+		assert.Equal(t, api.ExecuteSuccess, batchRsp.Code.GetValue())
+		assert.Equal(t, api.ExistedResource, batchRsp.Responses[0].Code.GetValue())
+		rsp = testSuit.testService.GetConfigFileBaseInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, "k1=v3", rsp.ConfigFile.Content.GetValue())
+
+		batchRsp = testSuit.testService.ImportConfigFile(testSuit.defaultCtx, testNamespace, data,
+			utils.ConfigFileImportConflictOverwrite, false)
+		assert.Equal(t, api.ExecuteSuccess, batchRsp.Code.GetValue())
+		assert.Equal(t, api.ExecuteSuccess, batchRsp.Responses[0].Code.GetValue())
+		rsp = testSuit.testService.GetConfigFileBaseInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, "k1=v1,k2=v2", rsp.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("错误的压缩包", func(t *testing.T) {
+		batchRsp := testSuit.testService.ImportConfigFile(testSuit.defaultCtx, testNamespace, []byte("not zip"),
+			utils.ConfigFileImportConflictSkip, false)
+		assert.Equal(t, api.InvalidParameter, batchRsp.Code.GetValue())
+
+		batchRsp = testSuit.testService.ImportConfigFile(testSuit.defaultCtx, testNamespace, data, "merge", false)
+		assert.Equal(t, api.InvalidParameter, batchRsp.Code.GetValue())
+	})
+}
@@ -32,6 +32,12 @@ import (
 
 // PublishConfigFile 发布配置文件
 func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.ConfigFileRelease) *api.ConfigResponse {
+	return s.doPublishConfigFile(ctx, configFileRelease, utils.ReleaseTypeNormal)
+}
+
+// doPublishConfigFile 发布配置文件的当前内容，releaseType 区分正常发布与回滚发布，记录在发布历史中
+func (s *Server) doPublishConfigFile(ctx context.Context, configFileRelease *api.ConfigFileRelease,
+	releaseType string) *api.ConfigResponse {
 	namespace := configFileRelease.Namespace.GetValue()
 	group := configFileRelease.Group.GetValue()
 	fileName := configFileRelease.FileName.GetValue()
@@ -69,7 +75,7 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 			zap.String("fileName", fileName),
 			zap.Error(err))
 
-		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease), releaseType)
 
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
@@ -78,7 +84,24 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 		return api.NewConfigFileResponse(api.NotFoundResource, nil)
 	}
 
-	md5 := utils2.CalMd5(toPublishFile.Content)
+	// 开启变更审核后只能发布审核通过的变更，回滚发布的是已经发布过的历史版本，不需要审核和校验
+	var approvedChange *model.ConfigFileChange
+	content := toPublishFile.Content
+	// 回滚直接发布历史版本的发布内容，配置文件本身（例如引用的模板）保持不变
+	if releaseType == utils.ReleaseTypeRollback {
+		content = configFileRelease.GetContent().GetValue()
+	}
+	if releaseType == utils.ReleaseTypeNormal {
+		var rsp *api.ConfigResponse
+		if content, rsp = s.prepareConfigFileContentForPublish(ctx, toPublishFile); rsp != nil {
+			return rsp
+		}
+		if approvedChange, rsp = s.checkConfigFileChangeApproved(tx, toPublishFile); rsp != nil {
+			return rsp
+		}
+	}
+
+	md5 := utils2.CalMd5(content)
 
 	// 获取 configFileRelease 信息
 	managedFileRelease, err := s.storage.GetConfigFileReleaseWithAllFlag(tx, namespace, group, fileName)
@@ -90,7 +113,7 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 			zap.String("fileName", fileName),
 			zap.Error(err))
 
-		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease), releaseType)
 
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
@@ -111,7 +134,7 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 			Namespace: namespace,
 			Group:     group,
 			FileName:  fileName,
-			Content:   toPublishFile.Content,
+			Content:   content,
 			Comment:   configFileRelease.Comment.GetValue(),
 			Md5:       md5,
 			Version:   1,
@@ -129,26 +152,46 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 				zap.String("fileName", fileName),
 				zap.Error(err))
 
-			s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+			s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease), releaseType)
 
 			return api.NewConfigFileResponse(api.StoreLayerException, nil)
 		}
 
-		s.recordReleaseHistory(ctx, createdFileRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusSuccess)
+		s.recordReleaseHistory(ctx, createdFileRelease, releaseType, utils.ReleaseStatusSuccess)
+		s.markConfigFileChangePublished(ctx, approvedChange)
 
 		return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(createdFileRelease))
 	}
 
+	// 全量发布会结束正在进行的灰度发布，版本号需要大于灰度发布的版本号
+	grayVersion, err := s.removeGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] remove config file gray release error.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+
+		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease), releaseType)
+
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	version := managedFileRelease.Version
+	if grayVersion > version {
+		version = grayVersion
+	}
+
 	// 更新发布
 	fileRelease := &model.ConfigFileRelease{
 		Name:      releaseName,
 		Namespace: namespace,
 		Group:     group,
 		FileName:  fileName,
-		Content:   toPublishFile.Content,
+		Content:   content,
 		Comment:   configFileRelease.Comment.GetValue(),
 		Md5:       md5,
-		Version:   managedFileRelease.Version + 1,
+		Version:   version + 1,
 		ModifyBy:  configFileRelease.CreateBy.GetValue(),
 	}
 
@@ -161,12 +204,13 @@ func (s *Server) PublishConfigFile(ctx context.Context, configFileRelease *api.C
 			zap.String("fileName", fileName),
 			zap.Error(err))
 
-		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+		s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease), releaseType)
 
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
 
-	s.recordReleaseHistory(ctx, updatedFileRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusSuccess)
+	s.recordReleaseHistory(ctx, updatedFileRelease, releaseType, utils.ReleaseStatusSuccess)
+	s.markConfigFileChangePublished(ctx, approvedChange)
 
 	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, configFileRelease2Api(updatedFileRelease))
 }
@@ -249,7 +293,17 @@ func (s *Server) DeleteConfigFileRelease(ctx context.Context, namespace,
 		return api.NewConfigFileResponse(api.StoreLayerException, nil)
 	}
 
-	err := s.storage.DeleteConfigFileRelease(s.getTx(ctx), namespace, group, fileName, deleteBy)
+	// 删除发布时一并结束灰度发布，删除后的版本号需要大于灰度发布的版本号，命中灰度的客户端才能感知到删除
+	grayVersion, err := s.removeGrayRelease(s.getTx(ctx), namespace, group, fileName)
+	if err == nil && grayVersion > latestRelease.Version.GetValue() {
+		releaseModel := transferConfigFileReleaseAPIModel2StoreModel(latestRelease)
+		releaseModel.Version = grayVersion
+		releaseModel.ModifyBy = deleteBy
+		_, err = s.storage.UpdateConfigFileRelease(s.getTx(ctx), releaseModel)
+	}
+	if err == nil {
+		err = s.storage.DeleteConfigFileRelease(s.getTx(ctx), namespace, group, fileName, deleteBy)
+	}
 
 	if err != nil {
 		log.ConfigScope().Error("[Config][Service] delete config file release error.",
@@ -281,8 +335,106 @@ func (s *Server) DeleteConfigFileRelease(ctx context.Context, namespace,
 	return api.NewConfigFileReleaseResponse(api.ExecuteSuccess, nil)
 }
 
-func (s *Server) recordReleaseFail(ctx context.Context, configFileRelease *model.ConfigFileRelease) {
-	s.recordReleaseHistory(ctx, configFileRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusFail)
+// RollbackConfigFileRelease 回滚配置文件到指定的发布历史版本，历史版本的内容会作为新版本重新发布
+func (s *Server) RollbackConfigFileRelease(ctx context.Context, namespace, group, fileName string,
+	historyId uint64) *api.ConfigResponse {
+
+	if err := utils2.CheckFileName(utils.NewStringValue(fileName)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileName, nil)
+	}
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileGroupName, nil)
+	}
+
+	requestID := utils.ParseRequestID(ctx)
+
+	history, err := s.storage.GetConfigFileReleaseHistory(historyId)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file release history error.",
+			zap.String("request-id", requestID),
+			zap.Uint64("historyId", historyId),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if history == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	if history.Namespace != namespace || history.Group != group || history.FileName != fileName {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter,
+			"release history does not belong to the config file")
+	}
+	// 删除记录以及失败的发布记录没有可以回滚的内容
+	if history.Type == utils.ReleaseTypeDelete || history.Status != utils.ReleaseStatusSuccess {
+		return api.NewConfigFileResponseWithMessage(api.BadRequest,
+			"only successful release history can be rolled back")
+	}
+
+	configFile, err := s.storage.GetConfigFile(nil, namespace, group, fileName)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file error.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if configFile == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	// 加密的配置文件按照当前的加密设置重新加密历史版本的内容
+	content, err := s.rollbackContent(history)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] prepare rollback content error.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.ExecuteException, nil)
+	}
+
+	tx, newCtx, err := s.StartTxAndSetToContext(ctx)
+	if err != nil {
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 历史版本的发布内容作为新版本重新发布，客户端通过发布事件感知到配置变更。配置文件保持不变，
+	// 历史版本的内容可能是模板渲染后的结果，覆盖配置文件会丢失模板
+	rsp := s.doPublishConfigFile(newCtx, &api.ConfigFileRelease{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+		Content:   utils.NewStringValue(content),
+		Comment:   utils.NewStringValue(history.Comment),
+	}, utils.ReleaseTypeRollback)
+	if rsp.Code.GetValue() != api.ExecuteSuccess {
+		return rsp
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.ConfigScope().Error("[Config][Service] commit rollback config file release tx error.",
+			zap.String("request-id", requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+
+	return rsp
+}
+
+func (s *Server) recordReleaseFail(ctx context.Context, configFileRelease *model.ConfigFileRelease,
+	releaseType string) {
+	s.recordReleaseHistory(ctx, configFileRelease, releaseType, utils.ReleaseStatusFail)
 }
 
 func transferConfigFileReleaseAPIModel2StoreModel(release *api.ConfigFileRelease) *model.ConfigFileRelease {
@@ -39,14 +39,18 @@ func (s *serverAuthability) PublishConfigFile(ctx context.Context,
 	ctx = authCtx.GetRequestContext()
 	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
 
-	return s.targetServer.PublishConfigFile(ctx, configFileRelease)
+	rsp := s.targetServer.PublishConfigFile(ctx, configFileRelease)
+	s.newDecryptChecker(ctx).decryptConfigFileReleases(rsp.GetConfigFileRelease())
+	return rsp
 }
 
 // GetConfigFileRelease 获取配置文件发布内容
 func (s *serverAuthability) GetConfigFileRelease(ctx context.Context,
 	namespace, group, fileName string) *api.ConfigResponse {
 
-	return s.targetServer.GetConfigFileRelease(ctx, namespace, group, fileName)
+	rsp := s.targetServer.GetConfigFileRelease(ctx, namespace, group, fileName)
+	s.newDecryptChecker(ctx).decryptConfigFileReleases(rsp.GetConfigFileRelease())
+	return rsp
 }
 
 // DeleteConfigFileRelease 删除配置文件发布，删除配置文件的时候，同步删除配置文件发布数据
@@ -70,3 +74,27 @@ func (s *serverAuthability) DeleteConfigFileRelease(ctx context.Context, namespa
 
 	return s.targetServer.DeleteConfigFileRelease(ctx, namespace, group, fileName, deleteBy)
 }
+
+// RollbackConfigFileRelease 回滚配置文件到指定的发布历史版本
+func (s *serverAuthability) RollbackConfigFileRelease(ctx context.Context, namespace, group, fileName string,
+	historyId uint64) *api.ConfigResponse {
+
+	req := []*api.ConfigFileRelease{
+		{
+			Namespace: utils.NewStringValue(namespace),
+			Group:     utils.NewStringValue(group),
+			FileName:  utils.NewStringValue(fileName),
+		},
+	}
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx, req, model.Modify, "RollbackConfigFileRelease")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	rsp := s.targetServer.RollbackConfigFileRelease(ctx, namespace, group, fileName, historyId)
+	s.newDecryptChecker(ctx).decryptConfigFileReleases(rsp.GetConfigFileRelease())
+	return rsp
+}
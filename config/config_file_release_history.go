@@ -141,6 +141,11 @@ func (s *Server) GetConfigFileLatestReleaseHistory(ctx context.Context, namespac
 		transferReleaseHistoryStoreModel2APIModel(history))
 }
 
+// isFullReleaseType 全量发布的发布类型，包括正常发布与回滚发布
+func isFullReleaseType(releaseType string) bool {
+	return releaseType == utils.ReleaseTypeNormal || releaseType == utils.ReleaseTypeRollback
+}
+
 func transferReleaseHistoryStoreModel2APIModel(
 	releaseHistory *model.ConfigFileReleaseHistory) *api.ConfigFileReleaseHistory {
 
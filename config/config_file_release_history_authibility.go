@@ -27,12 +27,16 @@ import (
 func (s *serverAuthability) GetConfigFileReleaseHistory(ctx context.Context, namespace, group, fileName string, offset,
 	limit uint32, endId uint64) *api.ConfigBatchQueryResponse {
 
-	return s.targetServer.GetConfigFileReleaseHistory(ctx, namespace, group, fileName, offset, limit, endId)
+	rsp := s.targetServer.GetConfigFileReleaseHistory(ctx, namespace, group, fileName, offset, limit, endId)
+	s.newDecryptChecker(ctx).decryptReleaseHistories(rsp.GetConfigFileReleaseHistories()...)
+	return rsp
 }
 
 // GetConfigFileLatestReleaseHistory 获取配置文件最后一次发布记录
 func (s *serverAuthability) GetConfigFileLatestReleaseHistory(ctx context.Context, namespace, group,
 	fileName string) *api.ConfigResponse {
 
-	return s.targetServer.GetConfigFileLatestReleaseHistory(ctx, namespace, group, fileName)
+	rsp := s.targetServer.GetConfigFileLatestReleaseHistory(ctx, namespace, group, fileName)
+	s.newDecryptChecker(ctx).decryptReleaseHistories(rsp.GetConfigFileReleaseHistory())
+	return rsp
 }
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// TestRollbackConfigFileRelease 测试回滚到历史版本，历史版本的内容作为新版本发布并通知客户端
+func TestRollbackConfigFileRelease(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	firstContent := configFile.Content.GetValue()
+	configFile.Content = utils.NewStringValue("k1=v2")
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	histories := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx, testNamespace, testGroup,
+		testFile, 0, 10, 0)
+	assert.Equal(t, api.ExecuteSuccess, histories.Code.GetValue())
+	assert.Equal(t, 2, len(histories.ConfigFileReleaseHistories))
+	// 发布历史按照 ID 倒序排列，最后一条是第一次发布
+	firstHistory := histories.ConfigFileReleaseHistories[1]
+	assert.Equal(t, firstContent, firstHistory.Content.GetValue())
+
+	t.Run("发布历史不属于配置文件", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			"other.yaml", firstHistory.Id.GetValue())
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+	})
+
+	t.Run("发布历史不存在", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, firstHistory.Id.GetValue()+100)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+	})
+
+	t.Run("回滚到第一次发布", func(t *testing.T) {
+		received := make(chan uint64, 1)
+		watchConfigFiles := assembleDefaultClientConfigFile(2)
+		clientId := "TestRollbackConfigFileRelease"
+		defer testSuit.testServer.WatchCenter().RemoveWatcher(clientId, watchConfigFiles)
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, clientIdentity{}, watchConfigFiles,
+			func(clientId string, rsp *api.ConfigClientResponse) bool {
+				received <- rsp.ConfigFile.Version.GetValue()
+				return true
+			})
+
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, firstHistory.Id.GetValue())
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint64(3), rsp.ConfigFileRelease.Version.GetValue())
+		assert.Equal(t, firstContent, rsp.ConfigFileRelease.Content.GetValue())
+
+		assert.Equal(t, uint64(3), <-received)
+
+		fileInfo := &api.ClientConfigFileInfo{
+			Namespace: &wrapperspb.StringValue{Value: testNamespace},
+			Group:     &wrapperspb.StringValue{Value: testGroup},
+			FileName:  &wrapperspb.StringValue{Value: testFile},
+			Version:   &wrapperspb.UInt64Value{Value: 2},
+		}
+		clientRsp := testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx, fileInfo)
+		assert.Equal(t, api.ExecuteSuccess, clientRsp.Code.GetValue())
+		assert.Equal(t, firstContent, clientRsp.ConfigFile.Content.GetValue())
+
+		latest := testSuit.testService.GetConfigFileLatestReleaseHistory(testSuit.defaultCtx, testNamespace,
+			testGroup, testFile)
+		assert.Equal(t, utils.ReleaseTypeRollback, latest.ConfigFileReleaseHistory.Type.GetValue())
+
+		// 配置文件保持回滚前的内容，与发布内容不一致时为待发布状态
+		richInfo := testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, richInfo.Code.GetValue())
+		assert.Equal(t, "k1=v2", richInfo.ConfigFile.Content.GetValue())
+		assert.Equal(t, utils.ReleaseStatusToRelease, richInfo.ConfigFile.Status.GetValue())
+	})
+}
@@ -0,0 +1,193 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// RenderConfigFile 预览配置文件发布后的内容，未开启渲染的配置文件返回原始内容，加密的配置文件不支持预览
+func (s *Server) RenderConfigFile(ctx context.Context, namespace, group,
+	name string) (string, *api.ConfigResponse) {
+
+	file, err := s.storage.GetConfigFile(s.getTx(ctx), namespace, group, name)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("file", utils.GenFileId(namespace, group, name)),
+			zap.Error(err))
+		return "", api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if file == nil {
+		return "", api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	tags, err := s.storage.QueryTagByConfigFile(namespace, group, name)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file tags error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("file", utils.GenFileId(namespace, group, name)),
+			zap.Error(err))
+		return "", api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if dataKey, _ := dataKeyOfStoreTags(tags); dataKey != "" {
+		return "", api.NewConfigFileResponseWithMessage(api.BadRequest,
+			"encrypted config file can not be previewed")
+	}
+	if !isRenderByStoreTags(tags) {
+		return file.Content, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+	}
+
+	rendered, err := s.renderConfigFileContent(ctx, file, file.Content)
+	if err != nil {
+		return "", renderErrorResponse(ctx, file, err)
+	}
+	return rendered, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// prepareConfigFileContentForPublish 发布前使用配置文件当前的标签处理待发布的内容，返回写入发布记录的内容。
+// 开启渲染的配置文件使用命名空间下的模板变量渲染，加密的配置文件先解密，渲染后重新加密；
+// 渲染后的内容会重新校验语法以及关联的 JSON Schema
+func (s *Server) prepareConfigFileContentForPublish(ctx context.Context,
+	file *model.ConfigFile) (string, *api.ConfigResponse) {
+
+	tags, err := s.storage.QueryTagByConfigFile(file.Namespace, file.Group, file.Name)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file tags error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("namespace", file.Namespace),
+			zap.String("group", file.Group), zap.String("fileName", file.Name), zap.Error(err))
+		return "", api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	dataKey, _ := dataKeyOfStoreTags(tags)
+	content, err := s.decryptContent(file.Content, dataKey)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] decrypt config file error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("namespace", file.Namespace),
+			zap.String("group", file.Group), zap.String("fileName", file.Name), zap.Error(err))
+		return "", api.NewConfigFileResponseWithMessage(api.ExecuteException, err.Error())
+	}
+
+	published := file.Content
+	if isRenderByStoreTags(tags) {
+		rendered, err := s.renderConfigFileContent(ctx, file, content)
+		if err != nil {
+			return "", renderErrorResponse(ctx, file, err)
+		}
+		content = rendered
+		published = rendered
+		if dataKey != "" && isEncryptedByStoreTags(tags) {
+			if published, err = s.encryptContent(rendered, dataKey); err != nil {
+				log.ConfigScope().Error("[Config][Service] encrypt rendered config file error.",
+					utils.ZapRequestIDByCtx(ctx), zap.String("namespace", file.Namespace),
+					zap.String("group", file.Group), zap.String("fileName", file.Name), zap.Error(err))
+				return "", api.NewConfigFileResponseWithMessage(api.ExecuteException, err.Error())
+			}
+		}
+	}
+
+	if rsp := s.checkConfigFileContent(ctx, file.Format, content, schemaNameOfStoreTags(tags)); rsp != nil {
+		return "", rsp
+	}
+	return published, nil
+}
+
+// renderConfigFileContent 使用命名空间下的模板变量渲染配置文件，include、extends 只能引用同一个分组下未加密的配置文件
+func (s *Server) renderConfigFileContent(ctx context.Context, file *model.ConfigFile,
+	content string) (string, error) {
+
+	variables, err := s.storage.QueryConfigFileVariables(file.Namespace)
+	if err != nil {
+		return "", store.Error(err)
+	}
+	values := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		values[variable.Name] = variable.Value
+	}
+
+	tx := s.getTx(ctx)
+	loader := func(name string) (*utils2.TemplateFile, error) {
+		target, err := s.storage.GetConfigFile(tx, file.Namespace, file.Group, name)
+		if err != nil || target == nil {
+			return nil, store.Error(err)
+		}
+		tags, err := s.storage.QueryTagByConfigFile(file.Namespace, file.Group, name)
+		if err != nil {
+			return nil, store.Error(err)
+		}
+		if dataKey, _ := dataKeyOfStoreTags(tags); dataKey != "" {
+			return nil, fmt.Errorf("%s: encrypted config file can not be referenced", name)
+		}
+		return &utils2.TemplateFile{Name: target.Name, Format: target.Format, Content: target.Content}, nil
+	}
+
+	return utils2.RenderTemplate(&utils2.TemplateFile{
+		Name:    file.Name,
+		Format:  file.Format,
+		Content: content,
+	}, values, loader)
+}
+
+func renderErrorResponse(ctx context.Context, file *model.ConfigFile, err error) *api.ConfigResponse {
+	var statusErr *store.StatusError
+	if errors.As(err, &statusErr) {
+		log.ConfigScope().Error("[Config][Service] render config file error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("namespace", file.Namespace),
+			zap.String("group", file.Group), zap.String("fileName", file.Name), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileTemplate, err.Error())
+}
+
+// checkConfigFileContentForSave 保存配置文件前校验内容，开启渲染并且包含模板指令的配置文件在发布时渲染后再校验
+func (s *Server) checkConfigFileContentForSave(ctx context.Context, format, content string,
+	tags []*api.ConfigFileTag) *api.ConfigResponse {
+
+	if isRenderByAPITags(tags) && utils2.HasTemplateDirective(content) {
+		return nil
+	}
+	return s.checkConfigFileContent(ctx, format, content, schemaNameOfAPITags(tags))
+}
+
+func isRenderByStoreTags(tags []*model.ConfigFileTag) bool {
+	for _, tag := range tags {
+		if tag.Key == utils.ConfigFileTagKeyRender && tag.Value == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+func isRenderByAPITags(tags []*api.ConfigFileTag) bool {
+	for _, tag := range tags {
+		if tag.Key.GetValue() == utils.ConfigFileTagKeyRender && tag.Value.GetValue() == "true" {
+			return true
+		}
+	}
+	return false
+}
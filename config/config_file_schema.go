@@ -0,0 +1,175 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+// GetAllConfigFileSchemas 获取全部的 JSON Schema
+func (s *Server) GetAllConfigFileSchemas(ctx context.Context) ([]*model.ConfigFileSchema, *api.ConfigResponse) {
+	schemas, err := s.storage.QueryAllConfigFileSchemas()
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query all config file schemas error.",
+			utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return schemas, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// GetConfigFileSchema 获取单个 JSON Schema
+func (s *Server) GetConfigFileSchema(ctx context.Context,
+	name string) (*model.ConfigFileSchema, *api.ConfigResponse) {
+
+	if name == "" {
+		return nil, api.NewConfigFileResponseWithMessage(api.InvalidParameter, "schema name can not be blank")
+	}
+	schema, err := s.storage.GetConfigFileSchema(name)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file schema error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("name", name), zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if schema == nil {
+		return nil, api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	return schema, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// CreateConfigFileSchema 创建 JSON Schema，内容必须是合法的 JSON Schema
+func (s *Server) CreateConfigFileSchema(ctx context.Context, schema *model.ConfigFileSchema) *api.ConfigResponse {
+	if rsp := checkConfigFileSchemaParam(schema); rsp != nil {
+		return rsp
+	}
+
+	managedSchema, rsp := s.GetConfigFileSchema(ctx, schema.Name)
+	if managedSchema != nil {
+		return api.NewConfigFileResponseWithMessage(api.ExistedResource, "config file schema existed")
+	}
+	if rsp.GetCode().GetValue() != api.NotFoundResource {
+		return rsp
+	}
+
+	userName := utils.ParseUserName(ctx)
+	schema.CreateBy = userName
+	schema.ModifyBy = userName
+	if _, err := s.storage.CreateConfigFileSchema(schema); err != nil {
+		log.ConfigScope().Error("[Config][Service] create config file schema error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("name", schema.Name), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// UpdateConfigFileSchema 更新 JSON Schema，已经发布的配置文件不会重新校验
+func (s *Server) UpdateConfigFileSchema(ctx context.Context, schema *model.ConfigFileSchema) *api.ConfigResponse {
+	if rsp := checkConfigFileSchemaParam(schema); rsp != nil {
+		return rsp
+	}
+
+	managedSchema, rsp := s.GetConfigFileSchema(ctx, schema.Name)
+	if managedSchema == nil {
+		return rsp
+	}
+
+	schema.ModifyBy = utils.ParseUserName(ctx)
+	if _, err := s.storage.UpdateConfigFileSchema(schema); err != nil {
+		log.ConfigScope().Error("[Config][Service] update config file schema error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("name", schema.Name), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+func checkConfigFileSchemaParam(schema *model.ConfigFileSchema) *api.ConfigResponse {
+	if schema == nil {
+		return api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	if err := utils2.CheckFileName(utils.NewStringValue(schema.Name)); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid schema name")
+	}
+	if err := utils2.CheckContentLength(schema.Content); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileContentLength, nil)
+	}
+	if _, err := utils2.CompileJSONSchema(schema.Content); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileSchema, err.Error())
+	}
+	return nil
+}
+
+// checkConfigFileContent 校验配置文件内容的语法，配置文件关联了 JSON Schema 时同时校验内容是否满足 Schema
+func (s *Server) checkConfigFileContent(ctx context.Context, format, content,
+	schemaName string) *api.ConfigResponse {
+
+	if err := utils2.CheckContentFormat(format, content); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileContent, err.Error())
+	}
+	if schemaName == "" {
+		return nil
+	}
+	if !utils2.IsSchemaSupportedFormat(format) {
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileSchema,
+			fmt.Sprintf("json schema is not supported for format %s", format))
+	}
+
+	schema, rsp := s.GetConfigFileSchema(ctx, schemaName)
+	if schema == nil {
+		if rsp.GetCode().GetValue() == api.NotFoundResource {
+			return api.NewConfigFileResponseWithMessage(api.NotFoundResource,
+				"config file schema not found: "+schemaName)
+		}
+		return rsp
+	}
+
+	err := utils2.CheckContentSchema(format, content, schema.Content)
+	switch err.(type) {
+	case nil:
+		return nil
+	case *utils2.ContentError, utils2.ContentErrors:
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileContent, err.Error())
+	default:
+		return api.NewConfigFileResponseWithMessage(api.InvalidConfigFileSchema, err.Error())
+	}
+}
+
+func schemaNameOfStoreTags(tags []*model.ConfigFileTag) string {
+	for _, tag := range tags {
+		if tag.Key == utils.ConfigFileTagKeySchema {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+func schemaNameOfAPITags(tags []*api.ConfigFileTag) string {
+	for _, tag := range tags {
+		if tag.Key.GetValue() == utils.ConfigFileTagKeySchema {
+			return tag.Value.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// GetAllConfigFileSchemas 获取全部的 JSON Schema
+func (s *serverAuthability) GetAllConfigFileSchemas(
+	ctx context.Context) ([]*model.ConfigFileSchema, *api.ConfigResponse) {
+	return s.targetServer.GetAllConfigFileSchemas(ctx)
+}
+
+// GetConfigFileSchema 获取单个 JSON Schema
+func (s *serverAuthability) GetConfigFileSchema(ctx context.Context,
+	name string) (*model.ConfigFileSchema, *api.ConfigResponse) {
+	return s.targetServer.GetConfigFileSchema(ctx, name)
+}
+
+// CreateConfigFileSchema 创建 JSON Schema
+func (s *serverAuthability) CreateConfigFileSchema(ctx context.Context,
+	schema *model.ConfigFileSchema) *api.ConfigResponse {
+
+	authCtx := s.collectConfigFileSchemaAuthContext(ctx, model.Create, "CreateConfigFileSchema")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.CreateConfigFileSchema(ctx, schema)
+}
+
+// UpdateConfigFileSchema 更新 JSON Schema
+func (s *serverAuthability) UpdateConfigFileSchema(ctx context.Context,
+	schema *model.ConfigFileSchema) *api.ConfigResponse {
+
+	authCtx := s.collectConfigFileSchemaAuthContext(ctx, model.Modify, "UpdateConfigFileSchema")
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.UpdateConfigFileSchema(ctx, schema)
+}
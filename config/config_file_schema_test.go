@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+var (
+	testSchema = "testSchema"
+)
+
+func assembleSchemaConfigFile(content string) *api.ConfigFile {
+	configFile := assembleConfigFile()
+	configFile.Format = utils.NewStringValue(utils.FileFormatYaml)
+	configFile.Content = utils.NewStringValue(content)
+	configFile.Tags = append(configFile.Tags, &api.ConfigFileTag{
+		Key:   utils.NewStringValue(utils.ConfigFileTagKeySchema),
+		Value: utils.NewStringValue(testSchema),
+	})
+	return configFile
+}
+
+// TestConfigFileContentValidate 测试保存、发布配置文件前校验内容的语法以及关联的 JSON Schema
+func TestConfigFileContentValidate(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	t.Run("语法错误的配置文件不能保存", func(t *testing.T) {
+		configFile := assembleConfigFile()
+		configFile.Format = utils.NewStringValue(utils.FileFormatJson)
+		configFile.Content = utils.NewStringValue("{\n  \"port\": 8080,\n}")
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.InvalidConfigFileContent, rsp.Code.GetValue())
+		assert.True(t, strings.Contains(rsp.Info.GetValue(), "line 3, column 1"), rsp.Info.GetValue())
+
+		configFile.Format = utils.NewStringValue(utils.FileFormatText)
+		rsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		// 更新时未指定格式，使用已有的文本格式，不做校验
+		configFile.Format = nil
+		configFile.Content = utils.NewStringValue("a: b: c")
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		configFile.Format = utils.NewStringValue(utils.FileFormatYaml)
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.InvalidConfigFileContent, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.DeleteConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile, operator)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	})
+
+	t.Run("创建JSON Schema", func(t *testing.T) {
+		rsp := testSuit.testService.CreateConfigFileSchema(testSuit.defaultCtx, &model.ConfigFileSchema{
+			Name:    testSchema,
+			Content: `{"type": "object", "properties": {"port": {"type": "invalid"}}}`,
+		})
+		assert.Equal(t, api.InvalidConfigFileSchema, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.CreateConfigFileSchema(testSuit.defaultCtx, &model.ConfigFileSchema{
+			Name:    testSchema,
+			Content: `{"type": "object", "required": ["port"], "properties": {"port": {"type": "integer"}}}`,
+			Comment: "server config",
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.CreateConfigFileSchema(testSuit.defaultCtx, &model.ConfigFileSchema{
+			Name:    testSchema,
+			Content: `{"type": "object"}`,
+		})
+		assert.Equal(t, api.ExistedResource, rsp.Code.GetValue())
+
+		schema, rsp := testSuit.testService.GetConfigFileSchema(testSuit.defaultCtx, testSchema)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, "server config", schema.Comment)
+
+		schemas, rsp := testSuit.testService.GetAllConfigFileSchemas(testSuit.defaultCtx)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, 1, len(schemas))
+	})
+
+	t.Run("不满足JSON Schema的配置文件不能保存", func(t *testing.T) {
+		configFile := assembleSchemaConfigFile("port: 8080\nhost:\n  name: localhost\n")
+		configFile.Tags[len(configFile.Tags)-1].Value = utils.NewStringValue("not-exist-schema")
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+
+		configFile = assembleSchemaConfigFile("host:\n  name: localhost\nport: \"8080\"\n")
+		rsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.InvalidConfigFileContent, rsp.Code.GetValue())
+		assert.True(t, strings.Contains(rsp.Info.GetValue(), "line 3, column 1: /port"), rsp.Info.GetValue())
+
+		configFile = assembleSchemaConfigFile("port: 8080\nhost:\n  name: localhost\n")
+		rsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	})
+
+	t.Run("JSON Schema更新后发布时重新校验", func(t *testing.T) {
+		rsp := testSuit.testService.UpdateConfigFileSchema(testSuit.defaultCtx, &model.ConfigFileSchema{
+			Name: testSchema,
+			Content: `{"type": "object", "required": ["port"], "properties": {"port": {"type": "integer"},
+"host": {"type": "object", "required": ["port"]}}}`,
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		configFile := assembleSchemaConfigFile("port: 8080\nhost:\n  name: localhost\n")
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.InvalidConfigFileContent, rsp.Code.GetValue())
+		assert.True(t, strings.Contains(rsp.Info.GetValue(), "line 2, column 1: /host"), rsp.Info.GetValue())
+
+		rsp = testSuit.testService.UpdateConfigFileSchema(testSuit.defaultCtx, &model.ConfigFileSchema{
+			Name:    "not-exist-schema",
+			Content: `{"type": "object"}`,
+		})
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+	})
+}
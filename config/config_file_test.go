@@ -317,3 +317,47 @@ func TestPublishConfigFile(t *testing.T) {
 	assert.Equal(t, 2, len(rsp9.ConfigFileReleaseHistories))
 
 }
+
+// TestConfigFileNamespaceQuota 测试命名空间的配置文件配额
+func TestConfigFileNamespaceQuota(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	group := randomStr()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, assembleConfigFileWithFixedGroupAndRandomFileName(group))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	updateQuota := func(quota uint32) {
+		namespace, err := testSuit.storage.GetNamespace(testNamespace)
+		assert.Nil(t, err)
+		assert.NotNil(t, namespace)
+		namespace.ConfigFileQuota = quota
+		assert.Nil(t, testSuit.storage.UpdateNamespace(namespace))
+	}
+	updateQuota(2)
+	defer updateQuota(0)
+
+	rsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, assembleConfigFileWithFixedGroupAndRandomFileName(group))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	// 达到配额之后，无法继续创建配置文件
+	rsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, assembleConfigFileWithFixedGroupAndRandomFileName(group))
+	assert.Equal(t, api.NamespaceQuotaExceeded, rsp.Code.GetValue())
+
+	count, err := testSuit.storage.CountConfigFiles(testNamespace)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	// 取消配额之后，可以继续创建配置文件
+	updateQuota(0)
+	rsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, assembleConfigFileWithFixedGroupAndRandomFileName(group))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+}
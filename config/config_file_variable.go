@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// GetConfigFileVariables 获取命名空间下的全部模板变量
+func (s *Server) GetConfigFileVariables(ctx context.Context,
+	namespace string) ([]*model.ConfigFileVariable, *api.ConfigResponse) {
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return nil, api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	variables, err := s.storage.QueryConfigFileVariables(namespace)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file variables error.",
+			utils.ZapRequestIDByCtx(ctx), zap.String("namespace", namespace), zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return variables, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// CreateConfigFileVariable 创建模板变量，只对之后发布的配置文件生效
+func (s *Server) CreateConfigFileVariable(ctx context.Context,
+	variable *model.ConfigFileVariable) *api.ConfigResponse {
+
+	if rsp := checkConfigFileVariableParam(variable); rsp != nil {
+		return rsp
+	}
+	if !s.checkNamespaceExisted(variable.Namespace) {
+		return api.NewConfigFileResponse(api.NotFoundNamespace, nil)
+	}
+
+	userName := utils.ParseUserName(ctx)
+	variable.CreateBy = userName
+	variable.ModifyBy = userName
+	if _, err := s.storage.CreateConfigFileVariable(variable); err != nil {
+		if store.Code(err) == store.DuplicateEntryErr {
+			return api.NewConfigFileResponseWithMessage(api.ExistedResource, "config file variable existed")
+		}
+		log.ConfigScope().Error("[Config][Service] create config file variable error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", variable.Namespace),
+			zap.String("name", variable.Name),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// UpdateConfigFileVariable 更新模板变量，已经发布的配置文件不会重新渲染
+func (s *Server) UpdateConfigFileVariable(ctx context.Context,
+	variable *model.ConfigFileVariable) *api.ConfigResponse {
+
+	if rsp := checkConfigFileVariableParam(variable); rsp != nil {
+		return rsp
+	}
+	managedVariable, err := s.storage.GetConfigFileVariable(variable.Namespace, variable.Name)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file variable error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", variable.Namespace),
+			zap.String("name", variable.Name),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if managedVariable == nil {
+		return api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+
+	variable.ModifyBy = utils.ParseUserName(ctx)
+	if err := s.storage.UpdateConfigFileVariable(variable); err != nil {
+		log.ConfigScope().Error("[Config][Service] update config file variable error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", variable.Namespace),
+			zap.String("name", variable.Name),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// DeleteConfigFileVariable 删除模板变量，引用了该变量的配置文件再次发布时会渲染失败
+func (s *Server) DeleteConfigFileVariable(ctx context.Context, namespace, name string) *api.ConfigResponse {
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if err := s.storage.DeleteConfigFileVariable(namespace, name); err != nil {
+		log.ConfigScope().Error("[Config][Service] delete config file variable error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", namespace),
+			zap.String("name", name),
+			zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// checkConfigFileVariableParam 校验模板变量，未指定类型时为字符串类型
+func checkConfigFileVariableParam(variable *model.ConfigFileVariable) *api.ConfigResponse {
+	if variable == nil {
+		return api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(variable.Namespace)); err != nil {
+		return api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if err := utils2.CheckVariableName(variable.Name); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+	if variable.Type == "" {
+		variable.Type = utils.VariableTypeString
+	}
+	if err := utils2.CheckVariableValue(variable.Type, variable.Value); err != nil {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, err.Error())
+	}
+	if err := utils2.CheckContentLength(variable.Value); err != nil {
+		return api.NewConfigFileResponse(api.InvalidConfigFileContentLength, nil)
+	}
+	return nil
+}
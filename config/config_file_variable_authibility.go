@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// GetConfigFileVariables 获取命名空间下的全部模板变量
+func (s *serverAuthability) GetConfigFileVariables(ctx context.Context,
+	namespace string) ([]*model.ConfigFileVariable, *api.ConfigResponse) {
+	return s.targetServer.GetConfigFileVariables(ctx, namespace)
+}
+
+// CreateConfigFileVariable 创建模板变量
+func (s *serverAuthability) CreateConfigFileVariable(ctx context.Context,
+	variable *model.ConfigFileVariable) *api.ConfigResponse {
+
+	ctx, rsp := s.checkConfigFileVariablePermission(ctx, model.Create, "CreateConfigFileVariable")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.CreateConfigFileVariable(ctx, variable)
+}
+
+// UpdateConfigFileVariable 更新模板变量
+func (s *serverAuthability) UpdateConfigFileVariable(ctx context.Context,
+	variable *model.ConfigFileVariable) *api.ConfigResponse {
+
+	ctx, rsp := s.checkConfigFileVariablePermission(ctx, model.Modify, "UpdateConfigFileVariable")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.UpdateConfigFileVariable(ctx, variable)
+}
+
+// DeleteConfigFileVariable 删除模板变量
+func (s *serverAuthability) DeleteConfigFileVariable(ctx context.Context, namespace, name string) *api.ConfigResponse {
+	ctx, rsp := s.checkConfigFileVariablePermission(ctx, model.Delete, "DeleteConfigFileVariable")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.DeleteConfigFileVariable(ctx, namespace, name)
+}
+
+// RenderConfigFile 预览配置文件渲染后的内容
+func (s *serverAuthability) RenderConfigFile(ctx context.Context, namespace, group,
+	name string) (string, *api.ConfigResponse) {
+	return s.targetServer.RenderConfigFile(ctx, namespace, group, name)
+}
+
+func (s *serverAuthability) checkConfigFileVariablePermission(ctx context.Context,
+	op model.ResourceOperation, method string) (context.Context, *api.ConfigResponse) {
+
+	authCtx := s.collectConfigFileVariableAuthContext(ctx, op, method)
+	if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+		return nil, api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return ctx, nil
+}
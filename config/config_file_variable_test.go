@@ -0,0 +1,192 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+var (
+	testBaseFile = "base.yaml"
+)
+
+func assembleRenderConfigFile(name, content string, render bool) *api.ConfigFile {
+	configFile := assembleConfigFile()
+	configFile.Name = utils.NewStringValue(name)
+	configFile.Format = utils.NewStringValue(utils.FileFormatYaml)
+	configFile.Content = utils.NewStringValue(content)
+	if render {
+		configFile.Tags = append(configFile.Tags, &api.ConfigFileTag{
+			Key:   utils.NewStringValue(utils.ConfigFileTagKeyRender),
+			Value: utils.NewStringValue("true"),
+		})
+	}
+	return configFile
+}
+
+// TestConfigFileVariable 测试模板变量的增删改查，以及开启渲染的配置文件发布时使用模板变量渲染
+func TestConfigFileVariable(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	baseFile := assembleRenderConfigFile(testBaseFile, "server:\n  host: localhost\n  port: 8080\nlog:\n  level: info\n", false)
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, baseFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	t.Run("模板变量增删改查", func(t *testing.T) {
+		rsp := testSuit.testService.CreateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "port",
+			Type:      utils.VariableTypeInt,
+			Value:     "abc",
+		})
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.CreateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "${port}",
+			Value:     "9090",
+		})
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.CreateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "port",
+			Type:      utils.VariableTypeInt,
+			Value:     "9090",
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.CreateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "port",
+			Value:     "9091",
+		})
+		assert.Equal(t, api.ExistedResource, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.CreateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "db.host",
+			Value:     "127.0.0.1",
+			Comment:   "database host",
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.UpdateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "db.host",
+			Value:     "10.0.0.1",
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.UpdateConfigFileVariable(testSuit.defaultCtx, &model.ConfigFileVariable{
+			Namespace: testNamespace,
+			Name:      "not.exist",
+			Value:     "value",
+		})
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+
+		variables, rsp := testSuit.testService.GetConfigFileVariables(testSuit.defaultCtx, testNamespace)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, 2, len(variables))
+		for _, variable := range variables {
+			if variable.Name == "db.host" {
+				assert.Equal(t, "10.0.0.1", variable.Value)
+				assert.Equal(t, utils.VariableTypeString, variable.Type)
+			}
+		}
+	})
+
+	content := "${extends:" + testBaseFile + "}\nserver:\n  port: ${port}\ndb:\n  host: ${db.host}\n  password: $${DB_PASSWORD}\n"
+	configFile := assembleRenderConfigFile(testFile, content, true)
+
+	t.Run("发布时渲染配置文件", func(t *testing.T) {
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rendered, rsp := testSuit.testService.RenderConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.True(t, strings.Contains(rendered, "host: localhost"), rendered)
+		assert.True(t, strings.Contains(rendered, "port: 9090"), rendered)
+		assert.True(t, strings.Contains(rendered, "level: info"), rendered)
+		assert.True(t, strings.Contains(rendered, "host: 10.0.0.1"), rendered)
+		assert.True(t, strings.Contains(rendered, "password: ${DB_PASSWORD}"), rendered)
+
+		// 未开启渲染的配置文件返回原始内容
+		raw, rsp := testSuit.testService.RenderConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testBaseFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, baseFile.Content.GetValue(), raw)
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.GetConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, rendered, rsp.ConfigFileRelease.Content.GetValue())
+		assert.Equal(t, utils2.CalMd5(rendered), rsp.ConfigFileRelease.Md5.GetValue())
+
+		// 配置文件本身保存的仍然是模板
+		rsp = testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, content, rsp.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("变量不存在时发布失败", func(t *testing.T) {
+		rsp := testSuit.testService.DeleteConfigFileVariable(testSuit.defaultCtx, testNamespace, "port")
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.InvalidConfigFileTemplate, rsp.Code.GetValue())
+		assert.True(t, strings.Contains(rsp.Info.GetValue(), "undefined variables port"), rsp.Info.GetValue())
+
+		_, rsp = testSuit.testService.RenderConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.InvalidConfigFileTemplate, rsp.Code.GetValue())
+	})
+
+	t.Run("回滚发布历史不覆盖模板", func(t *testing.T) {
+		latest := testSuit.testService.GetConfigFileLatestReleaseHistory(testSuit.defaultCtx, testNamespace,
+			testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, latest.Code.GetValue())
+		history := latest.ConfigFileReleaseHistory
+
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, history.Id.GetValue())
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, history.Content.GetValue(), rsp.ConfigFileRelease.Content.GetValue())
+
+		rsp = testSuit.testService.GetConfigFileRichInfo(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, content, rsp.ConfigFile.Content.GetValue())
+	})
+}
@@ -0,0 +1,232 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// CreateConfigFileWebhook 创建配置文件变更 webhook，未指定 secret 时自动生成
+func (s *Server) CreateConfigFileWebhook(ctx context.Context,
+	webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, *api.ConfigResponse) {
+
+	if webhook == nil {
+		return nil, api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(webhook.Name)); err != nil {
+		return nil, api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid webhook name")
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(webhook.Namespace)); err != nil {
+		return nil, api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if webhook.Group != "" {
+		if err := utils2.CheckResourceName(utils.NewStringValue(webhook.Group)); err != nil {
+			return nil, api.NewConfigFileResponse(api.InvalidConfigFileGroupName, nil)
+		}
+	}
+	if webhook.FileName != "" {
+		if webhook.Group == "" {
+			return nil, api.NewConfigFileResponseWithMessage(api.InvalidParameter,
+				"group can not be blank when file name is specified")
+		}
+		if err := utils2.CheckFileName(utils.NewStringValue(webhook.FileName)); err != nil {
+			return nil, api.NewConfigFileResponse(api.InvalidConfigFileName, nil)
+		}
+	}
+	if rsp := checkConfigFileWebhookParam(webhook); rsp != nil {
+		return nil, rsp
+	}
+
+	if webhook.Secret == "" {
+		webhook.Secret = strings.ReplaceAll(utils.NewUUID(), "-", "")
+	}
+	userName := utils.ParseUserName(ctx)
+	webhook.CreateBy = userName
+	webhook.ModifyBy = userName
+
+	created, err := s.storage.CreateConfigFileWebhook(webhook)
+	if err != nil {
+		if store.Code(err) == store.DuplicateEntryErr {
+			return nil, api.NewConfigFileResponseWithMessage(api.ExistedResource, "config file webhook existed")
+		}
+		log.ConfigScope().Error("[Config][Service] create config file webhook error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", webhook.Namespace),
+			zap.String("name", webhook.Name),
+			zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return created, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// UpdateConfigFileWebhook 更新配置文件变更 webhook，名称和订阅范围不能修改，secret 为空时保持不变
+func (s *Server) UpdateConfigFileWebhook(ctx context.Context, webhook *model.ConfigFileWebhook) *api.ConfigResponse {
+	if webhook == nil {
+		return api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	if rsp := checkConfigFileWebhookParam(webhook); rsp != nil {
+		return rsp
+	}
+
+	managedWebhook, rsp := s.GetConfigFileWebhook(ctx, webhook.Id)
+	if managedWebhook == nil {
+		return rsp
+	}
+
+	managedWebhook.Url = webhook.Url
+	managedWebhook.Events = webhook.Events
+	managedWebhook.Enable = webhook.Enable
+	managedWebhook.Comment = webhook.Comment
+	if webhook.Secret != "" {
+		managedWebhook.Secret = webhook.Secret
+	}
+	managedWebhook.ModifyBy = utils.ParseUserName(ctx)
+
+	if err := s.storage.UpdateConfigFileWebhook(managedWebhook); err != nil {
+		log.ConfigScope().Error("[Config][Service] update config file webhook error.",
+			utils.ZapRequestIDByCtx(ctx), zap.Uint64("id", webhook.Id), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// DeleteConfigFileWebhook 删除配置文件变更 webhook，推送中的记录不会再重试
+func (s *Server) DeleteConfigFileWebhook(ctx context.Context, id uint64) *api.ConfigResponse {
+	managedWebhook, rsp := s.GetConfigFileWebhook(ctx, id)
+	if managedWebhook == nil {
+		if rsp.GetCode().GetValue() == api.NotFoundResource {
+			return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+		}
+		return rsp
+	}
+
+	if err := s.storage.DeleteConfigFileWebhook(id); err != nil {
+		log.ConfigScope().Error("[Config][Service] delete config file webhook error.",
+			utils.ZapRequestIDByCtx(ctx), zap.Uint64("id", id), zap.Error(err))
+		return api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// GetConfigFileWebhook 获取单个配置文件变更 webhook
+func (s *Server) GetConfigFileWebhook(ctx context.Context,
+	id uint64) (*model.ConfigFileWebhook, *api.ConfigResponse) {
+
+	webhook, err := s.storage.GetConfigFileWebhook(id)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] get config file webhook error.",
+			utils.ZapRequestIDByCtx(ctx), zap.Uint64("id", id), zap.Error(err))
+		return nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	if webhook == nil {
+		return nil, api.NewConfigFileResponse(api.NotFoundResource, nil)
+	}
+	return webhook, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// QueryConfigFileWebhooks 查询命名空间下的配置文件变更 webhook，group、fileName 为空时不作为查询条件
+func (s *Server) QueryConfigFileWebhooks(ctx context.Context, namespace, group, fileName string,
+	offset, limit uint32) (uint32, []*model.ConfigFileWebhook, *api.ConfigResponse) {
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return 0, nil, api.NewConfigFileResponse(api.InvalidNamespaceName, nil)
+	}
+	if limit <= 0 || limit > MaxPageSize {
+		return 0, nil, api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+
+	total, webhooks, err := s.storage.QueryConfigFileWebhooks(namespace, group, fileName, offset, limit)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file webhooks error.",
+			utils.ZapRequestIDByCtx(ctx),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return 0, nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return total, webhooks, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// QueryConfigFileWebhookDeliveries 查询 webhook 的推送记录，status 为空时查询全部状态
+func (s *Server) QueryConfigFileWebhookDeliveries(ctx context.Context, webhookId uint64, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, *api.ConfigResponse) {
+
+	if limit <= 0 || limit > MaxPageSize {
+		return 0, nil, api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	if status != "" && !isValidWebhookDeliveryStatus(status) {
+		return 0, nil, api.NewConfigFileResponseWithMessage(api.InvalidParameter,
+			"invalid delivery status "+status)
+	}
+
+	total, deliveries, err := s.storage.QueryConfigFileWebhookDeliveries(webhookId, status, offset, limit)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Service] query config file webhook deliveries error.",
+			utils.ZapRequestIDByCtx(ctx), zap.Uint64("webhookId", webhookId), zap.Error(err))
+		return 0, nil, api.NewConfigFileResponse(api.StoreLayerException, nil)
+	}
+	return total, deliveries, api.NewConfigFileResponse(api.ExecuteSuccess, nil)
+}
+
+// checkConfigFileWebhookParam 校验 webhook 的回调地址和订阅的事件，events 会被规整为逗号分隔的去重列表
+func checkConfigFileWebhookParam(webhook *model.ConfigFileWebhook) *api.ConfigResponse {
+	u, err := url.Parse(webhook.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid webhook url")
+	}
+
+	events := make([]string, 0, 2)
+	seen := map[string]struct{}{}
+	for _, event := range strings.Split(webhook.Events, ",") {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
+		}
+		if event != utils.WebhookEventPublish && event != utils.WebhookEventDelete {
+			return api.NewConfigFileResponseWithMessage(api.InvalidParameter, "invalid webhook event "+event)
+		}
+		if _, ok := seen[event]; ok {
+			continue
+		}
+		seen[event] = struct{}{}
+		events = append(events, event)
+	}
+	webhook.Events = strings.Join(events, ",")
+	return nil
+}
+
+func isValidWebhookDeliveryStatus(status string) bool {
+	switch status {
+	case utils.WebhookDeliveryPending, utils.WebhookDeliverySuccess, utils.WebhookDeliveryFailed:
+		return true
+	default:
+		return false
+	}
+}
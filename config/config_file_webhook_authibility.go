@@ -0,0 +1,114 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// CreateConfigFileWebhook 创建 webhook，需要拥有订阅范围的写权限
+func (s *serverAuthability) CreateConfigFileWebhook(ctx context.Context,
+	webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, *api.ConfigResponse) {
+
+	if webhook == nil {
+		return nil, api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	ctx, rsp := s.checkConfigFileWebhookPermission(ctx, webhook, model.Create, "CreateConfigFileWebhook")
+	if rsp != nil {
+		return nil, rsp
+	}
+	return s.targetServer.CreateConfigFileWebhook(ctx, webhook)
+}
+
+// UpdateConfigFileWebhook 更新 webhook，需要拥有订阅范围的写权限
+func (s *serverAuthability) UpdateConfigFileWebhook(ctx context.Context,
+	webhook *model.ConfigFileWebhook) *api.ConfigResponse {
+
+	if webhook == nil {
+		return api.NewConfigFileResponse(api.InvalidParameter, nil)
+	}
+	managedWebhook, rsp := s.targetServer.GetConfigFileWebhook(ctx, webhook.Id)
+	if managedWebhook == nil {
+		return rsp
+	}
+	ctx, rsp = s.checkConfigFileWebhookPermission(ctx, managedWebhook, model.Modify, "UpdateConfigFileWebhook")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.UpdateConfigFileWebhook(ctx, webhook)
+}
+
+// DeleteConfigFileWebhook 删除 webhook，需要拥有订阅范围的写权限
+func (s *serverAuthability) DeleteConfigFileWebhook(ctx context.Context, id uint64) *api.ConfigResponse {
+	managedWebhook, rsp := s.targetServer.GetConfigFileWebhook(ctx, id)
+	if managedWebhook == nil {
+		return s.targetServer.DeleteConfigFileWebhook(ctx, id)
+	}
+	ctx, rsp = s.checkConfigFileWebhookPermission(ctx, managedWebhook, model.Delete, "DeleteConfigFileWebhook")
+	if rsp != nil {
+		return rsp
+	}
+	return s.targetServer.DeleteConfigFileWebhook(ctx, id)
+}
+
+// GetConfigFileWebhook 获取单个 webhook
+func (s *serverAuthability) GetConfigFileWebhook(ctx context.Context,
+	id uint64) (*model.ConfigFileWebhook, *api.ConfigResponse) {
+	return s.targetServer.GetConfigFileWebhook(ctx, id)
+}
+
+// QueryConfigFileWebhooks 查询命名空间下的 webhook
+func (s *serverAuthability) QueryConfigFileWebhooks(ctx context.Context, namespace, group, fileName string,
+	offset, limit uint32) (uint32, []*model.ConfigFileWebhook, *api.ConfigResponse) {
+	return s.targetServer.QueryConfigFileWebhooks(ctx, namespace, group, fileName, offset, limit)
+}
+
+// QueryConfigFileWebhookDeliveries 查询 webhook 的推送记录
+func (s *serverAuthability) QueryConfigFileWebhookDeliveries(ctx context.Context, webhookId uint64,
+	status string, offset, limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, *api.ConfigResponse) {
+	return s.targetServer.QueryConfigFileWebhookDeliveries(ctx, webhookId, status, offset, limit)
+}
+
+// checkConfigFileWebhookPermission 订阅了配置分组的 webhook 需要拥有分组的写权限，订阅整个命名空间的 webhook
+// 按照配置模块的权限校验
+func (s *serverAuthability) checkConfigFileWebhookPermission(ctx context.Context, webhook *model.ConfigFileWebhook,
+	op model.ResourceOperation, method string) (context.Context, *api.ConfigResponse) {
+
+	var authCtx *model.AcquireContext
+	if webhook.Group != "" {
+		var rsp *api.ConfigResponse
+		authCtx, rsp = s.checkConfigFileReleasePermission(ctx, webhook.Namespace, webhook.Group,
+			webhook.FileName, method)
+		if rsp != nil {
+			return nil, rsp
+		}
+	} else {
+		authCtx = s.collectConfigFileWebhookAuthContext(ctx, op, method)
+		if _, err := s.checker.CheckConsolePermission(authCtx); err != nil {
+			return nil, api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+		}
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return ctx, nil
+}
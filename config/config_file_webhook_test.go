@@ -0,0 +1,221 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+type webhookRequest struct {
+	header  http.Header
+	payload *WebhookPayload
+	body    []byte
+}
+
+// TestConfigFileWebhook 测试配置文件发布、删除后推送 webhook，推送失败后重试并记录推送结果
+func TestConfigFileWebhook(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// 第一次推送返回 500，用于验证失败重试
+	var calls int32
+	requests := make(chan *webhookRequest, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		payload := &WebhookPayload{}
+		_ = json.Unmarshal(body, payload)
+		requests <- &webhookRequest{header: r.Header, payload: payload, body: body}
+	}))
+	defer receiver.Close()
+
+	webhook := &model.ConfigFileWebhook{
+		Name:      "testWebhook",
+		Namespace: testNamespace,
+		Group:     testGroup,
+		FileName:  testFile,
+		Url:       receiver.URL,
+		Events:    utils.WebhookEventPublish,
+		Enable:    true,
+	}
+
+	t.Run("参数校验", func(t *testing.T) {
+		invalid := *webhook
+		invalid.Url = "ftp://example.com"
+		_, rsp := testSuit.testService.CreateConfigFileWebhook(testSuit.defaultCtx, &invalid)
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		invalid = *webhook
+		invalid.Events = "publish,rollback"
+		_, rsp = testSuit.testService.CreateConfigFileWebhook(testSuit.defaultCtx, &invalid)
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+
+		invalid = *webhook
+		invalid.Group = ""
+		_, rsp = testSuit.testService.CreateConfigFileWebhook(testSuit.defaultCtx, &invalid)
+		assert.Equal(t, api.InvalidParameter, rsp.Code.GetValue())
+	})
+
+	t.Run("创建webhook", func(t *testing.T) {
+		created, rsp := testSuit.testService.CreateConfigFileWebhook(testSuit.defaultCtx, webhook)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.Info.GetValue())
+		assert.NotEmpty(t, created.Secret)
+		webhook = created
+
+		duplicate := *webhook
+		_, rsp = testSuit.testService.CreateConfigFileWebhook(testSuit.defaultCtx, &duplicate)
+		assert.Equal(t, api.ExistedResource, rsp.Code.GetValue())
+
+		total, webhooks, rsp := testSuit.testService.QueryConfigFileWebhooks(testSuit.defaultCtx,
+			testNamespace, "", "", 0, 10)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint32(1), total)
+		assert.Equal(t, webhook.Id, webhooks[0].Id)
+	})
+
+	var publishedMd5 string
+	t.Run("发布配置文件后推送", func(t *testing.T) {
+		configFile := assembleConfigFile()
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		publishedMd5 = rsp.ConfigFileRelease.Md5.GetValue()
+
+		req := waitWebhookRequest(t, requests)
+		assert.Equal(t, utils.WebhookEventPublish, req.header.Get(WebhookEventHeader))
+		assert.Nil(t, VerifyWebhookSignature(webhook.Secret, req.header.Get(WebhookTimestampHeader),
+			req.header.Get(WebhookSignatureHeader), req.body, WebhookSignatureTolerance, time.Now()))
+		assert.NotEmpty(t, req.header.Get(WebhookDeliveryHeader))
+		assert.Equal(t, utils.WebhookEventPublish, req.payload.Event)
+		assert.Equal(t, testNamespace, req.payload.Namespace)
+		assert.Equal(t, testGroup, req.payload.Group)
+		assert.Equal(t, testFile, req.payload.FileName)
+		assert.Equal(t, publishedMd5, req.payload.NewMd5)
+		assert.Equal(t, "", req.payload.OldMd5)
+		assert.Equal(t, uint64(1), req.payload.Version)
+
+		delivery := waitWebhookDelivery(t, testSuit, webhook.Id, utils.WebhookDeliverySuccess)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, http.StatusOK, delivery.ResponseCode)
+		assert.Equal(t, utils.WebhookEventPublish, delivery.EventType)
+	})
+
+	t.Run("只订阅删除事件", func(t *testing.T) {
+		webhook.Events = utils.WebhookEventDelete
+		webhook.Secret = ""
+		rsp := testSuit.testService.UpdateConfigFileWebhook(testSuit.defaultCtx, webhook)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		managed, _ := testSuit.testService.GetConfigFileWebhook(testSuit.defaultCtx, webhook.Id)
+		assert.Equal(t, utils.WebhookEventDelete, managed.Events)
+		assert.NotEmpty(t, managed.Secret)
+
+		rsp = testSuit.testService.DeleteConfigFile(testSuit.defaultCtx, testNamespace, testGroup, testFile,
+			operator)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		req := waitWebhookRequest(t, requests)
+		assert.Equal(t, utils.WebhookEventDelete, req.payload.Event)
+		assert.Equal(t, publishedMd5, req.payload.OldMd5)
+		assert.Equal(t, "", req.payload.NewMd5)
+
+		total, deliveries, rsp := testSuit.testService.QueryConfigFileWebhookDeliveries(testSuit.defaultCtx,
+			webhook.Id, "", 0, 10)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+		assert.Equal(t, uint32(2), total)
+		assert.Equal(t, utils.WebhookEventDelete, deliveries[0].EventType)
+	})
+
+	t.Run("删除webhook", func(t *testing.T) {
+		rsp := testSuit.testService.DeleteConfigFileWebhook(testSuit.defaultCtx, webhook.Id)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		_, rsp = testSuit.testService.GetConfigFileWebhook(testSuit.defaultCtx, webhook.Id)
+		assert.Equal(t, api.NotFoundResource, rsp.Code.GetValue())
+	})
+}
+
+func waitWebhookRequest(t *testing.T, requests chan *webhookRequest) *webhookRequest {
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(10 * time.Second):
+		t.Fatal("wait webhook request timeout")
+		return nil
+	}
+}
+
+func waitWebhookDelivery(t *testing.T, testSuit *ConfigCenterTest, webhookId uint64,
+	status string) *model.ConfigFileWebhookDelivery {
+
+	for i := 0; i < 50; i++ {
+		_, deliveries, _ := testSuit.testService.QueryConfigFileWebhookDeliveries(testSuit.defaultCtx,
+			webhookId, status, 0, 10)
+		if len(deliveries) > 0 {
+			return deliveries[0]
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("wait webhook delivery with status %s timeout", status)
+	return nil
+}
+
+// TestVerifyWebhookSignature 测试签名覆盖推送时间，过期的请求以及篡改的请求体校验失败
+func TestVerifyWebhookSignature(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"event":"publish"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := SignWebhookPayload("secret", timestamp, body)
+
+	assert.Nil(t, VerifyWebhookSignature("secret", timestamp, signature, body, WebhookSignatureTolerance, now))
+	// 重放超过允许偏差的请求
+	assert.NotNil(t, VerifyWebhookSignature("secret", timestamp, signature, body, WebhookSignatureTolerance,
+		now.Add(WebhookSignatureTolerance+time.Second)))
+	// 修改推送时间后签名不匹配
+	newTimestamp := strconv.FormatInt(now.Unix()+1, 10)
+	assert.NotNil(t, VerifyWebhookSignature("secret", newTimestamp, signature, body, WebhookSignatureTolerance, now))
+	assert.NotNil(t, VerifyWebhookSignature("secret", timestamp, signature, []byte(`{}`), WebhookSignatureTolerance,
+		now))
+	assert.NotNil(t, VerifyWebhookSignature("other", timestamp, signature, body, WebhookSignatureTolerance, now))
+	assert.NotNil(t, VerifyWebhookSignature("secret", "invalid", signature, body, WebhookSignatureTolerance, now))
+}
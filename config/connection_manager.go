@@ -20,6 +20,7 @@ package config
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
@@ -28,8 +29,36 @@ import (
 
 const (
 	defaultLongPollingTimeout = 30000 * time.Millisecond
+	defaultPushBatchInterval  = 100 * time.Millisecond
 )
 
+// WatchConfig 客户端订阅配置的相关配置
+type WatchConfig struct {
+	// LongPollingTimeout 长轮询请求的最长等待时间
+	LongPollingTimeout time.Duration `yaml:"longPollingTimeout"`
+	// PushBatchInterval 长连接推送的合并间隔，间隔内发生变更的配置文件合并为一次推送
+	PushBatchInterval time.Duration `yaml:"pushBatchInterval"`
+}
+
+func (c *WatchConfig) setDefault() {
+	if c.LongPollingTimeout <= 0 {
+		c.LongPollingTimeout = defaultLongPollingTimeout
+	}
+	if c.PushBatchInterval <= 0 {
+		c.PushBatchInterval = defaultPushBatchInterval
+	}
+}
+
+// ConnStats 配置中心客户端连接统计
+type ConnStats struct {
+	// LongPollingConns 正在等待的长轮询请求数
+	LongPollingConns int64 `json:"longPollingConns"`
+	// StreamConns 订阅配置的长连接数
+	StreamConns int64 `json:"streamConns"`
+	// StreamWatchFiles 长连接上订阅的配置文件总数
+	StreamWatchFiles int64 `json:"streamWatchFiles"`
+}
+
 type connection struct {
 	finishTime       time.Time
 	finishChan       chan *api.ConfigClientResponse
@@ -37,8 +66,13 @@ type connection struct {
 }
 
 type connManager struct {
+	// connCount、streamCount 使用原子操作，放在结构体开头保证 64 位对齐
+	connCount      int64
+	streamCount    int64
 	watchCenter    *watchCenter
 	conns          *sync.Map // client -> connection
+	streams        *sync.Map // client -> WatchStream
+	conf           WatchConfig
 	stopWorkerFunc context.CancelFunc
 }
 
@@ -52,10 +86,13 @@ var (
 )
 
 // NewConfigConnManager 初始化连接管理器，定时响应超时的请求
-func NewConfigConnManager(ctx context.Context, watchCenter *watchCenter) *connManager {
+func NewConfigConnManager(ctx context.Context, watchCenter *watchCenter, conf WatchConfig) *connManager {
+	conf.setDefault()
 	cm = &connManager{
 		conns:       new(sync.Map),
+		streams:     new(sync.Map),
 		watchCenter: watchCenter,
+		conf:        conf,
 	}
 
 	go cm.startHandleTimeoutRequestWorker(ctx)
@@ -63,17 +100,18 @@ func NewConfigConnManager(ctx context.Context, watchCenter *watchCenter) *connMa
 	return cm
 }
 
-func (c *connManager) AddConn(clientId string, files []*api.ClientConfigFileInfo) chan *api.ConfigClientResponse {
+func (c *connManager) AddConn(clientId string, client clientIdentity, files []*api.ClientConfigFileInfo) chan *api.ConfigClientResponse {
 
 	finishChan := make(chan *api.ConfigClientResponse)
 
 	cm.conns.Store(clientId, &connection{
-		finishTime:       time.Now().Add(defaultLongPollingTimeout),
+		finishTime:       time.Now().Add(c.conf.LongPollingTimeout),
 		finishChan:       finishChan,
 		watchConfigFiles: files,
 	})
+	atomic.AddInt64(&c.connCount, 1)
 
-	c.watchCenter.AddWatcher(clientId, files, func(clientId string, rsp *api.ConfigClientResponse) bool {
+	c.watchCenter.AddWatcher(clientId, client, files, func(clientId string, rsp *api.ConfigClientResponse) bool {
 		connObj, ok := cm.conns.Load(clientId)
 		if ok {
 			conn := connObj.(*connection)
@@ -88,15 +126,27 @@ func (c *connManager) AddConn(clientId string, files []*api.ClientConfigFileInfo
 }
 
 func (c *connManager) removeConn(clientId string) {
-	conn, ok := cm.conns.Load(clientId)
+	conn, ok := cm.conns.LoadAndDelete(clientId)
 	if !ok {
 		return
 	}
 	connObj := conn.(*connection)
 
 	c.watchCenter.RemoveWatcher(clientId, connObj.watchConfigFiles)
+	atomic.AddInt64(&c.connCount, -1)
+}
 
-	cm.conns.Delete(clientId)
+// Stats 获取客户端连接统计
+func (c *connManager) Stats() *ConnStats {
+	stats := &ConnStats{
+		LongPollingConns: atomic.LoadInt64(&c.connCount),
+		StreamConns:      atomic.LoadInt64(&c.streamCount),
+	}
+	c.streams.Range(func(_, stream interface{}) bool {
+		stats.StreamWatchFiles += int64(stream.(*WatchStream).watchFileCount())
+		return true
+	})
+	return stats
 }
 
 func (c *connManager) startHandleTimeoutRequestWorker(ctx context.Context) {
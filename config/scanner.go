@@ -45,14 +45,23 @@ type releaseMessageScanner struct {
 
 	fileCache cache.FileCache
 
+	grayReleases *grayReleaseBucket
+
+	lastGrayScannerTime time.Time
+
+	clientPins *clientPinBucket
+
 	eventCenter *Center
 }
 
 func initReleaseMessageScanner(ctx context.Context, storage store.Store, fileCache cache.FileCache,
-	eventCenter *Center, scanInterval time.Duration) error {
+	grayReleases *grayReleaseBucket, clientPins *clientPinBucket, eventCenter *Center,
+	scanInterval time.Duration) error {
 	scanner := &releaseMessageScanner{
 		storage:      storage,
 		fileCache:    fileCache,
+		grayReleases: grayReleases,
+		clientPins:   clientPins,
 		eventCenter:  eventCenter,
 		scanInterval: scanInterval,
 	}
@@ -71,6 +80,17 @@ func (s *releaseMessageScanner) scanAtFirstTime() error {
 	t := time.Now().Add(FirstScanTimeOffset)
 	s.lastScannerTime = t
 
+	// 灰度发布在结束前一直生效，所以启动时需要加载全部的灰度发布
+	if err := s.scanGrayReleases(true, time.Time{}); err != nil {
+		log.ConfigScope().Error("[Config][Scanner] scan config file gray release error.", zap.Error(err))
+		return err
+	}
+
+	if err := s.scanClientPins(true); err != nil {
+		log.ConfigScope().Error("[Config][Scanner] scan config file client pin error.", zap.Error(err))
+		return err
+	}
+
 	releases, err := s.storage.FindConfigFileReleaseByModifyTimeAfter(t)
 	if err != nil {
 		log.ConfigScope().Error("[Config][Scanner] scan config file release error.", zap.Error(err))
@@ -114,6 +134,14 @@ func (s *releaseMessageScanner) startScanTask(ctx context.Context) {
 			if err != nil {
 				log.ConfigScope().Error("[Config][Scanner] handler release message error.", zap.Error(err))
 			}
+
+			if err := s.scanGrayReleases(false, s.lastGrayScannerTime.Add(DefaultScanTimeOffset)); err != nil {
+				log.ConfigScope().Error("[Config][Scanner] scan config file gray release error.", zap.Error(err))
+			}
+
+			if err := s.scanClientPins(false); err != nil {
+				log.ConfigScope().Error("[Config][Scanner] scan config file client pin error.", zap.Error(err))
+			}
 		}
 	}
 }
@@ -132,10 +160,22 @@ func (s *releaseMessageScanner) handlerReleases(firstTime bool, releases []*mode
 			newReleaseCnt++
 		}
 
+		// 结束灰度发布时全量发布的版本号会大于灰度发布的版本号，此时灰度发布已经失效
+		if gray := s.grayReleases.get(release.Namespace, release.Group, release.FileName); gray != nil &&
+			release.Version > gray.Version {
+			s.grayReleases.remove(release.Namespace, release.Group, release.FileName)
+		}
+
 		entry, ok := s.fileCache.Get(release.Namespace, release.Group, release.FileName)
 
 		// 缓存不存在，或者缓存的版本号落后数据库的版本号则处理消息. 因为有版本号判断，所以能够幂等处理重复消息
 		if !ok || entry.Empty || release.Version > entry.Version {
+			// 缓存刷新之前记录变更前的 md5，缓存不存在时为空
+			oldMd5 := ""
+			if ok && !entry.Empty {
+				oldMd5 = entry.Md5
+			}
+
 			if release.Flag == 1 {
 				// 删除的发布消息，因为缓存被清除了，所以会一直判断为新消息，所以通过判断消息是否过期来避免一直重复消费
 				if isExpireMessage(release) {
@@ -155,6 +195,13 @@ func (s *releaseMessageScanner) handlerReleases(firstTime bool, releases []*mode
 					EventType: eventTypePublishConfigFile,
 					Message:   release,
 				})
+				s.eventCenter.handleEvent(Event{
+					EventType: eventTypeConfigFileChanged,
+					Message: &configFileChangedEvent{
+						Release: release,
+						OldMd5:  oldMd5,
+					},
+				})
 			}
 		}
 	}
@@ -168,6 +215,79 @@ func (s *releaseMessageScanner) handlerReleases(firstTime bool, releases []*mode
 	return nil
 }
 
+// scanGrayReleases 扫描灰度发布，更新灰度发布缓存并通知命中灰度规则的客户端
+func (s *releaseMessageScanner) scanGrayReleases(firstTime bool, scanIdx time.Time) error {
+	grays, err := s.storage.FindConfigFileGrayReleaseByModifyTimeAfter(scanIdx)
+	if err != nil {
+		return err
+	}
+
+	for _, gray := range grays {
+		if gray.ModifyTime.After(s.lastGrayScannerTime) {
+			s.lastGrayScannerTime = gray.ModifyTime
+		}
+
+		// 全量发布已经覆盖了灰度发布，忽略已经结束的灰度发布
+		if entry, ok := s.fileCache.Get(gray.Namespace, gray.Group, gray.FileName); ok && !entry.Empty &&
+			entry.Version >= gray.Version {
+			continue
+		}
+
+		release, updated := s.grayReleases.save(gray)
+		if !updated || firstTime || gray.ModifyTime.Before(time.Now().Add(MessageExpireTime)) {
+			continue
+		}
+
+		log.ConfigScope().Info("[Config][Scanner] scan config file gray release.",
+			zap.String("file", release.FileName), zap.Uint64("version", release.Version))
+
+		s.eventCenter.handleEvent(Event{
+			EventType: eventTypeGrayPublishConfigFile,
+			Message:   release,
+		})
+	}
+	return nil
+}
+
+// scanClientPins 全量刷新客户端版本固定缓存，取消固定时记录会被删除，所以无法按照修改时间增量扫描
+func (s *releaseMessageScanner) scanClientPins(firstTime bool) error {
+	pins, err := s.storage.GetAllConfigFileClientPins()
+	if err != nil {
+		return err
+	}
+
+	changed, removed := s.clientPins.reload(pins)
+	if firstTime {
+		return nil
+	}
+
+	notify := func(pin *model.ConfigFileClientPin, isRemoved bool) {
+		entry, ok := s.fileCache.Get(pin.Namespace, pin.Group, pin.FileName)
+		if !ok || entry.Empty {
+			return
+		}
+		log.ConfigScope().Info("[Config][Scanner] scan config file client pin.",
+			zap.String("file", pin.FileName), zap.String("client", pin.Client), zap.Bool("removed", isRemoved))
+
+		s.eventCenter.handleEvent(Event{
+			EventType: eventTypeClientPinChanged,
+			Message: &clientPinEvent{
+				Pin:     pin,
+				Removed: isRemoved,
+				Entry:   entry,
+				Gray:    s.grayReleases.get(pin.Namespace, pin.Group, pin.FileName),
+			},
+		})
+	}
+	for _, pin := range changed {
+		notify(pin, false)
+	}
+	for _, pin := range removed {
+		notify(pin, true)
+	}
+	return nil
+}
+
 func isExpireMessage(release *model.ConfigFileRelease) bool {
 	return release.ModifyTime.Before(time.Now().Add(MessageExpireTime))
 }
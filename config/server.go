@@ -30,13 +30,20 @@ import (
 	"github.com/polarismesh/polaris/common/log"
 	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/namespace"
+	"github.com/polarismesh/polaris/plugin"
 	"github.com/polarismesh/polaris/store"
 )
 
 var _ ConfigCenterServer = (*Server)(nil)
 
 const (
-	eventTypePublishConfigFile  = "PublishConfigFile"
+	eventTypePublishConfigFile = "PublishConfigFile"
+	// eventTypeGrayPublishConfigFile 灰度发布事件，只通知命中灰度规则的客户端
+	eventTypeGrayPublishConfigFile = "GrayPublishConfigFile"
+	// eventTypeClientPinChanged 客户端版本固定变化事件，只通知被固定的客户端
+	eventTypeClientPinChanged = "ClientPinChanged"
+	// eventTypeConfigFileChanged 配置文件发布或者删除事件，携带变更前的 md5，用于推送 webhook
+	eventTypeConfigFileChanged  = "ConfigFileChanged"
 	defaultExpireTimeAfterWrite = 60 * 60 // expire after 1 hour
 )
 
@@ -49,6 +56,12 @@ var (
 type Config struct {
 	Open  bool                   `yaml:"open"`
 	Cache map[string]interface{} `yaml:"cache"`
+	// Review 是否开启配置文件变更审核，开启后编辑配置文件会生成待审核的变更，审核通过后才能发布
+	Review bool `yaml:"review"`
+	// Webhook 配置文件变更 webhook 推送参数
+	Webhook WebhookConfig `yaml:"webhook"`
+	// Watch 客户端订阅配置的相关配置
+	Watch WatchConfig `yaml:"watch"`
 }
 
 // Server 配置中心核心服务
@@ -57,9 +70,18 @@ type Server struct {
 	fileCache         cache.FileCache
 	caches            *cache.CacheManager
 	watchCenter       *watchCenter
+	grayReleases      *grayReleaseBucket
+	clientPins        *clientPinBucket
 	connManager       *connManager
 	namespaceOperator namespace.NamespaceOperateServer
 	initialized       bool
+	// crypto 和 kms 用于加密配置文件，未配置插件时不支持配置加密
+	crypto plugin.Crypto
+	kms    plugin.KMS
+	// reviewOpen 是否开启配置文件变更审核
+	reviewOpen bool
+	// webhooks 配置文件变更 webhook 推送器
+	webhooks *webhookDispatcher
 
 	hooks []ResourceHook
 }
@@ -94,17 +116,31 @@ func (s *Server) initialize(ctx context.Context, config Config, ss store.Store,
 	s.storage = ss
 	s.namespaceOperator = namespaceOperator
 	s.fileCache = cacheMgn.ConfigFile()
+	s.crypto = plugin.GetCrypto()
+	s.kms = plugin.GetKMS()
+	s.reviewOpen = config.Review
 
 	// 初始化事件中心
 	eventCenter := NewEventCenter()
 	s.watchCenter = NewWatchCenter(eventCenter)
+	s.grayReleases = newGrayReleaseBucket()
+	s.watchCenter.SetGrayMatcher(s.matchGrayRule)
+	s.clientPins = newClientPinBucket()
+	s.watchCenter.SetPinMatcher(func(namespace, group, fileName string, client clientIdentity) bool {
+		return s.clientPins.get(namespace, group, fileName, client) != nil
+	})
 
 	// 初始化连接管理器
-	connMng := NewConfigConnManager(ctx, s.watchCenter)
+	connMng := NewConfigConnManager(ctx, s.watchCenter, config.Watch)
 	s.connManager = connMng
 
+	// 初始化 webhook 推送器，需要在发布事件扫描器之前订阅事件
+	s.webhooks = newWebhookDispatcher(ctx, ss, config.Webhook)
+	eventCenter.WatchEvent(eventTypeConfigFileChanged, s.webhooks.onConfigFileChanged)
+
 	// 初始化发布事件扫描器
-	if err := initReleaseMessageScanner(ctx, ss, s.fileCache, eventCenter, time.Second); err != nil {
+	if err := initReleaseMessageScanner(ctx, ss, s.fileCache, s.grayReleases, s.clientPins, eventCenter,
+		time.Second); err != nil {
 		log.ConfigScope().Error("[Config][Server] init release message scanner error. ", zap.Error(err))
 		return errors.New("init config module error")
 	}
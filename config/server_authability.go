@@ -93,6 +93,39 @@ func (s *serverAuthability) collectConfigFileTemplateAuthContext(ctx context.Con
 	)
 }
 
+func (s *serverAuthability) collectConfigFileSchemaAuthContext(ctx context.Context,
+	op model.ResourceOperation, methodName string) *model.AcquireContext {
+	return model.NewAcquireContext(
+		model.WithRequestContext(ctx),
+		model.WithToken(utils.ParseAuthToken(ctx)),
+		model.WithModule(model.ConfigModule),
+		model.WithOperation(op),
+		model.WithMethod(methodName),
+	)
+}
+
+func (s *serverAuthability) collectConfigFileWebhookAuthContext(ctx context.Context,
+	op model.ResourceOperation, methodName string) *model.AcquireContext {
+	return model.NewAcquireContext(
+		model.WithRequestContext(ctx),
+		model.WithToken(utils.ParseAuthToken(ctx)),
+		model.WithModule(model.ConfigModule),
+		model.WithOperation(op),
+		model.WithMethod(methodName),
+	)
+}
+
+func (s *serverAuthability) collectConfigFileVariableAuthContext(ctx context.Context,
+	op model.ResourceOperation, methodName string) *model.AcquireContext {
+	return model.NewAcquireContext(
+		model.WithRequestContext(ctx),
+		model.WithToken(utils.ParseAuthToken(ctx)),
+		model.WithModule(model.ConfigModule),
+		model.WithOperation(op),
+		model.WithMethod(methodName),
+	)
+}
+
 func (s *serverAuthability) queryConfigGroupResource(ctx context.Context,
 	req []*api.ConfigFileGroup) map[api.ResourceType][]model.ResourceEntry {
 
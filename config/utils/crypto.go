@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+// EncryptedContent 加密配置文件下发给客户端的内容，数据密钥使用客户端的公钥加密，客户端用私钥解密数据密钥后再解密配置内容
+type EncryptedContent struct {
+	Algorithm string `json:"algorithm"`
+	DataKey   string `json:"dataKey"`
+	Content   string `json:"content"`
+}
+
+// GenEncryptedContent 生成下发给客户端的加密配置内容
+func GenEncryptedContent(algorithm, dataKey, content string) (string, error) {
+	data, err := json.Marshal(&EncryptedContent{
+		Algorithm: algorithm,
+		DataKey:   dataKey,
+		Content:   content,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// EncryptByPublicKey 使用客户端的 RSA 公钥加密数据，公钥支持 PEM 格式以及 base64 编码的 DER 格式，加密方式为 RSA-OAEP(SHA-256)
+func EncryptByPublicKey(publicKey string, data []byte) (string, error) {
+	pub, err := parseRSAPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func parseRSAPublicKey(publicKey string) (*rsa.PublicKey, error) {
+	publicKey = strings.TrimSpace(publicKey)
+	var der []byte
+	if block, _ := pem.Decode([]byte(publicKey)); block != nil {
+		der = block.Bytes
+	} else {
+		data, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil {
+			return nil, errors.New("public key must be pem or base64 encoded der")
+		}
+		der = data
+	}
+
+	if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+		if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+			return rsaPub, nil
+		}
+		return nil, errors.New("public key is not rsa public key")
+	}
+	return x509.ParsePKCS1PublicKey(der)
+}
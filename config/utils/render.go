@@ -0,0 +1,465 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// maxRenderDepth include、extends 的最大嵌套层数
+	maxRenderDepth = 8
+
+	directiveInclude = "include:"
+	directiveExtends = "extends:"
+)
+
+var (
+	regVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]{0,127}$`)
+)
+
+// TemplateFile 参与渲染的配置文件
+type TemplateFile struct {
+	Name    string
+	Format  string
+	Content string
+}
+
+// TemplateFileLoader 加载同一个分组下被 include、extends 的配置文件，文件不存在时返回 nil
+type TemplateFileLoader func(name string) (*TemplateFile, error)
+
+// CheckVariableName 校验模板变量名，变量名可以包含 '.'、'-'，例如 db.host
+func CheckVariableName(name string) error {
+	if !regVariableName.MatchString(name) {
+		return errors.New("invalid variable name")
+	}
+	return nil
+}
+
+// CheckVariableValue 按照变量类型校验变量值
+func CheckVariableValue(varType, value string) error {
+	var err error
+	switch varType {
+	case utils.VariableTypeString:
+	case utils.VariableTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case utils.VariableTypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case utils.VariableTypeBool:
+		_, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("invalid variable type %s", varType)
+	}
+	if err != nil {
+		return fmt.Errorf("value %q is not a valid %s", value, varType)
+	}
+	return nil
+}
+
+// HasTemplateDirective 判断内容中是否包含 ${...} 形式的占位符或者指令
+func HasTemplateDirective(content string) bool {
+	return strings.Contains(content, "${")
+}
+
+// RenderTemplate 渲染配置文件。内容中支持以下语法：
+//   - ${name} 替换为变量的值，变量不存在时渲染失败
+//   - ${include:fileName} 替换为同一个分组下另一个配置文件渲染后的内容，指令所在行只有缩进时，
+//     被包含内容的每一行都会使用相同的缩进
+//   - ${extends:fileName} 必须是第一个非空行，以同一个分组下另一个格式相同的配置文件作为基础，
+//     当前文件的内容覆盖基础文件中相同的配置项，只支持 yaml、json、properties 格式
+//   - $${ 输出 ${ 本身
+func RenderTemplate(file *TemplateFile, variables map[string]string, loader TemplateFileLoader) (string, error) {
+	r := &templateRenderer{
+		variables: variables,
+		loader:    loader,
+	}
+	return r.render(file)
+}
+
+type templateRenderer struct {
+	variables map[string]string
+	loader    TemplateFileLoader
+	stack     []string
+}
+
+func (r *templateRenderer) render(file *TemplateFile) (string, error) {
+	for _, name := range r.stack {
+		if name == file.Name {
+			return "", fmt.Errorf("circular reference: %s -> %s", strings.Join(r.stack, " -> "), file.Name)
+		}
+	}
+	if len(r.stack) >= maxRenderDepth {
+		return "", fmt.Errorf("%s: reference depth exceeds %d", file.Name, maxRenderDepth)
+	}
+	r.stack = append(r.stack, file.Name)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	base, content, err := splitExtends(file)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := r.substitute(file, content)
+	if err != nil {
+		return "", err
+	}
+	if base == "" {
+		return rendered, nil
+	}
+
+	baseFile, err := r.load(file, base)
+	if err != nil {
+		return "", err
+	}
+	if baseFile.Format != file.Format {
+		return "", fmt.Errorf("%s: can not extend %s file %s", file.Name, baseFile.Format, base)
+	}
+	baseRendered, err := r.render(baseFile)
+	if err != nil {
+		return "", err
+	}
+	merged, err := mergeContent(file.Format, baseRendered, rendered)
+	if err != nil {
+		return "", fmt.Errorf("%s: extend %s: %w", file.Name, base, err)
+	}
+	return merged, nil
+}
+
+func (r *templateRenderer) load(file *TemplateFile, name string) (*TemplateFile, error) {
+	if r.loader == nil {
+		return nil, fmt.Errorf("%s: file %s not found", file.Name, name)
+	}
+	target, err := r.loader(name)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%s: file %s not found", file.Name, name)
+	}
+	return target, nil
+}
+
+// splitExtends 第一个非空行是 extends 指令时返回基础文件名以及剩余的内容
+func splitExtends(file *TemplateFile) (string, string, error) {
+	content := file.Content
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "${"+directiveExtends) {
+		return "", content, nil
+	}
+	line := trimmed
+	rest := ""
+	if idx := strings.IndexByte(trimmed, '\n'); idx >= 0 {
+		line, rest = trimmed[:idx], trimmed[idx+1:]
+	}
+	line = strings.TrimSpace(line)
+	if !strings.HasSuffix(line, "}") || strings.Count(line, "${") != 1 {
+		return "", "", fmt.Errorf("%s: extends directive must be on its own line", file.Name)
+	}
+	base := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "${"+directiveExtends), "}"))
+	if base == "" {
+		return "", "", fmt.Errorf("%s: extends directive requires a file name", file.Name)
+	}
+	return base, rest, nil
+}
+
+// substitute 替换变量以及 include 指令，所有未定义的变量会一起返回
+func (r *templateRenderer) substitute(file *TemplateFile, content string) (string, error) {
+	var (
+		buf     strings.Builder
+		missing []string
+		line    = 1
+	)
+	for i := 0; i < len(content); {
+		c := content[i]
+		if c == '\n' {
+			line++
+		}
+		if c != '$' || i+1 >= len(content) {
+			buf.WriteByte(c)
+			i++
+			continue
+		}
+		// $${ 转义为 ${
+		if strings.HasPrefix(content[i:], "$${") {
+			buf.WriteString("${")
+			i += 3
+			continue
+		}
+		if content[i+1] != '{' {
+			buf.WriteByte(c)
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(content[i+2:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%s: line %d: unclosed placeholder", file.Name, line)
+		}
+		expr := strings.TrimSpace(content[i+2 : i+2+end])
+		i += end + 3
+
+		switch {
+		case strings.HasPrefix(expr, directiveInclude):
+			name := strings.TrimSpace(strings.TrimPrefix(expr, directiveInclude))
+			included, err := r.include(file, name, currentIndent(buf.String()))
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(included)
+		case strings.HasPrefix(expr, directiveExtends):
+			return "", fmt.Errorf("%s: line %d: extends directive must be the first line", file.Name, line)
+		default:
+			if err := CheckVariableName(expr); err != nil {
+				return "", fmt.Errorf("%s: line %d: invalid placeholder ${%s}", file.Name, line, expr)
+			}
+			value, ok := r.variables[expr]
+			if !ok {
+				missing = append(missing, expr)
+				continue
+			}
+			buf.WriteString(value)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%s: undefined variables %s", file.Name, strings.Join(uniqueSorted(missing), ", "))
+	}
+	return buf.String(), nil
+}
+
+func (r *templateRenderer) include(file *TemplateFile, name, indent string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("%s: include directive requires a file name", file.Name)
+	}
+	target, err := r.load(file, name)
+	if err != nil {
+		return "", err
+	}
+	included, err := r.render(target)
+	if err != nil {
+		return "", err
+	}
+	included = strings.TrimRight(included, "\r\n")
+	if indent == "" {
+		return included, nil
+	}
+	return strings.ReplaceAll(included, "\n", "\n"+indent), nil
+}
+
+// currentIndent 已经输出的内容中，最后一行只包含空白字符时返回这些空白字符
+func currentIndent(rendered string) string {
+	last := rendered[strings.LastIndexByte(rendered, '\n')+1:]
+	if strings.TrimLeft(last, " \t") != "" {
+		return ""
+	}
+	return last
+}
+
+func uniqueSorted(items []string) []string {
+	sort.Strings(items)
+	ret := items[:0]
+	for i, item := range items {
+		if i == 0 || item != items[i-1] {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// mergeContent 使用 child 中的配置项覆盖 base 中相同的配置项，map 类型的配置项会递归合并
+func mergeContent(format, base, child string) (string, error) {
+	if strings.TrimSpace(child) == "" {
+		return base, nil
+	}
+	if strings.TrimSpace(base) == "" {
+		return child, nil
+	}
+	switch format {
+	case utils.FileFormatYaml:
+		return mergeYaml(base, child)
+	case utils.FileFormatJson:
+		return mergeJson(base, child)
+	case utils.FileFormatProperties:
+		return mergeProperties(base, child)
+	}
+	return "", fmt.Errorf("extends is not supported for %s format", format)
+}
+
+func mergeYaml(base, child string) (string, error) {
+	baseNode, err := parseYamlMapping(base)
+	if err != nil {
+		return "", err
+	}
+	childNode, err := parseYamlMapping(child)
+	if err != nil {
+		return "", err
+	}
+	mergeYamlMapping(baseNode.Content[0], childNode.Content[0])
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(baseNode); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parseYamlMapping(content string) (*yaml.Node, error) {
+	docs, err := parseYamlDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) != 1 {
+		return nil, errors.New("only single yaml document can be extended")
+	}
+	if len(docs[0].Content) == 0 || docs[0].Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("yaml document must be a mapping")
+	}
+	return docs[0], nil
+}
+
+func mergeYamlMapping(base, child *yaml.Node) {
+	for i := 0; i+1 < len(child.Content); i += 2 {
+		key, value := child.Content[i], child.Content[i+1]
+		replaced := false
+		for j := 0; j+1 < len(base.Content); j += 2 {
+			if base.Content[j].Value != key.Value {
+				continue
+			}
+			if base.Content[j+1].Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+				mergeYamlMapping(base.Content[j+1], value)
+			} else {
+				base.Content[j+1] = value
+			}
+			replaced = true
+			break
+		}
+		if !replaced {
+			base.Content = append(base.Content, key, value)
+		}
+	}
+}
+
+func mergeJson(base, child string) (string, error) {
+	baseObj, err := decodeJsonObject(base)
+	if err != nil {
+		return "", err
+	}
+	childObj, err := decodeJsonObject(child)
+	if err != nil {
+		return "", err
+	}
+	mergeJsonObject(baseObj, childObj)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(baseObj); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+func decodeJsonObject(content string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	obj := map[string]interface{}{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, errors.New("json content must be an object")
+	}
+	return obj, nil
+}
+
+func mergeJsonObject(base, child map[string]interface{}) {
+	for key, value := range child {
+		baseValue, ok := base[key].(map[string]interface{})
+		childValue, isObj := value.(map[string]interface{})
+		if ok && isObj {
+			mergeJsonObject(baseValue, childValue)
+			continue
+		}
+		base[key] = value
+	}
+}
+
+// mergeProperties 覆盖的配置项替换基础文件中最后一次出现的位置，新增的配置项连同前面的注释追加到末尾
+func mergeProperties(base, child string) (string, error) {
+	baseEntries, err := parseProperties(base)
+	if err != nil {
+		return "", err
+	}
+	childEntries, err := parseProperties(child)
+	if err != nil {
+		return "", err
+	}
+
+	baseLines := strings.Split(strings.ReplaceAll(base, "\r\n", "\n"), "\n")
+	childLines := strings.Split(strings.ReplaceAll(child, "\r\n", "\n"), "\n")
+	// 记录每个 key 在基础文件中最后一次出现的位置
+	positions := map[string]*propertyEntry{}
+	for _, entry := range baseEntries {
+		positions[entry.key] = entry
+	}
+
+	// key 为被覆盖配置项在基础文件中的起始行
+	replaced := map[int]*propertyEntry{}
+	replacement := map[int][]string{}
+	var appended []string
+	prev := 0
+	for _, entry := range childEntries {
+		if target, ok := positions[entry.key]; ok {
+			replaced[target.line] = target
+			replacement[target.line] = childLines[entry.line-1 : entry.endLine]
+		} else {
+			appended = append(appended, childLines[prev:entry.endLine]...)
+		}
+		prev = entry.endLine
+	}
+
+	var out []string
+	for i := 1; i <= len(baseLines); i++ {
+		target, ok := replaced[i]
+		if !ok {
+			out = append(out, baseLines[i-1])
+			continue
+		}
+		out = append(out, replacement[i]...)
+		i = target.endLine
+	}
+	result := strings.TrimRight(strings.Join(out, "\n"), "\n")
+	if len(appended) > 0 {
+		result += "\n" + strings.Trim(strings.Join(appended, "\n"), "\n")
+	}
+	return result, nil
+}
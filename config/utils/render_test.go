@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func newTestLoader(files ...*TemplateFile) TemplateFileLoader {
+	return func(name string) (*TemplateFile, error) {
+		for _, file := range files {
+			if file.Name == name {
+				return file, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	variables := map[string]string{
+		"db.host": "127.0.0.1",
+		"db.port": "3306",
+	}
+
+	t.Run("替换变量", func(t *testing.T) {
+		file := &TemplateFile{Name: "app.yaml", Format: utils.FileFormatYaml,
+			Content: "db:\n  url: ${db.host}:${ db.port }\n  spring: $${server.port}\n  price: $5\n"}
+		rendered, err := RenderTemplate(file, variables, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "db:\n  url: 127.0.0.1:3306\n  spring: ${server.port}\n  price: $5\n", rendered)
+
+		file.Content = "a: ${db.user}\nb: ${db.password}\nc: ${db.user}\n"
+		_, err = RenderTemplate(file, variables, nil)
+		assert.EqualError(t, err, "app.yaml: undefined variables db.password, db.user")
+
+		file.Content = "a: 1\nb: ${db.host"
+		_, err = RenderTemplate(file, variables, nil)
+		assert.EqualError(t, err, "app.yaml: line 2: unclosed placeholder")
+	})
+
+	t.Run("include", func(t *testing.T) {
+		common := &TemplateFile{Name: "db.yaml", Format: utils.FileFormatYaml,
+			Content: "host: ${db.host}\nport: ${db.port}\n"}
+		file := &TemplateFile{Name: "app.yaml", Format: utils.FileFormatYaml,
+			Content: "db:\n  ${include:db.yaml}\nname: app\n"}
+		rendered, err := RenderTemplate(file, variables, newTestLoader(common))
+		assert.NoError(t, err)
+		assert.Equal(t, "db:\n  host: 127.0.0.1\n  port: 3306\nname: app\n", rendered)
+
+		file.Content = "${include:not-exist.yaml}"
+		_, err = RenderTemplate(file, variables, newTestLoader(common))
+		assert.EqualError(t, err, "app.yaml: file not-exist.yaml not found")
+
+		a := &TemplateFile{Name: "a", Content: "${include:b}"}
+		b := &TemplateFile{Name: "b", Content: "${include:a}"}
+		_, err = RenderTemplate(a, variables, newTestLoader(a, b))
+		assert.EqualError(t, err, "circular reference: a -> b -> a")
+	})
+
+	t.Run("extends yaml", func(t *testing.T) {
+		base := &TemplateFile{Name: "base.yaml", Format: utils.FileFormatYaml,
+			Content: "# base\nserver:\n  port: 8080\n  timeout: 3s\ndb:\n  host: ${db.host}\n"}
+		file := &TemplateFile{Name: "app.yaml", Format: utils.FileFormatYaml,
+			Content: "\n${extends:base.yaml}\nserver:\n  port: 9090\nname: app\n"}
+		rendered, err := RenderTemplate(file, variables, newTestLoader(base))
+		assert.NoError(t, err)
+		assert.Equal(t, "# base\nserver:\n  port: 9090\n  timeout: 3s\ndb:\n  host: 127.0.0.1\nname: app\n", rendered)
+
+		file.Content = "a: 1\n${extends:base.yaml}\n"
+		_, err = RenderTemplate(file, variables, newTestLoader(base))
+		assert.EqualError(t, err, "app.yaml: line 2: extends directive must be the first line")
+
+		json := &TemplateFile{Name: "base.json", Format: utils.FileFormatJson, Content: "{}"}
+		file.Content = "${extends:base.json}\n"
+		_, err = RenderTemplate(file, variables, newTestLoader(json))
+		assert.EqualError(t, err, "app.yaml: can not extend json file base.json")
+	})
+
+	t.Run("extends json", func(t *testing.T) {
+		base := &TemplateFile{Name: "base.json", Format: utils.FileFormatJson,
+			Content: `{"server": {"port": 8080, "timeout": "3s"}, "url": "<${db.host}>"}`}
+		file := &TemplateFile{Name: "app.json", Format: utils.FileFormatJson,
+			Content: "${extends:base.json}\n{\"server\": {\"port\": 9090}}"}
+		rendered, err := RenderTemplate(file, variables, newTestLoader(base))
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"server\": {\n    \"port\": 9090,\n    \"timeout\": \"3s\"\n  },\n"+
+			"  \"url\": \"<127.0.0.1>\"\n}", rendered)
+	})
+
+	t.Run("extends properties", func(t *testing.T) {
+		base := &TemplateFile{Name: "base.properties", Format: utils.FileFormatProperties,
+			Content: "# base\nserver.port=8080\nserver.hosts=a,\\\n  b\ndb.host=${db.host}\n"}
+		file := &TemplateFile{Name: "app.properties", Format: utils.FileFormatProperties,
+			Content: "${extends:base.properties}\nserver.hosts=c\n# app name\nname=app\n"}
+		rendered, err := RenderTemplate(file, variables, newTestLoader(base))
+		assert.NoError(t, err)
+		assert.Equal(t, "# base\nserver.port=8080\nserver.hosts=c\ndb.host=127.0.0.1\n# app name\nname=app",
+			rendered)
+
+		text := &TemplateFile{Name: "base.txt", Format: utils.FileFormatText, Content: "a"}
+		file = &TemplateFile{Name: "app.txt", Format: utils.FileFormatText, Content: "${extends:base.txt}\nb"}
+		_, err = RenderTemplate(file, variables, newTestLoader(text))
+		assert.EqualError(t, err, "app.txt: extend base.txt: extends is not supported for text format")
+	})
+}
+
+func TestCheckVariableValue(t *testing.T) {
+	assert.NoError(t, CheckVariableValue(utils.VariableTypeString, "abc"))
+	assert.NoError(t, CheckVariableValue(utils.VariableTypeInt, "-10"))
+	assert.NoError(t, CheckVariableValue(utils.VariableTypeFloat, "1.5"))
+	assert.NoError(t, CheckVariableValue(utils.VariableTypeBool, "true"))
+	assert.EqualError(t, CheckVariableValue(utils.VariableTypeInt, "1.5"), `value "1.5" is not a valid int`)
+	assert.EqualError(t, CheckVariableValue("list", "a"), "invalid variable type list")
+	assert.Error(t, CheckVariableName("db host"))
+	assert.NoError(t, CheckVariableName("db.host-1"))
+}
@@ -17,6 +17,12 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
@@ -42,3 +48,22 @@ func TestCheckFileName(t *testing.T) {
 	err := CheckFileName(w)
 	assert.Equal(t, err, nil)
 }
+
+func TestEncryptByPublicKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.Nil(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	ciphertext, err := EncryptByPublicKey(publicKey, []byte("data-key"))
+	assert.Nil(t, err)
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	assert.Nil(t, err)
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, data, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "data-key", string(plaintext))
+
+	_, err = EncryptByPublicKey("invalid public key", []byte("data-key"))
+	assert.NotNil(t, err)
+}
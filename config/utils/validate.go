@@ -0,0 +1,589 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/santhosh-tekuri/jsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// schemaResourceURL 编译 JSON Schema 时使用的资源地址，不会访问远程资源
+	schemaResourceURL = "polaris://config-file-schema.json"
+)
+
+var (
+	regYamlErrLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+	regYamlTypeErr = regexp.MustCompile(`^line (\d+): (.*)$`)
+)
+
+// ContentError 配置文件内容校验错误，Line、Column 从 1 开始，为 0 时表示无法定位
+type ContentError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error 输出带行列号的错误信息
+func (e *ContentError) Error() string {
+	if e.Line <= 0 {
+		return e.Message
+	}
+	if e.Column <= 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// ContentErrors 配置文件内容不满足 JSON Schema 时的全部错误，按照出现的位置排序
+type ContentErrors []*ContentError
+
+// Error 输出全部错误信息
+func (e ContentErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, item := range e {
+		msgs = append(msgs, item.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// CheckContentFormat 按照配置文件格式校验内容的语法，text、html 格式以及空内容不做校验，
+// 校验失败时返回 *ContentError
+func CheckContentFormat(format, content string) error {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	switch format {
+	case utils.FileFormatYaml:
+		_, err := parseYamlDocuments(content)
+		return err
+	case utils.FileFormatJson:
+		return checkJsonContent(content)
+	case utils.FileFormatXml:
+		return checkXmlContent(content)
+	case utils.FileFormatProperties:
+		_, err := parseProperties(content)
+		return err
+	}
+	return nil
+}
+
+// IsSchemaSupportedFormat 只有 json、yaml、properties 格式的配置文件可以关联 JSON Schema
+func IsSchemaSupportedFormat(format string) bool {
+	return format == utils.FileFormatJson || format == utils.FileFormatYaml ||
+		format == utils.FileFormatProperties
+}
+
+// CompileJSONSchema 编译 JSON Schema，schema 中引用的远程资源不会被加载
+func CompileJSONSchema(schema string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResourceURL, strings.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaResourceURL)
+}
+
+// CheckContentSchema 校验配置文件内容是否满足 JSON Schema，语法错误时返回 *ContentError，
+// 不满足 Schema 时返回 ContentErrors。properties 文件按照 key 平铺成一个对象，value 均为字符串
+func CheckContentSchema(format, content, schema string) error {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	if !IsSchemaSupportedFormat(format) {
+		return fmt.Errorf("json schema is not supported for format %s", format)
+	}
+	compiled, err := CompileJSONSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	var docs []*contentDocument
+	switch format {
+	case utils.FileFormatYaml:
+		nodes, err := parseYamlDocuments(content)
+		if err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			docs = append(docs, newYamlDocument(node))
+		}
+	case utils.FileFormatJson:
+		doc, err := newJsonDocument(content)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	case utils.FileFormatProperties:
+		entries, err := parseProperties(content)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, newPropertiesDocument(entries))
+	}
+
+	var errs ContentErrors
+	for _, doc := range docs {
+		err := compiled.ValidateInterface(doc.value)
+		if err == nil {
+			continue
+		}
+		verr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return err
+		}
+		for _, leaf := range leafValidationErrors(verr) {
+			line, column := doc.position(leaf.InstancePtr)
+			errs = append(errs, &ContentError{
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("%s %s", displayInstancePtr(leaf.InstancePtr), leaf.Message),
+			})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+// contentDocument 转换成 JSON 数据结构的配置内容，以及每个 JSON Pointer 在原文中的位置
+type contentDocument struct {
+	value     interface{}
+	positions map[string][2]int
+}
+
+// position 查找 JSON Pointer 对应的行列号，找不到时使用最近的父节点的位置
+func (d *contentDocument) position(ptr string) (int, int) {
+	for {
+		if pos, ok := d.positions[ptr]; ok {
+			return pos[0], pos[1]
+		}
+		index := strings.LastIndex(ptr, "/")
+		if index < 0 {
+			return 0, 0
+		}
+		ptr = ptr[:index]
+	}
+}
+
+func newYamlDocument(node *yaml.Node) *contentDocument {
+	doc := &contentDocument{positions: map[string][2]int{}}
+	doc.value = doc.convertYamlNode("#", node)
+	return doc
+}
+
+// convertYamlNode 把 yaml 节点转换成 jsonschema 支持的数据类型，同时记录节点位置
+func (d *contentDocument) convertYamlNode(ptr string, node *yaml.Node) interface{} {
+	d.positions[ptr] = [2]int{node.Line, node.Column}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return d.convertYamlNode(ptr, node.Content[0])
+	case yaml.AliasNode:
+		return d.convertYamlNode(ptr, node.Alias)
+	case yaml.MappingNode:
+		ret := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			ret[key] = d.convertYamlNode(ptr+"/"+escapePtrToken(key), node.Content[i+1])
+			// 属性缺失、多余等错误定位到 key 上更直观
+			d.positions[ptr+"/"+escapePtrToken(key)] = [2]int{node.Content[i].Line, node.Content[i].Column}
+		}
+		return ret
+	case yaml.SequenceNode:
+		ret := make([]interface{}, 0, len(node.Content))
+		for i, item := range node.Content {
+			ret = append(ret, d.convertYamlNode(ptr+"/"+strconv.Itoa(i), item))
+		}
+		return ret
+	}
+
+	var val interface{}
+	if err := node.Decode(&val); err != nil {
+		return node.Value
+	}
+	switch v := val.(type) {
+	case nil, bool, string, int, int64, float64:
+		return v
+	case uint64:
+		return json.Number(strconv.FormatUint(v, 10))
+	default:
+		return node.Value
+	}
+}
+
+// newJsonDocument json 是 yaml 的子集，优先按照 yaml 解析以获取位置信息，解析失败时退化为不带位置信息的校验
+func newJsonDocument(content string) (*contentDocument, error) {
+	if err := checkJsonContent(content); err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &node); err == nil {
+		return newYamlDocument(&node), nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	doc := &contentDocument{positions: map[string][2]int{}}
+	if err := decoder.Decode(&doc.value); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func newPropertiesDocument(entries []*propertyEntry) *contentDocument {
+	value := make(map[string]interface{}, len(entries))
+	doc := &contentDocument{value: value, positions: map[string][2]int{"#": {1, 1}}}
+	for _, entry := range entries {
+		value[entry.key] = entry.value
+		doc.positions["#/"+escapePtrToken(entry.key)] = [2]int{entry.line, entry.column}
+	}
+	return doc
+}
+
+// leafValidationErrors 只保留最底层的校验错误，上层错误只是对下层错误的汇总
+func leafValidationErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var ret []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		ret = append(ret, leafValidationErrors(cause)...)
+	}
+	return ret
+}
+
+// escapePtrToken 与 jsonschema 生成 InstancePtr 时的转义规则保持一致
+func escapePtrToken(token string) string {
+	token = strings.Replace(token, "~", "~0", -1)
+	token = strings.Replace(token, "/", "~1", -1)
+	return url.PathEscape(token)
+}
+
+func displayInstancePtr(ptr string) string {
+	ptr = strings.TrimPrefix(ptr, "#")
+	if ptr == "" {
+		return "/"
+	}
+	if unescaped, err := url.PathUnescape(ptr); err == nil {
+		return unescaped
+	}
+	return ptr
+}
+
+// parseYamlDocuments 解析 yaml 的全部文档，语法错误以及重复的 key 都会返回 *ContentError
+func parseYamlDocuments(content string) ([]*yaml.Node, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	var nodes []*yaml.Node
+	for {
+		node := &yaml.Node{}
+		err := decoder.Decode(node)
+		if err == io.EOF {
+			return nodes, nil
+		}
+		if err != nil {
+			return nil, yamlContentError(err)
+		}
+		var val interface{}
+		if err := node.Decode(&val); err != nil {
+			return nil, yamlContentError(err)
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+func yamlContentError(err error) *ContentError {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		// TypeError 中的行号从 1 开始
+		if match := regYamlTypeErr.FindStringSubmatch(typeErr.Errors[0]); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			return &ContentError{Line: line, Message: match[2]}
+		}
+		return &ContentError{Message: typeErr.Errors[0]}
+	}
+
+	msg := err.Error()
+	match := regYamlErrLine.FindStringSubmatch(msg)
+	if match == nil {
+		// 解析器在第一行出错时不会输出行号
+		return &ContentError{Line: 1, Message: strings.TrimPrefix(msg, "yaml: ")}
+	}
+	// 语法错误中的行号从 0 开始，只有 unexpected end of stream 会被修正为从 1 开始
+	line, _ := strconv.Atoi(match[1])
+	if !strings.Contains(match[2], "unexpected end of stream") {
+		line++
+	}
+	return &ContentError{Line: line, Message: match[2]}
+}
+
+func checkJsonContent(content string) error {
+	var val interface{}
+	err := json.Unmarshal([]byte(content), &val)
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := offsetToPosition(content, int(syntaxErr.Offset)-1)
+		return &ContentError{Line: line, Column: column, Message: syntaxErr.Error()}
+	}
+	return &ContentError{Message: err.Error()}
+}
+
+func checkXmlContent(content string) error {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	depth, roots := 0, 0
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line, column := offsetToPosition(content, int(decoder.InputOffset())-1)
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				if syntaxErr.Line != line {
+					line, column = syntaxErr.Line, 0
+				}
+				return &ContentError{Line: line, Column: column, Message: syntaxErr.Msg}
+			}
+			return &ContentError{Line: line, Column: column, Message: err.Error()}
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+				if roots > 1 {
+					line, column := offsetToPosition(content, offset)
+					return &ContentError{Line: line, Column: column, Message: "multiple root elements"}
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				line, column := offsetToPosition(content, offset)
+				return &ContentError{Line: line, Column: column,
+					Message: "content is not allowed outside of root element"}
+			}
+		}
+	}
+	if roots == 0 {
+		return &ContentError{Line: 1, Column: 1, Message: "root element is missing"}
+	}
+	return nil
+}
+
+// offsetToPosition 把字节偏移量转换成行列号，列号按照字符计算
+func offsetToPosition(content string, offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(content) {
+		offset = len(content)
+	}
+	prefix := content[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	lineStart := strings.LastIndex(prefix, "\n") + 1
+	return line, utf8.RuneCountInString(prefix[lineStart:]) + 1
+}
+
+// propertyEntry properties 文件中的一个配置项，line、column 为 key 在原文中的位置，endLine 为续行的最后一行
+type propertyEntry struct {
+	key     string
+	value   string
+	line    int
+	column  int
+	endLine int
+}
+
+// parseProperties 按照 java.util.Properties 的规则解析 properties 文件，
+// 支持注释、行尾反斜杠续行以及 \uXXXX 转义，转义不合法时返回 *ContentError
+func parseProperties(content string) ([]*propertyEntry, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var entries []*propertyEntry
+	for i := 0; i < len(lines); i++ {
+		text := strings.TrimLeft(lines[i], " \t\f")
+		if text == "" || text[0] == '#' || text[0] == '!' {
+			continue
+		}
+		line := i + 1
+		column := utf8.RuneCountInString(lines[i]) - utf8.RuneCountInString(text) + 1
+
+		// 记录逻辑行中每一段的起始位置，用于定位转义错误
+		segments := []propertySegment{{text: text, line: line, column: column}}
+		for endsWithContinuation(text) && i+1 < len(lines) {
+			i++
+			next := strings.TrimLeft(lines[i], " \t\f")
+			segments[len(segments)-1].text = strings.TrimSuffix(segments[len(segments)-1].text, "\\")
+			segments = append(segments, propertySegment{text: next, line: i + 1,
+				column: utf8.RuneCountInString(lines[i]) - utf8.RuneCountInString(next) + 1})
+			text = next
+		}
+
+		key, value, err := splitProperty(segments)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &propertyEntry{key: key, value: value, line: line, column: column,
+			endLine: i + 1})
+	}
+	return entries, nil
+}
+
+type propertySegment struct {
+	text   string
+	line   int
+	column int
+}
+
+// endsWithContinuation 行尾有奇数个反斜杠时表示续行
+func endsWithContinuation(text string) bool {
+	count := 0
+	for i := len(text) - 1; i >= 0 && text[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+type propertyRune struct {
+	r      rune
+	line   int
+	column int
+}
+
+// splitProperty 拆分 key 与 value，key 以第一个未转义的 '='、':' 或空白字符结束，
+// 之后的空白以及至多一个 '='、':' 分隔符会被忽略
+func splitProperty(segments []propertySegment) (string, string, error) {
+	var runes []propertyRune
+	for _, segment := range segments {
+		column := segment.column
+		for _, r := range segment.text {
+			runes = append(runes, propertyRune{r: r, line: segment.line, column: column})
+			column++
+		}
+	}
+
+	keyEnd := 0
+	for ; keyEnd < len(runes); keyEnd++ {
+		r := runes[keyEnd].r
+		if r == '\\' {
+			keyEnd++
+			continue
+		}
+		if r == '=' || r == ':' || isPropertySpace(r) {
+			break
+		}
+	}
+	if keyEnd > len(runes) {
+		keyEnd = len(runes)
+	}
+
+	valueStart := keyEnd
+	for valueStart < len(runes) && isPropertySpace(runes[valueStart].r) {
+		valueStart++
+	}
+	if valueStart < len(runes) && (runes[valueStart].r == '=' || runes[valueStart].r == ':') {
+		valueStart++
+		for valueStart < len(runes) && isPropertySpace(runes[valueStart].r) {
+			valueStart++
+		}
+	}
+
+	key, err := unescapeProperty(runes[:keyEnd])
+	if err != nil {
+		return "", "", err
+	}
+	value, err := unescapeProperty(runes[valueStart:])
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+func isPropertySpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\f'
+}
+
+func unescapeProperty(runes []propertyRune) (string, error) {
+	var ret strings.Builder
+	for i := 0; i < len(runes); i++ {
+		if runes[i].r != '\\' {
+			ret.WriteRune(runes[i].r)
+			continue
+		}
+		if i+1 >= len(runes) {
+			break
+		}
+		i++
+		switch next := runes[i].r; next {
+		case 'u':
+			if i+4 >= len(runes) {
+				return "", &ContentError{Line: runes[i-1].line, Column: runes[i-1].column,
+					Message: "malformed \\uxxxx encoding"}
+			}
+			hex := make([]rune, 0, 4)
+			for _, item := range runes[i+1 : i+5] {
+				hex = append(hex, item.r)
+			}
+			code, err := strconv.ParseUint(string(hex), 16, 32)
+			if err != nil {
+				return "", &ContentError{Line: runes[i-1].line, Column: runes[i-1].column,
+					Message: "malformed \\uxxxx encoding"}
+			}
+			ret.WriteRune(rune(code))
+			i += 4
+		case 't':
+			ret.WriteRune('\t')
+		case 'n':
+			ret.WriteRune('\n')
+		case 'r':
+			ret.WriteRune('\r')
+		case 'f':
+			ret.WriteRune('\f')
+		default:
+			ret.WriteRune(next)
+		}
+	}
+	return ret.String(), nil
+}
@@ -0,0 +1,135 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestCheckContentFormat(t *testing.T) {
+	t.Run("合法的内容", func(t *testing.T) {
+		assert.NoError(t, CheckContentFormat(utils.FileFormatYaml, "a: 1\nb:\n  - x\n---\nc: 2\n"))
+		assert.NoError(t, CheckContentFormat(utils.FileFormatJson, "{\n\t\"a\": [1, 2]\n}"))
+		assert.NoError(t, CheckContentFormat(utils.FileFormatXml, "<?xml version=\"1.0\"?>\n<a><b>1</b></a>\n"))
+		assert.NoError(t, CheckContentFormat(utils.FileFormatProperties, "# comment\na=1\nb : \\u4e2d\\\n  2\n"))
+		assert.NoError(t, CheckContentFormat(utils.FileFormatText, "{"))
+		assert.NoError(t, CheckContentFormat(utils.FileFormatJson, "  \n"))
+	})
+
+	t.Run("yaml语法错误", func(t *testing.T) {
+		err := CheckContentFormat(utils.FileFormatYaml, "a: 1\nb:\n  - 1\n\tc: 2\n")
+		assert.Equal(t, 4, err.(*ContentError).Line)
+
+		err = CheckContentFormat(utils.FileFormatYaml, "a: 1\nb: 2\na: 3\n")
+		assert.Equal(t, 3, err.(*ContentError).Line)
+		assert.Contains(t, err.Error(), "already defined")
+	})
+
+	t.Run("json语法错误", func(t *testing.T) {
+		err := CheckContentFormat(utils.FileFormatJson, "{\n  \"a\": 1,\n  \"b\" 2\n}")
+		cErr := err.(*ContentError)
+		assert.Equal(t, 3, cErr.Line)
+		assert.Equal(t, 7, cErr.Column)
+		assert.Equal(t, "line 3, column 7: invalid character '2' after object key", err.Error())
+	})
+
+	t.Run("xml语法错误", func(t *testing.T) {
+		err := CheckContentFormat(utils.FileFormatXml, "<a>\n  <b>1</c>\n</a>")
+		assert.Equal(t, 2, err.(*ContentError).Line)
+
+		err = CheckContentFormat(utils.FileFormatXml, "<a/>\n<b/>")
+		assert.Equal(t, "line 2, column 1: multiple root elements", err.Error())
+
+		err = CheckContentFormat(utils.FileFormatXml, "plain text")
+		assert.Error(t, err)
+	})
+
+	t.Run("properties转义错误", func(t *testing.T) {
+		err := CheckContentFormat(utils.FileFormatProperties, "a=1\n  b=\\u12g4\n")
+		assert.Equal(t, "line 2, column 5: malformed \\uxxxx encoding", err.Error())
+	})
+}
+
+func TestParseProperties(t *testing.T) {
+	entries, err := parseProperties("a=1\n! comment\nb:2\nc 3\nd = multi \\\n    line\ne\\=f = \\u0041\n")
+	assert.NoError(t, err)
+	ret := map[string]string{}
+	for _, entry := range entries {
+		ret[entry.key] = entry.value
+	}
+	assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": "3", "d": "multi line", "e=f": "A"}, ret)
+	assert.Equal(t, 5, entries[3].line)
+}
+
+func TestCheckContentSchema(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "required": ["port"],
+  "properties": {
+    "port": {"type": "integer", "maximum": 65535},
+    "hosts": {"type": "array", "items": {"type": "string"}}
+  }
+}`
+
+	t.Run("满足schema", func(t *testing.T) {
+		assert.NoError(t, CheckContentSchema(utils.FileFormatYaml, "port: 8080\nhosts:\n  - a\n", schema))
+		assert.NoError(t, CheckContentSchema(utils.FileFormatJson, `{"port": 8080}`, schema))
+	})
+
+	t.Run("yaml不满足schema", func(t *testing.T) {
+		err := CheckContentSchema(utils.FileFormatYaml, "port: 8080\nhosts:\n  - a\n  - 1\n", schema)
+		errs, ok := err.(ContentErrors)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(errs))
+		assert.Equal(t, 4, errs[0].Line)
+		assert.Equal(t, 5, errs[0].Column)
+		assert.Contains(t, errs[0].Message, "/hosts/1")
+
+		// 多文档的 yaml 每个文档单独校验
+		err = CheckContentSchema(utils.FileFormatYaml, "port: 70000\n---\nport: 1\nhosts: [1]\n", schema)
+		errs = err.(ContentErrors)
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, 1, errs[0].Line)
+		assert.Equal(t, 4, errs[1].Line)
+	})
+
+	t.Run("json不满足schema", func(t *testing.T) {
+		err := CheckContentSchema(utils.FileFormatJson, "{\n  \"hosts\": []\n}", schema)
+		errs := err.(ContentErrors)
+		assert.Equal(t, 1, errs[0].Line)
+		assert.Contains(t, errs[0].Message, "port")
+	})
+
+	t.Run("properties不满足schema", func(t *testing.T) {
+		err := CheckContentSchema(utils.FileFormatProperties, "a=1\nport=80\n", schema)
+		errs := err.(ContentErrors)
+		assert.Equal(t, 2, errs[0].Line)
+		assert.Contains(t, errs[0].Message, "/port")
+	})
+
+	t.Run("不支持的格式以及非法的schema", func(t *testing.T) {
+		assert.Error(t, CheckContentSchema(utils.FileFormatXml, "<a/>", schema))
+		assert.Error(t, CheckContentSchema(utils.FileFormatJson, `{}`, `{"type": 1}`))
+		_, err := CompileJSONSchema("{")
+		assert.Error(t, err)
+	})
+}
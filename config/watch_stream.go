@@ -0,0 +1,243 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// WatchStreamSender 通过长连接向客户端推送配置变更，同一个连接上不会并发调用
+type WatchStreamSender func(rsp *api.ConfigWatchStreamResponse) error
+
+// watchStreamChecker 检查客户端订阅的配置文件是否落后，返回需要通知客户端的配置文件
+type watchStreamChecker func(file *api.ClientConfigFileInfo) *api.ClientConfigFileInfo
+
+// WatchStream 客户端订阅配置的长连接，一个连接上可以增量订阅、取消订阅多个配置文件。
+// 配置文件发布后不会立即推送，合并间隔内发生变更的配置文件合并为一次推送
+type WatchStream struct {
+	clientId      string
+	client        clientIdentity
+	manager       *connManager
+	sender        WatchStreamSender
+	checker       watchStreamChecker
+	batchInterval time.Duration
+
+	lock sync.Mutex
+	// files fileId -> 客户端订阅的配置文件，版本号为最后一次通知客户端的版本号
+	files map[string]*api.ClientConfigFileInfo
+	// pending fileId -> 等待推送的配置文件
+	pending map[string]*api.ClientConfigFileInfo
+	timer   *time.Timer
+	closed  bool
+
+	sendLock sync.Mutex
+}
+
+// OpenWatchStream 新建一个订阅配置的长连接
+func (c *connManager) OpenWatchStream(clientId string, client clientIdentity, sender WatchStreamSender,
+	checker watchStreamChecker) *WatchStream {
+
+	stream := &WatchStream{
+		clientId:      clientId,
+		client:        client,
+		manager:       c,
+		sender:        sender,
+		checker:       checker,
+		batchInterval: c.conf.PushBatchInterval,
+		files:         map[string]*api.ClientConfigFileInfo{},
+		pending:       map[string]*api.ClientConfigFileInfo{},
+	}
+	c.streams.Store(clientId, stream)
+	atomic.AddInt64(&c.streamCount, 1)
+	return stream
+}
+
+// Add 新增订阅的配置文件，客户端的版本落后时立即通知客户端
+func (ws *WatchStream) Add(files []*api.ClientConfigFileInfo) uint32 {
+	if len(files) == 0 {
+		return api.InvalidWatchConfigFileFormat
+	}
+	for _, file := range files {
+		if file.GetNamespace().GetValue() == "" || file.GetGroup().GetValue() == "" ||
+			file.GetFileName().GetValue() == "" {
+			return api.InvalidWatchConfigFileFormat
+		}
+	}
+
+	ws.lock.Lock()
+	if ws.closed {
+		ws.lock.Unlock()
+		return api.ExecuteSuccess
+	}
+	for _, file := range files {
+		ws.files[watchFileIdOf(file)] = &api.ClientConfigFileInfo{
+			Namespace: file.Namespace,
+			Group:     file.Group,
+			FileName:  file.FileName,
+			Version:   file.Version,
+		}
+	}
+	ws.lock.Unlock()
+
+	ws.manager.watchCenter.AddWatcher(ws.clientId, ws.client, files, ws.onFileReleased)
+
+	for _, file := range files {
+		if changed := ws.checker(file); changed != nil {
+			ws.push(changed)
+		}
+	}
+	return api.ExecuteSuccess
+}
+
+// Remove 取消订阅配置文件
+func (ws *WatchStream) Remove(files []*api.ClientConfigFileInfo) {
+	ws.lock.Lock()
+	for _, file := range files {
+		fileId := watchFileIdOf(file)
+		delete(ws.files, fileId)
+		delete(ws.pending, fileId)
+	}
+	ws.lock.Unlock()
+
+	ws.manager.watchCenter.RemoveWatcher(ws.clientId, files)
+}
+
+// Close 关闭长连接，取消全部订阅
+func (ws *WatchStream) Close() {
+	ws.lock.Lock()
+	if ws.closed {
+		ws.lock.Unlock()
+		return
+	}
+	ws.closed = true
+	if ws.timer != nil {
+		ws.timer.Stop()
+	}
+	files := make([]*api.ClientConfigFileInfo, 0, len(ws.files))
+	for _, file := range ws.files {
+		files = append(files, file)
+	}
+	ws.files = nil
+	ws.pending = nil
+	ws.lock.Unlock()
+
+	ws.manager.watchCenter.RemoveWatcher(ws.clientId, files)
+	ws.manager.streams.Delete(ws.clientId)
+	atomic.AddInt64(&ws.manager.streamCount, -1)
+}
+
+// onFileReleased 在 watchCenter 的通知协程中执行，只记录变更不做推送，避免阻塞其他客户端的通知
+func (ws *WatchStream) onFileReleased(_ string, rsp *api.ConfigClientResponse) bool {
+	ws.push(rsp.GetConfigFile())
+	return true
+}
+
+func (ws *WatchStream) push(file *api.ClientConfigFileInfo) {
+	if file == nil {
+		return
+	}
+	fileId := watchFileIdOf(file)
+
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	if ws.closed {
+		return
+	}
+	// 版本固定变化时推送的版本号可能与客户端的版本号相同，此时根据 md5 判断是否需要推送
+	watched, ok := ws.files[fileId]
+	if !ok || watched.GetVersion().GetValue() > file.GetVersion().GetValue() ||
+		(watched.GetVersion().GetValue() == file.GetVersion().GetValue() &&
+			watched.GetMd5().GetValue() == file.GetMd5().GetValue()) {
+		return
+	}
+	watched.Version = file.Version
+	watched.Md5 = file.Md5
+	ws.pending[fileId] = file
+	if ws.timer == nil {
+		ws.timer = time.AfterFunc(ws.batchInterval, ws.flush)
+	}
+}
+
+// flush 推送合并间隔内发生变更的配置文件
+func (ws *WatchStream) flush() {
+	ws.lock.Lock()
+	if ws.closed || len(ws.pending) == 0 {
+		ws.timer = nil
+		ws.lock.Unlock()
+		return
+	}
+	files := make([]*api.ClientConfigFileInfo, 0, len(ws.pending))
+	for _, file := range ws.pending {
+		files = append(files, file)
+	}
+	ws.pending = map[string]*api.ClientConfigFileInfo{}
+	ws.timer = nil
+	ws.lock.Unlock()
+
+	sort.Slice(files, func(i, j int) bool {
+		return watchFileIdOf(files[i]) < watchFileIdOf(files[j])
+	})
+
+	ws.sendLock.Lock()
+	defer ws.sendLock.Unlock()
+	if err := ws.sender(api.NewConfigWatchStreamResponse(api.ExecuteSuccess, files)); err != nil {
+		log.ConfigScope().Error("[Config][Watcher] push config files to stream error.",
+			zap.String("clientId", ws.clientId), zap.Int("files", len(files)), zap.Error(err))
+	}
+}
+
+// Send 在长连接上发送响应，和变更推送共用发送锁
+func (ws *WatchStream) Send(rsp *api.ConfigWatchStreamResponse) error {
+	ws.sendLock.Lock()
+	defer ws.sendLock.Unlock()
+	return ws.sender(rsp)
+}
+
+func (ws *WatchStream) watchFileCount() int {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	return len(ws.files)
+}
+
+func watchFileIdOf(file *api.ClientConfigFileInfo) string {
+	return utils.GenFileId(file.GetNamespace().GetValue(), file.GetGroup().GetValue(), file.GetFileName().GetValue())
+}
+
+// OpenWatchStream 客户端建立订阅配置的长连接，配置变更通过 sender 推送给客户端
+func (s *Server) OpenWatchStream(ctx context.Context, sender WatchStreamSender) (*WatchStream, error) {
+	clientId := utils.ParseClientAddress(ctx) + "@" + utils.NewUUID()[0:8]
+	checker := func(file *api.ClientConfigFileInfo) *api.ClientConfigFileInfo {
+		rsp := s.doCheckClientConfigFile(ctx, []*api.ClientConfigFileInfo{file}, compareByVersion)
+		if rsp.GetCode().GetValue() != api.ExecuteSuccess {
+			return nil
+		}
+		return rsp.GetConfigFile()
+	}
+	return s.ConnManager().OpenWatchStream(clientId, s.parseClient(ctx), sender, checker), nil
+}
@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// TestWatchConfigFilesStream 测试通过长连接增量订阅配置文件，配置发布后合并推送
+func TestWatchConfigFilesStream(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	received := make(chan *api.ConfigWatchStreamResponse, 10)
+	stream, err := testSuit.testService.OpenWatchStream(testSuit.defaultCtx,
+		func(rsp *api.ConfigWatchStreamResponse) error {
+			received <- rsp
+			return nil
+		})
+	assert.NoError(t, err)
+
+	waitPush := func() *api.ConfigWatchStreamResponse {
+		select {
+		case rsp := <-received:
+			return rsp
+		case <-time.After(5 * time.Second):
+			t.Fatal("wait config watch stream push timeout")
+			return nil
+		}
+	}
+
+	t.Run("订阅时客户端版本落后立即推送", func(t *testing.T) {
+		code := stream.Add([]*api.ClientConfigFileInfo{{Namespace: utils.NewStringValue(testNamespace)}})
+		assert.Equal(t, api.InvalidWatchConfigFileFormat, code)
+
+		code = stream.Add(assembleDefaultClientConfigFile(0))
+		assert.Equal(t, api.ExecuteSuccess, code)
+
+		pushed := waitPush()
+		assert.Equal(t, api.ExecuteSuccess, pushed.GetCode().GetValue())
+		assert.Equal(t, 1, len(pushed.GetConfigFiles()))
+		assert.Equal(t, uint64(1), pushed.GetConfigFiles()[0].GetVersion().GetValue())
+	})
+
+	t.Run("配置发布后推送变更", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		pushed := waitPush()
+		assert.Equal(t, 1, len(pushed.GetConfigFiles()))
+		assert.Equal(t, uint64(2), pushed.GetConfigFiles()[0].GetVersion().GetValue())
+	})
+
+	t.Run("合并间隔内的变更合并推送", func(t *testing.T) {
+		otherFile := assembleDefaultClientConfigFile(0)[0]
+		otherFile.FileName = utils.NewStringValue("other.txt")
+		assert.Equal(t, api.ExecuteSuccess, stream.Add([]*api.ClientConfigFileInfo{otherFile}))
+
+		stats := testSuit.testServer.ConnManager().Stats()
+		assert.Equal(t, int64(1), stats.StreamConns)
+		assert.Equal(t, int64(2), stats.StreamWatchFiles)
+
+		for _, file := range []*api.ClientConfigFileInfo{otherFile, assembleDefaultClientConfigFile(0)[0]} {
+			stream.onFileReleased("", api.NewConfigClientResponse(api.ExecuteSuccess, &api.ClientConfigFileInfo{
+				Namespace: file.Namespace,
+				Group:     file.Group,
+				FileName:  file.FileName,
+				Version:   utils.NewUInt64Value(3),
+			}))
+		}
+		// 重复的通知不会再次推送
+		stream.onFileReleased("", api.NewConfigClientResponse(api.ExecuteSuccess, &api.ClientConfigFileInfo{
+			Namespace: otherFile.Namespace,
+			Group:     otherFile.Group,
+			FileName:  otherFile.FileName,
+			Version:   utils.NewUInt64Value(3),
+		}))
+
+		pushed := waitPush()
+		assert.Equal(t, 2, len(pushed.GetConfigFiles()))
+		assert.Equal(t, "other.txt", pushed.GetConfigFiles()[0].GetFileName().GetValue())
+		assert.Equal(t, testFile, pushed.GetConfigFiles()[1].GetFileName().GetValue())
+	})
+
+	t.Run("取消订阅后不再推送", func(t *testing.T) {
+		stream.Remove(assembleDefaultClientConfigFile(0))
+		assert.Equal(t, int64(1), testSuit.testServer.ConnManager().Stats().StreamWatchFiles)
+
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		select {
+		case pushed := <-received:
+			t.Fatalf("unexpected push after remove: %v", pushed)
+		case <-time.After(2 * time.Second):
+		}
+
+		stream.Close()
+		assert.Equal(t, int64(0), testSuit.testServer.ConnManager().Stats().StreamConns)
+	})
+}
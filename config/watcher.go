@@ -38,14 +38,23 @@ type FileReleaseCallback func(clientId string, rsp *api.ConfigClientResponse) bo
 type watchContext struct {
 	fileReleaseCb FileReleaseCallback
 	ClientVersion uint64
+	Client        clientIdentity
 }
 
+// GrayMatcher 判断客户端是否命中灰度发布的灰度规则
+type GrayMatcher func(client clientIdentity, gray *grayRelease) bool
+
+// PinMatcher 判断客户端是否被固定了版本，被固定的客户端不再接收发布通知
+type PinMatcher func(namespace, group, fileName string, client clientIdentity) bool
+
 // watchCenter 处理客户端订阅配置请求，监听配置文件发布事件通知客户端
 type watchCenter struct {
 	eventCenter         *Center
 	configFileWatchers  *sync.Map // fileId -> clientId -> watchContext
 	lock                *sync.Mutex
-	releaseMessageQueue chan *model.ConfigFileRelease
+	releaseMessageQueue chan interface{}
+	grayMatcher         GrayMatcher
+	pinMatcher          PinMatcher
 }
 
 // NewWatchCenter 创建一个客户端监听配置发布的处理中心
@@ -54,21 +63,45 @@ func NewWatchCenter(eventCenter *Center) *watchCenter {
 		eventCenter:         eventCenter,
 		configFileWatchers:  new(sync.Map),
 		lock:                new(sync.Mutex),
-		releaseMessageQueue: make(chan *model.ConfigFileRelease, QueueSize),
+		releaseMessageQueue: make(chan interface{}, QueueSize),
+		grayMatcher: func(client clientIdentity, gray *grayRelease) bool {
+			return false
+		},
+		pinMatcher: func(namespace, group, fileName string, client clientIdentity) bool {
+			return false
+		},
 	}
 
 	eventCenter.WatchEvent(eventTypePublishConfigFile, func(event Event) bool {
 		wc.releaseMessageQueue <- event.Message.(*model.ConfigFileRelease)
 		return true
 	})
+	eventCenter.WatchEvent(eventTypeGrayPublishConfigFile, func(event Event) bool {
+		wc.releaseMessageQueue <- event.Message.(*grayRelease)
+		return true
+	})
+	eventCenter.WatchEvent(eventTypeClientPinChanged, func(event Event) bool {
+		wc.releaseMessageQueue <- event.Message.(*clientPinEvent)
+		return true
+	})
 
 	wc.handleMessage()
 
 	return wc
 }
 
+// SetGrayMatcher 设置灰度规则匹配器，用于灰度发布时筛选需要通知的客户端
+func (wc *watchCenter) SetGrayMatcher(matcher GrayMatcher) {
+	wc.grayMatcher = matcher
+}
+
+// SetPinMatcher 设置客户端版本固定匹配器，用于发布时跳过被固定的客户端
+func (wc *watchCenter) SetPinMatcher(matcher PinMatcher) {
+	wc.pinMatcher = matcher
+}
+
 // AddWatcher 新增订阅者
-func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*api.ClientConfigFileInfo,
+func (wc *watchCenter) AddWatcher(clientId string, client clientIdentity, watchConfigFiles []*api.ClientConfigFileInfo,
 	fileReleaseCb FileReleaseCallback) {
 	if len(watchConfigFiles) == 0 {
 		return
@@ -89,6 +122,7 @@ func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*api.Clien
 				newWatchers.Store(clientId, &watchContext{
 					fileReleaseCb: fileReleaseCb,
 					ClientVersion: file.Version.GetValue(),
+					Client:        client,
 				})
 				wc.configFileWatchers.Store(watchFileId, newWatchers)
 			}
@@ -101,6 +135,7 @@ func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*api.Clien
 		watcherMap.Store(clientId, &watchContext{
 			fileReleaseCb: fileReleaseCb,
 			ClientVersion: file.Version.GetValue(),
+			Client:        client,
 		})
 	}
 }
@@ -132,7 +167,14 @@ func (wc *watchCenter) handleMessage() {
 		}()
 
 		for message := range wc.releaseMessageQueue {
-			wc.notifyToWatchers(message)
+			switch msg := message.(type) {
+			case *model.ConfigFileRelease:
+				wc.notifyToWatchers(msg)
+			case *grayRelease:
+				wc.notifyGrayToWatchers(msg)
+			case *clientPinEvent:
+				wc.notifyPinToWatchers(msg)
+			}
 		}
 	}()
 }
@@ -154,6 +196,10 @@ func (wc *watchCenter) notifyToWatchers(publishConfigFile *model.ConfigFileRelea
 	watcherMap.Range(func(clientId, watchCtx interface{}) bool {
 
 		c := watchCtx.(*watchContext)
+		if wc.pinMatcher(publishConfigFile.Namespace, publishConfigFile.Group, publishConfigFile.FileName,
+			c.Client) {
+			return true
+		}
 		if c.ClientVersion < publishConfigFile.Version {
 			log.ConfigScope().Info("[Config][Watcher] notify to client.",
 				zap.String("file", watchFileId),
@@ -170,3 +216,73 @@ func (wc *watchCenter) notifyToWatchers(publishConfigFile *model.ConfigFileRelea
 		return true
 	})
 }
+
+// notifyGrayToWatchers 灰度发布只通知命中灰度规则的客户端
+func (wc *watchCenter) notifyGrayToWatchers(gray *grayRelease) {
+	watchFileId := utils.GenFileId(gray.Namespace, gray.Group, gray.FileName)
+
+	log.ConfigScope().Info("[Config][Watcher] received config file gray publish message.", zap.String("file", watchFileId))
+
+	watchers, ok := wc.configFileWatchers.Load(watchFileId)
+	if !ok {
+		return
+	}
+
+	response := utils2.GenConfigFileResponse(gray.Namespace, gray.Group, gray.FileName, "", gray.Md5, gray.Version)
+
+	watcherMap := watchers.(*sync.Map)
+	watcherMap.Range(func(clientId, watchCtx interface{}) bool {
+		c := watchCtx.(*watchContext)
+		if c.ClientVersion >= gray.Version || !wc.grayMatcher(c.Client, gray) ||
+			wc.pinMatcher(gray.Namespace, gray.Group, gray.FileName, c.Client) {
+			return true
+		}
+		log.ConfigScope().Info("[Config][Watcher] notify gray release to client.",
+			zap.String("file", watchFileId),
+			zap.String("clientId", clientId.(string)),
+			zap.String("client", c.Client.key()),
+			zap.Uint64("version", gray.Version))
+		c.fileReleaseCb(clientId.(string), response)
+		return true
+	})
+}
+
+// notifyPinToWatchers 客户端版本固定变化时通知被固定的客户端，固定内容与发布内容的版本号可能相同，
+// 所以不比较版本号，由客户端根据 md5 判断是否需要重新获取配置
+func (wc *watchCenter) notifyPinToWatchers(event *clientPinEvent) {
+	pin := event.Pin
+	watchFileId := utils.GenFileId(pin.Namespace, pin.Group, pin.FileName)
+
+	log.ConfigScope().Info("[Config][Watcher] received config file client pin message.",
+		zap.String("file", watchFileId), zap.String("client", pin.Client), zap.Bool("removed", event.Removed))
+
+	watchers, ok := wc.configFileWatchers.Load(watchFileId)
+	if !ok {
+		return
+	}
+
+	watcherMap := watchers.(*sync.Map)
+	watcherMap.Range(func(clientId, watchCtx interface{}) bool {
+		c := watchCtx.(*watchContext)
+		if !c.Client.is(pin.Client) {
+			return true
+		}
+		// 取消固定后客户端是否回到灰度发布取决于客户端自身是否命中灰度规则
+		current := event.Entry
+		if gray := event.Gray; gray != nil && gray.Version > current.Version &&
+			(!event.Removed || wc.grayMatcher(c.Client, gray)) {
+			current = gray.entry()
+		}
+		if !event.Removed {
+			current = pinnedEntry(pin, current)
+		}
+		response := utils2.GenConfigFileResponse(pin.Namespace, pin.Group, pin.FileName, "", current.Md5,
+			current.Version)
+		log.ConfigScope().Info("[Config][Watcher] notify client pin to client.",
+			zap.String("file", watchFileId),
+			zap.String("clientId", clientId.(string)),
+			zap.Uint64("version", current.Version))
+		c.fileReleaseCb(clientId.(string), response)
+		return true
+	})
+}
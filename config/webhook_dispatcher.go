@@ -0,0 +1,450 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	defaultWebhookTimeout           = 5 * time.Second
+	defaultWebhookMaxRetries        = 5
+	defaultWebhookRetryInterval     = 2 * time.Second
+	defaultWebhookWorkers           = 4
+	defaultWebhookDeliveryRetention = 7 * 24 * time.Hour
+
+	maxWebhookRetryInterval  = time.Minute
+	webhookCleanInterval     = time.Hour
+	webhookQueueSize         = 1024
+	maxWebhookErrorLength    = 1024
+	maxWebhookResponseLength = 4096
+
+	// WebhookEventHeader 推送的事件类型
+	WebhookEventHeader = "X-Polaris-Event"
+	// WebhookDeliveryHeader 推送记录的 ID，重试时保持不变，接收方可以据此去重
+	WebhookDeliveryHeader = "X-Polaris-Delivery"
+	// WebhookTimestampHeader 推送时间，Unix 时间戳，单位秒，每次重试时更新
+	WebhookTimestampHeader = "X-Polaris-Timestamp"
+	// WebhookSignatureHeader 推送时间以及请求体的签名，格式为 sha256=<hex(HMAC-SHA256(secret, timestamp + "." + body))>
+	WebhookSignatureHeader = "X-Polaris-Signature"
+	// WebhookSignatureTolerance 接收方允许的推送时间与本地时间的最大偏差，超过时应当拒绝请求，
+	// 偏差范围内通过 X-Polaris-Delivery 去重，避免请求被截获后重放
+	WebhookSignatureTolerance = 5 * time.Minute
+)
+
+// WebhookConfig 配置文件变更 webhook 推送参数
+type WebhookConfig struct {
+	// Timeout 单次推送的超时时间
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxRetries 推送失败后的最大重试次数
+	MaxRetries int `yaml:"maxRetries"`
+	// RetryInterval 第一次重试的间隔，之后按指数退避，最长不超过 1 分钟。同时也是扫描到期推送记录的间隔
+	RetryInterval time.Duration `yaml:"retryInterval"`
+	// Workers 并发推送的协程数
+	Workers int `yaml:"workers"`
+	// DeliveryRetention 推送记录的保留时间
+	DeliveryRetention time.Duration `yaml:"deliveryRetention"`
+}
+
+func (c *WebhookConfig) setDefault() {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultWebhookTimeout
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = defaultWebhookMaxRetries
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = defaultWebhookRetryInterval
+	}
+	if c.Workers <= 0 {
+		c.Workers = defaultWebhookWorkers
+	}
+	if c.DeliveryRetention <= 0 {
+		c.DeliveryRetention = defaultWebhookDeliveryRetention
+	}
+}
+
+// configFileChangedEvent 配置文件发布或者删除事件
+type configFileChangedEvent struct {
+	Release *model.ConfigFileRelease
+	// OldMd5 变更前的 md5，当前节点没有缓存配置文件时为空
+	OldMd5 string
+}
+
+// WebhookPayload webhook 推送的请求体
+type WebhookPayload struct {
+	Event       string `json:"event"`
+	Namespace   string `json:"namespace"`
+	Group       string `json:"group"`
+	FileName    string `json:"fileName"`
+	ReleaseName string `json:"releaseName"`
+	Version     uint64 `json:"version"`
+	OldMd5      string `json:"oldMd5"`
+	NewMd5      string `json:"newMd5"`
+	Operator    string `json:"operator"`
+	// Timestamp 发布时间，毫秒
+	Timestamp int64 `json:"timestamp"`
+}
+
+// webhookDispatcher 订阅配置文件变更事件，向匹配的 webhook 推送变更并记录推送结果。
+// 发布事件扫描器在每个节点上都会产生事件，通过推送记录的唯一键保证同一个事件只会创建一条推送记录。
+// 推送失败后记录下一次推送时间，每个节点定时认领到期的推送记录进行重试，节点重启后重试不会丢失
+type webhookDispatcher struct {
+	ctx     context.Context
+	storage store.Store
+	cfg     WebhookConfig
+	client  *http.Client
+	events  chan *configFileChangedEvent
+	tasks   chan *model.ConfigFileWebhookDelivery
+}
+
+func newWebhookDispatcher(ctx context.Context, storage store.Store, cfg WebhookConfig) *webhookDispatcher {
+	cfg.setDefault()
+	d := &webhookDispatcher{
+		ctx:     ctx,
+		storage: storage,
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		events:  make(chan *configFileChangedEvent, webhookQueueSize),
+		tasks:   make(chan *model.ConfigFileWebhookDelivery, webhookQueueSize),
+	}
+
+	go d.runEventLoop()
+	for i := 0; i < cfg.Workers; i++ {
+		go d.runWorker()
+	}
+	go d.runRetryTask()
+	go d.runCleanTask()
+	return d
+}
+
+// onConfigFileChanged 事件回调，不能阻塞扫描器，所以队列满时直接丢弃事件
+func (d *webhookDispatcher) onConfigFileChanged(event Event) bool {
+	changed, ok := event.Message.(*configFileChangedEvent)
+	if !ok {
+		return false
+	}
+	select {
+	case d.events <- changed:
+		return true
+	default:
+		log.ConfigScope().Error("[Config][Webhook] event queue is full, drop event.",
+			zap.String("namespace", changed.Release.Namespace),
+			zap.String("group", changed.Release.Group),
+			zap.String("fileName", changed.Release.FileName),
+			zap.Uint64("version", changed.Release.Version))
+		return false
+	}
+}
+
+func (d *webhookDispatcher) runEventLoop() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case event := <-d.events:
+			d.handleEvent(event)
+		}
+	}
+}
+
+func (d *webhookDispatcher) runWorker() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case delivery := <-d.tasks:
+			d.deliver(delivery)
+		}
+	}
+}
+
+// runRetryTask 定时从存储中获取到期的待推送记录，包括其他节点未完成的推送
+func (d *webhookDispatcher) runRetryTask() {
+	ticker := time.NewTicker(d.cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			deliveries, err := d.storage.GetDueConfigFileWebhookDeliveries(time.Now(), webhookQueueSize)
+			if err != nil {
+				log.ConfigScope().Error("[Config][Webhook] get due webhook deliveries error.", zap.Error(err))
+				continue
+			}
+			for _, delivery := range deliveries {
+				d.enqueue(delivery)
+			}
+		}
+	}
+}
+
+func (d *webhookDispatcher) runCleanTask() {
+	ticker := time.NewTicker(webhookCleanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			before := time.Now().Add(-d.cfg.DeliveryRetention)
+			if err := d.storage.CleanConfigFileWebhookDeliveries(before); err != nil {
+				log.ConfigScope().Error("[Config][Webhook] clean webhook deliveries error.", zap.Error(err))
+			}
+		}
+	}
+}
+
+// handleEvent 为事件匹配的每个 webhook 创建推送记录，推送记录已经存在说明事件已经被其他节点处理
+func (d *webhookDispatcher) handleEvent(event *configFileChangedEvent) {
+	release := event.Release
+	webhooks, err := d.storage.GetConfigFileWebhooksByNamespace(release.Namespace)
+	if err != nil {
+		log.ConfigScope().Error("[Config][Webhook] get webhooks error.",
+			zap.String("namespace", release.Namespace), zap.Error(err))
+		return
+	}
+
+	payload := newWebhookPayload(event)
+	for _, webhook := range webhooks {
+		if !matchWebhook(webhook, payload.Event, release) {
+			continue
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			log.ConfigScope().Error("[Config][Webhook] marshal webhook payload error.", zap.Error(err))
+			return
+		}
+		delivery, err := d.storage.CreateConfigFileWebhookDelivery(&model.ConfigFileWebhookDelivery{
+			WebhookId:     webhook.Id,
+			EventKey:      webhookEventKey(payload.Event, release),
+			EventType:     payload.Event,
+			Namespace:     release.Namespace,
+			Group:         release.Group,
+			FileName:      release.FileName,
+			Payload:       string(body),
+			Status:        utils.WebhookDeliveryPending,
+			NextRetryTime: time.Now(),
+		})
+		if err != nil {
+			if store.Code(err) != store.DuplicateEntryErr {
+				log.ConfigScope().Error("[Config][Webhook] create webhook delivery error.",
+					zap.String("webhook", webhook.Name), zap.Error(err))
+			}
+			continue
+		}
+		if delivery == nil {
+			continue
+		}
+		d.enqueue(delivery)
+	}
+}
+
+func (d *webhookDispatcher) enqueue(delivery *model.ConfigFileWebhookDelivery) {
+	select {
+	case <-d.ctx.Done():
+	case d.tasks <- delivery:
+	}
+}
+
+// deliver 认领推送记录后推送一次，失败后按指数退避记录下一次推送时间，超过最大重试次数后标记为失败
+func (d *webhookDispatcher) deliver(delivery *model.ConfigFileWebhookDelivery) {
+	// 认领期间其他节点不会重复推送，推送超时后认领失效，由其他节点接手
+	claimed, err := d.storage.ClaimConfigFileWebhookDelivery(delivery, time.Now().Add(2*d.cfg.Timeout))
+	if err != nil {
+		log.ConfigScope().Error("[Config][Webhook] claim webhook delivery error.",
+			zap.Uint64("id", delivery.Id), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	webhook, err := d.storage.GetConfigFileWebhook(delivery.WebhookId)
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case webhook == nil || !webhook.Enable:
+		// webhook 已经删除或者停用，不再重试
+		delivery.Status = utils.WebhookDeliveryFailed
+		delivery.Error = "webhook is deleted or disabled"
+		d.saveDelivery(delivery)
+		return
+	default:
+		delivery.ResponseCode, err = d.send(webhook, delivery)
+		if err == nil {
+			delivery.Status = utils.WebhookDeliverySuccess
+			delivery.Error = ""
+			d.saveDelivery(delivery)
+			return
+		}
+		delivery.Error = err.Error()
+	}
+
+	if len(delivery.Error) > maxWebhookErrorLength {
+		delivery.Error = delivery.Error[:maxWebhookErrorLength]
+	}
+	if delivery.Attempts > d.cfg.MaxRetries {
+		delivery.Status = utils.WebhookDeliveryFailed
+		d.saveDelivery(delivery)
+		return
+	}
+
+	delivery.NextRetryTime = time.Now().Add(d.retryInterval(delivery.Attempts))
+	d.saveDelivery(delivery)
+}
+
+func (d *webhookDispatcher) retryInterval(attempts int) time.Duration {
+	interval := d.cfg.RetryInterval
+	for i := 1; i < attempts && interval < maxWebhookRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxWebhookRetryInterval {
+		interval = maxWebhookRetryInterval
+	}
+	return interval
+}
+
+func (d *webhookDispatcher) send(webhook *model.ConfigFileWebhook, delivery *model.ConfigFileWebhookDelivery) (int,
+	error) {
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(delivery.Id, 10))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	rsp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, maxWebhookResponseLength))
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return rsp.StatusCode, fmt.Errorf("unexpected status code %d: %s", rsp.StatusCode, string(respBody))
+	}
+	return rsp.StatusCode, nil
+}
+
+func (d *webhookDispatcher) saveDelivery(delivery *model.ConfigFileWebhookDelivery) {
+	if err := d.storage.UpdateConfigFileWebhookDelivery(delivery); err != nil {
+		log.ConfigScope().Error("[Config][Webhook] update webhook delivery error.",
+			zap.Uint64("id", delivery.Id), zap.Error(err))
+	}
+}
+
+// SignWebhookPayload 计算 webhook 推送时间以及请求体的签名，接收方使用相同的 secret 计算签名并比较
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp + "."))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature 接收方校验 webhook 请求的签名，推送时间与 now 相差超过 tolerance 时校验失败
+func VerifyWebhookSignature(secret, timestamp, signature string, body []byte, tolerance time.Duration,
+	now time.Time) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook timestamp %q", timestamp)
+	}
+	if diff := now.Sub(time.Unix(sec, 0)); diff > tolerance || diff < -tolerance {
+		return fmt.Errorf("webhook timestamp %s is out of tolerance %s", timestamp, tolerance)
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignWebhookPayload(secret, timestamp, body))) {
+		return errors.New("webhook signature mismatch")
+	}
+	return nil
+}
+
+func newWebhookPayload(event *configFileChangedEvent) *WebhookPayload {
+	release := event.Release
+	payload := &WebhookPayload{
+		Event:       utils.WebhookEventPublish,
+		Namespace:   release.Namespace,
+		Group:       release.Group,
+		FileName:    release.FileName,
+		ReleaseName: release.Name,
+		Version:     release.Version,
+		OldMd5:      event.OldMd5,
+		NewMd5:      release.Md5,
+		Operator:    release.ModifyBy,
+		Timestamp:   release.ModifyTime.UnixNano() / int64(time.Millisecond),
+	}
+	if release.Flag == 1 {
+		payload.Event = utils.WebhookEventDelete
+		payload.NewMd5 = ""
+	}
+	return payload
+}
+
+// matchWebhook webhook 的分组、文件名为空时匹配全部，订阅的事件为空时匹配全部事件
+func matchWebhook(webhook *model.ConfigFileWebhook, eventType string, release *model.ConfigFileRelease) bool {
+	if !webhook.Enable {
+		return false
+	}
+	if webhook.Group != "" && webhook.Group != release.Group {
+		return false
+	}
+	if webhook.FileName != "" && webhook.FileName != release.FileName {
+		return false
+	}
+	if webhook.Events == "" {
+		return true
+	}
+	for _, event := range strings.Split(webhook.Events, ",") {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookEventKey(eventType string, release *model.ConfigFileRelease) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%d:%d", eventType, release.Namespace, release.Group, release.FileName,
+		release.Version, release.ModifyTime.Unix())
+}
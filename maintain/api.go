@@ -22,6 +22,7 @@ import (
 
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/connlimit"
+	"github.com/polarismesh/polaris/config"
 )
 
 type ConnReq struct {
@@ -54,6 +55,9 @@ type MaintainOperateServer interface {
 	// GetServerConnStats 获取连接缓存里面的统计信息
 	GetServerConnStats(ctx context.Context, req *ConnReq) (*ConnStatsResp, error)
 
+	// GetConfigConnections 获取配置中心客户端长轮询、长连接的统计
+	GetConfigConnections(ctx context.Context) (*config.ConnStats, error)
+
 	// CloseConnections Close connection by ip
 	CloseConnections(ctx context.Context, reqs []ConnReq) error
 
@@ -22,6 +22,7 @@ import (
 	"errors"
 
 	"github.com/polarismesh/polaris/auth"
+	"github.com/polarismesh/polaris/config"
 	"github.com/polarismesh/polaris/service"
 	"github.com/polarismesh/polaris/service/healthcheck"
 )
@@ -33,12 +34,13 @@ var (
 )
 
 // Initialize 初始化
-func Initialize(ctx context.Context, namingService service.DiscoverServer, healthCheckServer *healthcheck.Server) error {
+func Initialize(ctx context.Context, namingService service.DiscoverServer, healthCheckServer *healthcheck.Server,
+	configServer *config.Server) error {
 	if finishInit {
 		return nil
 	}
 
-	err := initialize(ctx, namingService, healthCheckServer)
+	err := initialize(ctx, namingService, healthCheckServer, configServer)
 	if err != nil {
 		return err
 	}
@@ -47,7 +49,8 @@ func Initialize(ctx context.Context, namingService service.DiscoverServer, healt
 	return nil
 }
 
-func initialize(_ context.Context, namingService service.DiscoverServer, healthCheckServer *healthcheck.Server) error {
+func initialize(_ context.Context, namingService service.DiscoverServer, healthCheckServer *healthcheck.Server,
+	configServer *config.Server) error {
 	authServer, err := auth.GetAuthServer()
 	if err != nil {
 		return err
@@ -55,6 +58,7 @@ func initialize(_ context.Context, namingService service.DiscoverServer, healthC
 
 	maintainServer.namingServer = namingService
 	maintainServer.healthCheckServer = healthCheckServer
+	maintainServer.configServer = configServer
 
 	server = newServerAuthAbility(maintainServer, authServer)
 	return nil
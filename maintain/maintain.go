@@ -26,6 +26,7 @@ import (
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/connlimit"
 	commonlog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/config"
 )
 
 func (s *Server) GetServerConnections(_ context.Context, req *ConnReq) (*ConnCountResp, error) {
@@ -55,6 +56,14 @@ func (s *Server) GetServerConnections(_ context.Context, req *ConnReq) (*ConnCou
 	return &resp, nil
 }
 
+// GetConfigConnections 获取配置中心客户端长轮询、长连接的统计，未开启配置中心时返回错误
+func (s *Server) GetConfigConnections(_ context.Context) (*config.ConnStats, error) {
+	if s.configServer == nil || s.configServer.ConnManager() == nil {
+		return nil, errors.New("config center is not open")
+	}
+	return s.configServer.ConnManager().Stats(), nil
+}
+
 func (s *Server) GetServerConnStats(_ context.Context, req *ConnReq) (*ConnStatsResp, error) {
 	if req.Protocol == "" {
 		return nil, errors.New("missing param protocol")
@@ -22,6 +22,7 @@ import (
 
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/config"
 )
 
 var _ MaintainOperateServer = (*serverAuthAbility)(nil)
@@ -46,6 +47,16 @@ func (svr *serverAuthAbility) GetServerConnStats(ctx context.Context, req *ConnR
 	return svr.targetServer.GetServerConnStats(ctx, req)
 }
 
+func (svr *serverAuthAbility) GetConfigConnections(ctx context.Context) (*config.ConnStats, error) {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Read, "GetConfigConnections")
+	_, err := svr.authMgn.CheckConsolePermission(authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return svr.targetServer.GetConfigConnections(ctx)
+}
+
 func (svr *serverAuthAbility) CloseConnections(ctx context.Context, reqs []ConnReq) error {
 	authCtx := svr.collectMaintainAuthContext(ctx, model.Delete, "CloseConnections")
 	_, err := svr.authMgn.CheckConsolePermission(authCtx)
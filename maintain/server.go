@@ -20,6 +20,7 @@ package maintain
 import (
 	"sync"
 
+	"github.com/polarismesh/polaris/config"
 	"github.com/polarismesh/polaris/service"
 	"github.com/polarismesh/polaris/service/healthcheck"
 )
@@ -30,4 +31,5 @@ type Server struct {
 	mu                sync.Mutex
 	namingServer      service.DiscoverServer
 	healthCheckServer *healthcheck.Server
+	configServer      *config.Server
 }
@@ -131,6 +131,10 @@ func (s *Server) createNamespaceModel(req *api.Namespace) *model.Namespace {
 		Comment: req.GetComment().GetValue(),
 		Owner:   req.GetOwners().GetValue(),
 		Token:   utils.NewUUID(),
+
+		ServiceQuota:    req.GetServiceQuota().GetValue(),
+		InstanceQuota:   req.GetInstanceQuota().GetValue(),
+		ConfigFileQuota: req.GetConfigFileQuota().GetValue(),
 	}
 
 	return namespace
@@ -298,6 +302,16 @@ func (s *Server) updateNamespaceAttribute(req *api.Namespace, namespace *model.N
 	if req.GetOwners() != nil {
 		namespace.Owner = req.GetOwners().GetValue()
 	}
+
+	if req.GetServiceQuota() != nil {
+		namespace.ServiceQuota = req.GetServiceQuota().GetValue()
+	}
+	if req.GetInstanceQuota() != nil {
+		namespace.InstanceQuota = req.GetInstanceQuota().GetValue()
+	}
+	if req.GetConfigFileQuota() != nil {
+		namespace.ConfigFileQuota = req.GetConfigFileQuota().GetValue()
+	}
 }
 
 // UpdateNamespaceToken 更新命名空间token
@@ -347,9 +361,23 @@ func (s *Server) GetNamespaces(ctx context.Context, query map[string][]string) *
 	out := api.NewBatchQueryResponse(api.ExecuteSuccess)
 	out.Amount = utils.NewUInt32Value(amount)
 	out.Size = utils.NewUInt32Value(uint32(len(namespaces)))
+
+	names := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+	configFileCnts, err := s.storage.CountConfigFilesByNamespaces(names)
+	if err != nil {
+		log.Error("count config files with namespaces err",
+			zap.Strings("namespaces", names),
+			zap.String("err", err.Error()))
+		return api.NewBatchQueryResponse(api.StoreLayerException)
+	}
+
 	for _, namespace := range namespaces {
 
 		nsCntInfo := s.caches.Service().GetNamespaceCntInfo(namespace.Name)
+		configFileCnt := configFileCnts[namespace.Name]
 
 		out.AddNamespace(&api.Namespace{
 			Id:                       utils.NewStringValue(namespace.Name),
@@ -361,6 +389,10 @@ func (s *Server) GetNamespaces(ctx context.Context, query map[string][]string) *
 			TotalServiceCount:        utils.NewUInt32Value(nsCntInfo.ServiceCount),
 			TotalInstanceCount:       utils.NewUInt32Value(nsCntInfo.InstanceCnt.TotalInstanceCount),
 			TotalHealthInstanceCount: utils.NewUInt32Value(nsCntInfo.InstanceCnt.HealthyInstanceCount),
+			TotalConfigFileCount:     utils.NewUInt32Value(uint32(configFileCnt)),
+			ServiceQuota:             utils.NewUInt32Value(namespace.ServiceQuota),
+			InstanceQuota:            utils.NewUInt32Value(namespace.InstanceQuota),
+			ConfigFileQuota:          utils.NewUInt32Value(namespace.ConfigFileQuota),
 		})
 	}
 	return out
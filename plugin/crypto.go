@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package plugin
+
+import (
+	"os"
+	"sync"
+
+	"github.com/polarismesh/polaris/common/log"
+)
+
+var (
+	cryptoOnce = &sync.Once{}
+)
+
+// Crypto 加密插件，使用数据密钥对配置内容进行加解密
+type Crypto interface {
+	Plugin
+	// GenerateKey 生成数据密钥
+	GenerateKey() ([]byte, error)
+	// Encrypt 使用数据密钥加密明文
+	Encrypt(plaintext string, key []byte) (string, error)
+	// Decrypt 使用数据密钥解密密文
+	Decrypt(ciphertext string, key []byte) (string, error)
+}
+
+// GetCrypto 获取加密插件
+func GetCrypto() Crypto {
+	c := &config.Crypto
+	plugin, exist := pluginSet[c.Name]
+	if !exist {
+		return nil
+	}
+
+	cryptoOnce.Do(func() {
+		if err := plugin.Initialize(c); err != nil {
+			log.Errorf("plugin init err: %s", err.Error())
+			os.Exit(-1)
+		}
+	})
+
+	return plugin.(Crypto)
+}
@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "AES"
+	// keySize 数据密钥长度，使用 AES-256
+	keySize = 32
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(PluginName, &AESCrypto{})
+}
+
+// AESCrypto 使用 AES-GCM 加解密配置内容，密文格式为 base64(nonce + 密文)
+type AESCrypto struct{}
+
+// Name 返回插件名称
+func (c *AESCrypto) Name() string {
+	return PluginName
+}
+
+// Initialize 初始化插件
+func (c *AESCrypto) Initialize(conf *plugin.ConfigEntry) error {
+	return nil
+}
+
+// Destroy 销毁插件
+func (c *AESCrypto) Destroy() error {
+	return nil
+}
+
+// GenerateKey 生成随机的数据密钥
+func (c *AESCrypto) GenerateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypt 加密明文
+func (c *AESCrypto) Encrypt(plaintext string, key []byte) (string, error) {
+	data, err := Seal([]byte(plaintext), key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt 解密密文，密文被篡改或者密钥不匹配时返回错误
+func (c *AESCrypto) Decrypt(ciphertext string, key []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := Open(data, key)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Seal 使用 AES-GCM 加密数据，随机生成的 nonce 放在密文头部
+func Seal(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open 解密 Seal 加密的数据
+func Open(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
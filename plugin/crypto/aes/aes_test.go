@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package aes
+
+import (
+	"testing"
+)
+
+// TestEncryptDecrypt 测试加解密
+func TestEncryptDecrypt(t *testing.T) {
+	c := &AESCrypto{}
+	key, err := c.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key err: %s", err.Error())
+	}
+	if len(key) != keySize {
+		t.Fatalf("key size expect %d, actual %d", keySize, len(key))
+	}
+
+	plaintext := "username=polaris\npassword=polaris"
+	ciphertext, err := c.Encrypt(plaintext, key)
+	if err != nil {
+		t.Fatalf("encrypt err: %s", err.Error())
+	}
+	if ciphertext == plaintext {
+		t.Fatalf("ciphertext should not equal plaintext")
+	}
+
+	ret, err := c.Decrypt(ciphertext, key)
+	if err != nil {
+		t.Fatalf("decrypt err: %s", err.Error())
+	}
+	if ret != plaintext {
+		t.Fatalf("decrypt expect %s, actual %s", plaintext, ret)
+	}
+
+	t.Run("WrongKey", func(t *testing.T) {
+		otherKey, _ := c.GenerateKey()
+		if _, err := c.Decrypt(ciphertext, otherKey); err == nil {
+			t.Fatalf("decrypt with wrong key should fail")
+		}
+	})
+
+	t.Run("Plaintext", func(t *testing.T) {
+		if _, err := c.Decrypt(plaintext, key); err == nil {
+			t.Fatalf("decrypt plaintext should fail")
+		}
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"sync"
 
+	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/log"
 )
 
@@ -36,6 +37,8 @@ type CheckRequest struct {
 	QueryRequest
 	ExpireDurationSec uint32
 	CurTimeSec        func() int64
+	// Metadata instance metadata, active probes read their options from it
+	Metadata map[string]string
 }
 
 // CheckResponse check heartbeat response
@@ -71,11 +74,23 @@ type AddCheckRequest struct {
 type HealthCheckType int32
 
 const (
-	HealthCheckerHeartbeat HealthCheckType = iota + 1
+	// HealthCheckerHeartbeat client reports heartbeat
+	HealthCheckerHeartbeat = HealthCheckType(api.HealthCheck_HEARTBEAT)
+	// HealthCheckerTCP server side active probe by tcp connect
+	HealthCheckerTCP = HealthCheckType(api.HealthCheck_TCP)
+	// HealthCheckerHTTP server side active probe by http get
+	HealthCheckerHTTP = HealthCheckType(api.HealthCheck_HTTP)
+	// HealthCheckerGRPC server side active probe by grpc health checking protocol
+	HealthCheckerGRPC = HealthCheckType(api.HealthCheck_GRPC)
 )
 
+// IsActiveProbe whether the check type is probed by server instead of reported by client
+func (t HealthCheckType) IsActiveProbe() bool {
+	return t >= HealthCheckerTCP && t <= HealthCheckerGRPC
+}
+
 var (
-	healthCheckOnce = &sync.Once{}
+	healthCheckOnces = &sync.Map{}
 )
 
 // HealthChecker health checker plugin interface
@@ -104,7 +119,8 @@ func GetHealthChecker(name string, cfg *ConfigEntry) HealthChecker {
 		return nil
 	}
 
-	healthCheckOnce.Do(func() {
+	onceValue, _ := healthCheckOnces.LoadOrStore(name, &sync.Once{})
+	onceValue.(*sync.Once).Do(func() {
 		if err := plugin.Initialize(cfg); err != nil {
 			log.Errorf("plugin init err: %s", err.Error())
 			os.Exit(-1)
@@ -0,0 +1,202 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package activeprobe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	commonlog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+var log = commonlog.HealthCheckScope()
+
+const (
+	// PluginNameTCP tcp connect probe plugin
+	PluginNameTCP = "probeTcp"
+	// PluginNameHTTP http get probe plugin
+	PluginNameHTTP = "probeHttp"
+	// PluginNameGRPC grpc health checking protocol probe plugin
+	PluginNameGRPC = "probeGrpc"
+
+	defaultTimeout = 2 * time.Second
+)
+
+// Config active probe option
+type Config struct {
+	// Timeout timeout for one probe, like 2s
+	Timeout string `json:"timeout"`
+}
+
+// prober do the probe to the target address, returns nil if the target is healthy
+type prober func(ctx context.Context, address string, metadata map[string]string) error
+
+// ProbeRecord record for the last probe
+type ProbeRecord struct {
+	Healthy    bool
+	CurTimeSec int64
+}
+
+// ActiveHealthChecker health checker probing the instances from server side,
+// the instances are dispatched to checker nodes by the health check dispatcher
+type ActiveHealthChecker struct {
+	name      string
+	checkType plugin.HealthCheckType
+	probe     prober
+	timeout   time.Duration
+	records   *sync.Map
+}
+
+func newActiveHealthChecker(name string, checkType plugin.HealthCheckType, probe prober) *ActiveHealthChecker {
+	return &ActiveHealthChecker{
+		name:      name,
+		checkType: checkType,
+		probe:     probe,
+		timeout:   defaultTimeout,
+		records:   &sync.Map{},
+	}
+}
+
+// Name return plugin name
+func (a *ActiveHealthChecker) Name() string {
+	return a.name
+}
+
+// Initialize initialize plugin
+func (a *ActiveHealthChecker) Initialize(c *plugin.ConfigEntry) error {
+	a.records = &sync.Map{}
+	a.timeout = defaultTimeout
+	if len(c.Option) == 0 {
+		return nil
+	}
+	optBytes, err := json.Marshal(c.Option)
+	if err != nil {
+		return fmt.Errorf("fail to marshal %s config entry, err is %v", a.name, err)
+	}
+	var config Config
+	if err = json.Unmarshal(optBytes, &config); err != nil {
+		return fmt.Errorf("fail to unmarshal %s config entry, err is %v", a.name, err)
+	}
+	if len(config.Timeout) > 0 {
+		timeout, err := time.ParseDuration(config.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid %s timeout %s", a.name, config.Timeout)
+		}
+		a.timeout = timeout
+	}
+	return nil
+}
+
+// Destroy plugin destruction
+func (a *ActiveHealthChecker) Destroy() error {
+	return nil
+}
+
+// Type for health check plugin, only one same type plugin is allowed
+func (a *ActiveHealthChecker) Type() plugin.HealthCheckType {
+	return a.checkType
+}
+
+// Report active probe does not need heartbeat, the report is ignored
+func (a *ActiveHealthChecker) Report(request *plugin.ReportRequest) error {
+	return nil
+}
+
+// Query queries the last probe time
+func (a *ActiveHealthChecker) Query(request *plugin.QueryRequest) (*plugin.QueryResponse, error) {
+	value, ok := a.records.Load(request.InstanceId)
+	if !ok {
+		return &plugin.QueryResponse{}, nil
+	}
+	record := value.(ProbeRecord)
+	return &plugin.QueryResponse{
+		Exists:           true,
+		LastHeartbeatSec: record.CurTimeSec,
+	}, nil
+}
+
+// Check probe the instance and compare the result with the current health status
+func (a *ActiveHealthChecker) Check(request *plugin.CheckRequest) (*plugin.CheckResponse, error) {
+	address := probeAddress(request.Host, request.Port, request.Metadata)
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	err := a.probe(ctx, address, request.Metadata)
+	curTimeSec := request.CurTimeSec()
+	healthy := err == nil
+	a.records.Store(request.InstanceId, ProbeRecord{Healthy: healthy, CurTimeSec: curTimeSec})
+
+	checkResp := &plugin.CheckResponse{
+		Healthy:              healthy,
+		LastHeartbeatTimeSec: curTimeSec,
+		Regular:              true,
+		StayUnchanged:        healthy == request.Healthy,
+	}
+	if !checkResp.StayUnchanged {
+		if healthy {
+			log.Infof("[Health Check][%s]probe resumed, instanceId %s, address %s",
+				a.name, request.InstanceId, address)
+		} else {
+			log.Infof("[Health Check][%s]probe failed, instanceId %s, address %s, err is %v",
+				a.name, request.InstanceId, address, err)
+		}
+	}
+	return checkResp, nil
+}
+
+// AddToCheck add the instances to check procedure
+func (a *ActiveHealthChecker) AddToCheck(request *plugin.AddCheckRequest) error {
+	return nil
+}
+
+// RemoveFromCheck removes the instances from check procedure
+func (a *ActiveHealthChecker) RemoveFromCheck(request *plugin.AddCheckRequest) error {
+	for _, id := range request.Instances {
+		a.records.Delete(id)
+	}
+	return nil
+}
+
+// Delete delete the id
+func (a *ActiveHealthChecker) Delete(id string) error {
+	a.records.Delete(id)
+	return nil
+}
+
+// probeAddress the port in metadata takes precedence over the instance port
+func probeAddress(host string, port uint32, metadata map[string]string) string {
+	if value, ok := metadata[model.MetaKeyHealthCheckPort]; ok {
+		if probePort, err := strconv.ParseUint(value, 10, 32); err == nil && probePort > 0 {
+			port = uint32(probePort)
+		}
+	}
+	return net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+}
+
+func init() {
+	plugin.RegisterPlugin(PluginNameTCP, newActiveHealthChecker(PluginNameTCP, plugin.HealthCheckerTCP, probeTCP))
+	plugin.RegisterPlugin(PluginNameHTTP, newActiveHealthChecker(PluginNameHTTP, plugin.HealthCheckerHTTP, probeHTTP))
+	plugin.RegisterPlugin(PluginNameGRPC, newActiveHealthChecker(PluginNameGRPC, plugin.HealthCheckerGRPC, probeGRPC))
+}
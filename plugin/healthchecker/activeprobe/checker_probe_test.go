@@ -0,0 +1,180 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package activeprobe
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+func splitHostPort(t *testing.T, address string) (string, uint32) {
+	host, portStr, err := net.SplitHostPort(address)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	assert.NoError(t, err)
+	return host, uint32(port)
+}
+
+func newCheckRequest(host string, port uint32, healthy bool, metadata map[string]string) *plugin.CheckRequest {
+	return &plugin.CheckRequest{
+		QueryRequest: plugin.QueryRequest{
+			InstanceId: "key",
+			Host:       host,
+			Port:       port,
+			Healthy:    healthy,
+		},
+		CurTimeSec: func() int64 {
+			return time.Now().Unix()
+		},
+		ExpireDurationSec: 5,
+		Metadata:          metadata,
+	}
+}
+
+func TestActiveHealthChecker_Type(t *testing.T) {
+	checkers := map[api.HealthCheck_HealthCheckType]*ActiveHealthChecker{
+		api.HealthCheck_TCP:  newActiveHealthChecker(PluginNameTCP, plugin.HealthCheckerTCP, probeTCP),
+		api.HealthCheck_HTTP: newActiveHealthChecker(PluginNameHTTP, plugin.HealthCheckerHTTP, probeHTTP),
+		api.HealthCheck_GRPC: newActiveHealthChecker(PluginNameGRPC, plugin.HealthCheckerGRPC, probeGRPC),
+	}
+	for checkType, checker := range checkers {
+		assert.Equal(t, plugin.HealthCheckType(checkType), checker.Type(), checkType.String())
+		assert.True(t, checker.Type().IsActiveProbe(), checkType.String())
+	}
+	assert.False(t, plugin.HealthCheckType(api.HealthCheck_HEARTBEAT).IsActiveProbe())
+	assert.False(t, plugin.HealthCheckType(api.HealthCheck_UNKNOWN).IsActiveProbe())
+}
+
+func TestActiveHealthChecker_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	host, port := splitHostPort(t, ln.Addr().String())
+
+	checker := newActiveHealthChecker(PluginNameTCP, plugin.HealthCheckerTCP, probeTCP)
+	assert.NoError(t, checker.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{"timeout": "500ms"}}))
+
+	resp, err := checker.Check(newCheckRequest(host, port, false, nil))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.True(t, resp.Regular)
+	assert.False(t, resp.StayUnchanged)
+
+	queryResp, err := checker.Query(&plugin.QueryRequest{InstanceId: "key"})
+	assert.NoError(t, err)
+	assert.True(t, queryResp.Exists)
+
+	_ = ln.Close()
+	resp, err = checker.Check(newCheckRequest(host, port, true, nil))
+	assert.NoError(t, err)
+	assert.False(t, resp.Healthy)
+	assert.False(t, resp.StayUnchanged)
+
+	resp, err = checker.Check(newCheckRequest(host, port, false, nil))
+	assert.NoError(t, err)
+	assert.True(t, resp.StayUnchanged)
+}
+
+func TestActiveHealthChecker_HTTP(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer svr.Close()
+	host, port := splitHostPort(t, svr.Listener.Addr().String())
+
+	checker := newActiveHealthChecker(PluginNameHTTP, plugin.HealthCheckerHTTP, probeHTTP)
+	assert.NoError(t, checker.Initialize(&plugin.ConfigEntry{}))
+
+	resp, err := checker.Check(newCheckRequest(host, port, true, map[string]string{
+		model.MetaKeyHealthCheckHTTPPath: "/health",
+	}))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.True(t, resp.StayUnchanged)
+
+	resp, err = checker.Check(newCheckRequest(host, port, true, map[string]string{
+		model.MetaKeyHealthCheckHTTPPath: "/other",
+	}))
+	assert.NoError(t, err)
+	assert.False(t, resp.Healthy)
+
+	resp, err = checker.Check(newCheckRequest(host, port, false, map[string]string{
+		model.MetaKeyHealthCheckHTTPPath:   "/other",
+		model.MetaKeyHealthCheckHTTPStatus: "503",
+	}))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+}
+
+func TestActiveHealthChecker_GRPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	host, port := splitHostPort(t, ln.Addr().String())
+
+	healthSvr := health.NewServer()
+	healthSvr.SetServingStatus("echo", healthpb.HealthCheckResponse_NOT_SERVING)
+	grpcSvr := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcSvr, healthSvr)
+	go func() {
+		_ = grpcSvr.Serve(ln)
+	}()
+	defer grpcSvr.Stop()
+
+	checker := newActiveHealthChecker(PluginNameGRPC, plugin.HealthCheckerGRPC, probeGRPC)
+	assert.NoError(t, checker.Initialize(&plugin.ConfigEntry{}))
+
+	resp, err := checker.Check(newCheckRequest(host, port, false, nil))
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+
+	resp, err = checker.Check(newCheckRequest(host, port, true, map[string]string{
+		model.MetaKeyHealthCheckGRPCService: "echo",
+	}))
+	assert.NoError(t, err)
+	assert.False(t, resp.Healthy)
+}
+
+func TestMatchStatus(t *testing.T) {
+	assert.True(t, matchStatus("2xx", 204))
+	assert.True(t, matchStatus("200", 200))
+	assert.False(t, matchStatus("2XX", 301))
+	assert.False(t, matchStatus("200", 201))
+}
+
+func TestProbeAddress(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:8080", probeAddress("127.0.0.1", 8080, nil))
+	assert.Equal(t, "127.0.0.1:9090", probeAddress("127.0.0.1", 8080,
+		map[string]string{model.MetaKeyHealthCheckPort: "9090"}))
+	assert.Equal(t, "[::1]:8080", probeAddress("::1", 8080, nil))
+}
@@ -0,0 +1,82 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package activeprobe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	defaultHTTPPath   = "/"
+	defaultHTTPStatus = "2xx"
+)
+
+var httpClient = &http.Client{
+	// the probe only cares about the first response
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// probeHTTP the target is healthy when the response status matches the expected one
+func probeHTTP(ctx context.Context, address string, metadata map[string]string) error {
+	path := metadata[model.MetaKeyHealthCheckHTTPPath]
+	if len(path) == 0 {
+		path = defaultHTTPPath
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	expectStatus := metadata[model.MetaKeyHealthCheckHTTPStatus]
+	if len(expectStatus) == 0 {
+		expectStatus = defaultHTTPStatus
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if !matchStatus(expectStatus, resp.StatusCode) {
+		return fmt.Errorf("unexpected status %d, expect %s", resp.StatusCode, expectStatus)
+	}
+	return nil
+}
+
+// matchStatus expect can be an exact code like 200, or a class like 2xx
+func matchStatus(expect string, code int) bool {
+	expect = strings.ToLower(strings.TrimSpace(expect))
+	if len(expect) == 3 && strings.HasSuffix(expect, "xx") {
+		return strconv.Itoa(code/100) == expect[:1]
+	}
+	return strconv.Itoa(code) == expect
+}
@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package plugin
+
+import (
+	"os"
+	"sync"
+
+	"github.com/polarismesh/polaris/common/log"
+)
+
+var (
+	kmsOnce = &sync.Once{}
+)
+
+// KMS 密钥管理插件，使用主密钥对数据密钥进行加解密，主密钥不会离开 KMS
+type KMS interface {
+	Plugin
+	// EncryptDataKey 使用主密钥加密数据密钥
+	EncryptDataKey(dataKey []byte) (string, error)
+	// DecryptDataKey 使用主密钥解密数据密钥
+	DecryptDataKey(encryptedKey string) ([]byte, error)
+}
+
+// GetKMS 获取密钥管理插件
+func GetKMS() KMS {
+	c := &config.KMS
+	plugin, exist := pluginSet[c.Name]
+	if !exist {
+		return nil
+	}
+
+	kmsOnce.Do(func() {
+		if err := plugin.Initialize(c); err != nil {
+			log.Errorf("plugin init err: %s", err.Error())
+			os.Exit(-1)
+		}
+	})
+
+	return plugin.(KMS)
+}
@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package local
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/plugin/crypto/aes"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "localKMS"
+	// masterKeySize 主密钥长度，使用 AES-256
+	masterKeySize = 32
+	// defaultMasterKeyFile 默认的主密钥文件
+	defaultMasterKeyFile = "./conf/master.key"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(PluginName, &KMS{})
+}
+
+// KMS 基于本地文件的密钥管理插件，主密钥以 base64 格式保存在本地文件中，文件不存在时自动生成
+type KMS struct {
+	masterKey []byte
+}
+
+// Name 返回插件名称
+func (k *KMS) Name() string {
+	return PluginName
+}
+
+// Initialize 初始化插件，option.masterKey 可以直接指定 base64 格式的主密钥，否则从 option.masterKeyFile 中读取
+func (k *KMS) Initialize(conf *plugin.ConfigEntry) error {
+	if encoded, _ := conf.Option["masterKey"].(string); encoded != "" {
+		key, err := decodeMasterKey(encoded)
+		if err != nil {
+			return err
+		}
+		k.masterKey = key
+		return nil
+	}
+
+	keyFile, _ := conf.Option["masterKeyFile"].(string)
+	if keyFile == "" {
+		keyFile = defaultMasterKeyFile
+	}
+	key, err := loadOrCreateMasterKey(keyFile)
+	if err != nil {
+		return err
+	}
+	k.masterKey = key
+	return nil
+}
+
+// Destroy 销毁插件
+func (k *KMS) Destroy() error {
+	return nil
+}
+
+// EncryptDataKey 使用主密钥加密数据密钥
+func (k *KMS) EncryptDataKey(dataKey []byte) (string, error) {
+	data, err := aes.Seal(dataKey, k.masterKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecryptDataKey 使用主密钥解密数据密钥
+func (k *KMS) DecryptDataKey(encryptedKey string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	return aes.Open(data, k.masterKey)
+}
+
+func loadOrCreateMasterKey(keyFile string) ([]byte, error) {
+	data, err := os.ReadFile(keyFile)
+	if err == nil {
+		return decodeMasterKey(string(data))
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key := make([]byte, masterKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+	log.Infof("[Plugin][KMS] generate master key file %s", keyFile)
+	return key, nil
+}
+
+func decodeMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != masterKeySize {
+		return nil, errors.New("master key must be 32 bytes")
+	}
+	return key, nil
+}
@@ -0,0 +1,76 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package local
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+// TestMasterKeyFile 测试主密钥文件不存在时自动生成，并且重新加载后可以解密数据密钥
+func TestMasterKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "conf", "master.key")
+	entry := &plugin.ConfigEntry{
+		Option: map[string]interface{}{"masterKeyFile": keyFile},
+	}
+
+	k := &KMS{}
+	if err := k.Initialize(entry); err != nil {
+		t.Fatalf("initialize err: %s", err.Error())
+	}
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatalf("master key file should be created: %s", err.Error())
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("master key file mode expect 0600, actual %o", info.Mode().Perm())
+	}
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	encrypted, err := k.EncryptDataKey(dataKey)
+	if err != nil {
+		t.Fatalf("encrypt data key err: %s", err.Error())
+	}
+
+	reload := &KMS{}
+	if err := reload.Initialize(entry); err != nil {
+		t.Fatalf("initialize err: %s", err.Error())
+	}
+	ret, err := reload.DecryptDataKey(encrypted)
+	if err != nil {
+		t.Fatalf("decrypt data key err: %s", err.Error())
+	}
+	if !bytes.Equal(ret, dataKey) {
+		t.Fatalf("decrypt data key not match")
+	}
+}
+
+// TestInvalidMasterKey 测试错误的主密钥配置
+func TestInvalidMasterKey(t *testing.T) {
+	k := &KMS{}
+	entry := &plugin.ConfigEntry{
+		Option: map[string]interface{}{"masterKey": "MTIz"},
+	}
+	if err := k.Initialize(entry); err == nil {
+		t.Fatalf("initialize with short master key should fail")
+	}
+}
@@ -66,4 +66,6 @@ type Config struct {
 	Auth                 ConfigEntry `yaml:"auth"`
 	MeshResourceValidate ConfigEntry `yaml:"meshResourceValidate"`
 	DiscoverEvent        ConfigEntry `yaml:"discoverEvent"`
+	Crypto               ConfigEntry `yaml:"crypto"`
+	KMS                  ConfigEntry `yaml:"kms"`
 }
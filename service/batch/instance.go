@@ -307,7 +307,13 @@ func (ctrl *InstanceCtrl) registerHandler(futures []*InstanceFuture) error {
 	for _, entry := range remains {
 		instances = append(instances, entry.instance)
 	}
+	MarkInstanceQuotaCheck(ctrl.cacheMgn, instances)
 	if err := ctrl.storage.BatchAddInstances(instances); err != nil {
+		// 超出命名空间配额时整批写入会失败，逐个重新注册，只让超出配额的实例注册失败
+		if store.Code(err) == store.ExceedNamespaceQuota {
+			ctrl.serialRegister(remains)
+			return nil
+		}
 		sendReply(remains, StoreCode2APICode(err), err)
 		return err
 	}
@@ -316,6 +322,17 @@ func (ctrl *InstanceCtrl) registerHandler(futures []*InstanceFuture) error {
 	return nil
 }
 
+// serialRegister 逐个注册实例，并分别返回每个实例的注册结果
+func (ctrl *InstanceCtrl) serialRegister(futures map[string]*InstanceFuture) {
+	for _, entry := range futures {
+		if err := ctrl.storage.AddInstance(entry.instance); err != nil {
+			entry.Reply(time.Now(), StoreCode2APICode(err), err)
+			continue
+		}
+		entry.Reply(time.Now(), api.ExecuteSuccess, nil)
+	}
+}
+
 // heartbeatHandler 心跳状态变更处理函数
 func (ctrl *InstanceCtrl) heartbeatHandler(futures []*InstanceFuture) error {
 	if len(futures) == 0 {
@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package batch
+
+import (
+	"github.com/polarismesh/polaris/cache"
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	// minInstanceQuotaMargin 缓存中的实例数距离配额的最小余量
+	minInstanceQuotaMargin = 100
+	// instanceQuotaMarginRatio 按照配额的比例计算余量，覆盖缓存刷新间隔内其他节点新注册的实例
+	instanceQuotaMarginRatio = 10
+)
+
+// MarkInstanceQuotaCheck 根据缓存中命名空间的实例数，标记实例写入存储层时是否可以跳过配额检查
+// 存储层的配额检查需要对命名空间加锁并统计全部实例，只有缓存中的实例数接近配额时才需要由存储层精确检查
+func MarkInstanceQuotaCheck(cacheMgn *cache.CacheManager, instances []*model.Instance) {
+	if cacheMgn == nil {
+		return
+	}
+	adds := make(map[string][]*model.Instance)
+	for _, instance := range instances {
+		svc := cacheMgn.Service().GetServiceByID(instance.ServiceID)
+		if svc == nil {
+			continue
+		}
+		adds[svc.Namespace] = append(adds[svc.Namespace], instance)
+	}
+	for name, items := range adds {
+		namespace := cacheMgn.Namespace().GetNamespace(name)
+		if namespace == nil || namespace.InstanceQuota == 0 {
+			continue
+		}
+		total := cacheMgn.Service().GetNamespaceCntInfo(name).InstanceCnt.TotalInstanceCount
+		if !farBelowInstanceQuota(total, uint32(len(items)), namespace.InstanceQuota) {
+			continue
+		}
+		for _, instance := range items {
+			instance.SkipQuotaCheck = true
+		}
+	}
+}
+
+// farBelowInstanceQuota 新增实例之后距离配额是否还有足够的余量
+func farBelowInstanceQuota(total, adds, quota uint32) bool {
+	margin := quota / instanceQuotaMarginRatio
+	if margin < minInstanceQuotaMargin {
+		margin = minInstanceQuotaMargin
+	}
+	return uint64(total)+uint64(adds)+uint64(margin) <= uint64(quota)
+}
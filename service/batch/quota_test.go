@@ -0,0 +1,45 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package batch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_farBelowInstanceQuota(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  uint32
+		adds   uint32
+		quota  uint32
+		expect bool
+	}{
+		{name: "小配额始终由存储层检查", total: 0, adds: 1, quota: 100, expect: false},
+		{name: "余量充足", total: 100, adds: 10, quota: 10000, expect: true},
+		{name: "接近配额", total: 9000, adds: 10, quota: 10000, expect: false},
+		{name: "刚好保留余量", total: 8990, adds: 10, quota: 10000, expect: true},
+		{name: "已经超出配额", total: 20000, adds: 1, quota: 10000, expect: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, farBelowInstanceQuota(tt.total, tt.adds, tt.quota))
+		})
+	}
+}
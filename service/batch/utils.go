@@ -32,6 +32,7 @@ var storeCodeAPICodeMap = map[store.StatusCode]uint32{
 	store.NotFoundTagConfigOrService: api.NotFoundTagConfigOrService,
 	store.ExistReleasedConfig:        api.ExistReleasedConfig,
 	store.DuplicateEntryErr:          api.ExistedResource,
+	store.ExceedNamespaceQuota:       api.NamespaceQuotaExceeded,
 }
 
 // StoreCode2APICode store code to api code
@@ -28,6 +28,7 @@ import (
 
 	api "github.com/polarismesh/polaris/common/api/v1"
 	v2 "github.com/polarismesh/polaris/common/api/v2"
+	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/common/utils"
 )
 
@@ -93,6 +94,12 @@ func TestDiscoverInstances(t *testing.T) {
 			So(resp.Responses[0].GetService().GetMetadata()["new-metadata2"], ShouldEqual, "2342")
 			serviceCheck(t, service, resp.Responses[0].GetService())
 		})
+		Convey("实例保护阈值不合法，修改服务失败", func() {
+			service.Metadata = map[string]string{model.MetaKeyProtectThreshold: "2"}
+			resp := discoverSuit.server.UpdateServices(discoverSuit.defaultCtx, []*api.Service{service})
+			So(respSuccess(resp), ShouldEqual, false)
+			So(resp.Responses[0].GetCode().GetValue(), ShouldEqual, api.InvalidServiceMetadata)
+		})
 	})
 }
 
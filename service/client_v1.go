@@ -239,14 +239,32 @@ func (s *Server) ServiceInstancesCache(ctx context.Context, req *api.Service) *a
 	resp.Service.Namespace = req.GetNamespace()
 	resp.Service.Name = req.GetName() // 别名场景，response需要保持和request的服务名一致
 	// 填充instance数据
-	resp.Instances = make([]*api.Instance, 0) // TODO
+	instances := make([]*model.Instance, 0)
 	_ = s.caches.Instance().
 		IteratorInstancesWithService(service.ID, // service已经是源服务
 			func(key string, value *model.Instance) (b bool, e error) {
-				// 注意：这里的value是cache的，不修改cache的数据，通过getInstance，浅拷贝一份数据
-				resp.Instances = append(resp.Instances, s.getInstance(req, value.Proto))
+				instances = append(instances, value)
 				return true, nil
 			})
+	// 开启本集群实例优先时，本集群实例不可用时才返回从其他集群同步过来的实例
+	if s.isPreferLocal(service) {
+		instances = preferLocalInstances(instances)
+	}
+	instances, engaged := s.protectInstances(service, instances)
+	if engaged {
+		// 注意：service的metadata是cache的，不能直接修改
+		metadata := make(map[string]string, len(service.Meta)+1)
+		for k, v := range service.Meta {
+			metadata[k] = v
+		}
+		metadata[model.MetaKeyProtectionEngaged] = "true"
+		resp.Service.Metadata = metadata
+	}
+	resp.Instances = make([]*api.Instance, 0, len(instances))
+	for _, value := range instances {
+		// 注意：这里的value是cache的，不修改cache的数据，通过getInstance，浅拷贝一份数据
+		resp.Instances = append(resp.Instances, s.getInstance(req, value.Proto))
+	}
 
 	return resp
 }
@@ -444,10 +462,15 @@ func (s *Server) getSourceServiceID(service *model.Service) string {
 }
 
 // 根据服务名获取服务缓存数据
-// 注意，如果是服务别名查询，这里会返回别名的源服务，不会返回别名
+// 注意，如果是服务别名查询，这里会返回别名的源服务，不会返回别名；
+// 命名空间下不存在该服务时，会返回其他命名空间导出到该命名空间的同名服务
 func (s *Server) getServiceCache(name string, namespace string) *model.Service {
 	sc := s.caches.Service()
 	service := sc.GetServiceByName(name, namespace)
+	if service == nil {
+		// 本命名空间下不存在，查找一下其他命名空间导出过来的服务
+		service = sc.GetExportedService(name, namespace)
+	}
 	if service == nil {
 		return nil
 	}
@@ -0,0 +1,127 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package clustersync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	defaultInterval = "30s"
+	defaultTimeout  = "5s"
+)
+
+// Config 多集群服务同步配置
+type Config struct {
+	// 是否开启多集群服务同步
+	Open bool `mapstructure:"open"`
+	// 同步周期
+	Interval string `mapstructure:"interval"`
+	// 执行同步任务的节点地址，需要与节点探测到的本机地址一致，集群部署时只有该节点执行同步，其他节点保持空闲
+	Node string `mapstructure:"node"`
+	// 服务发现时本集群实例优先，本集群还有可用实例时不返回同步过来的实例，服务元数据可以单独开启或者关闭
+	PreferLocal bool `mapstructure:"preferLocal"`
+	// 远端集群列表
+	Remotes []*RemoteConfig `mapstructure:"remotes"`
+}
+
+// RemoteConfig 远端集群配置
+type RemoteConfig struct {
+	// 远端集群名称，会写入同步实例的元数据中，不同远端集群的名称不能相同
+	Name string `mapstructure:"name"`
+	// 远端集群的 gRPC 客户端接入地址，如 127.0.0.1:8091
+	Address string `mapstructure:"address"`
+	// 访问远端集群的超时时间
+	Timeout string `mapstructure:"timeout"`
+	// 远端集群所在的地域信息，同步的实例没有地域信息时使用
+	Region string `mapstructure:"region"`
+	Zone   string `mapstructure:"zone"`
+	Campus string `mapstructure:"campus"`
+	// 需要同步全部服务的命名空间
+	Namespaces []string `mapstructure:"namespaces"`
+	// 需要同步的服务
+	Services []*ServiceConfig `mapstructure:"services"`
+}
+
+// ServiceConfig 需要同步的服务
+type ServiceConfig struct {
+	Namespace string `mapstructure:"namespace"`
+	Name      string `mapstructure:"name"`
+}
+
+// ParseConfig 解析配置文件为config
+func ParseConfig(opt map[string]interface{}) (*Config, error) {
+	config := &Config{Interval: defaultInterval}
+	if opt == nil {
+		return config, nil
+	}
+
+	if err := mapstructure.Decode(opt, config); err != nil {
+		log.Errorf("[ClusterSync] parse config(%+v) err: %s", opt, err.Error())
+		return nil, err
+	}
+	if err := checkConfig(config); err != nil {
+		log.Errorf("[ClusterSync] config is invalid: %s", err.Error())
+		return nil, err
+	}
+	return config, nil
+}
+
+// checkConfig 配置文件校验，并填充默认值
+func checkConfig(config *Config) error {
+	if config.Interval == "" {
+		config.Interval = defaultInterval
+	}
+	if interval, err := time.ParseDuration(config.Interval); err != nil || interval <= 0 {
+		return fmt.Errorf("invalid sync interval: %s", config.Interval)
+	}
+	if !config.Open {
+		return nil
+	}
+	if config.Node == "" {
+		return errors.New("sync node can not be empty")
+	}
+
+	names := make(map[string]struct{}, len(config.Remotes))
+	for _, remote := range config.Remotes {
+		if remote == nil || remote.Name == "" || remote.Address == "" {
+			return errors.New("remote cluster name and address can not be empty")
+		}
+		if _, ok := names[remote.Name]; ok {
+			return fmt.Errorf("duplicate remote cluster name: %s", remote.Name)
+		}
+		names[remote.Name] = struct{}{}
+
+		if remote.Timeout == "" {
+			remote.Timeout = defaultTimeout
+		}
+		if timeout, err := time.ParseDuration(remote.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout of remote cluster %s: %s", remote.Name, remote.Timeout)
+		}
+		for _, svc := range remote.Services {
+			if svc == nil || svc.Namespace == "" || svc.Name == "" {
+				return fmt.Errorf("invalid service of remote cluster %s", remote.Name)
+			}
+		}
+	}
+	return nil
+}
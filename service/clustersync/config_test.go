@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package clustersync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(nil)
+	assert.NoError(t, err)
+	assert.False(t, config.Open)
+	assert.False(t, config.PreferLocal)
+	assert.Equal(t, defaultInterval, config.Interval)
+
+	config, err = ParseConfig(map[string]interface{}{
+		"open":        true,
+		"interval":    "10s",
+		"node":        "127.0.0.1",
+		"preferLocal": true,
+		"remotes": []interface{}{
+			map[string]interface{}{
+				"name":       "cluster-b",
+				"address":    "127.0.0.1:8091",
+				"region":     "region-b",
+				"namespaces": []interface{}{"default"},
+				"services": []interface{}{
+					map[string]interface{}{"namespace": "Production", "name": "echo"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, config.Open)
+	assert.True(t, config.PreferLocal)
+	assert.Equal(t, "10s", config.Interval)
+	assert.Equal(t, "127.0.0.1", config.Node)
+	if assert.Equal(t, 1, len(config.Remotes)) {
+		remote := config.Remotes[0]
+		assert.Equal(t, defaultTimeout, remote.Timeout)
+		assert.Equal(t, "region-b", remote.Region)
+		assert.Equal(t, []string{"default"}, remote.Namespaces)
+		assert.Equal(t, "echo", remote.Services[0].Name)
+	}
+
+	_, err = ParseConfig(map[string]interface{}{"interval": "abc"})
+	assert.Error(t, err)
+
+	_, err = ParseConfig(map[string]interface{}{
+		"open": true,
+		"node": "127.0.0.1",
+		"remotes": []interface{}{
+			map[string]interface{}{"name": "cluster-b", "address": "127.0.0.1:8091"},
+			map[string]interface{}{"name": "cluster-b", "address": "127.0.0.2:8091"},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = ParseConfig(map[string]interface{}{
+		"open":    true,
+		"node":    "127.0.0.1",
+		"remotes": []interface{}{map[string]interface{}{"name": "cluster-b"}},
+	})
+	assert.Error(t, err)
+
+	// 开启同步时必须指定执行同步的节点
+	_, err = ParseConfig(map[string]interface{}{
+		"open":    true,
+		"remotes": []interface{}{map[string]interface{}{"name": "cluster-b", "address": "127.0.0.1:8091"}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package clustersync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// Discoverer 远端集群的服务发现客户端
+type Discoverer interface {
+	// GetServices 获取远端集群命名空间下的服务列表
+	GetServices(ctx context.Context, namespace string) ([]*api.Service, error)
+	// GetInstances 获取远端集群服务下的实例列表，服务不存在时返回空列表
+	GetInstances(ctx context.Context, namespace, service string) ([]*api.Instance, error)
+	// Close 关闭客户端
+	Close() error
+}
+
+// grpcDiscoverer 通过远端集群的 gRPC 客户端接口进行服务发现
+type grpcDiscoverer struct {
+	conn    *grpc.ClientConn
+	client  api.PolarisGRPCClient
+	timeout time.Duration
+}
+
+// newGRPCDiscoverer 创建远端集群的 gRPC 服务发现客户端，连接是异步建立的
+func newGRPCDiscoverer(remote *RemoteConfig) (Discoverer, error) {
+	timeout, err := time.ParseDuration(remote.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(remote.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &grpcDiscoverer{
+		conn:    conn,
+		client:  api.NewPolarisGRPCClient(conn),
+		timeout: timeout,
+	}, nil
+}
+
+// GetServices 获取远端集群命名空间下的服务列表
+func (d *grpcDiscoverer) GetServices(ctx context.Context, namespace string) ([]*api.Service, error) {
+	resp, err := d.discover(ctx, &api.DiscoverRequest{
+		Type:    api.DiscoverRequest_SERVICES,
+		Service: &api.Service{Namespace: utils.NewStringValue(namespace)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		return nil, fmt.Errorf("discover services of namespace %s, code: %d, info: %s",
+			namespace, resp.GetCode().GetValue(), resp.GetInfo().GetValue())
+	}
+	return resp.GetServices(), nil
+}
+
+// GetInstances 获取远端集群服务下的实例列表
+func (d *grpcDiscoverer) GetInstances(ctx context.Context, namespace, service string) ([]*api.Instance, error) {
+	resp, err := d.discover(ctx, &api.DiscoverRequest{
+		Type: api.DiscoverRequest_INSTANCE,
+		Service: &api.Service{
+			Name:      utils.NewStringValue(service),
+			Namespace: utils.NewStringValue(namespace),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch resp.GetCode().GetValue() {
+	case api.ExecuteSuccess:
+		return resp.GetInstances(), nil
+	case api.NotFoundService, api.NotFoundResource:
+		return []*api.Instance{}, nil
+	default:
+		return nil, fmt.Errorf("discover instances of service %s/%s, code: %d, info: %s",
+			namespace, service, resp.GetCode().GetValue(), resp.GetInfo().GetValue())
+	}
+}
+
+// Close 关闭连接
+func (d *grpcDiscoverer) Close() error {
+	return d.conn.Close()
+}
+
+// discover 发送一次发现请求，并等待远端返回应答
+func (d *grpcDiscoverer) discover(ctx context.Context, req *api.DiscoverRequest) (*api.DiscoverResponse, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "request-id", utils.NewUUID())
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	stream, err := d.client.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err == io.EOF {
+		return nil, fmt.Errorf("discover stream closed without response")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
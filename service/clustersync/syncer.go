@@ -0,0 +1,304 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package clustersync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/cache"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	instancecommon "github.com/polarismesh/polaris/common/service"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/service"
+	"github.com/polarismesh/polaris/store"
+)
+
+// ServiceCreator 本集群服务不存在时，用于创建服务
+type ServiceCreator interface {
+	CreateService(ctx context.Context, req *api.Service) *api.Response
+}
+
+// remoteCluster 远端集群
+type remoteCluster struct {
+	cfg        *RemoteConfig
+	discoverer Discoverer
+}
+
+// Syncer 多集群服务同步，定期把远端集群的服务实例镜像到本集群。
+// 同步过来的实例带有来源集群的元数据，在本集群只读，并且不会再被同步到其他集群，避免双向同步时出现环路
+type Syncer struct {
+	interval time.Duration
+	// node 执行同步任务的节点，localHost 为本节点地址，两者不一致时本节点不执行同步
+	node      string
+	localHost string
+	remotes   []*remoteCluster
+	storage   store.Store
+	caches    *cache.CacheManager
+	creator   ServiceCreator
+}
+
+// NewSyncer 创建多集群服务同步器
+func NewSyncer(cfg *Config, storage store.Store, caches *cache.CacheManager,
+	creator ServiceCreator) (*Syncer, error) {
+	interval, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer := &Syncer{
+		interval:  interval,
+		node:      cfg.Node,
+		localHost: utils.LocalHost,
+		storage:   storage,
+		caches:    caches,
+		creator:   creator,
+	}
+	for _, remote := range cfg.Remotes {
+		discoverer, err := newGRPCDiscoverer(remote)
+		if err != nil {
+			log.Errorf("[ClusterSync] create discoverer of remote cluster %s err: %s", remote.Name, err.Error())
+			syncer.close()
+			return nil, err
+		}
+		syncer.remotes = append(syncer.remotes, &remoteCluster{cfg: remote, discoverer: discoverer})
+	}
+	return syncer, nil
+}
+
+// Start 启动同步任务，ctx结束时停止同步并关闭远端连接
+// 只有配置的同步节点执行同步，避免集群内多个节点同时写入同步的实例，其他节点直接关闭远端连接
+func (s *Syncer) Start(ctx context.Context) {
+	if s.node != s.localHost {
+		log.Infof("[ClusterSync] sync node is %s, local host %s skip sync", s.node, s.localHost)
+		s.close()
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		defer s.close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, remote := range s.remotes {
+					s.syncRemote(ctx, remote)
+				}
+			}
+		}
+	}()
+}
+
+// close 关闭全部远端连接
+func (s *Syncer) close() {
+	for _, remote := range s.remotes {
+		_ = remote.discoverer.Close()
+	}
+}
+
+// syncRemote 同步一个远端集群的全部目标服务
+func (s *Syncer) syncRemote(ctx context.Context, remote *remoteCluster) {
+	targets, err := listTargetServices(ctx, remote)
+	if err != nil {
+		log.Error("[ClusterSync] list services of remote cluster", zap.String("remote", remote.cfg.Name),
+			zap.Error(err))
+		return
+	}
+	for _, target := range targets {
+		if err := s.syncService(ctx, remote, target); err != nil {
+			log.Error("[ClusterSync] sync service from remote cluster", zap.String("remote", remote.cfg.Name),
+				zap.String("namespace", target.Namespace), zap.String("service", target.Name), zap.Error(err))
+		}
+	}
+}
+
+// listTargetServices 获取远端集群需要同步的服务，包括命名空间下的全部服务和单独配置的服务
+func listTargetServices(ctx context.Context, remote *remoteCluster) ([]*ServiceConfig, error) {
+	targets := make([]*ServiceConfig, 0, len(remote.cfg.Services))
+	exists := make(map[model.ServiceKey]struct{})
+	appendTarget := func(namespace, name string) {
+		key := model.ServiceKey{Namespace: namespace, Name: name}
+		if _, ok := exists[key]; ok {
+			return
+		}
+		exists[key] = struct{}{}
+		targets = append(targets, &ServiceConfig{Namespace: namespace, Name: name})
+	}
+
+	for _, namespace := range remote.cfg.Namespaces {
+		services, err := remote.discoverer.GetServices(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, svc := range services {
+			appendTarget(svc.GetNamespace().GetValue(), svc.GetName().GetValue())
+		}
+	}
+	for _, svc := range remote.cfg.Services {
+		appendTarget(svc.Namespace, svc.Name)
+	}
+	return targets, nil
+}
+
+// syncService 同步一个服务的实例，远端获取失败时不会删除本集群已经同步的实例
+func (s *Syncer) syncService(ctx context.Context, remote *remoteCluster, target *ServiceConfig) error {
+	remoteInstances, err := remote.discoverer.GetInstances(ctx, target.Namespace, target.Name)
+	if err != nil {
+		return err
+	}
+
+	svc := s.caches.Service().GetServiceByName(target.Name, target.Namespace)
+	if svc == nil {
+		// 远端没有实例的服务，不需要在本集群创建
+		if len(remoteInstances) == 0 {
+			return nil
+		}
+		if svc, err = s.createService(ctx, target); err != nil {
+			return err
+		}
+	}
+	if svc.IsAlias() {
+		return errors.New("local service is alias")
+	}
+
+	localInstances := make([]*model.Instance, 0)
+	_ = s.caches.Instance().IteratorInstancesWithService(svc.ID,
+		func(key string, value *model.Instance) (bool, error) {
+			localInstances = append(localInstances, value)
+			return true, nil
+		})
+
+	adds, deletes := diffInstances(remote.cfg, svc.ID, remoteInstances, localInstances)
+	if len(adds) > 0 {
+		if err := s.storage.BatchAddInstances(adds); err != nil {
+			return err
+		}
+	}
+	if len(deletes) > 0 {
+		if err := s.storage.BatchDeleteInstances(deletes); err != nil {
+			return err
+		}
+	}
+	if len(adds) > 0 || len(deletes) > 0 {
+		log.Info("[ClusterSync] sync instances from remote cluster", zap.String("remote", remote.cfg.Name),
+			zap.String("namespace", target.Namespace), zap.String("service", target.Name),
+			zap.Int("upsert", len(adds)), zap.Int("delete", len(deletes)))
+	}
+	return nil
+}
+
+// createService 本集群不存在的服务，自动创建
+func (s *Syncer) createService(ctx context.Context, target *ServiceConfig) (*model.Service, error) {
+	resp := s.creator.CreateService(ctx, &api.Service{
+		Name:      utils.NewStringValue(target.Name),
+		Namespace: utils.NewStringValue(target.Namespace),
+		Owners:    utils.NewStringValue("Polaris"),
+		Metadata: map[string]string{
+			service.MetadataInternalAutoCreated: "true",
+		},
+	})
+	code := resp.GetCode().GetValue()
+	if code != api.ExecuteSuccess && code != api.ExistedResource {
+		return nil, errors.New(resp.GetInfo().GetValue())
+	}
+	return &model.Service{
+		ID:        resp.GetService().GetId().GetValue(),
+		Name:      target.Name,
+		Namespace: target.Namespace,
+	}, nil
+}
+
+// diffInstances 对比远端实例和本集群实例，返回需要新增或者更新的同步实例，以及需要删除的同步实例ID。
+// 远端本身就是同步过来的实例不会再同步，防止环路；本集群注册的实例以及其他远端集群同步的实例优先，不会被覆盖
+func diffInstances(remote *RemoteConfig, serviceID string, remoteInstances []*api.Instance,
+	localInstances []*model.Instance) ([]*model.Instance, []interface{}) {
+	locals := make(map[string]*model.Instance, len(localInstances))
+	for _, instance := range localInstances {
+		locals[instance.ID()] = instance
+	}
+
+	adds := make([]*model.Instance, 0)
+	seen := make(map[string]struct{}, len(remoteInstances))
+	for _, instance := range remoteInstances {
+		id := instance.GetId().GetValue()
+		if id == "" || instance.GetMetadata()[model.MetaKeySyncSourceCluster] != "" {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if local, ok := locals[id]; ok {
+			if local.SyncSourceCluster() != remote.Name {
+				continue
+			}
+			revision := instance.GetRevision().GetValue()
+			if revision != "" && revision == local.Revision() {
+				continue
+			}
+		}
+		adds = append(adds, mirrorInstance(remote, serviceID, instance))
+	}
+
+	deletes := make([]interface{}, 0)
+	for _, instance := range localInstances {
+		if instance.SyncSourceCluster() != remote.Name {
+			continue
+		}
+		if _, ok := seen[instance.ID()]; !ok {
+			deletes = append(deletes, instance.ID())
+		}
+	}
+	return adds, deletes
+}
+
+// mirrorInstance 生成远端实例在本集群的镜像：保留实例ID和版本号，打上来源集群的标签，并关闭健康检查，
+// 健康状态以远端集群为准
+func mirrorInstance(remote *RemoteConfig, serviceID string, instance *api.Instance) *model.Instance {
+	ins := *instance
+	ins.Metadata = make(map[string]string, len(instance.GetMetadata())+1)
+	for k, v := range instance.GetMetadata() {
+		ins.Metadata[k] = v
+	}
+	ins.Metadata[model.MetaKeySyncSourceCluster] = remote.Name
+	ins.EnableHealthCheck = utils.NewBoolValue(false)
+	ins.HealthCheck = nil
+	if isEmptyLocation(ins.GetLocation()) {
+		ins.Location = &api.Location{
+			Region: utils.NewStringValue(remote.Region),
+			Zone:   utils.NewStringValue(remote.Zone),
+			Campus: utils.NewStringValue(remote.Campus),
+		}
+	}
+
+	data := instancecommon.CreateInstanceModel(serviceID, &ins)
+	if revision := instance.GetRevision().GetValue(); revision != "" {
+		data.Proto.Revision = utils.NewStringValue(revision)
+	}
+	return data
+}
+
+func isEmptyLocation(location *api.Location) bool {
+	return location.GetRegion().GetValue() == "" && location.GetZone().GetValue() == "" &&
+		location.GetCampus().GetValue() == ""
+}
@@ -0,0 +1,193 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package clustersync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+type fakeDiscoverer struct {
+	services map[string][]*api.Service
+	calls    int32
+	closed   int32
+}
+
+func (d *fakeDiscoverer) GetServices(ctx context.Context, namespace string) ([]*api.Service, error) {
+	atomic.AddInt32(&d.calls, 1)
+	return d.services[namespace], nil
+}
+
+func (d *fakeDiscoverer) GetInstances(ctx context.Context, namespace, service string) ([]*api.Instance, error) {
+	return nil, nil
+}
+
+func (d *fakeDiscoverer) Close() error {
+	atomic.StoreInt32(&d.closed, 1)
+	return nil
+}
+
+func mockRemoteInstance(id, revision string, metadata map[string]string) *api.Instance {
+	return &api.Instance{
+		Id:       utils.NewStringValue(id),
+		Host:     utils.NewStringValue("127.0.0.1"),
+		Port:     utils.NewUInt32Value(8080),
+		Healthy:  utils.NewBoolValue(true),
+		Revision: utils.NewStringValue(revision),
+		Metadata: metadata,
+		HealthCheck: &api.HealthCheck{
+			Type:      api.HealthCheck_HEARTBEAT,
+			Heartbeat: &api.HeartbeatHealthCheck{Ttl: utils.NewUInt32Value(5)},
+		},
+	}
+}
+
+func mockLocalInstance(id, revision, cluster string) *model.Instance {
+	metadata := map[string]string{}
+	if cluster != "" {
+		metadata[model.MetaKeySyncSourceCluster] = cluster
+	}
+	return &model.Instance{
+		Proto: &api.Instance{
+			Id:       utils.NewStringValue(id),
+			Revision: utils.NewStringValue(revision),
+			Metadata: metadata,
+		},
+	}
+}
+
+func TestListTargetServices(t *testing.T) {
+	remote := &remoteCluster{
+		cfg: &RemoteConfig{
+			Name:       "cluster-b",
+			Namespaces: []string{"default"},
+			Services: []*ServiceConfig{
+				{Namespace: "default", Name: "svc-a"},
+				{Namespace: "Production", Name: "svc-c"},
+			},
+		},
+		discoverer: &fakeDiscoverer{
+			services: map[string][]*api.Service{
+				"default": {
+					{Namespace: utils.NewStringValue("default"), Name: utils.NewStringValue("svc-a")},
+					{Namespace: utils.NewStringValue("default"), Name: utils.NewStringValue("svc-b")},
+				},
+			},
+		},
+	}
+
+	targets, err := listTargetServices(context.Background(), remote)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ServiceConfig{
+		{Namespace: "default", Name: "svc-a"},
+		{Namespace: "default", Name: "svc-b"},
+		{Namespace: "Production", Name: "svc-c"},
+	}, targets)
+}
+
+func TestDiffInstances(t *testing.T) {
+	remote := &RemoteConfig{Name: "cluster-b", Region: "region-b", Zone: "zone-b"}
+
+	remoteInstances := []*api.Instance{
+		mockRemoteInstance("new", "rev-1", map[string]string{"env": "test"}),
+		mockRemoteInstance("changed", "rev-2", nil),
+		mockRemoteInstance("unchanged", "rev-1", nil),
+		mockRemoteInstance("local", "rev-1", nil),
+		mockRemoteInstance("other-cluster", "rev-1", nil),
+		// 远端也是同步过来的实例，不能再同步回来
+		mockRemoteInstance("loop", "rev-1", map[string]string{model.MetaKeySyncSourceCluster: "cluster-a"}),
+	}
+	localInstances := []*model.Instance{
+		mockLocalInstance("changed", "rev-1", "cluster-b"),
+		mockLocalInstance("unchanged", "rev-1", "cluster-b"),
+		mockLocalInstance("local", "rev-0", ""),
+		mockLocalInstance("other-cluster", "rev-0", "cluster-c"),
+		mockLocalInstance("removed", "rev-1", "cluster-b"),
+		mockLocalInstance("native", "rev-1", ""),
+	}
+
+	adds, deletes := diffInstances(remote, "service-id", remoteInstances, localInstances)
+	assert.Equal(t, []interface{}{"removed"}, deletes)
+	if !assert.Equal(t, 2, len(adds)) {
+		return
+	}
+
+	added := adds[0]
+	assert.Equal(t, "new", added.ID())
+	assert.Equal(t, "service-id", added.ServiceID)
+	assert.Equal(t, "rev-1", added.Revision())
+	assert.Equal(t, "cluster-b", added.SyncSourceCluster())
+	assert.Equal(t, "test", added.Metadata()["env"])
+	assert.False(t, added.EnableHealthCheck())
+	assert.Nil(t, added.HealthCheck())
+	assert.Equal(t, "region-b", added.Location().GetRegion().GetValue())
+	assert.Equal(t, "zone-b", added.Location().GetZone().GetValue())
+	// 不能修改远端实例的数据
+	assert.Empty(t, remoteInstances[0].GetMetadata()[model.MetaKeySyncSourceCluster])
+
+	assert.Equal(t, "changed", adds[1].ID())
+	assert.Equal(t, "rev-2", adds[1].Revision())
+}
+
+func TestSyncer_Start(t *testing.T) {
+	newSyncer := func(node string) (*Syncer, *fakeDiscoverer) {
+		discoverer := &fakeDiscoverer{}
+		return &Syncer{
+			interval:  10 * time.Millisecond,
+			node:      node,
+			localHost: "127.0.0.1",
+			remotes: []*remoteCluster{{
+				cfg:        &RemoteConfig{Name: "cluster-b", Namespaces: []string{"default"}},
+				discoverer: discoverer,
+			}},
+		}, discoverer
+	}
+
+	t.Run("非同步节点保持空闲", func(t *testing.T) {
+		syncer, discoverer := newSyncer("127.0.0.2")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		syncer.Start(ctx)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&discoverer.calls))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&discoverer.closed))
+	})
+
+	t.Run("同步节点定期同步", func(t *testing.T) {
+		syncer, discoverer := newSyncer("127.0.0.1")
+		ctx, cancel := context.WithCancel(context.Background())
+
+		syncer.Start(ctx)
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&discoverer.calls) > 0
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&discoverer.closed) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -59,6 +59,7 @@ var (
 type Config struct {
 	Auth  map[string]interface{} `yaml:"auth"`
 	Batch map[string]interface{} `yaml:"batch"`
+	Sync  map[string]interface{} `yaml:"sync"`
 }
 
 // Initialize 初始化
@@ -136,9 +136,8 @@ const (
 )
 
 func (c *CheckScheduler) doAdopt(ctx context.Context) {
-	instancesToAdd := make(map[string]bool)
-	instancesToRemove := make(map[string]bool)
-	var checker plugin.HealthChecker
+	instancesToAdd := make(map[string]plugin.HealthChecker)
+	instancesToRemove := make(map[string]plugin.HealthChecker)
 	ticker := time.NewTicker(batchAdoptInterval)
 	defer func() {
 		ticker.Stop()
@@ -148,25 +147,24 @@ func (c *CheckScheduler) doAdopt(ctx context.Context) {
 		case event := <-c.adoptInstancesChan:
 			instanceId := event.InstanceId
 			if event.Add {
-				instancesToAdd[instanceId] = true
+				instancesToAdd[instanceId] = event.Checker
 				delete(instancesToRemove, instanceId)
 			} else {
-				instancesToRemove[instanceId] = true
+				instancesToRemove[instanceId] = event.Checker
 				delete(instancesToAdd, instanceId)
 			}
-			checker = event.Checker
 			if len(instancesToAdd) == batchAdoptCount {
-				instancesToAdd = c.processAdoptEvents(instancesToAdd, true, checker)
+				instancesToAdd = c.processAdoptEvents(instancesToAdd, true)
 			}
 			if len(instancesToRemove) == batchAdoptCount {
-				instancesToRemove = c.processAdoptEvents(instancesToRemove, false, checker)
+				instancesToRemove = c.processAdoptEvents(instancesToRemove, false)
 			}
 		case <-ticker.C:
 			if len(instancesToAdd) > 0 {
-				instancesToAdd = c.processAdoptEvents(instancesToAdd, true, checker)
+				instancesToAdd = c.processAdoptEvents(instancesToAdd, true)
 			}
 			if len(instancesToRemove) > 0 {
-				instancesToRemove = c.processAdoptEvents(instancesToRemove, false, checker)
+				instancesToRemove = c.processAdoptEvents(instancesToRemove, false)
 			}
 		case <-ctx.Done():
 			log.Infof("[Health Check][Check]adopting routine has been stopped")
@@ -175,32 +173,38 @@ func (c *CheckScheduler) doAdopt(ctx context.Context) {
 	}
 }
 
+// processAdoptEvents instances may belong to different checkers, adopt them by checker
 func (c *CheckScheduler) processAdoptEvents(
-	instances map[string]bool, add bool, checker plugin.HealthChecker) map[string]bool {
-	instanceIds := make([]string, 0, len(instances))
-	for id := range instances {
-		instanceIds = append(instanceIds, id)
-	}
-	var err error
-	if add {
-		log.Infof("[Health Check][Check]add adopting instances, ids are %v", instanceIds)
-		err = checker.AddToCheck(&plugin.AddCheckRequest{
-			Instances: instanceIds,
-			LocalHost: server.localHost,
-		})
-	} else {
-		log.Infof("[Health Check][Check]remove adopting instances, ids are %v", instanceIds)
-		err = checker.RemoveFromCheck(&plugin.AddCheckRequest{
-			Instances: instanceIds,
-			LocalHost: server.localHost,
-		})
-	}
-	if err != nil {
-		log.Errorf("[Health Check][Check]fail to do adopt event, instances %v, localhost %s, add %v",
-			instanceIds, server.localHost, add)
-		return instances
+	instances map[string]plugin.HealthChecker, add bool) map[string]plugin.HealthChecker {
+	instanceIdsByChecker := make(map[plugin.HealthChecker][]string)
+	for id, checker := range instances {
+		instanceIdsByChecker[checker] = append(instanceIdsByChecker[checker], id)
+	}
+	failed := make(map[string]plugin.HealthChecker)
+	for checker, instanceIds := range instanceIdsByChecker {
+		var err error
+		if add {
+			log.Infof("[Health Check][Check]add adopting instances, ids are %v", instanceIds)
+			err = checker.AddToCheck(&plugin.AddCheckRequest{
+				Instances: instanceIds,
+				LocalHost: server.localHost,
+			})
+		} else {
+			log.Infof("[Health Check][Check]remove adopting instances, ids are %v", instanceIds)
+			err = checker.RemoveFromCheck(&plugin.AddCheckRequest{
+				Instances: instanceIds,
+				LocalHost: server.localHost,
+			})
+		}
+		if err != nil {
+			log.Errorf("[Health Check][Check]fail to do adopt event, instances %v, localhost %s, add %v",
+				instanceIds, server.localHost, add)
+			for _, id := range instanceIds {
+				failed[id] = checker
+			}
+		}
 	}
-	return make(map[string]bool)
+	return failed
 }
 
 func (c *CheckScheduler) addAdopting(instanceId string, checker plugin.HealthChecker) {
@@ -309,6 +313,10 @@ func (c *CheckScheduler) AddClient(clientWithChecker *ClientWithChecker) {
 
 func getExpireDurationSec(instance *api.Instance) uint32 {
 	ttlValue := instance.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
+	if plugin.HealthCheckType(instance.GetHealthCheck().GetType()).IsActiveProbe() {
+		// active probe is triggered by server, ttl is the probe interval
+		return ttlValue
+	}
 	return expireTtlCount * ttlValue
 }
 
@@ -452,6 +460,7 @@ func (c *CheckScheduler) checkCallbackInstance(value interface{}) {
 		},
 		CurTimeSec:        currentTimeSec,
 		ExpireDurationSec: instanceValue.expireDurationSec,
+		Metadata:          cachedInstance.Metadata(),
 	}
 	checkResp, err = instanceValue.checker.Check(request)
 	if err != nil {
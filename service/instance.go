@@ -31,6 +31,7 @@ import (
 	"github.com/polarismesh/polaris/common/model"
 	instancecommon "github.com/polarismesh/polaris/common/service"
 	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/service/batch"
 )
 
 var (
@@ -94,6 +95,10 @@ func (s *Server) CreateInstance(ctx context.Context, req *api.Instance) *api.Res
 			ZapRequestID(rid), ZapPlatformID(pid), ZapInstanceID(instanceID))
 		return api.NewInstanceResponse(api.InstanceTooManyRequests, req)
 	}
+	// 从其他集群同步过来的实例只读，不允许在本集群重新注册覆盖
+	if s.isSyncedInstance(instanceID) {
+		return api.NewInstanceResponse(api.NotAllowModifySyncedInstance, req)
+	}
 
 	// 防止污染req，拷贝一份出来，并且填充一下token ID
 	ins := *req
@@ -178,12 +183,17 @@ func (s *Server) serialCreateInstance(ctx context.Context, svcId string, req *ap
 		log.Error("[Instance] get instance from store", ZapRequestID(rid), ZapPlatformID(pid), zap.Error(err))
 		return nil, api.NewInstanceResponse(api.StoreLayerException, req)
 	}
+	// 缓存可能还没有加载同步过来的实例，以存储层的数据为准
+	if instance != nil && instance.IsSynced() {
+		return nil, api.NewInstanceResponse(api.NotAllowModifySyncedInstance, req)
+	}
 	// 如果存在，则替换实例的属性数据，但是需要保留用户设置的隔离状态，以免出现关键状态丢失
 	if instance != nil && ins.Isolate == nil {
 		ins.Isolate = instance.Proto.Isolate
 	}
 	// 直接同步创建服务实例
 	data := instancecommon.CreateInstanceModel(svcId, ins)
+	batch.MarkInstanceQuotaCheck(s.caches, []*model.Instance{data})
 	if err := s.storage.AddInstance(data); err != nil {
 		log.Error(err.Error(), ZapRequestID(rid), ZapPlatformID(pid))
 		return nil, wrapperInstanceStoreResponse(req, err)
@@ -217,6 +227,11 @@ func (s *Server) DeleteInstance(ctx context.Context, req *api.Instance) *api.Res
 		return api.NewInstanceResponse(api.InstanceTooManyRequests, req)
 	}
 
+	// 从其他集群同步过来的实例只读
+	if s.isSyncedInstance(instanceID) {
+		return api.NewInstanceResponse(api.NotAllowModifySyncedInstance, req)
+	}
+
 	ins := *req // 防止污染外部的req
 	ins.Id = utils.NewStringValue(instanceID)
 	ins.ServiceToken = utils.NewStringValue(parseInstanceReqToken(ctx, req))
@@ -376,6 +391,9 @@ func (s *Server) UpdateInstance(ctx context.Context, req *api.Instance) *api.Res
 	if preErr != nil {
 		return preErr
 	}
+	if instance.IsSynced() {
+		return api.NewInstanceResponse(api.NotAllowModifySyncedInstance, req)
+	}
 	if err := checkMetadata(req.GetMetadata()); err != nil {
 		return api.NewInstanceResponse(api.InvalidMetadata, req)
 	}
@@ -532,13 +550,13 @@ func (s *Server) getInstancesMainByService(ctx context.Context, req *api.Instanc
 		return nil, nil, api.NewInstanceResponse(api.NotFoundService, req)
 	}
 
-	// 获取服务实例
+	// 获取服务实例，从其他集群同步过来的实例只读，不参与按host的批量操作
 	instances, err := s.storage.GetInstancesMainByService(service.ID, req.GetHost().GetValue())
 	if err != nil {
 		log.Error(err.Error(), ZapRequestID(requestID), ZapPlatformID(platformID))
 		return nil, nil, api.NewInstanceResponse(api.StoreLayerException, req)
 	}
-	return instances, service, nil
+	return s.excludeSyncedInstances(instances), service, nil
 }
 
 /**
@@ -650,12 +668,13 @@ func updateHealthCheck(req *api.Instance, instance *model.Instance) bool {
 			// ttl有变更
 			needUpdate = true
 		}
-		if api.HealthCheck_HEARTBEAT != instance.HealthCheck().GetType() {
+		checkType := instancecommon.HealthCheckType(req.GetHealthCheck())
+		if checkType != instance.HealthCheck().GetType() {
 			// health check type有变更
 			needUpdate = true
 		}
 		insProto.HealthCheck = req.GetHealthCheck()
-		insProto.HealthCheck.Type = api.HealthCheck_HEARTBEAT
+		insProto.HealthCheck.Type = checkType
 		if insProto.HealthCheck.Heartbeat.Ttl == nil {
 			insProto.HealthCheck.Heartbeat.Ttl = utils.NewUInt32Value(0)
 		}
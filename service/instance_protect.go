@@ -0,0 +1,125 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+var (
+	errInvalidProtectThreshold = errors.New("protect threshold must be a number between 0 and 1")
+)
+
+// parseProtectThreshold 解析服务的实例保护阈值，没有设置时返回 0，表示不开启实例保护
+func parseProtectThreshold(meta map[string]string) (float64, error) {
+	value, ok := meta[model.MetaKeyProtectThreshold]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return 0, errInvalidProtectThreshold
+	}
+	return threshold, nil
+}
+
+// checkProtectThreshold 检查服务元数据中的实例保护阈值
+func checkProtectThreshold(meta map[string]string) error {
+	_, err := parseProtectThreshold(meta)
+	return err
+}
+
+// protectInstances 开启了实例保护的服务，健康实例的比例低于保护阈值时，返回全部没有隔离的实例，
+// 其中不健康的实例以健康状态返回，避免客户端过滤后流量集中到少量实例或者没有实例可用；
+// 没有触发实例保护时原样返回实例列表，不改变返回结果
+func (s *Server) protectInstances(service *model.Service,
+	instances []*model.Instance) ([]*model.Instance, bool) {
+	threshold, _ := parseProtectThreshold(service.Meta)
+	if threshold <= 0 {
+		return instances, false
+	}
+
+	available := make([]*model.Instance, 0, len(instances))
+	var healthy int
+	for _, instance := range instances {
+		if instance.Isolate() {
+			continue
+		}
+		available = append(available, instance)
+		if instance.Healthy() {
+			healthy++
+		}
+	}
+
+	engaged := len(available) != 0 && float64(healthy)/float64(len(available)) < threshold
+	s.onProtectionChanged(service, engaged)
+	if !engaged {
+		return instances, false
+	}
+	for i, instance := range available {
+		if !instance.Healthy() {
+			available[i] = healthyInstanceCopy(instance)
+		}
+	}
+	return available, true
+}
+
+// healthyInstanceCopy 浅拷贝一份健康状态的实例，instance 是 cache 的数据，不能直接修改
+func healthyInstanceCopy(instance *model.Instance) *model.Instance {
+	ret := *instance
+	proto := *instance.Proto
+	proto.Healthy = utils.NewBoolValue(true)
+	ret.Proto = &proto
+	return &ret
+}
+
+// onProtectionChanged 服务的实例保护状态发生变化时记录服务事件
+func (s *Server) onProtectionChanged(service *model.Service, engaged bool) {
+	var changed bool
+	if engaged {
+		_, loaded := s.protectedServices.LoadOrStore(service.ID, struct{}{})
+		changed = !loaded
+	} else {
+		_, changed = s.protectedServices.LoadAndDelete(service.ID)
+	}
+	if !changed {
+		return
+	}
+
+	eventType := model.EventServiceProtectionReleased
+	if engaged {
+		eventType = model.EventServiceProtectionEngaged
+	}
+	log.Warnf("[Server][Service][Instance] service(%s) namespace(%s) instance protection: %s",
+		service.Name, service.Namespace, eventType)
+
+	if s.healthServer == nil {
+		return
+	}
+	s.healthServer.PublishDiscoverEvent(service.ID, model.DiscoverEvent{
+		Namespace:  service.Namespace,
+		Service:    service.Name,
+		EType:      eventType,
+		CreateTime: time.Now(),
+	})
+}
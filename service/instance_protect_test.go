@@ -0,0 +1,134 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func mockProtectInstances(healthy, unhealthy, isolated int) []*model.Instance {
+	instances := make([]*model.Instance, 0, healthy+unhealthy+isolated)
+	add := func(count int, isHealthy, isIsolate bool) {
+		for i := 0; i < count; i++ {
+			instances = append(instances, &model.Instance{
+				Proto: &api.Instance{
+					Id:      utils.NewStringValue(fmt.Sprintf("ins-%d", len(instances))),
+					Healthy: utils.NewBoolValue(isHealthy),
+					Isolate: utils.NewBoolValue(isIsolate),
+				},
+			})
+		}
+	}
+	add(healthy, true, false)
+	add(unhealthy, false, false)
+	add(isolated, true, true)
+	return instances
+}
+
+func TestParseProtectThreshold(t *testing.T) {
+	threshold, err := parseProtectThreshold(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), threshold)
+
+	threshold, err = parseProtectThreshold(map[string]string{model.MetaKeyProtectThreshold: "0.5"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, threshold)
+
+	assert.Error(t, checkProtectThreshold(map[string]string{model.MetaKeyProtectThreshold: "1.5"}))
+	assert.Error(t, checkProtectThreshold(map[string]string{model.MetaKeyProtectThreshold: "half"}))
+}
+
+func TestProtectInstances(t *testing.T) {
+	s := &Server{}
+	service := &model.Service{
+		ID:        "protect-service",
+		Name:      "protect-service",
+		Namespace: "protect-namespace",
+		Meta:      map[string]string{model.MetaKeyProtectThreshold: "0.5"},
+	}
+
+	t.Run("没有开启实例保护返回全部实例", func(t *testing.T) {
+		instances := mockProtectInstances(1, 3, 1)
+		ret, engaged := s.protectInstances(&model.Service{ID: "other"}, instances)
+		assert.False(t, engaged)
+		assert.Equal(t, 5, len(ret))
+	})
+
+	t.Run("健康比例高于阈值原样返回实例列表", func(t *testing.T) {
+		instances := mockProtectInstances(3, 1, 1)
+		ret, engaged := s.protectInstances(service, instances)
+		assert.False(t, engaged)
+		assert.Equal(t, instances, ret)
+	})
+
+	t.Run("没有未隔离实例时不触发实例保护", func(t *testing.T) {
+		instances := mockProtectInstances(0, 0, 2)
+		ret, engaged := s.protectInstances(service, instances)
+		assert.False(t, engaged)
+		assert.Equal(t, instances, ret)
+	})
+
+	t.Run("健康比例低于阈值返回全部未隔离实例", func(t *testing.T) {
+		instances := mockProtectInstances(1, 3, 1)
+		ret, engaged := s.protectInstances(service, instances)
+		assert.True(t, engaged)
+		assert.Equal(t, 4, len(ret))
+		for _, instance := range ret {
+			assert.False(t, instance.Isolate())
+		}
+
+		// 客户端只使用健康的实例，不健康的实例需要以健康状态返回
+		var clientHealthy int
+		for _, instance := range ret {
+			if s.getInstance(&api.Service{}, instance.Proto).GetHealthy().GetValue() {
+				clientHealthy++
+			}
+		}
+		assert.Equal(t, 4, clientHealthy)
+
+		// 不修改 cache 中的实例
+		var cacheHealthy int
+		for _, instance := range instances {
+			if !instance.Isolate() && instance.Healthy() {
+				cacheHealthy++
+			}
+		}
+		assert.Equal(t, 1, cacheHealthy)
+		_, ok := s.protectedServices.Load(service.ID)
+		assert.True(t, ok)
+
+		// 没有健康实例时同样返回全部未隔离实例
+		ret, engaged = s.protectInstances(service, mockProtectInstances(0, 2, 1))
+		assert.True(t, engaged)
+		assert.Equal(t, 2, len(ret))
+	})
+
+	t.Run("健康比例恢复后解除实例保护", func(t *testing.T) {
+		_, engaged := s.protectInstances(service, mockProtectInstances(3, 1, 0))
+		assert.False(t, engaged)
+		_, ok := s.protectedServices.Load(service.ID)
+		assert.False(t, ok)
+	})
+}
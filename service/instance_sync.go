@@ -0,0 +1,83 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// isSyncedInstance 判断实例是否为从其他集群同步过来的实例，同步实例在本集群只读
+func (s *Server) isSyncedInstance(instanceID string) bool {
+	instance := s.caches.Instance().GetInstance(instanceID)
+	if instance == nil {
+		return false
+	}
+	return instance.IsSynced()
+}
+
+// excludeSyncedInstances 按照host批量操作实例时，跳过从其他集群同步过来的实例
+func (s *Server) excludeSyncedInstances(instances []*model.Instance) []*model.Instance {
+	if len(instances) == 0 {
+		return instances
+	}
+	out := make([]*model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if s.isSyncedInstance(instance.ID()) {
+			continue
+		}
+		out = append(out, instance)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// isPreferLocal 服务发现时是否本集群实例优先，服务元数据的配置优先于全局配置
+func (s *Server) isPreferLocal(service *model.Service) bool {
+	if value, ok := service.Meta[model.MetaKeyPreferLocalInstances]; ok {
+		if preferLocal, err := strconv.ParseBool(value); err == nil {
+			return preferLocal
+		}
+	}
+	return s.preferLocal
+}
+
+// preferLocalInstances 服务同时存在本集群注册的实例和从其他集群同步过来的实例时，
+// 只要本集群还有健康且未隔离的实例，就只返回本集群的实例；否则返回全部实例，由客户端故障转移到远端集群
+func preferLocalInstances(instances []*model.Instance) []*model.Instance {
+	local := make([]*model.Instance, 0, len(instances))
+	hasSynced := false
+	hasAvailable := false
+	for _, instance := range instances {
+		if instance.IsSynced() {
+			hasSynced = true
+			continue
+		}
+		if instance.Healthy() && !instance.Isolate() {
+			hasAvailable = true
+		}
+		local = append(local, instance)
+	}
+	if !hasSynced || !hasAvailable {
+		return instances
+	}
+	return local
+}
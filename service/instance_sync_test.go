@@ -0,0 +1,180 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	instancecommon "github.com/polarismesh/polaris/common/service"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func mockSyncInstance(id string, healthy bool, cluster string) *model.Instance {
+	metadata := map[string]string{}
+	if cluster != "" {
+		metadata[model.MetaKeySyncSourceCluster] = cluster
+	}
+	return &model.Instance{
+		Proto: &api.Instance{
+			Id:       utils.NewStringValue(id),
+			Healthy:  utils.NewBoolValue(healthy),
+			Isolate:  utils.NewBoolValue(false),
+			Metadata: metadata,
+		},
+	}
+}
+
+func TestPreferLocalInstances(t *testing.T) {
+	t.Run("没有同步实例返回全部实例", func(t *testing.T) {
+		instances := []*model.Instance{mockSyncInstance("a", true, ""), mockSyncInstance("b", false, "")}
+		assert.Equal(t, 2, len(preferLocalInstances(instances)))
+	})
+
+	t.Run("本集群有可用实例只返回本集群实例", func(t *testing.T) {
+		instances := []*model.Instance{
+			mockSyncInstance("a", true, ""),
+			mockSyncInstance("b", false, ""),
+			mockSyncInstance("c", true, "cluster-b"),
+		}
+		ret := preferLocalInstances(instances)
+		assert.Equal(t, 2, len(ret))
+		for _, instance := range ret {
+			assert.False(t, instance.IsSynced())
+		}
+	})
+
+	t.Run("本集群没有可用实例返回全部实例", func(t *testing.T) {
+		instances := []*model.Instance{
+			mockSyncInstance("a", false, ""),
+			mockSyncInstance("c", true, "cluster-b"),
+		}
+		assert.Equal(t, 2, len(preferLocalInstances(instances)))
+	})
+}
+
+// 测试从其他集群同步过来的实例只读，并且本集群实例不可用时才会被发现
+func TestSyncedInstance(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	serviceReq := genMainService(910)
+	discoverSuit.cleanServiceName(serviceReq.GetName().GetValue(), serviceReq.GetNamespace().GetValue())
+	resp := discoverSuit.server.CreateServices(discoverSuit.defaultCtx, []*api.Service{serviceReq})
+	if !respSuccess(resp) {
+		t.Fatalf("error: %s", resp.GetInfo().GetValue())
+	}
+	serviceResp := resp.Responses[0].GetService()
+	defer discoverSuit.cleanServiceName(serviceReq.GetName().GetValue(), serviceReq.GetNamespace().GetValue())
+
+	addInstance := func(id, host, cluster string) {
+		ins := &api.Instance{
+			Id:                utils.NewStringValue(id),
+			Host:              utils.NewStringValue(host),
+			Port:              utils.NewUInt32Value(8080),
+			Healthy:           utils.NewBoolValue(true),
+			EnableHealthCheck: utils.NewBoolValue(false),
+			Metadata:          map[string]string{},
+		}
+		if cluster != "" {
+			ins.Metadata[model.MetaKeySyncSourceCluster] = cluster
+		}
+		if err := discoverSuit.storage.AddInstance(
+			instancecommon.CreateInstanceModel(serviceResp.GetId().GetValue(), ins)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	discover := func() []*api.Instance {
+		discoverResp := discoverSuit.server.ServiceInstancesCache(discoverSuit.defaultCtx, serviceReq)
+		if !respSuccess(discoverResp) {
+			t.Fatalf("error: %s", discoverResp.GetInfo().GetValue())
+		}
+		return discoverResp.GetInstances()
+	}
+
+	syncedID := "synced-instance-910"
+	addInstance(syncedID, "10.10.10.1", "cluster-b")
+	defer discoverSuit.cleanInstance(syncedID)
+	time.Sleep(discoverSuit.updateCacheInterval)
+
+	t.Run("同步的实例不允许修改和删除", func(t *testing.T) {
+		req := &api.Instance{
+			Id:           utils.NewStringValue(syncedID),
+			ServiceToken: serviceResp.GetToken(),
+			Weight:       utils.NewUInt32Value(50),
+		}
+		updateResp := discoverSuit.server.UpdateInstances(discoverSuit.defaultCtx, []*api.Instance{req})
+		assert.Equal(t, api.NotAllowModifySyncedInstance, updateResp.Responses[0].GetCode().GetValue())
+
+		deleteResp := discoverSuit.server.DeleteInstances(discoverSuit.defaultCtx, []*api.Instance{req})
+		assert.Equal(t, api.NotAllowModifySyncedInstance, deleteResp.Responses[0].GetCode().GetValue())
+
+		createResp := discoverSuit.server.CreateInstances(discoverSuit.defaultCtx, []*api.Instance{{
+			Id:           utils.NewStringValue(syncedID),
+			Service:      serviceResp.GetName(),
+			Namespace:    serviceResp.GetNamespace(),
+			ServiceToken: serviceResp.GetToken(),
+			Host:         utils.NewStringValue("10.10.10.1"),
+			Port:         utils.NewUInt32Value(8080),
+		}})
+		assert.Equal(t, api.NotAllowModifySyncedInstance, createResp.Responses[0].GetCode().GetValue())
+	})
+
+	t.Run("本集群没有实例时返回同步的实例", func(t *testing.T) {
+		instances := discover()
+		if assert.Equal(t, 1, len(instances)) {
+			assert.Equal(t, syncedID, instances[0].GetId().GetValue())
+			assert.Equal(t, "cluster-b", instances[0].GetMetadata()[model.MetaKeySyncSourceCluster])
+		}
+	})
+
+	localID := "local-instance-910"
+	addInstance(localID, "10.10.10.2", "")
+	defer discoverSuit.cleanInstance(localID)
+	time.Sleep(discoverSuit.updateCacheInterval)
+
+	t.Run("未开启本集群实例优先时返回全部实例", func(t *testing.T) {
+		assert.Equal(t, 2, len(discover()))
+	})
+
+	t.Run("服务开启本集群实例优先后只返回本集群实例", func(t *testing.T) {
+		updateReq := &api.Service{
+			Name:      serviceResp.GetName(),
+			Namespace: serviceResp.GetNamespace(),
+			Token:     serviceResp.GetToken(),
+			Metadata:  map[string]string{model.MetaKeyPreferLocalInstances: "true"},
+		}
+		updateResp := discoverSuit.server.UpdateServices(discoverSuit.defaultCtx, []*api.Service{updateReq})
+		if !respSuccess(updateResp) {
+			t.Fatalf("error: %s", updateResp.GetInfo().GetValue())
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		instances := discover()
+		if assert.Equal(t, 1, len(instances)) {
+			assert.Equal(t, localID, instances[0].GetId().GetValue())
+		}
+	})
+}
@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// 测试命名空间的资源配额
+func TestNamespaceQuota(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	namespaceReq := &api.Namespace{
+		Name:          utils.NewStringValue("namespace-quota-test"),
+		Owners:        utils.NewStringValue("owner-quota"),
+		ServiceQuota:  utils.NewUInt32Value(2),
+		InstanceQuota: utils.NewUInt32Value(2),
+	}
+	discoverSuit.cleanNamespace(namespaceReq.GetName().GetValue())
+	resp := discoverSuit.namespaceSvr.CreateNamespace(discoverSuit.defaultCtx, namespaceReq)
+	if !respSuccess(resp) {
+		t.Fatalf("error: %s", resp.GetInfo().GetValue())
+	}
+	defer discoverSuit.cleanNamespace(namespaceReq.GetName().GetValue())
+
+	genQuotaService := func(id int) *api.Service {
+		return &api.Service{
+			Name:      utils.NewStringValue(fmt.Sprintf("quota-service-%d", id)),
+			Namespace: namespaceReq.GetName(),
+			Owners:    utils.NewStringValue("owner-quota"),
+		}
+	}
+
+	services := make([]*api.Service, 0, 2)
+	t.Run("服务数量达到配额后，无法继续创建服务", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			serviceReq := genQuotaService(i)
+			discoverSuit.cleanServiceName(serviceReq.GetName().GetValue(), serviceReq.GetNamespace().GetValue())
+			resp := discoverSuit.server.CreateServices(discoverSuit.defaultCtx, []*api.Service{serviceReq})
+			if !respSuccess(resp) {
+				t.Fatalf("error: %s", resp.GetInfo().GetValue())
+			}
+			services = append(services, resp.Responses[0].GetService())
+		}
+
+		serviceReq := genQuotaService(2)
+		discoverSuit.cleanServiceName(serviceReq.GetName().GetValue(), serviceReq.GetNamespace().GetValue())
+		resp := discoverSuit.server.CreateServices(discoverSuit.defaultCtx, []*api.Service{serviceReq})
+		if resp.Responses[0].GetCode().GetValue() != api.NamespaceQuotaExceeded {
+			t.Fatalf("error: %+v", resp)
+		}
+	})
+	defer func() {
+		for i := 0; i < 3; i++ {
+			discoverSuit.cleanServiceName(fmt.Sprintf("quota-service-%d", i), namespaceReq.GetName().GetValue())
+		}
+	}()
+
+	t.Run("实例数量达到配额后，无法继续注册新实例", func(t *testing.T) {
+		if len(services) == 0 {
+			t.Skip("services not created")
+		}
+		time.Sleep(discoverSuit.updateCacheInterval)
+		for i := 0; i < 2; i++ {
+			_, instanceResp := discoverSuit.createCommonInstance(t, services[0], 100+i)
+			defer discoverSuit.cleanInstance(instanceResp.GetId().GetValue())
+		}
+		// 等待缓存中的实例数量刷新
+		time.Sleep(discoverSuit.updateCacheInterval * 2)
+
+		instanceReq := &api.Instance{
+			ServiceToken: services[0].GetToken(),
+			Service:      services[0].GetName(),
+			Namespace:    services[0].GetNamespace(),
+			Host:         utils.NewStringValue("9.9.9.200"),
+			Port:         utils.NewUInt32Value(8200),
+		}
+		resp := discoverSuit.server.CreateInstances(discoverSuit.defaultCtx, []*api.Instance{instanceReq})
+		if resp.Responses[0].GetCode().GetValue() != api.NamespaceQuotaExceeded {
+			t.Fatalf("error: %+v", resp)
+		}
+	})
+
+	t.Run("查询命名空间，可以返回资源配额", func(t *testing.T) {
+		resp := discoverSuit.namespaceSvr.GetNamespaces(discoverSuit.defaultCtx, map[string][]string{
+			"name": {namespaceReq.GetName().GetValue()},
+		})
+		if !respSuccess(resp) {
+			t.Fatalf("error: %s", resp.GetInfo().GetValue())
+		}
+		if len(resp.GetNamespaces()) != 1 {
+			t.Fatalf("error: %+v", resp)
+		}
+		namespace := resp.GetNamespaces()[0]
+		if namespace.GetServiceQuota().GetValue() != 2 || namespace.GetInstanceQuota().GetValue() != 2 ||
+			namespace.GetConfigFileQuota().GetValue() != 0 {
+			t.Fatalf("error: %+v", namespace)
+		}
+	})
+
+	t.Run("调大配额后，可以继续创建服务", func(t *testing.T) {
+		updateReq := &api.Namespace{
+			Name:         namespaceReq.GetName(),
+			ServiceQuota: utils.NewUInt32Value(0),
+		}
+		discoverSuit.updateCommonNamespaces(t, []*api.Namespace{updateReq})
+
+		resp := discoverSuit.server.CreateServices(discoverSuit.defaultCtx, []*api.Service{genQuotaService(2)})
+		if !respSuccess(resp) {
+			t.Fatalf("error: %s", resp.GetInfo().GetValue())
+		}
+	})
+}
@@ -61,6 +61,13 @@ func WithBatchController(c *batch.Controller) InitOption {
 	}
 }
 
+// WithPreferLocalInstances 服务发现时本集群实例优先
+func WithPreferLocalInstances(preferLocal bool) InitOption {
+	return func(s *Server) {
+		s.preferLocal = preferLocal
+	}
+}
+
 func WithHiddenService(c map[model.ServiceKey]struct{}) InitOption {
 	return func(s *Server) {
 		s.polarisServiceSet = c
@@ -19,6 +19,7 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/singleflight"
@@ -58,6 +59,12 @@ type Server struct {
 	hooks []ResourceHook
 
 	polarisServiceSet map[model.ServiceKey]struct{}
+
+	// protectedServices 触发了实例保护的服务，service id -> struct{}
+	protectedServices sync.Map
+
+	// preferLocal 服务发现时本集群实例优先，服务元数据 MetaKeyPreferLocalInstances 可以覆盖该配置
+	preferLocal bool
 }
 
 // HealthServer 健康检查Server
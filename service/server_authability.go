@@ -242,6 +242,34 @@ func (svr *serverAuthAbility) queryServiceResource(
 	return ret
 }
 
+// queryServiceExportResource 收集服务新增导出的目标命名空间，导出服务需要拥有目标命名空间的权限
+func (svr *serverAuthAbility) queryServiceExportResource(req []*api.Service) []model.ResourceEntry {
+	names := utils.NewStringSet()
+	for index := range req {
+		if req[index].GetMetadata() == nil {
+			continue
+		}
+		namespace := req[index].GetNamespace().GetValue()
+		oldService := svr.Cache().Service().GetServiceByName(req[index].GetName().GetValue(), namespace)
+		for _, item := range newExportNamespaces(oldService, namespace, req[index].GetMetadata()) {
+			if item != model.ExportToAllNamespaces {
+				names.Add(item)
+				continue
+			}
+			for _, ns := range svr.Cache().Namespace().GetNamespaceList() {
+				names.Add(ns.Name)
+			}
+		}
+	}
+	if len(names.ToSlice()) == 0 {
+		return nil
+	}
+
+	ret := svr.convertToDiscoverResourceEntryMaps(names, servicecommon.NewServiceSet())
+	commonlog.AuthScope().Debug("[Auth][Server] collect service export access res", zap.Any("res", ret))
+	return ret[api.ResourceType_Namespaces]
+}
+
 // queryServiceAliasResource  根据所给的 servicealias 信息，收集对应的 ResourceEntry 列表
 func (svr *serverAuthAbility) queryServiceAliasResource(
 	req []*api.ServiceAlias) map[api.ResourceType][]model.ResourceEntry {
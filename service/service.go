@@ -115,7 +115,7 @@ func (s *Server) CreateService(ctx context.Context, req *api.Service) *api.Respo
 		return api.NewServiceResponse(api.ExistedResource, req)
 	}
 
-	// 存储层操作
+	// 存储层操作，命名空间的服务配额在存储层的事务内检查
 	data := s.createServiceModel(req)
 	if err := s.storage.AddService(data); err != nil {
 		log.Error("[Service] save service fail", ZapRequestID(requestID), ZapPlatformID(platformID), zap.Error(err))
@@ -514,6 +514,12 @@ func (s *Server) updateServiceAttribute(req *api.Service, service *model.Service
 	if err := checkMetadata(req.GetMetadata()); err != nil {
 		return api.NewServiceResponse(api.InvalidMetadata, req), false, false
 	}
+	if err := checkProtectThreshold(req.GetMetadata()); err != nil {
+		return api.NewServiceResponse(api.InvalidServiceMetadata, req), false, false
+	}
+	if err := checkServiceExportTo(req.GetMetadata()); err != nil {
+		return api.NewServiceResponse(api.InvalidServiceMetadata, req), false, false
+	}
 
 	needUpdate := false
 	needNewRevision := false
@@ -919,6 +925,14 @@ func checkCreateService(req *api.Service) *api.Response {
 		return api.NewServiceResponse(api.InvalidMetadata, req)
 	}
 
+	if err := checkProtectThreshold(req.GetMetadata()); err != nil {
+		return api.NewServiceResponse(api.InvalidServiceMetadata, req)
+	}
+
+	if err := checkServiceExportTo(req.GetMetadata()); err != nil {
+		return api.NewServiceResponse(api.InvalidServiceMetadata, req)
+	}
+
 	// 检查字段长度是否大于DB中对应字段长
 	err, notOk := CheckDbServiceFieldLen(req)
 	if notOk {
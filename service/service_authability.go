@@ -29,6 +29,12 @@ import (
 func (svr *serverAuthAbility) CreateServices(ctx context.Context, reqs []*api.Service) *api.BatchWriteResponse {
 	authCtx := svr.collectServiceAuthContext(ctx, reqs, model.Create, "CreateServices")
 
+	// 导出服务需要同时拥有目标命名空间的权限
+	accessRes := authCtx.GetAccessResources()
+	accessRes[api.ResourceType_Namespaces] = append(accessRes[api.ResourceType_Namespaces],
+		svr.queryServiceExportResource(reqs)...)
+	authCtx.SetAccessResources(accessRes)
+
 	_, err := svr.authMgn.CheckConsolePermission(authCtx)
 	if err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
@@ -76,6 +82,10 @@ func (svr *serverAuthAbility) UpdateServices(ctx context.Context, reqs []*api.Se
 
 	accessRes := authCtx.GetAccessResources()
 	delete(accessRes, api.ResourceType_Namespaces)
+	// 新增导出的目标命名空间，需要拥有对应命名空间的权限
+	if exportRes := svr.queryServiceExportResource(reqs); len(exportRes) != 0 {
+		accessRes[api.ResourceType_Namespaces] = exportRes
+	}
 	authCtx.SetAccessResources(accessRes)
 
 	_, err := svr.authMgn.CheckConsolePermission(authCtx)
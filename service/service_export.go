@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+var (
+	errInvalidExportNamespace = errors.New("export namespace contains invalid character")
+)
+
+// checkServiceExportTo 检查服务元数据中导出的目标命名空间
+func checkServiceExportTo(meta map[string]string) error {
+	svc := &model.Service{Meta: meta}
+	for _, namespace := range svc.ExportTo() {
+		if namespace == model.ExportToAllNamespaces {
+			continue
+		}
+		if err := checkResourceName(utils.NewStringValue(namespace)); err != nil {
+			return errInvalidExportNamespace
+		}
+	}
+	return nil
+}
+
+// newExportNamespaces 返回本次请求新增的导出目标命名空间，已经导出过的命名空间不需要再次鉴权
+func newExportNamespaces(oldService *model.Service, namespace string, meta map[string]string) []string {
+	svc := &model.Service{Namespace: namespace, Meta: meta}
+	ret := make([]string, 0)
+	for _, item := range svc.ExportTo() {
+		if item == namespace {
+			continue
+		}
+		if oldService != nil && oldService.IsExportedTo(item) {
+			continue
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// 测试服务导出到其他命名空间
+func TestServiceExport(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	consumerNamespace := "export-consumer-ns"
+	discoverSuit.cleanNamespace(consumerNamespace)
+	nsResp := discoverSuit.namespaceSvr.CreateNamespace(discoverSuit.defaultCtx, &api.Namespace{
+		Name:   utils.NewStringValue(consumerNamespace),
+		Owners: utils.NewStringValue("owner-export"),
+	})
+	if !respSuccess(nsResp) {
+		t.Fatalf("error: %s", nsResp.GetInfo().GetValue())
+	}
+	defer discoverSuit.cleanNamespace(consumerNamespace)
+
+	t.Run("导出的目标命名空间非法，无法创建服务", func(t *testing.T) {
+		serviceReq := genMainService(900)
+		serviceReq.Metadata[model.MetaKeyServiceExportTo] = "ns-a,ns@b"
+		resp := discoverSuit.server.CreateServices(discoverSuit.defaultCtx, []*api.Service{serviceReq})
+		if resp.Responses[0].GetCode().GetValue() != api.InvalidServiceMetadata {
+			t.Fatalf("error: %+v", resp)
+		}
+	})
+
+	t.Run("导出到其他命名空间的服务，可以在目标命名空间中发现", func(t *testing.T) {
+		serviceReq := genMainService(901)
+		serviceReq.Metadata[model.MetaKeyServiceExportTo] = consumerNamespace
+		discoverSuit.cleanServiceName(serviceReq.GetName().GetValue(), serviceReq.GetNamespace().GetValue())
+		resp := discoverSuit.server.CreateServices(discoverSuit.defaultCtx, []*api.Service{serviceReq})
+		if !respSuccess(resp) {
+			t.Fatalf("error: %s", resp.GetInfo().GetValue())
+		}
+		serviceResp := resp.Responses[0].GetService()
+		defer discoverSuit.cleanServiceName(serviceReq.GetName().GetValue(), serviceReq.GetNamespace().GetValue())
+
+		_, instanceResp := discoverSuit.createCommonInstance(t, serviceResp, 1)
+		defer discoverSuit.cleanInstance(instanceResp.GetId().GetValue())
+
+		time.Sleep(discoverSuit.updateCacheInterval)
+
+		discoverResp := discoverSuit.server.ServiceInstancesCache(discoverSuit.defaultCtx, &api.Service{
+			Name:      serviceReq.GetName(),
+			Namespace: utils.NewStringValue(consumerNamespace),
+		})
+		if !respSuccess(discoverResp) {
+			t.Fatalf("error: %s", discoverResp.GetInfo().GetValue())
+		}
+		if len(discoverResp.GetInstances()) != 1 ||
+			discoverResp.GetService().GetNamespace().GetValue() != consumerNamespace {
+			t.Fatalf("error: %+v", discoverResp)
+		}
+
+		// 没有导出的命名空间，无法发现
+		discoverResp = discoverSuit.server.ServiceInstancesCache(discoverSuit.defaultCtx, &api.Service{
+			Name:      serviceReq.GetName(),
+			Namespace: utils.NewStringValue("export-other-ns"),
+		})
+		if discoverResp.GetCode().GetValue() != api.NotFoundResource {
+			t.Fatalf("error: %+v", discoverResp)
+		}
+	})
+}
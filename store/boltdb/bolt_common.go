@@ -41,14 +41,14 @@ func DoTransactionIfNeed(sTx store.Tx, handler BoltHandler, handle transactionFu
 	tx := sTx.GetDelegateTx().(*bolt.Tx)
 	defer func() {
 		if autoManageTx {
-			tx.Rollback()
+			_ = sTx.Rollback()
 		}
 	}()
 
 	ret, err := handle(tx)
 
 	if autoManageTx && err == nil {
-		if err := tx.Commit(); err != nil {
+		if err := sTx.Commit(); err != nil {
 			log.Error("do tx commit", zap.Error(err))
 			return nil, err
 		}
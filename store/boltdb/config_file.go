@@ -328,6 +328,43 @@ func (cf *configFileStore) CountByConfigFileGroup(namespace, group string) (uint
 	return uint64(len(ret)), nil
 }
 
+// CountConfigFiles 统计命名空间下的配置文件数量
+func (cf *configFileStore) CountConfigFiles(namespace string) (uint64, error) {
+	if len(namespace) == 0 {
+		return 0, nil
+	}
+	return cf.CountByConfigFileGroup(namespace, "")
+}
+
+// CountConfigFilesByNamespaces 一次遍历统计多个命名空间下的配置文件数量
+func (cf *configFileStore) CountConfigFilesByNamespaces(namespaces []string) (map[string]uint64, error) {
+	counts := make(map[string]uint64, len(namespaces))
+	if len(namespaces) == 0 {
+		return counts, nil
+	}
+	for i := range namespaces {
+		counts[namespaces[i]] = 0
+	}
+
+	fields := []string{FileFieldNamespace, FileFieldValid}
+	_, err := cf.handler.LoadValuesByFilter(tblConfigFile, fields, &model.ConfigFile{},
+		func(m map[string]interface{}) bool {
+			valid, _ := m[FileFieldValid].(bool)
+			if !valid {
+				return false
+			}
+			saveNs, _ := m[FileFieldNamespace].(string)
+			if _, ok := counts[saveNs]; ok {
+				counts[saveNs]++
+			}
+			return false
+		})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
 // doConfigFilePage 进行分页
 func doConfigFilePage(ret map[string]interface{}, offset, limit uint32) []*model.ConfigFile {
 
@@ -0,0 +1,260 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileChange   string = "ConfigFileChange"
+	tblConfigFileChangeID string = "ConfigFileChangeID"
+
+	FileChangeFieldNamespace     string = "Namespace"
+	FileChangeFieldGroup         string = "Group"
+	FileChangeFieldFileName      string = "FileName"
+	FileChangeFieldContent       string = "Content"
+	FileChangeFieldDiff          string = "Diff"
+	FileChangeFieldMd5           string = "Md5"
+	FileChangeFieldStatus        string = "Status"
+	FileChangeFieldReviewBy      string = "ReviewBy"
+	FileChangeFieldReviewComment string = "ReviewComment"
+	FileChangeFieldModifyTime    string = "ModifyTime"
+	FileChangeFieldModifyBy      string = "ModifyBy"
+)
+
+type configFileChangeStore struct {
+	id      uint64
+	handler BoltHandler
+}
+
+func newConfigFileChangeStore(handler BoltHandler) (*configFileChangeStore, error) {
+	s := &configFileChangeStore{handler: handler, id: 0}
+	ret, err := handler.LoadValues(tblConfigFileChangeID, []string{tblConfigFileChangeID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return s, nil
+	}
+	val := ret[tblConfigFileChangeID].(*IDHolder)
+	s.id = val.ID
+	return s, nil
+}
+
+// CreateConfigFileChange 新建配置文件变更
+func (cfc *configFileChangeStore) CreateConfigFileChange(proxyTx store.Tx,
+	change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+
+	ret, err := DoTransactionIfNeed(proxyTx, cfc.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		cfc.id++
+		change.Id = cfc.id
+		change.Valid = true
+		tN := time.Now()
+		change.CreateTime = tN
+		change.ModifyTime = tN
+
+		if err := saveValue(tx, tblConfigFileChangeID, tblConfigFileChangeID, &IDHolder{
+			ID: cfc.id,
+		}); err != nil {
+			log.Error("[ConfigFileChange] save auto_increment id", zap.Error(err))
+			return nil, err
+		}
+
+		key := strconv.FormatUint(change.Id, 10)
+		if err := saveValue(tx, tblConfigFileChange, key, change); err != nil {
+			log.Error("[ConfigFileChange] save info", zap.Error(err))
+			return nil, err
+		}
+
+		return cfc.getConfigFileChange(tx, change.Id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileChange), nil
+}
+
+// UpdateConfigFileChange 更新配置文件变更的内容以及审核状态
+func (cfc *configFileChangeStore) UpdateConfigFileChange(proxyTx store.Tx,
+	change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+
+	ret, err := DoTransactionIfNeed(proxyTx, cfc.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		properties := make(map[string]interface{})
+
+		properties[FileChangeFieldContent] = change.Content
+		properties[FileChangeFieldDiff] = change.Diff
+		properties[FileChangeFieldMd5] = change.Md5
+		properties[FileChangeFieldStatus] = change.Status
+		properties[FileChangeFieldReviewBy] = change.ReviewBy
+		properties[FileChangeFieldReviewComment] = change.ReviewComment
+		properties[FileChangeFieldModifyTime] = time.Now()
+		properties[FileChangeFieldModifyBy] = change.ModifyBy
+
+		key := strconv.FormatUint(change.Id, 10)
+		if err := updateValue(tx, tblConfigFileChange, key, properties); err != nil {
+			log.Error("[ConfigFileChange] update info", zap.Error(err))
+			return nil, err
+		}
+
+		return cfc.getConfigFileChange(tx, change.Id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileChange), nil
+}
+
+// GetConfigFileChange 获取单个配置文件变更
+func (cfc *configFileChangeStore) GetConfigFileChange(proxyTx store.Tx, id uint64) (*model.ConfigFileChange, error) {
+	ret, err := DoTransactionIfNeed(proxyTx, cfc.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		return cfc.getConfigFileChange(tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileChange), nil
+}
+
+func (cfc *configFileChangeStore) getConfigFileChange(tx *bolt.Tx, id uint64) ([]interface{}, error) {
+	key := strconv.FormatUint(id, 10)
+
+	ret := make(map[string]interface{})
+	if err := loadValues(tx, tblConfigFileChange, []string{key}, &model.ConfigFileChange{}, ret); err != nil {
+		return nil, err
+	}
+
+	data, ok := ret[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return []interface{}{data}, nil
+}
+
+// GetLatestConfigFileChange 获取配置文件最后一次的变更
+func (cfc *configFileChangeStore) GetLatestConfigFileChange(proxyTx store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileChange, error) {
+
+	ret, err := DoTransactionIfNeed(proxyTx, cfc.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		values := make(map[string]interface{})
+		if err := loadValuesByFilter(tx, tblConfigFileChange, cfc.filterFields(), &model.ConfigFileChange{},
+			cfc.filter(namespace, group, fileName, ""), values); err != nil {
+			return nil, err
+		}
+
+		var latest *model.ConfigFileChange
+		for _, v := range values {
+			change := v.(*model.ConfigFileChange)
+			if latest == nil || change.Id > latest.Id {
+				latest = change
+			}
+		}
+		if latest == nil {
+			return nil, nil
+		}
+		return []interface{}{latest}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileChange), nil
+}
+
+// QueryConfigFileChanges 查询配置文件变更，namespace、group、fileName 为空时不作为查询条件
+func (cfc *configFileChangeStore) QueryConfigFileChanges(namespace, group, fileName, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileChange, error) {
+
+	ret, err := cfc.handler.LoadValuesByFilter(tblConfigFileChange, cfc.filterFields(), &model.ConfigFileChange{},
+		cfc.filter(namespace, group, fileName, status))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	changes := make([]*model.ConfigFileChange, 0, len(ret))
+	for _, v := range ret {
+		changes = append(changes, v.(*model.ConfigFileChange))
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Id > changes[j].Id
+	})
+
+	total := uint32(len(changes))
+	if offset >= total {
+		return total, []*model.ConfigFileChange{}, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return total, changes[offset:end], nil
+}
+
+func (cfc *configFileChangeStore) filterFields() []string {
+	return []string{FileChangeFieldNamespace, FileChangeFieldGroup, FileChangeFieldFileName, FileChangeFieldStatus}
+}
+
+func (cfc *configFileChangeStore) filter(namespace, group, fileName,
+	status string) func(m map[string]interface{}) bool {
+
+	return func(m map[string]interface{}) bool {
+		if saveNs, _ := m[FileChangeFieldNamespace].(string); namespace != "" && saveNs != namespace {
+			return false
+		}
+		if saveGroup, _ := m[FileChangeFieldGroup].(string); group != "" && saveGroup != group {
+			return false
+		}
+		if saveName, _ := m[FileChangeFieldFileName].(string); fileName != "" && saveName != fileName {
+			return false
+		}
+		if saveStatus, _ := m[FileChangeFieldStatus].(string); status != "" && saveStatus != status {
+			return false
+		}
+		return true
+	}
+}
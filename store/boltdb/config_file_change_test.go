@@ -0,0 +1,107 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func mockConfigFileChange(content string) *model.ConfigFileChange {
+	return &model.ConfigFileChange{
+		Namespace: "config-file-change",
+		Group:     "config-file-change",
+		FileName:  "config-file-change",
+		Content:   content,
+		Diff:      "+" + content,
+		Md5:       content,
+		Status:    utils.ChangeStatusPending,
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
+
+func Test_configFileChangeStore(t *testing.T) {
+	t.Run("创建并审核配置文件变更", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileChange, func(t *testing.T, handler BoltHandler) {
+			s := &configFileChangeStore{handler: handler}
+
+			change, err := s.CreateConfigFileChange(nil, mockConfigFileChange("v1"))
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), change.Id)
+			assert.True(t, change.Valid)
+
+			change.Status = utils.ChangeStatusApproved
+			change.ReviewBy = "reviewer"
+			change.ReviewComment = "lgtm"
+			updated, err := s.UpdateConfigFileChange(nil, change)
+			assert.NoError(t, err)
+			assert.Equal(t, utils.ChangeStatusApproved, updated.Status)
+			assert.Equal(t, "reviewer", updated.ReviewBy)
+			assert.Equal(t, "v1", updated.Content)
+
+			ret, err := s.GetConfigFileChange(nil, change.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, "lgtm", ret.ReviewComment)
+
+			ret, err = s.GetConfigFileChange(nil, 100)
+			assert.NoError(t, err)
+			assert.Nil(t, ret)
+		})
+	})
+
+	t.Run("查询配置文件变更", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileChange, func(t *testing.T, handler BoltHandler) {
+			s := &configFileChangeStore{handler: handler}
+
+			for _, content := range []string{"v1", "v2", "v3"} {
+				_, err := s.CreateConfigFileChange(nil, mockConfigFileChange(content))
+				assert.NoError(t, err)
+			}
+			first, err := s.GetConfigFileChange(nil, 1)
+			assert.NoError(t, err)
+			first.Status = utils.ChangeStatusRejected
+			_, err = s.UpdateConfigFileChange(nil, first)
+			assert.NoError(t, err)
+
+			latest, err := s.GetLatestConfigFileChange(nil, first.Namespace, first.Group, first.FileName)
+			assert.NoError(t, err)
+			assert.Equal(t, "v3", latest.Content)
+
+			total, changes, err := s.QueryConfigFileChanges(first.Namespace, "", "", "", 0, 2)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(3), total)
+			assert.Equal(t, 2, len(changes))
+			assert.Equal(t, uint64(3), changes[0].Id)
+
+			total, changes, err = s.QueryConfigFileChanges(first.Namespace, first.Group, first.FileName,
+				utils.ChangeStatusPending, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), total)
+			assert.Equal(t, 2, len(changes))
+
+			latest, err = s.GetLatestConfigFileChange(nil, first.Namespace, first.Group, "not-exist")
+			assert.NoError(t, err)
+			assert.Nil(t, latest)
+		})
+	})
+}
@@ -0,0 +1,208 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileClientPin   string = "ConfigFileClientPin"
+	tblConfigFileClientPinID string = "ConfigFileClientPinID"
+
+	FileClientPinFieldNamespace  string = "Namespace"
+	FileClientPinFieldGroup      string = "Group"
+	FileClientPinFieldFileName   string = "FileName"
+	FileClientPinFieldClient     string = "Client"
+	FileClientPinFieldHistoryId  string = "HistoryId"
+	FileClientPinFieldContent    string = "Content"
+	FileClientPinFieldMd5        string = "Md5"
+	FileClientPinFieldComment    string = "Comment"
+	FileClientPinFieldModifyTime string = "ModifyTime"
+	FileClientPinFieldModifyBy   string = "ModifyBy"
+)
+
+type configFileClientPinStore struct {
+	id      uint64
+	handler BoltHandler
+}
+
+func newConfigFileClientPinStore(handler BoltHandler) (*configFileClientPinStore, error) {
+	s := &configFileClientPinStore{handler: handler}
+	ret, err := handler.LoadValues(tblConfigFileClientPinID, []string{tblConfigFileClientPinID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 0 {
+		s.id = ret[tblConfigFileClientPinID].(*IDHolder).ID
+	}
+	return s, nil
+}
+
+// CreateConfigFileClientPin 固定客户端的版本，同一个配置文件下客户端唯一
+func (cfp *configFileClientPinStore) CreateConfigFileClientPin(
+	pin *model.ConfigFileClientPin) (*model.ConfigFileClientPin, error) {
+
+	err := cfp.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValuesByFilter(tx, tblConfigFileClientPin, cfp.filterFields(), &model.ConfigFileClientPin{},
+			cfp.filterByClient(pin.Namespace, pin.Group, pin.FileName, pin.Client), values); err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file client pin existed")
+		}
+
+		cfp.id++
+		pin.Id = cfp.id
+		pin.Valid = true
+		tN := time.Now()
+		pin.CreateTime = tN
+		pin.ModifyTime = tN
+
+		if err := saveValue(tx, tblConfigFileClientPinID, tblConfigFileClientPinID, &IDHolder{
+			ID: cfp.id,
+		}); err != nil {
+			log.Error("[ConfigFileClientPin] save auto_increment id", zap.Error(err))
+			return err
+		}
+		if err := saveValue(tx, tblConfigFileClientPin, strconv.FormatUint(pin.Id, 10), pin); err != nil {
+			log.Error("[ConfigFileClientPin] save info", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pin, nil
+}
+
+// UpdateConfigFileClientPin 更新客户端固定的发布内容
+func (cfp *configFileClientPinStore) UpdateConfigFileClientPin(pin *model.ConfigFileClientPin) error {
+	saved, err := cfp.GetConfigFileClientPin(pin.Namespace, pin.Group, pin.FileName, pin.Client)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return nil
+	}
+
+	properties := map[string]interface{}{
+		FileClientPinFieldHistoryId:  pin.HistoryId,
+		FileClientPinFieldContent:    pin.Content,
+		FileClientPinFieldMd5:        pin.Md5,
+		FileClientPinFieldComment:    pin.Comment,
+		FileClientPinFieldModifyTime: time.Now(),
+		FileClientPinFieldModifyBy:   pin.ModifyBy,
+	}
+	if err := cfp.handler.UpdateValue(tblConfigFileClientPin, strconv.FormatUint(saved.Id, 10),
+		properties); err != nil {
+		log.Error("[ConfigFileClientPin] update info", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteConfigFileClientPin 取消客户端的版本固定
+func (cfp *configFileClientPinStore) DeleteConfigFileClientPin(namespace, group, fileName, client string) error {
+	saved, err := cfp.GetConfigFileClientPin(namespace, group, fileName, client)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return nil
+	}
+	return cfp.handler.DeleteValues(tblConfigFileClientPin, []string{strconv.FormatUint(saved.Id, 10)}, false)
+}
+
+// GetConfigFileClientPin 获取客户端的版本固定
+func (cfp *configFileClientPinStore) GetConfigFileClientPin(namespace, group, fileName,
+	client string) (*model.ConfigFileClientPin, error) {
+
+	ret, err := cfp.handler.LoadValuesByFilter(tblConfigFileClientPin, cfp.filterFields(),
+		&model.ConfigFileClientPin{}, cfp.filterByClient(namespace, group, fileName, client))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range ret {
+		return v.(*model.ConfigFileClientPin), nil
+	}
+	return nil, nil
+}
+
+// QueryConfigFileClientPins 获取配置文件的全部版本固定，按照创建顺序排序
+func (cfp *configFileClientPinStore) QueryConfigFileClientPins(namespace, group,
+	fileName string) ([]*model.ConfigFileClientPin, error) {
+
+	ret, err := cfp.handler.LoadValuesByFilter(tblConfigFileClientPin, cfp.filterFields(),
+		&model.ConfigFileClientPin{}, func(m map[string]interface{}) bool {
+			saveNs, _ := m[FileClientPinFieldNamespace].(string)
+			saveGroup, _ := m[FileClientPinFieldGroup].(string)
+			saveFileName, _ := m[FileClientPinFieldFileName].(string)
+			return saveNs == namespace && saveGroup == group && saveFileName == fileName
+		})
+	if err != nil {
+		return nil, err
+	}
+	return toSortedClientPins(ret), nil
+}
+
+// GetAllConfigFileClientPins 获取全部的版本固定
+func (cfp *configFileClientPinStore) GetAllConfigFileClientPins() ([]*model.ConfigFileClientPin, error) {
+	ret, err := cfp.handler.LoadValuesAll(tblConfigFileClientPin, &model.ConfigFileClientPin{})
+	if err != nil {
+		return nil, err
+	}
+	return toSortedClientPins(ret), nil
+}
+
+func (cfp *configFileClientPinStore) filterFields() []string {
+	return []string{FileClientPinFieldNamespace, FileClientPinFieldGroup, FileClientPinFieldFileName,
+		FileClientPinFieldClient}
+}
+
+func (cfp *configFileClientPinStore) filterByClient(namespace, group, fileName,
+	client string) func(m map[string]interface{}) bool {
+	return func(m map[string]interface{}) bool {
+		saveNs, _ := m[FileClientPinFieldNamespace].(string)
+		saveGroup, _ := m[FileClientPinFieldGroup].(string)
+		saveFileName, _ := m[FileClientPinFieldFileName].(string)
+		saveClient, _ := m[FileClientPinFieldClient].(string)
+		return saveNs == namespace && saveGroup == group && saveFileName == fileName && saveClient == client
+	}
+}
+
+func toSortedClientPins(values map[string]interface{}) []*model.ConfigFileClientPin {
+	pins := make([]*model.ConfigFileClientPin, 0, len(values))
+	for _, v := range values {
+		pins = append(pins, v.(*model.ConfigFileClientPin))
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].Id < pins[j].Id
+	})
+	return pins
+}
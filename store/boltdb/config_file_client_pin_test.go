@@ -0,0 +1,92 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+func mockConfigFileClientPin(client string) *model.ConfigFileClientPin {
+	return &model.ConfigFileClientPin{
+		Namespace: "config-file-client-pin",
+		Group:     "config-file-client-pin",
+		FileName:  "config-file-client-pin",
+		Client:    client,
+		HistoryId: 1,
+		Content:   "config-file-client-pin",
+		Md5:       "config-file-client-pin",
+	}
+}
+
+func Test_configFileClientPinStore(t *testing.T) {
+	t.Run("创建并更新版本固定", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileClientPin, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileClientPinStore(handler)
+			assert.NoError(t, err)
+
+			pin, err := s.CreateConfigFileClientPin(mockConfigFileClientPin("127.0.0.1"))
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), pin.Id)
+			assert.True(t, pin.Valid)
+
+			_, err = s.CreateConfigFileClientPin(mockConfigFileClientPin("127.0.0.1"))
+			assert.Error(t, err)
+			assert.Equal(t, store.DuplicateEntryErr, store.Code(err))
+
+			pin.HistoryId = 2
+			pin.Content = "update config file client pin"
+			err = s.UpdateConfigFileClientPin(pin)
+			assert.NoError(t, err)
+
+			ret, err := s.GetConfigFileClientPin(pin.Namespace, pin.Group, pin.FileName, pin.Client)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(2), ret.HistoryId)
+			assert.Equal(t, pin.Content, ret.Content)
+		})
+	})
+
+	t.Run("查询并删除版本固定", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileClientPin, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileClientPinStore(handler)
+			assert.NoError(t, err)
+
+			_, err = s.CreateConfigFileClientPin(mockConfigFileClientPin("127.0.0.1"))
+			assert.NoError(t, err)
+			pin, err := s.CreateConfigFileClientPin(mockConfigFileClientPin("127.0.0.2"))
+			assert.NoError(t, err)
+
+			pins, err := s.QueryConfigFileClientPins(pin.Namespace, pin.Group, pin.FileName)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(pins))
+			assert.Equal(t, "127.0.0.1", pins[0].Client)
+
+			err = s.DeleteConfigFileClientPin(pin.Namespace, pin.Group, pin.FileName, "127.0.0.1")
+			assert.NoError(t, err)
+
+			pins, err = s.GetAllConfigFileClientPins()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(pins))
+			assert.Equal(t, "127.0.0.2", pins[0].Client)
+		})
+	})
+}
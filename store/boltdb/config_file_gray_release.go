@@ -0,0 +1,244 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileGrayRelease   string = "ConfigFileGrayRelease"
+	tblConfigFileGrayReleaseID string = "ConfigFileGrayReleaseID"
+
+	FileGrayReleaseFieldRule       string = "Rule"
+	FileGrayReleaseFieldErrorCount string = "ErrorCount"
+)
+
+type configFileGrayReleaseStore struct {
+	id      uint64
+	handler BoltHandler
+}
+
+func newConfigFileGrayReleaseStore(handler BoltHandler) (*configFileGrayReleaseStore, error) {
+	s := &configFileGrayReleaseStore{handler: handler, id: 0}
+	ret, err := handler.LoadValues(tblConfigFileGrayReleaseID, []string{tblConfigFileGrayReleaseID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return s, nil
+	}
+	val := ret[tblConfigFileGrayReleaseID].(*IDHolder)
+	s.id = val.ID
+	return s, nil
+}
+
+// CreateConfigFileGrayRelease 新建配置文件灰度发布
+func (cfg *configFileGrayReleaseStore) CreateConfigFileGrayRelease(proxyTx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+
+	ret, err := DoTransactionIfNeed(proxyTx, cfg.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		cfg.id++
+		grayRelease.Id = cfg.id
+		grayRelease.Valid = true
+		tN := time.Now()
+		grayRelease.CreateTime = tN
+		grayRelease.ModifyTime = tN
+
+		if err := saveValue(tx, tblConfigFileGrayReleaseID, tblConfigFileGrayReleaseID, &IDHolder{
+			ID: cfg.id,
+		}); err != nil {
+			log.Error("[ConfigFileGrayRelease] save auto_increment id", zap.Error(err))
+			return nil, err
+		}
+
+		key := grayReleaseKey(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+		if err := saveValue(tx, tblConfigFileGrayRelease, key, grayRelease); err != nil {
+			log.Error("[ConfigFileGrayRelease] save info", zap.Error(err))
+			return nil, err
+		}
+
+		return cfg.getConfigFileGrayRelease(tx, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+// UpdateConfigFileGrayRelease 更新配置文件灰度发布
+func (cfg *configFileGrayReleaseStore) UpdateConfigFileGrayRelease(proxyTx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+
+	ret, err := DoTransactionIfNeed(proxyTx, cfg.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		properties := make(map[string]interface{})
+
+		properties[FileReleaseFieldName] = grayRelease.Name
+		properties[FileReleaseFieldContent] = grayRelease.Content
+		properties[FileReleaseFieldComment] = grayRelease.Comment
+		properties[FileReleaseFieldMd5] = grayRelease.Md5
+		properties[FileReleaseFieldVersion] = grayRelease.Version
+		properties[FileGrayReleaseFieldRule] = grayRelease.Rule
+		properties[FileGrayReleaseFieldErrorCount] = grayRelease.ErrorCount
+		properties[FileReleaseFieldValid] = true
+		properties[FileReleaseFieldModifyTime] = time.Now()
+		properties[FileReleaseFieldModifyBy] = grayRelease.ModifyBy
+
+		key := grayReleaseKey(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+		if err := updateValue(tx, tblConfigFileGrayRelease, key, properties); err != nil {
+			log.Error("[ConfigFileGrayRelease] update info", zap.Error(err))
+			return nil, err
+		}
+
+		return cfg.getConfigFileGrayRelease(tx, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+// GetConfigFileGrayRelease 获取配置文件的灰度发布
+func (cfg *configFileGrayReleaseStore) GetConfigFileGrayRelease(proxyTx store.Tx, namespace,
+	group, fileName string) (*model.ConfigFileGrayRelease, error) {
+
+	ret, err := DoTransactionIfNeed(proxyTx, cfg.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		return cfg.getConfigFileGrayRelease(tx, namespace, group, fileName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+func (cfg *configFileGrayReleaseStore) getConfigFileGrayRelease(tx *bolt.Tx, namespace, group,
+	fileName string) ([]interface{}, error) {
+
+	key := grayReleaseKey(namespace, group, fileName)
+
+	ret := make(map[string]interface{})
+	if err := loadValues(tx, tblConfigFileGrayRelease, []string{key}, &model.ConfigFileGrayRelease{}, ret); err != nil {
+		return nil, err
+	}
+
+	data, ok := ret[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return []interface{}{data}, nil
+}
+
+// DeleteConfigFileGrayRelease 删除配置文件的灰度发布
+func (cfg *configFileGrayReleaseStore) DeleteConfigFileGrayRelease(proxyTx store.Tx, namespace, group,
+	fileName string) error {
+
+	_, err := DoTransactionIfNeed(proxyTx, cfg.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		key := grayReleaseKey(namespace, group, fileName)
+		if err := deleteValues(tx, tblConfigFileGrayRelease, []string{key}, false); err != nil {
+			log.Error("[ConfigFileGrayRelease] delete info", zap.Error(err))
+			return nil, err
+		}
+		return nil, nil
+	})
+
+	return err
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter 获取最后更新时间大于某个时间点的灰度发布
+func (cfg *configFileGrayReleaseStore) FindConfigFileGrayReleaseByModifyTimeAfter(
+	modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+
+	fields := []string{FileReleaseFieldModifyTime}
+
+	ret, err := cfg.handler.LoadValuesByFilter(tblConfigFileGrayRelease, fields, &model.ConfigFileGrayRelease{},
+		func(m map[string]interface{}) bool {
+			saveMt, _ := m[FileReleaseFieldModifyTime].(time.Time)
+			return !saveMt.Before(modifyTime)
+		})
+
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]*model.ConfigFileGrayRelease, 0, len(ret))
+	for _, v := range ret {
+		releases = append(releases, v.(*model.ConfigFileGrayRelease))
+	}
+
+	return releases, nil
+}
+
+// IncrConfigFileGrayReleaseErrorCount 累加灰度发布的加载失败次数
+func (cfg *configFileGrayReleaseStore) IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName,
+	md5 string) (uint32, error) {
+
+	ret, err := DoTransactionIfNeed(nil, cfg.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		values, err := cfg.getConfigFileGrayRelease(tx, namespace, group, fileName)
+		if err != nil || len(values) == 0 {
+			return nil, err
+		}
+		gray := values[0].(*model.ConfigFileGrayRelease)
+		if gray.Md5 != md5 {
+			return nil, nil
+		}
+
+		count := gray.ErrorCount + 1
+		properties := map[string]interface{}{
+			FileGrayReleaseFieldErrorCount: count,
+		}
+		key := grayReleaseKey(namespace, group, fileName)
+		if err := updateValue(tx, tblConfigFileGrayRelease, key, properties); err != nil {
+			log.Error("[ConfigFileGrayRelease] update error count", zap.Error(err))
+			return nil, err
+		}
+		return []interface{}{count}, nil
+	})
+	if err != nil || len(ret) == 0 {
+		return 0, err
+	}
+	return ret[0].(uint32), nil
+}
+
+func grayReleaseKey(namespace, group, fileName string) string {
+	return fmt.Sprintf("%s@@%s@@%s", namespace, group, fileName)
+}
@@ -0,0 +1,125 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func mockConfigFileGrayRelease() *model.ConfigFileGrayRelease {
+	return &model.ConfigFileGrayRelease{
+		Name:      "config-file-gray-release",
+		Namespace: "config-file-gray-release",
+		Group:     "config-file-gray-release",
+		FileName:  "config-file-gray-release",
+		Content:   "config-file-gray-release",
+		Md5:       "config-file-gray-release",
+		Version:   2,
+		Rule:      `{"clientIps":["127.0.0.1"]}`,
+	}
+}
+
+func Test_configFileGrayReleaseStore(t *testing.T) {
+	t.Run("创建并更新灰度发布", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileGrayReleaseStore{handler: handler}
+
+			gray, err := s.CreateConfigFileGrayRelease(nil, mockConfigFileGrayRelease())
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), gray.Id)
+			assert.True(t, gray.Valid)
+
+			gray.Content = "update config gray release"
+			gray.Version = 3
+			gray.Rule = `{"labels":{"region":"gz"}}`
+			newGray, err := s.UpdateConfigFileGrayRelease(nil, gray)
+			assert.NoError(t, err)
+			assert.Equal(t, gray.Content, newGray.Content)
+			assert.Equal(t, gray.Rule, newGray.Rule)
+			assert.Equal(t, uint64(3), newGray.Version)
+
+			ret, err := s.GetConfigFileGrayRelease(nil, gray.Namespace, gray.Group, gray.FileName)
+			assert.NoError(t, err)
+			assert.Equal(t, newGray.Content, ret.Content)
+		})
+	})
+
+	t.Run("删除灰度发布", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileGrayReleaseStore{handler: handler}
+
+			gray, err := s.CreateConfigFileGrayRelease(nil, mockConfigFileGrayRelease())
+			assert.NoError(t, err)
+
+			err = s.DeleteConfigFileGrayRelease(nil, gray.Namespace, gray.Group, gray.FileName)
+			assert.NoError(t, err)
+
+			ret, err := s.GetConfigFileGrayRelease(nil, gray.Namespace, gray.Group, gray.FileName)
+			assert.NoError(t, err)
+			assert.Nil(t, ret)
+		})
+	})
+
+	t.Run("累加灰度发布失败次数", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileGrayReleaseStore{handler: handler}
+
+			gray, err := s.CreateConfigFileGrayRelease(nil, mockConfigFileGrayRelease())
+			assert.NoError(t, err)
+
+			count, err := s.IncrConfigFileGrayReleaseErrorCount(gray.Namespace, gray.Group, gray.FileName, gray.Md5)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), count)
+			count, err = s.IncrConfigFileGrayReleaseErrorCount(gray.Namespace, gray.Group, gray.FileName, gray.Md5)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), count)
+
+			// md5 不一致说明灰度内容已经变化，不累加
+			count, err = s.IncrConfigFileGrayReleaseErrorCount(gray.Namespace, gray.Group, gray.FileName, "other")
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(0), count)
+
+			ret, err := s.GetConfigFileGrayRelease(nil, gray.Namespace, gray.Group, gray.FileName)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), ret.ErrorCount)
+		})
+	})
+
+	t.Run("根据修改时间查询灰度发布", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileGrayReleaseStore{handler: handler}
+
+			start := time.Now()
+			_, err := s.CreateConfigFileGrayRelease(nil, mockConfigFileGrayRelease())
+			assert.NoError(t, err)
+
+			ret, err := s.FindConfigFileGrayReleaseByModifyTimeAfter(start)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(ret))
+
+			ret, err = s.FindConfigFileGrayReleaseByModifyTimeAfter(time.Now().Add(time.Second))
+			assert.NoError(t, err)
+			assert.Equal(t, 0, len(ret))
+		})
+	})
+}
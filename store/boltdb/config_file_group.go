@@ -96,7 +96,7 @@ func (fg *configFileGroupStore) createConfigFileGroup(
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	fg.id++
 	fileGroup.Id = fg.id
@@ -118,7 +118,7 @@ func (fg *configFileGroupStore) createConfigFileGroup(
 		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		log.Error("[ConfigFileGroup] do tx commit", zap.Error(err))
 		return nil, err
 	}
@@ -179,6 +179,21 @@ func (rh *configFileReleaseHistoryStore) GetLatestConfigFileReleaseHistory(names
 	return histories[0], nil
 }
 
+// GetConfigFileReleaseHistory 根据 ID 获取配置文件发布历史记录
+func (rh *configFileReleaseHistoryStore) GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error) {
+	key := strconv.FormatUint(id, 10)
+	ret, err := rh.handler.LoadValues(tblConfigFileReleaseHistory, []string{key}, &model.ConfigFileReleaseHistory{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[key].(*model.ConfigFileReleaseHistory), nil
+}
+
 // doConfigFileGroupPage 进行分页
 func doConfigFileHistoryPage(ret map[string]interface{}, offset, limit uint32) []*model.ConfigFileReleaseHistory {
 
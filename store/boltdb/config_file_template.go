@@ -18,6 +18,7 @@
 package boltdb
 
 import (
+	"sort"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -29,11 +30,14 @@ import (
 const (
 	tblConfigFileTemplate   string = "ConfigFileTemplate"
 	tblConfigFileTemplateID string = "ConfigFileTemplateID"
+	tblConfigFileSchema     string = "ConfigFileSchema"
+	tblConfigFileSchemaID   string = "ConfigFileSchemaID"
 )
 
 type configFileTemplateStore struct {
-	id      uint64
-	handler BoltHandler
+	id       uint64
+	schemaID uint64
+	handler  BoltHandler
 }
 
 func newConfigFileTemplateStore(handler BoltHandler) (*configFileTemplateStore, error) {
@@ -42,11 +46,16 @@ func newConfigFileTemplateStore(handler BoltHandler) (*configFileTemplateStore,
 	if err != nil {
 		return nil, err
 	}
-	if len(ret) == 0 {
-		return s, nil
+	if len(ret) != 0 {
+		s.id = ret[tblConfigFileTemplateID].(*IDHolder).ID
+	}
+	ret, err = handler.LoadValues(tblConfigFileSchemaID, []string{tblConfigFileSchemaID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 0 {
+		s.schemaID = ret[tblConfigFileSchemaID].(*IDHolder).ID
 	}
-	val := ret[tblConfigFileTemplateID].(*IDHolder)
-	s.id = val.ID
 	return s, nil
 }
 
@@ -74,14 +83,14 @@ func (cf *configFileTemplateStore) GetConfigFileTemplate(name string) (*model.Co
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	values := make(map[string]interface{})
 	if err = loadValues(tx, tblConfigFileTemplate, []string{name}, &model.ConfigFileTemplate{}, values); err != nil {
 		return nil, err
 	}
 
-	err = tx.Commit()
+	err = proxy.Commit()
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +118,7 @@ func (cf *configFileTemplateStore) CreateConfigFileTemplate(
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	cf.id++
 	template.Id = cf.id
@@ -130,7 +139,7 @@ func (cf *configFileTemplateStore) CreateConfigFileTemplate(
 		return nil, err
 	}
 
-	err = tx.Commit()
+	err = proxy.Commit()
 	if err != nil {
 		log.Error("[ConfigFileTemplate] commit error", zap.Error(err))
 		return nil, err
@@ -138,3 +147,89 @@ func (cf *configFileTemplateStore) CreateConfigFileTemplate(
 
 	return template, nil
 }
+
+// QueryAllConfigFileSchemas query all config file json schemas
+func (cf *configFileTemplateStore) QueryAllConfigFileSchemas() ([]*model.ConfigFileSchema, error) {
+	ret, err := cf.handler.LoadValuesAll(tblConfigFileSchema, &model.ConfigFileSchema{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	var schemas []*model.ConfigFileSchema
+	for _, v := range ret {
+		schemas = append(schemas, v.(*model.ConfigFileSchema))
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Id > schemas[j].Id
+	})
+	return schemas, nil
+}
+
+// GetConfigFileSchema get config file json schema
+func (cf *configFileTemplateStore) GetConfigFileSchema(name string) (*model.ConfigFileSchema, error) {
+	ret, err := cf.handler.LoadValues(tblConfigFileSchema, []string{name}, &model.ConfigFileSchema{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	return ret[name].(*model.ConfigFileSchema), nil
+}
+
+// CreateConfigFileSchema create config file json schema
+func (cf *configFileTemplateStore) CreateConfigFileSchema(
+	schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+
+	proxy, err := cf.handler.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	tx := proxy.GetDelegateTx().(*bolt.Tx)
+
+	defer proxy.Rollback()
+
+	cf.schemaID++
+	schema.Id = cf.schemaID
+	schema.CreateTime = time.Now()
+	schema.ModifyTime = time.Now()
+
+	if err := saveValue(tx, tblConfigFileSchemaID, tblConfigFileSchemaID, &IDHolder{
+		ID: cf.schemaID,
+	}); err != nil {
+		log.Error("[ConfigFileSchema] save auto_increment id", zap.Error(err))
+		return nil, err
+	}
+
+	if err := saveValue(tx, tblConfigFileSchema, schema.Name, schema); err != nil {
+		log.Error("[ConfigFileSchema] save error", zap.Error(err))
+		return nil, err
+	}
+
+	if err := proxy.Commit(); err != nil {
+		log.Error("[ConfigFileSchema] commit error", zap.Error(err))
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// UpdateConfigFileSchema update config file json schema
+func (cf *configFileTemplateStore) UpdateConfigFileSchema(
+	schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+
+	properties := map[string]interface{}{
+		"Content":    schema.Content,
+		"Comment":    schema.Comment,
+		"ModifyBy":   schema.ModifyBy,
+		"ModifyTime": time.Now(),
+	}
+	if err := cf.handler.UpdateValue(tblConfigFileSchema, schema.Name, properties); err != nil {
+		log.Error("[ConfigFileSchema] update error", zap.Error(err))
+		return nil, err
+	}
+
+	return cf.GetConfigFileSchema(schema.Name)
+}
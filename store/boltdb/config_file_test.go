@@ -237,4 +237,23 @@ func Test_configFileStore(t *testing.T) {
 			assert.Empty(t, ret)
 		})
 	})
+	t.Run("按命名空间统计配置文件", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFile, func(t *testing.T, handler BoltHandler) {
+
+			s := &configFileStore{handler: handler}
+
+			mocks := append(mockConfigFile(3, map[string]string{"namespace": "ns-a"}),
+				mockConfigFile(2, map[string]string{"namespace": "ns-b"})...)
+			for i := range mocks {
+				_, err := s.CreateConfigFile(nil, mocks[i])
+				assert.NoError(t, err, "%+v", err)
+			}
+			err := s.DeleteConfigFile(nil, mocks[0].Namespace, mocks[0].Group, mocks[0].Name)
+			assert.NoError(t, err, "%+v", err)
+
+			counts, err := s.CountConfigFilesByNamespaces([]string{"ns-a", "ns-b", "ns-c"})
+			assert.NoError(t, err, "%+v", err)
+			assert.Equal(t, map[string]uint64{"ns-a": 2, "ns-b": 2, "ns-c": 0}, counts)
+		})
+	})
 }
@@ -0,0 +1,182 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileVariable   string = "ConfigFileVariable"
+	tblConfigFileVariableID string = "ConfigFileVariableID"
+
+	FileVariableFieldNamespace  string = "Namespace"
+	FileVariableFieldName       string = "Name"
+	FileVariableFieldType       string = "Type"
+	FileVariableFieldValue      string = "Value"
+	FileVariableFieldComment    string = "Comment"
+	FileVariableFieldModifyTime string = "ModifyTime"
+	FileVariableFieldModifyBy   string = "ModifyBy"
+)
+
+type configFileVariableStore struct {
+	id      uint64
+	handler BoltHandler
+}
+
+func newConfigFileVariableStore(handler BoltHandler) (*configFileVariableStore, error) {
+	s := &configFileVariableStore{handler: handler}
+	ret, err := handler.LoadValues(tblConfigFileVariableID, []string{tblConfigFileVariableID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 0 {
+		s.id = ret[tblConfigFileVariableID].(*IDHolder).ID
+	}
+	return s, nil
+}
+
+// CreateConfigFileVariable 创建模板变量，同一个命名空间下变量名唯一
+func (cfv *configFileVariableStore) CreateConfigFileVariable(
+	variable *model.ConfigFileVariable) (*model.ConfigFileVariable, error) {
+
+	err := cfv.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValuesByFilter(tx, tblConfigFileVariable, []string{FileVariableFieldNamespace,
+			FileVariableFieldName}, &model.ConfigFileVariable{},
+			cfv.filterByName(variable.Namespace, variable.Name), values); err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file variable existed")
+		}
+
+		cfv.id++
+		variable.Id = cfv.id
+		tN := time.Now()
+		variable.CreateTime = tN
+		variable.ModifyTime = tN
+
+		if err := saveValue(tx, tblConfigFileVariableID, tblConfigFileVariableID, &IDHolder{
+			ID: cfv.id,
+		}); err != nil {
+			log.Error("[ConfigFileVariable] save auto_increment id", zap.Error(err))
+			return err
+		}
+		if err := saveValue(tx, tblConfigFileVariable, strconv.FormatUint(variable.Id, 10), variable); err != nil {
+			log.Error("[ConfigFileVariable] save info", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return variable, nil
+}
+
+// UpdateConfigFileVariable 更新模板变量的类型、值以及描述
+func (cfv *configFileVariableStore) UpdateConfigFileVariable(variable *model.ConfigFileVariable) error {
+	saved, err := cfv.GetConfigFileVariable(variable.Namespace, variable.Name)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return nil
+	}
+
+	properties := map[string]interface{}{
+		FileVariableFieldType:       variable.Type,
+		FileVariableFieldValue:      variable.Value,
+		FileVariableFieldComment:    variable.Comment,
+		FileVariableFieldModifyTime: time.Now(),
+		FileVariableFieldModifyBy:   variable.ModifyBy,
+	}
+	if err := cfv.handler.UpdateValue(tblConfigFileVariable, strconv.FormatUint(saved.Id, 10),
+		properties); err != nil {
+		log.Error("[ConfigFileVariable] update info", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteConfigFileVariable 删除模板变量
+func (cfv *configFileVariableStore) DeleteConfigFileVariable(namespace, name string) error {
+	saved, err := cfv.GetConfigFileVariable(namespace, name)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return nil
+	}
+	return cfv.handler.DeleteValues(tblConfigFileVariable, []string{strconv.FormatUint(saved.Id, 10)}, false)
+}
+
+// GetConfigFileVariable 获取单个模板变量
+func (cfv *configFileVariableStore) GetConfigFileVariable(namespace,
+	name string) (*model.ConfigFileVariable, error) {
+
+	ret, err := cfv.handler.LoadValuesByFilter(tblConfigFileVariable, []string{FileVariableFieldNamespace,
+		FileVariableFieldName}, &model.ConfigFileVariable{}, cfv.filterByName(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range ret {
+		return v.(*model.ConfigFileVariable), nil
+	}
+	return nil, nil
+}
+
+// QueryConfigFileVariables 获取命名空间下的全部模板变量，按照变量名排序
+func (cfv *configFileVariableStore) QueryConfigFileVariables(namespace string) ([]*model.ConfigFileVariable,
+	error) {
+
+	ret, err := cfv.handler.LoadValuesByFilter(tblConfigFileVariable, []string{FileVariableFieldNamespace},
+		&model.ConfigFileVariable{}, func(m map[string]interface{}) bool {
+			saveNs, _ := m[FileVariableFieldNamespace].(string)
+			return saveNs == namespace
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]*model.ConfigFileVariable, 0, len(ret))
+	for _, v := range ret {
+		variables = append(variables, v.(*model.ConfigFileVariable))
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables, nil
+}
+
+func (cfv *configFileVariableStore) filterByName(namespace, name string) func(m map[string]interface{}) bool {
+	return func(m map[string]interface{}) bool {
+		saveNs, _ := m[FileVariableFieldNamespace].(string)
+		saveName, _ := m[FileVariableFieldName].(string)
+		return saveNs == namespace && saveName == name
+	}
+}
@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+func mockConfigFileVariable(namespace, name, value string) *model.ConfigFileVariable {
+	return &model.ConfigFileVariable{
+		Namespace: namespace,
+		Name:      name,
+		Type:      utils.VariableTypeString,
+		Value:     value,
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
+
+func Test_configFileVariableStore(t *testing.T) {
+	CreateTableDBHandlerAndRun(t, tblConfigFileVariable, func(t *testing.T, handler BoltHandler) {
+		s, err := newConfigFileVariableStore(handler)
+		assert.NoError(t, err)
+
+		variable, err := s.CreateConfigFileVariable(mockConfigFileVariable("ns1", "db.host", "127.0.0.1"))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), variable.Id)
+
+		_, err = s.CreateConfigFileVariable(mockConfigFileVariable("ns1", "db.host", "127.0.0.2"))
+		assert.Equal(t, store.DuplicateEntryErr, store.Code(err))
+
+		_, err = s.CreateConfigFileVariable(mockConfigFileVariable("ns2", "db.host", "127.0.0.2"))
+		assert.NoError(t, err)
+
+		variable.Value = "10.0.0.1"
+		assert.NoError(t, s.UpdateConfigFileVariable(variable))
+		ret, err := s.GetConfigFileVariable("ns1", "db.host")
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.1", ret.Value)
+
+		variables, err := s.QueryConfigFileVariables("ns2")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(variables))
+		assert.Equal(t, "127.0.0.2", variables[0].Value)
+
+		assert.NoError(t, s.DeleteConfigFileVariable("ns1", "db.host"))
+		ret, err = s.GetConfigFileVariable("ns1", "db.host")
+		assert.NoError(t, err)
+		assert.Nil(t, ret)
+	})
+}
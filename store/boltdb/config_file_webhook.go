@@ -0,0 +1,408 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileWebhook           string = "ConfigFileWebhook"
+	tblConfigFileWebhookID         string = "ConfigFileWebhookID"
+	tblConfigFileWebhookDelivery   string = "ConfigFileWebhookDelivery"
+	tblConfigFileWebhookDeliveryID string = "ConfigFileWebhookDeliveryID"
+
+	FileWebhookFieldName       string = "Name"
+	FileWebhookFieldNamespace  string = "Namespace"
+	FileWebhookFieldGroup      string = "Group"
+	FileWebhookFieldFileName   string = "FileName"
+	FileWebhookFieldUrl        string = "Url"
+	FileWebhookFieldSecret     string = "Secret"
+	FileWebhookFieldEvents     string = "Events"
+	FileWebhookFieldEnable     string = "Enable"
+	FileWebhookFieldComment    string = "Comment"
+	FileWebhookFieldModifyTime string = "ModifyTime"
+	FileWebhookFieldModifyBy   string = "ModifyBy"
+
+	WebhookDeliveryFieldWebhookId    string = "WebhookId"
+	WebhookDeliveryFieldEventKey     string = "EventKey"
+	WebhookDeliveryFieldStatus       string = "Status"
+	WebhookDeliveryFieldAttempts     string = "Attempts"
+	WebhookDeliveryFieldResponseCode string = "ResponseCode"
+	WebhookDeliveryFieldError        string = "Error"
+	WebhookDeliveryFieldNextRetry    string = "NextRetryTime"
+	WebhookDeliveryFieldCreateTime   string = "CreateTime"
+	WebhookDeliveryFieldModifyTime   string = "ModifyTime"
+)
+
+type configFileWebhookStore struct {
+	webhookID  uint64
+	deliveryID uint64
+	handler    BoltHandler
+}
+
+func newConfigFileWebhookStore(handler BoltHandler) (*configFileWebhookStore, error) {
+	s := &configFileWebhookStore{handler: handler}
+	ret, err := handler.LoadValues(tblConfigFileWebhookID, []string{tblConfigFileWebhookID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 0 {
+		s.webhookID = ret[tblConfigFileWebhookID].(*IDHolder).ID
+	}
+	ret, err = handler.LoadValues(tblConfigFileWebhookDeliveryID, []string{tblConfigFileWebhookDeliveryID},
+		&IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 0 {
+		s.deliveryID = ret[tblConfigFileWebhookDeliveryID].(*IDHolder).ID
+	}
+	return s, nil
+}
+
+// CreateConfigFileWebhook 创建 webhook，同一个命名空间下名称唯一
+func (cfw *configFileWebhookStore) CreateConfigFileWebhook(
+	webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, error) {
+
+	err := cfw.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValuesByFilter(tx, tblConfigFileWebhook, []string{FileWebhookFieldNamespace,
+			FileWebhookFieldName}, &model.ConfigFileWebhook{}, func(m map[string]interface{}) bool {
+			saveNs, _ := m[FileWebhookFieldNamespace].(string)
+			saveName, _ := m[FileWebhookFieldName].(string)
+			return saveNs == webhook.Namespace && saveName == webhook.Name
+		}, values); err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file webhook existed")
+		}
+
+		cfw.webhookID++
+		webhook.Id = cfw.webhookID
+		tN := time.Now()
+		webhook.CreateTime = tN
+		webhook.ModifyTime = tN
+
+		if err := saveValue(tx, tblConfigFileWebhookID, tblConfigFileWebhookID, &IDHolder{
+			ID: cfw.webhookID,
+		}); err != nil {
+			log.Error("[ConfigFileWebhook] save auto_increment id", zap.Error(err))
+			return err
+		}
+		if err := saveValue(tx, tblConfigFileWebhook, strconv.FormatUint(webhook.Id, 10), webhook); err != nil {
+			log.Error("[ConfigFileWebhook] save info", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateConfigFileWebhook 更新 webhook，名称以及订阅的配置文件范围不能修改
+func (cfw *configFileWebhookStore) UpdateConfigFileWebhook(webhook *model.ConfigFileWebhook) error {
+	properties := map[string]interface{}{
+		FileWebhookFieldUrl:        webhook.Url,
+		FileWebhookFieldSecret:     webhook.Secret,
+		FileWebhookFieldEvents:     webhook.Events,
+		FileWebhookFieldEnable:     webhook.Enable,
+		FileWebhookFieldComment:    webhook.Comment,
+		FileWebhookFieldModifyTime: time.Now(),
+		FileWebhookFieldModifyBy:   webhook.ModifyBy,
+	}
+	if err := cfw.handler.UpdateValue(tblConfigFileWebhook, strconv.FormatUint(webhook.Id, 10),
+		properties); err != nil {
+		log.Error("[ConfigFileWebhook] update info", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteConfigFileWebhook 删除 webhook
+func (cfw *configFileWebhookStore) DeleteConfigFileWebhook(id uint64) error {
+	return cfw.handler.DeleteValues(tblConfigFileWebhook, []string{strconv.FormatUint(id, 10)}, false)
+}
+
+// GetConfigFileWebhook 获取单个 webhook
+func (cfw *configFileWebhookStore) GetConfigFileWebhook(id uint64) (*model.ConfigFileWebhook, error) {
+	key := strconv.FormatUint(id, 10)
+	ret, err := cfw.handler.LoadValues(tblConfigFileWebhook, []string{key}, &model.ConfigFileWebhook{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := ret[key]
+	if !ok {
+		return nil, nil
+	}
+	return data.(*model.ConfigFileWebhook), nil
+}
+
+// GetConfigFileWebhooksByNamespace 获取命名空间下的全部 webhook
+func (cfw *configFileWebhookStore) GetConfigFileWebhooksByNamespace(
+	namespace string) ([]*model.ConfigFileWebhook, error) {
+
+	_, webhooks, err := cfw.loadConfigFileWebhooks(namespace, "", "")
+	return webhooks, err
+}
+
+// QueryConfigFileWebhooks 分页查询 webhook，group、fileName 为空时不作为查询条件
+func (cfw *configFileWebhookStore) QueryConfigFileWebhooks(namespace, group, fileName string, offset,
+	limit uint32) (uint32, []*model.ConfigFileWebhook, error) {
+
+	total, webhooks, err := cfw.loadConfigFileWebhooks(namespace, group, fileName)
+	if err != nil {
+		return 0, nil, err
+	}
+	if offset >= total {
+		return total, []*model.ConfigFileWebhook{}, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return total, webhooks[offset:end], nil
+}
+
+func (cfw *configFileWebhookStore) loadConfigFileWebhooks(namespace, group,
+	fileName string) (uint32, []*model.ConfigFileWebhook, error) {
+
+	fields := []string{FileWebhookFieldNamespace, FileWebhookFieldGroup, FileWebhookFieldFileName}
+	ret, err := cfw.handler.LoadValuesByFilter(tblConfigFileWebhook, fields, &model.ConfigFileWebhook{},
+		func(m map[string]interface{}) bool {
+			if saveNs, _ := m[FileWebhookFieldNamespace].(string); saveNs != namespace {
+				return false
+			}
+			if saveGroup, _ := m[FileWebhookFieldGroup].(string); group != "" && saveGroup != group {
+				return false
+			}
+			if saveName, _ := m[FileWebhookFieldFileName].(string); fileName != "" && saveName != fileName {
+				return false
+			}
+			return true
+		})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	webhooks := make([]*model.ConfigFileWebhook, 0, len(ret))
+	for _, v := range ret {
+		webhooks = append(webhooks, v.(*model.ConfigFileWebhook))
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Id > webhooks[j].Id
+	})
+	return uint32(len(webhooks)), webhooks, nil
+}
+
+// CreateConfigFileWebhookDelivery 创建推送记录，同一个 webhook 的事件已经存在推送记录时返回 DuplicateEntryErr
+func (cfw *configFileWebhookStore) CreateConfigFileWebhookDelivery(
+	delivery *model.ConfigFileWebhookDelivery) (*model.ConfigFileWebhookDelivery, error) {
+
+	err := cfw.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValuesByFilter(tx, tblConfigFileWebhookDelivery, []string{WebhookDeliveryFieldWebhookId,
+			WebhookDeliveryFieldEventKey}, &model.ConfigFileWebhookDelivery{}, func(m map[string]interface{}) bool {
+			saveWebhookId, _ := m[WebhookDeliveryFieldWebhookId].(uint64)
+			saveEventKey, _ := m[WebhookDeliveryFieldEventKey].(string)
+			return saveWebhookId == delivery.WebhookId && saveEventKey == delivery.EventKey
+		}, values); err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file webhook delivery existed")
+		}
+
+		cfw.deliveryID++
+		delivery.Id = cfw.deliveryID
+		tN := time.Now()
+		delivery.CreateTime = tN
+		delivery.ModifyTime = tN
+
+		if err := saveValue(tx, tblConfigFileWebhookDeliveryID, tblConfigFileWebhookDeliveryID, &IDHolder{
+			ID: cfw.deliveryID,
+		}); err != nil {
+			log.Error("[ConfigFileWebhookDelivery] save auto_increment id", zap.Error(err))
+			return err
+		}
+		if err := saveValue(tx, tblConfigFileWebhookDelivery, strconv.FormatUint(delivery.Id, 10),
+			delivery); err != nil {
+			log.Error("[ConfigFileWebhookDelivery] save info", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// UpdateConfigFileWebhookDelivery 更新推送记录的状态
+func (cfw *configFileWebhookStore) UpdateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) error {
+	properties := map[string]interface{}{
+		WebhookDeliveryFieldStatus:       delivery.Status,
+		WebhookDeliveryFieldAttempts:     delivery.Attempts,
+		WebhookDeliveryFieldResponseCode: delivery.ResponseCode,
+		WebhookDeliveryFieldError:        delivery.Error,
+		WebhookDeliveryFieldNextRetry:    delivery.NextRetryTime,
+		WebhookDeliveryFieldModifyTime:   time.Now(),
+	}
+	if err := cfw.handler.UpdateValue(tblConfigFileWebhookDelivery, strconv.FormatUint(delivery.Id, 10),
+		properties); err != nil {
+		log.Error("[ConfigFileWebhookDelivery] update info", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ClaimConfigFileWebhookDelivery 认领待推送的记录，通过推送次数做乐观锁，保证同一次推送只有一个节点认领成功
+func (cfw *configFileWebhookStore) ClaimConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery,
+	leaseTime time.Time) (bool, error) {
+
+	claimed := false
+	err := cfw.handler.Execute(true, func(tx *bolt.Tx) error {
+		key := strconv.FormatUint(delivery.Id, 10)
+		values := make(map[string]interface{})
+		if err := loadValues(tx, tblConfigFileWebhookDelivery, []string{key}, &model.ConfigFileWebhookDelivery{},
+			values); err != nil {
+			return err
+		}
+		saved, ok := values[key].(*model.ConfigFileWebhookDelivery)
+		if !ok || saved.Status != utils.WebhookDeliveryPending || saved.Attempts != delivery.Attempts {
+			return nil
+		}
+		claimed = true
+		return updateValue(tx, tblConfigFileWebhookDelivery, key, map[string]interface{}{
+			WebhookDeliveryFieldAttempts:   saved.Attempts + 1,
+			WebhookDeliveryFieldNextRetry:  leaseTime,
+			WebhookDeliveryFieldModifyTime: time.Now(),
+		})
+	})
+	if err != nil {
+		log.Error("[ConfigFileWebhookDelivery] claim delivery", zap.Error(err))
+		return false, err
+	}
+	if claimed {
+		delivery.Attempts++
+		delivery.NextRetryTime = leaseTime
+	}
+	return claimed, nil
+}
+
+// GetDueConfigFileWebhookDeliveries 获取下一次推送时间早于 now 的待推送记录
+func (cfw *configFileWebhookStore) GetDueConfigFileWebhookDeliveries(now time.Time,
+	limit uint32) ([]*model.ConfigFileWebhookDelivery, error) {
+
+	fields := []string{WebhookDeliveryFieldStatus, WebhookDeliveryFieldNextRetry}
+	ret, err := cfw.handler.LoadValuesByFilter(tblConfigFileWebhookDelivery, fields,
+		&model.ConfigFileWebhookDelivery{}, func(m map[string]interface{}) bool {
+			if saveStatus, _ := m[WebhookDeliveryFieldStatus].(string); saveStatus != utils.WebhookDeliveryPending {
+				return false
+			}
+			saveRetryTime, _ := m[WebhookDeliveryFieldNextRetry].(time.Time)
+			return !saveRetryTime.After(now)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*model.ConfigFileWebhookDelivery, 0, len(ret))
+	for _, v := range ret {
+		deliveries = append(deliveries, v.(*model.ConfigFileWebhookDelivery))
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].NextRetryTime.Before(deliveries[j].NextRetryTime)
+	})
+	if uint32(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// QueryConfigFileWebhookDeliveries 分页查询 webhook 的推送记录，status 为空时查询全部状态
+func (cfw *configFileWebhookStore) QueryConfigFileWebhookDeliveries(webhookId uint64, status string, offset,
+	limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, error) {
+
+	fields := []string{WebhookDeliveryFieldWebhookId, WebhookDeliveryFieldStatus}
+	ret, err := cfw.handler.LoadValuesByFilter(tblConfigFileWebhookDelivery, fields,
+		&model.ConfigFileWebhookDelivery{}, func(m map[string]interface{}) bool {
+			if saveWebhookId, _ := m[WebhookDeliveryFieldWebhookId].(uint64); saveWebhookId != webhookId {
+				return false
+			}
+			if saveStatus, _ := m[WebhookDeliveryFieldStatus].(string); status != "" && saveStatus != status {
+				return false
+			}
+			return true
+		})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	deliveries := make([]*model.ConfigFileWebhookDelivery, 0, len(ret))
+	for _, v := range ret {
+		deliveries = append(deliveries, v.(*model.ConfigFileWebhookDelivery))
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Id > deliveries[j].Id
+	})
+
+	total := uint32(len(deliveries))
+	if offset >= total {
+		return total, []*model.ConfigFileWebhookDelivery{}, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return total, deliveries[offset:end], nil
+}
+
+// CleanConfigFileWebhookDeliveries 清理创建时间早于 before 的推送记录
+func (cfw *configFileWebhookStore) CleanConfigFileWebhookDeliveries(before time.Time) error {
+	return cfw.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValuesByFilter(tx, tblConfigFileWebhookDelivery, []string{WebhookDeliveryFieldCreateTime},
+			&model.ConfigFileWebhookDelivery{}, func(m map[string]interface{}) bool {
+				saveCt, _ := m[WebhookDeliveryFieldCreateTime].(time.Time)
+				return saveCt.Before(before)
+			}, values); err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		return deleteValues(tx, tblConfigFileWebhookDelivery, keys, false)
+	})
+}
@@ -0,0 +1,175 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+func mockConfigFileWebhook(name, group string) *model.ConfigFileWebhook {
+	return &model.ConfigFileWebhook{
+		Name:      name,
+		Namespace: "config-file-webhook",
+		Group:     group,
+		Url:       "http://127.0.0.1:8080/hook",
+		Secret:    "secret",
+		Events:    utils.WebhookEventPublish,
+		Enable:    true,
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
+
+func mockConfigFileWebhookDelivery(webhookId uint64, eventKey string) *model.ConfigFileWebhookDelivery {
+	return &model.ConfigFileWebhookDelivery{
+		WebhookId: webhookId,
+		EventKey:  eventKey,
+		EventType: utils.WebhookEventPublish,
+		Namespace: "config-file-webhook",
+		Group:     "group",
+		FileName:  "file",
+		Payload:   "{}",
+		Status:    utils.WebhookDeliveryPending,
+	}
+}
+
+func Test_configFileWebhookStore(t *testing.T) {
+	t.Run("创建并更新webhook", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileWebhook, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileWebhookStore(handler)
+			assert.NoError(t, err)
+
+			webhook, err := s.CreateConfigFileWebhook(mockConfigFileWebhook("hook1", ""))
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), webhook.Id)
+
+			_, err = s.CreateConfigFileWebhook(mockConfigFileWebhook("hook1", "group"))
+			assert.Equal(t, store.DuplicateEntryErr, store.Code(err))
+
+			webhook.Url = "https://127.0.0.1/hook"
+			webhook.Enable = false
+			webhook.Group = "ignored"
+			assert.NoError(t, s.UpdateConfigFileWebhook(webhook))
+
+			ret, err := s.GetConfigFileWebhook(webhook.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, "https://127.0.0.1/hook", ret.Url)
+			assert.False(t, ret.Enable)
+			assert.Equal(t, "", ret.Group)
+
+			_, err = s.CreateConfigFileWebhook(mockConfigFileWebhook("hook2", "group"))
+			assert.NoError(t, err)
+			total, webhooks, err := s.QueryConfigFileWebhooks(webhook.Namespace, "group", "", 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+			assert.Equal(t, "hook2", webhooks[0].Name)
+
+			webhooks, err = s.GetConfigFileWebhooksByNamespace(webhook.Namespace)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(webhooks))
+
+			assert.NoError(t, s.DeleteConfigFileWebhook(webhook.Id))
+			ret, err = s.GetConfigFileWebhook(webhook.Id)
+			assert.NoError(t, err)
+			assert.Nil(t, ret)
+		})
+	})
+
+	t.Run("推送记录", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileWebhookDelivery, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileWebhookStore(handler)
+			assert.NoError(t, err)
+
+			delivery, err := s.CreateConfigFileWebhookDelivery(mockConfigFileWebhookDelivery(1, "event-1"))
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), delivery.Id)
+
+			// 同一个事件只能创建一条推送记录
+			_, err = s.CreateConfigFileWebhookDelivery(mockConfigFileWebhookDelivery(1, "event-1"))
+			assert.Equal(t, store.DuplicateEntryErr, store.Code(err))
+			_, err = s.CreateConfigFileWebhookDelivery(mockConfigFileWebhookDelivery(2, "event-1"))
+			assert.NoError(t, err)
+			_, err = s.CreateConfigFileWebhookDelivery(mockConfigFileWebhookDelivery(1, "event-2"))
+			assert.NoError(t, err)
+
+			delivery.Status = utils.WebhookDeliverySuccess
+			delivery.Attempts = 1
+			delivery.ResponseCode = 200
+			assert.NoError(t, s.UpdateConfigFileWebhookDelivery(delivery))
+
+			total, deliveries, err := s.QueryConfigFileWebhookDeliveries(1, "", 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), total)
+			assert.Equal(t, "event-2", deliveries[0].EventKey)
+
+			total, deliveries, err = s.QueryConfigFileWebhookDeliveries(1, utils.WebhookDeliverySuccess, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+			assert.Equal(t, 200, deliveries[0].ResponseCode)
+
+			assert.NoError(t, s.CleanConfigFileWebhookDeliveries(time.Now().Add(time.Minute)))
+			total, _, err = s.QueryConfigFileWebhookDeliveries(1, "", 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(0), total)
+		})
+	})
+
+	t.Run("认领到期的推送记录", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileWebhookDelivery, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileWebhookStore(handler)
+			assert.NoError(t, err)
+
+			now := time.Now()
+			due := mockConfigFileWebhookDelivery(1, "event-1")
+			due.NextRetryTime = now.Add(-time.Second)
+			due, err = s.CreateConfigFileWebhookDelivery(due)
+			assert.NoError(t, err)
+			later := mockConfigFileWebhookDelivery(1, "event-2")
+			later.NextRetryTime = now.Add(time.Minute)
+			_, err = s.CreateConfigFileWebhookDelivery(later)
+			assert.NoError(t, err)
+
+			deliveries, err := s.GetDueConfigFileWebhookDeliveries(now, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(deliveries))
+			assert.Equal(t, due.Id, deliveries[0].Id)
+
+			// 多个节点同时认领同一条推送记录，只有一个节点能够认领成功
+			other := *deliveries[0]
+			claimed, err := s.ClaimConfigFileWebhookDelivery(deliveries[0], now.Add(time.Minute))
+			assert.NoError(t, err)
+			assert.True(t, claimed)
+			assert.Equal(t, 1, deliveries[0].Attempts)
+			claimed, err = s.ClaimConfigFileWebhookDelivery(&other, now.Add(time.Minute))
+			assert.NoError(t, err)
+			assert.False(t, claimed)
+
+			// 认领期间不会再被获取
+			deliveries, err = s.GetDueConfigFileWebhookDeliveries(now, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, len(deliveries))
+		})
+	})
+}
@@ -18,6 +18,8 @@
 package boltdb
 
 import (
+	"errors"
+	"io"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -32,6 +34,9 @@ import (
 
 const (
 	STORENAME = "boltdbStore"
+
+	// tblAppliedIndex 保存 ApplyWithIndex 已经执行的最大 index
+	tblAppliedIndex = "applied_index"
 )
 
 type boltStore struct {
@@ -60,6 +65,11 @@ type boltStore struct {
 	*configFileGroupStore
 	*configFileStore
 	*configFileReleaseStore
+	*configFileGrayReleaseStore
+	*configFileChangeStore
+	*configFileWebhookStore
+	*configFileVariableStore
+	*configFileClientPinStore
 	*configFileReleaseHistoryStore
 	*configFileTagStore
 	*configFileTemplateStore
@@ -69,6 +79,9 @@ type boltStore struct {
 
 	handler BoltHandler
 	start   bool
+
+	// applyStore 与当前 store 共用一个 boltdb，读写操作都绑定在 ApplyWithIndex 的事务中
+	applyStore *boltStore
 }
 
 // Name store name
@@ -88,6 +101,7 @@ func (m *boltStore) Initialize(c *store.Config) error {
 		return err
 	}
 	m.handler = handler
+	m.applyStore = nil
 	if err = m.newStore(); err != nil {
 		_ = handler.Close()
 		return err
@@ -242,16 +256,19 @@ func (m *boltStore) initAuthStoreData() error {
 }
 
 func (m *boltStore) newStore() error {
-	var err error
-
-	m.l5Store = &l5Store{handler: m.handler}
-	if err = m.l5Store.InitL5Data(); err != nil {
+	if err := m.bindStores(); err != nil {
 		return err
 	}
-	m.namespaceStore = &namespaceStore{handler: m.handler}
-	if err = m.namespaceStore.InitData(); err != nil {
+	if err := m.l5Store.InitL5Data(); err != nil {
 		return err
 	}
+	return m.namespaceStore.InitData()
+}
+
+// bindStores 创建使用 m.handler 的各个子 store
+func (m *boltStore) bindStores() error {
+	m.l5Store = &l5Store{handler: m.handler}
+	m.namespaceStore = &namespaceStore{handler: m.handler}
 	m.businessStore = &businessStore{handler: m.handler}
 	m.platformStore = &platformStore{handler: m.handler}
 	m.clientStore = &clientStore{handler: m.handler}
@@ -323,11 +340,36 @@ func (m *boltStore) newConfigModuleStore() error {
 		return err
 	}
 
+	m.configFileGrayReleaseStore, err = newConfigFileGrayReleaseStore(m.handler)
+	if err != nil {
+		return err
+	}
+
+	m.configFileChangeStore, err = newConfigFileChangeStore(m.handler)
+	if err != nil {
+		return err
+	}
+
 	m.configFileTemplateStore, err = newConfigFileTemplateStore(m.handler)
 	if err != nil {
 		return err
 	}
 
+	m.configFileWebhookStore, err = newConfigFileWebhookStore(m.handler)
+	if err != nil {
+		return err
+	}
+
+	m.configFileVariableStore, err = newConfigFileVariableStore(m.handler)
+	if err != nil {
+		return err
+	}
+
+	m.configFileClientPinStore, err = newConfigFileClientPinStore(m.handler)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -350,6 +392,96 @@ func (m *boltStore) StartTx() (store.Tx, error) {
 	return m.handler.StartTx()
 }
 
+// LocalStore boltdb store used as the local storage by other stores, like raftStore
+type LocalStore interface {
+	store.Store
+
+	// StartReadTx starting read only transactions
+	StartReadTx() (store.Tx, error)
+
+	// Snapshot write a consistent copy of all the data to w
+	Snapshot(w io.Writer) error
+
+	// ApplyWithIndex run process and save index in one writable transaction, all the reads and writes
+	// of the store passed to process are bound to the transaction. If process fails, all of its writes
+	// are rolled back and only the index is saved
+	ApplyWithIndex(index uint64, process func(s store.Store) error) error
+
+	// AppliedIndex the max index saved by ApplyWithIndex
+	AppliedIndex() (uint64, error)
+}
+
+// NewLocalStore create a boltdb store which is not registered to the store slots
+func NewLocalStore() LocalStore {
+	return &boltStore{}
+}
+
+// StartReadTx starting read only transactions
+func (m *boltStore) StartReadTx() (store.Tx, error) {
+	return m.handler.StartReadTx()
+}
+
+// Snapshot write a consistent copy of all the data to w
+func (m *boltStore) Snapshot(w io.Writer) error {
+	return m.handler.Execute(false, func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
+// ApplyWithIndex run process and save index in one writable transaction
+func (m *boltStore) ApplyWithIndex(index uint64, process func(s store.Store) error) error {
+	if m.applyStore == nil {
+		handler, ok := m.handler.(*boltHandler)
+		if !ok {
+			return errors.New("boltdb handler does not support binding transaction")
+		}
+		applyStore := &boltStore{handler: &boltHandler{db: handler.db}, start: true}
+		if err := applyStore.bindStores(); err != nil {
+			return err
+		}
+		m.applyStore = applyStore
+	}
+	applyHandler := m.applyStore.handler.(*boltHandler)
+
+	var processErr error
+	err := m.handler.Execute(true, func(tx *bolt.Tx) error {
+		applyHandler.bound = &boundTx{delegateTx: tx}
+		defer func() {
+			applyHandler.bound = nil
+		}()
+
+		processErr = process(m.applyStore)
+		if processErr == nil && applyHandler.bound.rollback {
+			processErr = errors.New("write operation failed in the applying transaction")
+		}
+		if processErr != nil {
+			return processErr
+		}
+		return saveValue(tx, tblAppliedIndex, tblAppliedIndex, &IDHolder{ID: index})
+	})
+	if processErr == nil {
+		return err
+	}
+	// process 的写操作已经全部回滚，单独保存 index，重启后不需要再次执行
+	if err := m.handler.SaveValue(tblAppliedIndex, tblAppliedIndex, &IDHolder{ID: index}); err != nil {
+		return err
+	}
+	return processErr
+}
+
+// AppliedIndex the max index saved by ApplyWithIndex
+func (m *boltStore) AppliedIndex() (uint64, error) {
+	ret, err := m.handler.LoadValues(tblAppliedIndex, []string{tblAppliedIndex}, &IDHolder{})
+	if err != nil {
+		return 0, err
+	}
+	if len(ret) == 0 {
+		return 0, nil
+	}
+	return ret[tblAppliedIndex].(*IDHolder).ID, nil
+}
+
 func init() {
 	s := &boltStore{}
 	_ = store.RegisterStore(s)
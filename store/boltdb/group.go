@@ -86,7 +86,7 @@ func (gs *groupStore) AddGroup(group *model.UserGroupDetail) error {
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	if err := gs.cleanInValidGroup(tx, group.Name, group.Owner); err != nil {
 		logger.StoreScope().Error("[Store][Group] clean invalid usergroup", zap.Error(err),
@@ -94,11 +94,12 @@ func (gs *groupStore) AddGroup(group *model.UserGroupDetail) error {
 		return err
 	}
 
-	return gs.addGroup(tx, group)
+	return gs.addGroup(proxy, group)
 }
 
 // addGroup to boltdb
-func (gs *groupStore) addGroup(tx *bolt.Tx, group *model.UserGroupDetail) error {
+func (gs *groupStore) addGroup(proxy store.Tx, group *model.UserGroupDetail) error {
+	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
 	group.Valid = true
 	group.CreateTime = time.Now()
@@ -121,7 +122,7 @@ func (gs *groupStore) addGroup(tx *bolt.Tx, group *model.UserGroupDetail) error
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][Group] add usergroup tx commit", zap.Error(err),
 			zap.String("name", group.Name), zap.String("owner", group.Owner))
 		return err
@@ -147,7 +148,7 @@ func (gs *groupStore) updateGroup(group *model.ModifyUserGroup) error {
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	values := make(map[string]interface{})
 
@@ -181,7 +182,7 @@ func (gs *groupStore) updateGroup(group *model.ModifyUserGroup) error {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][Group] update usergroup tx commit",
 			zap.Error(err), zap.String("id", ret.ID))
 		return err
@@ -217,7 +218,7 @@ func (gs *groupStore) deleteGroup(group *model.UserGroupDetail) error {
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	properties := make(map[string]interface{})
 	properties[GroupFieldValid] = false
@@ -234,7 +235,7 @@ func (gs *groupStore) deleteGroup(group *model.UserGroupDetail) error {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][Group] delete usergroupr tx commit",
 			zap.Error(err), zap.String("id", group.ID))
 		return err
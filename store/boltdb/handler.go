@@ -76,6 +76,9 @@ type BoltHandler interface {
 	// StartTx start new tx
 	StartTx() (store.Tx, error)
 
+	// StartReadTx start new read only tx
+	StartReadTx() (store.Tx, error)
+
 	// Close boltdb
 	Close() error
 }
@@ -115,6 +118,8 @@ func NewBoltHandler(config *BoltConfig) (BoltHandler, error) {
 
 type boltHandler struct {
 	db *bolt.DB
+	// bound 不为空时，全部的读写操作都在这个事务中执行，由事务的创建者统一提交或者回滚
+	bound *boundTx
 }
 
 func openBoltDB(path string) (*bolt.DB, error) {
@@ -125,7 +130,7 @@ func openBoltDB(path string) (*bolt.DB, error) {
 
 // SaveValue insert data object, each data object should be identified by unique key
 func (b *boltHandler) SaveValue(typ string, key string, value interface{}) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.Execute(true, func(tx *bolt.Tx) error {
 		return saveValue(tx, typ, key, value)
 	})
 }
@@ -181,7 +186,7 @@ func (b *boltHandler) LoadValues(typ string, keys []string, typObject interface{
 	if len(keys) == 0 {
 		return values, nil
 	}
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.Execute(false, func(tx *bolt.Tx) error {
 		return loadValues(tx, typ, keys, typObject, values)
 	})
 	return values, err
@@ -206,7 +211,7 @@ func loadValues(tx *bolt.Tx, typ string, keys []string, typObject interface{}, v
 func (b *boltHandler) LoadValuesByFilter(typ string, fields []string,
 	typObject interface{}, filter func(map[string]interface{}) bool) (map[string]interface{}, error) {
 	values := make(map[string]interface{})
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.Execute(false, func(tx *bolt.Tx) error {
 		return loadValuesByFilter(tx, typ, fields, typObject, filter, values)
 	})
 	return values, err
@@ -350,7 +355,7 @@ func (b *boltHandler) IterateFields(typ string, field string, typObject interfac
 	if filter == nil {
 		return nil
 	}
-	return b.db.View(func(tx *bolt.Tx) error {
+	return b.Execute(false, func(tx *bolt.Tx) error {
 		typeBucket := tx.Bucket([]byte(typ))
 		if typeBucket == nil {
 			return nil
@@ -392,7 +397,7 @@ func (b *boltHandler) DeleteValues(typ string, keys []string, logicDelete bool)
 	if len(keys) == 0 {
 		return nil
 	}
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.Execute(true, func(tx *bolt.Tx) error {
 		return deleteValues(tx, typ, keys, logicDelete)
 	})
 }
@@ -471,7 +476,7 @@ func getKeys(bucket *bolt.Bucket) ([]string, error) {
 // CountValues count all data objects
 func (b *boltHandler) CountValues(typ string) (int, error) {
 	var count int
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.Execute(false, func(tx *bolt.Tx) error {
 		typeBucket := tx.Bucket([]byte(typ))
 		if typeBucket == nil {
 			return nil
@@ -504,7 +509,7 @@ func (b *boltHandler) CountValues(typ string) (int, error) {
 
 // UpdateValue update properties of data object
 func (b *boltHandler) UpdateValue(typ string, key string, properties map[string]interface{}) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.Execute(true, func(tx *bolt.Tx) error {
 		return updateValue(tx, typ, key, properties)
 	})
 }
@@ -565,7 +570,7 @@ func updateValue(tx *bolt.Tx, typ string, key string, properties map[string]inte
 // LoadValuesAll load all saved data objects, return value is 'key->object' map
 func (b *boltHandler) LoadValuesAll(typ string, typObject interface{}) (map[string]interface{}, error) {
 	values := make(map[string]interface{})
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.Execute(false, func(tx *bolt.Tx) error {
 		typeBucket := tx.Bucket([]byte(typ))
 		if typeBucket == nil {
 			return nil
@@ -597,6 +602,13 @@ func (b *boltHandler) LoadValuesAll(typ string, typObject interface{}) (map[stri
 
 // Execute execute scripts directly
 func (b *boltHandler) Execute(writable bool, process func(tx *bolt.Tx) error) error {
+	if b.bound != nil {
+		err := process(b.bound.delegateTx)
+		if err != nil && writable {
+			b.bound.rollback = true
+		}
+		return err
+	}
 	if writable {
 		return b.db.Update(process)
 	}
@@ -605,9 +617,24 @@ func (b *boltHandler) Execute(writable bool, process func(tx *bolt.Tx) error) er
 
 // StartTx start a new tx
 func (b *boltHandler) StartTx() (store.Tx, error) {
+	if b.bound != nil {
+		return &nestedTx{parent: b.bound}, nil
+	}
 	tx, err := b.db.Begin(true)
 	if err != nil {
 		return nil, err
 	}
 	return NewBoltTx(tx), nil
 }
+
+// StartReadTx start a new read only tx
+func (b *boltHandler) StartReadTx() (store.Tx, error) {
+	if b.bound != nil {
+		return &nestedTx{parent: b.bound}, nil
+	}
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoltTx(tx), nil
+}
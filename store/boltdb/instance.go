@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
@@ -48,18 +49,21 @@ const (
 // AddInstance add an instance
 func (i *instanceStore) AddInstance(instance *model.Instance) error {
 	initInstance([]*model.Instance{instance})
-	// Before adding new data, you must clean up the old data
-	if err := i.handler.DeleteValues(tblNameInstance, []string{instance.ID()}, true); err != nil {
-		log.Errorf("[Store][boltdb] delete instance to kv error, %v", err)
-		return err
-	}
-
-	if err := i.handler.SaveValue(tblNameInstance, instance.ID(), instance); err != nil {
-		log.Errorf("[Store][boltdb] save instance to kv error, %v", err)
-		return err
-	}
-
-	return nil
+	return i.handler.Execute(true, func(tx *bolt.Tx) error {
+		if err := checkInstanceQuotaWithTx(tx, []*model.Instance{instance}); err != nil {
+			return err
+		}
+		// Before adding new data, you must clean up the old data
+		if err := deleteValues(tx, tblNameInstance, []string{instance.ID()}, true); err != nil {
+			log.Errorf("[Store][boltdb] delete instance to kv error, %v", err)
+			return err
+		}
+		if err := saveValue(tx, tblNameInstance, instance.ID(), instance); err != nil {
+			log.Errorf("[Store][boltdb] save instance to kv error, %v", err)
+			return err
+		}
+		return nil
+	})
 }
 
 // BatchAddInstances Add multiple instances
@@ -74,21 +78,24 @@ func (i *instanceStore) BatchAddInstances(instances []*model.Instance) error {
 		insIds = append(insIds, instance.ID())
 	}
 
-	// clear old instances
-	if err := i.handler.DeleteValues(tblNameInstance, insIds, true); err != nil {
-		log.Errorf("[Store][boltdb] save instance to kv error, %v", err)
-		return err
-	}
-
 	initInstance(instances)
-	for _, instance := range instances {
-		if err := i.handler.SaveValue(tblNameInstance, instance.ID(), instance); err != nil {
+	return i.handler.Execute(true, func(tx *bolt.Tx) error {
+		if err := checkInstanceQuotaWithTx(tx, instances); err != nil {
+			return err
+		}
+		// clear old instances
+		if err := deleteValues(tx, tblNameInstance, insIds, true); err != nil {
 			log.Errorf("[Store][boltdb] save instance to kv error, %v", err)
 			return err
 		}
-	}
-
-	return nil
+		for _, instance := range instances {
+			if err := saveValue(tx, tblNameInstance, instance.ID(), instance); err != nil {
+				log.Errorf("[Store][boltdb] save instance to kv error, %v", err)
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // UpdateInstance Update instance
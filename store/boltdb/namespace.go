@@ -24,7 +24,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/boltdb/bolt"
+
 	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
 )
 
 const (
@@ -115,6 +118,9 @@ func (n *namespaceStore) UpdateNamespace(namespace *model.Namespace) error {
 	properties := make(map[string]interface{})
 	properties["Owner"] = namespace.Owner
 	properties["Comment"] = namespace.Comment
+	properties["ServiceQuota"] = namespace.ServiceQuota
+	properties["InstanceQuota"] = namespace.InstanceQuota
+	properties["ConfigFileQuota"] = namespace.ConfigFileQuota
 	properties["ModifyTime"] = time.Now()
 	return n.handler.UpdateValue(tblNameNamespace, namespace.Name, properties)
 }
@@ -164,6 +170,118 @@ func (n *namespaceStore) GetNamespace(name string) (*model.Namespace, error) {
 	return ns, nil
 }
 
+// loadNamespaceWithTx 在事务内查询命名空间
+func loadNamespaceWithTx(tx *bolt.Tx, name string) (*model.Namespace, error) {
+	values := make(map[string]interface{})
+	if err := loadValues(tx, tblNameNamespace, []string{name}, &model.Namespace{}, values); err != nil {
+		return nil, err
+	}
+	ns, _ := values[name].(*model.Namespace)
+	return ns, nil
+}
+
+// checkServiceQuotaWithTx 在写事务内检查命名空间的服务配额
+// boltdb 的写事务是串行执行的，配额检查与服务写入放在同一个事务内，避免并发创建超出配额
+func checkServiceQuotaWithTx(tx *bolt.Tx, namespace string) error {
+	ns, err := loadNamespaceWithTx(tx, namespace)
+	if err != nil || ns == nil || ns.ServiceQuota == 0 {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	fields := []string{SvcFieldNamespace, SvcFieldValid}
+	err = loadValuesByFilter(tx, tblNameService, fields, &model.Service{},
+		func(m map[string]interface{}) bool {
+			if valid, _ := m[SvcFieldValid].(bool); !valid {
+				return false
+			}
+			saveNs, _ := m[SvcFieldNamespace].(string)
+			return saveNs == namespace
+		}, values)
+	if err != nil {
+		return err
+	}
+	if uint32(len(values)) >= ns.ServiceQuota {
+		return store.NewStatusError(store.ExceedNamespaceQuota,
+			fmt.Sprintf("namespace(%s) exceed service quota %d", namespace, ns.ServiceQuota))
+	}
+	return nil
+}
+
+// checkInstanceQuotaWithTx 在写事务内检查命名空间的实例配额，已经存在的实例重新注册不占用新的配额
+// 实例数据中不保存命名空间，需要通过实例所属的服务找到对应的命名空间
+// 服务层根据缓存判断距离配额还有足够余量的实例（SkipQuotaCheck）不需要统计检查
+func checkInstanceQuotaWithTx(tx *bolt.Tx, instances []*model.Instance) error {
+	svcIds := make([]string, 0, len(instances))
+	checks := make([]*model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.SkipQuotaCheck {
+			continue
+		}
+		svcIds = append(svcIds, instance.ServiceID)
+		checks = append(checks, instance)
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+	services := make(map[string]interface{})
+	if err := loadValues(tx, tblNameService, svcIds, &model.Service{}, services); err != nil {
+		return err
+	}
+	newIds := make(map[string][]string)
+	for _, instance := range checks {
+		svc, ok := services[instance.ServiceID].(*model.Service)
+		if !ok {
+			continue
+		}
+		newIds[svc.Namespace] = append(newIds[svc.Namespace], instance.ID())
+	}
+
+	for namespace, ids := range newIds {
+		ns, err := loadNamespaceWithTx(tx, namespace)
+		if err != nil {
+			return err
+		}
+		if ns == nil || ns.InstanceQuota == 0 {
+			continue
+		}
+
+		nsServices := make(map[string]interface{})
+		err = loadValuesByFilter(tx, tblNameService, []string{SvcFieldNamespace, SvcFieldValid}, &model.Service{},
+			func(m map[string]interface{}) bool {
+				valid, _ := m[SvcFieldValid].(bool)
+				saveNs, _ := m[SvcFieldNamespace].(string)
+				return valid && saveNs == namespace
+			}, nsServices)
+		if err != nil {
+			return err
+		}
+		nsInstances := make(map[string]interface{})
+		err = loadValuesByFilter(tx, tblNameInstance, []string{insFieldServiceID, insFieldValid}, &model.Instance{},
+			func(m map[string]interface{}) bool {
+				valid, _ := m[insFieldValid].(bool)
+				svcId, _ := m[insFieldServiceID].(string)
+				_, ok := nsServices[svcId]
+				return valid && ok
+			}, nsInstances)
+		if err != nil {
+			return err
+		}
+
+		total := uint32(len(nsInstances))
+		for _, id := range ids {
+			if _, ok := nsInstances[id]; !ok {
+				total++
+			}
+		}
+		if total > ns.InstanceQuota {
+			return store.NewStatusError(store.ExceedNamespaceQuota,
+				fmt.Sprintf("namespace(%s) exceed instance quota %d", namespace, ns.InstanceQuota))
+		}
+	}
+	return nil
+}
+
 type NamespaceSlice []*model.Namespace
 
 // Len length of namespace slice
@@ -24,7 +24,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
 )
 
 const (
@@ -409,3 +414,48 @@ func TestTransaction_DeleteNamespace(t *testing.T) {
 		}
 	}
 }
+
+func TestNamespaceStore_Quota(t *testing.T) {
+	CreateTableDBHandlerAndRun(t, tblNameNamespace, func(t *testing.T, handler BoltHandler) {
+		nsStore := &namespaceStore{handler: handler}
+		sStore := &serviceStore{handler: handler}
+		insStore := &instanceStore{handler: handler}
+
+		err := nsStore.AddNamespace(&model.Namespace{
+			Name:          "quota",
+			Owner:         nsOwner,
+			ServiceQuota:  1,
+			InstanceQuota: 2,
+			Valid:         true,
+		})
+		assert.NoError(t, err)
+
+		err = sStore.AddService(&model.Service{ID: "quota-svc-0", Name: "quota-svc-0", Namespace: "quota"})
+		assert.NoError(t, err)
+		err = sStore.AddService(&model.Service{ID: "quota-svc-1", Name: "quota-svc-1", Namespace: "quota"})
+		assert.Equal(t, store.ExceedNamespaceQuota, store.Code(err))
+
+		genInstance := func(id string) *model.Instance {
+			return &model.Instance{
+				ServiceID: "quota-svc-0",
+				Proto: &api.Instance{
+					Id:      &wrappers.StringValue{Value: id},
+					Service: &wrappers.StringValue{Value: "quota-svc-0"},
+					Host:    &wrappers.StringValue{Value: "127.0.0.1"},
+				},
+			}
+		}
+		err = insStore.BatchAddInstances([]*model.Instance{genInstance("ins-0"), genInstance("ins-1")})
+		assert.NoError(t, err)
+		err = insStore.AddInstance(genInstance("ins-2"))
+		assert.Equal(t, store.ExceedNamespaceQuota, store.Code(err))
+		// 已经存在的实例重新注册不占用新的配额
+		err = insStore.AddInstance(genInstance("ins-1"))
+		assert.NoError(t, err)
+		// 服务层标记跳过检查的实例不再统计配额
+		skip := genInstance("ins-3")
+		skip.SkipQuotaCheck = true
+		err = insStore.AddInstance(skip)
+		assert.NoError(t, err)
+	})
+}
@@ -244,7 +244,7 @@ func (r *routingStoreV2) GetRoutingConfigV2WithID(id string) (*v2.RoutingConfig,
 	}
 
 	boldTx := tx.GetDelegateTx().(*bolt.Tx)
-	defer boldTx.Rollback()
+	defer tx.Rollback()
 
 	return r.getRoutingConfigV2WithIDTx(boldTx, id)
 }
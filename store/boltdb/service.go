@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/boltdb/bolt"
 	"go.uber.org/zap"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
@@ -77,7 +78,12 @@ func (ss *serviceStore) AddService(s *model.Service) error {
 		return store.NewStatusError(store.EmptyParamsErr, "add Service missing some params")
 	}
 
-	err := ss.handler.SaveValue(tblNameService, s.ID, s)
+	err := ss.handler.Execute(true, func(tx *bolt.Tx) error {
+		if err := checkServiceQuotaWithTx(tx, s.Namespace); err != nil {
+			return err
+		}
+		return saveValue(tx, tblNameService, s.ID, s)
+	})
 
 	return store.Error(err)
 }
@@ -96,14 +96,14 @@ func (ss *strategyStore) AddStrategy(strategy *model.StrategyDetail) error {
 	if err != nil {
 		return err
 	}
-	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
-	return ss.addStrategy(tx, strategy)
+	return ss.addStrategy(proxy, strategy)
 }
 
-func (ss *strategyStore) addStrategy(tx *bolt.Tx, strategy *model.StrategyDetail) error {
+func (ss *strategyStore) addStrategy(proxy store.Tx, strategy *model.StrategyDetail) error {
+	tx := proxy.GetDelegateTx().(*bolt.Tx)
 	if err := ss.cleanInvalidStrategy(tx, strategy.Name, strategy.Owner); err != nil {
 		logger.StoreScope().Error("[Store][Strategy] clean invalid auth_strategy", zap.Error(err),
 			zap.String("name", strategy.Name), zap.Any("owner", strategy.Owner))
@@ -116,7 +116,7 @@ func (ss *strategyStore) addStrategy(tx *bolt.Tx, strategy *model.StrategyDetail
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][Strategy] clean invalid auth_strategy tx commit", zap.Error(err),
 			zap.String("name", strategy.Name), zap.String("owner", strategy.Owner))
 		return err
@@ -138,7 +138,7 @@ func (ss *strategyStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) er
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	ret, err := loadStrategyById(tx, strategy.ID)
 	if err != nil {
@@ -148,12 +148,13 @@ func (ss *strategyStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) er
 		return ErrorStrategyNotFound
 	}
 
-	return ss.updateStrategy(tx, strategy, ret)
+	return ss.updateStrategy(proxy, strategy, ret)
 }
 
 // updateStrategy
-func (ss *strategyStore) updateStrategy(tx *bolt.Tx, modify *model.ModifyStrategyDetail,
+func (ss *strategyStore) updateStrategy(proxy store.Tx, modify *model.ModifyStrategyDetail,
 	saveVal *strategyForStore) error {
+	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
 	saveVal.Action = modify.Action
 	saveVal.Comment = modify.Comment
@@ -173,7 +174,7 @@ func (ss *strategyStore) updateStrategy(tx *bolt.Tx, modify *model.ModifyStrateg
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][Strategy] update auth_strategy tx commit", zap.Error(err),
 			zap.String("id", saveVal.ID))
 		return err
@@ -267,7 +268,7 @@ func (ss *strategyStore) operateStrategyResources(remove bool, resources []model
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	resMap := buildResMap(resources)
 
@@ -289,7 +290,7 @@ func (ss *strategyStore) operateStrategyResources(remove bool, resources []model
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][Strategy] update auth_strategy resource tx commit",
 			zap.Error(err), zap.Bool("remove", remove))
 		return err
@@ -353,7 +354,7 @@ func (ss *strategyStore) GetStrategyDetail(id string) (*model.StrategyDetail, er
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	return ss.getStrategyDetail(tx, id)
 }
@@ -44,3 +44,28 @@ func (t *Tx) Rollback() error {
 func (t *Tx) GetDelegateTx() interface{} {
 	return t.delegateTx
 }
+
+// boundTx 绑定在 handler 上的写事务，handler 上的全部读写操作都在这个事务中执行
+type boundTx struct {
+	delegateTx *bolt.Tx
+	// rollback 事务中有写操作失败，整个事务都不能提交
+	rollback bool
+}
+
+// nestedTx 在绑定的事务中开启的事务，提交和回滚都由绑定事务的创建者统一处理。
+// 只读的方法同样通过回滚结束事务，因此这里的回滚不会影响绑定的事务，写方法失败时由调用方根据返回的错误回滚
+type nestedTx struct {
+	parent *boundTx
+}
+
+func (t *nestedTx) Commit() error {
+	return nil
+}
+
+func (t *nestedTx) Rollback() error {
+	return nil
+}
+
+func (t *nestedTx) GetDelegateTx() interface{} {
+	return t.parent.delegateTx
+}
@@ -95,7 +95,7 @@ func (us *userStore) addUser(user *model.User) error {
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	owner := user.Owner
 	if owner == "" {
@@ -114,7 +114,7 @@ func (us *userStore) addUser(user *model.User) error {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][User] save user tx commit fail", zap.Error(err),
 			zap.String("name", user.Name))
 		return err
@@ -171,7 +171,7 @@ func (us *userStore) deleteUser(user *model.User) error {
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	properties := make(map[string]interface{})
 	properties[UserFieldValid] = false
@@ -191,7 +191,7 @@ func (us *userStore) deleteUser(user *model.User) error {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := proxy.Commit(); err != nil {
 		logger.StoreScope().Error("[Store][User] delete user tx commit", zap.Error(err), zap.String("id", user.ID))
 		return err
 	}
@@ -212,7 +212,7 @@ func (us *userStore) GetUser(id string) (*model.User, error) {
 	}
 	tx := proxy.GetDelegateTx().(*bolt.Tx)
 
-	defer tx.Rollback()
+	defer proxy.Rollback()
 
 	return us.getUser(tx, id)
 }
@@ -28,9 +28,14 @@ type ConfigFileModuleStore interface {
 	ConfigFileGroupStore
 	ConfigFileStore
 	ConfigFileReleaseStore
+	ConfigFileGrayReleaseStore
+	ConfigFileChangeStore
 	ConfigFileReleaseHistoryStore
 	ConfigFileTagStore
 	ConfigFileTemplateStore
+	ConfigFileWebhookStore
+	ConfigFileVariableStore
+	ConfigFileClientPinStore
 }
 
 // ConfigFileGroupStore 配置文件组存储接口
@@ -81,6 +86,12 @@ type ConfigFileStore interface {
 
 	// CountByConfigFileGroup 获取一个配置文件组下的文件数量
 	CountByConfigFileGroup(namespace, group string) (uint64, error)
+
+	// CountConfigFiles 获取一个命名空间下的文件数量
+	CountConfigFiles(namespace string) (uint64, error)
+
+	// CountConfigFilesByNamespaces 按命名空间分组统计文件数量
+	CountConfigFilesByNamespaces(namespaces []string) (map[string]uint64, error)
 }
 
 // ConfigFileReleaseStore 配置文件发布存储接口
@@ -106,6 +117,49 @@ type ConfigFileReleaseStore interface {
 	FindConfigFileReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileRelease, error)
 }
 
+// ConfigFileGrayReleaseStore 配置文件灰度发布存储接口
+type ConfigFileGrayReleaseStore interface {
+
+	// CreateConfigFileGrayRelease 创建配置文件灰度发布
+	CreateConfigFileGrayRelease(tx Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error)
+
+	// UpdateConfigFileGrayRelease 更新配置文件灰度发布
+	UpdateConfigFileGrayRelease(tx Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error)
+
+	// GetConfigFileGrayRelease 获取配置文件的灰度发布
+	GetConfigFileGrayRelease(tx Tx, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error)
+
+	// DeleteConfigFileGrayRelease 删除配置文件的灰度发布，灰度发布结束时调用
+	DeleteConfigFileGrayRelease(tx Tx, namespace, group, fileName string) error
+
+	// FindConfigFileGrayReleaseByModifyTimeAfter 获取最近更新的配置文件灰度发布
+	FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error)
+
+	// IncrConfigFileGrayReleaseErrorCount 灰度发布的 md5 与客户端上报的 md5 一致时累加加载失败次数，
+	// 返回累加后的次数，灰度发布不存在或者 md5 不一致时返回 0
+	IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName, md5 string) (uint32, error)
+}
+
+// ConfigFileChangeStore 配置文件变更审核存储接口
+type ConfigFileChangeStore interface {
+
+	// CreateConfigFileChange 创建配置文件变更
+	CreateConfigFileChange(tx Tx, change *model.ConfigFileChange) (*model.ConfigFileChange, error)
+
+	// UpdateConfigFileChange 更新配置文件变更的内容以及审核状态
+	UpdateConfigFileChange(tx Tx, change *model.ConfigFileChange) (*model.ConfigFileChange, error)
+
+	// GetConfigFileChange 获取单个配置文件变更
+	GetConfigFileChange(tx Tx, id uint64) (*model.ConfigFileChange, error)
+
+	// GetLatestConfigFileChange 获取配置文件最后一次的变更
+	GetLatestConfigFileChange(tx Tx, namespace, group, fileName string) (*model.ConfigFileChange, error)
+
+	// QueryConfigFileChanges 查询配置文件变更，status 为空时查询全部状态，按照 id 倒序返回
+	QueryConfigFileChanges(namespace, group, fileName, status string,
+		offset, limit uint32) (uint32, []*model.ConfigFileChange, error)
+}
+
 // ConfigFileReleaseHistoryStore 配置文件发布历史存储接口
 type ConfigFileReleaseHistoryStore interface {
 
@@ -118,6 +172,9 @@ type ConfigFileReleaseHistoryStore interface {
 
 	// GetLatestConfigFileReleaseHistory 获取配置文件最后一次发布
 	GetLatestConfigFileReleaseHistory(namespace, group, fileName string) (*model.ConfigFileReleaseHistory, error)
+
+	// GetConfigFileReleaseHistory 根据 ID 获取配置文件发布历史记录
+	GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error)
 }
 
 type ConfigFileTagStore interface {
@@ -148,4 +205,97 @@ type ConfigFileTemplateStore interface {
 
 	// GetConfigFileTemplate get config file template by name
 	GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error)
+
+	// QueryAllConfigFileSchemas query all config file json schemas
+	QueryAllConfigFileSchemas() ([]*model.ConfigFileSchema, error)
+
+	// CreateConfigFileSchema create config file json schema
+	CreateConfigFileSchema(schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error)
+
+	// UpdateConfigFileSchema update config file json schema
+	UpdateConfigFileSchema(schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error)
+
+	// GetConfigFileSchema get config file json schema by name
+	GetConfigFileSchema(name string) (*model.ConfigFileSchema, error)
+}
+
+// ConfigFileWebhookStore 配置文件变更订阅存储接口
+type ConfigFileWebhookStore interface {
+	// CreateConfigFileWebhook 创建 webhook，同一个命名空间下名称唯一
+	CreateConfigFileWebhook(webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, error)
+
+	// UpdateConfigFileWebhook 更新 webhook
+	UpdateConfigFileWebhook(webhook *model.ConfigFileWebhook) error
+
+	// DeleteConfigFileWebhook 删除 webhook
+	DeleteConfigFileWebhook(id uint64) error
+
+	// GetConfigFileWebhook 获取单个 webhook
+	GetConfigFileWebhook(id uint64) (*model.ConfigFileWebhook, error)
+
+	// GetConfigFileWebhooksByNamespace 获取命名空间下的全部 webhook
+	GetConfigFileWebhooksByNamespace(namespace string) ([]*model.ConfigFileWebhook, error)
+
+	// QueryConfigFileWebhooks 分页查询 webhook，group、fileName 为空时不作为查询条件
+	QueryConfigFileWebhooks(namespace, group, fileName string, offset,
+		limit uint32) (uint32, []*model.ConfigFileWebhook, error)
+
+	// CreateConfigFileWebhookDelivery 创建推送记录，同一个 webhook 的事件已经存在推送记录时返回 DuplicateEntryErr
+	CreateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) (*model.ConfigFileWebhookDelivery, error)
+
+	// UpdateConfigFileWebhookDelivery 更新推送记录的状态
+	UpdateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) error
+
+	// ClaimConfigFileWebhookDelivery 认领待推送的记录，推送次数与 delivery 一致时才能认领成功，
+	// 认领后推送次数加一，并且在 leaseTime 之前不会被其他节点认领
+	ClaimConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery, leaseTime time.Time) (bool, error)
+
+	// GetDueConfigFileWebhookDeliveries 获取下一次推送时间早于 now 的待推送记录
+	GetDueConfigFileWebhookDeliveries(now time.Time, limit uint32) ([]*model.ConfigFileWebhookDelivery, error)
+
+	// QueryConfigFileWebhookDeliveries 分页查询 webhook 的推送记录，status 为空时查询全部状态
+	QueryConfigFileWebhookDeliveries(webhookId uint64, status string, offset,
+		limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, error)
+
+	// CleanConfigFileWebhookDeliveries 清理创建时间早于 before 的推送记录
+	CleanConfigFileWebhookDeliveries(before time.Time) error
+}
+
+// ConfigFileVariableStore 配置文件模板变量存储接口
+type ConfigFileVariableStore interface {
+	// CreateConfigFileVariable 创建模板变量，同一个命名空间下变量名唯一
+	CreateConfigFileVariable(variable *model.ConfigFileVariable) (*model.ConfigFileVariable, error)
+
+	// UpdateConfigFileVariable 更新模板变量的类型、值以及描述
+	UpdateConfigFileVariable(variable *model.ConfigFileVariable) error
+
+	// DeleteConfigFileVariable 删除模板变量
+	DeleteConfigFileVariable(namespace, name string) error
+
+	// GetConfigFileVariable 获取单个模板变量
+	GetConfigFileVariable(namespace, name string) (*model.ConfigFileVariable, error)
+
+	// QueryConfigFileVariables 获取命名空间下的全部模板变量
+	QueryConfigFileVariables(namespace string) ([]*model.ConfigFileVariable, error)
+}
+
+// ConfigFileClientPinStore 客户端版本固定存储接口
+type ConfigFileClientPinStore interface {
+	// CreateConfigFileClientPin 固定客户端的版本，同一个配置文件下客户端唯一
+	CreateConfigFileClientPin(pin *model.ConfigFileClientPin) (*model.ConfigFileClientPin, error)
+
+	// UpdateConfigFileClientPin 更新客户端固定的发布内容
+	UpdateConfigFileClientPin(pin *model.ConfigFileClientPin) error
+
+	// DeleteConfigFileClientPin 取消客户端的版本固定
+	DeleteConfigFileClientPin(namespace, group, fileName, client string) error
+
+	// GetConfigFileClientPin 获取客户端的版本固定
+	GetConfigFileClientPin(namespace, group, fileName, client string) (*model.ConfigFileClientPin, error)
+
+	// QueryConfigFileClientPins 获取配置文件的全部版本固定
+	QueryConfigFileClientPins(namespace, group, fileName string) ([]*model.ConfigFileClientPin, error)
+
+	// GetAllConfigFileClientPins 获取全部的版本固定，用于发布事件扫描器刷新缓存
+	GetAllConfigFileClientPins() ([]*model.ConfigFileClientPin, error)
 }
@@ -234,6 +234,35 @@ func (mr *MockStoreMockRecorder) BatchSetInstanceIsolate(ids, isolate, revision
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchSetInstanceIsolate", reflect.TypeOf((*MockStore)(nil).BatchSetInstanceIsolate), ids, isolate, revision)
 }
 
+// ClaimConfigFileWebhookDelivery mocks base method.
+func (m *MockStore) ClaimConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery, leaseTime time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimConfigFileWebhookDelivery", delivery, leaseTime)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimConfigFileWebhookDelivery indicates an expected call of ClaimConfigFileWebhookDelivery.
+func (mr *MockStoreMockRecorder) ClaimConfigFileWebhookDelivery(delivery, leaseTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimConfigFileWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ClaimConfigFileWebhookDelivery), delivery, leaseTime)
+}
+
+// CleanConfigFileWebhookDeliveries mocks base method.
+func (m *MockStore) CleanConfigFileWebhookDeliveries(before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanConfigFileWebhookDeliveries", before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CleanConfigFileWebhookDeliveries indicates an expected call of CleanConfigFileWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CleanConfigFileWebhookDeliveries(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanConfigFileWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CleanConfigFileWebhookDeliveries), before)
+}
+
 // CleanInstance mocks base method.
 func (m *MockStore) CleanInstance(instanceID string) error {
 	m.ctrl.T.Helper()
@@ -263,6 +292,36 @@ func (mr *MockStoreMockRecorder) CountByConfigFileGroup(namespace, group interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByConfigFileGroup", reflect.TypeOf((*MockStore)(nil).CountByConfigFileGroup), namespace, group)
 }
 
+// CountConfigFiles mocks base method.
+func (m *MockStore) CountConfigFiles(namespace string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountConfigFiles", namespace)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountConfigFiles indicates an expected call of CountConfigFiles.
+func (mr *MockStoreMockRecorder) CountConfigFiles(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountConfigFiles", reflect.TypeOf((*MockStore)(nil).CountConfigFiles), namespace)
+}
+
+// CountConfigFilesByNamespaces mocks base method.
+func (m *MockStore) CountConfigFilesByNamespaces(namespaces []string) (map[string]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountConfigFilesByNamespaces", namespaces)
+	ret0, _ := ret[0].(map[string]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountConfigFilesByNamespaces indicates an expected call of CountConfigFilesByNamespaces.
+func (mr *MockStoreMockRecorder) CountConfigFilesByNamespaces(namespaces interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountConfigFilesByNamespaces", reflect.TypeOf((*MockStore)(nil).CountConfigFilesByNamespaces), namespaces)
+}
+
 // CreateCircuitBreaker mocks base method.
 func (m *MockStore) CreateCircuitBreaker(circuitBreaker *model.CircuitBreaker) error {
 	m.ctrl.T.Helper()
@@ -292,6 +351,51 @@ func (mr *MockStoreMockRecorder) CreateConfigFile(tx, file interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFile", reflect.TypeOf((*MockStore)(nil).CreateConfigFile), tx, file)
 }
 
+// CreateConfigFileChange mocks base method.
+func (m *MockStore) CreateConfigFileChange(tx store.Tx, change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileChange", tx, change)
+	ret0, _ := ret[0].(*model.ConfigFileChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileChange indicates an expected call of CreateConfigFileChange.
+func (mr *MockStoreMockRecorder) CreateConfigFileChange(tx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileChange", reflect.TypeOf((*MockStore)(nil).CreateConfigFileChange), tx, change)
+}
+
+// CreateConfigFileClientPin mocks base method.
+func (m *MockStore) CreateConfigFileClientPin(pin *model.ConfigFileClientPin) (*model.ConfigFileClientPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileClientPin", pin)
+	ret0, _ := ret[0].(*model.ConfigFileClientPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileClientPin indicates an expected call of CreateConfigFileClientPin.
+func (mr *MockStoreMockRecorder) CreateConfigFileClientPin(pin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileClientPin", reflect.TypeOf((*MockStore)(nil).CreateConfigFileClientPin), pin)
+}
+
+// CreateConfigFileGrayRelease mocks base method.
+func (m *MockStore) CreateConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileGrayRelease", tx, grayRelease)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileGrayRelease indicates an expected call of CreateConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) CreateConfigFileGrayRelease(tx, grayRelease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).CreateConfigFileGrayRelease), tx, grayRelease)
+}
+
 // CreateConfigFileGroup mocks base method.
 func (m *MockStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -336,6 +440,21 @@ func (mr *MockStoreMockRecorder) CreateConfigFileReleaseHistory(tx, fileReleaseH
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileReleaseHistory", reflect.TypeOf((*MockStore)(nil).CreateConfigFileReleaseHistory), tx, fileReleaseHistory)
 }
 
+// CreateConfigFileSchema mocks base method.
+func (m *MockStore) CreateConfigFileSchema(schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileSchema", schema)
+	ret0, _ := ret[0].(*model.ConfigFileSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileSchema indicates an expected call of CreateConfigFileSchema.
+func (mr *MockStoreMockRecorder) CreateConfigFileSchema(schema interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileSchema", reflect.TypeOf((*MockStore)(nil).CreateConfigFileSchema), schema)
+}
+
 // CreateConfigFileTag mocks base method.
 func (m *MockStore) CreateConfigFileTag(tx store.Tx, fileTag *model.ConfigFileTag) error {
 	m.ctrl.T.Helper()
@@ -365,6 +484,51 @@ func (mr *MockStoreMockRecorder) CreateConfigFileTemplate(template interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileTemplate", reflect.TypeOf((*MockStore)(nil).CreateConfigFileTemplate), template)
 }
 
+// CreateConfigFileVariable mocks base method.
+func (m *MockStore) CreateConfigFileVariable(variable *model.ConfigFileVariable) (*model.ConfigFileVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileVariable", variable)
+	ret0, _ := ret[0].(*model.ConfigFileVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileVariable indicates an expected call of CreateConfigFileVariable.
+func (mr *MockStoreMockRecorder) CreateConfigFileVariable(variable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileVariable", reflect.TypeOf((*MockStore)(nil).CreateConfigFileVariable), variable)
+}
+
+// CreateConfigFileWebhook mocks base method.
+func (m *MockStore) CreateConfigFileWebhook(webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileWebhook", webhook)
+	ret0, _ := ret[0].(*model.ConfigFileWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileWebhook indicates an expected call of CreateConfigFileWebhook.
+func (mr *MockStoreMockRecorder) CreateConfigFileWebhook(webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileWebhook", reflect.TypeOf((*MockStore)(nil).CreateConfigFileWebhook), webhook)
+}
+
+// CreateConfigFileWebhookDelivery mocks base method.
+func (m *MockStore) CreateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) (*model.ConfigFileWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileWebhookDelivery", delivery)
+	ret0, _ := ret[0].(*model.ConfigFileWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileWebhookDelivery indicates an expected call of CreateConfigFileWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateConfigFileWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateConfigFileWebhookDelivery), delivery)
+}
+
 // CreatePlatform mocks base method.
 func (m *MockStore) CreatePlatform(platform *model.Platform) error {
 	m.ctrl.T.Helper()
@@ -478,6 +642,34 @@ func (mr *MockStoreMockRecorder) DeleteConfigFile(tx, namespace, group, name int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFile", reflect.TypeOf((*MockStore)(nil).DeleteConfigFile), tx, namespace, group, name)
 }
 
+// DeleteConfigFileClientPin mocks base method.
+func (m *MockStore) DeleteConfigFileClientPin(namespace, group, fileName, client string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileClientPin", namespace, group, fileName, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileClientPin indicates an expected call of DeleteConfigFileClientPin.
+func (mr *MockStoreMockRecorder) DeleteConfigFileClientPin(namespace, group, fileName, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileClientPin", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileClientPin), namespace, group, fileName, client)
+}
+
+// DeleteConfigFileGrayRelease mocks base method.
+func (m *MockStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group, fileName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileGrayRelease", tx, namespace, group, fileName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileGrayRelease indicates an expected call of DeleteConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) DeleteConfigFileGrayRelease(tx, namespace, group, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileGrayRelease), tx, namespace, group, fileName)
+}
+
 // DeleteConfigFileGroup mocks base method.
 func (m *MockStore) DeleteConfigFileGroup(namespace, name string) error {
 	m.ctrl.T.Helper()
@@ -520,6 +712,34 @@ func (mr *MockStoreMockRecorder) DeleteConfigFileTag(tx, namespace, group, fileN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileTag", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileTag), tx, namespace, group, fileName, key, value)
 }
 
+// DeleteConfigFileVariable mocks base method.
+func (m *MockStore) DeleteConfigFileVariable(namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileVariable", namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileVariable indicates an expected call of DeleteConfigFileVariable.
+func (mr *MockStoreMockRecorder) DeleteConfigFileVariable(namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileVariable", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileVariable), namespace, name)
+}
+
+// DeleteConfigFileWebhook mocks base method.
+func (m *MockStore) DeleteConfigFileWebhook(id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileWebhook", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileWebhook indicates an expected call of DeleteConfigFileWebhook.
+func (mr *MockStoreMockRecorder) DeleteConfigFileWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileWebhook", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileWebhook), id)
+}
+
 // DeleteGroup mocks base method.
 func (m *MockStore) DeleteGroup(group *model.UserGroupDetail) error {
 	m.ctrl.T.Helper()
@@ -758,6 +978,21 @@ func (mr *MockStoreMockRecorder) EnableRouting(conf interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableRouting", reflect.TypeOf((*MockStore)(nil).EnableRouting), conf)
 }
 
+// FindConfigFileGrayReleaseByModifyTimeAfter mocks base method.
+func (m *MockStore) FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConfigFileGrayReleaseByModifyTimeAfter", modifyTime)
+	ret0, _ := ret[0].([]*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter indicates an expected call of FindConfigFileGrayReleaseByModifyTimeAfter.
+func (mr *MockStoreMockRecorder) FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConfigFileGrayReleaseByModifyTimeAfter", reflect.TypeOf((*MockStore)(nil).FindConfigFileGrayReleaseByModifyTimeAfter), modifyTime)
+}
+
 // FindConfigFileGroups mocks base method.
 func (m *MockStore) FindConfigFileGroups(namespace string, names []string) ([]*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -803,6 +1038,21 @@ func (mr *MockStoreMockRecorder) GenNextL5Sid(layoutID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenNextL5Sid", reflect.TypeOf((*MockStore)(nil).GenNextL5Sid), layoutID)
 }
 
+// GetAllConfigFileClientPins mocks base method.
+func (m *MockStore) GetAllConfigFileClientPins() ([]*model.ConfigFileClientPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllConfigFileClientPins")
+	ret0, _ := ret[0].([]*model.ConfigFileClientPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllConfigFileClientPins indicates an expected call of GetAllConfigFileClientPins.
+func (mr *MockStoreMockRecorder) GetAllConfigFileClientPins() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllConfigFileClientPins", reflect.TypeOf((*MockStore)(nil).GetAllConfigFileClientPins))
+}
+
 // GetBusinessByID mocks base method.
 func (m *MockStore) GetBusinessByID(id string) (*model.Business, error) {
 	m.ctrl.T.Helper()
@@ -923,6 +1173,51 @@ func (mr *MockStoreMockRecorder) GetConfigFile(tx, namespace, group, name interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFile", reflect.TypeOf((*MockStore)(nil).GetConfigFile), tx, namespace, group, name)
 }
 
+// GetConfigFileChange mocks base method.
+func (m *MockStore) GetConfigFileChange(tx store.Tx, id uint64) (*model.ConfigFileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileChange", tx, id)
+	ret0, _ := ret[0].(*model.ConfigFileChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileChange indicates an expected call of GetConfigFileChange.
+func (mr *MockStoreMockRecorder) GetConfigFileChange(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileChange", reflect.TypeOf((*MockStore)(nil).GetConfigFileChange), tx, id)
+}
+
+// GetConfigFileClientPin mocks base method.
+func (m *MockStore) GetConfigFileClientPin(namespace, group, fileName, client string) (*model.ConfigFileClientPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileClientPin", namespace, group, fileName, client)
+	ret0, _ := ret[0].(*model.ConfigFileClientPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileClientPin indicates an expected call of GetConfigFileClientPin.
+func (mr *MockStoreMockRecorder) GetConfigFileClientPin(namespace, group, fileName, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileClientPin", reflect.TypeOf((*MockStore)(nil).GetConfigFileClientPin), namespace, group, fileName, client)
+}
+
+// GetConfigFileGrayRelease mocks base method.
+func (m *MockStore) GetConfigFileGrayRelease(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileGrayRelease", tx, namespace, group, fileName)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileGrayRelease indicates an expected call of GetConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) GetConfigFileGrayRelease(tx, namespace, group, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).GetConfigFileGrayRelease), tx, namespace, group, fileName)
+}
+
 // GetConfigFileGroup mocks base method.
 func (m *MockStore) GetConfigFileGroup(namespace, name string) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -968,6 +1263,21 @@ func (mr *MockStoreMockRecorder) GetConfigFileRelease(tx, namespace, group, file
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileRelease", reflect.TypeOf((*MockStore)(nil).GetConfigFileRelease), tx, namespace, group, fileName)
 }
 
+// GetConfigFileReleaseHistory mocks base method.
+func (m *MockStore) GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileReleaseHistory", id)
+	ret0, _ := ret[0].(*model.ConfigFileReleaseHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileReleaseHistory indicates an expected call of GetConfigFileReleaseHistory.
+func (mr *MockStoreMockRecorder) GetConfigFileReleaseHistory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileReleaseHistory", reflect.TypeOf((*MockStore)(nil).GetConfigFileReleaseHistory), id)
+}
+
 // GetConfigFileReleaseWithAllFlag mocks base method.
 func (m *MockStore) GetConfigFileReleaseWithAllFlag(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileRelease, error) {
 	m.ctrl.T.Helper()
@@ -983,6 +1293,21 @@ func (mr *MockStoreMockRecorder) GetConfigFileReleaseWithAllFlag(tx, namespace,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileReleaseWithAllFlag", reflect.TypeOf((*MockStore)(nil).GetConfigFileReleaseWithAllFlag), tx, namespace, group, fileName)
 }
 
+// GetConfigFileSchema mocks base method.
+func (m *MockStore) GetConfigFileSchema(name string) (*model.ConfigFileSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileSchema", name)
+	ret0, _ := ret[0].(*model.ConfigFileSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileSchema indicates an expected call of GetConfigFileSchema.
+func (mr *MockStoreMockRecorder) GetConfigFileSchema(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileSchema", reflect.TypeOf((*MockStore)(nil).GetConfigFileSchema), name)
+}
+
 // GetConfigFileTemplate mocks base method.
 func (m *MockStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
 	m.ctrl.T.Helper()
@@ -998,6 +1323,51 @@ func (mr *MockStoreMockRecorder) GetConfigFileTemplate(name interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileTemplate", reflect.TypeOf((*MockStore)(nil).GetConfigFileTemplate), name)
 }
 
+// GetConfigFileVariable mocks base method.
+func (m *MockStore) GetConfigFileVariable(namespace, name string) (*model.ConfigFileVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileVariable", namespace, name)
+	ret0, _ := ret[0].(*model.ConfigFileVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileVariable indicates an expected call of GetConfigFileVariable.
+func (mr *MockStoreMockRecorder) GetConfigFileVariable(namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileVariable", reflect.TypeOf((*MockStore)(nil).GetConfigFileVariable), namespace, name)
+}
+
+// GetConfigFileWebhook mocks base method.
+func (m *MockStore) GetConfigFileWebhook(id uint64) (*model.ConfigFileWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileWebhook", id)
+	ret0, _ := ret[0].(*model.ConfigFileWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileWebhook indicates an expected call of GetConfigFileWebhook.
+func (mr *MockStoreMockRecorder) GetConfigFileWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileWebhook", reflect.TypeOf((*MockStore)(nil).GetConfigFileWebhook), id)
+}
+
+// GetConfigFileWebhooksByNamespace mocks base method.
+func (m *MockStore) GetConfigFileWebhooksByNamespace(namespace string) ([]*model.ConfigFileWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileWebhooksByNamespace", namespace)
+	ret0, _ := ret[0].([]*model.ConfigFileWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileWebhooksByNamespace indicates an expected call of GetConfigFileWebhooksByNamespace.
+func (mr *MockStoreMockRecorder) GetConfigFileWebhooksByNamespace(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileWebhooksByNamespace", reflect.TypeOf((*MockStore)(nil).GetConfigFileWebhooksByNamespace), namespace)
+}
+
 // GetDefaultStrategyDetailByPrincipal mocks base method.
 func (m *MockStore) GetDefaultStrategyDetailByPrincipal(principalId string, principalType model.PrincipalType) (*model.StrategyDetail, error) {
 	m.ctrl.T.Helper()
@@ -1013,6 +1383,21 @@ func (mr *MockStoreMockRecorder) GetDefaultStrategyDetailByPrincipal(principalId
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultStrategyDetailByPrincipal", reflect.TypeOf((*MockStore)(nil).GetDefaultStrategyDetailByPrincipal), principalId, principalType)
 }
 
+// GetDueConfigFileWebhookDeliveries mocks base method.
+func (m *MockStore) GetDueConfigFileWebhookDeliveries(now time.Time, limit uint32) ([]*model.ConfigFileWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueConfigFileWebhookDeliveries", now, limit)
+	ret0, _ := ret[0].([]*model.ConfigFileWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueConfigFileWebhookDeliveries indicates an expected call of GetDueConfigFileWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetDueConfigFileWebhookDeliveries(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueConfigFileWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetDueConfigFileWebhookDeliveries), now, limit)
+}
+
 // GetExpandInstances mocks base method.
 func (m *MockStore) GetExpandInstances(filter, metaFilter map[string]string, offset, limit uint32) (uint32, []*model.Instance, error) {
 	m.ctrl.T.Helper()
@@ -1181,6 +1566,21 @@ func (mr *MockStoreMockRecorder) GetL5Extend(serviceID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetL5Extend", reflect.TypeOf((*MockStore)(nil).GetL5Extend), serviceID)
 }
 
+// GetLatestConfigFileChange mocks base method.
+func (m *MockStore) GetLatestConfigFileChange(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestConfigFileChange", tx, namespace, group, fileName)
+	ret0, _ := ret[0].(*model.ConfigFileChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestConfigFileChange indicates an expected call of GetLatestConfigFileChange.
+func (mr *MockStoreMockRecorder) GetLatestConfigFileChange(tx, namespace, group, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestConfigFileChange", reflect.TypeOf((*MockStore)(nil).GetLatestConfigFileChange), tx, namespace, group, fileName)
+}
+
 // GetLatestConfigFileReleaseHistory mocks base method.
 func (m *MockStore) GetLatestConfigFileReleaseHistory(namespace, group, fileName string) (*model.ConfigFileReleaseHistory, error) {
 	m.ctrl.T.Helper()
@@ -1834,6 +2234,21 @@ func (mr *MockStoreMockRecorder) GetUsersForCache(mtime, firstUpdate interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersForCache", reflect.TypeOf((*MockStore)(nil).GetUsersForCache), mtime, firstUpdate)
 }
 
+// IncrConfigFileGrayReleaseErrorCount mocks base method.
+func (m *MockStore) IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName, md5 string) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrConfigFileGrayReleaseErrorCount", namespace, group, fileName, md5)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrConfigFileGrayReleaseErrorCount indicates an expected call of IncrConfigFileGrayReleaseErrorCount.
+func (mr *MockStoreMockRecorder) IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName, md5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrConfigFileGrayReleaseErrorCount", reflect.TypeOf((*MockStore)(nil).IncrConfigFileGrayReleaseErrorCount), namespace, group, fileName, md5)
+}
+
 // Initialize mocks base method.
 func (m *MockStore) Initialize(c *store.Config) error {
 	m.ctrl.T.Helper()
@@ -1936,6 +2351,21 @@ func (mr *MockStoreMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockStore)(nil).Name))
 }
 
+// QueryAllConfigFileSchemas mocks base method.
+func (m *MockStore) QueryAllConfigFileSchemas() ([]*model.ConfigFileSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAllConfigFileSchemas")
+	ret0, _ := ret[0].([]*model.ConfigFileSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAllConfigFileSchemas indicates an expected call of QueryAllConfigFileSchemas.
+func (mr *MockStoreMockRecorder) QueryAllConfigFileSchemas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAllConfigFileSchemas", reflect.TypeOf((*MockStore)(nil).QueryAllConfigFileSchemas))
+}
+
 // QueryAllConfigFileTemplates mocks base method.
 func (m *MockStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
 	m.ctrl.T.Helper()
@@ -1971,6 +2401,37 @@ func (mr *MockStoreMockRecorder) QueryConfigFileByTag(namespace, group, fileName
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileByTag", reflect.TypeOf((*MockStore)(nil).QueryConfigFileByTag), varargs...)
 }
 
+// QueryConfigFileChanges mocks base method.
+func (m *MockStore) QueryConfigFileChanges(namespace, group, fileName, status string, offset, limit uint32) (uint32, []*model.ConfigFileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryConfigFileChanges", namespace, group, fileName, status, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.ConfigFileChange)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryConfigFileChanges indicates an expected call of QueryConfigFileChanges.
+func (mr *MockStoreMockRecorder) QueryConfigFileChanges(namespace, group, fileName, status, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileChanges", reflect.TypeOf((*MockStore)(nil).QueryConfigFileChanges), namespace, group, fileName, status, offset, limit)
+}
+
+// QueryConfigFileClientPins mocks base method.
+func (m *MockStore) QueryConfigFileClientPins(namespace, group, fileName string) ([]*model.ConfigFileClientPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryConfigFileClientPins", namespace, group, fileName)
+	ret0, _ := ret[0].([]*model.ConfigFileClientPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryConfigFileClientPins indicates an expected call of QueryConfigFileClientPins.
+func (mr *MockStoreMockRecorder) QueryConfigFileClientPins(namespace, group, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileClientPins", reflect.TypeOf((*MockStore)(nil).QueryConfigFileClientPins), namespace, group, fileName)
+}
+
 // QueryConfigFileGroups mocks base method.
 func (m *MockStore) QueryConfigFileGroups(namespace, name string, offset, limit uint32) (uint32, []*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -2003,6 +2464,53 @@ func (mr *MockStoreMockRecorder) QueryConfigFileReleaseHistories(namespace, grou
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileReleaseHistories", reflect.TypeOf((*MockStore)(nil).QueryConfigFileReleaseHistories), namespace, group, fileName, offset, limit, endId)
 }
 
+// QueryConfigFileVariables mocks base method.
+func (m *MockStore) QueryConfigFileVariables(namespace string) ([]*model.ConfigFileVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryConfigFileVariables", namespace)
+	ret0, _ := ret[0].([]*model.ConfigFileVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryConfigFileVariables indicates an expected call of QueryConfigFileVariables.
+func (mr *MockStoreMockRecorder) QueryConfigFileVariables(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileVariables", reflect.TypeOf((*MockStore)(nil).QueryConfigFileVariables), namespace)
+}
+
+// QueryConfigFileWebhookDeliveries mocks base method.
+func (m *MockStore) QueryConfigFileWebhookDeliveries(webhookId uint64, status string, offset, limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryConfigFileWebhookDeliveries", webhookId, status, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.ConfigFileWebhookDelivery)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryConfigFileWebhookDeliveries indicates an expected call of QueryConfigFileWebhookDeliveries.
+func (mr *MockStoreMockRecorder) QueryConfigFileWebhookDeliveries(webhookId, status, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).QueryConfigFileWebhookDeliveries), webhookId, status, offset, limit)
+}
+
+// QueryConfigFileWebhooks mocks base method.
+func (m *MockStore) QueryConfigFileWebhooks(namespace, group, fileName string, offset, limit uint32) (uint32, []*model.ConfigFileWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryConfigFileWebhooks", namespace, group, fileName, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.ConfigFileWebhook)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryConfigFileWebhooks indicates an expected call of QueryConfigFileWebhooks.
+func (mr *MockStoreMockRecorder) QueryConfigFileWebhooks(namespace, group, fileName, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileWebhooks", reflect.TypeOf((*MockStore)(nil).QueryConfigFileWebhooks), namespace, group, fileName, offset, limit)
+}
+
 // QueryConfigFiles mocks base method.
 func (m *MockStore) QueryConfigFiles(namespace, group, name string, offset, limit uint32) (uint32, []*model.ConfigFile, error) {
 	m.ctrl.T.Helper()
@@ -2207,6 +2715,50 @@ func (mr *MockStoreMockRecorder) UpdateConfigFile(tx, file interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFile", reflect.TypeOf((*MockStore)(nil).UpdateConfigFile), tx, file)
 }
 
+// UpdateConfigFileChange mocks base method.
+func (m *MockStore) UpdateConfigFileChange(tx store.Tx, change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileChange", tx, change)
+	ret0, _ := ret[0].(*model.ConfigFileChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateConfigFileChange indicates an expected call of UpdateConfigFileChange.
+func (mr *MockStoreMockRecorder) UpdateConfigFileChange(tx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileChange", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileChange), tx, change)
+}
+
+// UpdateConfigFileClientPin mocks base method.
+func (m *MockStore) UpdateConfigFileClientPin(pin *model.ConfigFileClientPin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileClientPin", pin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileClientPin indicates an expected call of UpdateConfigFileClientPin.
+func (mr *MockStoreMockRecorder) UpdateConfigFileClientPin(pin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileClientPin", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileClientPin), pin)
+}
+
+// UpdateConfigFileGrayRelease mocks base method.
+func (m *MockStore) UpdateConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileGrayRelease", tx, grayRelease)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateConfigFileGrayRelease indicates an expected call of UpdateConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) UpdateConfigFileGrayRelease(tx, grayRelease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileGrayRelease), tx, grayRelease)
+}
+
 // UpdateConfigFileGroup mocks base method.
 func (m *MockStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -2237,6 +2789,63 @@ func (mr *MockStoreMockRecorder) UpdateConfigFileRelease(tx, fileRelease interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileRelease", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileRelease), tx, fileRelease)
 }
 
+// UpdateConfigFileSchema mocks base method.
+func (m *MockStore) UpdateConfigFileSchema(schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileSchema", schema)
+	ret0, _ := ret[0].(*model.ConfigFileSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateConfigFileSchema indicates an expected call of UpdateConfigFileSchema.
+func (mr *MockStoreMockRecorder) UpdateConfigFileSchema(schema interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileSchema", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileSchema), schema)
+}
+
+// UpdateConfigFileVariable mocks base method.
+func (m *MockStore) UpdateConfigFileVariable(variable *model.ConfigFileVariable) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileVariable", variable)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileVariable indicates an expected call of UpdateConfigFileVariable.
+func (mr *MockStoreMockRecorder) UpdateConfigFileVariable(variable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileVariable", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileVariable), variable)
+}
+
+// UpdateConfigFileWebhook mocks base method.
+func (m *MockStore) UpdateConfigFileWebhook(webhook *model.ConfigFileWebhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileWebhook", webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileWebhook indicates an expected call of UpdateConfigFileWebhook.
+func (mr *MockStoreMockRecorder) UpdateConfigFileWebhook(webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileWebhook", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileWebhook), webhook)
+}
+
+// UpdateConfigFileWebhookDelivery mocks base method.
+func (m *MockStore) UpdateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileWebhookDelivery indicates an expected call of UpdateConfigFileWebhookDelivery.
+func (mr *MockStoreMockRecorder) UpdateConfigFileWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileWebhookDelivery", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileWebhookDelivery), delivery)
+}
+
 // UpdateGroup mocks base method.
 func (m *MockStore) UpdateGroup(group *model.ModifyUserGroup) error {
 	m.ctrl.T.Helper()
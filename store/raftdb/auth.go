@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// AddUser Create a user
+func (r *raftStore) AddUser(user *model.User) error {
+	_, err := r.apply(targetStore, "AddUser", user)
+	return err
+}
+
+// UpdateUser Update user
+func (r *raftStore) UpdateUser(user *model.User) error {
+	_, err := r.apply(targetStore, "UpdateUser", user)
+	return err
+}
+
+// DeleteUser delete users
+func (r *raftStore) DeleteUser(user *model.User) error {
+	_, err := r.apply(targetStore, "DeleteUser", user)
+	return err
+}
+
+// AddGroup Add a user group
+func (r *raftStore) AddGroup(group *model.UserGroupDetail) error {
+	_, err := r.apply(targetStore, "AddGroup", group)
+	return err
+}
+
+// UpdateGroup Update user group
+func (r *raftStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	_, err := r.apply(targetStore, "UpdateGroup", group)
+	return err
+}
+
+// DeleteGroup Delete user group
+func (r *raftStore) DeleteGroup(group *model.UserGroupDetail) error {
+	_, err := r.apply(targetStore, "DeleteGroup", group)
+	return err
+}
+
+// AddStrategy Create authentication strategy
+func (r *raftStore) AddStrategy(strategy *model.StrategyDetail) error {
+	_, err := r.apply(targetStore, "AddStrategy", strategy)
+	return err
+}
+
+// UpdateStrategy Update authentication strategy
+func (r *raftStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	_, err := r.apply(targetStore, "UpdateStrategy", strategy)
+	return err
+}
+
+// DeleteStrategy Delete authentication strategy
+func (r *raftStore) DeleteStrategy(id string) error {
+	_, err := r.apply(targetStore, "DeleteStrategy", id)
+	return err
+}
+
+// LooseAddStrategyResources Song requires the resources of the authentication strategy,
+// allowing the issue of ignoring the primary key conflict
+func (r *raftStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	_, err := r.apply(targetStore, "LooseAddStrategyResources", resources)
+	return err
+}
+
+// RemoveStrategyResources Clean all the strategies associated with corresponding resources
+func (r *raftStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	_, err := r.apply(targetStore, "RemoveStrategyResources", resources)
+	return err
+}
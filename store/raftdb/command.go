@@ -0,0 +1,183 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// targetStore 操作作用在本地 store 上
+	targetStore = "store"
+	// targetTransaction 操作作用在本地 store 创建的 Transaction 上
+	targetTransaction = "transaction"
+)
+
+var (
+	txType      = reflect.TypeOf((*store.Tx)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	clientsType = reflect.TypeOf([]*model.Client{})
+)
+
+// command 写入 raft 日志的一条命令
+type command struct {
+	// Tx 为 true 时，全部操作在本地 boltdb 的同一个事务中执行
+	Tx bool `json:"tx"`
+	// Ops 需要执行的写操作
+	Ops []*operation `json:"ops"`
+}
+
+// operation 一次对本地 store 写方法的调用
+type operation struct {
+	Target string            `json:"target"`
+	Method string            `json:"method"`
+	Args   []json.RawMessage `json:"args"`
+}
+
+// applyResponse 命令在状态机中的执行结果
+type applyResponse struct {
+	// Results 写方法除 error 之外的返回值，只有单个操作的命令才会返回
+	Results []interface{}
+	Err     error
+}
+
+// newOperation 根据方法名和参数创建一个写操作，store.Tx 类型的参数在执行时由状态机替换
+func newOperation(target, method string, args ...interface{}) (*operation, error) {
+	op := &operation{
+		Target: target,
+		Method: method,
+		Args:   make([]json.RawMessage, 0, len(args)),
+	}
+	for i := range args {
+		if _, ok := args[i].(store.Tx); ok {
+			op.Args = append(op.Args, json.RawMessage("null"))
+			continue
+		}
+		data, err := encodeValue(args[i])
+		if err != nil {
+			return nil, fmt.Errorf("encode %s arg %d: %w", method, i, err)
+		}
+		op.Args = append(op.Args, data)
+	}
+	return op, nil
+}
+
+// encodeValue 序列化参数，model.Client 的字段未导出，需要转为 api.Client 再序列化
+func encodeValue(value interface{}) ([]byte, error) {
+	if clients, ok := value.([]*model.Client); ok {
+		protos := make([]*api.Client, 0, len(clients))
+		for i := range clients {
+			protos = append(protos, clients[i].Proto())
+		}
+		value = protos
+	}
+	return json.Marshal(value)
+}
+
+// decodeValue 按照方法签名中的类型反序列化参数或者返回值
+func decodeValue(data []byte, typ reflect.Type) (reflect.Value, error) {
+	if typ == clientsType {
+		protos := make([]*api.Client, 0)
+		if err := json.Unmarshal(data, &protos); err != nil {
+			return reflect.Value{}, err
+		}
+		clients := make([]*model.Client, 0, len(protos))
+		for i := range protos {
+			clients = append(clients, model.NewClient(protos[i]))
+		}
+		return reflect.ValueOf(clients), nil
+	}
+	value := reflect.New(typ)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return value.Elem(), nil
+}
+
+// lookupMethod 在执行对象上查找写方法
+func lookupMethod(receiver interface{}, op *operation) (reflect.Value, error) {
+	method := reflect.ValueOf(receiver).MethodByName(op.Method)
+	if !method.IsValid() {
+		return reflect.Value{}, fmt.Errorf("raft store method %s.%s not found", op.Target, op.Method)
+	}
+	mt := method.Type()
+	if mt.NumIn() != len(op.Args) || mt.NumOut() == 0 || mt.Out(mt.NumOut()-1) != errorType {
+		return reflect.Value{}, fmt.Errorf("raft store method %s.%s signature mismatch", op.Target, op.Method)
+	}
+	return method, nil
+}
+
+// call 执行一次写操作，tx 为需要传入的本地事务，可以为 nil
+// 写方法 panic 时转为 error 返回，避免状态机协程退出
+func call(receiver interface{}, op *operation, tx store.Tx) (results []interface{}, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			results, err = nil, fmt.Errorf("raft store method %s panic: %v", op.Method, e)
+		}
+	}()
+
+	method, err := lookupMethod(receiver, op)
+	if err != nil {
+		return nil, err
+	}
+	mt := method.Type()
+	in := make([]reflect.Value, 0, len(op.Args))
+	for i := range op.Args {
+		if mt.In(i) == txType {
+			if tx == nil {
+				in = append(in, reflect.Zero(txType))
+			} else {
+				in = append(in, reflect.ValueOf(tx))
+			}
+			continue
+		}
+		arg, err := decodeValue(op.Args[i], mt.In(i))
+		if err != nil {
+			return nil, fmt.Errorf("decode %s arg %d: %w", op.Method, i, err)
+		}
+		in = append(in, arg)
+	}
+
+	out := method.Call(in)
+	results = make([]interface{}, 0, len(out)-1)
+	for i := 0; i < len(out)-1; i++ {
+		results = append(results, out[i].Interface())
+	}
+	if errValue := out[len(out)-1]; !errValue.IsNil() {
+		return results, errValue.Interface().(error)
+	}
+	return results, nil
+}
+
+// resultOf 获取写方法的第 index 个返回值
+func resultOf(results []interface{}, index int, out interface{}) {
+	if index >= len(results) || results[index] == nil {
+		return
+	}
+	value := reflect.ValueOf(out).Elem()
+	result := reflect.ValueOf(results[index])
+	if result.Type().AssignableTo(value.Type()) {
+		value.Set(result)
+	}
+}
@@ -0,0 +1,125 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Config raftStore 的配置
+type Config struct {
+	// NodeID 当前节点在集群中的唯一标识
+	NodeID string `mapstructure:"nodeId"`
+	// BindAddress raft 节点之间通信的地址
+	BindAddress string `mapstructure:"bindAddress"`
+	// ForwardAddress 接收 follower 转发过来的写请求的 http 地址
+	ForwardAddress string `mapstructure:"forwardAddress"`
+	// DataDir raft 日志以及快照的存放目录
+	DataDir string `mapstructure:"dataDir"`
+	// Path 本地 boltdb 数据文件
+	Path string `mapstructure:"path"`
+	// Bootstrap 是否由当前节点引导集群，只需要在集群第一次启动时设置
+	Bootstrap bool `mapstructure:"bootstrap"`
+	// Peers 集群的全部节点，包括当前节点
+	Peers []*PeerConfig `mapstructure:"peers"`
+	// ApplyTimeout 一次写操作等待提交的最长时间
+	ApplyTimeout string `mapstructure:"applyTimeout"`
+	// SnapshotInterval 检查是否需要做快照的周期
+	SnapshotInterval string `mapstructure:"snapshotInterval"`
+	// SnapshotThreshold 距离上一次快照新增多少条日志后做快照
+	SnapshotThreshold uint64 `mapstructure:"snapshotThreshold"`
+	// SnapshotRetain 保留的快照个数
+	SnapshotRetain int `mapstructure:"snapshotRetain"`
+
+	applyTimeout     time.Duration
+	snapshotInterval time.Duration
+}
+
+// PeerConfig 集群节点的配置
+type PeerConfig struct {
+	// ID 节点唯一标识
+	ID string `mapstructure:"id"`
+	// Address raft 通信地址
+	Address string `mapstructure:"address"`
+	// ForwardAddress 写请求转发地址
+	ForwardAddress string `mapstructure:"forwardAddress"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		DataDir:           "./polaris-raft",
+		Path:              "./polaris.bolt",
+		ApplyTimeout:      "10s",
+		SnapshotInterval:  "2m",
+		SnapshotThreshold: 8192,
+		SnapshotRetain:    2,
+	}
+}
+
+// parseConfig 解析 store.option 为 raftStore 的配置
+func parseConfig(opt map[string]interface{}) (*Config, error) {
+	config := defaultConfig()
+	if opt != nil {
+		if err := mapstructure.Decode(opt, config); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) check() error {
+	var err error
+	if c.NodeID == "" {
+		return errors.New("raft store nodeId is empty")
+	}
+	if c.BindAddress == "" {
+		return errors.New("raft store bindAddress is empty")
+	}
+	if c.applyTimeout, err = time.ParseDuration(c.ApplyTimeout); err != nil {
+		return fmt.Errorf("raft store applyTimeout is invalid: %w", err)
+	}
+	if c.snapshotInterval, err = time.ParseDuration(c.SnapshotInterval); err != nil {
+		return fmt.Errorf("raft store snapshotInterval is invalid: %w", err)
+	}
+	if len(c.Peers) == 0 {
+		c.Peers = []*PeerConfig{{ID: c.NodeID, Address: c.BindAddress, ForwardAddress: c.ForwardAddress}}
+	}
+	for _, peer := range c.Peers {
+		if peer.ID == "" || peer.Address == "" {
+			return errors.New("raft store peer id or address is empty")
+		}
+	}
+	return nil
+}
+
+// forwardAddress 获取节点接收写请求转发的地址
+func (c *Config) forwardAddress(id string) string {
+	for _, peer := range c.Peers {
+		if peer.ID == id {
+			return peer.ForwardAddress
+		}
+	}
+	return ""
+}
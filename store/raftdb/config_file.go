@@ -0,0 +1,312 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// CreateConfigFileGroup 创建配置文件组
+func (r *raftStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileGroup", fileGroup)
+	var ret *model.ConfigFileGroup
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileGroup 更新配置文件组
+func (r *raftStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	results, err := r.apply(targetStore, "UpdateConfigFileGroup", fileGroup)
+	var ret *model.ConfigFileGroup
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// DeleteConfigFileGroup 删除配置文件组
+func (r *raftStore) DeleteConfigFileGroup(namespace, name string) error {
+	_, err := r.apply(targetStore, "DeleteConfigFileGroup", namespace, name)
+	return err
+}
+
+// CreateConfigFile 创建配置文件
+func (r *raftStore) CreateConfigFile(tx store.Tx, file *model.ConfigFile) (*model.ConfigFile, error) {
+	results, err := r.applyTx(tx, "CreateConfigFile", tx, file)
+	if tx != nil {
+		return file, err
+	}
+	var ret *model.ConfigFile
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFile 更新配置文件
+func (r *raftStore) UpdateConfigFile(tx store.Tx, file *model.ConfigFile) (*model.ConfigFile, error) {
+	results, err := r.applyTx(tx, "UpdateConfigFile", tx, file)
+	if tx != nil {
+		return file, err
+	}
+	var ret *model.ConfigFile
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// DeleteConfigFile 删除配置文件
+func (r *raftStore) DeleteConfigFile(tx store.Tx, namespace, group, name string) error {
+	_, err := r.applyTx(tx, "DeleteConfigFile", tx, namespace, group, name)
+	return err
+}
+
+// CreateConfigFileRelease 创建配置文件发布
+func (r *raftStore) CreateConfigFileRelease(tx store.Tx,
+	fileRelease *model.ConfigFileRelease) (*model.ConfigFileRelease, error) {
+	results, err := r.applyTx(tx, "CreateConfigFileRelease", tx, fileRelease)
+	if tx != nil {
+		return fileRelease, err
+	}
+	var ret *model.ConfigFileRelease
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileRelease 更新配置文件发布
+func (r *raftStore) UpdateConfigFileRelease(tx store.Tx,
+	fileRelease *model.ConfigFileRelease) (*model.ConfigFileRelease, error) {
+	results, err := r.applyTx(tx, "UpdateConfigFileRelease", tx, fileRelease)
+	if tx != nil {
+		return fileRelease, err
+	}
+	var ret *model.ConfigFileRelease
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// DeleteConfigFileRelease 删除配置文件发布内容
+func (r *raftStore) DeleteConfigFileRelease(tx store.Tx, namespace, group, fileName, deleteBy string) error {
+	_, err := r.applyTx(tx, "DeleteConfigFileRelease", tx, namespace, group, fileName, deleteBy)
+	return err
+}
+
+// CreateConfigFileGrayRelease 创建配置文件灰度发布
+func (r *raftStore) CreateConfigFileGrayRelease(tx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	results, err := r.applyTx(tx, "CreateConfigFileGrayRelease", tx, grayRelease)
+	if tx != nil {
+		return grayRelease, err
+	}
+	var ret *model.ConfigFileGrayRelease
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileGrayRelease 更新配置文件灰度发布
+func (r *raftStore) UpdateConfigFileGrayRelease(tx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	results, err := r.applyTx(tx, "UpdateConfigFileGrayRelease", tx, grayRelease)
+	if tx != nil {
+		return grayRelease, err
+	}
+	var ret *model.ConfigFileGrayRelease
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// DeleteConfigFileGrayRelease 删除配置文件的灰度发布
+func (r *raftStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group, fileName string) error {
+	_, err := r.applyTx(tx, "DeleteConfigFileGrayRelease", tx, namespace, group, fileName)
+	return err
+}
+
+// IncrConfigFileGrayReleaseErrorCount 累加灰度发布的加载失败次数
+func (r *raftStore) IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName, md5 string) (uint32, error) {
+	results, err := r.apply(targetStore, "IncrConfigFileGrayReleaseErrorCount", namespace, group, fileName, md5)
+	var ret uint32
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// CreateConfigFileChange 创建配置文件变更
+func (r *raftStore) CreateConfigFileChange(tx store.Tx,
+	change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+	results, err := r.applyTx(tx, "CreateConfigFileChange", tx, change)
+	if tx != nil {
+		return change, err
+	}
+	var ret *model.ConfigFileChange
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileChange 更新配置文件变更
+func (r *raftStore) UpdateConfigFileChange(tx store.Tx,
+	change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+	results, err := r.applyTx(tx, "UpdateConfigFileChange", tx, change)
+	if tx != nil {
+		return change, err
+	}
+	var ret *model.ConfigFileChange
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// CreateConfigFileReleaseHistory 创建配置文件发布历史记录
+func (r *raftStore) CreateConfigFileReleaseHistory(tx store.Tx,
+	fileReleaseHistory *model.ConfigFileReleaseHistory) error {
+	_, err := r.applyTx(tx, "CreateConfigFileReleaseHistory", tx, fileReleaseHistory)
+	return err
+}
+
+// CreateConfigFileTag 创建配置文件标签
+func (r *raftStore) CreateConfigFileTag(tx store.Tx, fileTag *model.ConfigFileTag) error {
+	_, err := r.applyTx(tx, "CreateConfigFileTag", tx, fileTag)
+	return err
+}
+
+// DeleteConfigFileTag 删除配置文件标签
+func (r *raftStore) DeleteConfigFileTag(tx store.Tx, namespace, group, fileName, key, value string) error {
+	_, err := r.applyTx(tx, "DeleteConfigFileTag", tx, namespace, group, fileName, key, value)
+	return err
+}
+
+// DeleteTagByConfigFile 删除配置文件标签
+func (r *raftStore) DeleteTagByConfigFile(tx store.Tx, namespace, group, fileName string) error {
+	_, err := r.applyTx(tx, "DeleteTagByConfigFile", tx, namespace, group, fileName)
+	return err
+}
+
+// CreateConfigFileTemplate create config file template
+func (r *raftStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileTemplate", template)
+	var ret *model.ConfigFileTemplate
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// CreateConfigFileSchema create config file json schema
+func (r *raftStore) CreateConfigFileSchema(schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileSchema", schema)
+	var ret *model.ConfigFileSchema
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileSchema update config file json schema
+func (r *raftStore) UpdateConfigFileSchema(schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+	results, err := r.apply(targetStore, "UpdateConfigFileSchema", schema)
+	var ret *model.ConfigFileSchema
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// CreateConfigFileWebhook create config file webhook
+func (r *raftStore) CreateConfigFileWebhook(webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileWebhook", webhook)
+	var ret *model.ConfigFileWebhook
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileWebhook update config file webhook
+func (r *raftStore) UpdateConfigFileWebhook(webhook *model.ConfigFileWebhook) error {
+	_, err := r.apply(targetStore, "UpdateConfigFileWebhook", webhook)
+	return err
+}
+
+// DeleteConfigFileWebhook delete config file webhook
+func (r *raftStore) DeleteConfigFileWebhook(id uint64) error {
+	_, err := r.apply(targetStore, "DeleteConfigFileWebhook", id)
+	return err
+}
+
+// CreateConfigFileWebhookDelivery create config file webhook delivery
+func (r *raftStore) CreateConfigFileWebhookDelivery(
+	delivery *model.ConfigFileWebhookDelivery) (*model.ConfigFileWebhookDelivery, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileWebhookDelivery", delivery)
+	var ret *model.ConfigFileWebhookDelivery
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileWebhookDelivery update config file webhook delivery
+func (r *raftStore) UpdateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) error {
+	_, err := r.apply(targetStore, "UpdateConfigFileWebhookDelivery", delivery)
+	return err
+}
+
+// ClaimConfigFileWebhookDelivery claim pending config file webhook delivery
+func (r *raftStore) ClaimConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery,
+	leaseTime time.Time) (bool, error) {
+	results, err := r.apply(targetStore, "ClaimConfigFileWebhookDelivery", delivery, leaseTime)
+	var claimed bool
+	resultOf(results, 0, &claimed)
+	if claimed {
+		delivery.Attempts++
+		delivery.NextRetryTime = leaseTime
+	}
+	return claimed, err
+}
+
+// CleanConfigFileWebhookDeliveries clean config file webhook deliveries created before the time
+func (r *raftStore) CleanConfigFileWebhookDeliveries(before time.Time) error {
+	_, err := r.apply(targetStore, "CleanConfigFileWebhookDeliveries", before)
+	return err
+}
+
+// CreateConfigFileVariable create config file template variable
+func (r *raftStore) CreateConfigFileVariable(
+	variable *model.ConfigFileVariable) (*model.ConfigFileVariable, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileVariable", variable)
+	var ret *model.ConfigFileVariable
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileVariable update config file template variable
+func (r *raftStore) UpdateConfigFileVariable(variable *model.ConfigFileVariable) error {
+	_, err := r.apply(targetStore, "UpdateConfigFileVariable", variable)
+	return err
+}
+
+// DeleteConfigFileVariable delete config file template variable
+func (r *raftStore) DeleteConfigFileVariable(namespace, name string) error {
+	_, err := r.apply(targetStore, "DeleteConfigFileVariable", namespace, name)
+	return err
+}
+
+// CreateConfigFileClientPin create config file client pin
+func (r *raftStore) CreateConfigFileClientPin(
+	pin *model.ConfigFileClientPin) (*model.ConfigFileClientPin, error) {
+	results, err := r.apply(targetStore, "CreateConfigFileClientPin", pin)
+	var ret *model.ConfigFileClientPin
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// UpdateConfigFileClientPin update config file client pin
+func (r *raftStore) UpdateConfigFileClientPin(pin *model.ConfigFileClientPin) error {
+	_, err := r.apply(targetStore, "UpdateConfigFileClientPin", pin)
+	return err
+}
+
+// DeleteConfigFileClientPin delete config file client pin
+func (r *raftStore) DeleteConfigFileClientPin(namespace, group, fileName, client string) error {
+	_, err := r.apply(targetStore, "DeleteConfigFileClientPin", namespace, group, fileName, client)
+	return err
+}
@@ -0,0 +1,260 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+const (
+	STORENAME = "raftStore"
+
+	raftLogFile        = "raft.db"
+	raftTransportPool  = 3
+	raftTransportTime  = 10 * time.Second
+	waitLeaderInterval = 100 * time.Millisecond
+)
+
+// raftStore 使用 raft 在多个节点之间复制写操作，每个节点使用本地的 boltdb 保存数据
+// 读操作直接读取本地 boltdb，写操作写入 raft 日志，提交后由状态机在每个节点上执行
+// follower 收到的写操作会通过 http 转发给 leader
+type raftStore struct {
+	// boltdb.LocalStore 本地 boltdb，提供全部的读操作
+	boltdb.LocalStore
+
+	config        *Config
+	raft          *raft.Raft
+	fsm           *raftFSM
+	transport     raft.Transport
+	logStore      *raftboltdb.BoltStore
+	forwardServer *http.Server
+	forwardClient *http.Client
+	start         bool
+}
+
+// Name store name
+func (r *raftStore) Name() string {
+	return STORENAME
+}
+
+// Initialize init store
+func (r *raftStore) Initialize(c *store.Config) error {
+	if r.start {
+		return nil
+	}
+	config, err := parseConfig(c.Option)
+	if err != nil {
+		return err
+	}
+	transport, err := raft.NewTCPTransportWithLogger(config.BindAddress, nil, raftTransportPool,
+		raftTransportTime, newRaftLogger("raft-transport"))
+	if err != nil {
+		return err
+	}
+	var ln net.Listener
+	if config.ForwardAddress != "" {
+		if ln, err = net.Listen("tcp", config.ForwardAddress); err != nil {
+			_ = transport.Close()
+			return err
+		}
+	}
+	if err := r.open(config, transport, ln); err != nil {
+		if ln != nil {
+			_ = ln.Close()
+		}
+		_ = transport.Close()
+		return err
+	}
+	r.start = true
+	return nil
+}
+
+// open 启动本地 boltdb 以及 raft 节点，ln 不为空时启动写请求转发服务
+func (r *raftStore) open(config *Config, transport raft.Transport, ln net.Listener) error {
+	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
+		return err
+	}
+
+	r.config = config
+	r.transport = transport
+	r.forwardClient = &http.Client{Timeout: config.applyTimeout * 2}
+	r.LocalStore = boltdb.NewLocalStore()
+
+	logStore, err := raftboltdb.NewBoltStore(filepath.Join(config.DataDir, raftLogFile))
+	if err != nil {
+		return err
+	}
+	r.logStore = logStore
+	snapshots, err := raft.NewFileSnapshotStoreWithLogger(config.DataDir, config.SnapshotRetain,
+		newRaftLogger("raft-snapshot"))
+	if err != nil {
+		_ = logStore.Close()
+		return err
+	}
+
+	if err := r.LocalStore.Initialize(localConfig(config)); err != nil {
+		_ = logStore.Close()
+		return err
+	}
+	if r.fsm, err = newRaftFSM(r.LocalStore, config, logStore); err != nil {
+		_ = r.LocalStore.Destroy()
+		_ = logStore.Close()
+		return err
+	}
+
+	raftConfig := raft.DefaultConfig()
+	raftConfig.LocalID = raft.ServerID(config.NodeID)
+	raftConfig.Logger = newRaftLogger("raft")
+	raftConfig.SnapshotInterval = config.snapshotInterval
+	raftConfig.SnapshotThreshold = config.SnapshotThreshold
+	// 本地 boltdb 已经持久化了数据，启动时不需要从快照恢复
+	raftConfig.NoSnapshotRestoreOnStart = true
+
+	if r.raft, err = raft.NewRaft(raftConfig, r.fsm, logStore, logStore, snapshots, transport); err != nil {
+		_ = r.LocalStore.Destroy()
+		_ = logStore.Close()
+		return err
+	}
+	if err := r.bootstrap(logStore, snapshots); err != nil {
+		_ = r.raft.Shutdown().Error()
+		_ = r.LocalStore.Destroy()
+		_ = logStore.Close()
+		return err
+	}
+	if ln != nil {
+		r.serveForward(ln)
+	}
+	r.waitLeader()
+	return nil
+}
+
+// bootstrap 集群第一次启动时，由配置了 bootstrap 的节点引导集群
+func (r *raftStore) bootstrap(logStore *raftboltdb.BoltStore, snapshots raft.SnapshotStore) error {
+	if !r.config.Bootstrap {
+		return nil
+	}
+	hasState, err := raft.HasExistingState(logStore, logStore, snapshots)
+	if err != nil {
+		return err
+	}
+	if hasState {
+		return nil
+	}
+	servers := make([]raft.Server, 0, len(r.config.Peers))
+	for _, peer := range r.config.Peers {
+		servers = append(servers, raft.Server{
+			ID:      raft.ServerID(peer.ID),
+			Address: raft.ServerAddress(peer.Address),
+		})
+	}
+	return r.raft.BootstrapCluster(raft.Configuration{Servers: servers}).Error()
+}
+
+// waitLeader 等待集群选出 leader，避免启动后的第一批写操作失败
+func (r *raftStore) waitLeader() {
+	deadline := time.Now().Add(r.config.applyTimeout)
+	for time.Now().Before(deadline) {
+		if addr, _ := r.raft.LeaderWithID(); addr != "" {
+			return
+		}
+		time.Sleep(waitLeaderInterval)
+	}
+	log.Warn("[Store][Raft] no leader elected", zap.String("node", r.config.NodeID))
+}
+
+// Destroy store
+func (r *raftStore) Destroy() error {
+	r.start = false
+	if r.forwardServer != nil {
+		_ = r.forwardServer.Close()
+	}
+	if r.raft != nil {
+		if err := r.raft.Shutdown().Error(); err != nil {
+			log.Error("[Store][Raft] shutdown raft", zap.Error(err))
+		}
+	}
+	if closer, ok := r.transport.(raft.WithClose); ok {
+		_ = closer.Close()
+	}
+	if r.logStore != nil {
+		_ = r.logStore.Close()
+	}
+	if r.LocalStore != nil {
+		return r.LocalStore.Destroy()
+	}
+	return nil
+}
+
+// apply 执行一次写操作
+func (r *raftStore) apply(target, method string, args ...interface{}) ([]interface{}, error) {
+	op, err := newOperation(target, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return r.propose(&command{Ops: []*operation{op}})
+}
+
+// propose 把命令写入 raft，当前节点不是 leader 时转发给 leader
+func (r *raftStore) propose(cmd *command) ([]interface{}, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.applyLocal(data)
+	if errors.Is(err, raft.ErrNotLeader) {
+		return r.forward(cmd, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, resp.Err
+}
+
+// applyLocal 当前节点是 leader 时，写入 raft 日志并等待状态机执行完成
+func (r *raftStore) applyLocal(data []byte) (*applyResponse, error) {
+	if r.raft.State() != raft.Leader {
+		return nil, raft.ErrNotLeader
+	}
+	future := r.raft.Apply(data, r.config.applyTimeout)
+	if err := future.Error(); err != nil {
+		return nil, err
+	}
+	resp, ok := future.Response().(*applyResponse)
+	if !ok {
+		return nil, errors.New("raft store apply response is invalid")
+	}
+	return resp, nil
+}
+
+func init() {
+	s := &raftStore{}
+	_ = store.RegisterStore(s)
+}
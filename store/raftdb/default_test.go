@@ -0,0 +1,369 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+// newTestCluster 在当前进程中启动一个使用内存传输的 raft 集群
+func newTestCluster(t *testing.T, count int) []*raftStore {
+	tempDir, err := ioutil.TempDir("", "raftstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	transports := make([]*raft.InmemTransport, 0, count)
+	listeners := make([]net.Listener, 0, count)
+	peers := make([]*PeerConfig, 0, count)
+	for i := 0; i < count; i++ {
+		addr, transport := raft.NewInmemTransport("")
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		transports = append(transports, transport)
+		listeners = append(listeners, ln)
+		peers = append(peers, &PeerConfig{
+			ID:             fmt.Sprintf("node-%d", i),
+			Address:        string(addr),
+			ForwardAddress: ln.Addr().String(),
+		})
+	}
+	for i := range transports {
+		for j := range transports {
+			if i != j {
+				transports[i].Connect(transports[j].LocalAddr(), transports[j])
+			}
+		}
+	}
+
+	stores := make([]*raftStore, count)
+	wait := &sync.WaitGroup{}
+	errs := make([]error, count)
+	for i := 0; i < count; i++ {
+		config := defaultConfig()
+		config.NodeID = peers[i].ID
+		config.BindAddress = peers[i].Address
+		config.ForwardAddress = peers[i].ForwardAddress
+		config.DataDir = filepath.Join(tempDir, peers[i].ID)
+		config.Path = filepath.Join(tempDir, peers[i].ID, "polaris.bolt")
+		config.Bootstrap = i == 0
+		config.Peers = peers
+		if err := config.check(); err != nil {
+			t.Fatal(err)
+		}
+
+		stores[i] = &raftStore{}
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			errs[i] = stores[i].open(config, transports[i], listeners[i])
+		}(i)
+	}
+	wait.Wait()
+	for i := range errs {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+	}
+	t.Cleanup(func() {
+		for i := range stores {
+			_ = stores[i].Destroy()
+		}
+	})
+	return stores
+}
+
+func follower(stores []*raftStore) *raftStore {
+	for i := range stores {
+		if stores[i].raft.State() != raft.Leader {
+			return stores[i]
+		}
+	}
+	return nil
+}
+
+// waitReplicated 等待全部节点执行完成当前已经提交的日志
+func waitReplicated(t *testing.T, stores []*raftStore, check func(s *raftStore) bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for _, s := range stores {
+		for !check(s) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %s not replicated", s.config.NodeID)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
+
+func TestRaftStore_ForwardWrite(t *testing.T) {
+	stores := newTestCluster(t, 3)
+	s := follower(stores)
+
+	err := s.AddNamespace(&model.Namespace{
+		Name:       "raft-ns",
+		Token:      utils.NewUUID(),
+		Owner:      "polaris",
+		Valid:      true,
+		CreateTime: time.Now(),
+		ModifyTime: time.Now(),
+	})
+	assert.NoError(t, err)
+	waitReplicated(t, stores, func(s *raftStore) bool {
+		ns, err := s.GetNamespace("raft-ns")
+		return err == nil && ns != nil
+	})
+
+	group, err := s.CreateConfigFileGroup(&model.ConfigFileGroup{
+		Namespace: "raft-ns",
+		Name:      "raft-group",
+		CreateBy:  "polaris",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, group)
+	assert.NotZero(t, group.Id)
+	waitReplicated(t, stores, func(s *raftStore) bool {
+		ret, err := s.GetConfigFileGroup("raft-ns", "raft-group")
+		return err == nil && ret != nil && ret.Id == group.Id
+	})
+}
+
+func TestRaftStore_Instance(t *testing.T) {
+	stores := newTestCluster(t, 3)
+	s := follower(stores)
+
+	assert.NoError(t, s.AddService(&model.Service{
+		ID:        "raft-svc-id",
+		Name:      "raft-svc",
+		Namespace: "default",
+		Token:     utils.NewUUID(),
+		Revision:  utils.NewUUID(),
+		Valid:     true,
+	}))
+	assert.NoError(t, s.AddInstance(&model.Instance{
+		Proto: &api.Instance{
+			Id:       utils.NewStringValue("raft-ins-id"),
+			Host:     utils.NewStringValue("127.0.0.1"),
+			Port:     utils.NewUInt32Value(8080),
+			Healthy:  utils.NewBoolValue(true),
+			Revision: utils.NewStringValue(utils.NewUUID()),
+		},
+		ServiceID: "raft-svc-id",
+		Valid:     true,
+	}))
+	assert.NoError(t, s.BatchSetInstanceHealthStatus([]interface{}{"raft-ins-id"}, 0, utils.NewUUID()))
+
+	waitReplicated(t, stores, func(s *raftStore) bool {
+		ins, err := s.GetInstance("raft-ins-id")
+		return err == nil && ins != nil && ins.Port() == 8080 && !ins.Healthy()
+	})
+}
+
+func TestRaftStore_Tx(t *testing.T) {
+	stores := newTestCluster(t, 3)
+	s := follower(stores)
+
+	tx, err := s.StartTx()
+	assert.NoError(t, err)
+	_, err = s.CreateConfigFile(tx, &model.ConfigFile{
+		Namespace: "default",
+		Group:     "raft-group",
+		Name:      "rollback.yaml",
+		Content:   "a: b",
+		Format:    "yaml",
+		Valid:     true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+	assert.Equal(t, ErrTxDone, tx.Commit())
+
+	tx, err = s.StartTx()
+	assert.NoError(t, err)
+	_, err = s.CreateConfigFile(tx, &model.ConfigFile{
+		Namespace: "default",
+		Group:     "raft-group",
+		Name:      "commit.yaml",
+		Content:   "a: b",
+		Format:    "yaml",
+		Valid:     true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, s.CreateConfigFileTag(tx, &model.ConfigFileTag{
+		Key:       "env",
+		Value:     "test",
+		Namespace: "default",
+		Group:     "raft-group",
+		FileName:  "commit.yaml",
+	}))
+
+	// 提交之前其他节点都看不到事务中的写操作
+	file, err := s.GetConfigFile(nil, "default", "raft-group", "commit.yaml")
+	assert.NoError(t, err)
+	assert.Nil(t, file)
+
+	assert.NoError(t, tx.Commit())
+	waitReplicated(t, stores, func(s *raftStore) bool {
+		file, err := s.GetConfigFile(nil, "default", "raft-group", "commit.yaml")
+		if err != nil || file == nil {
+			return false
+		}
+		tags, err := s.QueryTagByConfigFile("default", "raft-group", "commit.yaml")
+		return err == nil && len(tags) == 1
+	})
+	for _, s := range stores {
+		file, err := s.GetConfigFile(nil, "default", "raft-group", "rollback.yaml")
+		assert.NoError(t, err)
+		assert.Nil(t, file)
+	}
+}
+
+func TestRaftStore_Clients(t *testing.T) {
+	stores := newTestCluster(t, 3)
+	s := follower(stores)
+
+	client := model.NewClient(&api.Client{
+		Id:      utils.NewStringValue("raft-client"),
+		Host:    utils.NewStringValue("127.0.0.1"),
+		Version: utils.NewStringValue("1.0.0"),
+	})
+	assert.NoError(t, s.BatchAddClients([]*model.Client{client}))
+	waitReplicated(t, stores, func(s *raftStore) bool {
+		clients, err := s.GetMoreClients(time.Time{}, true)
+		return err == nil && clients["raft-client"] != nil &&
+			clients["raft-client"].Proto().GetHost().GetValue() == "127.0.0.1"
+	})
+}
+
+func TestRaftStore_StatusError(t *testing.T) {
+	stores := newTestCluster(t, 3)
+	s := follower(stores)
+
+	// leader 返回的状态码需要在 follower 上还原
+	err := s.AddUser(&model.User{ID: "raft-user", Name: "raft-user", Valid: true})
+	assert.Error(t, err)
+	assert.Equal(t, store.EmptyParamsErr, store.Code(err))
+	assert.Equal(t, "add user missing some params", err.Error())
+}
+
+func TestRaftFSM_SnapshotRestore(t *testing.T) {
+	stores := newTestCluster(t, 3)
+	leader := stores[0]
+	for _, s := range stores {
+		if s.raft.State() == raft.Leader {
+			leader = s
+		}
+	}
+	assert.NoError(t, leader.AddBusiness(&model.Business{
+		ID:    "raft-business",
+		Name:  "raft-business",
+		Token: utils.NewUUID(),
+		Owner: "polaris",
+		Valid: true,
+	}))
+
+	snapshot, err := leader.fsm.Snapshot()
+	assert.NoError(t, err)
+	defer snapshot.Release()
+	snapshots := raft.NewInmemSnapshotStore()
+	sink, err := snapshots.Create(raft.SnapshotVersionMax, 1, 1, raft.Configuration{}, 1, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, snapshot.Persist(sink))
+
+	s := follower(stores)
+	metas, err := snapshots.List()
+	assert.NoError(t, err)
+	_, reader, err := snapshots.Open(metas[0].ID)
+	assert.NoError(t, err)
+	assert.NoError(t, s.fsm.Restore(reader))
+
+	business, err := s.GetBusinessByID("raft-business")
+	assert.NoError(t, err)
+	assert.NotNil(t, business)
+	// 恢复后已执行的日志序号与快照一致
+	assert.Equal(t, leader.fsm.lastApplied, s.fsm.lastApplied)
+}
+
+func TestRaftFSM_ApplyIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-fsm-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := &Config{DataDir: dir, Path: filepath.Join(dir, "polaris.bolt")}
+	local := boltdb.NewLocalStore()
+	assert.NoError(t, local.Initialize(localConfig(config)))
+	defer local.Destroy()
+	stable := raft.NewInmemStore()
+
+	newCommand := func(ops ...*operation) []byte {
+		data, err := json.Marshal(&command{Ops: ops})
+		assert.NoError(t, err)
+		return data
+	}
+	addBusiness := func(id string) *operation {
+		op, err := newOperation(targetStore, "AddBusiness", &model.Business{
+			ID: id, Name: id, Token: utils.NewUUID(), Owner: "polaris", Valid: true,
+		})
+		assert.NoError(t, err)
+		return op
+	}
+
+	fsm, err := newRaftFSM(local, config, stable)
+	assert.NoError(t, err)
+	resp := fsm.Apply(&raft.Log{Index: 1, Data: newCommand(addBusiness("fsm-business-1"))}).(*applyResponse)
+	assert.NoError(t, resp.Err)
+	index, err := local.AppliedIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), index)
+
+	// 命令执行失败时全部写操作回滚，日志序号仍然保存
+	resp = fsm.Apply(&raft.Log{Index: 2, Data: newCommand(addBusiness("fsm-business-2"),
+		&operation{Target: targetStore, Method: "NotExistMethod"})}).(*applyResponse)
+	assert.Error(t, resp.Err)
+	business, err := local.GetBusinessByID("fsm-business-2")
+	assert.NoError(t, err)
+	assert.Nil(t, business)
+
+	// 重启后从本地 boltdb 读取已执行的日志序号，不会重复执行
+	fsm, err = newRaftFSM(local, config, stable)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), fsm.lastApplied)
+	resp = fsm.Apply(&raft.Log{Index: 2, Data: newCommand(addBusiness("fsm-business-2"))}).(*applyResponse)
+	assert.NoError(t, resp.Err)
+	business, err = local.GetBusinessByID("fsm-business-2")
+	assert.NoError(t, err)
+	assert.Nil(t, business)
+}
@@ -0,0 +1,286 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+// AddService 保存一个服务
+func (r *raftStore) AddService(service *model.Service) error {
+	_, err := r.apply(targetStore, "AddService", service)
+	return err
+}
+
+// DeleteService 删除服务
+func (r *raftStore) DeleteService(id, serviceName, namespaceName string) error {
+	_, err := r.apply(targetStore, "DeleteService", id, serviceName, namespaceName)
+	return err
+}
+
+// DeleteServiceAlias 删除服务别名
+func (r *raftStore) DeleteServiceAlias(name string, namespace string) error {
+	_, err := r.apply(targetStore, "DeleteServiceAlias", name, namespace)
+	return err
+}
+
+// UpdateServiceAlias 修改服务别名
+func (r *raftStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	_, err := r.apply(targetStore, "UpdateServiceAlias", alias, needUpdateOwner)
+	return err
+}
+
+// UpdateService 更新服务
+func (r *raftStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	_, err := r.apply(targetStore, "UpdateService", service, needUpdateOwner)
+	return err
+}
+
+// UpdateServiceToken 更新服务token
+func (r *raftStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	_, err := r.apply(targetStore, "UpdateServiceToken", serviceID, token, revision)
+	return err
+}
+
+// AddInstance 增加一个实例
+func (r *raftStore) AddInstance(instance *model.Instance) error {
+	_, err := r.apply(targetStore, "AddInstance", instance)
+	return err
+}
+
+// BatchAddInstances 增加多个实例
+func (r *raftStore) BatchAddInstances(instances []*model.Instance) error {
+	_, err := r.apply(targetStore, "BatchAddInstances", instances)
+	return err
+}
+
+// UpdateInstance 更新实例
+func (r *raftStore) UpdateInstance(instance *model.Instance) error {
+	_, err := r.apply(targetStore, "UpdateInstance", instance)
+	return err
+}
+
+// DeleteInstance 删除一个实例，实际是把valid置为false
+func (r *raftStore) DeleteInstance(instanceID string) error {
+	_, err := r.apply(targetStore, "DeleteInstance", instanceID)
+	return err
+}
+
+// BatchDeleteInstances 批量删除实例，flag=1
+func (r *raftStore) BatchDeleteInstances(ids []interface{}) error {
+	_, err := r.apply(targetStore, "BatchDeleteInstances", ids)
+	return err
+}
+
+// CleanInstance 清空一个实例，真正删除
+func (r *raftStore) CleanInstance(instanceID string) error {
+	_, err := r.apply(targetStore, "CleanInstance", instanceID)
+	return err
+}
+
+// SetInstanceHealthStatus 设置实例的健康状态
+func (r *raftStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	_, err := r.apply(targetStore, "SetInstanceHealthStatus", instanceID, flag, revision)
+	return err
+}
+
+// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+func (r *raftStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	_, err := r.apply(targetStore, "BatchSetInstanceHealthStatus", ids, healthy, revision)
+	return err
+}
+
+// BatchSetInstanceIsolate 批量修改实例的隔离状态
+func (r *raftStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	_, err := r.apply(targetStore, "BatchSetInstanceIsolate", ids, isolate, revision)
+	return err
+}
+
+// SetL5Extend 设置meta里保存的扩展数据，并返回剩余的meta
+func (r *raftStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	results, err := r.apply(targetStore, "SetL5Extend", serviceID, meta)
+	var ret map[string]interface{}
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// GenNextL5Sid 获取module
+func (r *raftStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	results, err := r.apply(targetStore, "GenNextL5Sid", layoutID)
+	var ret string
+	resultOf(results, 0, &ret)
+	return ret, err
+}
+
+// CreateRoutingConfig 新增一个路由配置
+func (r *raftStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	_, err := r.apply(targetStore, "CreateRoutingConfig", conf)
+	return err
+}
+
+// UpdateRoutingConfig 更新一个路由配置
+func (r *raftStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	_, err := r.apply(targetStore, "UpdateRoutingConfig", conf)
+	return err
+}
+
+// DeleteRoutingConfig 删除一个路由配置
+func (r *raftStore) DeleteRoutingConfig(serviceID string) error {
+	_, err := r.apply(targetStore, "DeleteRoutingConfig", serviceID)
+	return err
+}
+
+// DeleteRoutingConfigTx 删除一个路由配置
+func (r *raftStore) DeleteRoutingConfigTx(tx store.Tx, serviceID string) error {
+	_, err := r.applyTx(tx, "DeleteRoutingConfigTx", tx, serviceID)
+	return err
+}
+
+// CreateRateLimit 新增限流规则
+func (r *raftStore) CreateRateLimit(limiting *model.RateLimit) error {
+	_, err := r.apply(targetStore, "CreateRateLimit", limiting)
+	return err
+}
+
+// UpdateRateLimit 更新限流规则
+func (r *raftStore) UpdateRateLimit(limiting *model.RateLimit) error {
+	_, err := r.apply(targetStore, "UpdateRateLimit", limiting)
+	return err
+}
+
+// EnableRateLimit 启用限流规则
+func (r *raftStore) EnableRateLimit(limit *model.RateLimit) error {
+	_, err := r.apply(targetStore, "EnableRateLimit", limit)
+	return err
+}
+
+// DeleteRateLimit 删除限流规则
+func (r *raftStore) DeleteRateLimit(limiting *model.RateLimit) error {
+	_, err := r.apply(targetStore, "DeleteRateLimit", limiting)
+	return err
+}
+
+// CreateCircuitBreaker 新增熔断规则
+func (r *raftStore) CreateCircuitBreaker(circuitBreaker *model.CircuitBreaker) error {
+	_, err := r.apply(targetStore, "CreateCircuitBreaker", circuitBreaker)
+	return err
+}
+
+// TagCircuitBreaker 标记熔断规则
+func (r *raftStore) TagCircuitBreaker(circuitBreaker *model.CircuitBreaker) error {
+	_, err := r.apply(targetStore, "TagCircuitBreaker", circuitBreaker)
+	return err
+}
+
+// ReleaseCircuitBreaker 发布熔断规则
+func (r *raftStore) ReleaseCircuitBreaker(circuitBreakerRelation *model.CircuitBreakerRelation) error {
+	_, err := r.apply(targetStore, "ReleaseCircuitBreaker", circuitBreakerRelation)
+	return err
+}
+
+// UnbindCircuitBreaker 解绑熔断规则
+func (r *raftStore) UnbindCircuitBreaker(serviceID, ruleID, ruleVersion string) error {
+	_, err := r.apply(targetStore, "UnbindCircuitBreaker", serviceID, ruleID, ruleVersion)
+	return err
+}
+
+// DeleteTagCircuitBreaker 删除已标记熔断规则
+func (r *raftStore) DeleteTagCircuitBreaker(id string, version string) error {
+	_, err := r.apply(targetStore, "DeleteTagCircuitBreaker", id, version)
+	return err
+}
+
+// DeleteMasterCircuitBreaker 删除master熔断规则
+func (r *raftStore) DeleteMasterCircuitBreaker(id string) error {
+	_, err := r.apply(targetStore, "DeleteMasterCircuitBreaker", id)
+	return err
+}
+
+// UpdateCircuitBreaker 修改熔断规则
+func (r *raftStore) UpdateCircuitBreaker(circuitBraker *model.CircuitBreaker) error {
+	_, err := r.apply(targetStore, "UpdateCircuitBreaker", circuitBraker)
+	return err
+}
+
+// CreatePlatform 新增平台信息
+func (r *raftStore) CreatePlatform(platform *model.Platform) error {
+	_, err := r.apply(targetStore, "CreatePlatform", platform)
+	return err
+}
+
+// UpdatePlatform 更新平台信息
+func (r *raftStore) UpdatePlatform(platform *model.Platform) error {
+	_, err := r.apply(targetStore, "UpdatePlatform", platform)
+	return err
+}
+
+// DeletePlatform 删除平台信息
+func (r *raftStore) DeletePlatform(id string) error {
+	_, err := r.apply(targetStore, "DeletePlatform", id)
+	return err
+}
+
+// BatchAddClients insert the client info
+func (r *raftStore) BatchAddClients(clients []*model.Client) error {
+	_, err := r.apply(targetStore, "BatchAddClients", clients)
+	return err
+}
+
+// BatchDeleteClients delete the client info
+func (r *raftStore) BatchDeleteClients(ids []string) error {
+	_, err := r.apply(targetStore, "BatchDeleteClients", ids)
+	return err
+}
+
+// EnableRouting 设置路由规则是否启用
+func (r *raftStore) EnableRouting(conf *v2.RoutingConfig) error {
+	_, err := r.apply(targetStore, "EnableRouting", conf)
+	return err
+}
+
+// CreateRoutingConfigV2 新增一个路由配置
+func (r *raftStore) CreateRoutingConfigV2(conf *v2.RoutingConfig) error {
+	_, err := r.apply(targetStore, "CreateRoutingConfigV2", conf)
+	return err
+}
+
+// CreateRoutingConfigV2Tx 新增一个路由配置
+func (r *raftStore) CreateRoutingConfigV2Tx(tx store.Tx, conf *v2.RoutingConfig) error {
+	_, err := r.applyTx(tx, "CreateRoutingConfigV2Tx", tx, conf)
+	return err
+}
+
+// UpdateRoutingConfigV2 更新一个路由配置
+func (r *raftStore) UpdateRoutingConfigV2(conf *v2.RoutingConfig) error {
+	_, err := r.apply(targetStore, "UpdateRoutingConfigV2", conf)
+	return err
+}
+
+// UpdateRoutingConfigV2Tx 更新一个路由配置
+func (r *raftStore) UpdateRoutingConfigV2Tx(tx store.Tx, conf *v2.RoutingConfig) error {
+	_, err := r.applyTx(tx, "UpdateRoutingConfigV2Tx", tx, conf)
+	return err
+}
+
+// DeleteRoutingConfigV2 删除一个路由配置
+func (r *raftStore) DeleteRoutingConfigV2(serviceID string) error {
+	_, err := r.apply(targetStore, "DeleteRoutingConfigV2", serviceID)
+	return err
+}
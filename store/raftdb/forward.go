@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/raft"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// forwardPath follower 把写请求转发给 leader 的 http 路径
+	forwardPath = "/raftstore/v1/apply"
+)
+
+// forwardResponse leader 执行转发过来的命令后的返回
+type forwardResponse struct {
+	Results []json.RawMessage `json:"results"`
+	Error   *forwardError     `json:"error,omitempty"`
+}
+
+// forwardError 序列化 store 层返回的错误，保留 StatusError 的状态码
+type forwardError struct {
+	Status  bool             `json:"status"`
+	Code    store.StatusCode `json:"code"`
+	Message string           `json:"message"`
+}
+
+func newForwardError(err error) *forwardError {
+	if err == nil {
+		return nil
+	}
+	_, ok := err.(*store.StatusError)
+	return &forwardError{
+		Status:  ok,
+		Code:    store.Code(err),
+		Message: err.Error(),
+	}
+}
+
+func (e *forwardError) toError() error {
+	if e == nil {
+		return nil
+	}
+	if e.Status {
+		return store.NewStatusError(e.Code, e.Message)
+	}
+	return errors.New(e.Message)
+}
+
+// serveForward 启动接收转发写请求的 http 服务
+func (r *raftStore) serveForward(ln net.Listener) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(forwardPath, r.handleForward)
+	r.forwardServer = &http.Server{Handler: mux}
+	go func() {
+		if err := r.forwardServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Error("[Store][Raft] forward server stopped", zap.Error(err))
+		}
+	}()
+}
+
+// handleForward leader 执行 follower 转发过来的命令
+func (r *raftStore) handleForward(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rsp := &forwardResponse{}
+	resp, err := r.applyLocal(data)
+	if err != nil {
+		rsp.Error = newForwardError(err)
+	} else {
+		rsp.Error = newForwardError(resp.Err)
+		for i := range resp.Results {
+			result, err := encodeValue(resp.Results[i])
+			if err != nil {
+				rsp.Error = newForwardError(err)
+				break
+			}
+			rsp.Results = append(rsp.Results, result)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rsp); err != nil {
+		log.Error("[Store][Raft] write forward response", zap.Error(err))
+	}
+}
+
+// forward 把命令转发给 leader 执行
+func (r *raftStore) forward(cmd *command, data []byte) ([]interface{}, error) {
+	_, leaderID := r.raft.LeaderWithID()
+	if leaderID == "" {
+		return nil, raft.ErrNotLeader
+	}
+	address := r.config.forwardAddress(string(leaderID))
+	if address == "" {
+		return nil, fmt.Errorf("raft store leader %s has no forward address", leaderID)
+	}
+
+	httpRsp, err := r.forwardClient.Post("http://"+address+forwardPath, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer httpRsp.Body.Close()
+	if httpRsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("raft store forward to %s, status %d", address, httpRsp.StatusCode)
+	}
+
+	rsp := &forwardResponse{}
+	if err := json.NewDecoder(httpRsp.Body).Decode(rsp); err != nil {
+		return nil, err
+	}
+	results, err := r.decodeResults(cmd, rsp.Results)
+	if err != nil {
+		return nil, err
+	}
+	return results, rsp.Error.toError()
+}
+
+// decodeResults 按照写方法的返回值类型反序列化 leader 返回的结果
+func (r *raftStore) decodeResults(cmd *command, data []json.RawMessage) ([]interface{}, error) {
+	if len(data) == 0 || cmd.Tx || len(cmd.Ops) != 1 {
+		return nil, nil
+	}
+	op := cmd.Ops[0]
+	var receiver interface{} = r.LocalStore
+	if op.Target == targetTransaction {
+		trans, err := r.LocalStore.CreateTransaction()
+		if err != nil {
+			return nil, err
+		}
+		receiver = trans
+	}
+	method, err := lookupMethod(receiver, op)
+	if err != nil {
+		return nil, err
+	}
+
+	mt := method.Type()
+	if len(data) != mt.NumOut()-1 {
+		return nil, fmt.Errorf("raft store method %s results mismatch", op.Method)
+	}
+	results := make([]interface{}, 0, len(data))
+	for i := range data {
+		value, err := decodeValue(data[i], mt.Out(i))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, value.Interface())
+	}
+	return results, nil
+}
@@ -0,0 +1,231 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/store"
+	"github.com/polarismesh/polaris/store/boltdb"
+)
+
+var (
+	// keyLastApplied 旧版本保存在 raft StableStore 中的已执行日志序号，只在升级时读取一次
+	keyLastApplied = []byte("polaris_last_applied_index")
+)
+
+// raftFSM 把 raft 日志中的写操作应用到本地的 boltdb
+type raftFSM struct {
+	local  boltdb.LocalStore
+	config *Config
+
+	// lock 快照恢复时需要重建本地 boltdb，此时不能有写操作
+	lock        sync.RWMutex
+	lastApplied uint64
+}
+
+// newRaftFSM 本地 boltdb 需要已经初始化，已执行的日志序号与数据保存在同一个 boltdb 中
+func newRaftFSM(local boltdb.LocalStore, config *Config, stable raft.StableStore) (*raftFSM, error) {
+	lastApplied, err := local.AppliedIndex()
+	if err != nil {
+		return nil, err
+	}
+	if lastApplied == 0 {
+		if lastApplied, err = stable.GetUint64(keyLastApplied); err != nil &&
+			!errors.Is(err, raftboltdb.ErrKeyNotFound) {
+			return nil, err
+		}
+	}
+	return &raftFSM{
+		local:       local,
+		config:      config,
+		lastApplied: lastApplied,
+	}, nil
+}
+
+// localConfig 本地 boltdb 的初始化配置
+func localConfig(config *Config) *store.Config {
+	return &store.Config{
+		Name:   boltdb.STORENAME,
+		Option: map[string]interface{}{"path": config.Path},
+	}
+}
+
+// Apply 执行一条已经提交的日志
+// 命令的写操作和日志序号在本地 boltdb 的同一个事务中提交，启动时已经执行过的日志直接跳过，避免重复执行
+func (f *raftFSM) Apply(l *raft.Log) interface{} {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if l.Index <= f.lastApplied {
+		return &applyResponse{}
+	}
+	resp := &applyResponse{}
+	err := f.local.ApplyWithIndex(l.Index, func(s store.Store) error {
+		resp = f.applyCommand(s, l.Data)
+		return resp.Err
+	})
+	if err != nil && resp.Err == nil {
+		log.Error("[Store][Raft] save applied command", zap.Uint64("index", l.Index), zap.Error(err))
+		resp.Err = err
+	}
+	f.lastApplied = l.Index
+	return resp
+}
+
+// applyCommand 在 s 上执行命令，s 的全部读写都在同一个 boltdb 事务中
+func (f *raftFSM) applyCommand(s store.Store, data []byte) *applyResponse {
+	cmd := &command{}
+	if err := json.Unmarshal(data, cmd); err != nil {
+		log.Error("[Store][Raft] decode command", zap.Error(err))
+		return &applyResponse{Err: err}
+	}
+	if cmd.Tx {
+		return &applyResponse{Err: f.applyTx(s, cmd.Ops)}
+	}
+
+	resp := &applyResponse{}
+	for _, op := range cmd.Ops {
+		resp.Results, resp.Err = f.applyOperation(s, op, nil)
+		if resp.Err != nil {
+			break
+		}
+	}
+	return resp
+}
+
+// applyTx 带有事务的命令，写方法需要传入本地事务
+func (f *raftFSM) applyTx(s store.Store, ops []*operation) error {
+	tx, err := s.StartTx()
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if _, err := f.applyOperation(s, op, tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (f *raftFSM) applyOperation(s store.Store, op *operation, tx store.Tx) ([]interface{}, error) {
+	switch op.Target {
+	case targetTransaction:
+		trans, err := s.CreateTransaction()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = trans.Commit() }()
+		return call(trans, op, tx)
+	default:
+		return call(s, op, tx)
+	}
+}
+
+// Snapshot 在 FSM 协程中把 boltdb 的数据拷贝到临时文件，Persist 时再写入 raft 的快照
+func (f *raftFSM) Snapshot() (raft.FSMSnapshot, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	file, err := ioutil.TempFile(f.config.DataDir, "fsm-snapshot-*.bolt")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.local.Snapshot(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+	return &fsmSnapshot{file: file}, nil
+}
+
+// Restore 使用快照的内容替换本地的 boltdb 文件
+func (f *raftFSM) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	file, err := ioutil.TempFile(filepath.Dir(f.config.Path), "fsm-restore-*.bolt")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, rc); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	if err := f.local.Destroy(); err != nil {
+		log.Error("[Store][Raft] close local boltdb before restore", zap.Error(err))
+	}
+	if err := os.Rename(file.Name(), f.config.Path); err != nil {
+		return err
+	}
+	if err := f.local.Initialize(localConfig(f.config)); err != nil {
+		return err
+	}
+
+	// 快照是在状态机锁内拷贝的 boltdb，其中保存的日志序号就是快照对应的日志序号，
+	// 之后的日志由 raft 继续执行
+	lastApplied, err := f.local.AppliedIndex()
+	if err != nil {
+		return err
+	}
+	f.lastApplied = lastApplied
+	return nil
+}
+
+// fsmSnapshot boltdb 数据的一份拷贝
+type fsmSnapshot struct {
+	file *os.File
+}
+
+// Persist 把快照写入 raft 的 SnapshotSink
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	if _, err := io.Copy(sink, s.file); err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+// Release 删除临时文件
+func (s *fsmSnapshot) Release() {
+	_ = s.file.Close()
+	_ = os.Remove(s.file.Name())
+}
@@ -0,0 +1,45 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	commonlog "github.com/polarismesh/polaris/common/log"
+)
+
+var log = commonlog.StoreScope()
+
+// logWriter 把 raft 库输出的日志转到 store 的日志中
+type logWriter struct{}
+
+// Write 实现 io.Writer 接口
+func (w *logWriter) Write(p []byte) (int, error) {
+	log.Info(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+func newRaftLogger(name string) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:   name,
+		Level:  hclog.Info,
+		Output: &logWriter{},
+	})
+}
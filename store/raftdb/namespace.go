@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// AddNamespace Save a namespace
+func (r *raftStore) AddNamespace(namespace *model.Namespace) error {
+	_, err := r.apply(targetStore, "AddNamespace", namespace)
+	return err
+}
+
+// UpdateNamespace Update namespace
+func (r *raftStore) UpdateNamespace(namespace *model.Namespace) error {
+	_, err := r.apply(targetStore, "UpdateNamespace", namespace)
+	return err
+}
+
+// UpdateNamespaceToken Update namespace token
+func (r *raftStore) UpdateNamespaceToken(name string, token string) error {
+	_, err := r.apply(targetStore, "UpdateNamespaceToken", name, token)
+	return err
+}
+
+// AddBusiness 增加一个业务集
+func (r *raftStore) AddBusiness(business *model.Business) error {
+	_, err := r.apply(targetStore, "AddBusiness", business)
+	return err
+}
+
+// DeleteBusiness 删除一个业务集
+func (r *raftStore) DeleteBusiness(bid string) error {
+	_, err := r.apply(targetStore, "DeleteBusiness", bid)
+	return err
+}
+
+// UpdateBusiness 更新业务集
+func (r *raftStore) UpdateBusiness(business *model.Business) error {
+	_, err := r.apply(targetStore, "UpdateBusiness", business)
+	return err
+}
+
+// UpdateBusinessToken 更新业务集token
+func (r *raftStore) UpdateBusinessToken(bid string, token string) error {
+	_, err := r.apply(targetStore, "UpdateBusinessToken", bid, token)
+	return err
+}
@@ -0,0 +1,175 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package raftdb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/polarismesh/polaris/common/model"
+	v2 "github.com/polarismesh/polaris/common/model/v2"
+	"github.com/polarismesh/polaris/store"
+)
+
+var (
+	// ErrTxDone 事务已经提交或者回滚
+	ErrTxDone = errors.New("raft store tx has already been committed or rolled back")
+)
+
+// raftTx 缓存事务中的写操作，提交时作为一条日志写入 raft，由状态机在同一个 boltdb 事务中执行
+// 事务中的读操作读取的是已经提交的数据，读不到本事务中尚未提交的写操作
+type raftTx struct {
+	store *raftStore
+
+	mutex sync.Mutex
+	ops   []*operation
+	done  bool
+}
+
+// Commit 提交事务
+func (t *raftTx) Commit() error {
+	t.mutex.Lock()
+	if t.done {
+		t.mutex.Unlock()
+		return ErrTxDone
+	}
+	t.done = true
+	ops := t.ops
+	t.ops = nil
+	t.mutex.Unlock()
+
+	if len(ops) == 0 {
+		return nil
+	}
+	_, err := t.store.propose(&command{Tx: true, Ops: ops})
+	return err
+}
+
+// Rollback 回滚事务，丢弃缓存的写操作
+func (t *raftTx) Rollback() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.done = true
+	t.ops = nil
+	return nil
+}
+
+// GetDelegateTx 写操作在状态机中才会真正执行，这里没有可以直接使用的本地事务
+func (t *raftTx) GetDelegateTx() interface{} {
+	return nil
+}
+
+func (t *raftTx) append(method string, args ...interface{}) error {
+	op, err := newOperation(targetStore, method, args...)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.done {
+		return ErrTxDone
+	}
+	t.ops = append(t.ops, op)
+	return nil
+}
+
+// StartTx 开启事务
+func (r *raftStore) StartTx() (store.Tx, error) {
+	return &raftTx{store: r}, nil
+}
+
+// applyTx 写操作带有事务时先缓存到事务中，否则直接写入 raft
+// 缓存的写操作在事务提交后才会执行，因此带有事务的写方法直接返回传入的对象
+func (r *raftStore) applyTx(tx store.Tx, method string, args ...interface{}) ([]interface{}, error) {
+	if tx == nil {
+		return r.apply(targetStore, method, args...)
+	}
+	rtx, ok := tx.(*raftTx)
+	if !ok {
+		return nil, errors.New("raft store tx type is invalid")
+	}
+	return nil, rtx.append(method, args...)
+}
+
+// GetConfigFile 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetConfigFile(_ store.Tx, namespace, group, name string) (*model.ConfigFile, error) {
+	return r.LocalStore.GetConfigFile(nil, namespace, group, name)
+}
+
+// GetConfigFileRelease 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetConfigFileRelease(_ store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileRelease, error) {
+	return r.LocalStore.GetConfigFileRelease(nil, namespace, group, fileName)
+}
+
+// GetConfigFileReleaseWithAllFlag 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetConfigFileReleaseWithAllFlag(_ store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileRelease, error) {
+	return r.LocalStore.GetConfigFileReleaseWithAllFlag(nil, namespace, group, fileName)
+}
+
+// GetConfigFileGrayRelease 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetConfigFileGrayRelease(_ store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileGrayRelease, error) {
+	return r.LocalStore.GetConfigFileGrayRelease(nil, namespace, group, fileName)
+}
+
+// GetConfigFileChange 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetConfigFileChange(_ store.Tx, id uint64) (*model.ConfigFileChange, error) {
+	return r.LocalStore.GetConfigFileChange(nil, id)
+}
+
+// GetLatestConfigFileChange 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetLatestConfigFileChange(_ store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileChange, error) {
+	return r.LocalStore.GetLatestConfigFileChange(nil, namespace, group, fileName)
+}
+
+// GetRoutingConfigV2WithIDTx 事务中的读操作直接读取本地已经提交的数据
+func (r *raftStore) GetRoutingConfigV2WithIDTx(_ store.Tx, id string) (*v2.RoutingConfig, error) {
+	tx, err := r.LocalStore.StartReadTx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	return r.LocalStore.GetRoutingConfigV2WithIDTx(tx, id)
+}
+
+// raftTransaction 加锁的操作直接读取本地数据，删除 namespace 写入 raft
+type raftTransaction struct {
+	store.Transaction
+	store *raftStore
+}
+
+// CreateTransaction 创建事务对象
+func (r *raftStore) CreateTransaction() (store.Transaction, error) {
+	local, err := r.LocalStore.CreateTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return &raftTransaction{Transaction: local, store: r}, nil
+}
+
+// DeleteNamespace 删除 namespace
+func (t *raftTransaction) DeleteNamespace(name string) error {
+	_, err := t.store.apply(targetTransaction, "DeleteNamespace", name)
+	return err
+}
@@ -20,7 +20,6 @@ package sqldb
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"strings"
 	"time"
 
@@ -28,7 +27,7 @@ import (
 )
 
 // db抛出的异常，需要重试的字符串组
-var errMsg = []string{"Deadlock", "bad connection", "invalid connection"}
+var errMsg = []string{"Deadlock", "deadlock detected", "bad connection", "invalid connection"}
 
 // BaseDB 对sql.DB的封装
 type BaseDB struct {
@@ -36,6 +35,7 @@ type BaseDB struct {
 	cfg            *dbConfig
 	isolationLevel sql.IsolationLevel
 	parsePwd       plugin.ParsePassword
+	dialect        Dialect
 }
 
 // dbConfig store的配置
@@ -53,7 +53,7 @@ type dbConfig struct {
 
 // NewBaseDB 新建一个BaseDB
 func NewBaseDB(cfg *dbConfig, parsePwd plugin.ParsePassword) (*BaseDB, error) {
-	baseDb := &BaseDB{cfg: cfg, parsePwd: parsePwd}
+	baseDb := &BaseDB{cfg: cfg, parsePwd: parsePwd, dialect: newDialect(cfg.dbType)}
 	if cfg.txIsolationLevel > 0 {
 		baseDb.isolationLevel = sql.IsolationLevel(cfg.txIsolationLevel)
 		log.Infof("[Store][database] use isolation level: %s", baseDb.isolationLevel.String())
@@ -80,9 +80,9 @@ func (b *BaseDB) openDatabase() error {
 		c.dbPwd = pwd
 	}
 
-	dns := fmt.Sprintf("%s:%s@tcp(%s)/%s", c.dbUser, c.dbPwd, c.dbAddr, c.dbName)
+	dns := b.dialect.DSN(c)
 
-	db, err := sql.Open(c.dbType, dns)
+	db, err := sql.Open(b.dialect.DriverName(), dns)
 	if err != nil {
 		log.Errorf("[Store][database] sql open err: %s", err.Error())
 		return err
@@ -112,6 +112,7 @@ func (b *BaseDB) openDatabase() error {
 func (b *BaseDB) Exec(query string, args ...interface{}) (sql.Result, error) {
 	var result sql.Result
 	var err error
+	query, args = b.dialect.Rebind(query, args)
 	Retry("exec "+query, func() error {
 		result, err = b.DB.Exec(query, args...)
 		return err
@@ -124,6 +125,7 @@ func (b *BaseDB) Exec(query string, args ...interface{}) (sql.Result, error) {
 func (b *BaseDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	var rows *sql.Rows
 	var err error
+	query, args = b.dialect.Rebind(query, args)
 	Retry("query "+query, func() error {
 		rows, err = b.DB.Query(query, args...)
 		return err
@@ -132,6 +134,17 @@ func (b *BaseDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	return rows, err
 }
 
+// QueryRow 重写db.QueryRow函数
+func (b *BaseDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	query, args = b.dialect.Rebind(query, args)
+	return b.DB.QueryRow(query, args...)
+}
+
+// Dialect 获取数据库方言
+func (b *BaseDB) Dialect() Dialect {
+	return b.dialect
+}
+
 // Begin 重写db.Begin
 func (b *BaseDB) Begin() (*BaseTx, error) {
 	var tx *sql.Tx
@@ -145,12 +158,36 @@ func (b *BaseDB) Begin() (*BaseTx, error) {
 		return err
 	})
 
-	return &BaseTx{Tx: tx}, err
+	return &BaseTx{Tx: tx, dialect: b.dialect}, err
 }
 
 // BaseTx 对sql.Tx的封装
 type BaseTx struct {
 	*sql.Tx
+	dialect Dialect
+}
+
+// Exec 重写tx.Exec函数，转换为对应数据库的SQL
+func (t *BaseTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	query, args = t.dialect.Rebind(query, args)
+	return t.Tx.Exec(query, args...)
+}
+
+// Query 重写tx.Query函数，转换为对应数据库的SQL
+func (t *BaseTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = t.dialect.Rebind(query, args)
+	return t.Tx.Query(query, args...)
+}
+
+// QueryRow 重写tx.QueryRow函数，转换为对应数据库的SQL
+func (t *BaseTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	query, args = t.dialect.Rebind(query, args)
+	return t.Tx.QueryRow(query, args...)
+}
+
+// Dialect 获取数据库方言
+func (t *BaseTx) Dialect() Dialect {
+	return t.dialect
 }
 
 // Retry 重试主函数
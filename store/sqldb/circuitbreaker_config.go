@@ -121,10 +121,10 @@ func (c *circuitBreakerStore) releaseCircuitBreaker(cbr *model.CircuitBreakerRel
 		select '%s', '%s', '%s', 0, sysdate(), sysdate() from service, circuitbreaker_rule 
 		where service.id = ? and service.flag = 0 
 		and circuitbreaker_rule.id = ? and circuitbreaker_rule.version = ? 
-		and circuitbreaker_rule.flag = 0 
-		on DUPLICATE key update 
+		and circuitbreaker_rule.flag = 0 %s
 		rule_id = ?, rule_version = ?, flag = 0, mtime = sysdate()`
-	str = fmt.Sprintf(str, cbr.ServiceID, cbr.RuleID, cbr.RuleVersion)
+	str = fmt.Sprintf(str, cbr.ServiceID, cbr.RuleID, cbr.RuleVersion,
+		c.master.Dialect().OnDuplicateKeyUpdate("service_id"))
 	log.Infof("[Store][CircuitBreaker] exec release sql(%s)", str)
 	result, err := c.master.Exec(str, cbr.ServiceID, cbr.RuleID, cbr.RuleVersion, cbr.RuleID, cbr.RuleVersion)
 	if err != nil {
@@ -358,9 +358,11 @@ func addClientMain(tx *BaseTx, client *model.Client) error {
 	return err
 }
 
+// clientColumns client 表写入的字段
+var clientColumns = []string{"id", "host", "type", "version", "region", "zone", "campus", "flag", "ctime", "mtime"}
+
 func batchAddClientMain(tx *BaseTx, clients []*model.Client) error {
-	str := `replace into client(id, host, type, version, region, zone, campus, flag, ctime, mtime) 
-		 values`
+	str, onConflict := tx.Dialect().ReplaceInto("client", []string{"id"}, clientColumns)
 	first := true
 	args := make([]interface{}, 0)
 	for _, client := range clients {
@@ -378,6 +380,7 @@ func batchAddClientMain(tx *BaseTx, clients []*model.Client) error {
 			client.Proto().GetLocation().GetZone().GetValue(),
 			client.Proto().GetLocation().GetCampus().GetValue())
 	}
+	str += onConflict
 	_, err := tx.Exec(str, args...)
 	return err
 }
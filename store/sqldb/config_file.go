@@ -204,6 +204,48 @@ func (cf *configFileStore) CountByConfigFileGroup(namespace, group string) (uint
 	return count, nil
 }
 
+func (cf *configFileStore) CountConfigFiles(namespace string) (uint64, error) {
+	countSql := "select count(*) from config_file where namespace = ? and flag = 0"
+	var count uint64
+	err := cf.db.QueryRow(countSql, namespace).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (cf *configFileStore) CountConfigFilesByNamespaces(namespaces []string) (map[string]uint64, error) {
+	counts := make(map[string]uint64, len(namespaces))
+	if len(namespaces) == 0 {
+		return counts, nil
+	}
+	countSql := "select namespace, count(*) from config_file where flag = 0 and namespace in (" +
+		PlaceholdersN(len(namespaces)) + ") group by namespace"
+	args := make([]interface{}, 0, len(namespaces))
+	for i := range namespaces {
+		args = append(args, namespaces[i])
+	}
+	rows, err := cf.db.Query(countSql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			namespace string
+			count     uint64
+		)
+		if err := rows.Scan(&namespace, &count); err != nil {
+			return nil, err
+		}
+		counts[namespace] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
 func (cf *configFileStore) baseSelectConfigFileSql() string {
 	return "select id, name,namespace,`group`,content,IFNULL(comment, ''),format, UNIX_TIMESTAMP(create_time), " +
 		" IFNULL(create_by, ''),UNIX_TIMESTAMP(modify_time),IFNULL(modify_by, '') from config_file "
@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+type configFileChangeStore struct {
+	db *BaseDB
+}
+
+// CreateConfigFileChange 新建配置文件变更
+func (cfc *configFileChangeStore) CreateConfigFileChange(tx store.Tx,
+	change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+
+	sql := "insert into config_file_change(namespace, `group`, file_name, content, diff, md5, status, " +
+		" review_by, review_comment, create_time, create_by, modify_time, modify_by) values" +
+		"(?,?,?,?,?,?,?,?,?, sysdate(),?,sysdate(),?)"
+	args := []interface{}{change.Namespace, change.Group, change.FileName, change.Content, change.Diff,
+		change.Md5, change.Status, change.ReviewBy, change.ReviewComment,
+		change.CreateBy, change.ModifyBy}
+
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(sql, args...)
+	} else {
+		_, err = cfc.db.Exec(sql, args...)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfc.GetLatestConfigFileChange(tx, change.Namespace, change.Group, change.FileName)
+}
+
+// UpdateConfigFileChange 更新配置文件变更的内容以及审核状态
+func (cfc *configFileChangeStore) UpdateConfigFileChange(tx store.Tx,
+	change *model.ConfigFileChange) (*model.ConfigFileChange, error) {
+
+	sql := "update config_file_change set content = ?, diff = ?, md5 = ?, status = ?, " +
+		" review_by = ?, review_comment = ?, modify_time = sysdate(), modify_by = ? where id = ?"
+	args := []interface{}{change.Content, change.Diff, change.Md5, change.Status,
+		change.ReviewBy, change.ReviewComment, change.ModifyBy, change.Id}
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(sql, args...)
+	} else {
+		_, err = cfc.db.Exec(sql, args...)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfc.GetConfigFileChange(tx, change.Id)
+}
+
+// GetConfigFileChange 获取单个配置文件变更
+func (cfc *configFileChangeStore) GetConfigFileChange(tx store.Tx, id uint64) (*model.ConfigFileChange, error) {
+	querySql := cfc.baseQuerySql() + "where id = ?"
+	changes, err := cfc.query(tx, querySql, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		return changes[0], nil
+	}
+	return nil, nil
+}
+
+// GetLatestConfigFileChange 获取配置文件最后一次的变更
+func (cfc *configFileChangeStore) GetLatestConfigFileChange(tx store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileChange, error) {
+
+	querySql := cfc.baseQuerySql() + "where namespace = ? and `group` = ? and file_name = ? order by id desc limit 1"
+	changes, err := cfc.query(tx, querySql, namespace, group, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		return changes[0], nil
+	}
+	return nil, nil
+}
+
+// QueryConfigFileChanges 查询配置文件变更，namespace、group、fileName、status 为空时不作为查询条件
+func (cfc *configFileChangeStore) QueryConfigFileChanges(namespace, group, fileName, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileChange, error) {
+
+	countSql := "select count(*) from config_file_change where 1 = 1 "
+	querySql := cfc.baseQuerySql() + " where 1 = 1 "
+
+	var queryParams []interface{}
+	conditions := []struct {
+		column string
+		value  string
+	}{
+		{column: "namespace", value: namespace},
+		{column: "`group`", value: group},
+		{column: "file_name", value: fileName},
+		{column: "status", value: status},
+	}
+	for _, condition := range conditions {
+		if condition.value == "" {
+			continue
+		}
+		countSql += " and " + condition.column + " = ? "
+		querySql += " and " + condition.column + " = ? "
+		queryParams = append(queryParams, condition.value)
+	}
+
+	var count uint32
+	if err := cfc.db.QueryRow(countSql, queryParams...).Scan(&count); err != nil {
+		return 0, nil, err
+	}
+
+	querySql += " order by id desc limit ?, ?"
+	queryParams = append(queryParams, offset, limit)
+	changes, err := cfc.query(nil, querySql, queryParams...)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, changes, nil
+}
+
+func (cfc *configFileChangeStore) query(tx store.Tx, querySql string,
+	args ...interface{}) ([]*model.ConfigFileChange, error) {
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if tx != nil {
+		rows, err = tx.GetDelegateTx().(*BaseTx).Query(querySql, args...)
+	} else {
+		rows, err = cfc.db.Query(querySql, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cfc.transferRows(rows)
+}
+
+func (cfc *configFileChangeStore) baseQuerySql() string {
+	return "select id, namespace, `group`, file_name, content, diff, md5, status, " +
+		" IFNULL(review_by, ''), IFNULL(review_comment, ''), UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), " +
+		" UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') from config_file_change "
+}
+
+func (cfc *configFileChangeStore) transferRows(rows *sql.Rows) ([]*model.ConfigFileChange, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var changes []*model.ConfigFileChange
+
+	for rows.Next() {
+		change := &model.ConfigFileChange{}
+		var ctime, mtime int64
+		err := rows.Scan(&change.Id, &change.Namespace, &change.Group, &change.FileName, &change.Content,
+			&change.Diff, &change.Md5, &change.Status, &change.ReviewBy, &change.ReviewComment,
+			&ctime, &change.CreateBy, &mtime, &change.ModifyBy)
+		if err != nil {
+			return nil, err
+		}
+		change.CreateTime = time.Unix(ctime, 0)
+		change.ModifyTime = time.Unix(mtime, 0)
+		change.Valid = true
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
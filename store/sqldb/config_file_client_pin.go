@@ -0,0 +1,123 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+type configFileClientPinStore struct {
+	db *BaseDB
+}
+
+// CreateConfigFileClientPin 固定客户端的版本，同一个配置文件下客户端唯一
+func (cfp *configFileClientPinStore) CreateConfigFileClientPin(
+	pin *model.ConfigFileClientPin) (*model.ConfigFileClientPin, error) {
+
+	createSql := "insert into config_file_client_pin(namespace, `group`, file_name, client, history_id, " +
+		" content, md5, comment, create_time, create_by, modify_time, modify_by) values " +
+		" (?,?,?,?,?,?,?,?,sysdate(),?,sysdate(),?)"
+	_, err := cfp.db.Exec(createSql, pin.Namespace, pin.Group, pin.FileName, pin.Client, pin.HistoryId,
+		pin.Content, pin.Md5, pin.Comment, pin.CreateBy, pin.ModifyBy)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfp.GetConfigFileClientPin(pin.Namespace, pin.Group, pin.FileName, pin.Client)
+}
+
+// UpdateConfigFileClientPin 更新客户端固定的发布内容
+func (cfp *configFileClientPinStore) UpdateConfigFileClientPin(pin *model.ConfigFileClientPin) error {
+	updateSql := "update config_file_client_pin set history_id = ?, content = ?, md5 = ?, comment = ?, " +
+		" modify_time = sysdate(), modify_by = ? where namespace = ? and `group` = ? and file_name = ? " +
+		" and client = ?"
+	_, err := cfp.db.Exec(updateSql, pin.HistoryId, pin.Content, pin.Md5, pin.Comment, pin.ModifyBy,
+		pin.Namespace, pin.Group, pin.FileName, pin.Client)
+	return store.Error(err)
+}
+
+// DeleteConfigFileClientPin 取消客户端的版本固定
+func (cfp *configFileClientPinStore) DeleteConfigFileClientPin(namespace, group, fileName, client string) error {
+	deleteSql := "delete from config_file_client_pin where namespace = ? and `group` = ? and file_name = ? " +
+		" and client = ?"
+	_, err := cfp.db.Exec(deleteSql, namespace, group, fileName, client)
+	return store.Error(err)
+}
+
+// GetConfigFileClientPin 获取客户端的版本固定
+func (cfp *configFileClientPinStore) GetConfigFileClientPin(namespace, group, fileName,
+	client string) (*model.ConfigFileClientPin, error) {
+
+	pins, err := cfp.queryPins(cfp.baseSelectSql()+" where namespace = ? and `group` = ? and file_name = ? "+
+		" and client = ?", namespace, group, fileName, client)
+	if err != nil {
+		return nil, err
+	}
+	if len(pins) == 0 {
+		return nil, nil
+	}
+	return pins[0], nil
+}
+
+// QueryConfigFileClientPins 获取配置文件的全部版本固定，按照创建顺序排序
+func (cfp *configFileClientPinStore) QueryConfigFileClientPins(namespace, group,
+	fileName string) ([]*model.ConfigFileClientPin, error) {
+	return cfp.queryPins(cfp.baseSelectSql()+" where namespace = ? and `group` = ? and file_name = ? order by id",
+		namespace, group, fileName)
+}
+
+// GetAllConfigFileClientPins 获取全部的版本固定
+func (cfp *configFileClientPinStore) GetAllConfigFileClientPins() ([]*model.ConfigFileClientPin, error) {
+	return cfp.queryPins(cfp.baseSelectSql() + " order by id")
+}
+
+func (cfp *configFileClientPinStore) baseSelectSql() string {
+	return "select id, namespace, `group`, file_name, client, history_id, content, md5, IFNULL(comment, ''), " +
+		" UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') " +
+		" from config_file_client_pin "
+}
+
+func (cfp *configFileClientPinStore) queryPins(querySql string,
+	args ...interface{}) ([]*model.ConfigFileClientPin, error) {
+
+	rows, err := cfp.db.Query(querySql, args...)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	defer rows.Close()
+
+	var pins []*model.ConfigFileClientPin
+	for rows.Next() {
+		pin := &model.ConfigFileClientPin{}
+		var ctime, mtime int64
+		if err := rows.Scan(&pin.Id, &pin.Namespace, &pin.Group, &pin.FileName, &pin.Client, &pin.HistoryId,
+			&pin.Content, &pin.Md5, &pin.Comment, &ctime, &pin.CreateBy, &mtime, &pin.ModifyBy); err != nil {
+			return nil, err
+		}
+		pin.CreateTime = time.Unix(ctime, 0)
+		pin.ModifyTime = time.Unix(mtime, 0)
+		pin.Valid = true
+		pins = append(pins, pin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
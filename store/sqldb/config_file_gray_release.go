@@ -0,0 +1,193 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+type configFileGrayReleaseStore struct {
+	db *BaseDB
+}
+
+// CreateConfigFileGrayRelease 新建配置文件灰度发布
+func (cfg *configFileGrayReleaseStore) CreateConfigFileGrayRelease(tx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+
+	sql := "insert into config_file_gray_release(name, namespace, `group`, file_name, content, comment, md5, " +
+		" version, rule, create_time, create_by, modify_time, modify_by) values" +
+		"(?,?,?,?,?,?,?,?,?, sysdate(),?,sysdate(),?)"
+	args := []interface{}{grayRelease.Name, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName,
+		grayRelease.Content, grayRelease.Comment, grayRelease.Md5, grayRelease.Version, grayRelease.Rule,
+		grayRelease.CreateBy, grayRelease.ModifyBy}
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(sql, args...)
+	} else {
+		_, err = cfg.db.Exec(sql, args...)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfg.GetConfigFileGrayRelease(tx, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+}
+
+// UpdateConfigFileGrayRelease 更新配置文件灰度发布
+func (cfg *configFileGrayReleaseStore) UpdateConfigFileGrayRelease(tx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+
+	sql := "update config_file_gray_release set name = ?, content = ?, comment = ?, md5 = ?, version = ?, " +
+		" rule = ?, error_count = ?, modify_time = sysdate(), modify_by = ? " +
+		" where namespace = ? and `group` = ? and file_name = ?"
+	args := []interface{}{grayRelease.Name, grayRelease.Content, grayRelease.Comment, grayRelease.Md5,
+		grayRelease.Version, grayRelease.Rule, grayRelease.ErrorCount, grayRelease.ModifyBy, grayRelease.Namespace,
+		grayRelease.Group, grayRelease.FileName}
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(sql, args...)
+	} else {
+		_, err = cfg.db.Exec(sql, args...)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfg.GetConfigFileGrayRelease(tx, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+}
+
+// GetConfigFileGrayRelease 获取配置文件的灰度发布
+func (cfg *configFileGrayReleaseStore) GetConfigFileGrayRelease(tx store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileGrayRelease, error) {
+
+	querySql := cfg.baseQuerySql() + "where namespace = ? and `group` = ? and file_name = ?"
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if tx != nil {
+		rows, err = tx.GetDelegateTx().(*BaseTx).Query(querySql, namespace, group, fileName)
+	} else {
+		rows, err = cfg.db.Query(querySql, namespace, group, fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	grayReleases, err := cfg.transferRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(grayReleases) > 0 {
+		return grayReleases[0], nil
+	}
+	return nil, nil
+}
+
+// DeleteConfigFileGrayRelease 删除配置文件的灰度发布
+func (cfg *configFileGrayReleaseStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group,
+	fileName string) error {
+
+	sql := "delete from config_file_gray_release where namespace = ? and `group` = ? and file_name = ?"
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(sql, namespace, group, fileName)
+	} else {
+		_, err = cfg.db.Exec(sql, namespace, group, fileName)
+	}
+	if err != nil {
+		return store.Error(err)
+	}
+	return nil
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter 获取最后更新时间大于某个时间点的灰度发布
+func (cfg *configFileGrayReleaseStore) FindConfigFileGrayReleaseByModifyTimeAfter(
+	modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+
+	sql := cfg.baseQuerySql() + " where modify_time > FROM_UNIXTIME(?)"
+	rows, err := cfg.db.Query(sql, timeToTimestamp(modifyTime))
+	if err != nil {
+		return nil, err
+	}
+	return cfg.transferRows(rows)
+}
+
+// IncrConfigFileGrayReleaseErrorCount 累加灰度发布的加载失败次数，不更新修改时间，避免被当作新的灰度发布扫描
+func (cfg *configFileGrayReleaseStore) IncrConfigFileGrayReleaseErrorCount(namespace, group, fileName,
+	md5 string) (uint32, error) {
+
+	updateSql := "update config_file_gray_release set error_count = error_count + 1, modify_time = modify_time " +
+		" where namespace = ? and `group` = ? and file_name = ? and md5 = ?"
+	result, err := cfg.db.Exec(updateSql, namespace, group, fileName, md5)
+	if err != nil {
+		return 0, store.Error(err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return 0, err
+	}
+
+	var count uint32
+	querySql := "select error_count from config_file_gray_release where namespace = ? and `group` = ? " +
+		" and file_name = ?"
+	if err := cfg.db.QueryRow(querySql, namespace, group, fileName).Scan(&count); err != nil {
+		return 0, store.Error(err)
+	}
+	return count, nil
+}
+
+func (cfg *configFileGrayReleaseStore) baseQuerySql() string {
+	return "select id, name, namespace, `group`, file_name, content, IFNULL(comment, ''), md5, version, rule, " +
+		" error_count, " +
+		" UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') " +
+		" from config_file_gray_release "
+}
+
+func (cfg *configFileGrayReleaseStore) transferRows(rows *sql.Rows) ([]*model.ConfigFileGrayRelease, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var grayReleases []*model.ConfigFileGrayRelease
+
+	for rows.Next() {
+		grayRelease := &model.ConfigFileGrayRelease{}
+		var ctime, mtime int64
+		err := rows.Scan(&grayRelease.Id, &grayRelease.Name, &grayRelease.Namespace, &grayRelease.Group,
+			&grayRelease.FileName, &grayRelease.Content, &grayRelease.Comment, &grayRelease.Md5,
+			&grayRelease.Version, &grayRelease.Rule, &grayRelease.ErrorCount, &ctime, &grayRelease.CreateBy, &mtime,
+			&grayRelease.ModifyBy)
+		if err != nil {
+			return nil, err
+		}
+		grayRelease.CreateTime = time.Unix(ctime, 0)
+		grayRelease.ModifyTime = time.Unix(mtime, 0)
+		grayRelease.Valid = true
+
+		grayReleases = append(grayReleases, grayRelease)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return grayReleases, nil
+}
@@ -123,6 +123,27 @@ func (rh *configFileReleaseHistoryStore) GetLatestConfigFileReleaseHistory(names
 	return fileReleaseHistories[0], nil
 }
 
+// GetConfigFileReleaseHistory 根据 ID 获取配置文件发布历史记录
+func (rh *configFileReleaseHistoryStore) GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error) {
+	sql := rh.genSelectSql() + "where id = ?"
+
+	rows, err := rh.db.Query(sql, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fileReleaseHistories, err := rh.transferRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fileReleaseHistories) == 0 {
+		return nil, nil
+	}
+
+	return fileReleaseHistories[0], nil
+}
+
 func (rh *configFileReleaseHistoryStore) genSelectSql() string {
 	return "select id, name, namespace, `group`, file_name, content, IFNULL(comment, ''), md5, format, tags, type, " +
 		" status, UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), " +
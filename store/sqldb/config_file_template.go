@@ -111,3 +111,96 @@ func (cf *configFileTemplateStore) transferRows(rows *sql.Rows) ([]*model.Config
 
 	return templates, nil
 }
+
+// CreateConfigFileSchema create config file json schema
+func (cf *configFileTemplateStore) CreateConfigFileSchema(
+	schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+
+	createSql := "insert into config_file_schema(name,content,comment,create_time,create_by, " +
+		" modify_time,modify_by) values " +
+		"(?,?,?,sysdate(),?,sysdate(),?)"
+	_, err := cf.db.Exec(createSql, schema.Name, schema.Content, schema.Comment,
+		schema.CreateBy, schema.ModifyBy)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+
+	return cf.GetConfigFileSchema(schema.Name)
+}
+
+// UpdateConfigFileSchema update config file json schema
+func (cf *configFileTemplateStore) UpdateConfigFileSchema(
+	schema *model.ConfigFileSchema) (*model.ConfigFileSchema, error) {
+
+	updateSql := "update config_file_schema set content = ?, comment = ?, modify_time = sysdate(), modify_by = ? " +
+		" where name = ?"
+	_, err := cf.db.Exec(updateSql, schema.Content, schema.Comment, schema.ModifyBy, schema.Name)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+
+	return cf.GetConfigFileSchema(schema.Name)
+}
+
+// GetConfigFileSchema get config file json schema by name
+func (cf *configFileTemplateStore) GetConfigFileSchema(name string) (*model.ConfigFileSchema, error) {
+	querySql := cf.baseSelectConfigFileSchemaSql() + " where name = ?"
+	rows, err := cf.db.Query(querySql, name)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+
+	schemas, err := cf.transferSchemaRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) > 0 {
+		return schemas[0], nil
+	}
+	return nil, nil
+}
+
+// QueryAllConfigFileSchemas query all config file json schemas
+func (cf *configFileTemplateStore) QueryAllConfigFileSchemas() ([]*model.ConfigFileSchema, error) {
+	querySql := cf.baseSelectConfigFileSchemaSql() + " order by id desc"
+	rows, err := cf.db.Query(querySql)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+
+	return cf.transferSchemaRows(rows)
+}
+
+func (cf *configFileTemplateStore) baseSelectConfigFileSchemaSql() string {
+	return "select id, name, content, IFNULL(comment, ''), UNIX_TIMESTAMP(create_time),  " +
+		" IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') from config_file_schema "
+}
+
+func (cf *configFileTemplateStore) transferSchemaRows(rows *sql.Rows) ([]*model.ConfigFileSchema, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var schemas []*model.ConfigFileSchema
+
+	for rows.Next() {
+		schema := &model.ConfigFileSchema{}
+		var ctime, mtime int64
+		err := rows.Scan(&schema.Id, &schema.Name, &schema.Content, &schema.Comment,
+			&ctime, &schema.CreateBy, &mtime, &schema.ModifyBy)
+		if err != nil {
+			return nil, err
+		}
+		schema.CreateTime = time.Unix(ctime, 0)
+		schema.ModifyTime = time.Unix(mtime, 0)
+
+		schemas = append(schemas, schema)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
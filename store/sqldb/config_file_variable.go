@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+type configFileVariableStore struct {
+	db *BaseDB
+}
+
+// CreateConfigFileVariable 创建模板变量，同一个命名空间下变量名唯一
+func (cfv *configFileVariableStore) CreateConfigFileVariable(
+	variable *model.ConfigFileVariable) (*model.ConfigFileVariable, error) {
+
+	createSql := "insert into config_file_variable(namespace, name, type, value, comment, create_time, create_by, " +
+		" modify_time, modify_by) values (?,?,?,?,?,sysdate(),?,sysdate(),?)"
+	_, err := cfv.db.Exec(createSql, variable.Namespace, variable.Name, variable.Type, variable.Value,
+		variable.Comment, variable.CreateBy, variable.ModifyBy)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfv.GetConfigFileVariable(variable.Namespace, variable.Name)
+}
+
+// UpdateConfigFileVariable 更新模板变量的类型、值以及描述
+func (cfv *configFileVariableStore) UpdateConfigFileVariable(variable *model.ConfigFileVariable) error {
+	updateSql := "update config_file_variable set type = ?, value = ?, comment = ?, modify_time = sysdate(), " +
+		" modify_by = ? where namespace = ? and name = ?"
+	_, err := cfv.db.Exec(updateSql, variable.Type, variable.Value, variable.Comment, variable.ModifyBy,
+		variable.Namespace, variable.Name)
+	return store.Error(err)
+}
+
+// DeleteConfigFileVariable 删除模板变量
+func (cfv *configFileVariableStore) DeleteConfigFileVariable(namespace, name string) error {
+	_, err := cfv.db.Exec("delete from config_file_variable where namespace = ? and name = ?", namespace, name)
+	return store.Error(err)
+}
+
+// GetConfigFileVariable 获取单个模板变量
+func (cfv *configFileVariableStore) GetConfigFileVariable(namespace,
+	name string) (*model.ConfigFileVariable, error) {
+
+	variables, err := cfv.queryVariables(cfv.baseSelectSql()+" where namespace = ? and name = ?", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	return variables[0], nil
+}
+
+// QueryConfigFileVariables 获取命名空间下的全部模板变量，按照变量名排序
+func (cfv *configFileVariableStore) QueryConfigFileVariables(namespace string) ([]*model.ConfigFileVariable,
+	error) {
+	return cfv.queryVariables(cfv.baseSelectSql()+" where namespace = ? order by name", namespace)
+}
+
+func (cfv *configFileVariableStore) baseSelectSql() string {
+	return "select id, namespace, name, type, value, IFNULL(comment, ''), UNIX_TIMESTAMP(create_time), " +
+		" IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') from config_file_variable "
+}
+
+func (cfv *configFileVariableStore) queryVariables(querySql string,
+	args ...interface{}) ([]*model.ConfigFileVariable, error) {
+
+	rows, err := cfv.db.Query(querySql, args...)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	defer rows.Close()
+
+	var variables []*model.ConfigFileVariable
+	for rows.Next() {
+		variable := &model.ConfigFileVariable{}
+		var ctime, mtime int64
+		if err := rows.Scan(&variable.Id, &variable.Namespace, &variable.Name, &variable.Type, &variable.Value,
+			&variable.Comment, &ctime, &variable.CreateBy, &mtime, &variable.ModifyBy); err != nil {
+			return nil, err
+		}
+		variable.CreateTime = time.Unix(ctime, 0)
+		variable.ModifyTime = time.Unix(mtime, 0)
+		variables = append(variables, variable)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
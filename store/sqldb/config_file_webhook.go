@@ -0,0 +1,297 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+type configFileWebhookStore struct {
+	db *BaseDB
+}
+
+// CreateConfigFileWebhook 创建 webhook，同一个命名空间下名称唯一
+func (cfw *configFileWebhookStore) CreateConfigFileWebhook(
+	webhook *model.ConfigFileWebhook) (*model.ConfigFileWebhook, error) {
+
+	createSql := "insert into config_file_webhook(name, namespace, `group`, file_name, url, secret, events, " +
+		" enable, comment, create_time, create_by, modify_time, modify_by) values " +
+		"(?,?,?,?,?,?,?,?,?,sysdate(),?,sysdate(),?)"
+	_, err := cfw.db.Exec(createSql, webhook.Name, webhook.Namespace, webhook.Group, webhook.FileName,
+		webhook.Url, webhook.Secret, webhook.Events, boolToInt(webhook.Enable), webhook.Comment,
+		webhook.CreateBy, webhook.ModifyBy)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+
+	webhooks, err := cfw.queryWebhooks(cfw.baseSelectWebhookSql()+" where namespace = ? and name = ?",
+		webhook.Namespace, webhook.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+	return webhooks[0], nil
+}
+
+// UpdateConfigFileWebhook 更新 webhook，名称以及订阅的配置文件范围不能修改
+func (cfw *configFileWebhookStore) UpdateConfigFileWebhook(webhook *model.ConfigFileWebhook) error {
+	updateSql := "update config_file_webhook set url = ?, secret = ?, events = ?, enable = ?, comment = ?, " +
+		" modify_time = sysdate(), modify_by = ? where id = ?"
+	_, err := cfw.db.Exec(updateSql, webhook.Url, webhook.Secret, webhook.Events, boolToInt(webhook.Enable),
+		webhook.Comment, webhook.ModifyBy, webhook.Id)
+	return store.Error(err)
+}
+
+// DeleteConfigFileWebhook 删除 webhook
+func (cfw *configFileWebhookStore) DeleteConfigFileWebhook(id uint64) error {
+	_, err := cfw.db.Exec("delete from config_file_webhook where id = ?", id)
+	return store.Error(err)
+}
+
+// GetConfigFileWebhook 获取单个 webhook
+func (cfw *configFileWebhookStore) GetConfigFileWebhook(id uint64) (*model.ConfigFileWebhook, error) {
+	webhooks, err := cfw.queryWebhooks(cfw.baseSelectWebhookSql()+" where id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+	return webhooks[0], nil
+}
+
+// GetConfigFileWebhooksByNamespace 获取命名空间下的全部 webhook
+func (cfw *configFileWebhookStore) GetConfigFileWebhooksByNamespace(
+	namespace string) ([]*model.ConfigFileWebhook, error) {
+	return cfw.queryWebhooks(cfw.baseSelectWebhookSql()+" where namespace = ? order by id desc", namespace)
+}
+
+// QueryConfigFileWebhooks 分页查询 webhook，group、fileName 为空时不作为查询条件
+func (cfw *configFileWebhookStore) QueryConfigFileWebhooks(namespace, group, fileName string, offset,
+	limit uint32) (uint32, []*model.ConfigFileWebhook, error) {
+
+	countSql := "select count(*) from config_file_webhook where namespace = ? "
+	querySql := cfw.baseSelectWebhookSql() + " where namespace = ? "
+	queryParams := []interface{}{namespace}
+	if group != "" {
+		countSql += " and `group` = ? "
+		querySql += " and `group` = ? "
+		queryParams = append(queryParams, group)
+	}
+	if fileName != "" {
+		countSql += " and file_name = ? "
+		querySql += " and file_name = ? "
+		queryParams = append(queryParams, fileName)
+	}
+
+	var count uint32
+	if err := cfw.db.QueryRow(countSql, queryParams...).Scan(&count); err != nil {
+		return 0, nil, store.Error(err)
+	}
+
+	querySql += " order by id desc limit ?, ?"
+	queryParams = append(queryParams, offset, limit)
+	webhooks, err := cfw.queryWebhooks(querySql, queryParams...)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, webhooks, nil
+}
+
+func (cfw *configFileWebhookStore) baseSelectWebhookSql() string {
+	return "select id, name, namespace, `group`, file_name, url, secret, events, enable, IFNULL(comment, ''), " +
+		" UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') " +
+		" from config_file_webhook "
+}
+
+func (cfw *configFileWebhookStore) queryWebhooks(querySql string,
+	args ...interface{}) ([]*model.ConfigFileWebhook, error) {
+
+	rows, err := cfw.db.Query(querySql, args...)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	defer rows.Close()
+
+	var webhooks []*model.ConfigFileWebhook
+	for rows.Next() {
+		webhook := &model.ConfigFileWebhook{}
+		var ctime, mtime int64
+		var enable int
+		if err := rows.Scan(&webhook.Id, &webhook.Name, &webhook.Namespace, &webhook.Group, &webhook.FileName,
+			&webhook.Url, &webhook.Secret, &webhook.Events, &enable, &webhook.Comment, &ctime, &webhook.CreateBy,
+			&mtime, &webhook.ModifyBy); err != nil {
+			return nil, err
+		}
+		webhook.Enable = enable == 1
+		webhook.CreateTime = time.Unix(ctime, 0)
+		webhook.ModifyTime = time.Unix(mtime, 0)
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateConfigFileWebhookDelivery 创建推送记录，同一个 webhook 的事件已经存在推送记录时返回 DuplicateEntryErr
+func (cfw *configFileWebhookStore) CreateConfigFileWebhookDelivery(
+	delivery *model.ConfigFileWebhookDelivery) (*model.ConfigFileWebhookDelivery, error) {
+
+	createSql := "insert into config_file_webhook_delivery(webhook_id, event_key, event_type, namespace, " +
+		" `group`, file_name, payload, status, attempts, response_code, error, next_retry_time, create_time, " +
+		" modify_time) values (?,?,?,?,?,?,?,?,?,?,?,FROM_UNIXTIME(?),sysdate(),sysdate())"
+	_, err := cfw.db.Exec(createSql, delivery.WebhookId, delivery.EventKey, delivery.EventType,
+		delivery.Namespace, delivery.Group, delivery.FileName, delivery.Payload, delivery.Status,
+		delivery.Attempts, delivery.ResponseCode, delivery.Error, delivery.NextRetryTime.Unix())
+	if err != nil {
+		return nil, store.Error(err)
+	}
+
+	deliveries, err := cfw.queryDeliveries(cfw.baseSelectDeliverySql()+" where webhook_id = ? and event_key = ?",
+		delivery.WebhookId, delivery.EventKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+	return deliveries[0], nil
+}
+
+// UpdateConfigFileWebhookDelivery 更新推送记录的状态
+func (cfw *configFileWebhookStore) UpdateConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery) error {
+	updateSql := "update config_file_webhook_delivery set status = ?, attempts = ?, response_code = ?, error = ?, " +
+		" next_retry_time = FROM_UNIXTIME(?), modify_time = sysdate() where id = ?"
+	_, err := cfw.db.Exec(updateSql, delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.Error,
+		delivery.NextRetryTime.Unix(), delivery.Id)
+	return store.Error(err)
+}
+
+// ClaimConfigFileWebhookDelivery 认领待推送的记录，通过推送次数做乐观锁，保证同一次推送只有一个节点认领成功
+func (cfw *configFileWebhookStore) ClaimConfigFileWebhookDelivery(delivery *model.ConfigFileWebhookDelivery,
+	leaseTime time.Time) (bool, error) {
+
+	claimSql := "update config_file_webhook_delivery set attempts = attempts + 1, " +
+		" next_retry_time = FROM_UNIXTIME(?), modify_time = sysdate() where id = ? and status = ? and attempts = ?"
+	result, err := cfw.db.Exec(claimSql, leaseTime.Unix(), delivery.Id, utils.WebhookDeliveryPending,
+		delivery.Attempts)
+	if err != nil {
+		return false, store.Error(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, store.Error(err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+	delivery.Attempts++
+	delivery.NextRetryTime = leaseTime
+	return true, nil
+}
+
+// GetDueConfigFileWebhookDeliveries 获取下一次推送时间早于 now 的待推送记录
+func (cfw *configFileWebhookStore) GetDueConfigFileWebhookDeliveries(now time.Time,
+	limit uint32) ([]*model.ConfigFileWebhookDelivery, error) {
+
+	querySql := cfw.baseSelectDeliverySql() + " where status = ? and next_retry_time <= FROM_UNIXTIME(?) " +
+		" order by next_retry_time limit ?"
+	return cfw.queryDeliveries(querySql, utils.WebhookDeliveryPending, now.Unix(), limit)
+}
+
+// QueryConfigFileWebhookDeliveries 分页查询 webhook 的推送记录，status 为空时查询全部状态
+func (cfw *configFileWebhookStore) QueryConfigFileWebhookDeliveries(webhookId uint64, status string, offset,
+	limit uint32) (uint32, []*model.ConfigFileWebhookDelivery, error) {
+
+	countSql := "select count(*) from config_file_webhook_delivery where webhook_id = ? "
+	querySql := cfw.baseSelectDeliverySql() + " where webhook_id = ? "
+	queryParams := []interface{}{webhookId}
+	if status != "" {
+		countSql += " and status = ? "
+		querySql += " and status = ? "
+		queryParams = append(queryParams, status)
+	}
+
+	var count uint32
+	if err := cfw.db.QueryRow(countSql, queryParams...).Scan(&count); err != nil {
+		return 0, nil, store.Error(err)
+	}
+
+	querySql += " order by id desc limit ?, ?"
+	queryParams = append(queryParams, offset, limit)
+	deliveries, err := cfw.queryDeliveries(querySql, queryParams...)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, deliveries, nil
+}
+
+// CleanConfigFileWebhookDeliveries 清理创建时间早于 before 的推送记录
+func (cfw *configFileWebhookStore) CleanConfigFileWebhookDeliveries(before time.Time) error {
+	_, err := cfw.db.Exec("delete from config_file_webhook_delivery where create_time < FROM_UNIXTIME(?)",
+		before.Unix())
+	return store.Error(err)
+}
+
+func (cfw *configFileWebhookStore) baseSelectDeliverySql() string {
+	return "select id, webhook_id, event_key, event_type, namespace, `group`, file_name, payload, status, " +
+		" attempts, response_code, IFNULL(error, ''), UNIX_TIMESTAMP(next_retry_time), UNIX_TIMESTAMP(create_time), " +
+		" UNIX_TIMESTAMP(modify_time) " +
+		" from config_file_webhook_delivery "
+}
+
+func (cfw *configFileWebhookStore) queryDeliveries(querySql string,
+	args ...interface{}) ([]*model.ConfigFileWebhookDelivery, error) {
+
+	rows, err := cfw.db.Query(querySql, args...)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfw.transferDeliveryRows(rows)
+}
+
+func (cfw *configFileWebhookStore) transferDeliveryRows(rows *sql.Rows) ([]*model.ConfigFileWebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []*model.ConfigFileWebhookDelivery
+	for rows.Next() {
+		delivery := &model.ConfigFileWebhookDelivery{}
+		var retryTime, ctime, mtime int64
+		if err := rows.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventKey, &delivery.EventType,
+			&delivery.Namespace, &delivery.Group, &delivery.FileName, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.ResponseCode, &delivery.Error, &retryTime, &ctime, &mtime); err != nil {
+			return nil, err
+		}
+		delivery.NextRetryTime = time.Unix(retryTime, 0)
+		delivery.CreateTime = time.Unix(ctime, 0)
+		delivery.ModifyTime = time.Unix(mtime, 0)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 
 	"github.com/polarismesh/polaris/plugin"
 	"github.com/polarismesh/polaris/store"
@@ -59,9 +60,14 @@ type stableStore struct {
 	*configFileGroupStore
 	*configFileStore
 	*configFileReleaseStore
+	*configFileGrayReleaseStore
+	*configFileChangeStore
 	*configFileReleaseHistoryStore
 	*configFileTagStore
 	*configFileTemplateStore
+	*configFileWebhookStore
+	*configFileVariableStore
+	*configFileClientPinStore
 
 	//client info stores
 	*clientStore
@@ -267,11 +273,18 @@ func (s *stableStore) newStore() {
 
 	s.configFileReleaseStore = &configFileReleaseStore{db: s.master}
 
+	s.configFileGrayReleaseStore = &configFileGrayReleaseStore{db: s.master}
+
+	s.configFileChangeStore = &configFileChangeStore{db: s.master}
+
 	s.configFileReleaseHistoryStore = &configFileReleaseHistoryStore{db: s.master}
 
 	s.configFileTagStore = &configFileTagStore{db: s.master}
 
 	s.configFileTemplateStore = &configFileTemplateStore{db: s.master}
+	s.configFileWebhookStore = &configFileWebhookStore{db: s.master}
+	s.configFileVariableStore = &configFileVariableStore{db: s.master}
+	s.configFileClientPinStore = &configFileClientPinStore{db: s.master}
 
 	s.clientStore = &clientStore{master: s.master, slave: s.slave}
 
@@ -0,0 +1,447 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DialectMySQL mysql 数据库
+	DialectMySQL = "mysql"
+	// DialectPostgres postgresql 数据库
+	DialectPostgres = "postgres"
+)
+
+// Dialect 屏蔽不同数据库之间的 SQL 差异
+// store 内的 SQL 统一按照 MySQL 的语法编写，由 Dialect 转换为目标数据库可以执行的语句
+type Dialect interface {
+	// Name 方言名称
+	Name() string
+	// DriverName database/sql 使用的驱动名称
+	DriverName() string
+	// DSN 根据配置生成连接串
+	DSN(c *dbConfig) string
+	// Rebind 将 MySQL 语法的 SQL 以及参数转换为目标数据库的 SQL 以及参数
+	Rebind(query string, args []interface{}) (string, []interface{})
+	// ReplaceInto 返回覆盖写入语句的头部（到 values 为止）以及需要追加在 values 之后的尾部
+	ReplaceInto(table string, keys []string, columns []string) (string, string)
+	// InsertIgnoreInto 返回主键冲突时忽略写入的语句头部（到 values 为止）以及需要追加在 values 之后的尾部
+	InsertIgnoreInto(table string, keys []string, columns []string) (string, string)
+	// OnDuplicateKeyUpdate 返回主键冲突时进行更新的子句，后面需要紧跟 set 的赋值列表
+	OnDuplicateKeyUpdate(keys ...string) string
+}
+
+// newDialect 根据 dbType 获取对应的 Dialect
+func newDialect(dbType string) Dialect {
+	switch strings.ToLower(dbType) {
+	case DialectPostgres, "postgresql", "pgsql":
+		return &postgresDialect{}
+	default:
+		// 其他类型按照 MySQL 协议处理，保持原来直接使用 dbType 作为驱动名称的行为
+		return &mysqlDialect{driverName: dbType}
+	}
+}
+
+// mysqlDialect MySQL 方言，store 内的 SQL 本身就是 MySQL 语法，不做任何转换
+type mysqlDialect struct {
+	driverName string
+}
+
+// Name 方言名称
+func (m *mysqlDialect) Name() string {
+	return DialectMySQL
+}
+
+// DriverName 驱动名称
+func (m *mysqlDialect) DriverName() string {
+	return m.driverName
+}
+
+// DSN 生成 go-sql-driver/mysql 的连接串
+func (m *mysqlDialect) DSN(c *dbConfig) string {
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s", c.dbUser, c.dbPwd, c.dbAddr, c.dbName)
+}
+
+// Rebind MySQL 不需要转换
+func (m *mysqlDialect) Rebind(query string, args []interface{}) (string, []interface{}) {
+	return query, args
+}
+
+// ReplaceInto replace into
+func (m *mysqlDialect) ReplaceInto(table string, _ []string, columns []string) (string, string) {
+	return fmt.Sprintf("replace into %s(%s) values", table, strings.Join(columns, ", ")), ""
+}
+
+// InsertIgnoreInto insert ignore into
+func (m *mysqlDialect) InsertIgnoreInto(table string, _ []string, columns []string) (string, string) {
+	return fmt.Sprintf("insert ignore into %s(%s) values", table, strings.Join(columns, ", ")), ""
+}
+
+// OnDuplicateKeyUpdate on duplicate key update
+func (m *mysqlDialect) OnDuplicateKeyUpdate(_ ...string) string {
+	return " on duplicate key update "
+}
+
+// postgresDialect PostgreSQL 方言
+type postgresDialect struct {
+}
+
+// Name 方言名称
+func (p *postgresDialect) Name() string {
+	return DialectPostgres
+}
+
+// DriverName 驱动名称，对应 github.com/lib/pq
+func (p *postgresDialect) DriverName() string {
+	return "postgres"
+}
+
+// DSN 生成 lib/pq 的连接串，dbAddr 格式为 host:port
+func (p *postgresDialect) DSN(c *dbConfig) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.dbUser, c.dbPwd),
+		Host:     c.dbAddr,
+		Path:     "/" + c.dbName,
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
+
+// Rebind 转换 SQL 语法以及参数
+func (p *postgresDialect) Rebind(query string, args []interface{}) (string, []interface{}) {
+	r := &pgRewriter{src: query}
+	query = r.rewrite(false, false)
+
+	var newArgs []interface{}
+	for i := range args {
+		// MySQL 中 bool 存储在 tinyint 字段中，PostgreSQL 不会自动转换
+		b, ok := args[i].(bool)
+		if !ok {
+			continue
+		}
+		if newArgs == nil {
+			newArgs = make([]interface{}, len(args))
+			copy(newArgs, args)
+		}
+		if b {
+			newArgs[i] = 1
+		} else {
+			newArgs[i] = 0
+		}
+	}
+	if newArgs == nil {
+		newArgs = args
+	}
+	return query, newArgs
+}
+
+// ReplaceInto insert into ... on conflict do update
+func (p *postgresDialect) ReplaceInto(table string, keys []string, columns []string) (string, string) {
+	head := fmt.Sprintf("insert into %s(%s) values", quotePgTable(table), strings.Join(columns, ", "))
+	sets := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if containsString(keys, column) {
+			continue
+		}
+		sets = append(sets, fmt.Sprintf("%s = excluded.%s", column, column))
+	}
+	if len(sets) == 0 {
+		return head, fmt.Sprintf(" on conflict (%s) do nothing", strings.Join(keys, ", "))
+	}
+	return head, fmt.Sprintf(" on conflict (%s) do update set %s", strings.Join(keys, ", "),
+		strings.Join(sets, ", "))
+}
+
+// InsertIgnoreInto insert into ... on conflict do nothing
+func (p *postgresDialect) InsertIgnoreInto(table string, keys []string, columns []string) (string, string) {
+	head := fmt.Sprintf("insert into %s(%s) values", quotePgTable(table), strings.Join(columns, ", "))
+	return head, fmt.Sprintf(" on conflict (%s) do nothing", strings.Join(keys, ", "))
+}
+
+// OnDuplicateKeyUpdate on conflict do update set
+func (p *postgresDialect) OnDuplicateKeyUpdate(keys ...string) string {
+	return fmt.Sprintf(" on conflict (%s) do update set ", strings.Join(keys, ", "))
+}
+
+// quotePgTable user 在 PostgreSQL 中是保留字
+func quotePgTable(table string) string {
+	if strings.EqualFold(table, "user") {
+		return `"user"`
+	}
+	return table
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// pgFunctions 需要转换的 MySQL 函数以及对应的 PostgreSQL 函数
+var pgFunctions = map[string]string{
+	"UNIX_TIMESTAMP": "",
+	"STR_TO_DATE":    "TO_TIMESTAMP",
+	"FROM_UNIXTIME":  "TO_TIMESTAMP",
+	"SYSDATE":        "CLOCK_TIMESTAMP",
+	"IFNULL":         "COALESCE",
+}
+
+// pgDateFormat MySQL 日期格式到 PostgreSQL 日期格式的转换
+var pgDateFormat = strings.NewReplacer("%Y", "YYYY", "%m", "MM", "%d", "DD",
+	"%H", "HH24", "%i", "MI", "%s", "SS")
+
+// pgTableKeywords 后面紧跟表名的关键字
+var pgTableKeywords = map[string]struct{}{
+	"FROM":   {},
+	"JOIN":   {},
+	"INTO":   {},
+	"UPDATE": {},
+}
+
+// pgRewriter 将 MySQL 语法的 SQL 转换为 PostgreSQL 语法
+// 1. ? 占位符转换为 $n
+// 2. `name` 转换为 "name"，"value" 转换为 'value'
+// 3. UNIX_TIMESTAMP/FROM_UNIXTIME/SYSDATE/IFNULL/STR_TO_DATE 转换为对应的函数
+// 4. limit offset, count 转换为 limit count offset offset
+// 5. lock in share mode 转换为 for share
+// 6. 作为表名的 user 转换为 "user"
+type pgRewriter struct {
+	src      string
+	pos      int
+	bindVar  int
+	prevWord string
+}
+
+// rewrite 从当前位置开始转换，inParen 为 true 时在遇到未匹配的 ')' 时结束并消费掉该 ')'，
+// stopAtComma 为 true 时在遇到同层级的 ',' 时结束
+func (r *pgRewriter) rewrite(inParen bool, stopAtComma bool) string {
+	var sb strings.Builder
+	for r.pos < len(r.src) {
+		c := r.src[r.pos]
+		switch {
+		case c == '\'':
+			sb.WriteString(r.readQuoted('\''))
+		case c == '"':
+			// MySQL 中双引号表示字符串，PostgreSQL 中表示标识符
+			value := strings.ReplaceAll(trimQuote(r.readQuoted('"'), '"'), `""`, `"`)
+			sb.WriteString("'" + strings.ReplaceAll(value, "'", "''") + "'")
+		case c == '`':
+			name := trimQuote(r.readQuoted('`'), '`')
+			sb.WriteString(`"` + strings.ToLower(name) + `"`)
+			r.prevWord = ""
+		case c == '?':
+			r.pos++
+			sb.WriteString(r.nextBindVar())
+		case c == '(':
+			r.pos++
+			sb.WriteString("(" + r.rewrite(true, false) + ")")
+		case c == ')':
+			r.pos++
+			if inParen {
+				return sb.String()
+			}
+			sb.WriteByte(c)
+		case c == ',' && stopAtComma:
+			return sb.String()
+		case isIdentStart(c):
+			sb.WriteString(r.rewriteWord())
+		default:
+			r.pos++
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func (r *pgRewriter) rewriteWord() string {
+	qualified := r.pos > 0 && r.src[r.pos-1] == '.'
+	start := r.pos
+	for r.pos < len(r.src) && isIdentPart(r.src[r.pos]) {
+		r.pos++
+	}
+	word := r.src[start:r.pos]
+	upper := strings.ToUpper(word)
+	prevWord := r.prevWord
+	r.prevWord = upper
+	if qualified {
+		return word
+	}
+
+	if _, ok := pgFunctions[upper]; ok {
+		if next := r.skipSpaces(); next < len(r.src) && r.src[next] == '(' {
+			r.pos = next + 1
+			return r.rewriteFunction(upper)
+		}
+	}
+	switch upper {
+	case "LIMIT":
+		return r.rewriteLimit(word)
+	case "LOCK":
+		if end, ok := r.matchWords("IN", "SHARE", "MODE"); ok {
+			r.pos = end
+			return "FOR SHARE"
+		}
+	case "USER":
+		if _, ok := pgTableKeywords[prevWord]; ok {
+			return `"user"`
+		}
+	}
+	return word
+}
+
+// rewriteFunction 转换函数调用，此时已经消费掉函数名称后的 '('
+func (r *pgRewriter) rewriteFunction(name string) string {
+	switch name {
+	case "UNIX_TIMESTAMP":
+		arg := r.rewrite(true, false)
+		if strings.TrimSpace(arg) == "" {
+			arg = "CLOCK_TIMESTAMP()"
+		}
+		return "CAST(FLOOR(EXTRACT(EPOCH FROM " + arg + ")) AS BIGINT)"
+	case "STR_TO_DATE":
+		value := r.rewrite(true, true)
+		if r.pos < len(r.src) && r.src[r.pos] == ',' {
+			r.pos++
+		}
+		format := r.rewrite(true, false)
+		return "TO_TIMESTAMP(" + value + ", " + pgDateFormat.Replace(strings.TrimSpace(format)) + ")"
+	default:
+		return pgFunctions[name] + "(" + r.rewrite(true, false) + ")"
+	}
+}
+
+// rewriteLimit limit offset, count => limit count offset offset
+func (r *pgRewriter) rewriteLimit(word string) string {
+	start := r.pos
+	r.pos = r.skipSpaces()
+	first, ok := r.readOperand()
+	if !ok {
+		r.pos = start
+		return word
+	}
+	afterFirst := r.pos
+	r.pos = r.skipSpaces()
+	if r.pos >= len(r.src) || r.src[r.pos] != ',' {
+		r.pos = afterFirst
+		return word + " " + first
+	}
+	r.pos++
+	r.pos = r.skipSpaces()
+	second, ok := r.readOperand()
+	if !ok {
+		r.pos = afterFirst
+		return word + " " + first
+	}
+	return word + " " + second + " OFFSET " + first
+}
+
+// readOperand 读取 limit 的参数，只支持占位符以及数字
+func (r *pgRewriter) readOperand() (string, bool) {
+	if r.pos >= len(r.src) {
+		return "", false
+	}
+	if r.src[r.pos] == '?' {
+		r.pos++
+		return r.nextBindVar(), true
+	}
+	start := r.pos
+	for r.pos < len(r.src) && r.src[r.pos] >= '0' && r.src[r.pos] <= '9' {
+		r.pos++
+	}
+	return r.src[start:r.pos], r.pos > start
+}
+
+// matchWords 判断接下来的单词是否依次为 words，返回匹配结束的位置
+func (r *pgRewriter) matchWords(words ...string) (int, bool) {
+	pos := r.pos
+	for _, word := range words {
+		next := pos
+		for next < len(r.src) && isSpace(r.src[next]) {
+			next++
+		}
+		if next == pos || next+len(word) > len(r.src) ||
+			!strings.EqualFold(r.src[next:next+len(word)], word) {
+			return 0, false
+		}
+		pos = next + len(word)
+		if pos < len(r.src) && isIdentPart(r.src[pos]) {
+			return 0, false
+		}
+	}
+	return pos, true
+}
+
+func (r *pgRewriter) readQuoted(quote byte) string {
+	start := r.pos
+	r.pos++
+	for r.pos < len(r.src) {
+		if r.src[r.pos] == quote {
+			// 连续两个引号表示转义
+			if r.pos+1 < len(r.src) && r.src[r.pos+1] == quote {
+				r.pos += 2
+				continue
+			}
+			r.pos++
+			return r.src[start:r.pos]
+		}
+		r.pos++
+	}
+	return r.src[start:r.pos]
+}
+
+// trimQuote 去掉 readQuoted 结果两端的引号
+func trimQuote(raw string, quote byte) string {
+	raw = raw[1:]
+	if len(raw) > 0 && raw[len(raw)-1] == quote {
+		raw = raw[:len(raw)-1]
+	}
+	return raw
+}
+
+func (r *pgRewriter) nextBindVar() string {
+	r.bindVar++
+	return "$" + strconv.Itoa(r.bindVar)
+}
+
+func (r *pgRewriter) skipSpaces() int {
+	pos := r.pos
+	for pos < len(r.src) && isSpace(r.src[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
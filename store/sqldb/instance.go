@@ -56,6 +56,11 @@ func (ins *instanceStore) addInstance(instance *model.Instance) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	// 检查命名空间的实例配额
+	if err := checkInstanceQuota(tx, []*model.Instance{instance}); err != nil {
+		return err
+	}
+
 	// 先对服务加锁
 	revision, err := rlockServiceWithID(tx.QueryRow, instance.ServiceID)
 	if err != nil {
@@ -72,6 +77,11 @@ func (ins *instanceStore) addInstance(instance *model.Instance) error {
 		return err
 	}
 
+	// PostgreSQL 下 replace into 转换为 upsert，不会级联删除旧的健康检查数据，这里需要主动清理
+	if err := deleteInstanceCheck(tx, instance.ID()); err != nil {
+		log.Errorf("[Store][database] add instance clean health check err: %s", err.Error())
+		return err
+	}
 	if err := addInstanceCheck(tx, instance); err != nil {
 		return err
 	}
@@ -112,10 +122,19 @@ func (ins *instanceStore) batchAddInstances(instances []*model.Instance) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	// 检查命名空间的实例配额
+	if err := checkInstanceQuota(tx, instances); err != nil {
+		return err
+	}
+
 	if err := batchAddMainInstances(tx, instances); err != nil {
 		log.Errorf("[Store][database] batch add main instances err: %s", err.Error())
 		return err
 	}
+	if err := batchDeleteInstanceCheck(tx, instances); err != nil {
+		log.Errorf("[Store][database] batch delete instance check err: %s", err.Error())
+		return err
+	}
 	if err := batchAddInstanceCheck(tx, instances); err != nil {
 		log.Errorf("[Store][database] batch add instance check err: %s", err.Error())
 		return err
@@ -797,12 +816,19 @@ func batchQueryMetadata(queryHandler QueryHandler, instances []interface{}) (*sq
 	return rows, nil
 }
 
+// instanceColumns instance 主表写入的字段
+var instanceColumns = []string{"id", "service_id", "vpc_id", "host", "port", "protocol", "version", "health_status",
+	"isolate", "weight", "enable_health_check", "logic_set", "cmdb_region", "cmdb_zone", "cmdb_idc", "priority",
+	"revision", "ctime", "mtime"}
+
+// healthCheckColumns health_check 表写入的字段
+var healthCheckColumns = []string{"id", "type", "ttl"}
+
 // addMainInstance 往instance主表中增加数据
 func addMainInstance(tx *BaseTx, instance *model.Instance) error {
 	// #lizard forgives
-	str := `replace into instance(id, service_id, vpc_id, host, port, protocol, version, health_status, isolate,
-		 weight, enable_health_check, logic_set, cmdb_region, cmdb_zone, cmdb_idc, priority, revision, ctime, mtime)
-			 values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, sysdate(), sysdate())`
+	str, onConflict := tx.Dialect().ReplaceInto("instance", []string{"id"}, instanceColumns)
+	str += "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, sysdate(), sysdate())" + onConflict
 	_, err := tx.Exec(str, instance.ID(), instance.ServiceID, instance.VpcID(), instance.Host(), instance.Port(),
 		instance.Protocol(), instance.Version(), instance.Healthy(), instance.Isolate(), instance.Weight(),
 		instance.EnableHealthCheck(), instance.LogicSet(), instance.Location().GetRegion().GetValue(),
@@ -813,9 +839,7 @@ func addMainInstance(tx *BaseTx, instance *model.Instance) error {
 
 // batchAddMainInstances 批量增加main instance数据
 func batchAddMainInstances(tx *BaseTx, instances []*model.Instance) error {
-	str := `replace into instance(id, service_id, vpc_id, host, port, protocol, version, health_status, isolate,
-		 weight, enable_health_check, logic_set, cmdb_region, cmdb_zone, cmdb_idc, priority, revision, ctime, mtime) 
-		 values`
+	str, onConflict := tx.Dialect().ReplaceInto("instance", []string{"id"}, instanceColumns)
 	first := true
 	args := make([]interface{}, 0)
 	for _, entry := range instances {
@@ -831,6 +855,7 @@ func batchAddMainInstances(tx *BaseTx, instances []*model.Instance) error {
 			entry.Location().GetRegion().GetValue(), entry.Location().GetZone().GetValue(),
 			entry.Location().GetCampus().GetValue(), entry.Priority(), entry.Revision())
 	}
+	str += onConflict
 	_, err := tx.Exec(str, args...)
 	return err
 }
@@ -842,7 +867,8 @@ func addInstanceCheck(tx *BaseTx, instance *model.Instance) error {
 		return nil
 	}
 
-	str := "replace into health_check(`id`, `type`, `ttl`) values(?, ?, ?)"
+	str, onConflict := tx.Dialect().ReplaceInto("health_check", []string{"id"}, healthCheckColumns)
+	str += "(?, ?, ?)" + onConflict
 	_, err := tx.Exec(str, instance.ID(), check.GetType(),
 		check.GetHeartbeat().GetTtl().GetValue())
 	return err
@@ -850,7 +876,7 @@ func addInstanceCheck(tx *BaseTx, instance *model.Instance) error {
 
 // batchAddInstanceCheck 批量增加healthCheck数据
 func batchAddInstanceCheck(tx *BaseTx, instances []*model.Instance) error {
-	str := "replace into health_check(`id`, `type`, `ttl`) values"
+	str, onConflict := tx.Dialect().ReplaceInto("health_check", []string{"id"}, healthCheckColumns)
 	first := true
 	args := make([]interface{}, 0)
 	for _, entry := range instances {
@@ -869,6 +895,7 @@ func batchAddInstanceCheck(tx *BaseTx, instances []*model.Instance) error {
 	if first {
 		return nil
 	}
+	str += onConflict
 	_, err := tx.Exec(str, args...)
 	return err
 
@@ -926,6 +953,28 @@ func batchDeleteInstanceMeta(tx *BaseTx, instances []*model.Instance) error {
 	return err
 }
 
+// batchDeleteInstanceCheck 批量删除healthCheck数据
+func batchDeleteInstanceCheck(tx *BaseTx, instances []*model.Instance) error {
+	ids := make([]interface{}, 0, len(instances))
+	placeholders := make([]string, 0, len(instances))
+	for _, entry := range instances {
+		// 首次注册的实例不存在旧的健康检查数据
+		if entry.FirstRegis {
+			continue
+		}
+		placeholders = append(placeholders, "?")
+		ids = append(ids, entry.ID())
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	str := `delete from health_check where id in (` + strings.Join(placeholders, ",") + `)`
+	_, err := tx.Exec(str, ids...)
+	return err
+}
+
 // batchAddInstanceMeta 批量增加metadata数据
 func batchAddInstanceMeta(tx *BaseTx, instances []*model.Instance) error {
 	str := "insert into instance_metadata(`id`, `mkey`, `mvalue`, `ctime`, `mtime`) values"
@@ -978,7 +1027,8 @@ func updateInstanceCheck(tx *BaseTx, instance *model.Instance) error {
 		return deleteInstanceCheck(tx, instance.ID())
 	}
 
-	str := "replace into health_check(id, type, ttl) values(?, ?, ?)"
+	str, onConflict := tx.Dialect().ReplaceInto("health_check", []string{"id"}, healthCheckColumns)
+	str += "(?, ?, ?)" + onConflict
 	_, err := tx.Exec(str, instance.ID(), check.GetType(),
 		check.GetHeartbeat().GetTtl().GetValue())
 	return err
@@ -21,6 +21,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/polarismesh/polaris/common/model"
@@ -43,8 +44,10 @@ func (ns *namespaceStore) AddNamespace(namespace *model.Namespace) error {
 		return err
 	}
 
-	str := "insert into namespace(name, comment, token, owner, ctime, mtime) values(?,?,?,?,sysdate(),sysdate())"
-	_, err := ns.db.Exec(str, namespace.Name, namespace.Comment, namespace.Token, namespace.Owner)
+	str := `insert into namespace(name, comment, token, owner, service_quota, instance_quota, config_file_quota,
+		ctime, mtime) values(?,?,?,?,?,?,?,sysdate(),sysdate())`
+	_, err := ns.db.Exec(str, namespace.Name, namespace.Comment, namespace.Token, namespace.Owner,
+		namespace.ServiceQuota, namespace.InstanceQuota, namespace.ConfigFileQuota)
 	return store.Error(err)
 }
 
@@ -54,8 +57,10 @@ func (ns *namespaceStore) UpdateNamespace(namespace *model.Namespace) error {
 		return errors.New("store update namespace name is empty")
 	}
 
-	str := "update namespace set owner = ?, comment = ?,mtime = sysdate() where name = ?"
-	_, err := ns.db.Exec(str, namespace.Owner, namespace.Comment, namespace.Name)
+	str := `update namespace set owner = ?, comment = ?, service_quota = ?, instance_quota = ?,
+		config_file_quota = ?, mtime = sysdate() where name = ?`
+	_, err := ns.db.Exec(str, namespace.Owner, namespace.Comment, namespace.ServiceQuota, namespace.InstanceQuota,
+		namespace.ConfigFileQuota, namespace.Name)
 	return store.Error(err)
 }
 
@@ -219,9 +224,137 @@ func rlockNamespace(queryRow func(query string, args ...interface{}) *sql.Row, n
 	}
 }
 
+// getNamespaceQuota 在事务内查询命名空间的配额，lock 为 true 时对命名空间加写锁
+func getNamespaceQuota(tx *BaseTx, name string, lock bool) (*model.Namespace, error) {
+	str := "select name, service_quota, instance_quota from namespace where name = ? and flag != 1"
+	if lock {
+		str += " for update"
+	}
+
+	namespace := &model.Namespace{}
+	err := tx.QueryRow(str, name).Scan(&namespace.Name, &namespace.ServiceQuota, &namespace.InstanceQuota)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return namespace, nil
+	}
+}
+
+// checkServiceQuota 在事务内检查命名空间的服务配额
+// 设置了配额时对命名空间加写锁，同一命名空间下的服务创建串行执行，避免并发创建超出配额
+func checkServiceQuota(tx *BaseTx, name string) error {
+	namespace, err := getNamespaceQuota(tx, name, false)
+	if err != nil || namespace == nil || namespace.ServiceQuota == 0 {
+		return err
+	}
+	if namespace, err = getNamespaceQuota(tx, name, true); err != nil || namespace == nil {
+		return err
+	}
+
+	var total uint32
+	str := "select count(*) from service where namespace = ? and flag = 0"
+	if err := tx.QueryRow(str, name).Scan(&total); err != nil {
+		return err
+	}
+	if total >= namespace.ServiceQuota {
+		return store.NewStatusError(store.ExceedNamespaceQuota,
+			fmt.Sprintf("namespace(%s) exceed service quota %d", name, namespace.ServiceQuota))
+	}
+	return nil
+}
+
+// checkInstanceQuota 在事务内检查命名空间的实例配额，已经存在的实例重新注册不占用新的配额
+// 设置了配额时对命名空间加写锁，同一命名空间下的实例注册串行执行，避免并发注册超出配额
+// 服务层根据缓存判断距离配额还有足够余量的实例（SkipQuotaCheck）不需要加锁检查
+func checkInstanceQuota(tx *BaseTx, instances []*model.Instance) error {
+	svcIds := make([]interface{}, 0, len(instances))
+	checks := make([]*model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.SkipQuotaCheck {
+			continue
+		}
+		svcIds = append(svcIds, instance.ServiceID)
+		checks = append(checks, instance)
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+	str := "select id, namespace from service where id in (" + PlaceholdersN(len(svcIds)) + ")"
+	rows, err := tx.Query(str, svcIds...)
+	if err != nil {
+		return err
+	}
+	svcNamespaces := make(map[string]string, len(svcIds))
+	for rows.Next() {
+		var id, namespace string
+		if err := rows.Scan(&id, &namespace); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		svcNamespaces[id] = namespace
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	newIds := make(map[string][]interface{})
+	for _, instance := range checks {
+		namespace, ok := svcNamespaces[instance.ServiceID]
+		if !ok {
+			continue
+		}
+		newIds[namespace] = append(newIds[namespace], instance.ID())
+	}
+	// 按照固定的顺序对命名空间加锁，避免死锁
+	names := make([]string, 0, len(newIds))
+	for name := range newIds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		namespace, err := getNamespaceQuota(tx, name, false)
+		if err != nil {
+			return err
+		}
+		if namespace == nil || namespace.InstanceQuota == 0 {
+			continue
+		}
+		if namespace, err = getNamespaceQuota(tx, name, true); err != nil {
+			return err
+		}
+		if namespace == nil {
+			continue
+		}
+
+		countSql := "select count(*) from instance inner join service on instance.service_id = service.id " +
+			"where service.namespace = ? and instance.flag = 0"
+		var total, existed uint32
+		if err := tx.QueryRow(countSql, name).Scan(&total); err != nil {
+			return err
+		}
+		ids := newIds[name]
+		args := append([]interface{}{name}, ids...)
+		existSql := countSql + " and instance.id in (" + PlaceholdersN(len(ids)) + ")"
+		if err := tx.QueryRow(existSql, args...).Scan(&existed); err != nil {
+			return err
+		}
+		if total+uint32(len(ids))-existed > namespace.InstanceQuota {
+			return store.NewStatusError(store.ExceedNamespaceQuota,
+				fmt.Sprintf("namespace(%s) exceed instance quota %d", name, namespace.InstanceQuota))
+		}
+	}
+	return nil
+}
+
 // genNamespaceSelectSQL 生成namespace的查询语句
 func genNamespaceSelectSQL() string {
-	str := `select name, IFNULL(comment, ""), token, owner, flag, UNIX_TIMESTAMP(ctime), UNIX_TIMESTAMP(mtime)
+	str := `select name, IFNULL(comment, ""), token, owner, flag, service_quota, instance_quota,
+			config_file_quota, UNIX_TIMESTAMP(ctime), UNIX_TIMESTAMP(mtime)
 			from namespace `
 	return str
 }
@@ -245,6 +378,9 @@ func namespaceFetchRows(rows *sql.Rows) ([]*model.Namespace, error) {
 			&space.Token,
 			&space.Owner,
 			&flag,
+			&space.ServiceQuota,
+			&space.InstanceQuota,
+			&space.ConfigFileQuota,
 			&ctime,
 			&mtime)
 		if err != nil {
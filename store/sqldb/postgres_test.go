@@ -0,0 +1,206 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	. "github.com/smartystreets/goconvey/convey"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// newPostgresMockStore 使用 sqlmock 模拟 PostgreSQL 数据库创建 stableStore
+func newPostgresMockStore(t *testing.T) (*stableStore, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &dbConfig{dbType: DialectPostgres}
+	base := &BaseDB{DB: db, cfg: cfg, dialect: newDialect(cfg.dbType)}
+	s := &stableStore{master: base, masterTx: base, slave: base, start: true}
+	s.newStore()
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return s, mock
+}
+
+// TestPostgres_LockService 测试 PostgreSQL 下服务的加锁语句
+func TestPostgres_LockService(t *testing.T) {
+	Convey("LockService 使用 for update", t, func() {
+		s, mock := newPostgresMockStore(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`COALESCE(business, '')`)+".*"+regexp.QuoteMeta(
+			`from service where name = $1 and namespace = $2 and flag !=1 for update`)).
+			WithArgs("svc", "ns").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		tx, err := s.CreateTransaction()
+		So(err, ShouldBeNil)
+		svc, err := tx.LockService("svc", "ns")
+		So(err, ShouldBeNil)
+		So(svc, ShouldBeNil)
+		So(tx.Commit(), ShouldBeNil)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+	Convey("RLockService 使用 for share", t, func() {
+		s, mock := newPostgresMockStore(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`from service where name = $1 and namespace = $2 and flag !=1 FOR SHARE`)).
+			WithArgs("svc", "ns").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		tx, err := s.CreateTransaction()
+		So(err, ShouldBeNil)
+		svc, err := tx.RLockService("svc", "ns")
+		So(err, ShouldBeNil)
+		So(svc, ShouldBeNil)
+		So(tx.Commit(), ShouldBeNil)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+}
+
+// TestPostgres_GetMoreInstances 测试 PostgreSQL 下缓存增量拉取的时间条件
+func TestPostgres_GetMoreInstances(t *testing.T) {
+	Convey("mtime 条件转换为 TO_TIMESTAMP", t, func() {
+		s, mock := newPostgresMockStore(t)
+		mtime := time.Unix(1680000000, 0)
+		mock.ExpectQuery(regexp.QuoteMeta(`CAST(FLOOR(EXTRACT(EPOCH FROM instance.mtime)) AS BIGINT)`)+".*"+
+			regexp.QuoteMeta(`where instance.mtime >= TO_TIMESTAMP($1) and service_id in ($2)`)).
+			WithArgs(mtime.Unix(), "svc-id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		instances, err := s.GetMoreInstances(mtime, false, false, []string{"svc-id"})
+		So(err, ShouldBeNil)
+		So(instances, ShouldBeEmpty)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+	Convey("获取数据库时间", t, func() {
+		s, mock := newPostgresMockStore(t)
+		mock.ExpectQuery(regexp.QuoteMeta(`select CAST(FLOOR(EXTRACT(EPOCH FROM CLOCK_TIMESTAMP())) AS BIGINT)`)).
+			WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(int64(1680000000)))
+
+		now, err := s.GetUnixSecond()
+		So(err, ShouldBeNil)
+		So(now, ShouldEqual, 1680000000)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+}
+
+// TestPostgres_AddInstance 测试 PostgreSQL 下实例的覆盖写入
+func TestPostgres_AddInstance(t *testing.T) {
+	Convey("replace into 转换为 on conflict", t, func() {
+		s, mock := newPostgresMockStore(t)
+		instance := &model.Instance{
+			ServiceID: "svc-id",
+			Proto: &api.Instance{
+				Id:          &wrappers.StringValue{Value: "ins-id"},
+				Host:        &wrappers.StringValue{Value: "127.0.0.1"},
+				Port:        &wrappers.UInt32Value{Value: 8080},
+				Healthy:     &wrappers.BoolValue{Value: true},
+				Revision:    &wrappers.StringValue{Value: "rev"},
+				HealthCheck: &api.HealthCheck{Type: api.HealthCheck_HEARTBEAT},
+			},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`select id, namespace from service where id in ($1)`)).
+			WithArgs("svc-id").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "namespace"}).AddRow("svc-id", "ns"))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`select name, service_quota, instance_quota from namespace where name = $1 and flag != 1`)).
+			WithArgs("ns").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "service_quota", "instance_quota"}).AddRow("ns", 0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`select revision from service where id = $1 and flag != 1 FOR SHARE`)).
+			WithArgs("svc-id").
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow("svc-rev"))
+		mock.ExpectExec(regexp.QuoteMeta(`insert into instance(id, service_id, vpc_id, host, port, protocol, `)+
+			".*"+regexp.QuoteMeta(`values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, `+
+			`$17, CLOCK_TIMESTAMP(), CLOCK_TIMESTAMP()) on conflict (id) do update set service_id = excluded.service_id`)).
+			WithArgs("ins-id", "svc-id", "", "127.0.0.1", uint32(8080), "", "", 1, 0, uint32(0), 0, "",
+				"", "", "", int32(0), "rev").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`delete from health_check where id = $1`)).
+			WithArgs("ins-id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`insert into health_check(id, type, ttl) values($1, $2, $3) `+
+			`on conflict (id) do update set type = excluded.type, ttl = excluded.ttl`)).
+			WithArgs("ins-id", api.HealthCheck_HEARTBEAT, uint32(0)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		So(s.addInstance(instance), ShouldBeNil)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+}
+
+// TestPostgres_SkipInstanceQuotaCheck 测试服务层根据缓存判断余量充足的实例不对命名空间加锁统计
+func TestPostgres_SkipInstanceQuotaCheck(t *testing.T) {
+	Convey("跳过配额检查的实例不执行任何查询", t, func() {
+		s, mock := newPostgresMockStore(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		tx, err := s.master.Begin()
+		So(err, ShouldBeNil)
+		err = checkInstanceQuota(tx, []*model.Instance{{
+			ServiceID:      "svc-id",
+			SkipQuotaCheck: true,
+			Proto:          &api.Instance{Id: &wrappers.StringValue{Value: "ins-id"}},
+		}})
+		So(err, ShouldBeNil)
+		So(tx.Rollback(), ShouldBeNil)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+}
+
+// TestPostgres_AddServiceExceedQuota 测试服务配额在创建服务的事务内加锁检查
+func TestPostgres_AddServiceExceedQuota(t *testing.T) {
+	Convey("超出服务配额时回滚事务", t, func() {
+		s, mock := newPostgresMockStore(t)
+		quotaSql := `select name, service_quota, instance_quota from namespace where name = $1 and flag != 1`
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(quotaSql)).
+			WithArgs("ns").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "service_quota", "instance_quota"}).AddRow("ns", 1, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(quotaSql + ` for update`)).
+			WithArgs("ns").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "service_quota", "instance_quota"}).AddRow("ns", 1, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`select count(*) from service where namespace = $1 and flag = 0`)).
+			WithArgs("ns").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		err := s.addService(&model.Service{ID: "svc-id", Name: "svc", Namespace: "ns"})
+		So(store.Code(err), ShouldEqual, store.ExceedNamespaceQuota)
+		So(mock.ExpectationsWereMet(), ShouldBeNil)
+	})
+}
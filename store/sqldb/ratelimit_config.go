@@ -82,8 +82,8 @@ func (rls *rateLimitStore) createRateLimit(limit *model.RateLimit) error {
 	}
 
 	// 更新last_revision
-	str = `insert into ratelimit_revision(service_id,last_revision,mtime) values(?,?,sysdate()) on duplicate key update
-			last_revision = ?`
+	str = `insert into ratelimit_revision(service_id,last_revision,mtime) values(?,?,sysdate())` +
+		tx.Dialect().OnDuplicateKeyUpdate("service_id") + `last_revision = ?`
 	if _, err := tx.Exec(str, limit.ServiceID, limit.Revision, limit.Revision); err != nil {
 		log.Errorf("[Store][database][Create] update rate limit revision with service id(%s) err: %s",
 			limit.ServiceID, err.Error())
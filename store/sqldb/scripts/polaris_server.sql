@@ -111,6 +111,9 @@ CREATE TABLE `namespace`
     `token`   varchar(64)   NOT NULL comment 'TOKEN named space for write operation check',
     `owner`   varchar(1024) NOT NULL comment 'Responsible for named space Owner',
     `flag`    tinyint(4)    NOT NULL DEFAULT '0' comment 'Logic delete flag, 0 means visible, 1 means that it has been logically deleted',
+    `service_quota`     int(11) unsigned NOT NULL DEFAULT '0' comment 'Max services of namespace, 0 means unlimited',
+    `instance_quota`    int(11) unsigned NOT NULL DEFAULT '0' comment 'Max instances of namespace, 0 means unlimited',
+    `config_file_quota` int(11) unsigned NOT NULL DEFAULT '0' comment 'Max config files of namespace, 0 means unlimited',
     `ctime`   timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP comment 'Create time',
     `mtime`   timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP comment 'Last updated time',
     PRIMARY KEY (`name`)
@@ -629,6 +632,59 @@ CREATE TABLE `config_file_release`
 ) ENGINE = InnoDB
   AUTO_INCREMENT = 1 COMMENT = '配置文件发布表';
 
+-- --------------------------------------------------------
+--
+-- Table structure `config_file_gray_release`
+--
+CREATE TABLE `config_file_gray_release`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `name`        varchar(128)             DEFAULT NULL COMMENT '发布标题',
+    `namespace`   varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`       varchar(128)    NOT NULL COMMENT '所属的文件组',
+    `file_name`   varchar(128)    NOT NULL COMMENT '配置文件名',
+    `content`     longtext        NOT NULL COMMENT '文件内容',
+    `comment`     varchar(512)             DEFAULT NULL COMMENT '备注信息',
+    `md5`         varchar(128)    NOT NULL COMMENT 'content的md5值',
+    `version`     int(11)         NOT NULL COMMENT '版本号，与配置文件发布共用版本号',
+    `rule`        text            NOT NULL COMMENT '灰度规则',
+    `error_count` int(11)         NOT NULL DEFAULT 0 COMMENT '客户端上报的加载失败次数',
+    `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`   varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`   varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_file` (`namespace`, `group`, `file_name`),
+    KEY `idx_modify_time` (`modify_time`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置文件灰度发布表';
+
+-- --------------------------------------------------------
+--
+-- Table structure `config_file_change`
+--
+CREATE TABLE `config_file_change`
+(
+    `id`             bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `namespace`      varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`          varchar(128)    NOT NULL COMMENT '所属的文件组',
+    `file_name`      varchar(128)    NOT NULL COMMENT '配置文件名',
+    `content`        longtext        NOT NULL COMMENT '变更后的文件内容',
+    `diff`           longtext        NOT NULL COMMENT '相对最新发布内容的 unified diff',
+    `md5`            varchar(128)    NOT NULL COMMENT 'content的md5值',
+    `status`         varchar(16)     NOT NULL COMMENT '审核状态：pending、approved、rejected、published',
+    `review_by`      varchar(32)              DEFAULT NULL COMMENT '审核人',
+    `review_comment` varchar(512)             DEFAULT NULL COMMENT '审核意见',
+    `create_time`    timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`      varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time`    timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`      varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    KEY `idx_file` (`namespace`, `group`, `file_name`),
+    KEY `idx_status` (`status`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置文件变更审核表';
+
 -- --------------------------------------------------------
 --
 -- Table structure `config_file_release_history`
@@ -919,6 +975,93 @@ VALUES ("spring-cloud-gateway-braining", '{
 }', "json", "Spring Cloud Gateway  染色规则", NOW()
 	, "polaris", NOW(), "polaris");
 
+CREATE TABLE `config_file_schema` (
+    `id` bigint(10) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `name` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '配置文件 JSON Schema 名称',
+    `content` longtext COLLATE utf8_bin NOT NULL COMMENT 'JSON Schema 内容',
+    `comment` varchar(512) COLLATE utf8_bin DEFAULT NULL COMMENT 'Schema 描述信息',
+    `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_name` (`name`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_bin COMMENT='配置文件 JSON Schema 表';
+
+CREATE TABLE `config_file_webhook` (
+    `id` bigint(10) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `name` varchar(128) COLLATE utf8_bin NOT NULL COMMENT 'webhook 名称',
+    `namespace` varchar(64) COLLATE utf8_bin NOT NULL COMMENT '所属的namespace',
+    `group` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '' COMMENT '订阅的配置文件分组，为空表示全部分组',
+    `file_name` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '' COMMENT '订阅的配置文件名，为空表示全部文件',
+    `url` varchar(1024) COLLATE utf8_bin NOT NULL COMMENT '回调地址',
+    `secret` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '' COMMENT '签名密钥',
+    `events` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '' COMMENT '订阅的事件类型，逗号分隔',
+    `enable` tinyint(4) NOT NULL DEFAULT '1' COMMENT '是否启用',
+    `comment` varchar(512) COLLATE utf8_bin DEFAULT NULL COMMENT '描述信息',
+    `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_name` (`namespace`, `name`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_bin COMMENT='配置文件变更 webhook 表';
+
+CREATE TABLE `config_file_webhook_delivery` (
+    `id` bigint(10) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `webhook_id` bigint(10) unsigned NOT NULL COMMENT 'webhook id',
+    `event_key` varchar(512) COLLATE utf8_bin NOT NULL COMMENT '事件唯一标识，用于多节点之间去重',
+    `event_type` varchar(32) COLLATE utf8_bin NOT NULL COMMENT '事件类型',
+    `namespace` varchar(64) COLLATE utf8_bin NOT NULL COMMENT '所属的namespace',
+    `group` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '所属的文件组',
+    `file_name` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '配置文件名',
+    `payload` text COLLATE utf8_bin NOT NULL COMMENT '推送内容',
+    `status` varchar(16) COLLATE utf8_bin NOT NULL COMMENT '推送状态',
+    `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '已推送次数',
+    `response_code` int(11) NOT NULL DEFAULT '0' COMMENT '最后一次推送的 HTTP 响应码',
+    `error` varchar(1024) COLLATE utf8_bin DEFAULT NULL COMMENT '最后一次推送的错误信息',
+    `next_retry_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下一次推送时间',
+    `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `modify_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_event` (`webhook_id`, `event_key`),
+    KEY `idx_create_time` (`create_time`),
+    KEY `idx_next_retry_time` (`status`, `next_retry_time`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_bin COMMENT='配置文件变更 webhook 推送记录表';
+
+CREATE TABLE `config_file_variable` (
+    `id` bigint(10) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `namespace` varchar(64) COLLATE utf8_bin NOT NULL COMMENT '所属的namespace',
+    `name` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '变量名',
+    `type` varchar(16) COLLATE utf8_bin NOT NULL COMMENT '变量类型',
+    `value` text COLLATE utf8_bin NOT NULL COMMENT '变量值',
+    `comment` varchar(512) COLLATE utf8_bin DEFAULT NULL COMMENT '描述信息',
+    `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_name` (`namespace`, `name`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_bin COMMENT='配置文件模板变量表';
+
+CREATE TABLE `config_file_client_pin` (
+    `id` bigint(10) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `namespace` varchar(64) COLLATE utf8_bin NOT NULL COMMENT '所属的namespace',
+    `group` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '所属的文件组',
+    `file_name` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '配置文件名',
+    `client` varchar(128) COLLATE utf8_bin NOT NULL COMMENT '客户端 ID 或者客户端 IP',
+    `history_id` bigint(10) unsigned NOT NULL COMMENT '固定的发布历史记录 ID',
+    `content` longtext COLLATE utf8_bin NOT NULL COMMENT '固定的发布内容',
+    `md5` varchar(128) COLLATE utf8_bin NOT NULL COMMENT 'content的md5值',
+    `comment` varchar(512) COLLATE utf8_bin DEFAULT NULL COMMENT '备注信息',
+    `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by` varchar(32) COLLATE utf8_bin DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_client` (`namespace`, `group`, `file_name`, `client`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_bin COMMENT='配置文件客户端版本固定表';
+
 -- v1.12.0
 CREATE TABLE `routing_config_v2`
 (
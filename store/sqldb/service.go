@@ -64,6 +64,11 @@ func (ss *serviceStore) addService(s *model.Service) error {
 		}
 	}()
 
+	// 检查命名空间的服务配额，需要在锁namespace之前进行，避免读锁升级为写锁时出现死锁
+	if err = checkServiceQuota(tx, s.Namespace); err != nil {
+		return err
+	}
+
 	// 锁namespace
 	namespace, err := rlockNamespace(tx.QueryRow, s.Namespace)
 	if err != nil {
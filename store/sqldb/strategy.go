@@ -215,6 +215,13 @@ func (s *strategyStore) deleteStrategy(id string) error {
 	return nil
 }
 
+var (
+	// principalColumns auth_principal 表写入的字段，同时也是主键
+	principalColumns = []string{"strategy_id", "principal_id", "principal_role"}
+	// strategyResourceColumns auth_strategy_resource 表写入的字段，同时也是主键
+	strategyResourceColumns = []string{"strategy_id", "res_type", "res_id"}
+)
+
 // addStrategyPrincipals
 func (s *strategyStore) addStrategyPrincipals(tx *BaseTx, id string, principals []model.Principal) error {
 
@@ -222,7 +229,8 @@ func (s *strategyStore) addStrategyPrincipals(tx *BaseTx, id string, principals
 		return nil
 	}
 
-	savePrincipalSql := "INSERT IGNORE INTO auth_principal(strategy_id, principal_id, principal_role) VALUES "
+	savePrincipalSql, onConflict := tx.Dialect().InsertIgnoreInto("auth_principal", principalColumns,
+		principalColumns)
 	values := make([]string, 0)
 	args := make([]interface{}, 0)
 
@@ -232,7 +240,7 @@ func (s *strategyStore) addStrategyPrincipals(tx *BaseTx, id string, principals
 		args = append(args, id, principal.PrincipalID, principal.PrincipalRole)
 	}
 
-	savePrincipalSql += strings.Join(values, ",")
+	savePrincipalSql += strings.Join(values, ",") + onConflict
 
 	logger.StoreScope().Debug("[Store][Strategy] add strategy principal", zap.String("sql", savePrincipalSql),
 		zap.Any("args", args))
@@ -268,7 +276,8 @@ func (s *strategyStore) addStrategyResources(tx *BaseTx, id string, resources []
 		return nil
 	}
 
-	saveResSql := "REPLACE INTO auth_strategy_resource(strategy_id, res_type, res_id) VALUES "
+	saveResSql, onConflict := tx.Dialect().ReplaceInto("auth_strategy_resource", strategyResourceColumns,
+		strategyResourceColumns)
 
 	values := make([]string, 0)
 	args := make([]interface{}, 0)
@@ -283,7 +292,7 @@ func (s *strategyStore) addStrategyResources(tx *BaseTx, id string, resources []
 		return nil
 	}
 
-	saveResSql += strings.Join(values, ",")
+	saveResSql += strings.Join(values, ",") + onConflict
 
 	logger.StoreScope().Debug("[Store][Strategy] add strategy resources", zap.String("sql", saveResSql),
 		zap.Any("args", args))
@@ -325,7 +334,9 @@ func (s *strategyStore) LooseAddStrategyResources(resources []model.StrategyReso
 	for i := range resources {
 		resource := resources[i]
 
-		saveResSql := "REPLACE INTO auth_strategy_resource(strategy_id, res_type, res_id) VALUES (?,?,?)"
+		saveResSql, onConflict := tx.Dialect().ReplaceInto("auth_strategy_resource", strategyResourceColumns,
+			strategyResourceColumns)
+		saveResSql += "(?,?,?)" + onConflict
 		args := make([]interface{}, 0)
 		args = append(args, resource.StrategyID, resource.ResType, resource.ResID)
 
@@ -44,9 +44,10 @@ const (
 	NotFoundCircuitBreaker                   // Failed to find target CircuitBreaker
 	NotFoundReleaseCircuitBreaker            // Failed to find fuse breaker information associated with service
 	Unknown
-	NotFoundUser       // 用户不存在
-	NotFoundUserGroup  // 用户组不存在
-	InvalidUserIDSlice // 非法的用户ID列表
+	NotFoundUser         // 用户不存在
+	NotFoundUserGroup    // 用户组不存在
+	InvalidUserIDSlice   // 非法的用户ID列表
+	ExceedNamespaceQuota // 超出命名空间的资源配额
 )
 
 // Error 普通error转StatusError
@@ -61,13 +62,15 @@ func Error(err error) error {
 	}
 
 	s := &StatusError{message: err.Error()}
-	if strings.Contains(s.message, "Data too long") {
+	if strings.Contains(s.message, "Data too long") || strings.Contains(s.message, "value too long") {
 		s.code = OutOfRangeErr
-	} else if strings.Contains(s.message, "Duplicate entry") {
+	} else if strings.Contains(s.message, "Duplicate entry") ||
+		strings.Contains(s.message, "duplicate key value violates unique constraint") {
 		s.code = DuplicateEntryErr
-	} else if strings.Contains(s.message, "a foreign key constraint fails") {
+	} else if strings.Contains(s.message, "a foreign key constraint fails") ||
+		strings.Contains(s.message, "violates foreign key constraint") {
 		s.code = ForeignKeyErr
-	} else if strings.Contains(s.message, "Deadlock") {
+	} else if strings.Contains(s.message, "Deadlock") || strings.Contains(s.message, "deadlock detected") {
 		s.code = DeadlockErr
 	} else {
 		s.code = Unknown